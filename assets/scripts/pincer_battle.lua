@@ -0,0 +1,28 @@
+-- 「挟撃」のシナリオスクリプト（stages.toml の script から読み込む）
+-- 持ちこたえる残り時間を1分ごとに知らせ、同盟軍の片方が崩れたら伝える
+
+local SURVIVE = 300 -- 勝利条件の survive の秒数
+local ALLIES = { "b", "c" }
+
+local announced = 0 -- 最後に知らせた経過分
+local routed = {}
+
+function on_start()
+  battle.announce("東と北から同盟軍が迫る。5分間持ちこたえよ")
+end
+
+function on_tick(dt)
+  local minutes = math.floor(battle.time() / 60)
+  if minutes > announced and battle.time() < SURVIVE then
+    announced = minutes
+    battle.announce(string.format("残り%d分", math.ceil((SURVIVE - battle.time()) / 60)))
+  end
+
+  for _, label in ipairs(ALLIES) do
+    local army = battle.army(label)
+    if army and army.routed and not routed[label] then
+      routed[label] = true
+      battle.announce(army.name .. "が総崩れになった")
+    end
+  end
+end
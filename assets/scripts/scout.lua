@@ -0,0 +1,32 @@
+-- 斥候の行動スクリプト（units.toml の ai_script から読み込む）
+-- 敵の後衛（弓兵・魔術師・投石機・召喚士）を見つけたら潜伏したまま回り込んで不意を打ち、
+-- 深手を負ったら相手から離れる。狙える後衛がいなければ通常の行動ツリーに任せる
+
+local BACKLINE = { archer = true, mage = true, catapult = true, summoner = true }
+local RETREAT_HEALTH = 0.3 -- 体力がこの割合を切ると下がる
+
+local function distance(a, b)
+  local dx, dy = a.x - b.x, a.y - b.y
+  return math.sqrt(dx * dx + dy * dy)
+end
+
+-- think(unit, enemies) は判断のたび（0.1秒ごと）に呼ばれる
+function think(unit, enemies)
+  if unit.hp < unit.max_hp * RETREAT_HEALTH and unit.target then
+    return "retreat"
+  end
+
+  local best, best_distance = nil, math.huge
+  for _, enemy in ipairs(enemies) do
+    if BACKLINE[enemy.type] then
+      local d = distance(unit, enemy)
+      if d < best_distance then
+        best, best_distance = enemy, d
+      end
+    end
+  end
+  if best then
+    return "attack", best.id
+  end
+  return nil
+end
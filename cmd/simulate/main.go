@@ -0,0 +1,231 @@
+// Command simulate sweeps preset matchups across stages with headless battles and reports
+// win rates and casualties for balancing unit stats
+//
+// Run it from the repository root so the data files under assets/data are found:
+//
+//	go run ./cmd/simulate -seeds 16 -format csv -o balance.csv
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+)
+
+// Matchup is one preset fielded by army A against another on a stage
+// Every other army of the stage fields preset B unless the stage gives it its own
+type Matchup struct {
+	Stage   string `json:"stage"`
+	PresetA string `json:"preset_a"`
+	PresetB string `json:"preset_b"`
+}
+
+// MatchupResult sums up the seeded battles of a matchup, seen from army A
+type MatchupResult struct {
+	Matchup
+	Trials      int     `json:"trials"`
+	Wins        int     `json:"wins"`
+	Draws       int     `json:"draws"`
+	Losses      int     `json:"losses"`
+	WinRate     float64 `json:"win_rate"`
+	UnitsA      int     `json:"units_a"`      // 軍勢Aの同盟の兵数
+	UnitsB      int     `json:"units_b"`      // 敵対する軍勢の兵数
+	CasualtiesA float64 `json:"casualties_a"` // 軍勢Aの同盟の戦死数の平均
+	CasualtiesB float64 `json:"casualties_b"` // 敵対する軍勢の戦死数の平均
+	BattleTime  float64 `json:"battle_time"`  // 戦闘時間の平均（秒）
+	Error       string  `json:"error,omitempty"`
+}
+
+// csvHeader names the CSV columns in the order of csvRecord
+var csvHeader = []string{"stage", "preset_a", "preset_b", "trials", "wins", "draws", "losses", "win_rate", "units_a", "units_b", "casualties_a", "casualties_b", "battle_time", "error"}
+
+func main() {
+	seeds := flag.Int("seeds", game.AutoResolveTrials, "battles fought per matchup, seeded 1..N")
+	stages := flag.String("stages", "", "comma-separated stage IDs (default: every stage)")
+	presets := flag.String("presets", "", "comma-separated preset names (default: every preset)")
+	format := flag.String("format", "csv", "output format: csv or json")
+	outPath := flag.String("o", "", "write the report to the given file instead of standard output")
+	workers := flag.Int("workers", runtime.NumCPU(), "battles simulated in parallel")
+	mods := flag.String("mods", "", "comma-separated mod folders under mods/ to load, lowest priority first (default: none)")
+	flag.Parse()
+	
+	if *format != "csv" && *format != "json" {
+		log.Fatalf("unknown format %q (csv or json)", *format)
+	}
+	
+	dataManager := data.NewDataManager()
+	if *mods != "" {
+		dataManager.Mods = strings.Split(*mods, ",")
+	}
+	if err := dataManager.LoadAll(); err != nil {
+		log.Fatalf("failed to load game data (run from the repository root): %v", err)
+	}
+	
+	stageIDs := dataManager.GetStageIDs()
+	if *stages != "" {
+		stageIDs = strings.Split(*stages, ",")
+	}
+	presetNames := game.ListPresets(dataManager)
+	if *presets != "" {
+		presetNames = strings.Split(*presets, ",")
+	}
+	
+	var matchups []Matchup
+	for _, stage := range stageIDs {
+		for _, presetA := range presetNames {
+			for _, presetB := range presetNames {
+				matchups = append(matchups, Matchup{Stage: stage, PresetA: presetA, PresetB: presetB})
+			}
+		}
+	}
+	
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *outPath, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	
+	// The simulation logs every unit it creates; keep standard output for the report
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	os.Stdout = devNull
+	
+	log.Printf("simulating %d matchups × %d seeds on %d workers", len(matchups), *seeds, *workers)
+	results := simulateAll(dataManager, matchups, *seeds, max(*workers, 1))
+	
+	if *format == "json" {
+		err = writeJSON(out, results)
+	} else {
+		err = writeCSV(out, results)
+	}
+	if err != nil {
+		log.Fatalf("failed to write the report: %v", err)
+	}
+}
+
+// simulateAll fights the matchups on a pool of workers and returns the results in matchup order
+func simulateAll(dataManager *data.DataManager, matchups []Matchup, seeds, workers int) []MatchupResult {
+	results := make([]MatchupResult, len(matchups))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = simulate(dataManager, matchups[i], seeds)
+				log.Printf("%s: %s vs %s  win %.0f%%", matchups[i].Stage, matchups[i].PresetA, matchups[i].PresetB, results[i].WinRate*100)
+			}
+		}()
+	}
+	for i := range matchups {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// simulate fights the matchup once per seed at auto-resolve fidelity
+func simulate(dataManager *data.DataManager, matchup Matchup, seeds int) MatchupResult {
+	result := MatchupResult{Matchup: matchup, Trials: seeds}
+	resolved, err := game.AutoResolve(func(seed int64) (*game.BattleManager, error) {
+		return newBattle(dataManager, matchup, seed)
+	}, 0, seeds)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	
+	result.Wins, result.Draws, result.Losses = resolved.Wins, resolved.Draws, resolved.Losses
+	result.WinRate = resolved.WinRate()
+	result.UnitsA, result.UnitsB = resolved.Units, resolved.EnemyUnits
+	result.CasualtiesA, result.CasualtiesB = resolved.Casualties, resolved.EnemyCasualties
+	result.BattleTime = resolved.BattleTime
+	return result
+}
+
+// newBattle builds the matchup's battle on its stage the way the army setup forecast does, without mutators or handicaps
+func newBattle(dataManager *data.DataManager, matchup Matchup, seed int64) (*game.BattleManager, error) {
+	stage, err := dataManager.GetStageConfig(matchup.Stage)
+	if err != nil {
+		return nil, err
+	}
+	terrain, err := dataManager.GetTerrainConfig(stage.Terrain)
+	if err != nil {
+		return nil, err
+	}
+	
+	battleManager := game.NewBattleManager(stage, terrain)
+	battleManager.SetRandomSeed(seed)
+	battleManager.SetArmyPreset(0, matchup.PresetA)
+	if err := battleManager.CreateArmies(matchup.PresetA, matchup.PresetB, dataManager); err != nil {
+		return nil, err
+	}
+	return battleManager, nil
+}
+
+// writeCSV writes the results as CSV with a header row
+func writeCSV(out io.Writer, results []MatchupResult) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := writer.Write(csvRecord(result)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRecord formats a result as a CSV row
+func csvRecord(result MatchupResult) []string {
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 3, 64)
+	}
+	return []string{
+		result.Stage,
+		result.PresetA,
+		result.PresetB,
+		strconv.Itoa(result.Trials),
+		strconv.Itoa(result.Wins),
+		strconv.Itoa(result.Draws),
+		strconv.Itoa(result.Losses),
+		formatFloat(result.WinRate),
+		strconv.Itoa(result.UnitsA),
+		strconv.Itoa(result.UnitsB),
+		formatFloat(result.CasualtiesA),
+		formatFloat(result.CasualtiesB),
+		formatFloat(result.BattleTime),
+		result.Error,
+	}
+}
+
+// writeJSON writes the results as an indented JSON array
+func writeJSON(out io.Writer, results []MatchupResult) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(results); err != nil {
+		return fmt.Errorf("encode results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package campaign
+
+import (
+	"math/rand"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// SetDifficulty scales the enemy armies fielded in battles by the game difficulty and, with rubber banding,
+// by the player's standing on the map; difficulties missing from the config field the armies as they stand
+func (o *Overworld) SetDifficulty(difficulty string, rubberBanding bool) {
+	o.difficulty = nil
+	if config, ok := o.difficulties[difficulty]; ok {
+		o.difficulty = &config
+	}
+	o.rubberBanding = rubberBanding
+}
+
+// EnemyBudget returns the gold the enemy army may field in battle: the value of its groups
+// scaled by the difficulty, and up or down by the rubber band while the player is ahead or behind
+func (o *Overworld) EnemyBudget(army *Army) int {
+	value := 0
+	for _, group := range army.Groups {
+		value += o.groupValue(group)
+	}
+	if o.difficulty == nil {
+		return value
+	}
+	
+	scale := o.difficulty.Budget
+	if scale <= 0 {
+		scale = 1
+	}
+	if o.rubberBanding {
+		scale *= 1 + o.difficulty.RubberBand*o.standing()
+	}
+	return int(float64(value)*scale + 0.5)
+}
+
+// standing returns how far ahead the player is on the map, from -1 (the enemy holds every province) to 1
+func (o *Overworld) standing() float64 {
+	if len(o.Provinces) == 0 {
+		return 0
+	}
+	lead := 0
+	for _, province := range o.Provinces {
+		switch province.Owner {
+		case data.SidePlayer:
+			lead++
+		case data.SideEnemy:
+			lead--
+		}
+	}
+	return float64(lead) / float64(len(o.Provinces))
+}
+
+// enemyGroups returns the groups the enemy army fields in the battle over the province
+// The army keeps its own groups, dropping the last ones past the budget or filling the rest of the budget
+// with random recruits; the draw is seeded by the turn and province so every trial of the battle agrees
+func (o *Overworld) enemyGroups(army *Army, provinceIndex int) []data.ReinforcementGroupConfig {
+	if o.difficulty == nil || len(o.recruits) == 0 {
+		return army.Groups
+	}
+	rng := rand.New(rand.NewSource(int64(o.Turn)<<16 + int64(provinceIndex)))
+	return o.composeGroups(army.Groups, o.EnemyBudget(army), rng)
+}
+
+// composeGroups is the budget-based randomizer: it trims the groups to the budget, keeping at least one
+// and any group at least half paid for, then adds recruits drawn at random among those the rest of the budget affords, up to MaxArmyGroups
+func (o *Overworld) composeGroups(groups []data.ReinforcementGroupConfig, budget int, rng *rand.Rand) []data.ReinforcementGroupConfig {
+	composed := make([]data.ReinforcementGroupConfig, 0, MaxArmyGroups)
+	spent := 0
+	for _, group := range groups {
+		value := o.groupValue(group)
+		if len(composed) > 0 && spent+value/2 > budget {
+			break
+		}
+		composed = append(composed, group)
+		spent += value
+	}
+	
+	for len(composed) < MaxArmyGroups {
+		var affordable []data.RecruitConfig
+		for _, recruit := range o.recruits {
+			if recruit.Cost <= budget-spent {
+				affordable = append(affordable, recruit)
+			}
+		}
+		if len(affordable) == 0 {
+			break
+		}
+		recruit := affordable[rng.Intn(len(affordable))]
+		composed = append(composed, data.ReinforcementGroupConfig{Leader: recruit.Leader, Member: recruit.Member, Count: recruit.Count})
+		spent += recruit.Cost
+	}
+	return composed
+}
+
+// groupValue returns what the group would cost to recruit: the price of the recruit with the same leader
+// and members for its size, or the average price per soldier of all recruits
+func (o *Overworld) groupValue(group data.ReinforcementGroupConfig) int {
+	soldiers := group.Count + 1
+	for _, recruit := range o.recruits {
+		if recruit.Leader == group.Leader && recruit.Member == group.Member {
+			return recruit.Cost * soldiers / (recruit.Count + 1)
+		}
+	}
+	
+	cost, recruited := 0, 0
+	for _, recruit := range o.recruits {
+		cost += recruit.Cost
+		recruited += recruit.Count + 1
+	}
+	if recruited == 0 {
+		return 0
+	}
+	return cost * soldiers / recruited
+}
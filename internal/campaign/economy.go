@@ -0,0 +1,180 @@
+package campaign
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// MaxArmyGroups is the most groups an army can have; stages deploy at most this many per army
+const MaxArmyGroups = 5
+
+// Recruits returns the groups that can be raised in held provinces
+func (o *Overworld) Recruits() []data.RecruitConfig {
+	return o.recruits
+}
+
+// Income returns the gold the side earns each turn from the provinces it holds
+func (o *Overworld) Income(side string) int {
+	held := 0
+	for _, province := range o.Provinces {
+		if province.Owner == side {
+			held++
+		}
+	}
+	return held * o.economy.ProvinceIncome
+}
+
+// Upkeep returns the gold the side pays each turn for its soldiers
+func (o *Overworld) Upkeep(side string) int {
+	upkeep := 0
+	for _, army := range o.Armies {
+		if army.Side == side {
+			for _, group := range army.Groups {
+				upkeep += o.groupUpkeep(group)
+			}
+		}
+	}
+	return upkeep
+}
+
+// Recruit raises a group in a province the side holds: it joins the side's army standing there,
+// or musters a new army that can march from the next turn
+func (o *Overworld) Recruit(side, provinceID string, recruit data.RecruitConfig) (*Army, error) {
+	province := o.GetProvince(provinceID)
+	if province == nil || province.Owner != side {
+		return nil, fmt.Errorf("支配していない地方では徴募できません")
+	}
+	if o.IsContested(provinceID) {
+		return nil, fmt.Errorf("%sは交戦中です", province.Name)
+	}
+	if o.Gold[side] < recruit.Cost {
+		return nil, fmt.Errorf("軍資金が足りません（%d / %d）", o.Gold[side], recruit.Cost)
+	}
+	
+	group := data.ReinforcementGroupConfig{Leader: recruit.Leader, Member: recruit.Member, Count: recruit.Count}
+	armies := o.ArmiesAt(provinceID, side)
+	var army *Army
+	for _, candidate := range armies {
+		if len(candidate.Groups) < MaxArmyGroups {
+			army = candidate
+			break
+		}
+	}
+	switch {
+	case army != nil:
+		army.Groups = append(army.Groups, group)
+	case len(armies) > 0:
+		return nil, fmt.Errorf("%sの軍勢はこれ以上部隊を加えられません", province.Name)
+	default:
+		army = o.newArmy(side, provinceID, recruit.Name)
+		army.Groups = append(army.Groups, group)
+		o.Armies = append(o.Armies, army)
+	}
+	
+	o.Gold[side] -= recruit.Cost
+	return army, nil
+}
+
+// payday adds the side's income and pays its upkeep; when the treasury cannot cover it,
+// unpaid groups leave the largest armies and armies left without groups disband
+// It returns how many groups left
+func (o *Overworld) payday(side string) int {
+	o.Gold[side] += o.Income(side) - o.Upkeep(side)
+	
+	disbanded := 0
+	for o.Gold[side] < 0 {
+		group, ok := o.disbandGroup(side)
+		if !ok {
+			break
+		}
+		
+		// 払えなかった維持費はその部隊の分だけ帳消しになる
+		o.Gold[side] += o.groupUpkeep(group)
+		disbanded++
+	}
+	
+	if o.Gold[side] < 0 {
+		o.Gold[side] = 0
+	}
+	return disbanded
+}
+
+// disbandGroup removes the last group of the side's largest army, disbanding the army when it was the last,
+// and returns the group; it reports false when the side has no groups left
+func (o *Overworld) disbandGroup(side string) (data.ReinforcementGroupConfig, bool) {
+	var largest *Army
+	for _, army := range o.Armies {
+		if army.Side == side && (largest == nil || len(army.Groups) > len(largest.Groups)) {
+			largest = army
+		}
+	}
+	if largest == nil || len(largest.Groups) == 0 {
+		return data.ReinforcementGroupConfig{}, false
+	}
+	
+	last := len(largest.Groups) - 1
+	group := largest.Groups[last]
+	largest.Groups = largest.Groups[:last]
+	if len(largest.Groups) == 0 {
+		o.removeArmy(largest)
+	}
+	return group, true
+}
+
+// recruitEnemies reinforces each enemy army resting in enemy ground with one group,
+// as long as the treasury still covers next turn's upkeep
+func (o *Overworld) recruitEnemies() {
+	if len(o.recruits) == 0 {
+		return
+	}
+	recruit := o.recruits[o.Turn%len(o.recruits)]
+	group := data.ReinforcementGroupConfig{Leader: recruit.Leader, Member: recruit.Member, Count: recruit.Count}
+	
+	for _, army := range o.Armies {
+		if army.Side != data.SideEnemy || len(army.Groups) >= MaxArmyGroups {
+			continue
+		}
+		if o.GetProvince(army.Province).Owner != data.SideEnemy || o.IsContested(army.Province) {
+			continue
+		}
+		balance := o.Gold[data.SideEnemy] - recruit.Cost + o.Income(data.SideEnemy) - o.Upkeep(data.SideEnemy) - o.groupUpkeep(group)
+		if balance < 0 {
+			return
+		}
+		army.Groups = append(army.Groups, group)
+		o.Gold[data.SideEnemy] -= recruit.Cost
+	}
+}
+
+// groupUpkeep returns the gold a group costs each turn, its leader included
+func (o *Overworld) groupUpkeep(group data.ReinforcementGroupConfig) int {
+	return (group.Count + 1) * o.economy.UnitUpkeep
+}
+
+// newArmy musters an empty army in the province; its formation is named after the first group raised in it
+func (o *Overworld) newArmy(side, provinceID, recruitName string) *Army {
+	number := 1
+	for o.getArmy(fmt.Sprintf("levy%d", number)) != nil {
+		number++
+	}
+	return &Army{
+		ID:       fmt.Sprintf("levy%d", number),
+		Name:     fmt.Sprintf("第%d徴募隊", number),
+		Side:     side,
+		Preset:   recruitName,
+		Province: provinceID,
+		From:     provinceID,
+		Moved:    true, // 徴募したターンは進軍できない
+	}
+}
+
+// getArmy returns the army with the given ID, or nil
+func (o *Overworld) getArmy(id string) *Army {
+	for _, army := range o.Armies {
+		if army.ID == id {
+			return army
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package campaign
+
+import (
+	"fmt"
+)
+
+// Equip puts an item in one equipment slot of the leader of the army's group; an empty item ID empties the slot
+// Equipment stays with the group until it is disbanded or destroyed, and is changed only between battles
+func (o *Overworld) Equip(army *Army, groupIndex int, slot, itemID string) error {
+	if groupIndex < 0 || groupIndex >= len(army.Groups) {
+		return fmt.Errorf("%sに部隊%dはありません", army.Name, groupIndex+1)
+	}
+	if o.IsContested(army.Province) {
+		return fmt.Errorf("%sは交戦中です", army.Name)
+	}
+	army.Groups[groupIndex].Equipment.Set(slot, itemID)
+	return nil
+}
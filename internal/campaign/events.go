@@ -0,0 +1,143 @@
+package campaign
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// Event returns the event waiting for the player's choice, or nil
+func (o *Overworld) Event() *data.CampaignEventConfig {
+	return o.event
+}
+
+// ChooseEvent applies the effects of the player's choice to the waiting event and puts the event away
+func (o *Overworld) ChooseEvent(index int) (string, error) {
+	event := o.event
+	if event == nil {
+		return "", fmt.Errorf("イベントはありません")
+	}
+	if len(event.Choices) == 0 {
+		o.event = nil
+		return event.Name, nil
+	}
+	if index < 0 || index >= len(event.Choices) {
+		return "", fmt.Errorf("その選択肢はありません")
+	}
+	
+	choice := event.Choices[index]
+	side := data.SidePlayer
+	if o.Gold[side]+choice.Gold < 0 {
+		return "", fmt.Errorf("軍資金が足りません（%d / %d）", o.Gold[side], -choice.Gold)
+	}
+	
+	o.Gold[side] += choice.Gold
+	for i := 0; i < choice.Disband; i++ {
+		if _, ok := o.disbandGroup(side); !ok {
+			break
+		}
+	}
+	if choice.Recruit != "" {
+		o.enlist(side, choice.Recruit)
+	}
+	o.revolt(side, choice.Revolt)
+	
+	o.event = nil
+	return fmt.Sprintf("%s: %s", event.Name, choice.Label), nil
+}
+
+// drawEvent turns over the top card of the event deck, shuffling a new deck when it runs out
+func (o *Overworld) drawEvent() {
+	if len(o.deck) == 0 {
+		o.shuffleDeck()
+	}
+	for len(o.deck) > 0 {
+		id := o.deck[0]
+		o.deck = o.deck[1:]
+		if o.event = o.getEvent(id); o.event != nil {
+			return
+		}
+	}
+}
+
+// eventRandom returns the random source of the turn's event draws, seeded by the campaign's seed and the turn
+// so a reloaded save shuffles the deck and picks the revolting provinces the same way again
+func (o *Overworld) eventRandom() *rand.Rand {
+	return rand.New(rand.NewSource(o.seed + int64(o.Turn)))
+}
+
+// shuffleDeck fills the deck with every event's copies in random order
+func (o *Overworld) shuffleDeck() {
+	o.deck = nil
+	for _, event := range o.events {
+		for i := 0; i < max(event.Copies, 1); i++ {
+			o.deck = append(o.deck, event.ID)
+		}
+	}
+	o.eventRandom().Shuffle(len(o.deck), func(i, j int) {
+		o.deck[i], o.deck[j] = o.deck[j], o.deck[i]
+	})
+}
+
+// getEvent returns the event with the given ID, or nil; events removed from the config are skipped
+func (o *Overworld) getEvent(id string) *data.CampaignEventConfig {
+	for i := range o.events {
+		if o.events[i].ID == id {
+			return &o.events[i]
+		}
+	}
+	return nil
+}
+
+// enlist adds the named recruit for free to the side's smallest army with room,
+// or musters it in the first province the side holds in peace
+func (o *Overworld) enlist(side, recruitName string) {
+	var recruit *data.RecruitConfig
+	for i := range o.recruits {
+		if o.recruits[i].Name == recruitName {
+			recruit = &o.recruits[i]
+		}
+	}
+	if recruit == nil {
+		fmt.Printf("Warning: unknown recruit '%s' in campaign event\n", recruitName)
+		return
+	}
+	group := data.ReinforcementGroupConfig{Leader: recruit.Leader, Member: recruit.Member, Count: recruit.Count}
+	
+	var smallest *Army
+	for _, army := range o.Armies {
+		if army.Side == side && len(army.Groups) < MaxArmyGroups && (smallest == nil || len(army.Groups) < len(smallest.Groups)) {
+			smallest = army
+		}
+	}
+	if smallest != nil {
+		smallest.Groups = append(smallest.Groups, group)
+		return
+	}
+	
+	for _, province := range o.Provinces {
+		if province.Owner == side && !o.IsContested(province.ID) && len(o.ArmiesAt(province.ID, side)) == 0 {
+			army := o.newArmy(side, province.ID, recruit.Name)
+			army.Groups = append(army.Groups, group)
+			o.Armies = append(o.Armies, army)
+			return
+		}
+	}
+}
+
+// revolt lets up to count of the side's provinces without an army fall away to unclaimed
+func (o *Overworld) revolt(side string, count int) {
+	var undefended []*Province
+	for _, province := range o.Provinces {
+		if province.Owner == side && len(o.ArmiesAt(province.ID, side)) == 0 {
+			undefended = append(undefended, province)
+		}
+	}
+	o.eventRandom().Shuffle(len(undefended), func(i, j int) {
+		undefended[i], undefended[j] = undefended[j], undefended[i]
+	})
+	for _, province := range undefended[:min(count, len(undefended))] {
+		province.Owner = ""
+	}
+}
@@ -0,0 +1,443 @@
+package campaign
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// Province is a node of the overworld map
+type Province struct {
+	ID    string
+	Name  string
+	X     float64
+	Y     float64
+	Stage string // 合戦の舞台になるステージ設定ID
+	Owner string // data.SidePlayer, data.SideEnemy or "" for unclaimed
+	Links []string
+}
+
+// Army is an army standing in a province
+type Army struct {
+	ID       string
+	Name     string
+	Side     string
+	Preset   string
+	Province string
+	From     string // このターンに移動してくる前の地方（撤退先）
+	Moved    bool   // このターンは移動済み
+	Groups   []data.ReinforcementGroupConfig
+}
+
+// Battle is a province where armies of both sides meet
+type Battle struct {
+	Province    *Province
+	Player      *Army
+	Enemy       *Army
+	EnemyGroups []data.ReinforcementGroupConfig // 敵軍が合戦に出す部隊（難易度で増減する）
+}
+
+// Overworld is the strategic map of the campaign: provinces linked by paths and the armies moving between them
+type Overworld struct {
+	Turn      int
+	Provinces []*Province
+	Armies    []*Army
+	Gold      map[string]int // 勢力ごとの軍資金
+	Disbanded int            // 直前のターン終了で維持費が払えず離散した自軍の部隊数
+	
+	economy  data.CampaignEconomyConfig
+	recruits []data.RecruitConfig
+	events   []data.CampaignEventConfig
+	deck     []string                  // イベントの山札の残り
+	event    *data.CampaignEventConfig // 選択を待っているイベント
+	seed     int64                     // 山札の切り直しと反乱の乱数の種（ターンと合わせて使う）
+	
+	difficulties  map[string]data.CampaignDifficultyConfig
+	difficulty    *data.CampaignDifficultyConfig // 敵軍の編成の補正（nil: 補正なし）
+	rubberBanding bool
+}
+
+// NewOverworld starts the campaign map from its configuration
+func NewOverworld(config data.CampaignConfig) *Overworld {
+	o := &Overworld{
+		Turn: 1,
+		Gold: map[string]int{
+			data.SidePlayer: config.Economy.StartingGold,
+			data.SideEnemy:  config.Economy.StartingGold,
+		},
+		economy:  config.Economy,
+		recruits: config.Recruits,
+		events:   config.Events,
+		seed:     time.Now().UnixNano(),
+		
+		difficulties: config.Difficulty,
+	}
+	for _, province := range config.Provinces {
+		o.Provinces = append(o.Provinces, &Province{
+			ID:    province.ID,
+			Name:  province.Name,
+			X:     province.X,
+			Y:     province.Y,
+			Stage: province.Stage,
+			Owner: province.Owner,
+		})
+	}
+	
+	// Links are mutual, so a path needs to be listed on one end only
+	for _, province := range config.Provinces {
+		for _, link := range province.Links {
+			if o.GetProvince(link) == nil {
+				fmt.Printf("Warning: unknown link '%s' from province %s\n", link, province.ID)
+				continue
+			}
+			o.link(province.ID, link)
+		}
+	}
+	
+	for _, army := range config.Armies {
+		o.Armies = append(o.Armies, &Army{
+			ID:       army.ID,
+			Name:     army.Name,
+			Side:     army.Side,
+			Preset:   army.Preset,
+			Province: army.Province,
+			Groups:   slices.Clone(army.Groups),
+		})
+	}
+	return o
+}
+
+// LoadOverworld resumes the campaign map saved in the campaign, or starts a new one
+func LoadOverworld(config data.CampaignConfig, state *save.Campaign) *Overworld {
+	o := NewOverworld(config)
+	if state.Turn == 0 {
+		return o
+	}
+	
+	o.Turn = state.Turn
+	for _, province := range o.Provinces {
+		province.Owner = state.Provinces[province.ID]
+	}
+	
+	// Maps saved before the economy keep the starting gold and the configured groups
+	if len(state.Gold) > 0 {
+		o.Gold = maps.Clone(state.Gold)
+	}
+	o.deck = slices.Clone(state.Deck)
+	o.event = o.getEvent(state.Event)
+	o.seed = state.Seed
+	startingGroups := make(map[string][]data.ReinforcementGroupConfig, len(o.Armies))
+	for _, army := range o.Armies {
+		startingGroups[army.ID] = army.Groups
+	}
+	
+	o.Armies = nil
+	for _, army := range state.Armies {
+		groups := startingGroups[army.ID]
+		if len(army.Groups) > 0 {
+			groups = nil
+			for _, group := range army.Groups {
+				groups = append(groups, data.ReinforcementGroupConfig{
+					Leader:    group.Leader,
+					Member:    group.Member,
+					Count:     group.Count,
+					Equipment: data.Equipment{Weapon: group.Weapon, Armor: group.Armor, Banner: group.Banner},
+				})
+			}
+		}
+		o.Armies = append(o.Armies, &Army{
+			ID:       army.ID,
+			Name:     army.Name,
+			Side:     army.Side,
+			Preset:   army.Preset,
+			Province: army.Province,
+			From:     army.From,
+			Moved:    army.Moved,
+			Groups:   slices.Clone(groups),
+		})
+	}
+	return o
+}
+
+// Store writes the map into the campaign so it can be saved
+func (o *Overworld) Store(state *save.Campaign) {
+	state.Turn = o.Turn
+	state.Provinces = make(map[string]string, len(o.Provinces))
+	for _, province := range o.Provinces {
+		if province.Owner != "" {
+			state.Provinces[province.ID] = province.Owner
+		}
+	}
+	state.Gold = maps.Clone(o.Gold)
+	state.Deck = slices.Clone(o.deck)
+	state.Seed = o.seed
+	state.Event = ""
+	if o.event != nil {
+		state.Event = o.event.ID
+	}
+	state.Armies = nil
+	for _, army := range o.Armies {
+		var groups []save.CampaignGroup
+		for _, group := range army.Groups {
+			groups = append(groups, save.CampaignGroup{
+				Leader: group.Leader,
+				Member: group.Member,
+				Count:  group.Count,
+				Weapon: group.Equipment.Weapon,
+				Armor:  group.Equipment.Armor,
+				Banner: group.Equipment.Banner,
+			})
+		}
+		state.Armies = append(state.Armies, save.CampaignArmy{
+			ID:       army.ID,
+			Name:     army.Name,
+			Side:     army.Side,
+			Preset:   army.Preset,
+			Province: army.Province,
+			From:     army.From,
+			Moved:    army.Moved,
+			Groups:   groups,
+		})
+	}
+}
+
+// link connects two provinces both ways
+func (o *Overworld) link(from, to string) {
+	if o.IsLinked(from, to) {
+		return
+	}
+	o.GetProvince(from).Links = append(o.GetProvince(from).Links, to)
+	o.GetProvince(to).Links = append(o.GetProvince(to).Links, from)
+}
+
+// GetProvince returns the province with the given ID, or nil
+func (o *Overworld) GetProvince(id string) *Province {
+	for _, province := range o.Provinces {
+		if province.ID == id {
+			return province
+		}
+	}
+	return nil
+}
+
+// IsLinked reports whether a path joins the two provinces
+func (o *Overworld) IsLinked(from, to string) bool {
+	province := o.GetProvince(from)
+	if province == nil {
+		return false
+	}
+	for _, link := range province.Links {
+		if link == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ArmiesAt returns the armies of a side standing in the province; an empty side returns both
+func (o *Overworld) ArmiesAt(provinceID, side string) []*Army {
+	var armies []*Army
+	for _, army := range o.Armies {
+		if army.Province == provinceID && (side == "" || army.Side == side) {
+			armies = append(armies, army)
+		}
+	}
+	return armies
+}
+
+// IsContested reports whether armies of both sides stand in the province
+func (o *Overworld) IsContested(provinceID string) bool {
+	return len(o.ArmiesAt(provinceID, data.SidePlayer)) > 0 && len(o.ArmiesAt(provinceID, data.SideEnemy)) > 0
+}
+
+// Battles returns the contested provinces in map order, pairing the first army of each side
+func (o *Overworld) Battles() []Battle {
+	var battles []Battle
+	for i, province := range o.Provinces {
+		if o.IsContested(province.ID) {
+			enemy := o.ArmiesAt(province.ID, data.SideEnemy)[0]
+			battles = append(battles, Battle{
+				Province:    province,
+				Player:      o.ArmiesAt(province.ID, data.SidePlayer)[0],
+				Enemy:       enemy,
+				EnemyGroups: o.enemyGroups(enemy, i),
+			})
+		}
+	}
+	return battles
+}
+
+// MoveArmy marches the army along a path to a neighbouring province; each army moves once per turn
+// Marching into enemy ground takes it unless an enemy army stands there, which starts a battle
+func (o *Overworld) MoveArmy(army *Army, provinceID string) error {
+	if army.Moved {
+		return fmt.Errorf("%sはこのターン移動済みです", army.Name)
+	}
+	if o.IsContested(army.Province) {
+		return fmt.Errorf("%sは交戦中です", army.Name)
+	}
+	if !o.IsLinked(army.Province, provinceID) {
+		return fmt.Errorf("%sへの道がありません", o.provinceName(provinceID))
+	}
+	
+	army.From = army.Province
+	army.Province = provinceID
+	army.Moved = true
+	o.settle()
+	return nil
+}
+
+// ResolveBattle applies the player's result of a battle: the loser's army is destroyed and the winner
+// holds the province; after a draw the army that marched in falls back to where it came from
+func (o *Overworld) ResolveBattle(battle Battle, result string) {
+	switch result {
+	case save.ResultWin:
+		o.removeArmy(battle.Enemy)
+	case save.ResultLoss:
+		o.removeArmy(battle.Player)
+	default:
+		attacker := battle.Enemy
+		if battle.Province.Owner == data.SideEnemy {
+			attacker = battle.Player
+		}
+		if attacker.From == "" || attacker.From == attacker.Province {
+			o.removeArmy(attacker)
+		} else {
+			attacker.Province = attacker.From
+		}
+	}
+	o.settle()
+}
+
+// EndTurn lets the enemy march, pays both sides' income and upkeep, then starts the next turn
+// with a card from the event deck; battles and the last event must be settled first
+func (o *Overworld) EndTurn() error {
+	if len(o.Battles()) > 0 {
+		return fmt.Errorf("未決着の合戦があります")
+	}
+	if o.event != nil {
+		return fmt.Errorf("イベント「%s」の対応を選んでください", o.event.Name)
+	}
+	
+	o.moveEnemies()
+	o.settle()
+	
+	o.Disbanded = o.payday(data.SidePlayer)
+	o.payday(data.SideEnemy)
+	o.recruitEnemies()
+	
+	o.Turn++
+	for _, army := range o.Armies {
+		army.Moved = false
+		if army.Side == data.SidePlayer {
+			army.From = ""
+		}
+	}
+	o.drawEvent()
+	return nil
+}
+
+// Winner returns the side that conquered the whole map or destroyed every army of the other, or ""
+func (o *Overworld) Winner() string {
+	for _, side := range []string{data.SidePlayer, data.SideEnemy} {
+		other := data.SideEnemy
+		if side == data.SideEnemy {
+			other = data.SidePlayer
+		}
+		if !o.hasArmies(other) || !o.ownsAny(other) {
+			return side
+		}
+	}
+	return ""
+}
+
+// moveEnemies marches every enemy army: first onto undefended player ground, then against player armies
+func (o *Overworld) moveEnemies() {
+	for _, army := range o.Armies {
+		if army.Side != data.SideEnemy {
+			continue
+		}
+		army.From = ""
+		if o.IsContested(army.Province) {
+			continue
+		}
+		
+		var target string
+		bestScore := 0
+		for _, link := range o.GetProvince(army.Province).Links {
+			score := 0
+			switch {
+			case len(o.ArmiesAt(link, data.SideEnemy)) > 0:
+				continue // 味方の軍勢がいる地方には重ならない
+			case len(o.ArmiesAt(link, data.SidePlayer)) > 0:
+				score = 1
+			case o.GetProvince(link).Owner != data.SideEnemy:
+				score = 2
+			}
+			if score > bestScore {
+				target = link
+				bestScore = score
+			}
+		}
+		if target != "" {
+			army.From = army.Province
+			army.Province = target
+			army.Moved = true
+		}
+	}
+}
+
+// settle hands each province held by the armies of only one side to that side
+func (o *Overworld) settle() {
+	for _, province := range o.Provinces {
+		if o.IsContested(province.ID) {
+			continue
+		}
+		if armies := o.ArmiesAt(province.ID, ""); len(armies) > 0 {
+			province.Owner = armies[0].Side
+		}
+	}
+}
+
+// removeArmy takes a destroyed army off the map
+func (o *Overworld) removeArmy(target *Army) {
+	armies := o.Armies[:0]
+	for _, army := range o.Armies {
+		if army != target {
+			armies = append(armies, army)
+		}
+	}
+	o.Armies = armies
+}
+
+// hasArmies reports whether the side still has an army on the map
+func (o *Overworld) hasArmies(side string) bool {
+	for _, army := range o.Armies {
+		if army.Side == side {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsAny reports whether the side holds at least one province
+func (o *Overworld) ownsAny(side string) bool {
+	for _, province := range o.Provinces {
+		if province.Owner == side {
+			return true
+		}
+	}
+	return false
+}
+
+// provinceName returns the display name of a province, or its ID if unknown
+func (o *Overworld) provinceName(id string) string {
+	if province := o.GetProvince(id); province != nil {
+		return province.Name
+	}
+	return id
+}
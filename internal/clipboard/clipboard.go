@@ -0,0 +1,20 @@
+// Package clipboard copies text to and pastes text from the system clipboard
+// Windows uses the Win32 clipboard; other systems go through the usual command line tools
+// (pbcopy/pbpaste, wl-copy/wl-paste, xclip or xsel)
+package clipboard
+
+import "strings"
+
+// ReadText returns the text on the clipboard, without a trailing newline
+func ReadText() (string, error) {
+	text, err := readText()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(text, "\r\n"), nil
+}
+
+// WriteText puts the text on the clipboard
+func WriteText(text string) error {
+	return writeText(text)
+}
@@ -0,0 +1,76 @@
+//go:build !windows
+
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// tool is a command line program that copies to or pastes from the clipboard
+type tool struct {
+	name string
+	args []string
+}
+
+// clipboardTools returns the copy and paste programs of the system, in order of preference
+func clipboardTools() (copyTools, pasteTools []tool) {
+	if runtime.GOOS == "darwin" {
+		return []tool{{"pbcopy", nil}}, []tool{{"pbpaste", nil}}
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		copyTools = append(copyTools, tool{"wl-copy", nil})
+		pasteTools = append(pasteTools, tool{"wl-paste", []string{"--no-newline"}})
+	}
+	copyTools = append(copyTools,
+		tool{"xclip", []string{"-selection", "clipboard"}},
+		tool{"xsel", []string{"--clipboard", "--input"}})
+	pasteTools = append(pasteTools,
+		tool{"xclip", []string{"-selection", "clipboard", "-out"}},
+		tool{"xsel", []string{"--clipboard", "--output"}})
+	return copyTools, pasteTools
+}
+
+// findTool returns the first of the programs that is installed
+func findTool(tools []tool) (tool, error) {
+	var names []string
+	for _, t := range tools {
+		if _, err := exec.LookPath(t.name); err == nil {
+			return t, nil
+		}
+		names = append(names, t.name)
+	}
+	return tool{}, fmt.Errorf("no clipboard program found (install one of %s)", strings.Join(names, ", "))
+}
+
+// readText runs the paste program and returns its output
+func readText() (string, error) {
+	_, pasteTools := clipboardTools()
+	t, err := findTool(pasteTools)
+	if err != nil {
+		return "", err
+	}
+	output, err := exec.Command(t.name, t.args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", t.name, err)
+	}
+	return string(output), nil
+}
+
+// writeText feeds the text to the copy program
+func writeText(text string) error {
+	copyTools, _ := clipboardTools()
+	t, err := findTool(copyTools)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(t.name, t.args...)
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", t.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package clipboard
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Win32 clipboard
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+	
+	openClipboard    = user32.NewProc("OpenClipboard")
+	closeClipboard   = user32.NewProc("CloseClipboard")
+	emptyClipboard   = user32.NewProc("EmptyClipboard")
+	getClipboardData = user32.NewProc("GetClipboardData")
+	setClipboardData = user32.NewProc("SetClipboardData")
+	globalAlloc      = kernel32.NewProc("GlobalAlloc")
+	globalFree       = kernel32.NewProc("GlobalFree")
+	globalLock       = kernel32.NewProc("GlobalLock")
+	globalUnlock     = kernel32.NewProc("GlobalUnlock")
+	globalSize       = kernel32.NewProc("GlobalSize")
+	moveMemory       = kernel32.NewProc("RtlMoveMemory")
+)
+
+// Win32 constants
+const (
+	cfUnicodeText = 13     // CF_UNICODETEXT
+	gmemMoveable  = 0x0002 // GMEM_MOVEABLE
+)
+
+// readText copies the UTF-16 text off the clipboard
+func readText() (string, error) {
+	if ret, _, err := openClipboard.Call(0); ret == 0 {
+		return "", fmt.Errorf("failed to open the clipboard: %w", err)
+	}
+	defer closeClipboard.Call()
+	
+	handle, _, _ := getClipboardData.Call(cfUnicodeText)
+	if handle == 0 {
+		return "", nil // テキストがない
+	}
+	size, _, _ := globalSize.Call(handle)
+	pointer, _, err := globalLock.Call(handle)
+	if pointer == 0 {
+		return "", fmt.Errorf("failed to lock the clipboard text: %w", err)
+	}
+	defer globalUnlock.Call(handle)
+	
+	buffer := make([]uint16, size/2)
+	if len(buffer) == 0 {
+		return "", nil
+	}
+	moveMemory.Call(uintptr(unsafe.Pointer(&buffer[0])), pointer, uintptr(len(buffer)*2))
+	return syscall.UTF16ToString(buffer), nil
+}
+
+// writeText hands a global copy of the text to the clipboard, which owns it from then on
+func writeText(text string) error {
+	buffer, err := syscall.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+	size := uintptr(len(buffer) * 2)
+	
+	handle, _, err := globalAlloc.Call(gmemMoveable, size)
+	if handle == 0 {
+		return fmt.Errorf("failed to allocate the clipboard text: %w", err)
+	}
+	pointer, _, err := globalLock.Call(handle)
+	if pointer == 0 {
+		globalFree.Call(handle)
+		return fmt.Errorf("failed to lock the clipboard text: %w", err)
+	}
+	moveMemory.Call(pointer, uintptr(unsafe.Pointer(&buffer[0])), size)
+	globalUnlock.Call(handle)
+	
+	if ret, _, err := openClipboard.Call(0); ret == 0 {
+		globalFree.Call(handle)
+		return fmt.Errorf("failed to open the clipboard: %w", err)
+	}
+	defer closeClipboard.Call()
+	
+	emptyClipboard.Call()
+	if ret, _, err := setClipboardData.Call(cfUnicodeText, handle); ret == 0 {
+		globalFree.Call(handle)
+		return fmt.Errorf("failed to set the clipboard text: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"math"
 	"os"
 
 	"github.com/pelletier/go-toml/v2"
@@ -11,8 +12,13 @@ type Config struct {
 	Graphics GraphicsConfig `toml:"graphics"`
 	Audio    AudioConfig    `toml:"audio"`
 	Game     GameConfig     `toml:"game"`
+	Controls ControlsConfig `toml:"controls"`
+	Mods     ModsConfig     `toml:"mods"`
 }
 
+// DefaultConfigPath is the configuration file the game reads at startup and the settings screen saves
+const DefaultConfigPath = "config.toml"
+
 // GraphicsConfig represents graphics settings
 type GraphicsConfig struct {
 	FontPath     string  `toml:"font_path"`
@@ -20,6 +26,22 @@ type GraphicsConfig struct {
 	UIScale      float64 `toml:"ui_scale"`
 	ShowFPS      bool    `toml:"show_fps"`
 	VSync        bool    `toml:"vsync"`
+	
+	// Accessibility
+	TextScale      float64 `toml:"text_scale"`      // Multiplier for all UI text
+	ReduceFlashing bool    `toml:"reduce_flashing"` // Disable flash effects
+	
+	// Battlefield post-processing (skipped where shaders are unsupported)
+	Bloom           bool `toml:"bloom"`            // Glow around spells
+	PauseDesaturate bool `toml:"pause_desaturate"` // Drain the colors while paused
+	NightShading    bool `toml:"night_shading"`    // Shade dusk and night instead of laying a flat tint over them
+	MoraleVignette  bool `toml:"morale_vignette"`  // Darken the screen edges at low morale and gray out a lost battle
+	
+	// Battle HUD
+	HUDPreset       string `toml:"hud_preset"`        // "standard", "compact", "auto" (empty: auto)
+	CompactHUDWidth int    `toml:"compact_hud_width"` // Window width below which "auto" picks the compact HUD
+	
+	Theme string `toml:"theme"` // UI color theme ID from themes.toml (F7 switches while playing)
 }
 
 // AudioConfig represents audio settings
@@ -30,11 +52,138 @@ type AudioConfig struct {
 	Enabled      bool    `toml:"enabled"`
 }
 
+// ControlsConfig represents camera scrolling and key bindings
+type ControlsConfig struct {
+	KeyScrollSpeed  float64           `toml:"key_scroll_speed"`  // Camera speed with W/A/S/D and the arrow keys (px/s)
+	EdgeScrollSpeed float64           `toml:"edge_scroll_speed"` // Camera speed with the cursor at the screen edges (px/s)
+	KeyBindings     map[string]string `toml:"key_bindings"`      // Key by battle command ("pause" = "P"), unset commands use their default
+}
+
+// ModsConfig chooses the mod folders under mods/ to load
+type ModsConfig struct {
+	Enabled  []string `toml:"enabled"`  // Mods to load, later ones overriding earlier ones (empty: every folder, by name)
+	Disabled []string `toml:"disabled"` // Mods never loaded
+}
+
 // GameConfig represents game settings
 type GameConfig struct {
-	Language     string `toml:"language"`
-	AutoSave     bool   `toml:"auto_save"`
-	ShowTutorial bool   `toml:"show_tutorial"`
+	Language      string  `toml:"language"`
+	AutoSave      bool    `toml:"auto_save"`
+	ShowTutorial  bool    `toml:"show_tutorial"`
+	Difficulty    string  `toml:"difficulty"`     // "easy", "normal", "hard"
+	RubberBanding bool    `toml:"rubber_banding"` // Campaign enemies grow stronger while the player is ahead, weaker while behind
+	TacticalPause string  `toml:"tactical_pause"` // "allowed", "limited", "disabled" (empty: by difficulty)
+	CommandPoints bool    `toml:"command_points"` // Orders cost regenerating command points
+	CommandDelay  bool    `toml:"command_delay"`  // Orders reach members some time after their leader, the later the further they stand
+	GameSpeed     float64 `toml:"game_speed"`     // Battle simulation speed multiplier
+	ObserverDelay float64 `toml:"observer_delay"` // Seconds the co-op host's stream to observers lags behind
+	DirectorMode  bool    `toml:"director_mode"`  // Battles start with the camera following the fiercest fighting (C toggles)
+}
+
+// Game speed limits
+const (
+	MinGameSpeed = 0.25
+	MaxGameSpeed = 2.0
+)
+
+// UI scale limits
+const (
+	MinUIScale = 0.5
+	MaxUIScale = 2.0
+)
+
+// Default camera scroll speeds (px/s)
+const (
+	DefaultKeyScrollSpeed  = 500.0
+	DefaultEdgeScrollSpeed = 400.0
+)
+
+// GetUIScale returns the window scale clamped to the supported range, defaulting to 1.0 when unset
+func (gc GraphicsConfig) GetUIScale() float64 {
+	if gc.UIScale <= 0 {
+		return 1.0
+	}
+	return math.Max(MinUIScale, math.Min(MaxUIScale, gc.UIScale))
+}
+
+// GetScrollSpeeds returns the edge and keyboard scroll speeds, defaulting when unset
+func (cc ControlsConfig) GetScrollSpeeds() (edge, key float64) {
+	edge, key = cc.EdgeScrollSpeed, cc.KeyScrollSpeed
+	if edge <= 0 {
+		edge = DefaultEdgeScrollSpeed
+	}
+	if key <= 0 {
+		key = DefaultKeyScrollSpeed
+	}
+	return edge, key
+}
+
+// GetTextScale returns the UI text scale, defaulting to 1.0 when unset
+func (gc GraphicsConfig) GetTextScale() float64 {
+	if gc.TextScale <= 0 {
+		return 1.0
+	}
+	return gc.TextScale
+}
+
+// HUD presets
+const (
+	HUDPresetStandard = "standard"
+	HUDPresetCompact  = "compact"
+	HUDPresetAuto     = "auto"
+)
+
+// DefaultCompactHUDWidth is the window width below which the "auto" HUD preset turns compact
+const DefaultCompactHUDWidth = 900
+
+// IsCompactHUD reports whether the battle uses the compact HUD in a window of the given size
+// The "auto" preset turns compact when the window is narrower than the threshold,
+// or shorter than the threshold scaled to the 4:3 screen, and on touch screens for their larger buttons
+func (gc GraphicsConfig) IsCompactHUD(windowWidth, windowHeight int, touch bool) bool {
+	switch gc.HUDPreset {
+	case HUDPresetStandard:
+		return false
+	case HUDPresetCompact:
+		return true
+	}
+	
+	threshold := gc.CompactHUDWidth
+	if threshold <= 0 {
+		threshold = DefaultCompactHUDWidth
+	}
+	return touch || windowWidth < threshold || windowHeight < threshold*3/4
+}
+
+// GetGameSpeed returns the battle speed clamped to the supported range, defaulting to 1.0 when unset
+func (gc GameConfig) GetGameSpeed() float64 {
+	if gc.GameSpeed <= 0 {
+		return 1.0
+	}
+	return math.Max(MinGameSpeed, math.Min(MaxGameSpeed, gc.GameSpeed))
+}
+
+// Tactical pause modes
+const (
+	TacticalPauseAllowed  = "allowed"
+	TacticalPauseLimited  = "limited"
+	TacticalPauseDisabled = "disabled"
+)
+
+// GetTacticalPauseMode returns the tactical pause mode, derived from difficulty unless set explicitly
+func (gc GameConfig) GetTacticalPauseMode() string {
+	switch gc.TacticalPause {
+	case TacticalPauseAllowed, TacticalPauseLimited, TacticalPauseDisabled:
+		return gc.TacticalPause
+	}
+	
+	switch gc.Difficulty {
+	case "easy":
+		return TacticalPauseAllowed
+	case "hard":
+		return TacticalPauseDisabled
+	default:
+		return TacticalPauseLimited
+	}
 }
 
 // DefaultConfig returns the default configuration
@@ -46,6 +195,19 @@ func DefaultConfig() *Config {
 			UIScale:  1.0,
 			ShowFPS:  false,
 			VSync:    true,
+			
+			TextScale:      1.0,
+			ReduceFlashing: false,
+			
+			Bloom:           true,
+			PauseDesaturate: true,
+			NightShading:    true,
+			MoraleVignette:  true,
+			
+			HUDPreset:       HUDPresetAuto,
+			CompactHUDWidth: DefaultCompactHUDWidth,
+			
+			Theme: "dark",
 		},
 		Audio: AudioConfig{
 			MasterVolume: 0.8,
@@ -54,9 +216,21 @@ func DefaultConfig() *Config {
 			Enabled:      true,
 		},
 		Game: GameConfig{
-			Language:     "ja",
-			AutoSave:     true,
-			ShowTutorial: true,
+			Language:      "ja",
+			AutoSave:      true,
+			ShowTutorial:  true,
+			Difficulty:    "normal",
+			RubberBanding: true,
+			TacticalPause: "",
+			CommandPoints: false,
+			CommandDelay:  false,
+			GameSpeed:     1.0,
+			ObserverDelay: 10.0,
+			DirectorMode:  false,
+		},
+		Controls: ControlsConfig{
+			KeyScrollSpeed:  DefaultKeyScrollSpeed,
+			EdgeScrollSpeed: DefaultEdgeScrollSpeed,
 		},
 	}
 }
@@ -0,0 +1,131 @@
+package controls
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Action is a battle command the player can bind to a key in the settings
+type Action string
+
+// Battle commands bound to keys
+const (
+	ActionPause         Action = "pause"           // 一時停止
+	ActionTacticalPause Action = "tactical_pause"  // 作戦タイム
+	ActionSlower        Action = "slower"          // 戦闘速度を下げる
+	ActionFaster        Action = "faster"          // 戦闘速度を上げる
+	ActionStepBack      Action = "step_back"       // 停止中に1ティック戻す
+	ActionStepForward   Action = "step_forward"    // 停止中に1ティック進める
+	ActionRewind        Action = "rewind"          // 5秒巻き戻す
+	ActionResume        Action = "resume_here"     // 巻き戻した所から再開
+	ActionDirector      Action = "director"        // ディレクターモード
+	ActionEject         Action = "eject"           // 選択部隊を櫓から出す
+	ActionAssignGroup   Action = "assign_group"    // 部隊の指揮権を相方に渡す
+	ActionHelp          Action = "help"            // 操作方法
+	ActionScreenshot    Action = "screenshot"      // スクリーンショット
+	ActionSettings      Action = "settings"        // 一時停止中に設定を開く
+	ActionReturnToSetup Action = "return_to_setup" // 軍勢設定に戻る
+)
+
+// Actions lists the bindable commands in the order the settings show them
+var Actions = []Action{
+	ActionPause, ActionTacticalPause, ActionSlower, ActionFaster, ActionStepBack, ActionStepForward,
+	ActionRewind, ActionResume, ActionDirector, ActionEject, ActionAssignGroup, ActionHelp,
+	ActionScreenshot, ActionSettings, ActionReturnToSetup,
+}
+
+// defaultBindings are the keys of the commands until the player binds others
+var defaultBindings = map[Action]ebiten.Key{
+	ActionPause:         ebiten.KeyP,
+	ActionTacticalPause: ebiten.KeySpace,
+	ActionSlower:        ebiten.KeyBracketLeft,
+	ActionFaster:        ebiten.KeyBracketRight,
+	ActionStepBack:      ebiten.KeyComma,
+	ActionStepForward:   ebiten.KeyPeriod,
+	ActionRewind:        ebiten.KeyB,
+	ActionResume:        ebiten.KeyN,
+	ActionDirector:      ebiten.KeyC,
+	ActionEject:         ebiten.KeyE,
+	ActionAssignGroup:   ebiten.KeyG,
+	ActionHelp:          ebiten.KeyF2,
+	ActionScreenshot:    ebiten.KeyF12,
+	ActionSettings:      ebiten.KeyO,
+	ActionReturnToSetup: ebiten.KeyR,
+}
+
+// bindings are the keys of the commands now
+var bindings = defaultKeys()
+
+// defaultKeys returns a copy of the default bindings
+func defaultKeys() map[Action]ebiten.Key {
+	keys := make(map[Action]ebiten.Key, len(defaultBindings))
+	for action, key := range defaultBindings {
+		keys[action] = key
+	}
+	return keys
+}
+
+// SetBindings binds the keys named in the settings ("P", "Space", "BracketLeft", as ebiten names them)
+// over the defaults; unknown commands and key names are reported and left at their default
+func SetBindings(names map[string]string) {
+	bindings = defaultKeys()
+	for name, keyName := range names {
+		action := Action(name)
+		if _, exists := defaultBindings[action]; !exists {
+			fmt.Printf("Warning: unknown key binding %q\n", name)
+			continue
+		}
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(keyName)); err != nil {
+			fmt.Printf("Warning: unknown key %q for %s, using %s\n", keyName, name, defaultBindings[action])
+			continue
+		}
+		bindings[action] = key
+	}
+}
+
+// Bindings returns the key names of all commands, as SetBindings reads them
+func Bindings() map[string]string {
+	names := make(map[string]string, len(bindings))
+	for action, key := range bindings {
+		names[string(action)] = key.String()
+	}
+	return names
+}
+
+// Bind binds the key to the command; the command that had the key takes the one it gives up
+func Bind(action Action, key ebiten.Key) {
+	for other, otherKey := range bindings {
+		if otherKey == key && other != action {
+			bindings[other] = bindings[action]
+		}
+	}
+	bindings[action] = key
+}
+
+// ResetBindings binds every command to its default key again
+func ResetBindings() {
+	bindings = defaultKeys()
+}
+
+// ActionKey returns the key bound to the command
+func ActionKey(action Action) ebiten.Key {
+	return bindings[action]
+}
+
+// IsActionJustPressed reports whether the key of the command was pressed this tick
+func IsActionJustPressed(action Action) bool {
+	key, exists := bindings[action]
+	return exists && IsKeyJustPressed(key)
+}
+
+// JustPressedKey returns a key pressed this tick, for binding it to a command
+func JustPressedKey() (ebiten.Key, bool) {
+	for _, key := range current.Keys {
+		if !previous.hasKey(key) {
+			return key, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,208 @@
+package controls
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Mode is where the input of each frame comes from
+type Mode int
+
+const (
+	ModeLive      Mode = iota // 実際の入力
+	ModeRecording             // 実際の入力を記録
+	ModePlayback              // 記録した入力を再生
+	ModeScripted              // プログラムが押すキー（起動時の動作確認）
+)
+
+// mouseButtons are the mouse buttons the game reads
+var mouseButtons = []ebiten.MouseButton{
+	ebiten.MouseButtonLeft,
+	ebiten.MouseButtonRight,
+	ebiten.MouseButtonMiddle,
+}
+
+// Frame is the keyboard and mouse state of one game tick; a gamepad and touches are captured as the keys
+// and clicks they stand for
+type Frame struct {
+	Keys    []ebiten.Key         `json:"keys,omitempty"`
+	Buttons []ebiten.MouseButton `json:"buttons,omitempty"` // 0: 左, 1: 右, 2: 中
+	CursorX int                  `json:"x"`
+	CursorY int                  `json:"y"`
+	WheelX  float64              `json:"wheel_x,omitempty"`
+	WheelY  float64              `json:"wheel_y,omitempty"`
+	Text    string               `json:"text,omitempty"`  // 入力された文字（IMEで確定した文字を含む）
+	Touch   bool                 `json:"touch,omitempty"` // タッチで操作している（カーソルは最後に触れた位置）
+	Hold    bool                 `json:"hold,omitempty"`  // 指を動かさずに長押ししている
+	
+	// Number of extra ticks the same input is held
+	Repeat int `json:"repeat,omitempty"`
+}
+
+// sameInput reports whether two frames hold the same input
+func (f *Frame) sameInput(other *Frame) bool {
+	if f.CursorX != other.CursorX || f.CursorY != other.CursorY || f.WheelX != other.WheelX || f.WheelY != other.WheelY || f.Text != other.Text ||
+		f.Touch != other.Touch || f.Hold != other.Hold {
+		return false
+	}
+	if len(f.Keys) != len(other.Keys) || len(f.Buttons) != len(other.Buttons) {
+		return false
+	}
+	for i := range f.Keys {
+		if f.Keys[i] != other.Keys[i] {
+			return false
+		}
+	}
+	for i := range f.Buttons {
+		if f.Buttons[i] != other.Buttons[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasKey reports whether the key is held in the frame
+func (f *Frame) hasKey(key ebiten.Key) bool {
+	for _, k := range f.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// hasButton reports whether the mouse button is held in the frame
+func (f *Frame) hasButton(button ebiten.MouseButton) bool {
+	for _, b := range f.Buttons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
+
+// captureFrame reads the current input from ebiten
+func captureFrame() Frame {
+	frame := Frame{
+		Keys: inpututil.AppendPressedKeys(nil),
+	}
+	for _, button := range mouseButtons {
+		if ebiten.IsMouseButtonPressed(button) {
+			frame.Buttons = append(frame.Buttons, button)
+		}
+	}
+	frame.CursorX, frame.CursorY = ebiten.CursorPosition()
+	frame.WheelX, frame.WheelY = ebiten.Wheel()
+	frame.Text = captureText()
+	
+	// Touches and the gamepad stand in for the mouse until it moves or the keyboard is used
+	mouseMoved := frame.CursorX != mouseX || frame.CursorY != mouseY
+	mouseX, mouseY = frame.CursorX, frame.CursorY
+	deviceUsed := mouseMoved || len(frame.Keys) > 0 || len(frame.Buttons) > 0
+	captureTouch(&frame, deviceUsed)
+	capturePad(&frame, deviceUsed)
+	return frame
+}
+
+// Input state shared by every scene
+var (
+	mode     Mode
+	current  Frame
+	previous Frame
+	
+	recording *Recording
+	playIndex int // 再生中のフレーム
+	playTick  int // 再生中のフレームを繰り返した回数
+	finished  bool
+	
+	lastUpdate time.Time
+	deltaTime  float64
+	
+	mouseX, mouseY int // 前のティックのマウスの位置
+)
+
+// GetMode returns where the input comes from
+func GetMode() Mode {
+	return mode
+}
+
+// Update advances the input state by one tick; call it once at the start of every game update
+func Update() {
+	previous = current
+	
+	now := time.Now()
+	if !lastUpdate.IsZero() {
+		deltaTime = now.Sub(lastUpdate).Seconds()
+	}
+	lastUpdate = now
+	
+	switch mode {
+	case ModePlayback:
+		current = nextPlaybackFrame()
+	case ModeRecording:
+		current = captureFrame()
+		recording.append(current)
+	case ModeScripted:
+		current = nextScriptedFrame()
+	default:
+		current = captureFrame()
+	}
+}
+
+// DeltaTime returns the time step of the current tick
+// Recording, playback and scripted runs use a fixed step so replays are deterministic
+func DeltaTime() float64 {
+	if mode != ModeLive {
+		return 1.0 / float64(ebiten.TPS())
+	}
+	return deltaTime
+}
+
+// IsKeyPressed reports whether the key is held
+func IsKeyPressed(key ebiten.Key) bool {
+	return current.hasKey(key)
+}
+
+// IsKeyJustPressed reports whether the key was pressed this tick
+func IsKeyJustPressed(key ebiten.Key) bool {
+	return current.hasKey(key) && !previous.hasKey(key)
+}
+
+// IsShortcutJustPressed reports whether the key was pressed this tick with Ctrl (or Cmd on macOS) held
+func IsShortcutJustPressed(key ebiten.Key) bool {
+	modifier := IsKeyPressed(ebiten.KeyControlLeft) || IsKeyPressed(ebiten.KeyControlRight) ||
+		IsKeyPressed(ebiten.KeyMetaLeft) || IsKeyPressed(ebiten.KeyMetaRight)
+	return modifier && IsKeyJustPressed(key)
+}
+
+// IsMouseButtonPressed reports whether the mouse button is held
+func IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return current.hasButton(button)
+}
+
+// IsMouseButtonJustPressed reports whether the mouse button was pressed this tick
+func IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
+	return current.hasButton(button) && !previous.hasButton(button)
+}
+
+// IsMouseButtonJustReleased reports whether the mouse button was released this tick
+func IsMouseButtonJustReleased(button ebiten.MouseButton) bool {
+	return !current.hasButton(button) && previous.hasButton(button)
+}
+
+// CursorPosition returns the mouse cursor position
+func CursorPosition() (int, int) {
+	return current.CursorX, current.CursorY
+}
+
+// CursorMoved reports whether the mouse cursor moved since the last tick
+func CursorMoved() bool {
+	return current.CursorX != previous.CursorX || current.CursorY != previous.CursorY
+}
+
+// Wheel returns the mouse wheel movement of this tick
+func Wheel() (float64, float64) {
+	return current.WheelX, current.WheelY
+}
@@ -0,0 +1,161 @@
+package controls
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Gamepad tuning
+const (
+	stickThreshold   = 0.5   // 左スティックをこれより倒すと方向キーを押したとみなす
+	pointerDeadZone  = 0.2   // 右スティックの遊び
+	pointerSpeed     = 600.0 // 右スティックで動かすカーソルの速さ（px/秒）
+	triggerDeadZone  = 0.05  // トリガーの遊び
+	triggerZoomSpeed = 0.1   // トリガーを引き切ったときの1ティックのホイール量
+	pointerWidth     = 1024  // カーソルを動かせる論理画面の大きさ
+	pointerHeight    = 768
+)
+
+// padDirections are the arrow keys the d-pad and the left stick press
+var padDirections = []struct {
+	button ebiten.StandardGamepadButton
+	axis   ebiten.StandardGamepadAxis
+	sign   float64
+	key    ebiten.Key
+}{
+	{ebiten.StandardGamepadButtonLeftTop, ebiten.StandardGamepadAxisLeftStickVertical, -1, ebiten.KeyArrowUp},
+	{ebiten.StandardGamepadButtonLeftBottom, ebiten.StandardGamepadAxisLeftStickVertical, 1, ebiten.KeyArrowDown},
+	{ebiten.StandardGamepadButtonLeftLeft, ebiten.StandardGamepadAxisLeftStickHorizontal, -1, ebiten.KeyArrowLeft},
+	{ebiten.StandardGamepadButtonLeftRight, ebiten.StandardGamepadAxisLeftStickHorizontal, 1, ebiten.KeyArrowRight},
+}
+
+// padActions are the commands the other buttons press, with the keys bound to them
+var padActions = []struct {
+	button ebiten.StandardGamepadButton
+	action Action
+}{
+	{ebiten.StandardGamepadButtonCenterRight, ActionPause},       // START
+	{ebiten.StandardGamepadButtonRightLeft, ActionTacticalPause}, // X
+	{ebiten.StandardGamepadButtonRightTop, ActionHelp},           // Y
+	{ebiten.StandardGamepadButtonFrontTopLeft, ActionSlower},     // LB
+	{ebiten.StandardGamepadButtonFrontTopRight, ActionFaster},    // RB
+}
+
+// Gamepad state kept between ticks while the input is live
+var (
+	padActive  bool    // 最後の入力がゲームパッド
+	padPointer bool    // 右スティックでカーソルを動かしている（A/Bがクリックになる）
+	padX, padY float64 // 右スティックで動かすカーソルの位置
+)
+
+// standardGamepad returns the first connected gamepad with the standard layout
+func standardGamepad() (ebiten.GamepadID, bool) {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// capturePad adds the gamepad to the frame as the keyboard and mouse input it stands for, so every
+// scene reads it without knowing of it and recordings replay it: the d-pad and the left stick press
+// the arrow keys, A and B press Enter and Esc, or click while the right stick moves the cursor,
+// the triggers turn the wheel and the other buttons press the keys bound to their commands
+// deviceUsed tells that the keyboard or the mouse was used this tick, which leaves the gamepad
+func capturePad(frame *Frame, deviceUsed bool) {
+	if deviceUsed {
+		padActive = false
+		padPointer = false
+	}
+	
+	id, ok := standardGamepad()
+	if !ok {
+		padPointer = false
+		return
+	}
+	used := false
+	press := func(key ebiten.Key) {
+		if !frame.hasKey(key) {
+			frame.Keys = append(frame.Keys, key)
+		}
+		used = true
+	}
+	
+	// The d-pad leaves the cursor for the keys; the left stick keeps it, to pan the camera while pointing
+	for _, direction := range padDirections {
+		if ebiten.IsStandardGamepadButtonPressed(id, direction.button) {
+			press(direction.key)
+			padPointer = false
+		} else if ebiten.StandardGamepadAxisValue(id, direction.axis)*direction.sign > stickThreshold {
+			press(direction.key)
+		}
+	}
+	
+	// The right stick takes over the cursor from where the mouse left it
+	x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisRightStickVertical)
+	if math.Hypot(x, y) > pointerDeadZone {
+		if !padPointer {
+			padPointer = true
+			padX, padY = float64(frame.CursorX), float64(frame.CursorY)
+		}
+		step := pointerSpeed / float64(ebiten.TPS())
+		padX = math.Max(0, math.Min(pointerWidth-1, padX+x*step))
+		padY = math.Max(0, math.Min(pointerHeight-1, padY+y*step))
+		used = true
+	}
+	if padPointer {
+		frame.CursorX, frame.CursorY = int(padX), int(padY)
+	}
+	
+	clicks := []struct {
+		button ebiten.StandardGamepadButton
+		mouse  ebiten.MouseButton
+		key    ebiten.Key
+	}{
+		{ebiten.StandardGamepadButtonRightBottom, ebiten.MouseButtonLeft, ebiten.KeyEnter},  // A
+		{ebiten.StandardGamepadButtonRightRight, ebiten.MouseButtonRight, ebiten.KeyEscape}, // B
+	}
+	for _, click := range clicks {
+		if !ebiten.IsStandardGamepadButtonPressed(id, click.button) {
+			continue
+		}
+		if !padPointer {
+			press(click.key)
+			continue
+		}
+		if !frame.hasButton(click.mouse) {
+			frame.Buttons = append(frame.Buttons, click.mouse)
+		}
+		used = true
+	}
+	for _, pad := range padActions {
+		if ebiten.IsStandardGamepadButtonPressed(id, pad.button) {
+			press(ActionKey(pad.action))
+		}
+	}
+	
+	// The right trigger zooms in and the left one out
+	zoom := ebiten.StandardGamepadButtonValue(id, ebiten.StandardGamepadButtonFrontBottomRight) -
+		ebiten.StandardGamepadButtonValue(id, ebiten.StandardGamepadButtonFrontBottomLeft)
+	if math.Abs(zoom) > triggerDeadZone {
+		frame.WheelY += zoom * triggerZoomSpeed
+		used = true
+	}
+	
+	if used {
+		padActive = true
+	}
+}
+
+// GamepadActive reports whether the last input came from a gamepad, for showing its button hints
+func GamepadActive() bool {
+	return padActive
+}
+
+// GamepadPointer reports whether the right stick moves the cursor, which is then drawn on the screen
+func GamepadPointer() bool {
+	return padActive && padPointer
+}
@@ -0,0 +1,103 @@
+package controls
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// RecordingVersion is the current input recording format
+const RecordingVersion = 1
+
+// Recording is a sequence of input frames saved to or loaded from a JSON file
+type Recording struct {
+	Version int     `json:"version"`
+	Seed    int64   `json:"seed"` // 戦闘の乱数シード
+	Frames  []Frame `json:"frames"`
+}
+
+// append adds a frame, merging it into the previous frame when the input did not change
+func (r *Recording) append(frame Frame) {
+	if n := len(r.Frames); n > 0 && r.Frames[n-1].sameInput(&frame) {
+		r.Frames[n-1].Repeat++
+		return
+	}
+	r.Frames = append(r.Frames, frame)
+}
+
+// StartRecording records the live input from the next tick on
+func StartRecording(seed int64) {
+	mode = ModeRecording
+	recording = &Recording{
+		Version: RecordingVersion,
+		Seed:    seed,
+	}
+}
+
+// SaveRecording writes the recorded input to a JSON file
+func SaveRecording(path string) error {
+	if mode != ModeRecording || recording == nil {
+		return fmt.Errorf("input is not being recorded")
+	}
+	
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write recording %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadPlayback replaces the live input with a recording loaded from a JSON file
+func LoadPlayback(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+	
+	var loaded Recording
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("failed to parse recording %s: %w", path, err)
+	}
+	if loaded.Version != RecordingVersion {
+		return fmt.Errorf("unsupported recording version %d in %s", loaded.Version, path)
+	}
+	
+	mode = ModePlayback
+	recording = &loaded
+	playIndex = 0
+	playTick = 0
+	finished = len(loaded.Frames) == 0
+	return nil
+}
+
+// nextPlaybackFrame returns the recorded input for the current tick
+func nextPlaybackFrame() Frame {
+	if playIndex >= len(recording.Frames) {
+		finished = true
+		return Frame{CursorX: current.CursorX, CursorY: current.CursorY}
+	}
+	
+	frame := recording.Frames[playIndex]
+	playTick++
+	if playTick > frame.Repeat {
+		playIndex++
+		playTick = 0
+	}
+	return frame
+}
+
+// IsPlaybackFinished reports whether every recorded frame has been played
+func IsPlaybackFinished() bool {
+	return mode == ModePlayback && finished
+}
+
+// Seed returns the random seed of the recording, if input is being recorded or played back
+func Seed() (int64, bool) {
+	if mode == ModeLive || recording == nil {
+		return 0, false
+	}
+	return recording.Seed, true
+}
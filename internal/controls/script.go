@@ -0,0 +1,29 @@
+package controls
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// scripted is the input the script set up for the next tick
+var scripted Frame
+
+// StartScript replaces the live input with keys pressed by the program through Press,
+// stepping time by a fixed amount every tick
+func StartScript() {
+	mode = ModeScripted
+}
+
+// Press holds the keys in the next tick's input; a key pressed on two ticks in a row counts as held
+func Press(keys ...ebiten.Key) {
+	scripted.Keys = append(scripted.Keys, keys...)
+}
+
+// nextScriptedFrame returns the input the script set up and clears it for the tick after
+func nextScriptedFrame() Frame {
+	frame := scripted
+	scripted = Frame{}
+	return frame
+}
+
+// IsPlayed reports whether a person is giving the input; replays and scripted runs leave the saves alone
+func IsPlayed() bool {
+	return mode == ModeLive || mode == ModeRecording
+}
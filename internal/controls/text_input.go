@@ -0,0 +1,105 @@
+package controls
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/exp/textinput"
+)
+
+// IME session of the focused text field
+var (
+	textRequested bool // このティックにテキスト入力が求められた
+	textX, textY  int  // IMEの候補ウィンドウを出す位置
+	textStates    chan textinput.State
+	endText       func()
+	composition   string // IMEで変換中の未確定の文字列
+	imeFailed     bool   // IMEのセッションでエラーが起き、直接のキー入力だけを使う
+)
+
+// StartTextInput asks for typed text from the next tick on; call it every tick while a text field has focus
+// The IME shows its candidates at the screen position; the session ends one tick after the calls stop
+func StartTextInput(x, y int) {
+	textRequested = true
+	textX, textY = x, y
+}
+
+// TypedText returns the text typed this tick, including text committed by the IME
+// Typed text is recorded and replayed like the other input
+func TypedText() string {
+	return current.Text
+}
+
+// Composition returns the text being composed in the IME, not yet committed
+// It is empty during playback, which only replays committed text
+func Composition() string {
+	return composition
+}
+
+// captureText reads the text typed since the last tick while a text field asks for it
+// IME sessions deliver both committed text and the composition; platforms without them fall back to AppendInputChars
+func captureText() string {
+	if !textRequested {
+		stopTextInput()
+		return ""
+	}
+	textRequested = false
+	if imeFailed {
+		return string(ebiten.AppendInputChars(nil))
+	}
+	
+	var typed strings.Builder
+	for first := true; ; first = false {
+		if textStates == nil {
+			textStates, endText = textinput.Start(textX, textY)
+			if textStates == nil {
+				if first {
+					return string(ebiten.AppendInputChars(nil))
+				}
+				return typed.String()
+			}
+		}
+		if !drainText(&typed) {
+			return typed.String()
+		}
+	}
+}
+
+// drainText reads the pending states of the IME session into the typed text and the composition,
+// reporting whether the session ended and another one should be started
+func drainText(typed *strings.Builder) bool {
+	for {
+		select {
+		case state, ok := <-textStates:
+			if state.Error != nil {
+				fmt.Printf("Warning: IME unavailable, falling back to direct key input: %v\n", state.Error)
+				imeFailed = true
+				stopTextInput()
+				return false
+			}
+			if !ok {
+				textStates, endText = nil, nil
+				composition = ""
+				return true
+			}
+			if state.Committed {
+				typed.WriteString(state.Text)
+				composition = ""
+				continue
+			}
+			composition = state.Text
+		default:
+			return false
+		}
+	}
+}
+
+// stopTextInput ends the IME session once no text field has focus
+func stopTextInput() {
+	if endText != nil {
+		endText()
+	}
+	textStates, endText = nil, nil
+	composition = ""
+}
@@ -0,0 +1,107 @@
+package controls
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Touch gesture tuning
+const (
+	touchSlop      = 12.0 // 指がこれ以上動いたらタップではなくドラッグ（px）
+	longPressTime  = 0.5  // 長押しとみなす秒数
+	pinchZoomScale = 80.0 // ホイール1段ぶんのピンチの幅（px）
+)
+
+// Touch state kept between ticks while the input is live
+var (
+	touchIDs     []ebiten.TouchID
+	touchActive  bool // 最後の入力がタッチ
+	touching     bool // 指が画面に触れている
+	touchTicks   int  // 今の指が触れてからのティック数
+	touchStartX  int
+	touchStartY  int
+	touchX       int // 最後に触れた位置
+	touchY       int
+	touchDrag    bool    // 指が動いたのでカメラのドラッグとみなす
+	touchHeld    bool    // 長押しになった
+	touchPinched bool    // 2本目の指が触れた（指がすべて離れるまでタップにしない）
+	pinchSpan    float64 // 前のティックの2本の指の距離（0: ピンチしていない）
+)
+
+// captureTouch adds the touches to the frame as the mouse input they stand for: a tap clicks the left
+// button where it touched, a drag holds the middle button so the camera follows the finger, a pinch
+// turns the wheel around the middle of the two fingers and a long press holds Frame.Hold
+// deviceUsed tells that the keyboard or the mouse was used this tick, which leaves the touch screen
+func captureTouch(frame *Frame, deviceUsed bool) {
+	if deviceUsed {
+		touchActive = false
+	}
+	
+	touchIDs = ebiten.AppendTouchIDs(touchIDs[:0])
+	switch len(touchIDs) {
+	case 0:
+		if touching && !touchDrag && !touchHeld && !touchPinched {
+			frame.Buttons = append(frame.Buttons, ebiten.MouseButtonLeft)
+		}
+		touching, touchDrag, touchHeld, touchPinched = false, false, false, false
+		pinchSpan = 0
+	case 1:
+		x, y := ebiten.TouchPosition(touchIDs[0])
+		if !touching {
+			touching = true
+			touchTicks = 0
+			touchStartX, touchStartY = x, y
+		}
+		touchTicks++
+		touchX, touchY = x, y
+		touchActive = true
+		pinchSpan = 0
+		if touchPinched {
+			break
+		}
+		
+		moved := math.Hypot(float64(x-touchStartX), float64(y-touchStartY))
+		if !touchHeld && moved > touchSlop {
+			touchDrag = true
+		}
+		if !touchDrag && float64(touchTicks) >= longPressTime*float64(ebiten.TPS()) {
+			touchHeld = true
+		}
+		if touchDrag {
+			frame.Buttons = append(frame.Buttons, ebiten.MouseButtonMiddle)
+		}
+		frame.Hold = touchHeld
+	default:
+		x1, y1 := ebiten.TouchPosition(touchIDs[0])
+		x2, y2 := ebiten.TouchPosition(touchIDs[1])
+		span := math.Hypot(float64(x2-x1), float64(y2-y1))
+		if pinchSpan > 0 {
+			frame.WheelY += (span - pinchSpan) / pinchZoomScale
+		}
+		pinchSpan = span
+		touching, touchPinched, touchHeld = true, true, false
+		touchX, touchY = (x1+x2)/2, (y1+y2)/2
+		touchActive = true
+	}
+	
+	if touchActive {
+		frame.CursorX, frame.CursorY = touchX, touchY
+		frame.Touch = true
+	}
+}
+
+// TouchActive reports whether the last input came from the touch screen, which asks for larger buttons
+func TouchActive() bool {
+	return touchActive
+}
+
+// IsTouch reports whether this tick's input comes from the touch screen; it is recorded with the frame
+func IsTouch() bool {
+	return current.Touch
+}
+
+// IsLongPressed reports whether a finger is held still on the screen, at the cursor position
+func IsLongPressed() bool {
+	return current.Hold
+}
@@ -0,0 +1,34 @@
+package data
+
+// Ability effects
+const (
+	AbilityBolt  = "bolt"  // 目標の1体に当たる
+	AbilityBurst = "burst" // 目標の周りの敵も巻き込む
+)
+
+// AbilityConfig represents a spell from abilities.toml, cast by the unit types that list it in abilities
+type AbilityConfig struct {
+	Name            string  `toml:"name"`
+	Effect          string  `toml:"effect"`           // 効果（"bolt": 単体、"burst": 範囲）
+	Damage          int     `toml:"damage"`           // 術者の攻撃力と魔法攻撃力に加えるダメージ
+	Radius          float64 `toml:"radius"`           // burst の効果範囲（0: 8m）
+	Splash          float64 `toml:"splash"`           // burst が目標の周りの敵に与えるダメージの割合（0: 0.6）
+	Cluster         int     `toml:"cluster"`          // AIが burst を使う、目標の周りに固まった敵の数の下限（0: 3体）
+	ManaCost        float64 `toml:"mana_cost"`        // 1回の消費魔力
+	Cooldown        float64 `toml:"cooldown"`         // 唱えてから次に唱えられるまでの秒数（0: 攻撃の間隔のみ）
+	Channel         float64 `toml:"channel"`          // 詠唱時間（秒、0: 詠唱なしで放つ）
+	ProjectileSpeed float64 `toml:"projectile_speed"` // 魔法弾の飛ぶ速さ（px/秒、0: すぐに当たる）
+	Ignite          bool    `toml:"ignite"`           // 当たった森や藪に火をつける
+	Visual          string  `toml:"visual"`           // 詠唱の光と魔法弾の色 "#RRGGBB"（省略時は紫）
+}
+
+// AbilitiesConfig represents the entire abilities configuration
+type AbilitiesConfig struct {
+	Abilities map[string]AbilityConfig `toml:"abilities"`
+}
+
+// GetAbilityConfig returns the configuration for a specific ability
+func (ac *AbilitiesConfig) GetAbilityConfig(abilityID string) (AbilityConfig, bool) {
+	config, exists := ac.Abilities[abilityID]
+	return config, exists
+}
@@ -0,0 +1,43 @@
+package data
+
+// Bark occasions
+const (
+	BarkSelect = "select" // 選択されたとき
+	BarkOrder  = "order"  // 命令を受けたとき
+	BarkRout   = "rout"   // 敗走を始めたとき
+)
+
+// BarkConfig lists the short lines a unit type says on each occasion from TOML
+// Empty lists fall back to the default table
+type BarkConfig struct {
+	Select []string `toml:"select"`
+	Order  []string `toml:"order"`
+	Rout   []string `toml:"rout"`
+}
+
+// BarksConfig represents the bark tables per unit type, with "default" for types without their own
+type BarksConfig struct {
+	Barks map[string]BarkConfig `toml:"barks"`
+}
+
+// Lines returns the lines for the occasion
+func (bc BarkConfig) Lines(occasion string) []string {
+	switch occasion {
+	case BarkSelect:
+		return bc.Select
+	case BarkOrder:
+		return bc.Order
+	case BarkRout:
+		return bc.Rout
+	default:
+		return nil
+	}
+}
+
+// GetBarkLines returns what the unit type may say on the occasion, from its own table or the default one
+func (bc *BarksConfig) GetBarkLines(unitType, occasion string) []string {
+	if lines := bc.Barks[unitType].Lines(occasion); len(lines) > 0 {
+		return lines
+	}
+	return bc.Barks["default"].Lines(occasion)
+}
@@ -0,0 +1,79 @@
+package data
+
+// Campaign sides
+const (
+	SidePlayer = "player" // プレイヤー
+	SideEnemy  = "enemy"  // 敵
+)
+
+// CampaignConfig represents the overworld map of the campaign from TOML
+type CampaignConfig struct {
+	Provinces  []ProvinceConfig                    `toml:"provinces"`
+	Armies     []CampaignArmyConfig                `toml:"armies"`
+	Economy    CampaignEconomyConfig               `toml:"economy"`
+	Recruits   []RecruitConfig                     `toml:"recruits"`
+	Events     []CampaignEventConfig               `toml:"events"`
+	Difficulty map[string]CampaignDifficultyConfig `toml:"difficulty"` // Keyed by the game difficulty
+}
+
+// CampaignDifficultyConfig scales the enemy armies fielded in campaign battles
+type CampaignDifficultyConfig struct {
+	Budget     float64 `toml:"budget"`      // Multiplier on the gold value of the enemy army's groups
+	RubberBand float64 `toml:"rubber_band"` // Largest budget change from the player's standing on the map (0: none)
+}
+
+// CampaignEconomyConfig sets the gold both sides earn and spend each turn
+type CampaignEconomyConfig struct {
+	StartingGold   int `toml:"starting_gold"`   // Gold each side starts with
+	ProvinceIncome int `toml:"province_income"` // Gold per held province each turn
+	UnitUpkeep     int `toml:"unit_upkeep"`     // Gold per soldier each turn
+}
+
+// CampaignEventConfig represents a card of the event deck drawn at the start of each turn
+type CampaignEventConfig struct {
+	ID          string              `toml:"id"`
+	Name        string              `toml:"name"`
+	Description string              `toml:"description"`
+	Copies      int                 `toml:"copies"` // Cards of the event in the deck (0: 1)
+	Choices     []EventChoiceConfig `toml:"choices"`
+}
+
+// EventChoiceConfig represents one answer to an event and its effects on the player's side
+type EventChoiceConfig struct {
+	Label   string `toml:"label"`
+	Gold    int    `toml:"gold"`    // Gold gained (negative: paid, and the choice needs that much)
+	Disband int    `toml:"disband"` // Groups leaving the largest armies
+	Recruit string `toml:"recruit"` // Name of a recruit joining the smallest army for free
+	Revolt  int    `toml:"revolt"`  // Undefended provinces falling away to unclaimed
+}
+
+// RecruitConfig represents a group that can be raised in a held province
+type RecruitConfig struct {
+	Name   string `toml:"name"`
+	Leader string `toml:"leader"`
+	Member string `toml:"member"`
+	Count  int    `toml:"count"`
+	Cost   int    `toml:"cost"` // Gold paid once when raised
+}
+
+// ProvinceConfig represents one node of the overworld map
+type ProvinceConfig struct {
+	ID    string   `toml:"id"`
+	Name  string   `toml:"name"`
+	X     float64  `toml:"x"` // Position on the map screen
+	Y     float64  `toml:"y"`
+	Stage string   `toml:"stage"` // Stage config ID fought over the province
+	Owner string   `toml:"owner"` // "player", "enemy" or empty for unclaimed
+	Links []string `toml:"links"` // Provinces reachable in one turn (links are mutual)
+}
+
+// CampaignArmyConfig represents an army standing on the overworld map at the start of the campaign
+type CampaignArmyConfig struct {
+	ID       string `toml:"id"`
+	Name     string `toml:"name"`
+	Side     string `toml:"side"`     // "player" or "enemy"
+	Province string `toml:"province"` // Province ID where the army starts
+	Preset   string `toml:"preset"`   // Name of the army's formation, kept in the battle records
+	
+	Groups []ReinforcementGroupConfig `toml:"groups"` // Groups fielded in battle
+}
@@ -0,0 +1,115 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Challenge goal kinds, each worth a star on top of clearing the challenge
+const (
+	GoalTime      = "time"      // この秒数以内に勝利
+	GoalSurvivors = "survivors" // 自軍のこの割合以上が生き残って勝利
+)
+
+// ChallengeConfig represents a scenario challenge from TOML: a stage fought with the custom army
+// under limits on its groups, rated by the goals met on top of the win
+type ChallengeConfig struct {
+	Name            string          `toml:"name"`
+	Description     string          `toml:"description"`
+	Order           int             `toml:"order"`             // チャレンジ一覧での並び順
+	Stage           string          `toml:"stage"`             // ステージ設定ID
+	EnemyPreset     string          `toml:"enemy_preset"`      // 敵軍のプリセット（空: バランス型）
+	Mutators        []string        `toml:"mutators"`          // 有効にする特殊ルールのID
+	PointBudgetRate float64         `toml:"point_budget_rate"` // 自軍編成の戦力点の上限の倍率（0: そのまま）
+	MaxGroups       int             `toml:"max_groups"`        // 出陣できる部隊の数（0: 自軍編成の上限）
+	AllowedUnits    []string        `toml:"allowed_units"`     // 出陣できるユニットの種類（空: すべて）
+	Goals           []ChallengeGoal `toml:"goals"`
+}
+
+// ChallengeGoal is an extra star of a challenge, earned when the win meets it
+type ChallengeGoal struct {
+	Kind  string  `toml:"kind"` // GoalTime / GoalSurvivors
+	Value float64 `toml:"value"`
+}
+
+// ChallengesConfig represents the entire challenges configuration
+type ChallengesConfig struct {
+	Challenges map[string]ChallengeConfig `toml:"challenges"`
+}
+
+// ChallengeListing names a challenge in the challenge list
+type ChallengeListing struct {
+	ID   string // チャレンジID
+	Name string // 表示名
+}
+
+// GetChallengeConfig returns the configuration for a specific challenge
+func (cc *ChallengesConfig) GetChallengeConfig(challengeID string) (ChallengeConfig, bool) {
+	config, exists := cc.Challenges[challengeID]
+	return config, exists
+}
+
+// PointBudget returns the points the challenge allows out of the custom army's budget
+func (cc ChallengeConfig) PointBudget(recruitment *RecruitmentConfig) int {
+	if cc.PointBudgetRate <= 0 {
+		return recruitment.PointBudget
+	}
+	return int(float64(recruitment.PointBudget) * cc.PointBudgetRate)
+}
+
+// GroupLimit returns the number of groups the challenge allows
+func (cc ChallengeConfig) GroupLimit(recruitment *RecruitmentConfig) int {
+	if cc.MaxGroups <= 0 {
+		return recruitment.MaxGroups
+	}
+	return min(cc.MaxGroups, recruitment.MaxGroups)
+}
+
+// AllowsUnit reports whether groups of the unit type may take the field
+func (cc ChallengeConfig) AllowsUnit(unitType string) bool {
+	return len(cc.AllowedUnits) == 0 || slices.Contains(cc.AllowedUnits, unitType)
+}
+
+// MaxStars returns the stars the challenge can award: one for the win and one per goal
+func (cc ChallengeConfig) MaxStars() int {
+	return 1 + len(cc.Goals)
+}
+
+// Stars rates a finished attempt: none for a battle not won, otherwise one plus one per goal met
+// survivors is the share of the player's units alive at the end
+func (cc ChallengeConfig) Stars(won bool, battleTime, survivors float64) int {
+	if !won {
+		return 0
+	}
+	stars := 1
+	for _, goal := range cc.Goals {
+		if goal.Met(battleTime, survivors) {
+			stars++
+		}
+	}
+	return stars
+}
+
+// Met reports whether a won battle meets the goal
+func (cg ChallengeGoal) Met(battleTime, survivors float64) bool {
+	switch cg.Kind {
+	case GoalTime:
+		return battleTime <= cg.Value
+	case GoalSurvivors:
+		return survivors >= cg.Value
+	default:
+		return false
+	}
+}
+
+// String describes the goal as shown in the challenge list
+func (cg ChallengeGoal) String() string {
+	switch cg.Kind {
+	case GoalTime:
+		return fmt.Sprintf("%d秒以内に勝利", int(cg.Value))
+	case GoalSurvivors:
+		return fmt.Sprintf("自軍の%d%%以上が生存して勝利", int(cg.Value*100))
+	default:
+		return cg.Kind
+	}
+}
@@ -0,0 +1,23 @@
+package data
+
+// DoctrineConfig represents an army-wide passive doctrine from TOML
+// Zero modifiers leave the stat unchanged
+type DoctrineConfig struct {
+	Name                 string  `toml:"name"`
+	Description          string  `toml:"description"`
+	DefenseModifier      float64 `toml:"defense_modifier"`
+	SpeedModifier        float64 `toml:"speed_modifier"`
+	PursuitSpeedModifier float64 `toml:"pursuit_speed_modifier"` // Speed near fleeing enemies; also lets the army strike them
+	PursuitRange         float64 `toml:"pursuit_range"`
+}
+
+// DoctrinesConfig represents the entire doctrines configuration
+type DoctrinesConfig struct {
+	Doctrines map[string]DoctrineConfig `toml:"doctrines"`
+}
+
+// GetDoctrineConfig returns the configuration for a specific doctrine
+func (dc *DoctrinesConfig) GetDoctrineConfig(doctrineID string) (DoctrineConfig, bool) {
+	config, exists := dc.Doctrines[doctrineID]
+	return config, exists
+}
@@ -0,0 +1,26 @@
+package data
+
+// LanguageConfig represents the text metrics of one UI language from TOML
+// Japanese and Latin scripts look different at the same font size, so each language sets its own
+// Zero values leave the metric unchanged
+type LanguageConfig struct {
+	Name        string  `toml:"name"`
+	FontScale   float64 `toml:"font_scale"`   // Multiplier on the configured font size
+	LineSpacing float64 `toml:"line_spacing"` // Multiplier on the spacing between rows of text
+}
+
+// I18nConfig represents the per-language tables
+type I18nConfig struct {
+	Languages map[string]LanguageConfig `toml:"languages"`
+}
+
+// GetLanguageConfig returns the configuration for a specific language
+func (ic *I18nConfig) GetLanguageConfig(language string) (LanguageConfig, bool) {
+	config, exists := ic.Languages[language]
+	return config, exists
+}
+
+// ListLanguages returns the IDs of the UI languages in order
+func (dm *DataManager) ListLanguages() []string {
+	return sortedKeys(dm.I18n.Languages)
+}
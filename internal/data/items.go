@@ -0,0 +1,81 @@
+package data
+
+// Equipment slots of a group's leader
+const (
+	SlotWeapon = "weapon"
+	SlotArmor  = "armor"
+	SlotBanner = "banner"
+)
+
+// EquipmentSlots lists the slots in display order
+var EquipmentSlots = []string{SlotWeapon, SlotArmor, SlotBanner}
+
+// ItemConfig represents an item a leader can carry, from TOML
+// Stat bonuses are added to the leader; zero values leave the stat unchanged
+type ItemConfig struct {
+	Name          string  `toml:"name"`
+	Slot          string  `toml:"slot"` // "weapon", "armor" or "banner"
+	Description   string  `toml:"description"`
+	HP            int     `toml:"hp"`
+	Attack        int     `toml:"attack"`
+	Magic         int     `toml:"magic"`
+	Defense       int     `toml:"defense"`
+	SpeedModifier float64 `toml:"speed_modifier"`
+	Regeneration  float64 `toml:"regeneration"` // Hit points the leader recovers per second
+	Morale        float64 `toml:"morale"`       // Added to the maximum morale of the whole group
+}
+
+// ItemsConfig represents the entire items configuration
+type ItemsConfig struct {
+	Items map[string]ItemConfig `toml:"items"`
+}
+
+// GetItemConfig returns the configuration for a specific item
+func (ic *ItemsConfig) GetItemConfig(itemID string) (ItemConfig, bool) {
+	config, exists := ic.Items[itemID]
+	return config, exists
+}
+
+// Equipment is the items a group's leader carries, by item ID ("" for an empty slot)
+type Equipment struct {
+	Weapon string `toml:"weapon"`
+	Armor  string `toml:"armor"`
+	Banner string `toml:"banner"`
+}
+
+// Get returns the item ID in the slot
+func (e Equipment) Get(slot string) string {
+	switch slot {
+	case SlotWeapon:
+		return e.Weapon
+	case SlotArmor:
+		return e.Armor
+	case SlotBanner:
+		return e.Banner
+	default:
+		return ""
+	}
+}
+
+// Set puts the item ID in the slot; unknown slots are ignored
+func (e *Equipment) Set(slot, itemID string) {
+	switch slot {
+	case SlotWeapon:
+		e.Weapon = itemID
+	case SlotArmor:
+		e.Armor = itemID
+	case SlotBanner:
+		e.Banner = itemID
+	}
+}
+
+// Items returns the item IDs of the filled slots in slot order
+func (e Equipment) Items() []string {
+	var ids []string
+	for _, slot := range EquipmentSlots {
+		if id := e.Get(slot); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -3,38 +3,105 @@ package data
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
 // DataManager manages all game data
 type DataManager struct {
-	Units    *UnitsConfig
-	Terrains *TerrainsConfig
-	Stages   *StagesConfig
+	Units       *UnitsConfig
+	Abilities   *AbilitiesConfig
+	Terrains    *TerrainsConfig
+	Stages      *StagesConfig
+	Doctrines   *DoctrinesConfig
+	Items       *ItemsConfig
+	Barks       *BarksConfig
+	Campaign    *CampaignConfig
+	Recruitment *RecruitmentConfig
+	Challenges  *ChallengesConfig
+	I18n        *I18nConfig
+	Themes      *ThemesConfig
+	Presets     *PresetsConfig
+	
+	// Mod folders under mods/ merged over the data by LoadAll, lowest priority first
+	Mods []string
 }
 
 // NewDataManager creates a new data manager
 func NewDataManager() *DataManager {
 	return &DataManager{
-		Units:    &UnitsConfig{UnitTypes: make(map[string]UnitTypeConfig)},
-		Terrains: &TerrainsConfig{TerrainTypes: make(map[string]TerrainConfig)},
-		Stages:   &StagesConfig{Stages: make(map[string]StageConfig)},
+		Units:       &UnitsConfig{UnitTypes: make(map[string]UnitTypeConfig)},
+		Abilities:   &AbilitiesConfig{Abilities: make(map[string]AbilityConfig)},
+		Terrains:    &TerrainsConfig{TerrainTypes: make(map[string]TerrainConfig)},
+		Stages:      &StagesConfig{Stages: make(map[string]StageConfig)},
+		Doctrines:   &DoctrinesConfig{Doctrines: make(map[string]DoctrineConfig)},
+		Items:       &ItemsConfig{Items: make(map[string]ItemConfig)},
+		Barks:       &BarksConfig{Barks: make(map[string]BarkConfig)},
+		Campaign:    &CampaignConfig{},
+		Recruitment: &RecruitmentConfig{},
+		Challenges:  &ChallengesConfig{Challenges: make(map[string]ChallengeConfig)},
+		I18n:        &I18nConfig{Languages: make(map[string]LanguageConfig)},
+		Themes:      &ThemesConfig{Themes: make(map[string]ThemeConfig)},
+		Presets:     &PresetsConfig{Presets: make(map[string]PresetConfig)},
 	}
 }
 
-// LoadAll loads all data files
+// dataFiles lists the data files LoadAll reads, in order
+var dataFiles = []struct {
+	name string
+	path string
+	load func(dm *DataManager, path string) error
+}{
+	{"units", "assets/data/units.toml", (*DataManager).LoadUnits},
+	{"abilities", "assets/data/abilities.toml", (*DataManager).LoadAbilities},
+	{"terrains", "assets/data/terrain.toml", (*DataManager).LoadTerrains},
+	{"stages", "assets/data/stages.toml", (*DataManager).LoadStages},
+	{"doctrines", "assets/data/doctrines.toml", (*DataManager).LoadDoctrines},
+	{"items", "assets/data/items.toml", (*DataManager).LoadItems},
+	{"barks", "assets/data/barks.toml", (*DataManager).LoadBarks},
+	{"campaign", "assets/data/campaign.toml", (*DataManager).LoadCampaign},
+	{"recruitment", "assets/data/recruitment.toml", (*DataManager).LoadRecruitment},
+	{"challenges", "assets/data/challenges.toml", (*DataManager).LoadChallenges},
+	{"i18n", "assets/data/i18n.toml", (*DataManager).LoadI18n},
+	{"themes", "assets/data/themes.toml", (*DataManager).LoadThemes},
+}
+
+// LoadAll loads all data files, then the mods in dm.Mods over them
 func (dm *DataManager) LoadAll() error {
-	if err := dm.LoadUnits("assets/data/units.toml"); err != nil {
-		return fmt.Errorf("failed to load units: %w", err)
+	return dm.LoadAllWithProgress(nil)
+}
+
+// LoadAllWithProgress loads all data like LoadAll, telling progress (when not nil) how far it has
+// got (0-1) and what it loads next
+func (dm *DataManager) LoadAllWithProgress(progress func(done float64, step string)) error {
+	steps := float64(len(dataFiles) + 3)
+	report := func(step int, name string) {
+		if progress != nil {
+			progress(float64(step)/steps, name)
+		}
 	}
 	
-	if err := dm.LoadTerrains("assets/data/terrain.toml"); err != nil {
-		return fmt.Errorf("failed to load terrains: %w", err)
+	for i, file := range dataFiles {
+		report(i, filepath.Base(file.path))
+		if err := file.load(dm, file.path); err != nil {
+			return fmt.Errorf("failed to load %s: %w", file.name, err)
+		}
 	}
 	
-	if err := dm.LoadStages("assets/data/stages.toml"); err != nil {
-		return fmt.Errorf("failed to load stages: %w", err)
+	// Mods add to or override the units, abilities, terrains, stages and presets
+	report(len(dataFiles), "MOD")
+	dm.loadMods(DefaultModDir)
+	
+	// Stages made in Tiled take their layout from the map, after mods may have changed which map
+	report(len(dataFiles)+1, "Tiledマップ")
+	dm.importMaps()
+	
+	// Bad values are replaced by their defaults rather than left to produce broken units
+	report(len(dataFiles)+2, "データの検証")
+	for _, issue := range dm.Validate() {
+		fmt.Printf("Warning: %s\n", issue)
 	}
 	
 	return nil
@@ -56,6 +123,22 @@ func (dm *DataManager) LoadUnits(filename string) error {
 	return nil
 }
 
+// LoadAbilities loads the spells cast by unit types from TOML file
+func (dm *DataManager) LoadAbilities(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config AbilitiesConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Abilities = &config
+	return nil
+}
+
 // LoadTerrains loads terrain configurations from TOML file
 func (dm *DataManager) LoadTerrains(filename string) error {
 	data, err := os.ReadFile(filename)
@@ -88,6 +171,134 @@ func (dm *DataManager) LoadStages(filename string) error {
 	return nil
 }
 
+// LoadDoctrines loads doctrine configurations from TOML file
+func (dm *DataManager) LoadDoctrines(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config DoctrinesConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Doctrines = &config
+	return nil
+}
+
+// LoadItems loads the leaders' equipment from TOML file
+func (dm *DataManager) LoadItems(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config ItemsConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Items = &config
+	return nil
+}
+
+// LoadBarks loads the units' bark tables from TOML file
+func (dm *DataManager) LoadBarks(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config BarksConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Barks = &config
+	return nil
+}
+
+// LoadRecruitment loads the gold rewards and the hireable groups of the custom army from TOML file
+func (dm *DataManager) LoadRecruitment(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config RecruitmentConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Recruitment = &config
+	return nil
+}
+
+// LoadChallenges loads the scenario challenges from TOML file
+func (dm *DataManager) LoadChallenges(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config ChallengesConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Challenges = &config
+	return nil
+}
+
+// LoadCampaign loads the campaign overworld map from TOML file
+func (dm *DataManager) LoadCampaign(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config CampaignConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Campaign = &config
+	return nil
+}
+
+// LoadI18n loads the per-language tables from TOML file
+func (dm *DataManager) LoadI18n(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config I18nConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.I18n = &config
+	return nil
+}
+
+// LoadThemes loads the UI color themes from TOML file
+func (dm *DataManager) LoadThemes(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var config ThemesConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	
+	dm.Themes = &config
+	return nil
+}
+
 // GetUnitConfig returns unit configuration by type
 func (dm *DataManager) GetUnitConfig(unitType string) (UnitTypeConfig, error) {
 	config, exists := dm.Units.GetUnitConfig(unitType)
@@ -97,6 +308,15 @@ func (dm *DataManager) GetUnitConfig(unitType string) (UnitTypeConfig, error) {
 	return config, nil
 }
 
+// GetAbilityConfig returns ability configuration by ID
+func (dm *DataManager) GetAbilityConfig(abilityID string) (AbilityConfig, error) {
+	config, exists := dm.Abilities.GetAbilityConfig(abilityID)
+	if !exists {
+		return AbilityConfig{}, fmt.Errorf("ability %s not found", abilityID)
+	}
+	return config, nil
+}
+
 // GetTerrainConfig returns terrain configuration by type
 func (dm *DataManager) GetTerrainConfig(terrainType string) (TerrainConfig, error) {
 	config, exists := dm.Terrains.GetTerrainConfig(terrainType)
@@ -114,3 +334,127 @@ func (dm *DataManager) GetStageConfig(stageName string) (StageConfig, error) {
 	}
 	return config, nil
 }
+
+// GetChallengeConfig returns challenge configuration by ID
+func (dm *DataManager) GetChallengeConfig(challengeID string) (ChallengeConfig, error) {
+	config, exists := dm.Challenges.GetChallengeConfig(challengeID)
+	if !exists {
+		return ChallengeConfig{}, fmt.Errorf("challenge %s not found", challengeID)
+	}
+	return config, nil
+}
+
+// GetDoctrineConfig returns doctrine configuration by ID
+func (dm *DataManager) GetDoctrineConfig(doctrineID string) (DoctrineConfig, error) {
+	config, exists := dm.Doctrines.GetDoctrineConfig(doctrineID)
+	if !exists {
+		return DoctrineConfig{}, fmt.Errorf("doctrine %s not found", doctrineID)
+	}
+	return config, nil
+}
+
+// GetItemConfig returns item configuration by ID
+func (dm *DataManager) GetItemConfig(itemID string) (ItemConfig, error) {
+	config, exists := dm.Items.GetItemConfig(itemID)
+	if !exists {
+		return ItemConfig{}, fmt.Errorf("item %s not found", itemID)
+	}
+	return config, nil
+}
+
+// GetLanguageConfig returns the text metrics of a language
+func (dm *DataManager) GetLanguageConfig(language string) (LanguageConfig, error) {
+	config, exists := dm.I18n.GetLanguageConfig(language)
+	if !exists {
+		return LanguageConfig{}, fmt.Errorf("language %s not found", language)
+	}
+	return config, nil
+}
+
+// GetThemeConfig returns the colors of a UI theme
+func (dm *DataManager) GetThemeConfig(themeID string) (ThemeConfig, error) {
+	config, exists := dm.Themes.GetThemeConfig(themeID)
+	if !exists {
+		return ThemeConfig{}, fmt.Errorf("theme %s not found", themeID)
+	}
+	return config, nil
+}
+
+// GetDoctrineIDs returns the IDs of all doctrines in sorted order
+func (dm *DataManager) GetDoctrineIDs() []string {
+	ids := make([]string, 0, len(dm.Doctrines.Doctrines))
+	for id := range dm.Doctrines.Doctrines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetStageIDs returns the IDs of all stages in sorted order
+func (dm *DataManager) GetStageIDs() []string {
+	ids := make([]string, 0, len(dm.Stages.Stages))
+	for id := range dm.Stages.Stages {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ListStages returns the stages offered in the stage selector, in their order
+// Stages without an order are only fought from the campaign or the simulator
+func (dm *DataManager) ListStages() []StageListing {
+	var stages []StageListing
+	for _, id := range dm.GetStageIDs() {
+		if stage := dm.Stages.Stages[id]; stage.Order > 0 {
+			stages = append(stages, StageListing{ID: id, Name: stage.Name, Difficulty: stage.Difficulty})
+		}
+	}
+	sort.SliceStable(stages, func(i, j int) bool {
+		return dm.Stages.Stages[stages[i].ID].Order < dm.Stages.Stages[stages[j].ID].Order
+	})
+	return stages
+}
+
+// ListThemes returns the UI themes in their order
+func (dm *DataManager) ListThemes() []ThemeListing {
+	themes := make([]ThemeListing, 0, len(dm.Themes.Themes))
+	for id, theme := range dm.Themes.Themes {
+		themes = append(themes, ThemeListing{ID: id, Name: theme.Name})
+	}
+	sort.Slice(themes, func(i, j int) bool {
+		a, b := dm.Themes.Themes[themes[i].ID], dm.Themes.Themes[themes[j].ID]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return themes[i].ID < themes[j].ID
+	})
+	return themes
+}
+
+// ListChallenges returns the challenges in their order
+func (dm *DataManager) ListChallenges() []ChallengeListing {
+	challenges := make([]ChallengeListing, 0, len(dm.Challenges.Challenges))
+	for id, challenge := range dm.Challenges.Challenges {
+		challenges = append(challenges, ChallengeListing{ID: id, Name: challenge.Name})
+	}
+	sort.Slice(challenges, func(i, j int) bool {
+		a, b := dm.Challenges.Challenges[challenges[i].ID], dm.Challenges.Challenges[challenges[j].ID]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return challenges[i].ID < challenges[j].ID
+	})
+	return challenges
+}
+
+// GetSlotItemIDs returns the IDs of the items that fit the equipment slot in sorted order
+func (dm *DataManager) GetSlotItemIDs(slot string) []string {
+	var ids []string
+	for id, item := range dm.Items.Items {
+		if item.Slot == slot {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
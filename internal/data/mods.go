@@ -0,0 +1,122 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// DefaultModDir is the folder holding one folder per mod
+const DefaultModDir = "mods"
+
+// modTable is a data file a mod may ship and the table its entries are merged into
+type modTable struct {
+	file  string // MODフォルダ内のファイル名
+	table string // エントリを並べるテーブル名
+	merge func(entries map[string]any) error
+}
+
+// modTables lists the data files a mod may add to or override
+func (dm *DataManager) modTables() []modTable {
+	return []modTable{
+		{"units.toml", "unit_types", func(entries map[string]any) error { return mergeEntries(dm.Units.UnitTypes, entries) }},
+		{"abilities.toml", "abilities", func(entries map[string]any) error { return mergeEntries(dm.Abilities.Abilities, entries) }},
+		{"terrain.toml", "terrain_types", func(entries map[string]any) error { return mergeEntries(dm.Terrains.TerrainTypes, entries) }},
+		{"stages.toml", "stages", func(entries map[string]any) error { return mergeEntries(dm.Stages.Stages, entries) }},
+		{"presets.toml", "presets", func(entries map[string]any) error { return mergeEntries(dm.Presets.Presets, entries) }},
+	}
+}
+
+// FindMods returns the mod folders under dir to load, lowest priority first: the enabled ones in the
+// order given, or every folder by name when none are listed, leaving out the disabled ones
+func FindMods(dir string, enabled, disabled []string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("Warning: Failed to read mod folder %s: %v\n", dir, err)
+		}
+		return nil
+	}
+	var found []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			found = append(found, entry.Name())
+		}
+	}
+	
+	mods := found
+	if len(enabled) > 0 {
+		mods = nil
+		for _, name := range enabled {
+			if !slices.Contains(found, name) {
+				fmt.Printf("Warning: enabled mod '%s' not found in %s\n", name, dir)
+				continue
+			}
+			mods = append(mods, name)
+		}
+	}
+	return slices.DeleteFunc(mods, func(name string) bool { return slices.Contains(disabled, name) })
+}
+
+// loadMods merges the data files of each mod in dm.Mods over the loaded data, so later mods override earlier ones
+// A broken file is reported and skipped without stopping the other files or mods
+func (dm *DataManager) loadMods(dir string) {
+	for _, mod := range dm.Mods {
+		if _, err := os.Stat(filepath.Join(dir, mod)); err != nil {
+			fmt.Printf("Warning: mod %s: %v\n", mod, err)
+			continue
+		}
+		for _, table := range dm.modTables() {
+			filename := filepath.Join(dir, mod, table.file)
+			if err := loadModTable(filename, table); err != nil {
+				fmt.Printf("Warning: mod %s: %v\n", mod, err)
+			}
+		}
+		fmt.Printf("Loaded mod %s\n", mod)
+	}
+}
+
+// loadModTable merges the entries of one mod file; a mod without the file leaves the data as it is
+func loadModTable(filename string, table modTable) error {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	
+	var document map[string]any
+	if err := toml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to parse TOML in %s: %w", filename, err)
+	}
+	entries, ok := document[table.table].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s has no [%s] table", filename, table.table)
+	}
+	if err := table.merge(entries); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// mergeEntries decodes the mod's entries onto the table: a new ID adds an entry, and a known one
+// takes only the keys the mod sets (lists are replaced, tables merged) and keeps the rest
+func mergeEntries[T any](table map[string]T, entries map[string]any) error {
+	for _, id := range sortedKeys(entries) {
+		raw, err := toml.Marshal(entries[id])
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		entry := table[id]
+		if err := toml.Unmarshal(raw, &entry); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+		table[id] = entry
+	}
+	return nil
+}
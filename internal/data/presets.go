@@ -0,0 +1,32 @@
+package data
+
+import "sort"
+
+// PresetConfig represents a preset army from a mod's presets.toml, fielding one group per deployment point
+// It replaces the built-in preset of the same name, traps included
+type PresetConfig struct {
+	Order     int                        `toml:"order"`      // 組み込みのプリセットの後ろでの並び順
+	Groups    []ReinforcementGroupConfig `toml:"groups"`     // 部隊（leader・member・count。装備は付かない）
+	SpikePits int                        `toml:"spike_pits"` // 設置できる落とし穴の数
+	Caltrops  int                        `toml:"caltrops"`   // 設置できるまきびしの数
+}
+
+// PresetsConfig represents the preset armies added or overridden by mods
+type PresetsConfig struct {
+	Presets map[string]PresetConfig `toml:"presets"`
+}
+
+// GetPresetConfig returns the configuration for a specific preset
+func (pc *PresetsConfig) GetPresetConfig(name string) (PresetConfig, bool) {
+	config, exists := pc.Presets[name]
+	return config, exists
+}
+
+// ListPresets returns the names of the mods' presets in their order
+func (dm *DataManager) ListPresets() []string {
+	names := sortedKeys(dm.Presets.Presets)
+	sort.SliceStable(names, func(i, j int) bool {
+		return dm.Presets.Presets[names[i]].Order < dm.Presets.Presets[names[j]].Order
+	})
+	return names
+}
@@ -0,0 +1,50 @@
+package data
+
+// RecruitmentConfig represents the gold rewards and the groups the player can hire for the custom army, from TOML
+type RecruitmentConfig struct {
+	PointBudget int               `toml:"point_budget"` // 出陣する部隊の戦力点の上限
+	MaxGroups   int               `toml:"max_groups"`   // 出陣できる部隊の数
+	RefundRate  float64           `toml:"refund_rate"`  // 解散した部隊に使った金貨のうち戻る割合
+	Rewards     map[string]int    `toml:"rewards"`      // 戦闘の結果（"win"/"draw"/"loss"）ごとの金貨
+	ItemPrices  map[string]int    `toml:"item_prices"`  // 装備の値段（載っていない装備は買えない）
+	Recruits    []MercenaryConfig `toml:"recruits"`
+}
+
+// MercenaryConfig represents a group the player can hire between battles
+type MercenaryConfig struct {
+	Name         string `toml:"name"`
+	Leader       string `toml:"leader"`
+	Member       string `toml:"member"`
+	Count        int    `toml:"count"`         // 雇ったときの兵数（リーダーを除く）
+	MaxCount     int    `toml:"max_count"`     // 増員できる兵数の上限
+	Cost         int    `toml:"cost"`          // 雇うときに払う金貨
+	Points       int    `toml:"points"`        // 出陣したときの戦力点
+	MemberCost   int    `toml:"member_cost"`   // 1人増員する金貨
+	MemberPoints int    `toml:"member_points"` // 増員した1人ごとに加わる戦力点
+}
+
+// GetReward returns the gold earned for a battle with the result
+func (rc *RecruitmentConfig) GetReward(result string) int {
+	return rc.Rewards[result]
+}
+
+// GetRecruit returns the hireable group with the name
+func (rc *RecruitmentConfig) GetRecruit(name string) (MercenaryConfig, bool) {
+	for _, recruit := range rc.Recruits {
+		if recruit.Name == name {
+			return recruit, true
+		}
+	}
+	return MercenaryConfig{}, false
+}
+
+// GetItemPrice returns the price of an item; items without one cannot be bought
+func (rc *RecruitmentConfig) GetItemPrice(itemID string) (int, bool) {
+	price, exists := rc.ItemPrices[itemID]
+	return price, exists
+}
+
+// GroupPoints returns the points a group hired from the recruit takes up at the given strength
+func (mc MercenaryConfig) GroupPoints(count int) int {
+	return mc.Points + max(count-mc.Count, 0)*mc.MemberPoints
+}
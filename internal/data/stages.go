@@ -1,6 +1,13 @@
 package data
 
 import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
 	gamemath "github.com/shirou/tinygocha/internal/math"
 )
 
@@ -15,15 +22,315 @@ func (dp DeploymentPoint) ToVector2D() gamemath.Vector2D {
 	return gamemath.Vector2D{X: dp.X, Y: dp.Y}
 }
 
+// DirectionConfig represents a direction on the stage; the zero value leaves it unset
+type DirectionConfig struct {
+	X float64 `toml:"x"`
+	Y float64 `toml:"y"`
+}
+
+// IsSet reports whether the stage gives the direction
+func (dc DirectionConfig) IsSet() bool {
+	return dc.X != 0 || dc.Y != 0
+}
+
+// ToVector2D converts the direction to a unit Vector2D
+func (dc DirectionConfig) ToVector2D() gamemath.Vector2D {
+	return gamemath.Vector2D{X: dc.X, Y: dc.Y}.Normalize()
+}
+
+// ArmyIndex converts an army label ("a", "b", "c", ...) to its army index
+// Returns -1 for an empty or invalid label
+func ArmyIndex(label string) int {
+	if len(label) != 1 || label[0] < 'a' || label[0] > 'z' {
+		return -1
+	}
+	return int(label[0] - 'a')
+}
+
+// ArmyLabel returns the display label ("A", "B", "C", ...) of an army index
+func ArmyLabel(index int) string {
+	if index < 0 || index >= 26 {
+		return "?"
+	}
+	return strings.ToUpper(string(rune('a' + index)))
+}
+
+// StageArmyConfig represents an army taking part in a stage
+type StageArmyConfig struct {
+	Name             string            `toml:"name"`
+	Preset           string            `toml:"preset"` // Empty: the preset chosen on the setup screen
+	Allies           []string          `toml:"allies"` // Labels of allied armies
+	DeploymentPoints []DeploymentPoint `toml:"deployment_points"`
+	Facing           DirectionConfig   `toml:"facing"`   // Front the army deploys facing (unset: its nearest hostile army)
+	Approach         DirectionConfig   `toml:"approach"` // Direction the army advances in at the start (unset: straight at the enemy)
+}
+
+// GetDeploymentPoints returns deployment points as Vector2D slice
+func (ac StageArmyConfig) GetDeploymentPoints() []gamemath.Vector2D {
+	points := make([]gamemath.Vector2D, len(ac.DeploymentPoints))
+	for i, dp := range ac.DeploymentPoints {
+		points[i] = dp.ToVector2D()
+	}
+	return points
+}
+
+// Victory condition types
+const (
+	VictoryCommander   = "commander"    // 敵将撃破
+	VictoryCaptureZone = "capture_zone" // 拠点確保
+	VictorySurvive     = "survive"      // 耐久
+	VictoryEscort      = "escort"       // 護衛
+	VictoryConvoy      = "convoy"       // 輸送隊の護送
+)
+
+// DefaultConvoyUnit is the unit type of a convoy whose victory condition names none
+const DefaultConvoyUnit = "convoy"
+
+// VictoryConditionConfig represents an additional win condition of a stage
+type VictoryConditionConfig struct {
+	Type     string  `toml:"type"`
+	Army     string  `toml:"army"`     // "a", "b", ... or empty for all armies
+	X        float64 `toml:"x"`        // Zone center (capture_zone, escort)
+	Y        float64 `toml:"y"`
+	Radius   float64 `toml:"radius"`   // Zone radius (capture_zone, escort) or arrival distance (convoy)
+	Duration float64 `toml:"duration"` // Seconds to hold (capture_zone) or survive (survive)
+	
+	// Convoy route from its starting point to the destination, and the unit type driven along it
+	Path []DeploymentPoint `toml:"path"`
+	Unit string            `toml:"unit"` // Empty: DefaultConvoyUnit
+}
+
+// GetPath returns the convoy route as Vector2D slice
+func (vc VictoryConditionConfig) GetPath() []gamemath.Vector2D {
+	points := make([]gamemath.Vector2D, len(vc.Path))
+	for i, point := range vc.Path {
+		points[i] = point.ToVector2D()
+	}
+	return points
+}
+
+// ConvoyUnit returns the unit type of the convoy
+func (vc VictoryConditionConfig) ConvoyUnit() string {
+	if vc.Unit == "" {
+		return DefaultConvoyUnit
+	}
+	return vc.Unit
+}
+
+// AppliesTo reports whether the condition can be achieved by the given army
+func (vc VictoryConditionConfig) AppliesTo(armyID int) bool {
+	if vc.Army == "" {
+		return true
+	}
+	return ArmyIndex(vc.Army) == armyID
+}
+
+// CapturePointConfig represents a capture point placed on the stage
+type CapturePointConfig struct {
+	Name        string  `toml:"name"`
+	X           float64 `toml:"x"`
+	Y           float64 `toml:"y"`
+	Radius      float64 `toml:"radius"`
+	CaptureTime float64 `toml:"capture_time"` // Seconds for a single army to take the point
+	ScoreRate   float64 `toml:"score_rate"`   // Victory score per second for the owner
+}
+
+// Reinforcement trigger types
+const (
+	TriggerTime          = "time"           // trigger_time 経過で到着
+	TriggerHealthBelow   = "health_below"   // 軍勢の残存体力が threshold 未満
+	TriggerCommanderLost = "commander_lost" // 総大将の戦死
+)
+
+// ReinforcementGroupConfig represents a group arriving with a reinforcement wave
+type ReinforcementGroupConfig struct {
+	Leader    string    `toml:"leader"`
+	Member    string    `toml:"member"`
+	Count     int       `toml:"count"`
+	Equipment Equipment `toml:"equipment"` // リーダーの装備
+}
+
+// ReinforcementConfig represents a scripted reinforcement wave
+type ReinforcementConfig struct {
+	Name        string                     `toml:"name"`
+	Army        string                     `toml:"army"`         // "a", "b", ...
+	Trigger     string                     `toml:"trigger"`      // Defaults to "time"
+	TriggerTime float64                    `toml:"trigger_time"` // Earliest arrival time in seconds
+	Threshold   float64                    `toml:"threshold"`    // health_below: army health ratio
+	X           float64                    `toml:"x"`            // Spawn point
+	Y           float64                    `toml:"y"`
+	Groups      []ReinforcementGroupConfig `toml:"groups"`
+}
+
+// ArmyID returns the army index the wave belongs to
+func (rc ReinforcementConfig) ArmyID() int {
+	if index := ArmyIndex(rc.Army); index >= 0 {
+		return index
+	}
+	return 0
+}
+
+// NeutralCampConfig represents monsters or bandits guarding a spot on the stage
+type NeutralCampConfig struct {
+	Name        string                     `toml:"name"`
+	X           float64                    `toml:"x"`            // Guarded spot
+	Y           float64                    `toml:"y"`
+	GuardRadius float64                    `toml:"guard_radius"` // Intruders within this radius are attacked
+	Groups      []ReinforcementGroupConfig `toml:"groups"`
+}
+
+// TerrainAreaConfig represents a rectangle of the stage with its own movement cost
+type TerrainAreaConfig struct {
+	Name             string  `toml:"name"`
+	X1               float64 `toml:"x1"` // Top-left corner
+	Y1               float64 `toml:"y1"`
+	X2               float64 `toml:"x2"` // Bottom-right corner
+	Y2               float64 `toml:"y2"`
+	MovementModifier float64 `toml:"movement_modifier"` // Speed multiplier (0: impassable)
+	Cover            bool    `toml:"cover"`             // Forest or thicket where stealthed units are harder to spot
+}
+
+// Contains reports whether the point lies inside the area
+func (ta TerrainAreaConfig) Contains(x, y float64) bool {
+	return x >= ta.X1 && x < ta.X2 && y >= ta.Y1 && y < ta.Y2
+}
+
+// DefaultSupplyRadius is the radius of supply points that do not set one (15m)
+const DefaultSupplyRadius = 150.0
+
+// SupplyPointConfig represents a spot where an army's ranged units restock their ammunition
+type SupplyPointConfig struct {
+	Name   string  `toml:"name"`
+	Army   string  `toml:"army"` // "a", "b", ...
+	X      float64 `toml:"x"`
+	Y      float64 `toml:"y"`
+	Radius float64 `toml:"radius"` // Units inside restock (default 150)
+}
+
+// ArmyID returns the army index the supply point belongs to
+func (sc SupplyPointConfig) ArmyID() int {
+	if index := ArmyIndex(sc.Army); index >= 0 {
+		return index
+	}
+	return 0
+}
+
+// Center returns the position of the supply point
+func (sc SupplyPointConfig) Center() gamemath.Vector2D {
+	return gamemath.Vector2D{X: sc.X, Y: sc.Y}
+}
+
+// GetRadius returns the radius in which units restock
+func (sc SupplyPointConfig) GetRadius() float64 {
+	if sc.Radius > 0 {
+		return sc.Radius
+	}
+	return DefaultSupplyRadius
+}
+
+// Obstacle kinds
+const (
+	ObstacleTree = "tree" // 木
+	ObstacleRock = "rock" // 岩
+)
+
+// ObstacleConfig represents a tree or boulder that units walk around and that stops missiles
+type ObstacleConfig struct {
+	Kind   string  `toml:"kind"` // "tree" or "rock"
+	X      float64 `toml:"x"`
+	Y      float64 `toml:"y"`
+	Radius float64 `toml:"radius"` // Collision radius
+}
+
+// Structure kinds
+const (
+	StructureGate  = "gate"  // 門（所有する陣営は通れる）
+	StructureWall  = "wall"  // 城壁
+	StructureTower = "tower" // 櫓（近づいた敵を矢で射る、味方の歩兵・弓兵が入れる）
+)
+
+// StructureConfig represents a gate, wall or tower that blocks movement until destroyed
+type StructureConfig struct {
+	Kind     string  `toml:"kind"` // "gate", "wall" or "tower"
+	Name     string  `toml:"name"`
+	Army     string  `toml:"army"` // Owner; its alliance passes through gates (unset: nobody's)
+	X1       float64 `toml:"x1"`
+	Y1       float64 `toml:"y1"`
+	X2       float64 `toml:"x2"`
+	Y2       float64 `toml:"y2"`
+	HP       int     `toml:"hp"`       // Durability (0: kind default)
+	Defense  int     `toml:"defense"`  // Subtracted from each hit (0: kind default)
+	Range    float64 `toml:"range"`    // Towers: arrow range (0: default)
+	Attack   int     `toml:"attack"`   // Towers: arrow damage (0: default)
+	Capacity int     `toml:"capacity"` // Towers: units that fit inside (0: default, -1: none)
+}
+
+// ArmyID returns the army index owning the structure, or -1 when nobody owns it
+func (sc StructureConfig) ArmyID() int {
+	return ArmyIndex(sc.Army)
+}
+
+// MaxStageDifficulty is the highest difficulty rating of a stage
+const MaxStageDifficulty = 5
+
+// DefaultStageSize is the width and height of a stage that leaves them out (500m)
+const DefaultStageSize = 5000
+
 // StageConfig represents stage configuration from TOML
 type StageConfig struct {
-	Name              string            `toml:"name"`
-	Terrain           string            `toml:"terrain"`
-	DeploymentPointsA []DeploymentPoint `toml:"deployment_points_a"`
-	DeploymentPointsB []DeploymentPoint `toml:"deployment_points_b"`
-	TimeLimit         float64           `toml:"time_limit"`
-	Width             int               `toml:"width"`
-	Height            int               `toml:"height"`
+	Name              string                   `toml:"name"`
+	Terrain           string                   `toml:"terrain"`
+	Order             int                      `toml:"order"` // Position in the stage selector (0: not offered)
+	Description       string                   `toml:"description"`
+	Difficulty        int                      `toml:"difficulty"`         // 1-MaxStageDifficulty (0: not rated)
+	RecommendedGroups int                      `toml:"recommended_groups"` // Groups the stage is meant for (0: any)
+	Preview           string                   `toml:"preview"`            // Optional preview image path
+	Map               string                   `toml:"map"`                // Optional Tiled map (.tmx, .tmj) the layout is imported from
+	DeploymentPointsA []DeploymentPoint        `toml:"deployment_points_a"`
+	DeploymentPointsB []DeploymentPoint        `toml:"deployment_points_b"`
+	Armies            []StageArmyConfig        `toml:"armies"` // Overrides deployment_points_a/b for 3+ armies
+	TimeLimit         float64                  `toml:"time_limit"`
+	Width             int                      `toml:"width"`
+	Height            int                      `toml:"height"`
+	VictoryConditions []VictoryConditionConfig `toml:"victory_conditions"`
+	CapturePoints     []CapturePointConfig     `toml:"capture_points"`
+	ScoreLimit        float64                  `toml:"score_limit"` // Victory score needed to win (0: disabled)
+	Reinforcements    []ReinforcementConfig    `toml:"reinforcements"`
+	NeutralCamps      []NeutralCampConfig      `toml:"neutral_camps"`
+	Camera            StageCameraConfig        `toml:"camera"`
+	TerrainAreas      []TerrainAreaConfig      `toml:"terrain_areas"`
+	Obstacles         []ObstacleConfig         `toml:"obstacles"`
+	SupplyPoints      []SupplyPointConfig      `toml:"supply_points"`
+	Structures        []StructureConfig        `toml:"structures"`
+	DayNight          *DayNightConfig          `toml:"day_night"` // Time of day (unset: always daylight)
+	Triggers          []TriggerConfig          `toml:"triggers"`  // Scripted events
+	Script            string                   `toml:"script"`    // Optional Lua script with on_start/on_tick hooks
+}
+
+// DayNightConfig sets the time of day at the start of the battle and how fast it passes
+type DayNightConfig struct {
+	StartHour      float64 `toml:"start_hour"`       // Hour when the battle starts (0-24)
+	HoursPerMinute float64 `toml:"hours_per_minute"` // In-game hours per minute of battle (0: the time stands still)
+}
+
+// StageCameraConfig sets where the battle camera starts and how far it can scroll
+type StageCameraConfig struct {
+	StartX       float64 `toml:"start_x"` // Initial view center (0, 0: the player's deployment zone)
+	StartY       float64 `toml:"start_y"`
+	BoundsLeft   float64 `toml:"bounds_left"` // Scroll area in world coordinates (unset: the whole world)
+	BoundsTop    float64 `toml:"bounds_top"`
+	BoundsRight  float64 `toml:"bounds_right"`
+	BoundsBottom float64 `toml:"bounds_bottom"`
+}
+
+// HasStart reports whether the stage sets the initial camera position
+func (cc StageCameraConfig) HasStart() bool {
+	return cc.StartX != 0 || cc.StartY != 0
+}
+
+// HasBounds reports whether the stage limits the camera to an area
+func (cc StageCameraConfig) HasBounds() bool {
+	return cc.BoundsRight > cc.BoundsLeft && cc.BoundsBottom > cc.BoundsTop
 }
 
 // StagesConfig represents the entire stages configuration
@@ -31,6 +338,13 @@ type StagesConfig struct {
 	Stages map[string]StageConfig `toml:"stages"`
 }
 
+// StageListing names a stage offered in the stage selector
+type StageListing struct {
+	ID         string // ステージ設定ID
+	Name       string // 表示名
+	Difficulty int    // 難易度（0: 未設定）
+}
+
 // GetStageConfig returns the configuration for a specific stage
 func (sc *StagesConfig) GetStageConfig(stageName string) (StageConfig, bool) {
 	config, exists := sc.Stages[stageName]
@@ -54,3 +368,61 @@ func (sc StageConfig) GetDeploymentPointsB() []gamemath.Vector2D {
 	}
 	return points
 }
+
+// WorldSize returns the stage's width and height in pixels, DefaultStageSize for a side left out
+func (sc StageConfig) WorldSize() (float64, float64) {
+	width, height := float64(sc.Width), float64(sc.Height)
+	if width <= 0 {
+		width = DefaultStageSize
+	}
+	if height <= 0 {
+		height = DefaultStageSize
+	}
+	return width, height
+}
+
+// Fingerprint returns a short hash of the stage's data and its preview image file,
+// which changes whenever either of them does
+func (sc StageConfig) Fingerprint() string {
+	hash := fnv.New64a()
+	if raw, err := toml.Marshal(sc); err == nil {
+		hash.Write(raw)
+	}
+	if info, err := os.Stat(sc.Preview); sc.Preview != "" && err == nil {
+		fmt.Fprintf(hash, "%d %d", info.Size(), info.ModTime().UnixNano())
+	}
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// GetCameraStart returns the initial camera focus: the configured start,
+// otherwise the center of the army's deployment zone, otherwise the stage center
+func (sc StageConfig) GetCameraStart(armyID int) gamemath.Vector2D {
+	if sc.Camera.HasStart() {
+		return gamemath.Vector2D{X: sc.Camera.StartX, Y: sc.Camera.StartY}
+	}
+	
+	armies := sc.GetArmyConfigs()
+	if armyID >= 0 && armyID < len(armies) {
+		if points := armies[armyID].GetDeploymentPoints(); len(points) > 0 {
+			var center gamemath.Vector2D
+			for _, point := range points {
+				center = center.Add(point)
+			}
+			return center.Mul(1.0 / float64(len(points)))
+		}
+	}
+	width, height := sc.WorldSize()
+	return gamemath.Vector2D{X: width / 2, Y: height / 2}
+}
+
+// GetArmyConfigs returns the armies of the stage
+// Stages without an armies table get the classic two armies from deployment_points_a/b
+func (sc StageConfig) GetArmyConfigs() []StageArmyConfig {
+	if len(sc.Armies) > 0 {
+		return sc.Armies
+	}
+	return []StageArmyConfig{
+		{Name: "軍勢A", DeploymentPoints: sc.DeploymentPointsA},
+		{Name: "軍勢B", DeploymentPoints: sc.DeploymentPointsB},
+	}
+}
@@ -2,12 +2,22 @@ package data
 
 // TerrainConfig represents terrain configuration from TOML
 type TerrainConfig struct {
-	Name             string  `toml:"name"`
-	MovementModifier float64 `toml:"movement_modifier"`
-	DefenseModifier  float64 `toml:"defense_modifier"`
-	ArcherBonus      float64 `toml:"archer_bonus"`
-	MageBonus        float64 `toml:"mage_bonus"`
-	InfantryBonus    float64 `toml:"infantry_bonus"`
+	Name               string             `toml:"name"`
+	MovementModifier   float64            `toml:"movement_modifier"`
+	DefenseModifier    float64            `toml:"defense_modifier"`
+	AttackBonuses      map[string]float64 `toml:"attack_bonuses"`       // ユニット種別ごとの攻撃力・魔力の倍率
+	DefaultAttackBonus float64            `toml:"default_attack_bonus"` // 載っていない種別の倍率（0: 100%）
+}
+
+// GetAttackBonus returns the attack multiplier the terrain gives a unit type
+func (tc TerrainConfig) GetAttackBonus(unitType string) float64 {
+	if bonus, ok := tc.AttackBonuses[unitType]; ok {
+		return bonus
+	}
+	if tc.DefaultAttackBonus > 0 {
+		return tc.DefaultAttackBonus
+	}
+	return 1.0
 }
 
 // TerrainsConfig represents the entire terrain configuration
@@ -0,0 +1,25 @@
+package data
+
+// ThemeConfig represents a UI color theme from TOML
+type ThemeConfig struct {
+	Name   string            `toml:"name"`
+	Order  int               `toml:"order"`  // テーマを切り替える順番
+	Colors map[string]string `toml:"colors"` // 役割ごとの色 "#RRGGBB" / "#RRGGBBAA"（省略した役割はダークテーマの色）
+}
+
+// ThemesConfig represents the entire themes configuration
+type ThemesConfig struct {
+	Themes map[string]ThemeConfig `toml:"themes"`
+}
+
+// ThemeListing names a theme in the order themes are switched through
+type ThemeListing struct {
+	ID   string // テーマID
+	Name string // 表示名
+}
+
+// GetThemeConfig returns the configuration for a specific theme
+func (tc *ThemesConfig) GetThemeConfig(themeID string) (ThemeConfig, bool) {
+	config, exists := tc.Themes[themeID]
+	return config, exists
+}
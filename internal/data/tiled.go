@@ -0,0 +1,739 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Tiled object classes a stage imports (obstacles use ObstacleTree and ObstacleRock)
+const (
+	TiledDeployment    = "deployment"    // 配置ポイント・配置ゾーン（army プロパティの軍勢）
+	TiledCapturePoint  = "capture_point" // 拠点
+	TiledReinforcement = "reinforcement" // 名前の同じ増援の出現地点
+)
+
+// tiledFlipFlags are the top bits of a GID that flip or rotate the tile
+const tiledFlipFlags = 0xF0000000
+
+// tiledMap is a map made in the Tiled editor, read from TMX or JSON and reduced to what a stage imports
+type tiledMap struct {
+	Width      int            `json:"width"`
+	Height     int            `json:"height"`
+	TileWidth  int            `json:"tilewidth"`
+	TileHeight int            `json:"tileheight"`
+	Infinite   bool           `json:"infinite"`
+	Layers     []tiledLayer   `json:"layers"`
+	Tilesets   []tiledTileset `json:"tilesets"`
+}
+
+// tiledLayer is a tile layer, an object layer or a group of layers
+type tiledLayer struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"` // "tilelayer", "objectgroup" or "group"
+	Visible     bool            `json:"visible"`
+	OffsetX     float64         `json:"offsetx"`
+	OffsetY     float64         `json:"offsety"`
+	Width       int             `json:"width"`
+	Data        json.RawMessage `json:"data"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Chunks      json.RawMessage `json:"chunks"` // 無限マップのタイルデータ（読めない）
+	Objects     []tiledObject   `json:"objects"`
+	Layers      []tiledLayer    `json:"layers"`
+	
+	tiles []uint32 // タイルレイヤーのGID（左上から行ごと）
+}
+
+// tiledObject is an object placed on an object layer
+type tiledObject struct {
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`  // Tiled 1.8 まで
+	Class      string          `json:"class"` // Tiled 1.9 から
+	X          float64         `json:"x"`
+	Y          float64         `json:"y"`
+	Width      float64         `json:"width"`
+	Height     float64         `json:"height"`
+	Point      bool            `json:"point"`
+	Ellipse    bool            `json:"ellipse"`
+	GID        uint32          `json:"gid"` // タイルオブジェクト（0: なし）
+	Properties tiledProperties `json:"properties"`
+}
+
+// tiledTileset is a tileset of the map; only tiles with properties matter
+type tiledTileset struct {
+	FirstGID uint32      `json:"firstgid"`
+	Source   string      `json:"source"` // 外部タイルセット（.tsx・.tsj）
+	Tiles    []tiledTile `json:"tiles"`
+}
+
+// tiledTile is a tile of a tileset with its custom properties
+type tiledTile struct {
+	ID         uint32          `json:"id"`
+	Properties tiledProperties `json:"properties"`
+}
+
+// tiledProperty is a custom property; values are kept as text whatever their Tiled type
+type tiledProperty struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// tiledProperties are the custom properties of an object or tile
+type tiledProperties []tiledProperty
+
+// text returns the property's value as text, or "" when it is not set
+func (tp tiledProperties) text(name string) string {
+	for _, property := range tp {
+		if property.Name == name && property.Value != nil {
+			return fmt.Sprint(property.Value)
+		}
+	}
+	return ""
+}
+
+// float returns the property's value as a number, and false when it is not set or not a number
+func (tp tiledProperties) float(name string) (float64, bool) {
+	value, err := strconv.ParseFloat(tp.text(name), 64)
+	return value, err == nil
+}
+
+// loadTiledMap reads a Tiled map saved as TMX (.tmx) or JSON (.tmj, .json) with its external tilesets
+func loadTiledMap(filename string) (*tiledMap, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read map %s: %w", filename, err)
+	}
+	
+	var tm *tiledMap
+	if strings.EqualFold(filepath.Ext(filename), ".tmx") {
+		tm, err = parseTMX(data)
+	} else {
+		tm, err = parseTiledJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse map %s: %w", filename, err)
+	}
+	if tm.Infinite {
+		return nil, fmt.Errorf("map %s is infinite; save it with a fixed size", filename)
+	}
+	
+	for i := range tm.Tilesets {
+		tileset := &tm.Tilesets[i]
+		if tileset.Source == "" {
+			continue
+		}
+		source := filepath.Join(filepath.Dir(filename), tileset.Source)
+		tiles, err := loadTiledTileset(source)
+		if err != nil {
+			return nil, err
+		}
+		tileset.Tiles = tiles
+	}
+	return tm, nil
+}
+
+// loadTiledTileset reads the tiles of an external tileset saved as TSX (.tsx) or JSON (.tsj, .json)
+func loadTiledTileset(filename string) ([]tiledTile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tileset %s: %w", filename, err)
+	}
+	
+	if strings.EqualFold(filepath.Ext(filename), ".tsx") {
+		var tileset tmxTileset
+		if err := xml.Unmarshal(data, &tileset); err != nil {
+			return nil, fmt.Errorf("failed to parse tileset %s: %w", filename, err)
+		}
+		return tileset.tiles(), nil
+	}
+	var tileset tiledTileset
+	if err := json.Unmarshal(data, &tileset); err != nil {
+		return nil, fmt.Errorf("failed to parse tileset %s: %w", filename, err)
+	}
+	return tileset.Tiles, nil
+}
+
+// parseTiledJSON reads a map saved as JSON, decoding the tile data of its layers
+func parseTiledJSON(data []byte) (*tiledMap, error) {
+	var tm tiledMap
+	if err := json.Unmarshal(data, &tm); err != nil {
+		return nil, err
+	}
+	if err := decodeJSONLayers(tm.Layers); err != nil {
+		return nil, err
+	}
+	return &tm, nil
+}
+
+// decodeJSONLayers decodes the tiles of each tile layer, given as a list of GIDs or as base64 text
+func decodeJSONLayers(layers []tiledLayer) error {
+	for i := range layers {
+		layer := &layers[i]
+		if err := decodeJSONLayers(layer.Layers); err != nil {
+			return err
+		}
+		if layer.Type != "tilelayer" {
+			continue
+		}
+		if len(layer.Chunks) > 0 {
+			return fmt.Errorf("layer %s is stored in chunks; save the map with a fixed size", layer.Name)
+		}
+		if len(layer.Data) == 0 {
+			continue
+		}
+		
+		var err error
+		if layer.Encoding == "base64" {
+			var text string
+			if err = json.Unmarshal(layer.Data, &text); err == nil {
+				layer.tiles, err = decodeTiledBase64(text, layer.Compression)
+			}
+		} else {
+			err = json.Unmarshal(layer.Data, &layer.tiles)
+		}
+		if err != nil {
+			return fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+	}
+	return nil
+}
+
+// decodeTiledBase64 decodes base64 tile data, optionally zlib or gzip compressed, into GIDs
+func decodeTiledBase64(text, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+	
+	var reader io.Reader = bytes.NewReader(raw)
+	switch compression {
+	case "":
+	case "zlib":
+		if reader, err = zlib.NewReader(reader); err != nil {
+			return nil, err
+		}
+	case "gzip":
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tile compression %q; use CSV, zlib or gzip", compression)
+	}
+	raw, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	
+	tiles := make([]uint32, len(raw)/4)
+	for i := range tiles {
+		tiles[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return tiles, nil
+}
+
+// decodeTiledCSV decodes CSV tile data into GIDs
+func decodeTiledCSV(text string) ([]uint32, error) {
+	var tiles []uint32
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		gid, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		tiles = append(tiles, uint32(gid))
+	}
+	return tiles, nil
+}
+
+// tmxMap is a map saved as TMX, the XML form of the same map, converted to tiledMap once read
+type tmxMap struct {
+	Width      int          `xml:"width,attr"`
+	Height     int          `xml:"height,attr"`
+	TileWidth  int          `xml:"tilewidth,attr"`
+	TileHeight int          `xml:"tileheight,attr"`
+	Infinite   int          `xml:"infinite,attr"`
+	Tilesets   []tmxTileset `xml:"tileset"`
+	tmxGroup
+}
+
+// tmxGroup holds the layers of the map or of a group layer
+type tmxGroup struct {
+	Name         string           `xml:"name,attr"`
+	Visible      string           `xml:"visible,attr"` // 省略時 "1"
+	OffsetX      float64          `xml:"offsetx,attr"`
+	OffsetY      float64          `xml:"offsety,attr"`
+	Layers       []tmxLayer       `xml:"layer"`
+	ObjectGroups []tmxObjectGroup `xml:"objectgroup"`
+	Groups       []tmxGroup       `xml:"group"`
+}
+
+// tmxLayer is a tile layer of a TMX map
+type tmxLayer struct {
+	Name    string  `xml:"name,attr"`
+	Visible string  `xml:"visible,attr"`
+	OffsetX float64 `xml:"offsetx,attr"`
+	OffsetY float64 `xml:"offsety,attr"`
+	Width   int     `xml:"width,attr"`
+	Data    struct {
+		Encoding    string `xml:"encoding,attr"`
+		Compression string `xml:"compression,attr"`
+		Text        string `xml:",chardata"`
+		Tiles       []struct {
+			GID uint32 `xml:"gid,attr"`
+		} `xml:"tile"`
+		Chunks []struct{} `xml:"chunk"` // 無限マップのタイルデータ（読めない）
+	} `xml:"data"`
+}
+
+// tmxObjectGroup is an object layer of a TMX map
+type tmxObjectGroup struct {
+	Name    string      `xml:"name,attr"`
+	Visible string      `xml:"visible,attr"`
+	OffsetX float64     `xml:"offsetx,attr"`
+	OffsetY float64     `xml:"offsety,attr"`
+	Objects []tmxObject `xml:"object"`
+}
+
+// tmxObject is an object of a TMX map
+type tmxObject struct {
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Point      *struct{}     `xml:"point"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+// tmxTileset is a tileset of a TMX map or a TSX file
+type tmxTileset struct {
+	FirstGID uint32 `xml:"firstgid,attr"`
+	Source   string `xml:"source,attr"`
+	Tiles    []struct {
+		ID         uint32        `xml:"id,attr"`
+		Properties []tmxProperty `xml:"properties>property"`
+	} `xml:"tile"`
+}
+
+// tmxProperty is a custom property of a TMX object or tile
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"` // 複数行の文字列
+}
+
+// parseTMX reads a map saved as TMX
+func parseTMX(data []byte) (*tiledMap, error) {
+	var tmx tmxMap
+	if err := xml.Unmarshal(data, &tmx); err != nil {
+		return nil, err
+	}
+	
+	tm := &tiledMap{
+		Width:      tmx.Width,
+		Height:     tmx.Height,
+		TileWidth:  tmx.TileWidth,
+		TileHeight: tmx.TileHeight,
+		Infinite:   tmx.Infinite != 0,
+	}
+	for _, tileset := range tmx.Tilesets {
+		tm.Tilesets = append(tm.Tilesets, tiledTileset{FirstGID: tileset.FirstGID, Source: tileset.Source, Tiles: tileset.tiles()})
+	}
+	layers, err := tmx.tmxGroup.layers()
+	if err != nil {
+		return nil, err
+	}
+	tm.Layers = layers
+	return tm, nil
+}
+
+// tiles converts the tileset's tiles
+func (ts tmxTileset) tiles() []tiledTile {
+	var tiles []tiledTile
+	for _, tile := range ts.Tiles {
+		tiles = append(tiles, tiledTile{ID: tile.ID, Properties: tmxProperties(tile.Properties)})
+	}
+	return tiles
+}
+
+// layers converts the group's tile layers, object layers and nested groups
+// TMX keeps the three kinds apart, so they come out in that order rather than as drawn
+func (g tmxGroup) layers() ([]tiledLayer, error) {
+	var layers []tiledLayer
+	for _, layer := range g.Layers {
+		if len(layer.Data.Chunks) > 0 {
+			return nil, fmt.Errorf("layer %s is stored in chunks; save the map with a fixed size", layer.Name)
+		}
+		converted := tiledLayer{Name: layer.Name, Type: "tilelayer", Visible: layer.Visible != "0", OffsetX: layer.OffsetX, OffsetY: layer.OffsetY, Width: layer.Width}
+		var err error
+		switch layer.Data.Encoding {
+		case "csv":
+			converted.tiles, err = decodeTiledCSV(layer.Data.Text)
+		case "base64":
+			converted.tiles, err = decodeTiledBase64(layer.Data.Text, layer.Data.Compression)
+		default:
+			for _, tile := range layer.Data.Tiles {
+				converted.tiles = append(converted.tiles, tile.GID)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+		layers = append(layers, converted)
+	}
+	
+	for _, group := range g.ObjectGroups {
+		converted := tiledLayer{Name: group.Name, Type: "objectgroup", Visible: group.Visible != "0", OffsetX: group.OffsetX, OffsetY: group.OffsetY}
+		for _, object := range group.Objects {
+			converted.Objects = append(converted.Objects, tiledObject{
+				Name:       object.Name,
+				Type:       object.Type,
+				Class:      object.Class,
+				X:          object.X,
+				Y:          object.Y,
+				Width:      object.Width,
+				Height:     object.Height,
+				Point:      object.Point != nil,
+				Ellipse:    object.Ellipse != nil,
+				GID:        object.GID,
+				Properties: tmxProperties(object.Properties),
+			})
+		}
+		layers = append(layers, converted)
+	}
+	
+	for _, group := range g.Groups {
+		children, err := group.layers()
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, tiledLayer{Name: group.Name, Type: "group", Visible: group.Visible != "0", OffsetX: group.OffsetX, OffsetY: group.OffsetY, Layers: children})
+	}
+	return layers, nil
+}
+
+// tmxProperties converts TMX properties, whose long strings are the element's text
+func tmxProperties(properties []tmxProperty) tiledProperties {
+	var converted tiledProperties
+	for _, property := range properties {
+		value := property.Value
+		if value == "" {
+			value = strings.TrimSpace(property.Text)
+		}
+		converted = append(converted, tiledProperty{Name: property.Name, Value: value})
+	}
+	return converted
+}
+
+// tiledTerrain is the ground a tile stands for
+type tiledTerrain struct {
+	name     string
+	movement float64
+	cover    bool
+}
+
+// tileProperties returns the properties of the tile a GID refers to (nil: an empty cell or a plain tile)
+func (tm *tiledMap) tileProperties(gid uint32) tiledProperties {
+	gid &^= tiledFlipFlags
+	if gid == 0 {
+		return nil
+	}
+	var tileset *tiledTileset
+	for i := range tm.Tilesets {
+		if tm.Tilesets[i].FirstGID <= gid && (tileset == nil || tm.Tilesets[i].FirstGID > tileset.FirstGID) {
+			tileset = &tm.Tilesets[i]
+		}
+	}
+	if tileset == nil {
+		return nil
+	}
+	for _, tile := range tileset.Tiles {
+		if tile.ID == gid-tileset.FirstGID {
+			return tile.Properties
+		}
+	}
+	return nil
+}
+
+// visitLayers calls visit for every visible layer outside groups, with the offset of the groups it is in
+func visitLayers(layers []tiledLayer, offsetX, offsetY float64, visit func(layer tiledLayer, offsetX, offsetY float64)) {
+	for _, layer := range layers {
+		if !layer.Visible {
+			continue
+		}
+		if layer.Type == "group" {
+			visitLayers(layer.Layers, offsetX+layer.OffsetX, offsetY+layer.OffsetY, visit)
+			continue
+		}
+		visit(layer, offsetX+layer.OffsetX, offsetY+layer.OffsetY)
+	}
+}
+
+// importTiledMap lays the map out on the stage: tile layers become terrain areas, and object layers
+// add deployment points, obstacles and capture points and move reinforcements to their spawns
+// It returns what it could not import; the stage keeps everything else stages.toml gives it
+func (dm *DataManager) importTiledMap(stage *StageConfig, tm *tiledMap) []string {
+	var warnings []string
+	if stage.Width == 0 && stage.Height == 0 {
+		stage.Width, stage.Height = tm.Width*tm.TileWidth, tm.Height*tm.TileHeight
+	}
+	
+	terrains := make(map[uint32]*tiledTerrain)
+	visitLayers(tm.Layers, 0, 0, func(layer tiledLayer, offsetX, offsetY float64) {
+		switch layer.Type {
+		case "tilelayer":
+			width := layer.Width
+			if width <= 0 {
+				width = tm.Width
+			}
+			if width <= 0 {
+				warnings = append(warnings, fmt.Sprintf("layer %s has no width; its tiles are left out", layer.Name))
+				return
+			}
+			if len(layer.tiles)%width != 0 {
+				warnings = append(warnings, fmt.Sprintf("layer %s has %d tiles, not whole rows of %d; the last row is left out", layer.Name, len(layer.tiles), width))
+			}
+			grid := make([]*tiledTerrain, len(layer.tiles))
+			for i, gid := range layer.tiles {
+				if _, seen := terrains[gid]; !seen {
+					terrain, warning := dm.tileTerrain(tm.tileProperties(gid))
+					terrains[gid] = terrain
+					if warning != "" {
+						warnings = append(warnings, fmt.Sprintf("layer %s: %s", layer.Name, warning))
+					}
+				}
+				grid[i] = terrains[gid]
+			}
+			for _, area := range mergeTerrainCells(grid, width) {
+				area.X1 = area.X1*float64(tm.TileWidth) + offsetX
+				area.Y1 = area.Y1*float64(tm.TileHeight) + offsetY
+				area.X2 = area.X2*float64(tm.TileWidth) + offsetX
+				area.Y2 = area.Y2*float64(tm.TileHeight) + offsetY
+				stage.TerrainAreas = append(stage.TerrainAreas, area)
+			}
+		case "objectgroup":
+			for _, object := range layer.Objects {
+				if warning := importTiledObject(stage, object, offsetX, offsetY); warning != "" {
+					warnings = append(warnings, fmt.Sprintf("layer %s: %s", layer.Name, warning))
+				}
+			}
+		}
+	})
+	return warnings
+}
+
+// tileTerrain returns the ground a tile's properties describe: "terrain" takes the name and movement
+// of a terrain.toml type, and "name", "movement_modifier" and "cover" set or override them
+// It returns nil for a tile with none of them, which leaves the ground as it is
+func (dm *DataManager) tileTerrain(properties tiledProperties) (*tiledTerrain, string) {
+	var warning string
+	terrain := &tiledTerrain{movement: 1.0}
+	known := false
+	if id := properties.text("terrain"); id != "" {
+		if config, exists := dm.Terrains.TerrainTypes[id]; exists {
+			terrain.name, terrain.movement = config.Name, config.MovementModifier
+			known = true
+		} else {
+			warning = fmt.Sprintf("tile refers to unknown terrain %q", id)
+		}
+	}
+	if name := properties.text("name"); name != "" {
+		terrain.name = name
+	}
+	if movement, ok := properties.float("movement_modifier"); ok {
+		terrain.movement = movement
+		known = true
+	}
+	if cover := properties.text("cover"); cover != "" {
+		terrain.cover = cover == "true"
+		known = true
+	}
+	if !known {
+		return nil, warning
+	}
+	return terrain, warning
+}
+
+// mergeTerrainCells joins neighbouring cells of the same ground into as few rectangles as it easily can,
+// growing each run of a row downward while the rows below match; coordinates are in cells
+func mergeTerrainCells(grid []*tiledTerrain, width int) []TerrainAreaConfig {
+	var areas []TerrainAreaConfig
+	used := make([]bool, len(grid))
+	rows := len(grid) / width
+	same := func(col, row int, terrain *tiledTerrain) bool {
+		i := row*width + col
+		return !used[i] && grid[i] != nil && *grid[i] == *terrain
+	}
+	
+	for row := 0; row < rows; row++ {
+		for col := 0; col < width; col++ {
+			terrain := grid[row*width+col]
+			if terrain == nil || used[row*width+col] {
+				continue
+			}
+			
+			runWidth := 1
+			for col+runWidth < width && same(col+runWidth, row, terrain) {
+				runWidth++
+			}
+			runHeight := 1
+			for row+runHeight < rows {
+				matches := true
+				for c := col; c < col+runWidth && matches; c++ {
+					matches = same(c, row+runHeight, terrain)
+				}
+				if !matches {
+					break
+				}
+				runHeight++
+			}
+			for r := row; r < row+runHeight; r++ {
+				for c := col; c < col+runWidth; c++ {
+					used[r*width+c] = true
+				}
+			}
+			
+			areas = append(areas, TerrainAreaConfig{
+				Name:             terrain.name,
+				X1:               float64(col),
+				Y1:               float64(row),
+				X2:               float64(col + runWidth),
+				Y2:               float64(row + runHeight),
+				MovementModifier: terrain.movement,
+				Cover:            terrain.cover,
+			})
+		}
+	}
+	return areas
+}
+
+// importTiledObject adds what one object stands for to the stage by its class, ignoring classes it does not know
+// It returns why the object could not be imported ("" when it was)
+func importTiledObject(stage *StageConfig, object tiledObject, offsetX, offsetY float64) string {
+	class := object.Class
+	if class == "" {
+		class = object.Type
+	}
+	
+	// Tiled places rectangles and ellipses by their top-left corner and tile objects by their bottom-left
+	x, y := object.X+offsetX, object.Y+offsetY
+	if !object.Point {
+		x += object.Width / 2
+		if object.GID != 0 {
+			y -= object.Height / 2
+		} else {
+			y += object.Height / 2
+		}
+	}
+	radius, ok := object.Properties.float("radius")
+	if !ok {
+		radius = max(object.Width, object.Height) / 2
+	}
+	
+	switch class {
+	case TiledDeployment:
+		label := object.Properties.text("army")
+		if ArmyIndex(label) < 0 {
+			return fmt.Sprintf("deployment %q needs an army property (\"a\", \"b\", ...)", object.Name)
+		}
+		count := 1
+		if value, ok := object.Properties.float("count"); ok && value > 1 {
+			count = int(value)
+		}
+		for _, point := range spreadDeployment(x, y, object.Width, object.Height, count) {
+			stage.addDeploymentPoint(label, point)
+		}
+	case ObstacleTree, ObstacleRock:
+		stage.Obstacles = append(stage.Obstacles, ObstacleConfig{Kind: class, X: x, Y: y, Radius: radius})
+	case TiledCapturePoint:
+		point := CapturePointConfig{Name: object.Name, X: x, Y: y, Radius: radius}
+		point.CaptureTime, _ = object.Properties.float("capture_time")
+		point.ScoreRate, _ = object.Properties.float("score_rate")
+		stage.CapturePoints = append(stage.CapturePoints, point)
+	case TiledReinforcement:
+		found := false
+		for i := range stage.Reinforcements {
+			if stage.Reinforcements[i].Name == object.Name {
+				stage.Reinforcements[i].X, stage.Reinforcements[i].Y = x, y
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Sprintf("no reinforcement named %q in stages.toml", object.Name)
+		}
+	}
+	return ""
+}
+
+// spreadDeployment returns count points spaced evenly along the longer side of a deployment zone
+func spreadDeployment(x, y, width, height float64, count int) []DeploymentPoint {
+	points := make([]DeploymentPoint, count)
+	for i := range points {
+		offset := (float64(i)+0.5)/float64(count) - 0.5
+		if width >= height {
+			points[i] = DeploymentPoint{X: x + offset*width, Y: y}
+		} else {
+			points[i] = DeploymentPoint{X: x, Y: y + offset*height}
+		}
+	}
+	return points
+}
+
+// addDeploymentPoint adds a deployment point for the army with the label, turning
+// deployment_points_a/b into armies when a third army gets one
+func (sc *StageConfig) addDeploymentPoint(label string, point DeploymentPoint) {
+	index := ArmyIndex(label)
+	if len(sc.Armies) == 0 {
+		switch index {
+		case 0:
+			sc.DeploymentPointsA = append(sc.DeploymentPointsA, point)
+			return
+		case 1:
+			sc.DeploymentPointsB = append(sc.DeploymentPointsB, point)
+			return
+		}
+		sc.Armies = sc.GetArmyConfigs()
+	}
+	for len(sc.Armies) <= index {
+		sc.Armies = append(sc.Armies, StageArmyConfig{Name: "軍勢" + ArmyLabel(len(sc.Armies))})
+	}
+	sc.Armies[index].DeploymentPoints = append(sc.Armies[index].DeploymentPoints, point)
+}
+
+// importMaps lays out each stage that names a Tiled map; a map that cannot be read is reported
+// and its stage keeps only what stages.toml gives it
+func (dm *DataManager) importMaps() {
+	for _, id := range sortedKeys(dm.Stages.Stages) {
+		stage := dm.Stages.Stages[id]
+		if stage.Map == "" {
+			continue
+		}
+		tm, err := loadTiledMap(stage.Map)
+		if err != nil {
+			fmt.Printf("Warning: stage %s: %v\n", id, err)
+			continue
+		}
+		for _, warning := range dm.importTiledMap(&stage, tm) {
+			fmt.Printf("Warning: stage %s: %s\n", id, warning)
+		}
+		dm.Stages.Stages[id] = stage
+	}
+}
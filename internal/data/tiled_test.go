@@ -0,0 +1,261 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// encodeTiles writes GIDs as Tiled's base64 tile data with the given compression
+func encodeTiles(t *testing.T, tiles []uint32, compression string) string {
+	t.Helper()
+	raw := make([]byte, len(tiles)*4)
+	for i, gid := range tiles {
+		binary.LittleEndian.PutUint32(raw[i*4:], gid)
+	}
+	
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch compression {
+	case "":
+		buf.Write(raw)
+	case "zlib":
+		writer = zlib.NewWriter(&buf)
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	}
+	if writer != nil {
+		if _, err := writer.Write(raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDecodeTileData(t *testing.T) {
+	// A flipped tile keeps its flags until the tileset lookup strips them
+	want := []uint32{0, 1, 2, 0x80000002, 0x40000001 | 0x20000000}
+	
+	tests := []struct {
+		name   string
+		decode func() ([]uint32, error)
+	}{
+		{
+			name:   "csv",
+			decode: func() ([]uint32, error) { return decodeTiledCSV("\n0,1,2,\n2147483650,1610612737\n") },
+		},
+		{
+			name:   "base64",
+			decode: func() ([]uint32, error) { return decodeTiledBase64(encodeTiles(t, want, ""), "") },
+		},
+		{
+			name:   "base64 zlib",
+			decode: func() ([]uint32, error) { return decodeTiledBase64(encodeTiles(t, want, "zlib"), "zlib") },
+		},
+		{
+			name:   "base64 gzip",
+			decode: func() ([]uint32, error) { return decodeTiledBase64("\n  "+encodeTiles(t, want, "gzip")+"\n", "gzip") },
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiles, err := tt.decode()
+			if err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			if !reflect.DeepEqual(tiles, want) {
+				t.Errorf("tiles = %#x, want %#x", tiles, want)
+			}
+		})
+	}
+}
+
+func TestDecodeTileDataRejectsUnknownCompression(t *testing.T) {
+	_, err := decodeTiledBase64(encodeTiles(t, []uint32{1}, ""), "zstd")
+	if err == nil || !strings.Contains(err.Error(), "unsupported tile compression") {
+		t.Errorf("decodeTiledBase64() error = %v, want unsupported compression", err)
+	}
+}
+
+func TestTilePropertiesAcrossTilesets(t *testing.T) {
+	grass := tiledProperties{{Name: "terrain", Value: "grass"}}
+	forest := tiledProperties{{Name: "terrain", Value: "forest"}}
+	water := tiledProperties{{Name: "terrain", Value: "water"}}
+	tm := &tiledMap{Tilesets: []tiledTileset{
+		// Listed out of order: the tileset with the highest first GID not above the tile wins
+		{FirstGID: 10, Tiles: []tiledTile{{ID: 0, Properties: water}}},
+		{FirstGID: 1, Tiles: []tiledTile{{ID: 0, Properties: grass}, {ID: 2, Properties: forest}}},
+	}}
+	
+	tests := []struct {
+		name string
+		gid  uint32
+		want tiledProperties
+	}{
+		{name: "empty cell", gid: 0, want: nil},
+		{name: "first tileset", gid: 1, want: grass},
+		{name: "tile without properties", gid: 2, want: nil},
+		{name: "later tile of the first tileset", gid: 3, want: forest},
+		{name: "second tileset", gid: 10, want: water},
+		{name: "flipped horizontally", gid: 0x80000003, want: forest},
+		{name: "flipped vertically and diagonally", gid: 0x40000000 | 0x20000000 | 10, want: water},
+		{name: "flip flags alone", gid: 0x80000000, want: nil},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tm.tileProperties(tt.gid); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tileProperties(%#x) = %v, want %v", tt.gid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsChunkedLayers(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func() (*tiledMap, error)
+	}{
+		{
+			name: "json",
+			parse: func() (*tiledMap, error) {
+				return parseTiledJSON([]byte(`{"layers": [{"name": "ground", "type": "tilelayer", "chunks": [{"x": 0, "y": 0, "width": 16, "height": 16, "data": [1]}]}]}`))
+			},
+		},
+		{
+			name: "json in a group",
+			parse: func() (*tiledMap, error) {
+				return parseTiledJSON([]byte(`{"layers": [{"type": "group", "layers": [{"name": "ground", "type": "tilelayer", "chunks": [{"data": [1]}]}]}]}`))
+			},
+		},
+		{
+			name: "tmx",
+			parse: func() (*tiledMap, error) {
+				return parseTMX([]byte(`<map><layer name="ground"><data encoding="csv"><chunk x="0" y="0" width="2" height="1">1,1</chunk></data></layer></map>`))
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse()
+			if err == nil || !strings.Contains(err.Error(), "layer ground is stored in chunks") {
+				t.Errorf("parse error = %v, want the chunked layer reported", err)
+			}
+		})
+	}
+}
+
+func TestLoadTiledMapRejectsInfiniteMaps(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "endless.tmj")
+	if err := os.WriteFile(filename, []byte(`{"width": 4, "height": 4, "infinite": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadTiledMap(filename); err == nil || !strings.Contains(err.Error(), "is infinite") {
+		t.Errorf("loadTiledMap() error = %v, want the infinite map reported", err)
+	}
+}
+
+func TestImportTiledMapLeavesOutPartialRows(t *testing.T) {
+	dm := &DataManager{Terrains: &TerrainsConfig{TerrainTypes: map[string]TerrainConfig{
+		"forest": {Name: "森", MovementModifier: 0.5},
+	}}}
+	tm := &tiledMap{
+		Width:      3,
+		Height:     3,
+		TileWidth:  10,
+		TileHeight: 10,
+		Tilesets:   []tiledTileset{{FirstGID: 1, Tiles: []tiledTile{{ID: 0, Properties: tiledProperties{{Name: "terrain", Value: "forest"}}}}}},
+		// Seven tiles in rows of three: the forest tile on the unfinished third row is left out
+		Layers: []tiledLayer{{Name: "ground", Type: "tilelayer", Visible: true, Width: 3, tiles: []uint32{1, 1, 1, 1, 1, 1, 1}}},
+	}
+	
+	stage := &StageConfig{}
+	warnings := dm.importTiledMap(stage, tm)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "layer ground has 7 tiles") {
+		t.Errorf("warnings = %q, want one about the partial row", warnings)
+	}
+	want := []TerrainAreaConfig{{Name: "森", X1: 0, Y1: 0, X2: 30, Y2: 20, MovementModifier: 0.5}}
+	if !reflect.DeepEqual(stage.TerrainAreas, want) {
+		t.Errorf("TerrainAreas = %+v, want %+v", stage.TerrainAreas, want)
+	}
+}
+
+func TestMergeTerrainCells(t *testing.T) {
+	forest := &tiledTerrain{name: "森", movement: 0.5, cover: true}
+	water := &tiledTerrain{name: "川", movement: 0}
+	
+	tests := []struct {
+		name  string
+		grid  []*tiledTerrain
+		width int
+		want  []TerrainAreaConfig
+	}{
+		{
+			name:  "empty ground",
+			grid:  []*tiledTerrain{nil, nil, nil, nil},
+			width: 2,
+			want:  nil,
+		},
+		{
+			name:  "full rectangle",
+			grid:  []*tiledTerrain{forest, forest, forest, forest},
+			width: 2,
+			want: []TerrainAreaConfig{
+				{Name: "森", X1: 0, Y1: 0, X2: 2, Y2: 2, MovementModifier: 0.5, Cover: true},
+			},
+		},
+		{
+			// F F .
+			// F . .
+			// F . .
+			name:  "l-shape",
+			grid:  []*tiledTerrain{forest, forest, nil, forest, nil, nil, forest, nil, nil},
+			width: 3,
+			want: []TerrainAreaConfig{
+				{Name: "森", X1: 0, Y1: 0, X2: 2, Y2: 1, MovementModifier: 0.5, Cover: true},
+				{Name: "森", X1: 0, Y1: 1, X2: 1, Y2: 3, MovementModifier: 0.5, Cover: true},
+			},
+		},
+		{
+			// F W
+			// F W
+			name:  "two grounds side by side",
+			grid:  []*tiledTerrain{forest, water, forest, water},
+			width: 2,
+			want: []TerrainAreaConfig{
+				{Name: "森", X1: 0, Y1: 0, X2: 1, Y2: 2, MovementModifier: 0.5, Cover: true},
+				{Name: "川", X1: 1, Y1: 0, X2: 2, Y2: 2, MovementModifier: 0},
+			},
+		},
+		{
+			// Cells from different tiles that stand for the same ground still merge
+			name:  "equal grounds from different tiles",
+			grid:  []*tiledTerrain{forest, {name: "森", movement: 0.5, cover: true}},
+			width: 2,
+			want: []TerrainAreaConfig{
+				{Name: "森", X1: 0, Y1: 0, X2: 2, Y2: 1, MovementModifier: 0.5, Cover: true},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeTerrainCells(tt.grid, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeTerrainCells() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package data
+
+// Stage trigger conditions
+const (
+	ConditionTime        = "time"         // time 秒経過
+	ConditionUnitsBelow  = "units_below"  // army の生き残りのユニットが count 体未満
+	ConditionZoneEntered = "zone_entered" // army（空: どの軍勢でも）のユニットが x, y の radius 内に入る
+)
+
+// Stage trigger actions
+const (
+	ActionSpawn     = "spawn"      // army の軍勢に x, y 地点から groups の部隊が現れる
+	ActionWeather   = "weather"    // 天候を weather に変える
+	ActionMessage   = "message"    // text を戦闘中のメッセージに表示する
+	ActionEndBattle = "end_battle" // army の勝利（空: 引き分け）で戦闘を終える
+)
+
+// Weathers a trigger can bring
+const (
+	WeatherWind  = "wind"  // wind_from の方角から wind_speed の風が吹く
+	WeatherCalm  = "calm"  // 風がやむ
+	WeatherFog   = "fog"   // 霧が出て、味方の視界外の敵が見えなくなる
+	WeatherClear = "clear" // 霧が晴れる
+)
+
+// TriggerConfig represents a scripted event of a stage: once its condition is met, its actions run in order
+type TriggerConfig struct {
+	Name      string                `toml:"name"`
+	Condition string                `toml:"condition"` // 条件（省略時は "time"）
+	Time      float64               `toml:"time"`      // time: 経過秒数。ほかの条件ではこの秒数までは判定しない
+	Army      string                `toml:"army"`      // units_below: 数える軍勢、zone_entered: 入る軍勢（空: どの軍勢でも）
+	Count     int                   `toml:"count"`     // units_below: この数を下回ると満たす
+	X         float64               `toml:"x"`         // zone_entered: 範囲の中心
+	Y         float64               `toml:"y"`
+	Radius    float64               `toml:"radius"` // zone_entered: 範囲の半径
+	Actions   []TriggerActionConfig `toml:"actions"`
+}
+
+// TriggerActionConfig represents one thing a trigger does
+type TriggerActionConfig struct {
+	Type      string                     `toml:"type"`
+	Text      string                     `toml:"text"` // message: 表示する文
+	Army      string                     `toml:"army"` // spawn: 部隊が加わる軍勢、end_battle: 勝つ軍勢（空: 引き分け）
+	X         float64                    `toml:"x"`    // spawn: 出現地点
+	Y         float64                    `toml:"y"`
+	Groups    []ReinforcementGroupConfig `toml:"groups"`     // spawn: 現れる部隊
+	Weather   string                     `toml:"weather"`    // weather: "wind"・"calm"・"fog"・"clear"
+	WindSpeed float64                    `toml:"wind_speed"` // weather "wind": 風速（m/s）
+	WindFrom  float64                    `toml:"wind_from"`  // weather "wind": 風上の方角（度、0: 北、90: 東）
+}
+
+// ArmyID returns the army index the condition counts or watches, or -1 for any army
+func (tc TriggerConfig) ArmyID() int {
+	return ArmyIndex(tc.Army)
+}
+
+// ArmyID returns the army index the action spawns for or declares the winner, or -1 for none
+func (ac TriggerActionConfig) ArmyID() int {
+	return ArmyIndex(ac.Army)
+}
@@ -11,6 +11,27 @@ type UnitTypeConfig struct {
 	SightRange float64 `toml:"sight_range"` // 知覚範囲
 	MagicPower int     `toml:"magic_power"`
 	Size       float64 `toml:"size"`  // ユニットの大きさ（衝突判定用）
+	Knockback  float64 `toml:"knockback"` // 命中で敵を押し下げる距離（px、0: 押さない）
+	Ammo       int     `toml:"ammo"`  // 矢弾の数（0: 無制限）
+	HeavyArmor bool    `toml:"heavy_armor"` // 重装備（スタミナの消耗が大きい）
+	SiegeBonus float64 `toml:"siege_bonus"` // 建造物への攻撃力の倍率（0: 1倍）
+	Flying     bool    `toml:"flying"`      // 飛行（地形・地上の衝突を無視し、近接攻撃を受けない）
+	Stealth    bool    `toml:"stealth"`     // 潜伏（近づかれるまで敵から見えず、不意打ちで大ダメージ）
+	
+	// Summoning (summon empty: none)
+	Summon         string  `toml:"summon"`          // 召喚するユニット種別
+	SummonInterval float64 `toml:"summon_interval"` // 召喚の間隔（秒）
+	SummonCount    int     `toml:"summon_count"`    // 1回に召喚する数
+	SummonLifetime float64 `toml:"summon_lifetime"` // 召喚したユニットが消えるまでの秒数
+	SummonMax      int     `toml:"summon_max"`      // 同時に従える数
+	
+	// Spells (abilities empty: none; mana 0: spells cost nothing)
+	Abilities []string `toml:"abilities"`  // 唱える魔法（abilities.toml のID、先に並べたものを優先する）
+	Mana      float64  `toml:"mana"`       // 魔力の最大値
+	ManaRegen float64  `toml:"mana_regen"` // 1秒で回復する魔力
+	
+	// Scripted AI (empty: the built-in behavior tree only)
+	AIScript string `toml:"ai_script"` // think(unit, enemies) を定義したLuaスクリプトのパス
 }
 
 // UnitsConfig represents the entire units configuration
@@ -0,0 +1,429 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+)
+
+// Defaults that replace missing or invalid values in the data files
+const (
+	DefaultUnitHP      = 100     // hp が0以下のユニット
+	DefaultUnitSpeed   = 33.3    // speed が負のユニット（歩兵の駆け足）
+	DefaultUnitRange   = 15.0    // range が0以下のユニット（槍のリーチ）
+	DefaultUnitSize    = 16.0    // size が0以下のユニット
+	DefaultTerrain     = "plain" // terrain が見つからないステージ
+	DefaultTimeLimit   = 300.0   // time_limit が0以下のステージ（5分）
+	DefaultTerrainRate = 1.0     // movement_modifier・defense_modifier が0以下の地形
+)
+
+// ValidationIssue is a problem found in a data file, reported with where it is and what was done about it
+type ValidationIssue struct {
+	File    string // データファイル名
+	Key     string // 問題のあった値のキー（"unit_types.archer.hp" など）
+	Message string
+}
+
+// String formats the issue like "units.toml [unit_types.archer.hp] must be positive, using 100"
+func (vi ValidationIssue) String() string {
+	return fmt.Sprintf("%s [%s] %s", vi.File, vi.Key, vi.Message)
+}
+
+// validator collects the issues of one data file
+type validator struct {
+	file   string
+	issues []ValidationIssue
+}
+
+// report records an issue under the key
+func (v *validator) report(key, format string, args ...any) {
+	v.issues = append(v.issues, ValidationIssue{File: v.file, Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// sortedKeys returns the keys of a data table in order, so issues are reported the same way every run
+func sortedKeys[T any](table map[string]T) []string {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missingScript reports whether a script is set but its file cannot be found
+func missingScript(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err != nil
+}
+
+// Validate checks the loaded data for missing or invalid values, replacing each with its documented default
+// or dropping entries that refer to data that does not exist, and returns what it found
+// Abilities, units, terrains and stages are checked first since the other files refer to them
+func (dm *DataManager) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	issues = append(issues, dm.validateAbilities()...)
+	issues = append(issues, dm.validateUnits()...)
+	issues = append(issues, dm.validateTerrains()...)
+	issues = append(issues, dm.validateStages()...)
+	issues = append(issues, dm.validatePresets()...)
+	issues = append(issues, dm.validateRecruitment()...)
+	issues = append(issues, dm.validateChallenges()...)
+	return issues
+}
+
+// validateUnits checks units.toml
+func (dm *DataManager) validateUnits() []ValidationIssue {
+	v := &validator{file: "units.toml"}
+	for _, id := range sortedKeys(dm.Units.UnitTypes) {
+		config := dm.Units.UnitTypes[id]
+		key := "unit_types." + id
+		if config.Name == "" {
+			v.report(key+".name", "is missing, using %q", id)
+			config.Name = id
+		}
+		if config.HP <= 0 {
+			v.report(key+".hp", "must be positive, using %d", DefaultUnitHP)
+			config.HP = DefaultUnitHP
+		}
+		if config.Attack < 0 {
+			v.report(key+".attack", "must not be negative, using 0")
+			config.Attack = 0
+		}
+		if config.Defense < 0 {
+			v.report(key+".defense", "must not be negative, using 0")
+			config.Defense = 0
+		}
+		if config.Speed < 0 {
+			v.report(key+".speed", "must not be negative, using %g", DefaultUnitSpeed)
+			config.Speed = DefaultUnitSpeed
+		}
+		if config.Range <= 0 {
+			v.report(key+".range", "must be positive, using %g", DefaultUnitRange)
+			config.Range = DefaultUnitRange
+		}
+		if config.SightRange < 0 {
+			v.report(key+".sight_range", "must not be negative, using the default")
+			config.SightRange = 0
+		}
+		if config.Size <= 0 {
+			v.report(key+".size", "must be positive, using %g", DefaultUnitSize)
+			config.Size = DefaultUnitSize
+		}
+		if config.Ammo < 0 {
+			v.report(key+".ammo", "must not be negative, using 0 (unlimited)")
+			config.Ammo = 0
+		}
+		if config.Knockback < 0 {
+			v.report(key+".knockback", "must not be negative, using 0 (no knockback)")
+			config.Knockback = 0
+		}
+		if _, exists := dm.Units.UnitTypes[config.Summon]; config.Summon != "" && !exists {
+			v.report(key+".summon", "refers to unknown unit type %q, summoning nothing", config.Summon)
+			config.Summon = ""
+		}
+		config.Abilities = slices.DeleteFunc(config.Abilities, func(ability string) bool {
+			if _, exists := dm.Abilities.Abilities[ability]; !exists {
+				v.report(key+".abilities", "refers to unknown ability %q, dropping it", ability)
+				return true
+			}
+			return false
+		})
+		if missingScript(config.AIScript) {
+			v.report(key+".ai_script", "script %q does not exist, using the built-in AI", config.AIScript)
+			config.AIScript = ""
+		}
+		dm.Units.UnitTypes[id] = config
+	}
+	return v.issues
+}
+
+// validateAbilities checks abilities.toml
+func (dm *DataManager) validateAbilities() []ValidationIssue {
+	v := &validator{file: "abilities.toml"}
+	for _, id := range sortedKeys(dm.Abilities.Abilities) {
+		config := dm.Abilities.Abilities[id]
+		key := "abilities." + id
+		if config.Name == "" {
+			v.report(key+".name", "is missing, using %q", id)
+			config.Name = id
+		}
+		if config.Effect != AbilityBolt && config.Effect != AbilityBurst {
+			v.report(key+".effect", "must be %q or %q, using %q", AbilityBolt, AbilityBurst, AbilityBolt)
+			config.Effect = AbilityBolt
+		}
+		if config.Radius < 0 || config.Splash < 0 || config.Cluster < 0 {
+			v.report(key, "radius, splash and cluster must not be negative, using the defaults")
+			config.Radius, config.Splash, config.Cluster = max(config.Radius, 0), max(config.Splash, 0), max(config.Cluster, 0)
+		}
+		if config.ManaCost < 0 || config.Cooldown < 0 || config.Channel < 0 || config.ProjectileSpeed < 0 {
+			v.report(key, "mana_cost, cooldown, channel and projectile_speed must not be negative, using 0")
+			config.ManaCost, config.Cooldown = max(config.ManaCost, 0), max(config.Cooldown, 0)
+			config.Channel, config.ProjectileSpeed = max(config.Channel, 0), max(config.ProjectileSpeed, 0)
+		}
+		dm.Abilities.Abilities[id] = config
+	}
+	return v.issues
+}
+
+// validateTerrains checks terrain.toml
+func (dm *DataManager) validateTerrains() []ValidationIssue {
+	v := &validator{file: "terrain.toml"}
+	for _, id := range sortedKeys(dm.Terrains.TerrainTypes) {
+		config := dm.Terrains.TerrainTypes[id]
+		key := "terrain_types." + id
+		if config.Name == "" {
+			v.report(key+".name", "is missing, using %q", id)
+			config.Name = id
+		}
+		if config.MovementModifier <= 0 {
+			v.report(key+".movement_modifier", "must be positive, using %g", DefaultTerrainRate)
+			config.MovementModifier = DefaultTerrainRate
+		}
+		if config.DefenseModifier <= 0 {
+			v.report(key+".defense_modifier", "must be positive, using %g", DefaultTerrainRate)
+			config.DefenseModifier = DefaultTerrainRate
+		}
+		for _, unitType := range sortedKeys(config.AttackBonuses) {
+			if config.AttackBonuses[unitType] < 0 {
+				v.report(key+".attack_bonuses."+unitType, "must not be negative, ignoring it")
+				delete(config.AttackBonuses, unitType)
+			}
+		}
+		dm.Terrains.TerrainTypes[id] = config
+	}
+	return v.issues
+}
+
+// validateStages checks stages.toml
+func (dm *DataManager) validateStages() []ValidationIssue {
+	v := &validator{file: "stages.toml"}
+	for _, id := range sortedKeys(dm.Stages.Stages) {
+		config := dm.Stages.Stages[id]
+		key := "stages." + id
+		if config.Name == "" {
+			v.report(key+".name", "is missing, using %q", id)
+			config.Name = id
+		}
+		if _, exists := dm.Terrains.TerrainTypes[config.Terrain]; !exists {
+			v.report(key+".terrain", "refers to unknown terrain %q, using %q", config.Terrain, DefaultTerrain)
+			config.Terrain = DefaultTerrain
+		}
+		if config.TimeLimit <= 0 {
+			v.report(key+".time_limit", "must be positive, using %g", DefaultTimeLimit)
+			config.TimeLimit = DefaultTimeLimit
+		}
+		if config.Width < 0 || config.Height < 0 {
+			v.report(key+".width", "width and height must not be negative, using the default size")
+			config.Width, config.Height = 0, 0
+		}
+		if config.Difficulty < 0 || config.Difficulty > MaxStageDifficulty {
+			v.report(key+".difficulty", "must be 0-%d, leaving the stage unrated", MaxStageDifficulty)
+			config.Difficulty = 0
+		}
+		
+		config.VictoryConditions = dm.validConditions(v, key, config.VictoryConditions)
+		
+		// Reinforcements and neutral camps may only field known units
+		for i := range config.Reinforcements {
+			reinforcement := &config.Reinforcements[i]
+			reinforcement.Groups = dm.validGroups(v, fmt.Sprintf("%s.reinforcements[%d]", key, i), reinforcement.Groups)
+		}
+		for i := range config.NeutralCamps {
+			camp := &config.NeutralCamps[i]
+			camp.Groups = dm.validGroups(v, fmt.Sprintf("%s.neutral_camps[%d]", key, i), camp.Groups)
+		}
+		config.Triggers = dm.validTriggers(v, key, config.Triggers)
+		if missingScript(config.Script) {
+			v.report(key+".script", "script %q does not exist, running the stage without it", config.Script)
+			config.Script = ""
+		}
+		dm.Stages.Stages[id] = config
+	}
+	return v.issues
+}
+
+// validConditions returns the victory conditions that can be played, reporting the others
+// A convoy needs an army to escort it and a route of at least two points, and survive needs the army that holds out
+func (dm *DataManager) validConditions(v *validator, key string, conditions []VictoryConditionConfig) []VictoryConditionConfig {
+	var valid []VictoryConditionConfig
+	for i, condition := range conditions {
+		conditionKey := fmt.Sprintf("%s.victory_conditions[%d]", key, i)
+		if condition.Type == VictorySurvive && ArmyIndex(condition.Army) < 0 {
+			v.report(conditionKey+".army", "must name the army that holds out, dropping the condition")
+			continue
+		}
+		if condition.Type == VictoryConvoy {
+			if ArmyIndex(condition.Army) < 0 {
+				v.report(conditionKey+".army", "must name the escorting army, dropping the condition")
+				continue
+			}
+			if len(condition.Path) < 2 {
+				v.report(conditionKey+".path", "needs at least two points, dropping the condition")
+				continue
+			}
+			if _, ok := dm.unknownUnitType(condition.ConvoyUnit()); !ok {
+				v.report(conditionKey+".unit", "refers to unknown unit type %q, using %q", condition.Unit, DefaultConvoyUnit)
+				condition.Unit = ""
+			}
+		}
+		valid = append(valid, condition)
+	}
+	return valid
+}
+
+// validTriggers returns the stage triggers that can be played, reporting the others
+// A trigger needs a known condition and at least one action; unknown actions are dropped
+func (dm *DataManager) validTriggers(v *validator, key string, triggers []TriggerConfig) []TriggerConfig {
+	var valid []TriggerConfig
+	for i, trigger := range triggers {
+		triggerKey := fmt.Sprintf("%s.triggers[%d]", key, i)
+		switch trigger.Condition {
+		case "", ConditionTime:
+		case ConditionUnitsBelow:
+			if ArmyIndex(trigger.Army) < 0 || trigger.Count <= 0 {
+				v.report(triggerKey, "units_below needs an army and a positive count, dropping the trigger")
+				continue
+			}
+		case ConditionZoneEntered:
+			if trigger.Radius <= 0 {
+				v.report(triggerKey+".radius", "must be positive, dropping the trigger")
+				continue
+			}
+		default:
+			v.report(triggerKey+".condition", "is unknown (%q), dropping the trigger", trigger.Condition)
+			continue
+		}
+		
+		var actions []TriggerActionConfig
+		for j, action := range trigger.Actions {
+			actionKey := fmt.Sprintf("%s.actions[%d]", triggerKey, j)
+			switch action.Type {
+			case ActionSpawn:
+				if ArmyIndex(action.Army) < 0 {
+					v.report(actionKey+".army", "must name the army to spawn for, dropping the action")
+					continue
+				}
+				action.Groups = dm.validGroups(v, actionKey, action.Groups)
+			case ActionWeather:
+				switch action.Weather {
+				case WeatherWind, WeatherCalm, WeatherFog, WeatherClear:
+				default:
+					v.report(actionKey+".weather", "is unknown (%q), dropping the action", action.Weather)
+					continue
+				}
+			case ActionMessage, ActionEndBattle:
+			default:
+				v.report(actionKey+".type", "is unknown (%q), dropping the action", action.Type)
+				continue
+			}
+			actions = append(actions, action)
+		}
+		if len(actions) == 0 {
+			v.report(triggerKey+".actions", "has no actions, dropping the trigger")
+			continue
+		}
+		trigger.Actions = actions
+		valid = append(valid, trigger)
+	}
+	return valid
+}
+
+// validGroups returns the groups whose leader and members are known unit types, reporting the others
+func (dm *DataManager) validGroups(v *validator, key string, groups []ReinforcementGroupConfig) []ReinforcementGroupConfig {
+	var valid []ReinforcementGroupConfig
+	for i, group := range groups {
+		groupKey := fmt.Sprintf("%s.groups[%d]", key, i)
+		if unitType, ok := dm.unknownUnitType(group.Leader, group.Member); !ok {
+			v.report(groupKey, "refers to unknown unit type %q, dropping the group", unitType)
+			continue
+		}
+		if group.Count < 0 {
+			v.report(groupKey+".count", "must not be negative, using 0")
+			group.Count = 0
+		}
+		valid = append(valid, group)
+	}
+	return valid
+}
+
+// unknownUnitType returns the first of the unit types that is not defined, and false if there is one
+func (dm *DataManager) unknownUnitType(unitTypes ...string) (string, bool) {
+	for _, unitType := range unitTypes {
+		if _, exists := dm.Units.UnitTypes[unitType]; !exists {
+			return unitType, false
+		}
+	}
+	return "", true
+}
+
+// validatePresets checks the presets.toml of the mods
+func (dm *DataManager) validatePresets() []ValidationIssue {
+	v := &validator{file: "presets.toml"}
+	for _, name := range sortedKeys(dm.Presets.Presets) {
+		config := dm.Presets.Presets[name]
+		key := "presets." + name
+		config.Groups = dm.validGroups(v, key, config.Groups)
+		if len(config.Groups) == 0 {
+			v.report(key+".groups", "has no groups, dropping the preset")
+			delete(dm.Presets.Presets, name)
+			continue
+		}
+		if config.SpikePits < 0 || config.Caltrops < 0 {
+			v.report(key, "trap counts must not be negative, using 0")
+			config.SpikePits, config.Caltrops = max(config.SpikePits, 0), max(config.Caltrops, 0)
+		}
+		dm.Presets.Presets[name] = config
+	}
+	return v.issues
+}
+
+// validateRecruitment checks recruitment.toml
+func (dm *DataManager) validateRecruitment() []ValidationIssue {
+	v := &validator{file: "recruitment.toml"}
+	var recruits []MercenaryConfig
+	for i, recruit := range dm.Recruitment.Recruits {
+		key := fmt.Sprintf("recruits[%d]", i)
+		if unitType, ok := dm.unknownUnitType(recruit.Leader, recruit.Member); !ok {
+			v.report(key, "refers to unknown unit type %q, dropping %q", unitType, recruit.Name)
+			continue
+		}
+		if recruit.Count <= 0 {
+			v.report(key+".count", "must be positive, using 1")
+			recruit.Count = 1
+		}
+		if recruit.MaxCount < recruit.Count {
+			v.report(key+".max_count", "is below count, using %d", recruit.Count)
+			recruit.MaxCount = recruit.Count
+		}
+		recruits = append(recruits, recruit)
+	}
+	dm.Recruitment.Recruits = recruits
+	return v.issues
+}
+
+// validateChallenges checks challenges.toml
+func (dm *DataManager) validateChallenges() []ValidationIssue {
+	v := &validator{file: "challenges.toml"}
+	for _, id := range sortedKeys(dm.Challenges.Challenges) {
+		config := dm.Challenges.Challenges[id]
+		key := "challenges." + id
+		if _, exists := dm.Stages.Stages[config.Stage]; !exists {
+			v.report(key+".stage", "refers to unknown stage %q, dropping the challenge", config.Stage)
+			delete(dm.Challenges.Challenges, id)
+			continue
+		}
+		config.AllowedUnits = slices.DeleteFunc(config.AllowedUnits, func(unitType string) bool {
+			if _, ok := dm.unknownUnitType(unitType); !ok {
+				v.report(key+".allowed_units", "refers to unknown unit type %q, ignoring it", unitType)
+				return true
+			}
+			return false
+		})
+		dm.Challenges.Challenges[id] = config
+	}
+	return v.issues
+}
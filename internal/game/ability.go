@@ -0,0 +1,186 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// Ability tuning
+const (
+	defaultBurstRadius  = 80.0  // burst の効果範囲（8m、未設定時）
+	defaultBurstSplash  = 0.6   // burst が目標の周りの敵に与えるダメージの割合（未設定時）
+	defaultBurstCluster = 3     // AIが burst を使う、目標の周りに固まった敵の数（未設定時）
+	manaPanicRange      = 150.0 // 敵がこの距離（15m）まで迫ると burst の分の魔力を取っておかない
+)
+
+// AbilityEffect is what an ability does where it strikes
+type AbilityEffect string
+
+const (
+	EffectBolt  AbilityEffect = data.AbilityBolt  // 目標の1体に当たる
+	EffectBurst AbilityEffect = data.AbilityBurst // 目標の周りの敵も巻き込む
+)
+
+// Ability is a spell from abilities.toml as a unit type casts it
+type Ability struct {
+	ID              string
+	Name            string
+	Effect          AbilityEffect
+	Damage          int     // 術者の攻撃力と魔法攻撃力に加えるダメージ
+	Radius          float64 // burst の効果範囲
+	Splash          float64 // burst が目標の周りの敵に与えるダメージの割合
+	Cluster         int     // AIが burst を使う、目標の周りに固まった敵の数
+	ManaCost        float64 // 1回の消費魔力
+	Cooldown        float64 // 次に唱えられるまでの秒数
+	Channel         float64 // 詠唱時間（秒、0: 詠唱なし）
+	ProjectileSpeed float64 // 魔法弾の飛ぶ速さ（px/秒、0: すぐに当たる）
+	Ignite          bool    // 当たった森や藪に火をつける
+	Visual          string  // 詠唱の光と魔法弾の色 "#RRGGBB"（空: 既定の紫）
+	
+	slot int // 術者の魔法の中での位置（再使用までの時間の添字）
+}
+
+// newAbilities looks up the unit type's abilities and fills in their defaults
+func newAbilities(abilityIDs []string, dataManager *data.DataManager) []Ability {
+	if dataManager == nil {
+		return nil
+	}
+	var abilities []Ability
+	for _, id := range abilityIDs {
+		config, err := dataManager.GetAbilityConfig(id)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		ability := Ability{
+			ID:              id,
+			Name:            config.Name,
+			Effect:          AbilityEffect(config.Effect),
+			Damage:          config.Damage,
+			Radius:          config.Radius,
+			Splash:          config.Splash,
+			Cluster:         config.Cluster,
+			ManaCost:        config.ManaCost,
+			Cooldown:        config.Cooldown,
+			Channel:         config.Channel,
+			ProjectileSpeed: config.ProjectileSpeed,
+			Ignite:          config.Ignite,
+			Visual:          config.Visual,
+			slot:            len(abilities),
+		}
+		if ability.Effect != EffectBurst {
+			ability.Effect = EffectBolt
+		}
+		if ability.Radius <= 0 {
+			ability.Radius = defaultBurstRadius
+		}
+		if ability.Splash <= 0 {
+			ability.Splash = defaultBurstSplash
+		}
+		if ability.Cluster <= 0 {
+			ability.Cluster = defaultBurstCluster
+		}
+		abilities = append(abilities, ability)
+	}
+	return abilities
+}
+
+// HasAbilities reports whether the unit casts spells instead of only swinging its weapon
+func (u *Unit) HasAbilities() bool {
+	return len(u.Spells.Abilities) > 0
+}
+
+// CastingAbility returns the spell the unit is winding up or channeling, or nil for a weapon blow
+func (u *Unit) CastingAbility() *Ability {
+	return u.casting
+}
+
+// canCast reports whether the ability is off cooldown and, for a magic user, affordable
+func (u *Unit) canCast(ability *Ability) bool {
+	return u.abilityCooldowns[ability.slot] <= 0 && (!u.UsesMana() || u.Mana >= ability.ManaCost)
+}
+
+// coolAbilities counts down the time until each ability can be cast again
+func (u *Unit) coolAbilities(deltaTime float64) {
+	for i, cooldown := range u.abilityCooldowns {
+		u.abilityCooldowns[i] = max(cooldown-deltaTime, 0)
+	}
+}
+
+// spellDamage returns the damage of the ability before defense: the caster's attack and magic power with the ability's own
+func (u *Unit) spellDamage(ability *Ability) int {
+	return u.AttackPower + u.MagicPower + ability.Damage
+}
+
+// chooseAbility picks the spell the unit casts at the target, or nil to swing its weapon or staff
+// Bursts are saved for enemies clustered around the target, and channeled spells for when no enemy can
+// break them off; bolts leave enough mana for the costliest burst unless the enemy is upon the caster.
+// Among the abilities that fit, the one listed first for the unit type is cast
+func (bm *BattleManager) chooseAbility(unit, target *Unit, enemies []*Unit) *Ability {
+	abilities := unit.Spells.Abilities
+	ready := func(ability *Ability) bool {
+		return unit.canCast(ability) && (ability.Channel <= 0 || isSafeToChannel(unit, enemies, ability.Channel))
+	}
+	
+	reserve := 0.0
+	for i := range abilities {
+		ability := &abilities[i]
+		if ability.Effect != EffectBurst {
+			continue
+		}
+		reserve = max(reserve, ability.ManaCost)
+		if ready(ability) && countClustered(target, enemies, ability.Radius) >= ability.Cluster {
+			return ability
+		}
+	}
+	
+	if !unit.UsesMana() || unit.Position.Distance(target.Position) < manaPanicRange {
+		reserve = 0
+	}
+	for i := range abilities {
+		ability := &abilities[i]
+		if ability.Effect == EffectBolt && ready(ability) && unit.Mana-ability.ManaCost >= reserve {
+			return ability
+		}
+	}
+	return nil
+}
+
+// countClustered returns how many of the enemies stand within the radius of the target, the target included
+func countClustered(target *Unit, enemies []*Unit, radius float64) int {
+	count := 0
+	for _, enemy := range enemies {
+		if enemy.IsAlive && enemy.Position.Distance(target.Position) <= radius {
+			count++
+		}
+	}
+	return count
+}
+
+// landAbility lets a spell that struck the target take effect: a burst scorches the enemies around it,
+// and a fiery one sets the forest or thicket where it struck on fire
+func (bm *BattleManager) landAbility(caster, target *Unit, ability *Ability) {
+	if ability.Effect == EffectBurst {
+		bm.landBurst(caster, target, ability)
+	}
+	if ability.Ignite {
+		bm.igniteWithSpell(caster, target.Position)
+	}
+}
+
+// landBurst lets a burst that struck the target scorch the enemies around it
+func (bm *BattleManager) landBurst(caster, target *Unit, ability *Ability) {
+	caught := 0
+	for _, enemy := range bm.GetEnemyUnits(caster.ArmyID) {
+		if enemy == target || !caster.CanHit(enemy) || enemy.Position.Distance(target.Position) > ability.Radius {
+			continue
+		}
+		damage := max(1, int(float64(caster.spellDamage(ability))*ability.Splash)-enemy.GetDefense())
+		enemy.TakeDamage(damage)
+		bm.recordEvent(BattleEvent{Type: EventHit, UnitID: caster.ID, GroupID: caster.GroupID, OtherID: enemy.ID, Amount: damage})
+		bm.Heatmap.add(HeatmapDamage, enemy.Position, float64(damage))
+		caught++
+	}
+	bm.recordEvent(BattleEvent{Type: EventBurst, UnitID: caster.ID, GroupID: caster.GroupID, OtherID: target.ID, Amount: caught})
+}
@@ -17,8 +17,30 @@ type AIBehavior struct {
 	CurrentAction    AIAction
 	ActionStartTime  float64
 	ActionDuration   float64
+	
+	// 行動ツリー（ユニット種別ごと）
+	Tree   BTNode
+	Leader *Unit // 所属部隊のリーダー（リーダー自身は nil）
+	
+	// 軍の指揮官から部隊に与えられた目標（nil: 自由交戦）
+	Objective *GroupObjective
+	
+	// リーダーが指定した部隊の集中攻撃目標（リーダー自身は nil）
+	FocusTarget *Unit
+	
+	// 所属する軍の脅威マップ（中立勢力は nil）
+	ThreatMap *ThreatMap
+	
+	// 部隊が疲れていて深追いしない
+	Fatigued bool
+	
+	// 攻城兵器が狙う敵の建造物（nil: なし）
+	SiegeTarget *Structure
 }
 
+// rangedThreatPenalty is the score lost per point of enemy melee strength around a ranged unit's target
+const rangedThreatPenalty = 1.0
+
 // AIAction represents different AI actions
 type AIAction int
 
@@ -36,6 +58,7 @@ func NewAIBehavior(unitType UnitType) *AIBehavior {
 		DecisionCooldown: 0.1, // 0.1秒間隔で判断（高速化）
 		LastDecisionTime: 0,
 		CurrentAction:    AIActionIdle,
+		Tree:             NewBehaviorTree(unitType),
 	}
 	
 	// ユニット種別に応じた設定（新スケール対応）
@@ -55,6 +78,21 @@ func NewAIBehavior(unitType UnitType) *AIBehavior {
 	case "cavalry":
 		ai.PreferredRange = 25.0  // 2.5m = 25px
 		ai.AggressionLevel = 0.9
+	case "catapult":
+		ai.PreferredRange = 800.0 // 80m = 800px（射程100mの80%）
+		ai.AggressionLevel = 0.3
+	case "ram":
+		ai.PreferredRange = 20.0  // 2m = 20px
+		ai.AggressionLevel = 0.5
+	case "griffin":
+		ai.PreferredRange = 20.0  // 2m = 20px
+		ai.AggressionLevel = 0.9
+	case "summoner":
+		ai.PreferredRange = 400.0 // 40m = 400px（射程50mの80%）
+		ai.AggressionLevel = 0.3
+	case "scout":
+		ai.PreferredRange = 15.0  // 1.5m = 15px
+		ai.AggressionLevel = 0.7
 	default:
 		ai.PreferredRange = 15.0  // デフォルト
 		ai.AggressionLevel = 0.6
@@ -77,34 +115,48 @@ func (ai *AIBehavior) Update(unit *Unit, enemies []*Unit, deltaTime float64) {
 	
 	ai.LastDecisionTime = 0
 	
-	// デバッグ: リーダーのみログ出力
-	if unit.IsLeader {
-		fmt.Printf("AI Update: Unit %d, Enemies: %d\n", unit.ID, len(enemies))
+	// 攻撃の届かない敵（近接ユニットにとっての飛行ユニット）は狙わない
+	var reachable []*Unit
+	for _, enemy := range enemies {
+		if unit.CanHit(enemy) {
+			reachable = append(reachable, enemy)
+		}
 	}
+	enemies = reachable
 	
-	// 敵の探索・選択
-	ai.selectTarget(unit, enemies)
-	
-	if ai.TargetEnemy == nil || !ai.TargetEnemy.IsAlive {
-		ai.CurrentAction = AIActionIdle
-		if unit.IsLeader {
-			fmt.Printf("Unit %d: No target\n", unit.ID)
+	// 部隊目標の範囲外の敵は相手にしない（拠点防衛など）
+	if ai.Objective != nil {
+		var allowed []*Unit
+		for _, enemy := range enemies {
+			if ai.Objective.allowsEnemy(enemy) {
+				allowed = append(allowed, enemy)
+			}
 		}
-		return
+		enemies = allowed
 	}
 	
-	// 距離ベースの行動決定
-	distance := unit.Position.Distance(ai.TargetEnemy.Position)
-	ai.decideAction(unit, distance)
+	// デバッグ: リーダーのみログ出力
+	if unit.IsLeader {
+		fmt.Printf("AI Update: Unit %d, Enemies: %d\n", unit.ID, len(enemies))
+	}
+	
+	// 行動ツリーで目標選択と行動を決定
+	ai.Tree.Tick(&BTContext{
+		Unit:      unit,
+		AI:        ai,
+		Enemies:   enemies,
+		DeltaTime: deltaTime,
+	})
 	
 	// デバッグ: 行動決定の確認
 	if unit.IsLeader {
-		fmt.Printf("Unit %d: Target=%d, Distance=%.2f, Action=%s\n", 
-			unit.ID, ai.TargetEnemy.ID, distance, ai.GetActionName())
+		if ai.TargetEnemy != nil {
+			fmt.Printf("Unit %d: Target=%d, Distance=%.2f, Action=%s\n", 
+				unit.ID, ai.TargetEnemy.ID, unit.Position.Distance(ai.TargetEnemy.Position), ai.GetActionName())
+		} else {
+			fmt.Printf("Unit %d: No target\n", unit.ID)
+		}
 	}
-	
-	// 行動実行
-	ai.executeAction(unit, distance)
 }
 
 // selectTarget selects the best target enemy
@@ -182,11 +234,31 @@ func (ai *AIBehavior) calculateTargetScore(unit *Unit, enemy *Unit, distance flo
 		score += 50.0
 	}
 	
+	// 指揮官が指定した攻撃対象の部隊を優先
+	if ai.Objective != nil && ai.Objective.isTargetGroupUnit(enemy) {
+		score += 200.0
+	}
+	
+	// リーダーが指定した集中攻撃目標を優先
+	if enemy == ai.FocusTarget {
+		score += 150.0
+	}
+	
 	// 射程内の敵にボーナス
 	if distance <= unit.Range {
 		score += 100.0
 	}
 	
+	// 櫓に籠もった敵は堅いので後回し
+	if enemy.Garrison != nil {
+		score -= garrisonTargetPenalty
+	}
+	
+	// 遠隔ユニットは敵の近接部隊が密集している地点の敵を避ける
+	if ai.ThreatMap != nil && unit.Range > rangedThreatRange {
+		score -= ai.ThreatMap.GetMeleeThreat(enemy.Position) * rangedThreatPenalty
+	}
+	
 	// ユニット種別による優先度
 	switch enemy.Type {
 	case UnitTypeMage:
@@ -200,55 +272,6 @@ func (ai *AIBehavior) calculateTargetScore(unit *Unit, enemy *Unit, distance flo
 	return score
 }
 
-// decideAction decides what action to take based on distance
-func (ai *AIBehavior) decideAction(unit *Unit, distance float64) {
-	// 衝突半径を考慮した実効距離を計算
-	effectiveDistance := distance - unit.GetCollisionRadius() - ai.TargetEnemy.GetCollisionRadius()
-	
-	// 攻撃可能距離内かチェック（実効距離で判定）
-	if effectiveDistance <= unit.Range && unit.CanAttack() {
-		ai.CurrentAction = AIActionAttack
-		return
-	}
-	
-	// 理想的な距離と比較（実効距離で判定）
-	if effectiveDistance > ai.PreferredRange * 1.2 {
-		// 遠すぎる場合は接近
-		ai.CurrentAction = AIActionApproach
-	} else if effectiveDistance < ai.PreferredRange * 0.8 && ai.isRangedUnit(unit) {
-		// 近すぎる場合は後退（遠距離ユニットのみ）
-		ai.CurrentAction = AIActionRetreat
-	} else if effectiveDistance <= unit.Range {
-		// 射程内だが攻撃できない場合は位置保持
-		ai.CurrentAction = AIActionHold
-	} else {
-		// その他の場合は接近
-		ai.CurrentAction = AIActionApproach
-	}
-}
-
-// executeAction executes the decided action
-func (ai *AIBehavior) executeAction(unit *Unit, distance float64) {
-	switch ai.CurrentAction {
-	case AIActionApproach:
-		ai.moveTowardsTarget(unit, 1.0) // 敵に向かって移動
-		
-	case AIActionRetreat:
-		ai.moveAwayFromTarget(unit, 1.0) // 敵から離れる
-		
-	case AIActionAttack:
-		// 攻撃は Unit.Attack で自動実行される
-		
-	case AIActionHold:
-		// 現在位置を保持（移動しない）
-		unit.Target = unit.Position
-		
-	case AIActionIdle:
-		// 何もしない
-		unit.Target = unit.Position
-	}
-}
-
 // moveTowardsTarget moves unit towards the target enemy
 func (ai *AIBehavior) moveTowardsTarget(unit *Unit, intensity float64) {
 	if ai.TargetEnemy == nil {
@@ -299,11 +322,6 @@ func (ai *AIBehavior) moveAwayFromTarget(unit *Unit, intensity float64) {
 	}
 }
 
-// isRangedUnit checks if the unit is a ranged unit
-func (ai *AIBehavior) isRangedUnit(unit *Unit) bool {
-	return unit.Type == UnitTypeArcher || unit.Type == UnitTypeMage
-}
-
 // GetActionName returns human-readable action name for debugging
 func (ai *AIBehavior) GetActionName() string {
 	switch ai.CurrentAction {
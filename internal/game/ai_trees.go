@@ -0,0 +1,324 @@
+package game
+
+const (
+	// protectLeaderRadius is how close an enemy must get to the leader to draw its guards
+	protectLeaderRadius = 120.0 // 12m
+	
+	// focusFireReach is how far beyond its range a member moves in to join focus fire
+	focusFireReach = 100.0 // 10m
+)
+
+// behaviorTreeBuilders maps unit types to the behavior tree they use
+var behaviorTreeBuilders = map[UnitType]func() BTNode{
+	UnitTypeInfantry:           newGuardTree,
+	UnitType("heavy_infantry"): newGuardTree,
+	UnitType("cavalry"):        newMeleeTree,
+	UnitTypeArcher:             newRangedTree,
+	UnitTypeMage:               newRangedTree,
+	UnitType("catapult"):       newCatapultTree,
+	UnitType("ram"):            newRamTree,
+	UnitType("summoner"):       newRangedTree,
+}
+
+// RegisterBehaviorTree assigns a behavior tree builder to a unit type
+func RegisterBehaviorTree(unitType UnitType, builder func() BTNode) {
+	behaviorTreeBuilders[unitType] = builder
+}
+
+// NewBehaviorTree builds the behavior tree for a unit type (melee by default)
+func NewBehaviorTree(unitType UnitType) BTNode {
+	if builder, exists := behaviorTreeBuilders[unitType]; exists {
+		return builder()
+	}
+	return newMeleeTree()
+}
+
+// newMeleeTree joins the group's focus target or attacks the best target, closing in as needed
+func newMeleeTree() BTNode {
+	return newCombatTree(NewSelector(btFocusFire(), btSelectTarget()), btMeleeCombat())
+}
+
+// newGuardTree is a melee tree whose members protect their leader before joining focus fire
+func newGuardTree() BTNode {
+	return newCombatTree(NewSelector(btProtectLeader(), btFocusFire(), btSelectTarget()), btMeleeCombat())
+}
+
+// newRangedTree focuses fire on the group's target and kites enemies that get too close
+func newRangedTree() BTNode {
+	return newCombatTree(NewSelector(btFocusFire(), btSelectTarget()), btRangedCombat())
+}
+
+// newCatapultTree bombards hostile structures from afar and shoots at troops once they are down
+func newCatapultTree() BTNode {
+	return newSiegeTree(btRangedCombat())
+}
+
+// newRamTree batters hostile structures and fights in melee once they are down
+func newRamTree() BTNode {
+	return newSiegeTree(btMeleeCombat())
+}
+
+// newSiegeTree goes for the nearest hostile structure before picking a target among the troops
+func newSiegeTree(combat BTNode) BTNode {
+	return NewSelector(
+		btFlank(),
+		btBesiege(),
+		NewSequence(NewSelector(btFocusFire(), btSelectTarget()), combat),
+		btFollowObjective(),
+		btIdle(),
+	)
+}
+
+// newCombatTree picks a target and fights it, following the group objective when there is no target
+// Flanking groups finish their maneuver before engaging
+func newCombatTree(targeting, combat BTNode) BTNode {
+	return NewSelector(
+		btFlank(),
+		NewSequence(targeting, combat),
+		btFollowObjective(),
+		btIdle(),
+	)
+}
+
+// btMeleeCombat attacks in range, otherwise approaches; tired groups wait for the enemy to come
+func btMeleeCombat() BTNode {
+	return NewSelector(
+		NewSequence(btCanAttackTarget(), btAttack()),
+		NewSequence(btFatigued(), btTooFar(), btHold()),
+		NewSequence(btTooFar(), btApproach()),
+		NewSequence(btTargetInRange(), btHold()),
+		btApproach(),
+	)
+}
+
+// btRangedCombat attacks in range and keeps the preferred distance; tired groups stop kiting
+func btRangedCombat() BTNode {
+	return NewSelector(
+		NewSequence(btCanAttackTarget(), btAttack()),
+		NewSequence(btFatigued(), btTargetInRange(), btHold()),
+		NewSequence(btTooFar(), btApproach()),
+		NewSequence(btTooClose(), btRetreat()),
+		NewSequence(btTargetInRange(), btHold()),
+		btApproach(),
+	)
+}
+
+// effectiveTargetDistance returns the distance to the target between collision edges
+func effectiveTargetDistance(ctx *BTContext) float64 {
+	target := ctx.AI.TargetEnemy
+	distance := ctx.Unit.Position.Distance(target.Position)
+	return distance - ctx.Unit.GetCollisionRadius() - target.GetCollisionRadius()
+}
+
+// isValidTarget reports whether an enemy can still be engaged
+func isValidTarget(unit, enemy *Unit) bool {
+	return enemy != nil && enemy.IsAlive && !enemy.IsRetreating && unit.CanHit(enemy) &&
+		unit.Position.Distance(enemy.Position) <= unit.GetSightRange()
+}
+
+// Targeting
+
+func btSelectTarget() BTNode {
+	return NewAction("目標選択", func(ctx *BTContext) BTStatus {
+		ctx.AI.selectTarget(ctx.Unit, ctx.Enemies)
+		if ctx.AI.TargetEnemy == nil {
+			return BTFailure
+		}
+		return BTSuccess
+	})
+}
+
+func btProtectLeader() BTNode {
+	return NewAction("リーダー護衛", func(ctx *BTContext) BTStatus {
+		leader := ctx.AI.Leader
+		if leader == nil || !leader.IsAlive {
+			return BTFailure
+		}
+		
+		// Engage the enemy closest to the leader
+		var threat *Unit
+		minDistance := protectLeaderRadius
+		for _, enemy := range ctx.Enemies {
+			if !enemy.IsAlive || enemy.IsRetreating {
+				continue
+			}
+			if distance := leader.Position.Distance(enemy.Position); distance <= minDistance {
+				threat = enemy
+				minDistance = distance
+			}
+		}
+		
+		if threat == nil {
+			return BTFailure
+		}
+		ctx.AI.TargetEnemy = threat
+		return BTSuccess
+	})
+}
+
+// btFocusFire targets the enemy the leader designated when it is within reach
+func btFocusFire() BTNode {
+	return NewAction("集中攻撃", func(ctx *BTContext) BTStatus {
+		target := ctx.AI.FocusTarget
+		if !isValidTarget(ctx.Unit, target) {
+			return BTFailure
+		}
+		if ctx.AI.Objective != nil && !ctx.AI.Objective.allowsEnemy(target) {
+			return BTFailure
+		}
+		
+		distance := ctx.Unit.Position.Distance(target.Position) - ctx.Unit.GetCollisionRadius() - target.GetCollisionRadius()
+		if distance > ctx.Unit.Range+focusFireReach {
+			return BTFailure
+		}
+		ctx.AI.TargetEnemy = target
+		return BTSuccess
+	})
+}
+
+// Conditions
+
+func btCanAttackTarget() BTNode {
+	return NewCondition("攻撃可能", func(ctx *BTContext) bool {
+		return effectiveTargetDistance(ctx) <= ctx.Unit.Range && ctx.Unit.CanAttack()
+	})
+}
+
+func btTargetInRange() BTNode {
+	return NewCondition("射程内", func(ctx *BTContext) bool {
+		return effectiveTargetDistance(ctx) <= ctx.Unit.Range
+	})
+}
+
+func btFatigued() BTNode {
+	return NewCondition("疲労", func(ctx *BTContext) bool {
+		return ctx.AI.Fatigued
+	})
+}
+
+func btTooFar() BTNode {
+	return NewCondition("遠すぎる", func(ctx *BTContext) bool {
+		return effectiveTargetDistance(ctx) > ctx.AI.PreferredRange*1.2
+	})
+}
+
+func btTooClose() BTNode {
+	return NewCondition("近すぎる", func(ctx *BTContext) bool {
+		return effectiveTargetDistance(ctx) < ctx.AI.PreferredRange*0.8
+	})
+}
+
+// Actions
+
+func btAttack() BTNode {
+	return NewAction("攻撃", func(ctx *BTContext) BTStatus {
+		// 攻撃は BattleManager.processCombat で自動実行される
+		ctx.AI.CurrentAction = AIActionAttack
+		return BTSuccess
+	})
+}
+
+func btApproach() BTNode {
+	return NewAction("接近", func(ctx *BTContext) BTStatus {
+		ctx.AI.CurrentAction = AIActionApproach
+		ctx.AI.moveTowardsTarget(ctx.Unit, 1.0)
+		return BTRunning
+	})
+}
+
+func btRetreat() BTNode {
+	return NewAction("後退", func(ctx *BTContext) BTStatus {
+		ctx.AI.CurrentAction = AIActionRetreat
+		ctx.AI.moveAwayFromTarget(ctx.Unit, 1.0)
+		return BTRunning
+	})
+}
+
+func btHold() BTNode {
+	return NewAction("位置保持", func(ctx *BTContext) BTStatus {
+		ctx.AI.CurrentAction = AIActionHold
+		ctx.Unit.Target = ctx.Unit.Position
+		return BTSuccess
+	})
+}
+
+// btBesiege brings a siege engine within reach of its target structure; the attack itself
+// starts in BattleManager.processCombat
+func btBesiege() BTNode {
+	return NewAction("攻城", func(ctx *BTContext) BTStatus {
+		structure := ctx.AI.SiegeTarget
+		if structure == nil || structure.IsDestroyed {
+			return BTFailure
+		}
+		
+		ctx.AI.TargetEnemy = nil
+		if structure.DistanceTo(ctx.Unit.Position) <= ctx.Unit.Range+ctx.Unit.GetCollisionRadius() {
+			ctx.AI.CurrentAction = AIActionAttack
+			ctx.Unit.Target = ctx.Unit.Position
+			return BTRunning
+		}
+		
+		// Stop at the preferred distance in front of the closest part of the structure
+		point := structure.ClosestPoint(ctx.Unit.Position)
+		away := ctx.Unit.Position.Sub(point).Normalize()
+		ctx.AI.CurrentAction = AIActionApproach
+		ctx.Unit.MoveTo(point.Add(away.Mul(ctx.AI.PreferredRange)))
+		return BTRunning
+	})
+}
+
+func btFlank() BTNode {
+	return NewAction("側面移動", func(ctx *BTContext) BTStatus {
+		objective := ctx.AI.Objective
+		if objective == nil || (objective.Type != GroupObjectiveFlank && objective.Type != GroupObjectiveAdvance) || objective.Reached {
+			return BTFailure
+		}
+		
+		// Fight back when caught on the way
+		for _, enemy := range ctx.Enemies {
+			if enemy.IsAlive && !enemy.IsRetreating && ctx.Unit.Position.Distance(enemy.Position) <= ctx.Unit.Range {
+				return BTFailure
+			}
+		}
+		
+		// The leader leads the maneuver; members keep formation around it
+		if ctx.AI.Leader == nil {
+			if ctx.Unit.Position.Distance(objective.Target) <= flankArrivalDistance {
+				objective.Reached = true
+				return BTFailure
+			}
+			ctx.Unit.MoveTo(objective.Target)
+		}
+		ctx.AI.CurrentAction = AIActionApproach
+		ctx.AI.TargetEnemy = nil
+		return BTRunning
+	})
+}
+
+func btFollowObjective() BTNode {
+	return NewAction("部隊目標", func(ctx *BTContext) BTStatus {
+		objective := ctx.AI.Objective
+		if objective == nil {
+			return BTFailure
+		}
+		
+		// Only the leader moves; members keep formation around it
+		if ctx.AI.Leader == nil && ctx.Unit.Position.Distance(objective.Target) > orderArrivalDistance {
+			ctx.AI.CurrentAction = AIActionApproach
+			ctx.Unit.MoveTo(objective.Target)
+		} else {
+			ctx.AI.CurrentAction = AIActionHold
+		}
+		ctx.AI.TargetEnemy = nil
+		return BTRunning
+	})
+}
+
+func btIdle() BTNode {
+	return NewAction("待機", func(ctx *BTContext) BTStatus {
+		ctx.AI.CurrentAction = AIActionIdle
+		ctx.AI.TargetEnemy = nil
+		ctx.Unit.Target = ctx.Unit.Position
+		return BTSuccess
+	})
+}
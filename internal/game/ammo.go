@@ -0,0 +1,98 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Ammunition tuning
+const (
+	resupplyRate        = 5.0  // 補給地点で1秒に補充する矢弾の数
+	meleeFallbackRange  = 15.0 // 矢弾が尽きた後の射程（短剣、1.5m）
+	meleeFallbackAttack = 0.5  // 矢弾が尽きた後の攻撃力の倍率
+)
+
+// HasAmmo reports whether the unit can still shoot (or fights in melee without ammunition)
+func (u *Unit) HasAmmo() bool {
+	return u.MaxAmmo == 0 || u.Ammo > 0 || u.MeleeFallback
+}
+
+// switchToMelee makes a unit out of ammunition fight weakly at close range for the rest of the battle
+func (u *Unit) switchToMelee() {
+	u.leaveGarrison()
+	u.MeleeFallback = true
+	u.Range = meleeFallbackRange
+	u.AttackPower = max(1, int(float64(u.AttackPower)*meleeFallbackAttack))
+	if u.AI != nil {
+		u.AI.Tree = newMeleeTree()
+		if u.IsSiegeEngine() {
+			u.AI.Tree = newRamTree()
+		}
+		u.AI.PreferredRange = meleeFallbackRange
+	}
+}
+
+// restock refills the unit's ammunition at a supply point and reports whether it is full
+func (u *Unit) restock(deltaTime float64) bool {
+	u.resupplied += resupplyRate * deltaTime
+	for u.resupplied >= 1 && u.Ammo < u.MaxAmmo {
+		u.Ammo++
+		u.resupplied--
+	}
+	if u.Ammo < u.MaxAmmo {
+		return false
+	}
+	u.resupplied = 0
+	return true
+}
+
+// getSupplyPoint returns the army's supply point closest to the position, or nil
+func (bm *BattleManager) getSupplyPoint(armyID int, position gamemath.Vector2D) *data.SupplyPointConfig {
+	var closest *data.SupplyPointConfig
+	for i := range bm.Stage.SupplyPoints {
+		point := &bm.Stage.SupplyPoints[i]
+		if point.ArmyID() != armyID {
+			continue
+		}
+		if closest == nil || position.Distance(point.Center()) < position.Distance(closest.Center()) {
+			closest = point
+		}
+	}
+	return closest
+}
+
+// updateAmmo sends units out of ammunition back to their supply point, or into melee when there is none
+// Runs after the AI so the trip to the supply point overrides its orders
+func (bm *BattleManager) updateAmmo(deltaTime float64) {
+	for _, army := range bm.Armies {
+		for _, unit := range army.GetAliveUnits() {
+			if unit.MaxAmmo == 0 || unit.MeleeFallback {
+				continue
+			}
+			
+			if !unit.Resupplying {
+				if unit.Ammo > 0 {
+					continue
+				}
+				if bm.getSupplyPoint(army.ID, unit.Position) == nil {
+					unit.switchToMelee()
+					continue
+				}
+				unit.leaveGarrison()
+				unit.Resupplying = true
+			}
+			
+			// Head for the nearest supply point and restock inside it
+			point := bm.getSupplyPoint(army.ID, unit.Position)
+			center := point.Center()
+			if unit.Position.Distance(center) > point.GetRadius() {
+				unit.MoveTo(center)
+				continue
+			}
+			unit.MoveTo(unit.Position)
+			if unit.restock(deltaTime) {
+				unit.Resupplying = false
+			}
+		}
+	}
+}
@@ -1,11 +1,33 @@
 package game
 
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
 // Army represents a collection of groups
 type Army struct {
 	ID     int
 	Name   string
 	Groups []*Group
 	Side   int // 0: A軍, 1: B軍
+	
+	// Deployment: the front faces the enemy and retreats go the other way
+	Facing   gamemath.Vector2D // 布陣の正面（単位ベクトル）
+	Approach gamemath.Vector2D // 開戦時に前進する向き（ゼロ: 敵へ直行）
+	
+	// AllianceMask has bit N set when the army is allied with army N (always including itself)
+	AllianceMask uint32
+	
+	// Passive doctrine chosen in setup (nil: none)
+	Doctrine *data.DoctrineConfig
+	
+	// Morale state
+	IsRouted    bool         // 士気崩壊で総崩れ
+	fallenUnits map[int]bool // 士気処理済みの戦死ユニット
+	
+	// Orders take time to pass from the leaders to their members
+	CommandDelay bool
 }
 
 // NewArmy creates a new army
@@ -15,11 +37,22 @@ func NewArmy(id int, name string, side int) *Army {
 		Name:   name,
 		Groups: make([]*Group, 0),
 		Side:   side,
+		
+		AllianceMask: 1 << uint(id),
 	}
 }
 
+// IsAlliedWith reports whether the army is allied with the given army
+func (a *Army) IsAlliedWith(armyID int) bool {
+	if armyID < 0 || armyID >= 32 {
+		return false
+	}
+	return a.AllianceMask&(1<<uint(armyID)) != 0
+}
+
 // AddGroup adds a group to the army
 func (a *Army) AddGroup(group *Group) {
+	group.commandDelay = a.CommandDelay
 	a.Groups = append(a.Groups, group)
 }
 
@@ -56,8 +89,9 @@ func (a *Army) GetAliveCount() int {
 }
 
 // GetTotalHealth returns the total health percentage of the army
+// Summoned minions come and go and are left out
 func (a *Army) GetTotalHealth() float64 {
-	units := a.GetAllUnits()
+	units := a.getEnlistedUnits()
 	if len(units) == 0 {
 		return 0
 	}
@@ -70,6 +104,17 @@ func (a *Army) GetTotalHealth() float64 {
 	return totalHealth / float64(len(units))
 }
 
+// getEnlistedUnits returns all units except summoned minions
+func (a *Army) getEnlistedUnits() []*Unit {
+	var units []*Unit
+	for _, unit := range a.GetAllUnits() {
+		if !unit.IsSummoned() {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
 // IsDefeated returns true if the army is completely defeated
 func (a *Army) IsDefeated() bool {
 	for _, group := range a.Groups {
@@ -90,3 +135,11 @@ func (a *Army) GetActiveGroups() []*Group {
 	}
 	return activeGroups
 }
+
+// GetCommander returns the army commander (leader of the first group)
+func (a *Army) GetCommander() *Unit {
+	if len(a.Groups) == 0 || a.Groups[0] == nil {
+		return nil
+	}
+	return a.Groups[0].Leader
+}
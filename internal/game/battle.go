@@ -3,73 +3,358 @@ package game
 import (
 	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/shirou/tinygocha/internal/data"
 	gamemath "github.com/shirou/tinygocha/internal/math"
 )
 
+// Battle results stored in Winner besides the winning army ID
+const (
+	WinnerUndecided = -1
+	WinnerDraw      = -2
+)
+
+// maxFrameTime caps the real seconds one frame advances the battle by
+const maxFrameTime = 0.1
+
 // BattleManager manages the battle state and logic
 type BattleManager struct {
-	ArmyA        *Army
-	ArmyB        *Army
+	Armies       []*Army
 	Stage        data.StageConfig
 	TerrainData  data.TerrainConfig
 	BattleTime   float64
+	Ticks        int     // 進めたシミュレーションのティック数
+	TimeScale    float64 // 戦闘時間が実時間の何倍で進むか（0: 止まっている）
 	TimeLimit    float64
 	IsActive     bool
-	Winner       int // -1: 未決定, 0: A軍勝利, 1: B軍勝利, 2: 引き分け
+	Winner       int // WinnerUndecided, WinnerDraw or the ID of the winning army
+	
+	// Stage-specific victory conditions
+	Objectives []*Objective
+	
+	// Territory control
+	CapturePoints []*CapturePoint
+	Scores        []float64 // 拠点確保による戦果（軍勢ごと）
+	
+	// Scripted reinforcements
+	Reinforcements []*Reinforcement
+	Triggers       []*Trigger
+	Announcements  []Announcement
+	
+	// Player orders waiting for the tactical pause to end
+	QueuedOrders []Order
+	
+	// Neutral creatures hostile to every army
+	Neutrals     *Army
+	NeutralCamps []*NeutralCamp
+	
+	// Optional order budget (nil: orders are free)
+	CommandPoints *CommandPoints
+	
+	// Optional realism rule: orders take time to pass from leaders to members
+	CommandDelay bool
+	
+	// Group-level tactical AI, one commander per army
+	Commanders []*ArmyCommander
+	
+	// Enemy and friendly strength per army, indexed by army ID
+	ThreatMaps []*ThreatMap
 	
 	// Unit ID counter
 	nextUnitID int
+	
+	// Stage army definitions, indexed by army ID
+	armyConfigs   []data.StageArmyConfig
+	armyGroups    map[int][]PresetGroup    // Groups fielded instead of the preset's
+	armyEquipment map[int][]data.Equipment // Leaders' equipment of those groups, in group order
+	handicaps     map[int]Handicap         // Stat multipliers chosen in setup
+	
+	// Data used for mid-battle spawns
+	dataManager *data.DataManager
+	
+	// Random source for spawn placement, seeded for replays
+	rng    *rand.Rand
+	random *countingSource // rng の乱数源（引いた回数ごと巻き戻す）
+	
+	// Rule changes selected for this battle
+	Mutators []*Mutator
+	
+	// Movement cost of the stage's terrain areas
+	Terrain *TerrainGrid
+	
+	// Gates, walls and towers placed by the stage
+	Structures []*Structure
+	
+	// Spike pits and caltrops laid before the battle
+	Traps    []*Trap
+	trapKits map[int]TrapKit // 軍勢ごとに仕掛けられる罠の数
+	
+	// Spells flying towards their targets
+	Projectiles []*SpellProjectile
+	
+	// Where the wind blows to and how fast (px/秒), zero without wind
+	Wind gamemath.Vector2D
+	
+	// Fog brought in by a stage trigger, hiding enemies out of sight as the fog mutator does
+	Foggy bool
+	
+	// Lua running the stage's script and the unit types' AI scripts (nil until a script is needed)
+	scriptEngine *scriptEngine
+	
+	// Shared flow fields keyed by destination cell
+	flowFields map[int]*FlowField
+	
+	// Hits, orders and unit state changes in battle time order
+	Events []BattleEvent
+	
+	// Where units died, took damage and moved, for the result screen
+	Heatmap *BattleHeatmap
 }
 
 // NewBattleManager creates a new battle manager
 func NewBattleManager(stage data.StageConfig, terrainData data.TerrainConfig) *BattleManager {
-	return &BattleManager{
-		ArmyA:       NewArmy(0, "軍勢A", 0),
-		ArmyB:       NewArmy(1, "軍勢B", 1),
-		Stage:       stage,
-		TerrainData: terrainData,
-		BattleTime:  0.0,
-		TimeLimit:   stage.TimeLimit,
-		IsActive:    false,
-		Winner:      -1,
-		nextUnitID:  1,
+	armyConfigs := stage.GetArmyConfigs()
+	
+	bm := &BattleManager{
+		Armies:         make([]*Army, 0, len(armyConfigs)),
+		Stage:          stage,
+		TerrainData:    terrainData,
+		BattleTime:     0.0,
+		TimeLimit:      stage.TimeLimit,
+		TimeScale:      1.0,
+		IsActive:       false,
+		Winner:         WinnerUndecided,
+		Objectives:     NewObjectives(stage.VictoryConditions),
+		CapturePoints:  NewCapturePoints(stage.CapturePoints),
+		Scores:         make([]float64, len(armyConfigs)),
+		Reinforcements: NewReinforcements(stage.Reinforcements),
+		Triggers:       NewTriggers(stage.Triggers),
+		Neutrals:       NewArmy(NeutralArmyID, "中立勢力", NeutralArmyID),
+		NeutralCamps:   NewNeutralCamps(stage.NeutralCamps),
+		nextUnitID:     1,
+		armyConfigs:    armyConfigs,
+		Terrain:        NewTerrainGrid(float64(stage.Width), float64(stage.Height), stage.TerrainAreas, stage.Obstacles),
+		Structures:     NewStructures(stage.Structures),
+		flowFields:     make(map[int]*FlowField),
+		trapKits:       make(map[int]TrapKit),
+		Heatmap:        NewBattleHeatmap(float64(stage.Width), float64(stage.Height)),
+	}
+	bm.rng, bm.random = newRandom(time.Now().UnixNano())
+	
+	for i, config := range armyConfigs {
+		name := config.Name
+		if name == "" {
+			name = "軍勢" + data.ArmyLabel(i)
+		}
+		bm.Armies = append(bm.Armies, NewArmy(i, name, i))
+		bm.Commanders = append(bm.Commanders, NewArmyCommander(i))
+		bm.ThreatMaps = append(bm.ThreatMaps, NewThreatMap(i, float64(stage.Width), float64(stage.Height)))
+	}
+	
+	// Alliances are mutual
+	for i, config := range armyConfigs {
+		for _, label := range config.Allies {
+			ally := data.ArmyIndex(label)
+			if ally < 0 || ally >= len(bm.Armies) {
+				fmt.Printf("Warning: unknown ally '%s' for army %d\n", label, i)
+				continue
+			}
+			bm.Armies[i].AllianceMask |= 1 << uint(ally)
+			bm.Armies[ally].AllianceMask |= 1 << uint(i)
+		}
+	}
+	
+	// Armies face the stage's direction or their nearest enemy
+	for i, army := range bm.Armies {
+		army.Facing = bm.deploymentFacing(i)
+		if approach := armyConfigs[i].Approach; approach.IsSet() {
+			army.Approach = approach.ToVector2D()
+		}
+	}
+	
+	// Structures block their cells; gates open for their owner's alliance
+	for _, structure := range bm.Structures {
+		if owner := bm.GetArmy(structure.ArmyID); owner != nil {
+			structure.Alliance = owner.AllianceMask
+		}
+	}
+	bm.Terrain.Structures = bm.Structures
+	bm.Terrain.refreshMovement()
+	
+	return bm
+}
+
+// SetRandomSeed makes spawn placement repeatable; call it before CreateArmies
+func (bm *BattleManager) SetRandomSeed(seed int64) {
+	bm.rng, bm.random = newRandom(seed)
+}
+
+// GetArmy returns the army with the given ID, or nil if there is none
+func (bm *BattleManager) GetArmy(armyID int) *Army {
+	if armyID < 0 || armyID >= len(bm.Armies) {
+		return nil
+	}
+	return bm.Armies[armyID]
+}
+
+// getWorldSize returns the stage size in pixels
+func (bm *BattleManager) getWorldSize() (float64, float64) {
+	width, height := float64(bm.Stage.Width), float64(bm.Stage.Height)
+	if width <= 0 {
+		width = defaultWorldSize
+	}
+	if height <= 0 {
+		height = defaultWorldSize
+	}
+	return width, height
+}
+
+// AreAllied reports whether two armies fight on the same side
+func (bm *BattleManager) AreAllied(armyID1, armyID2 int) bool {
+	army := bm.GetArmy(armyID1)
+	return army != nil && army.IsAlliedWith(armyID2)
+}
+
+// GetEnemyUnits returns the alive units hostile to the given army, including neutral creatures
+// Hidden scouts are left out until the army spots them
+func (bm *BattleManager) GetEnemyUnits(armyID int) []*Unit {
+	var enemies []*Unit
+	for _, army := range bm.Armies {
+		if !bm.AreAllied(armyID, army.ID) {
+			enemies = append(enemies, army.GetAliveUnits()...)
+		}
+	}
+	if armyID != NeutralArmyID {
+		enemies = append(enemies, bm.Neutrals.GetAliveUnits()...)
+	}
+	
+	spotted := enemies[:0]
+	for _, enemy := range enemies {
+		if bm.isDetected(enemy, armyID) {
+			spotted = append(spotted, enemy)
+		}
 	}
+	return spotted
+}
+
+// getAllAliveUnits returns the alive units of every army and the neutral creatures
+func (bm *BattleManager) getAllAliveUnits() []*Unit {
+	var units []*Unit
+	for _, army := range bm.Armies {
+		units = append(units, army.GetAliveUnits()...)
+	}
+	return append(units, bm.Neutrals.GetAliveUnits()...)
+}
+
+// FindUnit returns the unit of any army or the neutral creatures with the ID, alive or not, or nil
+func (bm *BattleManager) FindUnit(id int) *Unit {
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, unit := range army.GetAllUnits() {
+			if unit.ID == id {
+				return unit
+			}
+		}
+	}
+	return nil
+}
+
+// CreateArmies creates every stage army, using the stage preset or the one chosen for its side:
+// the first army is the player's and fields playerPreset, the others field enemyPreset (empty: playerPreset)
+func (bm *BattleManager) CreateArmies(playerPreset, enemyPreset string, dataManager *data.DataManager) error {
+	if enemyPreset == "" {
+		enemyPreset = playerPreset
+	}
+	
+	var firstErr error
+	for i, config := range bm.armyConfigs {
+		preset := config.Preset
+		if preset == "" && i == 0 {
+			preset = playerPreset
+		} else if preset == "" {
+			preset = enemyPreset
+		}
+		
+		// A random preset is drawn from the battle's seed, so replays and co-op pick the same one
+		if preset == RandomPreset {
+			presets := ListPresets(dataManager)
+			preset = presets[bm.rng.Intn(len(presets))]
+		}
+		
+		if err := bm.CreatePresetArmy(i, preset, dataManager); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	
+	bm.spawnNeutralCamps(dataManager)
+	bm.spawnConvoys(dataManager)
+	return firstErr
+}
+
+// SetArmyPreset makes the army field the preset instead of the stage's or the default one; call it before CreateArmies
+func (bm *BattleManager) SetArmyPreset(armyID int, presetType string) {
+	if armyID < 0 || armyID >= len(bm.armyConfigs) {
+		return
+	}
+	
+	// The configs are shared with the loaded stage
+	bm.armyConfigs = slices.Clone(bm.armyConfigs)
+	bm.armyConfigs[armyID].Preset = presetType
+}
+
+// SetArmyGroups makes the army field the groups instead of any preset's; call it before CreateArmies
+func (bm *BattleManager) SetArmyGroups(armyID int, groups []data.ReinforcementGroupConfig) {
+	if armyID < 0 || armyID >= len(bm.armyConfigs) {
+		return
+	}
+	
+	if bm.armyGroups == nil {
+		bm.armyGroups = make(map[int][]PresetGroup)
+		bm.armyEquipment = make(map[int][]data.Equipment)
+	}
+	presetGroups := make([]PresetGroup, 0, len(groups))
+	equipment := make([]data.Equipment, 0, len(groups))
+	for _, group := range groups {
+		presetGroups = append(presetGroups, PresetGroup{group.Leader, group.Member, group.Count})
+		equipment = append(equipment, group.Equipment)
+	}
+	bm.armyGroups[armyID] = presetGroups
+	bm.armyEquipment[armyID] = equipment
 }
 
 // CreatePresetArmy creates a preset army configuration
 func (bm *BattleManager) CreatePresetArmy(armyID int, presetType string, dataManager *data.DataManager) error {
-	var army *Army
-	if armyID == 0 {
-		army = bm.ArmyA
-	} else {
-		army = bm.ArmyB
+	army := bm.GetArmy(armyID)
+	if army == nil {
+		return fmt.Errorf("army %d is not part of stage %s", armyID, bm.Stage.Name)
 	}
 	
 	fmt.Printf("Creating preset army %d (%s)\n", armyID, presetType)
 	
+	// Keep data for reinforcements spawned mid-battle
+	bm.dataManager = dataManager
+	
 	// Get deployment points
-	var deploymentPoints []gamemath.Vector2D
-	if armyID == 0 {
-		deploymentPoints = bm.Stage.GetDeploymentPointsA()
-	} else {
-		deploymentPoints = bm.Stage.GetDeploymentPointsB()
-	}
+	deploymentPoints := bm.armyConfigs[armyID].GetDeploymentPoints()
 	
 	fmt.Printf("Deployment points for army %d: %v\n", armyID, deploymentPoints)
 	
-	// Create groups based on preset type
-	switch presetType {
-	case "バランス型":
-		bm.createBalancedArmy(army, deploymentPoints, dataManager)
-	case "攻撃重視":
-		bm.createOffensiveArmy(army, deploymentPoints, dataManager)
-	case "防御重視":
-		bm.createDefensiveArmy(army, deploymentPoints, dataManager)
-	default:
-		bm.createBalancedArmy(army, deploymentPoints, dataManager)
+	// Create groups based on preset type, unless the army was given its own
+	groups, ok := bm.armyGroups[armyID]
+	if !ok && presetType == RandomArmyPreset {
+		groups = randomArmyGroups(dataManager.Recruitment, bm.rng, len(deploymentPoints))
+		fmt.Printf("Random army %d: %v\n", armyID, groups)
+	}
+	if !ok && len(groups) == 0 {
+		groups = GetPresetGroups(presetType, dataManager)
 	}
+	bm.createPresetGroups(army, groups, deploymentPoints, dataManager)
+	
+	// The army's AI lays the preset's traps; the player may lay them again before the battle
+	bm.trapKits[armyID] = GetPresetTraps(presetType, dataManager)
+	bm.layTraps(armyID)
 	
 	// デバッグ: 作成されたユニット数
 	allUnits := army.GetAllUnits()
@@ -82,127 +367,144 @@ func (bm *BattleManager) CreatePresetArmy(armyID int, presetType string, dataMan
 	return nil
 }
 
-// createBalancedArmy creates a balanced army composition
-func (bm *BattleManager) createBalancedArmy(army *Army, deploymentPoints []gamemath.Vector2D, dataManager *data.DataManager) {
-	groupConfigs := []struct {
-		leaderType string
-		memberType string
-		count      int
-	}{
-		{"infantry", "infantry", 4},
-		{"archer", "archer", 3},
-		{"mage", "infantry", 2},
-	}
-	
-	for i, config := range groupConfigs {
-		if i >= len(deploymentPoints) {
-			break
+// PresetGroup describes one group of a preset army
+type PresetGroup struct {
+	LeaderType string
+	MemberType string
+	Count      int
+}
+
+// PresetNames lists the built-in preset armies in menu order
+var PresetNames = []string{"バランス型", "攻撃重視", "防御重視", "攻城型", "召喚型"}
+
+// ListPresets returns the preset armies in menu order: the built-in ones, those added by mods, then the random army
+func ListPresets(dataManager *data.DataManager) []string {
+	presets := slices.Clone(PresetNames)
+	if dataManager != nil {
+		for _, name := range dataManager.ListPresets() {
+			if !slices.Contains(presets, name) {
+				presets = append(presets, name)
+			}
 		}
-		
-		group := bm.createGroup(army.ID, config.leaderType, config.memberType, config.count, deploymentPoints[i], dataManager)
-		army.AddGroup(group)
 	}
+	return append(presets, RandomArmyPreset)
 }
 
-// createOffensiveArmy creates an offensive army composition
-func (bm *BattleManager) createOffensiveArmy(army *Army, deploymentPoints []gamemath.Vector2D, dataManager *data.DataManager) {
-	groupConfigs := []struct {
-		leaderType string
-		memberType string
-		count      int
-	}{
-		{"cavalry", "cavalry", 2},
-		{"archer", "archer", 4},
-		{"infantry", "infantry", 3},
+// RandomPreset makes CreateArmies pick one of ListPresets for the army at random
+const RandomPreset = "おまかせ"
+
+// GetPresetGroups returns the group composition of a preset army, a mod's taking the place of a built-in one
+// Unknown presets fall back to バランス型
+func GetPresetGroups(presetType string, dataManager *data.DataManager) []PresetGroup {
+	if preset, exists := modPreset(presetType, dataManager); exists {
+		groups := make([]PresetGroup, 0, len(preset.Groups))
+		for _, group := range preset.Groups {
+			groups = append(groups, PresetGroup{group.Leader, group.Member, group.Count})
+		}
+		return groups
 	}
 	
-	for i, config := range groupConfigs {
-		if i >= len(deploymentPoints) {
-			break
+	switch presetType {
+	case "攻撃重視":
+		return []PresetGroup{
+			{"cavalry", "cavalry", 2},
+			{"archer", "archer", 4},
+			{"infantry", "infantry", 3},
+			{"griffin", "griffin", 2},
+		}
+	case "防御重視":
+		return []PresetGroup{
+			{"heavy_infantry", "heavy_infantry", 3},
+			{"infantry", "archer", 4},
+			{"mage", "mage", 2},
+			{"scout", "scout", 2},
+		}
+	case "攻城型":
+		return []PresetGroup{
+			{"infantry", "infantry", 4},
+			{"catapult", "catapult", 2},
+			{"ram", "ram", 1},
+			{"archer", "archer", 3},
+		}
+	case "召喚型":
+		return []PresetGroup{
+			{"infantry", "infantry", 4},
+			{"summoner", "summoner", 2},
+			{"archer", "archer", 3},
+		}
+	default: // バランス型
+		return []PresetGroup{
+			{"infantry", "infantry", 4},
+			{"archer", "archer", 3},
+			{"mage", "infantry", 2},
 		}
-		
-		group := bm.createGroup(army.ID, config.leaderType, config.memberType, config.count, deploymentPoints[i], dataManager)
-		army.AddGroup(group)
 	}
 }
 
-// createDefensiveArmy creates a defensive army composition
-func (bm *BattleManager) createDefensiveArmy(army *Army, deploymentPoints []gamemath.Vector2D, dataManager *data.DataManager) {
-	groupConfigs := []struct {
-		leaderType string
-		memberType string
-		count      int
-	}{
-		{"heavy_infantry", "heavy_infantry", 3},
-		{"infantry", "archer", 4},
-		{"mage", "mage", 2},
+// modPreset returns the preset a mod adds or overrides under the name
+func modPreset(presetType string, dataManager *data.DataManager) (data.PresetConfig, bool) {
+	if dataManager == nil {
+		return data.PresetConfig{}, false
 	}
-	
-	for i, config := range groupConfigs {
+	return dataManager.Presets.GetPresetConfig(presetType)
+}
+
+// createPresetGroups creates preset groups at the deployment points, one group per point
+func (bm *BattleManager) createPresetGroups(army *Army, groups []PresetGroup, deploymentPoints []gamemath.Vector2D, dataManager *data.DataManager) {
+	for i, config := range groups {
 		if i >= len(deploymentPoints) {
 			break
 		}
 		
-		group := bm.createGroup(army.ID, config.leaderType, config.memberType, config.count, deploymentPoints[i], dataManager)
+		group := bm.createGroup(army.ID, config.LeaderType, config.MemberType, config.Count, deploymentPoints[i], dataManager)
+		if equipment := bm.armyEquipment[army.ID]; i < len(equipment) {
+			bm.equip(group, equipment[i], dataManager)
+		}
 		army.AddGroup(group)
 	}
 }
 
 // createGroup creates a group with specified configuration
 func (bm *BattleManager) createGroup(armyID int, leaderType, memberType string, memberCount int, position gamemath.Vector2D, dataManager *data.DataManager) *Group {
-	// Get unit configurations
-	leaderConfig, err := dataManager.GetUnitConfig(leaderType)
+	// Get unit configurations, with unit types swapped by mutators
+	leaderType, leaderConfig, err := bm.mutateUnitType(leaderType, dataManager)
 	if err != nil {
 		fmt.Printf("Error getting leader config for %s: %v\n", leaderType, err)
 		return nil
 	}
 	
-	memberConfig, err := dataManager.GetUnitConfig(memberType)
+	memberType, memberConfig, err := bm.mutateUnitType(memberType, dataManager)
 	if err != nil {
 		fmt.Printf("Error getting member config for %s: %v\n", memberType, err)
 		return nil
 	}
 	
+	// Handicapped armies field more or fewer members
+	memberCount = bm.handicapCount(armyID, memberCount)
+	
 	fmt.Printf("Creating group: Leader=%s (HP=%d), Members=%s (HP=%d), Count=%d\n", 
 		leaderType, leaderConfig.HP, memberType, memberConfig.HP, memberCount)
 	
 	// Create leader
-	leader := bm.createUnit(UnitType(leaderType), UnitTypeConfig{
-		Name:       leaderConfig.Name,
-		HP:         leaderConfig.HP,
-		Attack:     leaderConfig.Attack,
-		Defense:    leaderConfig.Defense,
-		Speed:      leaderConfig.Speed,
-		Range:      leaderConfig.Range,
-		MagicPower: leaderConfig.MagicPower,
-		Size:       leaderConfig.Size,  // サイズフィールドを追加
-	}, true, armyID)
+	leader := bm.createUnit(UnitType(leaderType), newUnitTypeConfig(leaderConfig, dataManager), true, armyID)
 	leader.Position = position
 	leader.Target = position
 	
 	// Create members
 	var members []*Unit
 	for i := 0; i < memberCount; i++ {
-		member := bm.createUnit(UnitType(memberType), UnitTypeConfig{
-			Name:       memberConfig.Name,
-			HP:         memberConfig.HP,
-			Attack:     memberConfig.Attack,
-			Defense:    memberConfig.Defense,
-			Speed:      memberConfig.Speed,
-			Range:      memberConfig.Range,
-			MagicPower: memberConfig.MagicPower,
-			Size:       memberConfig.Size,  // サイズフィールドを追加
-		}, false, armyID)
+		member := bm.createUnit(UnitType(memberType), newUnitTypeConfig(memberConfig, dataManager), false, armyID)
 		member.Position = position.Add(gamemath.Vector2D{
-			X: float64(rand.Intn(40) - 20),
-			Y: float64(rand.Intn(40) - 20),
+			X: float64(bm.rng.Intn(40) - 20),
+			Y: float64(bm.rng.Intn(40) - 20),
 		})
 		member.Target = member.Position
 		members = append(members, member)
 	}
 	
 	// Create group
-	group := NewGroup(len(bm.ArmyA.Groups)+len(bm.ArmyB.Groups), armyID, leader, members)
+	group := NewGroup(bm.nextGroupID(), armyID, leader, members)
+	group.Facing = bm.armyFacing(armyID)
 	
 	// Set group IDs for all units
 	leader.GroupID = group.ID
@@ -213,6 +515,15 @@ func (bm *BattleManager) createGroup(armyID int, leaderType, memberType string,
 	return group
 }
 
+// nextGroupID returns the ID of the next group to be created
+func (bm *BattleManager) nextGroupID() int {
+	groupCount := len(bm.Neutrals.Groups)
+	for _, army := range bm.Armies {
+		groupCount += len(army.Groups)
+	}
+	return groupCount
+}
+
 // createUnit creates a new unit with terrain modifiers applied
 func (bm *BattleManager) createUnit(unitType UnitType, config UnitTypeConfig, isLeader bool, armyID int) *Unit {
 	unit := NewUnit(bm.nextUnitID, unitType, config, isLeader, 0, armyID)
@@ -220,35 +531,73 @@ func (bm *BattleManager) createUnit(unitType UnitType, config UnitTypeConfig, is
 	
 	// Apply terrain modifiers
 	bm.applyTerrainModifiers(unit)
+	unit.Terrain = bm.Terrain
+	
+	// Targeting avoids the enemy strongholds on the army's threat map
+	if unit.AI != nil {
+		unit.AI.ThreatMap = bm.GetThreatMap(armyID)
+	}
+	
+	// Scripted unit types ask their script before the behavior tree
+	if unit.AI != nil && config.AIScript != "" {
+		combat := btMeleeCombat()
+		if config.Range > rangedThreatRange {
+			combat = btRangedCombat()
+		}
+		unit.AI.Tree = NewSelector(bm.btScript(config.AIScript, combat), unit.AI.Tree)
+	}
+	
+	// Apply battle mutators
+	bm.mutateUnit(unit)
+	
+	// Apply the army's handicap
+	bm.handicapUnit(unit)
 	
 	return unit
 }
 
 // applyTerrainModifiers applies terrain effects to a unit
 func (bm *BattleManager) applyTerrainModifiers(unit *Unit) {
-	// Apply movement modifier
-	unit.Speed *= bm.TerrainData.MovementModifier
+	// Apply movement modifier; flyers are not slowed by the ground
+	if !unit.Flying {
+		unit.Speed *= bm.TerrainData.MovementModifier
+	}
 	
 	// Apply defense modifier
 	unit.Defense = int(float64(unit.Defense) * bm.TerrainData.DefenseModifier)
 	
-	// Apply unit type specific bonuses
-	switch unit.Type {
-	case UnitTypeInfantry:
-		unit.AttackPower = int(float64(unit.AttackPower) * bm.TerrainData.InfantryBonus)
-	case UnitTypeArcher:
-		unit.AttackPower = int(float64(unit.AttackPower) * bm.TerrainData.ArcherBonus)
-	case UnitTypeMage:
-		unit.AttackPower = int(float64(unit.AttackPower) * bm.TerrainData.MageBonus)
-		unit.MagicPower = int(float64(unit.MagicPower) * bm.TerrainData.MageBonus)
-	}
+	// Apply the unit type's attack bonus to both physical and magic attacks
+	bonus := bm.TerrainData.GetAttackBonus(string(unit.Type))
+	unit.AttackPower = int(float64(unit.AttackPower) * bonus)
+	unit.MagicPower = int(float64(unit.MagicPower) * bonus)
 }
 
 // StartBattle starts the battle
 func (bm *BattleManager) StartBattle() {
 	bm.IsActive = true
 	bm.BattleTime = 0.0
-	bm.Winner = -1
+	bm.Winner = WinnerUndecided
+}
+
+// FrameSeconds returns the real seconds a frame of the given length advances the battle by, capped at
+// maxFrameTime so a stalled frame slows the battle down instead of jumping it ahead
+func FrameSeconds(realSeconds float64) float64 {
+	return min(realSeconds, maxFrameTime)
+}
+
+// ScaledTime returns the battle seconds that pass in a frame of the given real length at the time scale
+// The simulation, replays after a rewind and everything drawn on the battle clock run on it: unit
+// animations and cooldowns, speech bubbles and burning woods alike slow down, stop and speed up with it
+func (bm *BattleManager) ScaledTime(realSeconds float64) float64 {
+	return FrameSeconds(realSeconds) * bm.TimeScale
+}
+
+// Advance runs the battle for a frame of the given real length at the time scale; a stopped battle stays put
+// Co-op and observed battles step by fixed ticks with Update instead
+func (bm *BattleManager) Advance(realSeconds float64) {
+	if step := bm.ScaledTime(realSeconds); step > 0 {
+		bm.Update(step)
+	}
 }
 
 // Update updates the battle state
@@ -259,19 +608,89 @@ func (bm *BattleManager) Update(deltaTime float64) {
 	
 	// Update battle time
 	bm.BattleTime += deltaTime
+	bm.Ticks++
 	
 	// Update armies
-	bm.ArmyA.Update(deltaTime)
-	bm.ArmyB.Update(deltaTime)
+	for _, army := range bm.Armies {
+		army.Update(deltaTime)
+	}
+	bm.Neutrals.Update(deltaTime)
+	
+	// Complete or cancel player orders
+	bm.updateCommandPoints(deltaTime)
+	bm.updateOrders()
+	bm.updateGarrisons()
+	
+	// Tired groups ease up before the AI decides
+	bm.updateFatigue()
+	bm.updateStealth(deltaTime)
+	
+	// Groups pressed from three or more sides lose heart and cover
+	bm.updateEncirclement(deltaTime)
 	
-	// Update AI behaviors
+	// Commanders assign group objectives, then units act on them
+	bm.updateThreatMaps(deltaTime)
+	bm.updateCommanders(deltaTime)
+	bm.updateSiegeTargets()
 	bm.updateAI(deltaTime)
 	
-	// Handle unit collisions
-	bm.handleCollisions()
+	// Neutral creatures guard their camps
+	bm.updateNeutrals(deltaTime)
 	
-	// Process combat
+	// Archers out of ammunition restock or draw their blades
+	bm.updateAmmo(deltaTime)
+	
+	// Steer units apart and keep groups together
+	bm.updateSteering()
+	
+	// Route groups around impassable and slow terrain
+	bm.updateFlowFields()
+	
+	// Channeled spells build up or break off, and stunned units come to
+	bm.updateChannels(deltaTime)
+	
+	// Process combat, then move the spells in flight
 	bm.processCombat()
+	bm.updateProjectiles(deltaTime)
+	
+	// Heavy blows shove their targets back
+	bm.updateKnockback(deltaTime)
+	
+	// Towers shoot at enemies below them
+	bm.updateStructures(deltaTime)
+	
+	// Fires spread through the woods and scorch those caught in them
+	bm.updateFire(deltaTime)
+	
+	// Hidden traps spring under the enemy
+	bm.updateTraps(deltaTime)
+	
+	// Update army morale
+	bm.updateMorale(deltaTime)
+	
+	// Refresh doctrine effects for the next tick
+	bm.updateDoctrines()
+	bm.updateDayNight()
+	
+	// Update territory control
+	bm.updateCapturePoints(deltaTime)
+	
+	// Spawn scripted reinforcements, fire the stage's scripted events and run its script
+	bm.updateReinforcements()
+	bm.updateTriggers()
+	bm.updateScript(deltaTime)
+	
+	// Summoners call up minions; expired minions vanish
+	bm.updateSummons(deltaTime)
+	
+	// Evaluate stage victory conditions
+	bm.updateObjectives(deltaTime)
+	
+	// Log target and state changes
+	bm.updateBattleLog()
+	
+	// Sample movement for the heatmap
+	bm.updateHeatmap(deltaTime)
 	
 	// Check win conditions
 	bm.checkWinConditions()
@@ -279,139 +698,238 @@ func (bm *BattleManager) Update(deltaTime float64) {
 
 // processCombat handles combat between units
 func (bm *BattleManager) processCombat() {
-	unitsA := bm.ArmyA.GetAliveUnits()
-	unitsB := bm.ArmyB.GetAliveUnits()
+	// Neutral creatures fight alongside the armies
+	fighters := append(append([]*Army{}, bm.Armies...), bm.Neutrals)
 	
-	// Army A attacks Army B
-	for _, unitA := range unitsA {
-		if !unitA.CanAttack() {
-			continue
-		}
-		
-		// Find closest enemy in range
-		var target *Unit
-		minDistance := float64(unitA.Range + 1) // Start with out of range
+	// Collect targets before any attack so all armies strike simultaneously
+	enemies := make([][]*Unit, len(fighters))
+	for i, army := range fighters {
+		enemies[i] = bm.GetEnemyUnits(army.ID)
 		
-		for _, unitB := range unitsB {
-			distance := unitA.Position.Distance(unitB.Position)
-			if distance <= unitA.Range && distance < minDistance {
-				target = unitB
-				minDistance = distance
-			}
+		// Pursuing armies also cut down fleeing enemies
+		if army.pursues() {
+			enemies[i] = append(enemies[i], bm.getFleeingEnemyUnits(army.ID)...)
 		}
-		
-		// Attack if target found
-		if target != nil {
-			unitA.Attack(target)
+	}
+	
+	// Attackers killed or routed mid-swing lose their blow
+	var landing []*Unit
+	for _, army := range fighters {
+		for _, unit := range army.GetAllUnits() {
+			if !unit.isSwinging() {
+				continue
+			}
+			if !unit.IsAlive || unit.IsRetreating {
+				unit.CancelAttack()
+			} else if unit.IsSwingAtHitFrame() {
+				landing = append(landing, unit)
+			}
 		}
 	}
 	
-	// Army B attacks Army A
-	for _, unitB := range unitsB {
-		if !unitB.CanAttack() {
+	// Swings reaching their hit frame land together so trading blows still kill both sides
+	for _, unit := range landing {
+		if unit.SwingStructure != nil {
+			if structure, damage := unit.LandStructureAttack(); damage > 0 {
+				bm.damageStructure(unit, structure, damage)
+			}
 			continue
 		}
-		
-		// Find closest enemy in range
-		var target *Unit
-		minDistance := float64(unitB.Range + 1)
-		
-		for _, unitA := range unitsA {
-			distance := unitB.Position.Distance(unitA.Position)
-			if distance <= unitB.Range && distance < minDistance {
-				target = unitA
-				minDistance = distance
-			}
+		eventType := EventHit
+		if unit.Hidden {
+			eventType = EventAmbush
 		}
-		
-		// Attack if target found
-		if target != nil {
-			unitB.Attack(target)
+		bm.driftShot(unit)
+		if unit.casting != nil && unit.casting.ProjectileSpeed > 0 {
+			bm.launchSpell(unit, eventType)
+			continue
+		}
+		if target, damage := unit.LandAttack(); damage > 0 {
+			bm.recordStrike(unit, target, damage, eventType, unit.casting)
+			unit.knockBack(target)
 		}
 	}
+	
+	for i, army := range fighters {
+		for _, unit := range army.GetAliveUnits() {
+			if !unit.CanAttack() {
+				continue
+			}
+			
+			// Siege engines batter structures before fighting troops
+			if unit.IsSiegeEngine() {
+				if structure := bm.getStructureInRange(unit); structure != nil {
+					unit.StartStructureAttack(structure)
+					continue
+				}
+			}
+			
+			// Find closest enemy in range
+			var target *Unit
+			minDistance := float64(unit.Range + 1) // Start with out of range
+			
+			for _, enemy := range enemies[i] {
+				distance := unit.Position.Distance(enemy.Position)
+				if distance <= unit.Range && distance < minDistance && unit.CanHit(enemy) {
+					// Trees and boulders stop arrows and spells
+					if unit.Range > rangedThreatRange && bm.Terrain.IsShotBlocked(unit.Position, enemy.Position) {
+						continue
+					}
+					target = enemy
+					minDistance = distance
+				}
+			}
+			
+			// Wind up an attack (or start channeling a spell) if target found; with no one to fight, hack at walls and gates in the way
+			if target != nil {
+				unit.casting = bm.chooseAbility(unit, target, enemies[i])
+				if unit.casting != nil && unit.casting.Channel > 0 {
+					unit.startChannel(target)
+				} else {
+					unit.StartAttack(target)
+				}
+			} else if structure := bm.getStructureInRange(unit); structure != nil {
+				unit.StartStructureAttack(structure)
+			}
+		}
+	}
+}
+
+// recordStrike logs a blow or spell that landed on the target and lets the spell, if any, take effect
+func (bm *BattleManager) recordStrike(attacker, target *Unit, damage int, eventType BattleEventType, spell *Ability) {
+	bm.recordEvent(BattleEvent{Type: eventType, UnitID: attacker.ID, GroupID: attacker.GroupID, OtherID: target.ID, Amount: damage})
+	bm.Heatmap.add(HeatmapDamage, target.Position, float64(damage))
+	bm.onHit(attacker, target, damage)
+	if spell != nil {
+		bm.landAbility(attacker, target, spell)
+	}
 }
 
 // checkWinConditions checks if the battle should end
 func (bm *BattleManager) checkWinConditions() {
+	// A stage trigger may have ended the battle this tick
+	if !bm.IsActive {
+		return
+	}
+	
+	// Check stage-specific victory conditions
+	for _, objective := range bm.Objectives {
+		if objective.Winner != WinnerUndecided {
+			bm.endBattle(objective.Winner)
+			return
+		}
+	}
+	
+	// Check victory score from capture points
+	if bm.Stage.ScoreLimit > 0 {
+		var reached []int
+		for armyID, score := range bm.Scores {
+			if score >= bm.Stage.ScoreLimit {
+				reached = append(reached, armyID)
+			}
+		}
+		if len(reached) > 0 {
+			bm.endBattle(bm.resolveWinner(reached))
+			return
+		}
+	}
+	
+	// Check if only one alliance is left standing (others routed or defeated)
+	var standing []int
+	for _, army := range bm.Armies {
+		if !army.IsRouted && !army.IsDefeated() {
+			standing = append(standing, army.ID)
+		}
+	}
+	if len(standing) == 0 || bm.isSingleAlliance(standing) {
+		bm.endBattle(bm.resolveWinner(standing))
+		return
+	}
+	
 	// Check if time limit reached
 	if bm.BattleTime >= bm.TimeLimit {
-		bm.IsActive = false
 		// Determine winner by remaining health
-		healthA := bm.ArmyA.GetTotalHealth()
-		healthB := bm.ArmyB.GetTotalHealth()
-		
-		if healthA > healthB {
-			bm.Winner = 0 // Army A wins
-		} else if healthB > healthA {
-			bm.Winner = 1 // Army B wins
-		} else {
-			bm.Winner = 2 // Draw
+		var leaders []int
+		bestHealth := -1.0
+		for _, army := range bm.Armies {
+			health := army.GetTotalHealth()
+			if health > bestHealth {
+				leaders = []int{army.ID}
+				bestHealth = health
+			} else if health == bestHealth {
+				leaders = append(leaders, army.ID)
+			}
 		}
-		return
+		bm.endBattle(bm.resolveWinner(leaders))
 	}
-	
-	// Check if either army is defeated
-	if bm.ArmyA.IsDefeated() && bm.ArmyB.IsDefeated() {
-		bm.IsActive = false
-		bm.Winner = 2 // Draw
-	} else if bm.ArmyA.IsDefeated() {
-		bm.IsActive = false
-		bm.Winner = 1 // Army B wins
-	} else if bm.ArmyB.IsDefeated() {
-		bm.IsActive = false
-		bm.Winner = 0 // Army A wins
+}
+
+// endBattle stops the battle with the given result
+func (bm *BattleManager) endBattle(winner int) {
+	bm.IsActive = false
+	bm.Winner = winner
+}
+
+// isSingleAlliance reports whether all given armies are allied with each other
+func (bm *BattleManager) isSingleAlliance(armyIDs []int) bool {
+	for _, a := range armyIDs {
+		for _, b := range armyIDs {
+			if !bm.AreAllied(a, b) {
+				return false
+			}
+		}
 	}
+	return true
+}
+
+// resolveWinner returns the winner among candidate armies; rival candidates result in a draw
+func (bm *BattleManager) resolveWinner(candidates []int) int {
+	if len(candidates) == 0 || !bm.isSingleAlliance(candidates) {
+		return WinnerDraw
+	}
+	return candidates[0]
 }
 
 // GetWinnerName returns the name of the winner
+// Allied armies share the victory
 func (bm *BattleManager) GetWinnerName() string {
 	switch bm.Winner {
-	case 0:
-		return "軍勢A"
-	case 1:
-		return "軍勢B"
-	case 2:
+	case WinnerUndecided:
+		return "未決定"
+	case WinnerDraw:
 		return "引き分け"
-	default:
+	}
+	
+	var names []string
+	for _, army := range bm.Armies {
+		if bm.AreAllied(bm.Winner, army.ID) {
+			names = append(names, army.Name)
+		}
+	}
+	if len(names) == 0 {
 		return "未決定"
 	}
+	return strings.Join(names, "・")
 }
 
 // updateAI updates AI behaviors for all units
 func (bm *BattleManager) updateAI(deltaTime float64) {
-	// Update Army A AI (fight against Army B)
-	unitsA := bm.ArmyA.GetAliveUnits()
-	unitsB := bm.ArmyB.GetAliveUnits()
-	
 	// デバッグ: 軍勢の状況
-	fmt.Printf("AI Update - Army A: %d units, Army B: %d units\n", len(unitsA), len(unitsB))
-	
-	for _, unit := range unitsA {
-		if unit.AI != nil {
-			unit.AI.Update(unit, unitsB, deltaTime)
-		}
+	counts := make([]string, 0, len(bm.Armies))
+	for _, army := range bm.Armies {
+		counts = append(counts, fmt.Sprintf("Army %s: %d units", data.ArmyLabel(army.ID), army.GetAliveCount()))
 	}
+	fmt.Printf("AI Update - %s\n", strings.Join(counts, ", "))
 	
-	// Update Army B AI (fight against Army A)
-	for _, unit := range unitsB {
-		if unit.AI != nil {
-			unit.AI.Update(unit, unitsA, deltaTime)
-		}
-	}
-}
-
-// handleCollisions handles collisions between all units
-func (bm *BattleManager) handleCollisions() {
-	allUnits := append(bm.ArmyA.GetAliveUnits(), bm.ArmyB.GetAliveUnits()...)
+	// Groups following player orders skip their AI
+	orderedGroups := bm.getOrderedGroupIDs()
 	
-	// Check collisions between all pairs of units
-	for i := 0; i < len(allUnits); i++ {
-		for j := i + 1; j < len(allUnits); j++ {
-			unit1 := allUnits[i]
-			unit2 := allUnits[j]
-			
-			if unit1.IsCollidingWith(unit2) {
-				unit1.ResolveCollision(unit2)
+	// Each army fights every army outside its alliance
+	for _, army := range bm.Armies {
+		enemies := bm.GetEnemyUnits(army.ID)
+		for _, unit := range army.GetAliveUnits() {
+			if unit.AI != nil && !orderedGroups[unit.GroupID] {
+				unit.AI.Update(unit, enemies, deltaTime)
 			}
 		}
 	}
@@ -0,0 +1,241 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// maxBattleEvents caps the battle log; the oldest tenth is dropped when it fills up
+const maxBattleEvents = 5000
+
+// BattleEventType represents the kind of a battle log record
+type BattleEventType int
+
+const (
+	EventHit                BattleEventType = iota // 攻撃が命中した
+	EventTarget                                    // 攻撃目標を定めた
+	EventOrder                                     // 部隊が移動命令を受けた
+	EventRetreat                                   // 撤退を始めた
+	EventResupply                                  // 補給地点へ向かった
+	EventMeleeFallback                             // 矢弾が尽きて白兵戦に切り替えた
+	EventDeath                                     // 戦死した
+	EventSiegeHit                                  // 建造物に攻撃が命中した（OtherID: 建造物）
+	EventStructureDestroyed                        // 建造物を破壊した（OtherID: 建造物）
+	EventTowerShot                                 // 櫓の矢を受けた（OtherID: 建造物）
+	EventSlam                                      // 押し下げられて壁・崖・障害物に叩きつけられた（Amount: ダメージ）
+	EventAssign                                    // 部隊の指揮権が協力プレイのプレイヤーに移った
+	EventGarrison                                  // 櫓に入った
+	EventLeaveGarrison                             // 櫓から出た
+	EventSummon                                    // ユニットを召喚した（OtherID: 召喚したユニット）
+	EventUnsummoned                                // 召喚の効果が切れて消えた
+	EventAmbush                                    // 潜伏中に不意打ちした（OtherID: 標的、Amount: ダメージ）
+	EventIgnite                                    // 攻撃魔法で森・藪に火を放った
+	EventBurn                                      // 燃えているマスで炎に焼かれた
+	EventSpikePit                                  // 落とし穴にはまった（Amount: ダメージ）
+	EventCaltrops                                  // まきびしを踏んで足が鈍った
+	EventBurst                                     // 大魔法を放った（OtherID: 目標、Amount: 巻き込んだ敵の数）
+	EventInterrupted                               // 大魔法の詠唱が途切れた
+	EventSurrounded                                // 部隊が三方以上から包囲された
+	EventCapture                                   // 拠点が奪われた（Amount: 奪った軍勢、Position: 拠点）
+	EventWindMiss                                  // 矢が風に流されて外れた（OtherID: 狙った標的、Position: 着弾点）
+)
+
+// BattleEvent is one record of the battle log
+type BattleEvent struct {
+	Time     float64
+	Type     BattleEventType
+	UnitID   int               // 主体のユニット（部隊への命令は 0）
+	GroupID  int               // 主体の部隊
+	OtherID  int               // 相手のユニット（0: なし）
+	Amount   int               // 与えたダメージ
+	Position gamemath.Vector2D // 命令の目標地点
+	Player   int               // 命令・指揮権の協力プレイのプレイヤー（0: 1人プレイ）
+}
+
+// unitLogState is the unit state last written to the battle log
+type unitLogState struct {
+	dead          bool
+	retreating    bool
+	resupplying   bool
+	meleeFallback bool
+	garrisoned    bool
+	surrounded    bool
+	target        *Unit
+}
+
+// recordEvent appends a record stamped with the current battle time
+func (bm *BattleManager) recordEvent(event BattleEvent) {
+	if len(bm.Events) >= maxBattleEvents {
+		bm.Events = append(bm.Events[:0], bm.Events[maxBattleEvents/10:]...)
+	}
+	event.Time = bm.BattleTime
+	bm.Events = append(bm.Events, event)
+}
+
+// updateBattleLog records the state changes of every unit since the last tick
+func (bm *BattleManager) updateBattleLog() {
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, unit := range army.GetAllUnits() {
+			bm.logUnitState(unit)
+		}
+	}
+}
+
+// logUnitState records the unit's new target and state transitions
+func (bm *BattleManager) logUnitState(unit *Unit) {
+	logged := &unit.logged
+	event := BattleEvent{UnitID: unit.ID, GroupID: unit.GroupID}
+	
+	if !unit.IsAlive {
+		if !logged.dead {
+			logged.dead = true
+			
+			// Minions whose time ran out did not fall in battle
+			if unit.IsSummoned() && unit.Lifetime <= 0 {
+				event.Type = EventUnsummoned
+				bm.recordEvent(event)
+				return
+			}
+			
+			event.Type = EventDeath
+			bm.recordEvent(event)
+			bm.Heatmap.add(HeatmapDeaths, unit.Position, 1)
+		}
+		return
+	}
+	
+	if unit.AI != nil && unit.AI.TargetEnemy != logged.target {
+		logged.target = unit.AI.TargetEnemy
+		if logged.target != nil {
+			targetEvent := event
+			targetEvent.Type = EventTarget
+			targetEvent.OtherID = logged.target.ID
+			bm.recordEvent(targetEvent)
+		}
+	}
+	
+	transitions := []struct {
+		now    bool
+		logged *bool
+		event  BattleEventType
+	}{
+		{unit.IsRetreating, &logged.retreating, EventRetreat},
+		{unit.Resupplying, &logged.resupplying, EventResupply},
+		{unit.MeleeFallback, &logged.meleeFallback, EventMeleeFallback},
+		{unit.Surrounded, &logged.surrounded, EventSurrounded},
+	}
+	for _, transition := range transitions {
+		if transition.now != *transition.logged {
+			*transition.logged = transition.now
+			if transition.now {
+				event.Type = transition.event
+				bm.recordEvent(event)
+			}
+		}
+	}
+	
+	// Entering and leaving a tower are both worth a line
+	if garrisoned := unit.Garrison != nil; garrisoned != logged.garrisoned {
+		logged.garrisoned = garrisoned
+		event.Type = EventLeaveGarrison
+		if garrisoned {
+			event.Type = EventGarrison
+			event.OtherID = unit.Garrison.ID
+		}
+		bm.recordEvent(event)
+	}
+}
+
+// GetUnitHistory returns the records involving the unit or orders to its group, oldest first
+func (bm *BattleManager) GetUnitHistory(unit *Unit) []BattleEvent {
+	var history []BattleEvent
+	for _, event := range bm.Events {
+		switch {
+		case event.UnitID == unit.ID:
+		case (event.Type == EventHit || event.Type == EventAmbush || event.Type == EventSummon) && event.OtherID == unit.ID:
+		case (event.Type == EventOrder || event.Type == EventAssign) && event.GroupID == unit.GroupID:
+		default:
+			continue
+		}
+		history = append(history, event)
+	}
+	return history
+}
+
+// DescribeEvent returns the record as seen by the unit with the given ID
+func DescribeEvent(event BattleEvent, unitID int) string {
+	switch event.Type {
+	case EventHit:
+		if event.UnitID == unitID {
+			return fmt.Sprintf("#%d に %d ダメージ", event.OtherID, event.Amount)
+		}
+		return fmt.Sprintf("#%d から %d ダメージ", event.UnitID, event.Amount)
+	case EventTarget:
+		return fmt.Sprintf("#%d を標的に", event.OtherID)
+	case EventOrder:
+		text := fmt.Sprintf("部隊に移動命令 (%.0fm, %.0fm)", event.Position.X/10, event.Position.Y/10)
+		if event.Player > 0 {
+			text = fmt.Sprintf("P%d: %s", event.Player, text)
+		}
+		return text
+	case EventAssign:
+		if event.Player == 0 {
+			return "部隊の指揮権を共有"
+		}
+		return fmt.Sprintf("部隊の指揮権が P%d に", event.Player)
+	case EventRetreat:
+		return "撤退開始"
+	case EventResupply:
+		return "矢弾切れ、補給へ後退"
+	case EventMeleeFallback:
+		return "矢弾切れ、白兵戦へ"
+	case EventDeath:
+		return "戦死"
+	case EventSiegeHit:
+		return fmt.Sprintf("建造物に %d ダメージ", event.Amount)
+	case EventStructureDestroyed:
+		return "建造物を破壊"
+	case EventTowerShot:
+		return fmt.Sprintf("櫓の矢で %d ダメージ", event.Amount)
+	case EventSlam:
+		return fmt.Sprintf("押し下げられて叩きつけられ %d ダメージ", event.Amount)
+	case EventGarrison:
+		return "櫓に入った"
+	case EventLeaveGarrison:
+		return "櫓から出た"
+	case EventSummon:
+		if event.UnitID == unitID {
+			return fmt.Sprintf("#%d を召喚", event.OtherID)
+		}
+		return fmt.Sprintf("#%d に召喚された", event.UnitID)
+	case EventUnsummoned:
+		return "召喚の効果が切れて消えた"
+	case EventAmbush:
+		if event.UnitID == unitID {
+			return fmt.Sprintf("#%d に不意打ち %d ダメージ", event.OtherID, event.Amount)
+		}
+		return fmt.Sprintf("#%d から不意打ち %d ダメージ", event.UnitID, event.Amount)
+	case EventIgnite:
+		return "森に火を放った"
+	case EventBurn:
+		return fmt.Sprintf("炎で %d ダメージ", event.Amount)
+	case EventSpikePit:
+		return fmt.Sprintf("落とし穴で %d ダメージ", event.Amount)
+	case EventCaltrops:
+		return "まきびしを踏んだ"
+	case EventInterrupted:
+		return "大魔法の詠唱が途切れた"
+	case EventSurrounded:
+		return "部隊が包囲された"
+	case EventCapture:
+		return fmt.Sprintf("軍勢%sが拠点を奪取", data.ArmyLabel(event.Amount))
+	case EventBurst:
+		return fmt.Sprintf("#%d に大魔法、周りの %d 体を巻き込んだ", event.OtherID, event.Amount)
+	case EventWindMiss:
+		return fmt.Sprintf("#%d を狙った矢が風に流された", event.OtherID)
+	default:
+		return "?"
+	}
+}
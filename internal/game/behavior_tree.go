@@ -0,0 +1,98 @@
+package game
+
+// BTStatus is the result of ticking a behavior tree node
+type BTStatus int
+
+const (
+	BTSuccess BTStatus = iota // 成功
+	BTFailure                 // 失敗
+	BTRunning                 // 実行中
+)
+
+// BTContext carries the state a behavior tree operates on during one tick
+type BTContext struct {
+	Unit      *Unit
+	AI        *AIBehavior
+	Enemies   []*Unit
+	DeltaTime float64
+}
+
+// BTNode is a node of a behavior tree
+type BTNode interface {
+	Tick(ctx *BTContext) BTStatus
+}
+
+// Selector ticks children in order until one does not fail
+type Selector struct {
+	Children []BTNode
+}
+
+// NewSelector creates a selector node
+func NewSelector(children ...BTNode) *Selector {
+	return &Selector{Children: children}
+}
+
+// Tick runs the selector
+func (s *Selector) Tick(ctx *BTContext) BTStatus {
+	for _, child := range s.Children {
+		if status := child.Tick(ctx); status != BTFailure {
+			return status
+		}
+	}
+	return BTFailure
+}
+
+// Sequence ticks children in order until one does not succeed
+type Sequence struct {
+	Children []BTNode
+}
+
+// NewSequence creates a sequence node
+func NewSequence(children ...BTNode) *Sequence {
+	return &Sequence{Children: children}
+}
+
+// Tick runs the sequence
+func (s *Sequence) Tick(ctx *BTContext) BTStatus {
+	for _, child := range s.Children {
+		if status := child.Tick(ctx); status != BTSuccess {
+			return status
+		}
+	}
+	return BTSuccess
+}
+
+// Condition succeeds when its check returns true
+type Condition struct {
+	Name  string
+	Check func(ctx *BTContext) bool
+}
+
+// NewCondition creates a condition node
+func NewCondition(name string, check func(ctx *BTContext) bool) *Condition {
+	return &Condition{Name: name, Check: check}
+}
+
+// Tick evaluates the condition
+func (c *Condition) Tick(ctx *BTContext) BTStatus {
+	if c.Check(ctx) {
+		return BTSuccess
+	}
+	return BTFailure
+}
+
+// Action performs a unit behavior
+type Action struct {
+	Name string
+	Run  func(ctx *BTContext) BTStatus
+}
+
+// NewAction creates an action node
+func NewAction(name string, run func(ctx *BTContext) BTStatus) *Action {
+	return &Action{Name: name, Run: run}
+}
+
+// Tick performs the action
+func (a *Action) Tick(ctx *BTContext) BTStatus {
+	return a.Run(ctx)
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// CapturePoint tracks control of a capture point on the battlefield
+type CapturePoint struct {
+	Config     data.CapturePointConfig
+	Owner      int     // -1: 中立, それ以外: 確保している軍勢
+	Controller int     // 支配を進めている軍勢 (-1: なし)
+	Control    float64 // Controller の支配度 0.0 .. 1.0
+}
+
+// NewCapturePoints creates capture points from the stage configuration
+func NewCapturePoints(configs []data.CapturePointConfig) []*CapturePoint {
+	points := make([]*CapturePoint, 0, len(configs))
+	for _, config := range configs {
+		if config.CaptureTime <= 0 {
+			config.CaptureTime = 10.0
+		}
+		points = append(points, &CapturePoint{
+			Config:     config,
+			Owner:      -1,
+			Controller: -1,
+		})
+	}
+	return points
+}
+
+// Center returns the capture point position
+func (cp *CapturePoint) Center() gamemath.Vector2D {
+	return gamemath.Vector2D{X: cp.Config.X, Y: cp.Config.Y}
+}
+
+// update shifts control toward the army standing on the point alone (-1: nobody)
+func (cp *CapturePoint) update(holder int, deltaTime float64) {
+	if holder < 0 {
+		return
+	}
+	
+	step := deltaTime / cp.Config.CaptureTime
+	if cp.Controller < 0 {
+		cp.Controller = holder
+	}
+	
+	// Fully captured points change hands
+	if holder == cp.Controller {
+		cp.Control += step
+		if cp.Control >= 1.0 {
+			cp.Control = 1.0
+			cp.Owner = holder
+		}
+		return
+	}
+	
+	// Other armies must wipe out the current control first, which neutralizes the point
+	cp.Control -= step
+	if cp.Control <= 0 {
+		cp.Control = 0
+		cp.Owner = -1
+		cp.Controller = holder
+	}
+}
+
+// updateCapturePoints updates point control and accumulates victory score
+func (bm *BattleManager) updateCapturePoints(deltaTime float64) {
+	for _, point := range bm.CapturePoints {
+		holder := bm.getZoneHolder(point.Center(), point.Config.Radius)
+		
+		// Allies reinforce each other's control
+		if holder >= 0 && point.Controller >= 0 && bm.AreAllied(holder, point.Controller) {
+			holder = point.Controller
+		}
+		owner := point.Owner
+		point.update(holder, deltaTime)
+		if point.Owner >= 0 && point.Owner != owner {
+			bm.recordEvent(BattleEvent{Type: EventCapture, Amount: point.Owner, Position: point.Center()})
+		}
+		
+		if point.Owner >= 0 {
+			bm.Scores[point.Owner] += point.Config.ScoreRate * deltaTime
+		}
+	}
+}
+
+// countUnitsNear counts alive units of the army within radius of the position
+func (bm *BattleManager) countUnitsNear(army *Army, position gamemath.Vector2D, radius float64) int {
+	count := 0
+	for _, unit := range army.GetAliveUnits() {
+		if unit.Position.Distance(position) <= radius {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,108 @@
+package game
+
+import "github.com/shirou/tinygocha/internal/graphics"
+
+// channelSafetyMargin is added to how far an enemy can close in while the caster channels (3m)
+const channelSafetyMargin = 30.0
+
+// IsChanneling reports whether the unit is building up a channeled spell
+func (u *Unit) IsChanneling() bool {
+	return u.channelTarget != nil
+}
+
+// GetChannelProgress returns how far the channeled spell has built up (0.0-1.0)
+func (u *Unit) GetChannelProgress() float64 {
+	if !u.IsChanneling() || u.casting == nil || u.casting.Channel <= 0 {
+		return 0
+	}
+	return min(u.Channeled/u.casting.Channel, 1)
+}
+
+// IsStunned reports whether the unit is knocked senseless and can neither move nor fight
+func (u *Unit) IsStunned() bool {
+	return u.Stunned > 0
+}
+
+// Stun knocks the unit senseless for the given seconds, breaking off its attack and any channeled spell
+func (u *Unit) Stun(seconds float64) {
+	if !u.IsAlive {
+		return
+	}
+	u.Stunned = max(u.Stunned, seconds)
+	u.CancelAttack()
+	u.interruptChannel()
+}
+
+// startChannel pays for the chosen spell and starts building it up at the target; the caster stands still until it goes off
+func (u *Unit) startChannel(target *Unit) {
+	u.spendMana()
+	u.channelTarget = target
+	u.Channeled = 0
+	u.Target = u.Position
+}
+
+// interruptChannel breaks off the channeled spell; the mana paid for it is lost
+func (u *Unit) interruptChannel() {
+	if !u.IsChanneling() {
+		return
+	}
+	u.channelTarget = nil
+	u.Channeled = 0
+	u.interrupted = true
+}
+
+// releaseChannel lets the built-up spell fly at the target; it lands on the attack's hit frame like any other
+func (u *Unit) releaseChannel() {
+	target := u.channelTarget
+	u.channelTarget = nil
+	u.Channeled = 0
+	
+	u.Animation.SetAnimation(graphics.AnimationAttack)
+	u.Animation.Reset()
+	u.LastAttackTime = u.AttackCooldown * u.fatigueCooldown()
+	u.SwingTarget = target
+}
+
+// isSafeToChannel reports whether no enemy could reach the caster before a spell channeled for the given
+// seconds goes off: each enemy's range plus the ground it covers meanwhile must fall short of the caster
+func isSafeToChannel(unit *Unit, enemies []*Unit, channel float64) bool {
+	for _, enemy := range enemies {
+		if !enemy.IsAlive || !enemy.CanHit(unit) {
+			continue
+		}
+		reach := enemy.Range + enemy.GetSpeed()*channel + channelSafetyMargin
+		if enemy.Position.Distance(unit.Position) <= reach {
+			return false
+		}
+	}
+	return true
+}
+
+// updateChannels counts down stuns and builds up channeled spells, breaking them off when the caster
+// was hurt, stunned or routed, or the target fell or moved out of range
+func (bm *BattleManager) updateChannels(deltaTime float64) {
+	for _, unit := range bm.getAllAliveUnits() {
+		if unit.Stunned > 0 {
+			unit.Stunned = max(unit.Stunned-deltaTime, 0)
+		}
+		
+		if unit.IsChanneling() {
+			target := unit.channelTarget
+			effectiveRange := unit.Range + unit.GetCollisionRadius() + target.GetCollisionRadius()
+			if unit.IsRetreating || !target.IsAlive || unit.Position.Distance(target.Position) > effectiveRange {
+				unit.interruptChannel()
+			} else {
+				unit.Channeled += deltaTime
+				unit.Target = unit.Position
+				if unit.Channeled >= unit.casting.Channel {
+					unit.releaseChannel()
+				}
+			}
+		}
+		
+		if unit.interrupted {
+			unit.interrupted = false
+			bm.recordEvent(BattleEvent{Type: EventInterrupted, UnitID: unit.ID, GroupID: unit.GroupID})
+		}
+	}
+}
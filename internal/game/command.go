@@ -0,0 +1,84 @@
+package game
+
+// Command point tuning
+const (
+	MaxCommandPoints      = 10.0
+	CommandPointRegenRate = 0.2 // 毎秒の回復量（5秒で1）
+	OrderMoveCost         = 3.0
+)
+
+// CommandPoints limits how often the player can issue orders
+type CommandPoints struct {
+	Current   float64
+	Max       float64
+	RegenRate float64 // Points recovered per second
+}
+
+// NewCommandPoints creates a full command point pool
+func NewCommandPoints() *CommandPoints {
+	return &CommandPoints{
+		Current:   MaxCommandPoints,
+		Max:       MaxCommandPoints,
+		RegenRate: CommandPointRegenRate,
+	}
+}
+
+// GetRatio returns the pool fill ratio (0.0-1.0)
+func (cp *CommandPoints) GetRatio() float64 {
+	if cp.Max <= 0 {
+		return 0
+	}
+	return cp.Current / cp.Max
+}
+
+// Spend deducts the cost if the pool can afford it
+func (cp *CommandPoints) Spend(cost float64) bool {
+	if cp.Current < cost {
+		return false
+	}
+	cp.Current -= cost
+	return true
+}
+
+// refund returns points, e.g. for a queued order that was replaced
+func (cp *CommandPoints) refund(cost float64) {
+	cp.Current += cost
+	if cp.Current > cp.Max {
+		cp.Current = cp.Max
+	}
+}
+
+// update regenerates command points
+func (cp *CommandPoints) update(deltaTime float64) {
+	cp.refund(cp.RegenRate * deltaTime)
+}
+
+// GetOrderCost returns the command point cost of an order
+func GetOrderCost(order Order) float64 {
+	switch order.Type {
+	case OrderMove, OrderGarrison:
+		return OrderMoveCost
+	default:
+		return 0
+	}
+}
+
+// EnableCommandPoints makes player orders cost command points
+func (bm *BattleManager) EnableCommandPoints() {
+	bm.CommandPoints = NewCommandPoints()
+}
+
+// spendCommandPoints pays for an order; always succeeds when command points are disabled
+func (bm *BattleManager) spendCommandPoints(order Order) bool {
+	if bm.CommandPoints == nil {
+		return true
+	}
+	return bm.CommandPoints.Spend(GetOrderCost(order))
+}
+
+// updateCommandPoints regenerates command points
+func (bm *BattleManager) updateCommandPoints(deltaTime float64) {
+	if bm.CommandPoints != nil {
+		bm.CommandPoints.update(deltaTime)
+	}
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Chain of command tuning
+const (
+	orderRelayBase      = 0.3   // 命令がメンバーに伝わるまでの最短の秒数（リーダーの号令）
+	orderRelaySpeed     = 150.0 // 命令が伝わる速さ（px/秒）。リーダーから離れたメンバーほど遅れる
+	orderRelayThreshold = 40.0  // 隊形の中心がこれ以上動いたら新しい命令とみなす
+)
+
+// EnableCommandDelay makes orders reach each member only some time after its leader,
+// for every army on the field; call it after CreateArmies
+func (bm *BattleManager) EnableCommandDelay() {
+	bm.CommandDelay = true
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		army.CommandDelay = true
+		for _, group := range army.Groups {
+			group.commandDelay = true
+		}
+	}
+}
+
+// relayedTarget returns the formation center the member moves around: with command delay a new one
+// reaches it only after the leader's order has travelled to it, the longer the further it stands from
+// the leader, and not at all while the leader is stunned. A leader who falls passes on nothing more
+func (g *Group) relayedTarget(member *Unit, deltaTime float64) gamemath.Vector2D {
+	if !g.commandDelay {
+		return g.targetPosition
+	}
+	
+	switch {
+	case !member.orderHeard:
+		// A member new to the group starts from where the group is headed
+		member.heardTarget = g.targetPosition
+		member.orderHeard = true
+	case member.orderDelay > 0:
+		if !g.Leader.IsStunned() {
+			member.orderDelay -= deltaTime
+		}
+		if member.orderDelay <= 0 {
+			member.orderDelay = 0
+			member.heardTarget = g.targetPosition
+		}
+	case member.heardTarget.Distance(g.targetPosition) >= orderRelayThreshold:
+		member.orderDelay = orderRelayBase + member.Position.Distance(g.Leader.Position)/orderRelaySpeed
+	default:
+		// Small shifts as the leader walks on are followed at once
+		member.heardTarget = g.targetPosition
+	}
+	return member.heardTarget
+}
+
+// AwaitingOrder reports whether the leader's latest order has yet to reach the unit
+func (u *Unit) AwaitingOrder() bool {
+	return u.orderDelay > 0
+}
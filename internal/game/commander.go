@@ -0,0 +1,304 @@
+package game
+
+import (
+	"math"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Commander tuning
+const (
+	commanderDecisionInterval = 2.0   // 部隊への指示を見直す間隔（秒）
+	flankOffset               = 300.0 // 側面攻撃で敵部隊の横に回り込む距離
+	flankArrivalDistance      = 80.0  // 回り込み完了とみなす距離
+	defendLeashFactor         = 1.5   // 防衛部隊が拠点から追撃する範囲（半径の倍率）
+	defendMinRadius           = 150.0 // 拠点半径が小さい場合の最小防衛範囲
+	threatDistanceWeight      = 3.0   // 攻撃目標の選択で敵戦力1あたりに加算する距離
+)
+
+// GroupObjectiveType represents the kind of objective a commander gives a group
+type GroupObjectiveType int
+
+const (
+	GroupObjectiveAttack  GroupObjectiveType = iota // 最寄りの敵部隊を攻撃
+	GroupObjectiveDefend                            // 拠点を防衛
+	GroupObjectiveFlank                             // 手薄な側面へ回り込んで攻撃
+	GroupObjectiveAdvance                           // 開戦時にステージで決まった向きへ前進
+)
+
+// GroupObjective is the objective a group works toward under the army commander
+type GroupObjective struct {
+	Type        GroupObjectiveType
+	Target      gamemath.Vector2D // 防衛地点・回り込み地点・前進地点
+	Radius      float64           // 防衛範囲
+	TargetGroup *Group            // 攻撃対象の敵部隊
+	Reached     bool              // 回り込み・前進が完了した
+}
+
+// allowsEnemy reports whether a group following the objective may engage the enemy
+func (o *GroupObjective) allowsEnemy(enemy *Unit) bool {
+	if o.Type != GroupObjectiveDefend {
+		return true
+	}
+	return enemy.Position.Distance(o.Target) <= o.Radius
+}
+
+// isTargetGroupUnit reports whether the enemy belongs to the objective's target group
+func (o *GroupObjective) isTargetGroupUnit(enemy *Unit) bool {
+	return o.TargetGroup != nil && enemy.ArmyID == o.TargetGroup.ArmyID && enemy.GroupID == o.TargetGroup.ID
+}
+
+// ArmyCommander assigns objectives to the groups of an army so they maneuver as units
+type ArmyCommander struct {
+	ArmyID int
+	
+	sinceDecision float64
+	opened        bool // 開戦時の前進を命じた
+}
+
+// NewArmyCommander creates a commander for the army
+func NewArmyCommander(armyID int) *ArmyCommander {
+	return &ArmyCommander{
+		ArmyID:        armyID,
+		sinceDecision: commanderDecisionInterval, // Decide on the first update
+	}
+}
+
+// Update reviews group objectives periodically
+func (c *ArmyCommander) Update(bm *BattleManager, deltaTime float64) {
+	c.sinceDecision += deltaTime
+	if c.sinceDecision < commanderDecisionInterval {
+		return
+	}
+	c.sinceDecision = 0
+	
+	army := bm.GetArmy(c.ArmyID)
+	if army == nil || army.IsRouted {
+		return
+	}
+	c.assignObjectives(bm, army)
+}
+
+// assignObjectives gives every commandable group a defend, flank or attack objective
+func (c *ArmyCommander) assignObjectives(bm *BattleManager, army *Army) {
+	var groups []*Group
+	for _, group := range army.GetActiveGroups() {
+		if group.Leader != nil && group.Leader.IsAlive && !group.Leader.IsRetreating && !group.Leader.Convoy {
+			groups = append(groups, group)
+		}
+	}
+	
+	enemyGroups := bm.getEnemyGroups(army.ID)
+	if len(enemyGroups) == 0 {
+		for _, group := range groups {
+			group.SetObjective(nil)
+		}
+		return
+	}
+	
+	// Keep objectives that are still valid, so flanks are not restarted
+	var unassigned []*Group
+	defenders := 0
+	for _, group := range groups {
+		if objective := group.Objective; c.isObjectiveValid(objective) {
+			switch objective.Type {
+			case GroupObjectiveDefend:
+				defenders++
+			case GroupObjectiveAttack:
+				// Follow the target group as it moves
+				objective.Target = objective.TargetGroup.Leader.Position
+			}
+			continue
+		}
+		unassigned = append(unassigned, group)
+	}
+	
+	// Up to a third of the groups hold the stage's key points
+	maxDefenders := len(groups) / 3
+	for _, point := range bm.getDefendPoints() {
+		if defenders >= maxDefenders || len(unassigned) == 0 {
+			break
+		}
+		if c.isPointDefended(groups, point.position) {
+			continue
+		}
+		
+		index := nearestGroupIndex(unassigned, point.position)
+		unassigned[index].SetObjective(&GroupObjective{
+			Type:   GroupObjectiveDefend,
+			Target: point.position,
+			Radius: point.radius,
+		})
+		unassigned = append(unassigned[:index], unassigned[index+1:]...)
+		defenders++
+	}
+	
+	// The first orders advance the rest along the stage's approach before they pick targets
+	if !c.opened {
+		c.opened = true
+		if army.Approach != (gamemath.Vector2D{}) {
+			for _, group := range unassigned {
+				group.SetObjective(&GroupObjective{
+					Type:   GroupObjectiveAdvance,
+					Target: bm.approachPoint(group.Leader.Position, army.Approach),
+				})
+			}
+			return
+		}
+	}
+	
+	// Cavalry flanks, everyone else attacks the nearest weakly defended enemy group, or a hostile convoy
+	threatMap := bm.GetThreatMap(army.ID)
+	convoy := bm.getHostileConvoy(army.ID)
+	for _, group := range unassigned {
+		target := chooseAttackTarget(threatMap, enemyGroups, group.Leader.Position)
+		if convoy != nil {
+			target = convoy
+		}
+		objective := &GroupObjective{
+			Type:        GroupObjectiveAttack,
+			Target:      target.Leader.Position,
+			TargetGroup: target,
+		}
+		
+		if group.Leader.Type == UnitType("cavalry") {
+			objective.Type = GroupObjectiveFlank
+			objective.Target = chooseFlankPoint(threatMap, group.Leader.Position, target.Leader.Position)
+		}
+		group.SetObjective(objective)
+	}
+}
+
+// isObjectiveValid reports whether a group should keep its current objective
+func (c *ArmyCommander) isObjectiveValid(objective *GroupObjective) bool {
+	if objective == nil {
+		return false
+	}
+	
+	switch objective.Type {
+	case GroupObjectiveDefend:
+		return true
+	case GroupObjectiveAdvance:
+		return !objective.Reached
+	default:
+		target := objective.TargetGroup
+		return target != nil && target.Leader != nil && target.Leader.IsAlive && !target.Leader.IsRetreating
+	}
+}
+
+// isPointDefended reports whether one of the groups already defends the position
+func (c *ArmyCommander) isPointDefended(groups []*Group, position gamemath.Vector2D) bool {
+	for _, group := range groups {
+		objective := group.Objective
+		if objective != nil && objective.Type == GroupObjectiveDefend && objective.Target == position {
+			return true
+		}
+	}
+	return false
+}
+
+// defendPoint is a battlefield position worth holding
+type defendPoint struct {
+	position gamemath.Vector2D
+	radius   float64
+}
+
+// getDefendPoints returns the capture points and objective zones of the stage
+func (bm *BattleManager) getDefendPoints() []defendPoint {
+	var points []defendPoint
+	add := func(position gamemath.Vector2D, radius float64) {
+		points = append(points, defendPoint{
+			position: position,
+			radius:   math.Max(radius*defendLeashFactor, defendMinRadius),
+		})
+	}
+	
+	for _, point := range bm.CapturePoints {
+		add(point.Center(), point.Config.Radius)
+	}
+	for _, objective := range bm.Objectives {
+		if objective.HasZone() {
+			add(objective.Center(), objective.Config.Radius)
+		}
+	}
+	return points
+}
+
+// getEnemyGroups returns the groups of hostile armies that still have an active leader
+func (bm *BattleManager) getEnemyGroups(armyID int) []*Group {
+	var groups []*Group
+	for _, army := range bm.Armies {
+		if bm.AreAllied(armyID, army.ID) {
+			continue
+		}
+		for _, group := range army.GetActiveGroups() {
+			if group.Leader != nil && group.Leader.IsAlive && !group.Leader.IsRetreating {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+// updateCommanders lets every army commander review its group objectives
+func (bm *BattleManager) updateCommanders(deltaTime float64) {
+	for _, commander := range bm.Commanders {
+		commander.Update(bm, deltaTime)
+	}
+}
+
+// nearestGroupIndex returns the index of the group whose leader is closest to the position
+func nearestGroupIndex(groups []*Group, position gamemath.Vector2D) int {
+	best := 0
+	for i, group := range groups {
+		if group.Leader.Position.Distance(position) < groups[best].Leader.Position.Distance(position) {
+			best = i
+		}
+	}
+	return best
+}
+
+// nearestGroup returns the group whose leader is closest to the position
+func nearestGroup(groups []*Group, position gamemath.Vector2D) *Group {
+	return groups[nearestGroupIndex(groups, position)]
+}
+
+// chooseAttackTarget returns the enemy group that is cheapest to reach, weighing
+// distance against the enemy strength gathered around each group
+func chooseAttackTarget(threatMap *ThreatMap, groups []*Group, position gamemath.Vector2D) *Group {
+	if threatMap == nil {
+		return nearestGroup(groups, position)
+	}
+	
+	var best *Group
+	bestCost := math.Inf(1)
+	for _, group := range groups {
+		cost := group.Leader.Position.Distance(position) + threatMap.GetThreat(group.Leader.Position)*threatDistanceWeight
+		if cost < bestCost {
+			best = group
+			bestCost = cost
+		}
+	}
+	return best
+}
+
+// chooseFlankPoint returns the side of the enemy with less enemy strength to maneuver through
+func chooseFlankPoint(threatMap *ThreatMap, from, enemy gamemath.Vector2D) gamemath.Vector2D {
+	left := getFlankPoint(from, enemy, 1)
+	if threatMap == nil {
+		return left
+	}
+	
+	right := getFlankPoint(from, enemy, -1)
+	if threatMap.GetThreat(right) < threatMap.GetThreat(left) {
+		return right
+	}
+	return left
+}
+
+// getFlankPoint returns a point beside the enemy as seen from the attacker (side 1: left, -1: right)
+func getFlankPoint(from, enemy gamemath.Vector2D, side float64) gamemath.Vector2D {
+	facing := enemy.Sub(from).Normalize()
+	left := gamemath.Vector2D{X: facing.Y, Y: -facing.X}
+	return enemy.Add(left.Mul(flankOffset * side))
+}
@@ -1,5 +1,9 @@
 package game
 
+import (
+	"github.com/shirou/tinygocha/internal/data"
+)
+
 // UnitTypeConfig represents unit configuration (re-exported from data package)
 type UnitTypeConfig struct {
 	Name       string
@@ -8,6 +12,52 @@ type UnitTypeConfig struct {
 	Defense    int
 	Speed      float64
 	Range      float64
+	SightRange float64  // 知覚範囲（0: defaultSightRange）
 	MagicPower int
 	Size       float64  // ユニットの大きさ（衝突判定用）
+	Knockback  float64  // 命中で敵を押し下げる距離（px、0: 押さない）
+	Ammo       int      // 矢弾の数（0: 無制限）
+	HeavyArmor bool     // 重装備（スタミナの消耗が大きい）
+	SiegeBonus float64  // 建造物への攻撃力の倍率（0: 1倍）
+	Flying     bool     // 飛行（地形・地上の衝突を無視し、近接攻撃を受けない）
+	Stealth    bool     // 潜伏（近づかれるまで敵から見えず、不意打ちで大ダメージ）
+	
+	Summon SummonAbility // 召喚能力（UnitType 空: なし）
+	Spells SpellAbility  // 魔力と魔法（Abilities 空: 魔法を使わない）
+	
+	AIScript string // 行動を決めるLuaスクリプトのパス（空: 行動ツリーのみ）
+}
+
+// newUnitTypeConfig converts a unit type loaded from data, looking up its abilities
+func newUnitTypeConfig(config data.UnitTypeConfig, dataManager *data.DataManager) UnitTypeConfig {
+	return UnitTypeConfig{
+		Name:       config.Name,
+		HP:         config.HP,
+		Attack:     config.Attack,
+		Defense:    config.Defense,
+		Speed:      config.Speed,
+		Range:      config.Range,
+		SightRange: config.SightRange,
+		MagicPower: config.MagicPower,
+		Size:       config.Size,
+		Knockback:  config.Knockback,
+		Ammo:       config.Ammo,
+		HeavyArmor: config.HeavyArmor,
+		SiegeBonus: config.SiegeBonus,
+		Flying:     config.Flying,
+		Stealth:    config.Stealth,
+		Summon: SummonAbility{
+			UnitType: config.Summon,
+			Interval: config.SummonInterval,
+			Count:    config.SummonCount,
+			Lifetime: config.SummonLifetime,
+			Max:      config.SummonMax,
+		},
+		Spells: SpellAbility{
+			MaxMana:   config.Mana,
+			Regen:     config.ManaRegen,
+			Abilities: newAbilities(config.Abilities, dataManager),
+		},
+		AIScript: config.AIScript,
+	}
 }
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// convoyWaypointReach is how close the convoy comes to a route point before heading for the next
+const convoyWaypointReach = 20.0
+
+// spawnConvoys places the wagons of every convoy objective at the start of its route, in a group of their own
+func (bm *BattleManager) spawnConvoys(dataManager *data.DataManager) {
+	for _, objective := range bm.Objectives {
+		if objective.Config.Type != data.VictoryConvoy || len(objective.Config.Path) < 2 {
+			continue
+		}
+		army := bm.GetArmy(data.ArmyIndex(objective.Config.Army))
+		if army == nil {
+			fmt.Printf("Warning: unknown convoy army '%s'\n", objective.Config.Army)
+			continue
+		}
+		
+		unitType := objective.Config.ConvoyUnit()
+		config, err := dataManager.GetUnitConfig(unitType)
+		if err != nil {
+			fmt.Printf("Error getting convoy config for %s: %v\n", unitType, err)
+			continue
+		}
+		
+		// The wagons only follow their route
+		convoy := bm.createUnit(UnitType(unitType), newUnitTypeConfig(config, dataManager), true, army.ID)
+		convoy.Convoy = true
+		convoy.AI = nil
+		convoy.Position = objective.Config.Path[0].ToVector2D()
+		convoy.Target = convoy.Position
+		
+		group := NewGroup(bm.nextGroupID(), army.ID, convoy, nil)
+		group.Facing = bm.armyFacing(army.ID)
+		convoy.GroupID = group.ID
+		army.AddGroup(group)
+		
+		objective.Convoy = convoy
+		objective.Waypoint = 1
+	}
+}
+
+// updateConvoyObjective drives the convoy along its route; the escort wins when it arrives and its enemies when it falls
+func (bm *BattleManager) updateConvoyObjective(objective *Objective) {
+	convoy := objective.Convoy
+	if convoy == nil {
+		return
+	}
+	
+	if !convoy.IsAlive {
+		if enemies := bm.getHostileArmies(convoy.ArmyID); len(enemies) > 0 {
+			objective.Winner = enemies[0].ID
+		}
+		return
+	}
+	
+	route := objective.Config.GetPath()
+	for objective.Waypoint < len(route) && convoy.Position.Distance(route[objective.Waypoint]) <= objective.arrivalDistance() {
+		objective.Waypoint++
+	}
+	if objective.Waypoint >= len(route) {
+		objective.Winner = convoy.ArmyID
+		return
+	}
+	convoy.MoveTo(route[objective.Waypoint])
+}
+
+// arrivalDistance returns how close the convoy must come to the route point it heads for
+// The destination may be given a wider radius than the points on the way
+func (o *Objective) arrivalDistance() float64 {
+	if o.Waypoint == len(o.Config.Path)-1 && o.Config.Radius > 0 {
+		return o.Config.Radius
+	}
+	return convoyWaypointReach
+}
+
+// ConvoyProgress returns the share of its route the convoy has covered (0-1)
+func (o *Objective) ConvoyProgress() float64 {
+	route := o.Config.GetPath()
+	if o.Convoy == nil || len(route) < 2 {
+		return 0
+	}
+	if o.Waypoint >= len(route) {
+		return 1
+	}
+	
+	total, covered := 0.0, 0.0
+	for i := 1; i < len(route); i++ {
+		leg := route[i-1].Distance(route[i])
+		total += leg
+		switch {
+		case i < o.Waypoint:
+			covered += leg
+		case i == o.Waypoint:
+			covered += max(leg-o.Convoy.Position.Distance(route[i]), 0)
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return covered / total
+}
+
+// getHostileConvoy returns the group of a convoy escorted by an army hostile to the given one, or nil
+func (bm *BattleManager) getHostileConvoy(armyID int) *Group {
+	for _, objective := range bm.Objectives {
+		convoy := objective.Convoy
+		if convoy == nil || !convoy.IsAlive || objective.Winner >= 0 || bm.AreAllied(armyID, convoy.ArmyID) {
+			continue
+		}
+		return bm.FindGroup(convoy.ArmyID, convoy.GroupID)
+	}
+	return nil
+}
+
+// ConvoyRoute returns the route points the convoy has still to pass, starting from where it is
+func (o *Objective) ConvoyRoute() []gamemath.Vector2D {
+	route := o.Config.GetPath()
+	if o.Convoy == nil || o.Waypoint >= len(route) {
+		return nil
+	}
+	return append([]gamemath.Vector2D{o.Convoy.Position}, route[o.Waypoint:]...)
+}
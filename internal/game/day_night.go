@@ -0,0 +1,66 @@
+package game
+
+import "math"
+
+// Day/night tuning
+const (
+	duskHour          = 17.0 // この時刻から暗くなり始める
+	nightHour         = 20.0 // この時刻から夜明けまで真っ暗
+	dawnHour          = 5.0  // この時刻から明るくなり始める
+	morningHour       = 7.0  // この時刻には明るくなっている
+	nightSightFactor  = 0.4  // 真夜中の知覚範囲・霧の視界の倍率
+	nightDetectFactor = 0.5  // 真夜中に潜伏ユニットに気付ける距離の倍率
+)
+
+// HasDayNight reports whether time of day passes on the stage
+func (bm *BattleManager) HasDayNight() bool {
+	return bm.Stage.DayNight != nil
+}
+
+// GetHour returns the time of day in hours (0-24) at the current battle time
+func (bm *BattleManager) GetHour() float64 {
+	if !bm.HasDayNight() {
+		return 12.0
+	}
+	config := bm.Stage.DayNight
+	hour := config.StartHour + bm.BattleTime/60.0*config.HoursPerMinute
+	return math.Mod(math.Mod(hour, 24.0)+24.0, 24.0)
+}
+
+// GetDarkness returns how dark it is, from 0 in daylight to 1 at night, easing in at dusk and out at dawn
+func (bm *BattleManager) GetDarkness() float64 {
+	if !bm.HasDayNight() {
+		return 0
+	}
+	hour := bm.GetHour()
+	switch {
+	case hour >= nightHour || hour < dawnHour:
+		return 1.0
+	case hour >= duskHour:
+		return (hour - duskHour) / (nightHour - duskHour)
+	case hour < morningHour:
+		return 1.0 - (hour-dawnHour)/(morningHour-dawnHour)
+	}
+	return 0
+}
+
+// IsNight reports whether it is more dark than light
+func (bm *BattleManager) IsNight() bool {
+	return bm.GetDarkness() >= 0.5
+}
+
+// getNightFactor blends a multiplier from 1 in daylight to the given value at night
+func (bm *BattleManager) getNightFactor(nightFactor float64) float64 {
+	return 1.0 - bm.GetDarkness()*(1.0-nightFactor)
+}
+
+// updateDayNight shortens every unit's sight as it grows dark
+func (bm *BattleManager) updateDayNight() {
+	if !bm.HasDayNight() {
+		return
+	}
+	sight := bm.getNightFactor(nightSightFactor)
+	for _, unit := range bm.getAllAliveUnits() {
+		unit.Effects.Sight = sight
+	}
+}
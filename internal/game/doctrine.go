@@ -0,0 +1,103 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// defaultPursuitRange is how close fleeing enemies must be to trigger pursuit (30m)
+const defaultPursuitRange = 300.0
+
+// StatusEffects are multipliers applied on top of a unit's stats, recomputed every tick
+type StatusEffects struct {
+	Defense float64
+	Speed   float64
+	Sight   float64
+}
+
+// NewStatusEffects returns effects that leave every stat unchanged
+func NewStatusEffects() StatusEffects {
+	return StatusEffects{Defense: 1.0, Speed: 1.0, Sight: 1.0}
+}
+
+// GetDefense returns the unit's defense with its status effects, encirclement and tower cover
+func (u *Unit) GetDefense() int {
+	multiplier := u.Effects.Defense
+	if u.Surrounded {
+		multiplier *= surroundedDefense
+	}
+	defense := int(float64(u.Defense)*multiplier + 0.5)
+	if u.Garrison != nil {
+		defense += garrisonDefenseBonus
+	}
+	return defense
+}
+
+// GetSpeed returns the unit's movement speed with its status effects, fatigue and caltrops
+func (u *Unit) GetSpeed() float64 {
+	return u.Speed * u.Effects.Speed * u.fatigueSpeed() * u.trapSpeed()
+}
+
+// SetDoctrine gives the army a passive doctrine for the rest of the battle
+func (bm *BattleManager) SetDoctrine(armyID int, doctrine data.DoctrineConfig) {
+	if army := bm.GetArmy(armyID); army != nil {
+		army.Doctrine = &doctrine
+	}
+}
+
+// pursues reports whether the army's doctrine runs down fleeing enemies
+func (a *Army) pursues() bool {
+	return a.Doctrine != nil && a.Doctrine.PursuitSpeedModifier > 0
+}
+
+// updateDoctrines refreshes the status effects every army's doctrine gives its units
+func (bm *BattleManager) updateDoctrines() {
+	for _, army := range bm.Armies {
+		doctrine := army.Doctrine
+		if doctrine == nil {
+			continue
+		}
+		
+		base := NewStatusEffects()
+		if doctrine.DefenseModifier > 0 {
+			base.Defense = doctrine.DefenseModifier
+		}
+		if doctrine.SpeedModifier > 0 {
+			base.Speed = doctrine.SpeedModifier
+		}
+		
+		var fleeing []*Unit
+		if army.pursues() {
+			fleeing = bm.getFleeingEnemyUnits(army.ID)
+		}
+		pursuitRange := doctrine.PursuitRange
+		if pursuitRange <= 0 {
+			pursuitRange = defaultPursuitRange
+		}
+		
+		for _, unit := range army.GetAliveUnits() {
+			unit.Effects = base
+			for _, enemy := range fleeing {
+				if unit.Position.Distance(enemy.Position) <= pursuitRange {
+					unit.Effects.Speed *= doctrine.PursuitSpeedModifier
+					break
+				}
+			}
+		}
+	}
+}
+
+// getFleeingEnemyUnits returns the retreating units of armies hostile to the given army
+func (bm *BattleManager) getFleeingEnemyUnits(armyID int) []*Unit {
+	var fleeing []*Unit
+	for _, army := range bm.Armies {
+		if bm.AreAllied(armyID, army.ID) {
+			continue
+		}
+		for _, unit := range army.GetAllUnits() {
+			if unit.IsAlive && unit.IsRetreating {
+				fleeing = append(fleeing, unit)
+			}
+		}
+	}
+	return fleeing
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"math"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Encirclement tuning
+const (
+	encircleEngageRange   = 60.0 // 部隊のユニットからこの距離（6m）以内の敵を交戦中とみなす
+	encircleMinSides      = 3    // 前後左右のうち、この数以上の方向から攻められると包囲
+	surroundedDefense     = 0.7  // 包囲された部隊の防御力の倍率
+	surroundedMoraleDrain = 4.0  // 包囲された部隊のユニットが1秒で失う士気
+)
+
+// Sides of a group relative to its facing
+const (
+	sideFront = iota
+	sideRight // 画面座標は y が下向きなので、正の角度は右回り
+	sideRear
+	sideLeft
+	sideCount
+)
+
+// sideOf returns which side of a group facing the given way the direction comes from
+func sideOf(facing, direction gamemath.Vector2D) int {
+	// 正面を 0 とした角度を 90 度ずつの四方向に分ける
+	angle := math.Atan2(facing.X*direction.Y-facing.Y*direction.X, facing.Dot(direction))
+	side := int(math.Floor((angle+math.Pi/4)/(math.Pi/2))) % sideCount
+	if side < 0 {
+		side += sideCount
+	}
+	return side
+}
+
+// engagedSides counts the sides of the group that enemies within reach press on
+func (g *Group) engagedSides(enemies []*Unit) int {
+	units := g.getAliveMembers()
+	if g.Leader != nil && g.Leader.IsAlive && !g.Leader.IsRetreating {
+		units = append(units, g.Leader)
+	}
+	if len(units) == 0 {
+		return 0
+	}
+	
+	center := gamemath.Vector2D{}
+	for _, unit := range units {
+		center = center.Add(unit.Position)
+	}
+	center = center.Mul(1 / float64(len(units)))
+	
+	facing := g.Facing
+	if facing.Length() == 0 {
+		facing = gamemath.Vector2D{X: 1, Y: 0}
+	}
+	
+	var pressed [sideCount]bool
+	count := 0
+	for _, enemy := range enemies {
+		for _, unit := range units {
+			if unit.Position.Distance(enemy.Position) > encircleEngageRange {
+				continue
+			}
+			if side := sideOf(facing, enemy.Position.Sub(center)); !pressed[side] {
+				pressed[side] = true
+				count++
+			}
+			break
+		}
+	}
+	return count
+}
+
+// updateEncirclement marks groups attacked from three or more sides as surrounded,
+// which weakens their defense and drains their morale
+func (bm *BattleManager) updateEncirclement(deltaTime float64) {
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		enemies := bm.GetEnemyUnits(army.ID)
+		for _, group := range army.Groups {
+			group.Surrounded = group.engagedSides(enemies) >= encircleMinSides
+			for _, unit := range group.GetAllUnits() {
+				unit.Surrounded = group.Surrounded && unit.IsAlive && !unit.IsRetreating
+				if unit.Surrounded {
+					unit.ChangeMorale(-surroundedMoraleDrain * deltaTime)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// equip gives the group's leader the items of its equipment: stat bonuses for the leader,
+// and for banners more morale for the whole group; unknown items are skipped
+func (bm *BattleManager) equip(group *Group, equipment data.Equipment, dataManager *data.DataManager) {
+	if group == nil || group.Leader == nil || dataManager == nil {
+		return
+	}
+	
+	leader := group.Leader
+	for _, id := range equipment.Items() {
+		item, err := dataManager.GetItemConfig(id)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		
+		leader.Items = append(leader.Items, id)
+		leader.MaxHP = max(leader.MaxHP+item.HP, 1)
+		leader.HP = leader.MaxHP
+		leader.AttackPower = max(leader.AttackPower+item.Attack, 0)
+		leader.MagicPower = max(leader.MagicPower+item.Magic, 0)
+		leader.Defense = max(leader.Defense+item.Defense, 0)
+		if item.SpeedModifier > 0 {
+			leader.Speed *= item.SpeedModifier
+		}
+		leader.Regeneration += item.Regeneration
+		
+		if item.Morale != 0 {
+			for _, unit := range group.GetAllUnits() {
+				unit.MaxMorale = max(unit.MaxMorale+item.Morale, 1)
+				unit.Morale = unit.MaxMorale
+			}
+		}
+	}
+}
+
+// regenerate heals the unit by its regeneration, carrying the fraction of a hit point over to the next tick
+func (u *Unit) regenerate(deltaTime float64) {
+	if u.Regeneration <= 0 || u.HP >= u.MaxHP {
+		u.regenerated = 0
+		return
+	}
+	u.regenerated += u.Regeneration * deltaTime
+	if whole := int(u.regenerated); whole > 0 {
+		u.Heal(whole)
+		u.regenerated -= float64(whole)
+	}
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Facing and approach tuning
+const (
+	retreatDistance  = 2000.0 // 退却で正面と逆向きに走る距離（戦場の外を目指す）
+	approachDistance = 600.0  // 開戦時に前進する距離
+)
+
+// deploymentFacing returns the front the army deploys facing: the stage's direction,
+// otherwise towards the nearest hostile army's deployment, otherwise east
+func (bm *BattleManager) deploymentFacing(armyID int) gamemath.Vector2D {
+	config := bm.armyConfigs[armyID]
+	if config.Facing.IsSet() {
+		return config.Facing.ToVector2D()
+	}
+	
+	own := config.GetDeploymentPoints()
+	if len(own) == 0 {
+		return gamemath.Vector2D{X: 1}
+	}
+	home := centroid(own)
+	front := gamemath.Vector2D{X: 1}
+	nearest := -1.0
+	for i, other := range bm.armyConfigs {
+		points := other.GetDeploymentPoints()
+		if bm.AreAllied(armyID, i) || len(points) == 0 {
+			continue
+		}
+		enemy := centroid(points)
+		if distance := enemy.Distance(home); distance > 0 && (nearest < 0 || distance < nearest) {
+			front = enemy.Sub(home).Normalize()
+			nearest = distance
+		}
+	}
+	return front
+}
+
+// armyFacing returns the front of the army; neutral creatures face east
+func (bm *BattleManager) armyFacing(armyID int) gamemath.Vector2D {
+	if army := bm.GetArmy(armyID); army != nil {
+		return army.Facing
+	}
+	return gamemath.Vector2D{X: 1}
+}
+
+// retreatPoint returns the exit point a unit flees to, behind it as seen from its army's front
+func retreatPoint(position, facing gamemath.Vector2D) gamemath.Vector2D {
+	return position.Sub(facing.Mul(retreatDistance))
+}
+
+// approachPoint returns where a group at the position advances to at the start, kept on the battlefield
+func (bm *BattleManager) approachPoint(position, approach gamemath.Vector2D) gamemath.Vector2D {
+	width, height := bm.getWorldSize()
+	point := position.Add(approach.Mul(approachDistance))
+	point.X = min(max(point.X, 0), width)
+	point.Y = min(max(point.Y, 0), height)
+	return point
+}
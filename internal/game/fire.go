@@ -0,0 +1,121 @@
+package game
+
+import (
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Fire tuning
+const (
+	fireBurnTime        = 10.0 // 1マスが燃え尽きるまでの秒数
+	fireSpreadDelay     = 3.0  // 燃え始めてから隣の森・藪のマスへ燃え移るまでの秒数
+	fireDamage          = 5    // 燃えているマスにいる地上ユニットが毎秒受けるダメージ（防御無視）
+	burntGroundMovement = 1.0  // 焼け野原の移動速度の倍率（下草が焼けて歩きやすくなる）
+)
+
+// fireNeighbours are the cells fire spreads to from a burning cell
+var fireNeighbours = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// IsBurning reports whether the cell at the position is on fire
+func (tg *TerrainGrid) IsBurning(position gamemath.Vector2D) bool {
+	col, row := tg.cellAt(position)
+	return tg.Fire[row*tg.Cols+col] > 0
+}
+
+// isFlammable reports whether the cell is forest or thicket that has not burnt yet and is not burning
+func (tg *TerrainGrid) isFlammable(index int) bool {
+	return tg.Cover[index] && !tg.Burnt[index] && tg.Fire[index] == 0
+}
+
+// Ignite sets the forest or thicket at the position on fire, reporting whether a new fire started
+func (tg *TerrainGrid) Ignite(position gamemath.Vector2D) bool {
+	col, row := tg.cellAt(position)
+	index := row*tg.Cols + col
+	if !tg.isFlammable(index) {
+		return false
+	}
+	tg.Fire[index] = fireBurnTime
+	return true
+}
+
+// isAblaze reports whether any cell is burning
+func (tg *TerrainGrid) isAblaze() bool {
+	for _, fire := range tg.Fire {
+		if fire > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFire burns the cells down, spreading to flammable neighbours once a cell has burned for a while,
+// and reports whether any cell burnt out into burnt ground
+func (tg *TerrainGrid) updateFire(deltaTime float64) bool {
+	spreadAt := fireBurnTime - fireSpreadDelay
+	var spreading []int
+	burntOut := false
+	for index, fire := range tg.Fire {
+		if fire <= 0 {
+			continue
+		}
+		
+		remaining := fire - deltaTime
+		if fire > spreadAt && remaining <= spreadAt {
+			spreading = append(spreading, index)
+		}
+		if remaining <= 0 {
+			remaining = 0
+			tg.Burnt[index] = true
+			burntOut = true
+		}
+		tg.Fire[index] = remaining
+	}
+	
+	// Cells caught this tick start burning from the next one
+	for _, index := range spreading {
+		col, row := index%tg.Cols, index/tg.Cols
+		for _, offset := range fireNeighbours {
+			nextCol, nextRow := col+offset[0], row+offset[1]
+			if nextCol < 0 || nextCol >= tg.Cols || nextRow < 0 || nextRow >= tg.Rows {
+				continue
+			}
+			if next := nextRow*tg.Cols + nextCol; tg.isFlammable(next) {
+				tg.Fire[next] = fireBurnTime
+			}
+		}
+	}
+	return burntOut
+}
+
+// igniteWithSpell lets a mage's spell set the forest or thicket where it strikes on fire
+func (bm *BattleManager) igniteWithSpell(mage *Unit, position gamemath.Vector2D) {
+	ablaze := bm.Terrain.isAblaze()
+	if !bm.Terrain.Ignite(position) {
+		return
+	}
+	bm.recordEvent(BattleEvent{Type: EventIgnite, UnitID: mage.ID, GroupID: mage.GroupID})
+	if !ablaze {
+		bm.Announce("森に火の手が上がった")
+	}
+}
+
+// updateFire spreads and burns out fires, reopening burnt ground, and scorches ground units
+// standing in burning cells once per second
+func (bm *BattleManager) updateFire(deltaTime float64) {
+	if bm.Terrain.updateFire(deltaTime) {
+		bm.Terrain.refreshMovement()
+		bm.flowFields = make(map[int]*FlowField)
+	}
+	
+	// Whole seconds follow the battle timer, so rewinding and replays burn alike
+	if int(bm.BattleTime) == int(bm.BattleTime-deltaTime) {
+		return
+	}
+	for _, unit := range bm.getAllAliveUnits() {
+		if unit.Flying || unit.Garrison != nil || !bm.Terrain.IsBurning(unit.Position) {
+			continue
+		}
+		unit.TakeDamage(fireDamage)
+		bm.recordEvent(BattleEvent{Type: EventBurn, UnitID: unit.ID, GroupID: unit.GroupID, Amount: fireDamage})
+		bm.Heatmap.add(HeatmapDamage, unit.Position, float64(fireDamage))
+	}
+}
@@ -0,0 +1,229 @@
+package game
+
+import (
+	"container/heap"
+	"math"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Flow field tuning
+const (
+	flowFieldMinUnits    = 20              // この人数以上の部隊は全員が流れ場に沿って移動
+	flowFieldExpiry      = 5.0             // 使われなくなった流れ場を破棄するまでの秒数
+	flowFieldDirectRange = terrainCellSize // 目標地点にこの距離まで近づいたら直進
+	flowFieldTargetSlack = 150.0           // 目的地からこの距離内の目標（隊形の位置）にも流れ場を使う
+)
+
+// flowNeighbors are the eight cell offsets a flow field steps through
+var flowNeighbors = [8][2]int{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// FlowField points every cell of the terrain grid along the cheapest route to one destination
+// It is shared by every unit heading to the destination, so its cost does not grow with unit count
+type FlowField struct {
+	Destination gamemath.Vector2D
+	Cost        []float64           // 目的地までの移動コスト（到達不能: +Inf）
+	Direction   []gamemath.Vector2D // 各マスで進む向き
+	
+	grid     *TerrainGrid
+	lastUsed float64
+}
+
+// NewFlowField integrates the terrain costs outward from the destination
+func NewFlowField(grid *TerrainGrid, destination gamemath.Vector2D) *FlowField {
+	cells := grid.Cols * grid.Rows
+	ff := &FlowField{
+		Destination: destination,
+		Cost:        make([]float64, cells),
+		Direction:   make([]gamemath.Vector2D, cells),
+		grid:        grid,
+	}
+	for i := range ff.Cost {
+		ff.Cost[i] = math.Inf(1)
+	}
+	
+	// Dijkstra from the destination cell; entering a cell costs its distance over its speed
+	col, row := grid.cellAt(destination)
+	start := row*grid.Cols + col
+	ff.Cost[start] = 0
+	queue := &flowQueue{{cell: start}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(flowQueueItem)
+		if current.cost > ff.Cost[current.cell] {
+			continue
+		}
+		
+		c, r := current.cell%grid.Cols, current.cell/grid.Cols
+		for _, offset := range flowNeighbors {
+			nc, nr := c+offset[0], r+offset[1]
+			if !ff.canStep(c, r, nc, nr) {
+				continue
+			}
+			
+			next := nr*grid.Cols + nc
+			step := math.Hypot(float64(offset[0]), float64(offset[1])) * terrainCellSize / grid.Movement[next]
+			if cost := current.cost + step; cost < ff.Cost[next] {
+				ff.Cost[next] = cost
+				heap.Push(queue, flowQueueItem{cell: next, cost: cost})
+			}
+		}
+	}
+	
+	// Each cell points to its cheapest neighbour
+	for cell := range ff.Direction {
+		c, r := cell%grid.Cols, cell/grid.Cols
+		best := ff.Cost[cell]
+		for _, offset := range flowNeighbors {
+			nc, nr := c+offset[0], r+offset[1]
+			if !ff.canStep(c, r, nc, nr) {
+				continue
+			}
+			if cost := ff.Cost[nr*grid.Cols+nc]; cost < best {
+				best = cost
+				ff.Direction[cell] = gamemath.Vector2D{X: float64(offset[0]), Y: float64(offset[1])}.Normalize()
+			}
+		}
+	}
+	return ff
+}
+
+// canStep reports whether a unit can move between two neighbouring cells
+// Diagonal steps may not cut the corner of an impassable cell
+func (ff *FlowField) canStep(col, row, nextCol, nextRow int) bool {
+	if !ff.grid.isCellPassable(nextCol, nextRow) {
+		return false
+	}
+	if col != nextCol && row != nextRow {
+		return ff.grid.isCellPassable(nextCol, row) && ff.grid.isCellPassable(col, nextRow)
+	}
+	return true
+}
+
+// GetDirection returns the direction to move at the position, blending the surrounding cells
+// so units do not zigzag along cell edges; false when the destination cannot be reached
+func (ff *FlowField) GetDirection(position gamemath.Vector2D) (gamemath.Vector2D, bool) {
+	grid := ff.grid
+	col, row := grid.cellAt(position)
+	if math.IsInf(ff.Cost[row*grid.Cols+col], 1) {
+		return gamemath.Vector2D{}, false
+	}
+	
+	// Bilinear weights of the four cells whose centers surround the position
+	fx := position.X/terrainCellSize - 0.5
+	fy := position.Y/terrainCellSize - 0.5
+	c0, r0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(c0), fy-float64(r0)
+	
+	var blended gamemath.Vector2D
+	for _, corner := range [4]struct {
+		dc, dr int
+		weight float64
+	}{
+		{0, 0, (1 - tx) * (1 - ty)},
+		{1, 0, tx * (1 - ty)},
+		{0, 1, (1 - tx) * ty},
+		{1, 1, tx * ty},
+	} {
+		c, r := c0+corner.dc, r0+corner.dr
+		if !grid.isCellPassable(c, r) || math.IsInf(ff.Cost[r*grid.Cols+c], 1) {
+			continue
+		}
+		blended = blended.Add(ff.Direction[r*grid.Cols+c].Mul(corner.weight))
+	}
+	
+	if blended.Length() == 0 {
+		blended = ff.Direction[row*grid.Cols+col]
+	}
+	if blended.Length() == 0 {
+		return gamemath.Vector2D{}, false
+	}
+	return blended.Normalize(), true
+}
+
+// Covers reports whether a unit heading to the target can follow the field
+func (ff *FlowField) Covers(target gamemath.Vector2D) bool {
+	return ff.Destination.Distance(target) <= flowFieldTargetSlack
+}
+
+// flowQueueItem is a cell waiting in the Dijkstra queue
+type flowQueueItem struct {
+	cell int
+	cost float64
+}
+
+// flowQueue is a min-heap of cells ordered by cost
+type flowQueue []flowQueueItem
+
+func (q flowQueue) Len() int           { return len(q) }
+func (q flowQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q flowQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *flowQueue) Push(x interface{}) {
+	*q = append(*q, x.(flowQueueItem))
+}
+
+func (q *flowQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// GetFlowField returns the shared flow field to the destination, building it on first use
+// Destinations in the same terrain cell share one field
+func (bm *BattleManager) GetFlowField(destination gamemath.Vector2D) *FlowField {
+	col, row := bm.Terrain.cellAt(destination)
+	key := row*bm.Terrain.Cols + col
+	
+	field, exists := bm.flowFields[key]
+	if !exists {
+		field = NewFlowField(bm.Terrain, bm.Terrain.cellCenter(col, row))
+		bm.flowFields[key] = field
+	}
+	field.lastUsed = bm.BattleTime
+	return field
+}
+
+// updateFlowFields routes groups whose way is blocked by terrain along shared flow fields
+// Large groups move every unit along the field; smaller ones only the leader, the members follow
+func (bm *BattleManager) updateFlowFields() {
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, group := range army.Groups {
+			bm.updateGroupFlowField(group)
+		}
+	}
+	
+	// Drop fields no group has used for a while
+	for key, field := range bm.flowFields {
+		if bm.BattleTime-field.lastUsed > flowFieldExpiry {
+			delete(bm.flowFields, key)
+		}
+	}
+}
+
+// updateGroupFlowField picks the flow field of a group and hands it to its units
+func (bm *BattleManager) updateGroupFlowField(group *Group) {
+	leader := group.Leader
+	var field *FlowField
+	if leader != nil && leader.IsAlive && !leader.Flying && !bm.Terrain.IsUniform() && leader.Position.Distance(leader.Target) > flowFieldDirectRange {
+		// Keep following the current field until the destination changes
+		if group.flowField != nil && group.flowField.Covers(leader.Target) {
+			field = bm.GetFlowField(group.flowField.Destination)
+		} else if !bm.Terrain.IsLineClear(leader.Position, leader.Target) {
+			field = bm.GetFlowField(leader.Target)
+		}
+	}
+	group.flowField = field
+	group.routeShared = field != nil && group.GetAliveCount() >= flowFieldMinUnits
+	
+	for _, unit := range group.GetAllUnits() {
+		if unit == leader || group.routeShared {
+			unit.FlowField = field
+		} else {
+			unit.FlowField = nil
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/math"
+)
+
+// Garrison tuning
+const (
+	garrisonCapacity       = 6     // 櫓に入れるユニット数（ステージで指定がないとき）
+	garrisonEnterRange     = 30.0  // 櫓の外壁からこの距離（3m）以内に来たユニットが入る
+	garrisonRangeBonus     = 150.0 // 櫓の上からの射程の延長（15m）
+	garrisonDefenseBonus   = 8     // 櫓の中での防御力の上乗せ
+	garrisonExitGap        = 10.0  // 櫓を出たユニットを外壁から離す距離（1m）
+	garrisonCollapseDamage = 20    // 崩れた櫓から投げ出されたときのダメージ
+	garrisonCollapseStun   = 1.5   // 崩れた櫓から投げ出されたユニットが気絶する秒数
+	garrisonTargetPenalty  = 80.0  // AIが櫓の中の敵を狙うときのスコアの減点
+)
+
+// CanGarrison reports whether the unit is the kind of troop that mans towers
+func (u *Unit) CanGarrison() bool {
+	if !u.IsAlive || u.IsRetreating || u.IsSiegeEngine() {
+		return false
+	}
+	switch u.Type {
+	case UnitTypeInfantry, UnitTypeArcher, "heavy_infantry":
+		return true
+	}
+	return false
+}
+
+// enterGarrison puts the unit inside the tower, where it stands still and shoots farther
+func (u *Unit) enterGarrison(tower *Structure) {
+	u.Garrison = tower
+	u.Position = tower.Center()
+	u.Target = u.Position
+	u.Velocity = math.Vector2D{}
+	u.Range += garrisonRangeBonus
+}
+
+// leaveGarrison puts the unit back on open ground just outside its tower
+func (u *Unit) leaveGarrison() {
+	tower := u.Garrison
+	if tower == nil {
+		return
+	}
+	
+	u.Garrison = nil
+	u.Range -= garrisonRangeBonus
+	u.Position = tower.exitPoint(u.Terrain, u.ArmyID, u.GetCollisionRadius())
+	u.Target = u.Position
+}
+
+// exitPoint returns a spot just outside the structure, preferring sides the army can stand on
+func (s *Structure) exitPoint(terrain *TerrainGrid, armyID int, radius float64) math.Vector2D {
+	center := s.Center()
+	gap := radius + garrisonExitGap
+	candidates := []math.Vector2D{
+		{X: center.X, Y: s.Max.Y + gap},
+		{X: center.X, Y: s.Min.Y - gap},
+		{X: s.Min.X - gap, Y: center.Y},
+		{X: s.Max.X + gap, Y: center.Y},
+	}
+	for _, candidate := range candidates {
+		if terrain == nil || terrain.isOpenTo(candidate, armyID) {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
+
+// CanGarrison reports whether units of the army may enter the structure
+func (bm *BattleManager) CanGarrison(structure *Structure, armyID int) bool {
+	if structure.IsDestroyed || structure.Kind != data.StructureTower || structure.Capacity <= 0 {
+		return false
+	}
+	return structure.ArmyID != NeutralArmyID && bm.AreAllied(armyID, structure.ArmyID)
+}
+
+// GetGarrison returns the living units inside the structure
+func (bm *BattleManager) GetGarrison(structure *Structure) []*Unit {
+	var garrison []*Unit
+	for _, army := range bm.Armies {
+		for _, unit := range army.GetAliveUnits() {
+			if unit.Garrison == structure {
+				garrison = append(garrison, unit)
+			}
+		}
+	}
+	return garrison
+}
+
+// GetStructureAt returns the standing structure at the position, or nil
+func (bm *BattleManager) GetStructureAt(position math.Vector2D) *Structure {
+	for _, structure := range bm.Structures {
+		if !structure.IsDestroyed && structure.Contains(position) {
+			return structure
+		}
+	}
+	return nil
+}
+
+// getStructure returns the structure with the given ID, or nil
+func (bm *BattleManager) getStructure(id int) *Structure {
+	for _, structure := range bm.Structures {
+		if structure.ID == id {
+			return structure
+		}
+	}
+	return nil
+}
+
+// updateGarrisons lets groups ordered into a tower enter it once they reach its walls
+// The order ends when every member able to enter is inside or the tower is full
+func (bm *BattleManager) updateGarrisons() {
+	for _, army := range bm.Armies {
+		for _, group := range army.Groups {
+			order := group.CurrentOrder
+			if order == nil || order.Type != OrderGarrison {
+				continue
+			}
+			
+			tower := bm.getStructure(order.StructureID)
+			if tower == nil || !bm.CanGarrison(tower, army.ID) {
+				group.CurrentOrder = nil
+				continue
+			}
+			
+			room := tower.Capacity - len(bm.GetGarrison(tower))
+			waiting := 0
+			for _, unit := range group.GetAllUnits() {
+				if unit.Garrison != nil || !unit.CanGarrison() {
+					continue
+				}
+				if room > 0 && tower.DistanceTo(unit.Position) <= garrisonEnterRange+unit.GetCollisionRadius() {
+					unit.enterGarrison(tower)
+					room--
+					continue
+				}
+				waiting++
+			}
+			if waiting == 0 || room <= 0 {
+				group.CurrentOrder = nil
+			}
+		}
+	}
+}
+
+// ejectGroup brings every member of the group out of its tower
+func ejectGroup(group *Group) {
+	for _, unit := range group.GetAllUnits() {
+		unit.leaveGarrison()
+	}
+}
+
+// collapseGarrison throws the units out of a destroyed tower, hurting them in the fall
+func (bm *BattleManager) collapseGarrison(structure *Structure) {
+	for _, unit := range bm.GetGarrison(structure) {
+		unit.leaveGarrison()
+		unit.TakeDamage(garrisonCollapseDamage)
+		unit.Stun(garrisonCollapseStun)
+	}
+}
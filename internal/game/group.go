@@ -28,13 +28,46 @@ type Group struct {
 	Members   []*Unit
 	Formation Formation
 	ArmyID    int
+	Facing    gamemath.Vector2D // 軍勢の正面（退却はその逆へ）
+	
+	// Player order being executed (nil: AI controlled)
+	CurrentOrder *Order
+	
+	// Co-op player commanding the group (0: any player)
+	Controller int
+	
+	// Objective assigned by the army commander (nil: free engagement)
+	Objective *GroupObjective
+	
+	// Enemy the leader designated for the whole group to focus on
+	FocusTarget *Unit
 	
 	// Formation state
 	targetPosition gamemath.Vector2D
+	
+	// Route around terrain (nil: straight to the target)
+	flowField   *FlowField
+	routeShared bool // 全員が流れ場に沿う（false: リーダーのみ）
+	
+	// Average stamina is low: members only keep loose formation
+	fatigued bool
+	
+	// Orders reach the members some time after the leader
+	commandDelay bool
+	
+	// Enemies press on three or more sides of the group
+	Surrounded bool
 }
 
 // NewGroup creates a new group
 func NewGroup(id, armyID int, leader *Unit, members []*Unit) *Group {
+	// Members' behavior trees follow and protect the leader
+	for _, member := range members {
+		if member.AI != nil {
+			member.AI.Leader = leader
+		}
+	}
+	
 	return &Group{
 		ID:      id,
 		Leader:  leader,
@@ -59,16 +92,21 @@ func (g *Group) Update(deltaTime float64) {
 	// Update leader first
 	g.Leader.Update(deltaTime)
 	
+	// Share the leader's target so members concentrate their attacks
+	g.updateFocusTarget()
+	
 	// Update formation target based on leader position
 	// リーダーが移動中の場合は目標位置、そうでなければ現在位置を使用
-	if g.Leader.Position.Distance(g.Leader.Target) > 5.0 {
+	// リーダーだけが流れ場で迂回する小部隊は、リーダーの周りに隊形を組んで追従する
+	followLeader := g.flowField != nil && !g.routeShared
+	if g.Leader.Position.Distance(g.Leader.Target) > 5.0 && !followLeader {
 		g.targetPosition = g.Leader.Target
 	} else {
 		g.targetPosition = g.Leader.Position
 	}
 	
 	// Update members and maintain formation
-	g.updateFormation()
+	g.updateFormation(deltaTime)
 	
 	// Update all members
 	for _, member := range g.Members {
@@ -79,19 +117,19 @@ func (g *Group) Update(deltaTime float64) {
 }
 
 // updateFormation maintains the group's formation
-func (g *Group) updateFormation() {
+func (g *Group) updateFormation(deltaTime float64) {
 	if g.Leader == nil || !g.Leader.IsAlive {
 		return
 	}
 	
 	switch g.Formation.Type {
 	case CircleFormation:
-		g.updateCircleFormation()
+		g.updateCircleFormation(deltaTime)
 	}
 }
 
 // updateCircleFormation arranges members in a circle around the leader
-func (g *Group) updateCircleFormation() {
+func (g *Group) updateCircleFormation(deltaTime float64) {
 	aliveMembers := g.getAliveMembers()
 	if len(aliveMembers) == 0 {
 		return
@@ -100,7 +138,7 @@ func (g *Group) updateCircleFormation() {
 	angleStep := 2 * math.Pi / float64(len(aliveMembers))
 	
 	for i, member := range aliveMembers {
-		if member.IsRetreating {
+		if member.IsRetreating || member.Resupplying {
 			continue
 		}
 		
@@ -108,11 +146,17 @@ func (g *Group) updateCircleFormation() {
 		offsetX := math.Cos(angle) * g.Formation.Radius
 		offsetY := math.Sin(angle) * g.Formation.Radius
 		
-		formationPos := g.targetPosition.Add(gamemath.Vector2D{
+		formationPos := g.relayedTarget(member, deltaTime).Add(gamemath.Vector2D{
 			X: offsetX,
 			Y: offsetY,
 		})
 		
+		// Tired members catch their breath instead of shuffling into exact slots
+		if g.fatigued && member.Position.Distance(formationPos) <= fatiguedSlotSlack {
+			member.MoveTo(member.Position)
+			continue
+		}
+		
 		member.MoveTo(formationPos)
 	}
 }
@@ -133,12 +177,38 @@ func (g *Group) handleLeaderDeath() {
 	// Make all members retreat
 	for _, member := range g.Members {
 		if member.IsAlive && !member.IsRetreating {
-			// Set retreat target to screen edge (simplified)
-			exitPoint := gamemath.Vector2D{X: -100, Y: member.Position.Y}
-			if member.ArmyID == 1 { // Army B retreats to right
-				exitPoint.X = 1124 // Screen width + 100
-			}
-			member.StartRetreating(exitPoint)
+			member.StartRetreating(retreatPoint(member.Position, g.Facing))
+		}
+	}
+}
+
+// SetObjective assigns a commander objective, which the leader passes on to its members
+func (g *Group) SetObjective(objective *GroupObjective) {
+	g.Objective = objective
+	for _, unit := range g.GetAllUnits() {
+		if unit.AI != nil {
+			unit.AI.Objective = objective
+		}
+	}
+}
+
+// updateFocusTarget designates the leader's current target for the members
+func (g *Group) updateFocusTarget() {
+	var target *Unit
+	if g.Leader.AI != nil && !g.Leader.IsRetreating {
+		target = g.Leader.AI.TargetEnemy
+	}
+	if target != nil && (!target.IsAlive || target.IsRetreating) {
+		target = nil
+	}
+	if target == g.FocusTarget {
+		return
+	}
+	
+	g.FocusTarget = target
+	for _, member := range g.Members {
+		if member.AI != nil {
+			member.AI.FocusTarget = target
 		}
 	}
 }
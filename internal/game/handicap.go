@@ -0,0 +1,59 @@
+package game
+
+import "math"
+
+// Handicap scales the units an army fields so that mismatched presets can still make a close fight
+// Each field is a multiplier; 1.0 leaves the stat as the preset has it
+type Handicap struct {
+	HP     float64
+	Attack float64 // 攻撃力と魔力
+	Count  float64 // 部隊ごとの兵数（リーダーを除く）
+}
+
+// NewHandicap returns a handicap that changes nothing
+func NewHandicap() Handicap {
+	return Handicap{HP: 1.0, Attack: 1.0, Count: 1.0}
+}
+
+// SetHandicap scales the units the army fields; call it before CreateArmies
+// Units the army gets later, such as reinforcements and summons, are scaled alike
+func (bm *BattleManager) SetHandicap(armyID int, handicap Handicap) {
+	if armyID < 0 || armyID >= len(bm.armyConfigs) {
+		return
+	}
+	
+	if bm.handicaps == nil {
+		bm.handicaps = make(map[int]Handicap)
+	}
+	bm.handicaps[armyID] = handicap
+}
+
+// GetHandicap returns the handicap of the army; armies without one get NewHandicap
+func (bm *BattleManager) GetHandicap(armyID int) Handicap {
+	if handicap, ok := bm.handicaps[armyID]; ok {
+		return handicap
+	}
+	return NewHandicap()
+}
+
+// handicapCount scales the member count of a group the army creates, keeping at least one member
+func (bm *BattleManager) handicapCount(armyID, memberCount int) int {
+	if memberCount <= 0 {
+		return memberCount
+	}
+	scaled := int(math.Round(float64(memberCount) * bm.GetHandicap(armyID).Count))
+	return max(scaled, 1)
+}
+
+// handicapUnit scales the hit points and attack of a newly created unit by its army's handicap
+func (bm *BattleManager) handicapUnit(unit *Unit) {
+	handicap := bm.GetHandicap(unit.ArmyID)
+	if handicap == NewHandicap() {
+		return
+	}
+	
+	unit.MaxHP = max(int(math.Round(float64(unit.MaxHP)*handicap.HP)), 1)
+	unit.HP = max(int(math.Round(float64(unit.HP)*handicap.HP)), 1)
+	unit.AttackPower = int(math.Round(float64(unit.AttackPower) * handicap.Attack))
+	unit.MagicPower = int(math.Round(float64(unit.MagicPower) * handicap.Attack))
+}
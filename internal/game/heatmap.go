@@ -0,0 +1,176 @@
+package game
+
+import (
+	"math"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Heatmap tuning
+const (
+	heatmapCellSize       = 100.0 // 1マス10m四方
+	heatmapSampleInterval = 1.0   // 移動密度を記録する間隔（秒）
+	heatmapMovingSpeed    = 1.0   // これより速く動いているユニットを移動中とみなす
+	heatmapRecentHalfLife = 3.0   // 直近のダメージが半分に薄れる秒数（見どころ探し用）
+)
+
+// HeatmapLayer represents a statistic recorded on the battle heatmap
+type HeatmapLayer int
+
+const (
+	HeatmapDeaths   HeatmapLayer = iota // 戦死
+	HeatmapDamage                       // 与えたダメージ（命中地点）
+	HeatmapMovement                     // 移動密度
+	heatmapLayerCount
+)
+
+// HeatmapLayers lists every layer in display order
+var HeatmapLayers = []HeatmapLayer{HeatmapDeaths, HeatmapDamage, HeatmapMovement}
+
+// String returns the layer's display name
+func (l HeatmapLayer) String() string {
+	switch l {
+	case HeatmapDeaths:
+		return "戦死"
+	case HeatmapDamage:
+		return "ダメージ"
+	case HeatmapMovement:
+		return "移動"
+	default:
+		return "?"
+	}
+}
+
+// BattleHeatmap accumulates where units died, took damage and moved over the whole battle
+// It also keeps the damage of the last few seconds, fading over time, to find where the fighting is now
+type BattleHeatmap struct {
+	Cols, Rows int
+	
+	values      [heatmapLayerCount][]float64
+	recent      []float64 // 直近のダメージ（heatmapRecentHalfLife 秒で半減）
+	sinceSample float64
+}
+
+// NewBattleHeatmap creates an empty heatmap covering a world of the given size
+func NewBattleHeatmap(width, height float64) *BattleHeatmap {
+	if width <= 0 {
+		width = defaultWorldSize
+	}
+	if height <= 0 {
+		height = defaultWorldSize
+	}
+	
+	hm := &BattleHeatmap{
+		Cols: int(math.Ceil(width / heatmapCellSize)),
+		Rows: int(math.Ceil(height / heatmapCellSize)),
+	}
+	for i := range hm.values {
+		hm.values[i] = make([]float64, hm.Cols*hm.Rows)
+	}
+	hm.recent = make([]float64, hm.Cols*hm.Rows)
+	return hm
+}
+
+// add accumulates an amount in the cell containing the position
+func (hm *BattleHeatmap) add(layer HeatmapLayer, position gamemath.Vector2D, amount float64) {
+	col := int(math.Floor(position.X / heatmapCellSize))
+	row := int(math.Floor(position.Y / heatmapCellSize))
+	if col < 0 || col >= hm.Cols || row < 0 || row >= hm.Rows {
+		return
+	}
+	hm.values[layer][row*hm.Cols+col] += amount
+	if layer == HeatmapDamage {
+		hm.recent[row*hm.Cols+col] += amount
+	}
+}
+
+// Get returns the accumulated value of a cell
+func (hm *BattleHeatmap) Get(layer HeatmapLayer, col, row int) float64 {
+	return hm.values[layer][row*hm.Cols+col]
+}
+
+// Max returns the highest cell value of the layer
+func (hm *BattleHeatmap) Max(layer HeatmapLayer) float64 {
+	highest := 0.0
+	for _, value := range hm.values[layer] {
+		highest = max(highest, value)
+	}
+	return highest
+}
+
+// Hotspot returns where the fiercest recent fighting is: the damage-weighted center of the cells around
+// the 3x3 block with the most recent damage, how far that damage spreads from it, and the block's damage
+// (0 when no one has been hit lately)
+func (hm *BattleHeatmap) Hotspot() (center gamemath.Vector2D, spread, intensity float64) {
+	bestCol, bestRow := 0, 0
+	for row := 0; row < hm.Rows; row++ {
+		for col := 0; col < hm.Cols; col++ {
+			if sum := hm.recentAround(col, row, 1); sum > intensity {
+				bestCol, bestRow, intensity = col, row, sum
+			}
+		}
+	}
+	if intensity <= 0 {
+		return center, 0, 0
+	}
+	
+	// Weigh the surrounding 5x5 cells to settle between neighbouring fights
+	left, right := max(bestCol-2, 0), min(bestCol+2, hm.Cols-1)
+	top, bottom := max(bestRow-2, 0), min(bestRow+2, hm.Rows-1)
+	total := 0.0
+	for row := top; row <= bottom; row++ {
+		for col := left; col <= right; col++ {
+			weight := hm.recent[row*hm.Cols+col]
+			center = center.Add(cellCenter(col, row).Mul(weight))
+			total += weight
+		}
+	}
+	center = center.Mul(1 / total)
+	
+	variance := 0.0
+	for row := top; row <= bottom; row++ {
+		for col := left; col <= right; col++ {
+			distance := cellCenter(col, row).Distance(center)
+			variance += hm.recent[row*hm.Cols+col] * distance * distance
+		}
+	}
+	return center, math.Sqrt(variance / total), intensity
+}
+
+// cellCenter returns the world position of the middle of a heatmap cell
+func cellCenter(col, row int) gamemath.Vector2D {
+	return gamemath.NewVector2D((float64(col)+0.5)*heatmapCellSize, (float64(row)+0.5)*heatmapCellSize)
+}
+
+// recentAround sums the recent damage of the cells within the given number of cells of one
+func (hm *BattleHeatmap) recentAround(col, row, cells int) float64 {
+	sum := 0.0
+	for r := max(row-cells, 0); r <= min(row+cells, hm.Rows-1); r++ {
+		for c := max(col-cells, 0); c <= min(col+cells, hm.Cols-1); c++ {
+			sum += hm.recent[r*hm.Cols+c]
+		}
+	}
+	return sum
+}
+
+// updateHeatmap fades the recent damage and samples the positions of moving units
+func (bm *BattleManager) updateHeatmap(deltaTime float64) {
+	fade := math.Pow(0.5, deltaTime/heatmapRecentHalfLife)
+	for i := range bm.Heatmap.recent {
+		bm.Heatmap.recent[i] *= fade
+	}
+	
+	bm.Heatmap.sinceSample += deltaTime
+	if bm.Heatmap.sinceSample < heatmapSampleInterval {
+		return
+	}
+	bm.Heatmap.sinceSample = 0
+	
+	for _, army := range bm.Armies {
+		for _, unit := range army.GetAliveUnits() {
+			if unit.Velocity.Length() > heatmapMovingSpeed {
+				bm.Heatmap.add(HeatmapMovement, unit.Position, 1)
+			}
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Highlight tuning
+const (
+	multiKillCount    = 3    // この数以上を続けて倒すと連続撃破
+	multiKillWindow   = 6.0  // 撃破がこの秒数以内に続けば連続撃破に数える
+	killCreditWindow  = 3.0  // 最後の命中からこの秒数以内の戦死を、命中させたユニットの撃破とする
+	duelWindow        = 10.0 // 討たれたリーダーがこの秒数以内に相手のリーダーを打っていれば一騎打ち
+	highlightPreroll  = 4.0  // 見どころの前から再生する秒数
+	highlightPostroll = 2.0  // 見どころの後まで再生する秒数
+	highlightInterval = 0.05 // 見どころの場面を残す間隔（戦闘時間の秒数）
+	maxHighlights     = 5    // 残す見どころの数（超えたら点の低いものから捨てる）
+)
+
+// HighlightKind represents what made a moment of the battle worth watching again
+type HighlightKind int
+
+const (
+	HighlightMultiKill     HighlightKind = iota // 1体のユニットの連続撃破
+	HighlightLeaderDuel                         // リーダー同士の一騎打ち
+	HighlightObjectiveFlip                      // 拠点の奪取
+)
+
+// String returns the display name of the highlight kind
+func (k HighlightKind) String() string {
+	switch k {
+	case HighlightMultiKill:
+		return "連続撃破"
+	case HighlightLeaderDuel:
+		return "一騎打ち"
+	case HighlightObjectiveFlip:
+		return "拠点奪取"
+	default:
+		return "?"
+	}
+}
+
+// Highlight is one notable moment of the battle
+type Highlight struct {
+	Kind     HighlightKind
+	Time     float64           // 見どころの瞬間の戦闘時間
+	UnitID   int               // カメラが追うユニット（0: 地点を映す）
+	OtherID  int               // 一騎打ちで討たれたリーダー
+	ArmyID   int               // 拠点を奪った軍勢
+	Count    int               // 連続撃破の数
+	Position gamemath.Vector2D // 見どころの地点（追うユニットがいないとき）
+}
+
+// Score ranks the highlight against the others; the lowest is dropped first
+func (h Highlight) Score() int {
+	switch h.Kind {
+	case HighlightMultiKill:
+		return h.Count * 10
+	case HighlightLeaderDuel:
+		return 40
+	default:
+		return 30
+	}
+}
+
+// Description returns the caption shown while the highlight plays
+func (h Highlight) Description() string {
+	switch h.Kind {
+	case HighlightMultiKill:
+		return fmt.Sprintf("#%d の%d体連続撃破", h.UnitID, h.Count)
+	case HighlightLeaderDuel:
+		return fmt.Sprintf("リーダー #%d が #%d との一騎打ちを制す", h.UnitID, h.OtherID)
+	default:
+		return fmt.Sprintf("軍勢%sが拠点を奪取", data.ArmyLabel(h.ArmyID))
+	}
+}
+
+// HighlightClip is a highlight with the snapshots that replay it, oldest first
+type HighlightClip struct {
+	Highlight
+	Frames []*BattleSnapshot
+	end    float64 // この戦闘時間まで場面を残し続ける
+}
+
+// hitRecord is the latest blow a unit took
+type hitRecord struct {
+	attacker int
+	time     float64
+}
+
+// HighlightRecorder picks highlight moments out of the battle log as it grows and keeps the
+// snapshots around them, so they can be replayed once the battle is over
+type HighlightRecorder struct {
+	Clips []*HighlightClip
+	
+	recent   []*BattleSnapshot // 直近 highlightPreroll 秒の場面
+	lastTime float64           // 調べ終えた戦闘ログの時刻
+	
+	lastHits   map[int]hitRecord         // ユニットごとの最後の被弾
+	kills      map[int][]float64         // ユニットごとの最近の撃破の時刻
+	leaderHits map[[2]int]float64        // リーダーがリーダーを最後に打った時刻（打った側, 打たれた側）
+	owners     map[gamemath.Vector2D]int // 拠点ごとの持ち主の軍勢
+}
+
+// NewHighlightRecorder creates a recorder for a new battle
+func NewHighlightRecorder() *HighlightRecorder {
+	return &HighlightRecorder{
+		lastHits:   make(map[int]hitRecord),
+		kills:      make(map[int][]float64),
+		leaderHits: make(map[[2]int]float64),
+		owners:     make(map[gamemath.Vector2D]int),
+	}
+}
+
+// Record scans the battle log written since the last call for highlights, then stores the state
+// after the tick for the clips still being filmed and the next highlight's lead-in
+func (r *HighlightRecorder) Record(bm *BattleManager) {
+	if bm.BattleTime < r.lastTime {
+		r.rewind(bm.BattleTime)
+	}
+	
+	// Records are stamped with the battle time, so the new ones are those after the last scan
+	start := len(bm.Events)
+	for start > 0 && bm.Events[start-1].Time > r.lastTime {
+		start--
+	}
+	for _, event := range bm.Events[start:] {
+		r.scan(bm, event)
+	}
+	r.lastTime = bm.BattleTime
+	
+	if len(r.recent) > 0 && bm.BattleTime-r.recent[len(r.recent)-1].BattleTime < highlightInterval {
+		return
+	}
+	snapshot := bm.TakeSnapshot()
+	r.recent = append(r.recent, snapshot)
+	for len(r.recent) > 0 && r.recent[0].BattleTime < bm.BattleTime-highlightPreroll {
+		r.recent = r.recent[1:]
+	}
+	for _, clip := range r.Clips {
+		if clip.end >= bm.BattleTime {
+			clip.Frames = append(clip.Frames, snapshot)
+		}
+	}
+}
+
+// rewind forgets the highlights and snapshots after the moment a rewound battle went on from
+func (r *HighlightRecorder) rewind(battleTime float64) {
+	r.recent = framesUntil(r.recent, battleTime)
+	clips := r.Clips[:0]
+	for _, clip := range r.Clips {
+		if clip.Time <= battleTime {
+			clip.Frames = framesUntil(clip.Frames, battleTime)
+			clips = append(clips, clip)
+		}
+	}
+	r.Clips = clips
+	
+	// Streaks and duels are not worth tracking across the cut
+	clear(r.lastHits)
+	clear(r.kills)
+	clear(r.leaderHits)
+}
+
+// framesUntil drops the snapshots after the battle time
+func framesUntil(frames []*BattleSnapshot, battleTime float64) []*BattleSnapshot {
+	for len(frames) > 0 && frames[len(frames)-1].BattleTime > battleTime {
+		frames = frames[:len(frames)-1]
+	}
+	return frames
+}
+
+// scan looks for a highlight in one battle log record
+func (r *HighlightRecorder) scan(bm *BattleManager, event BattleEvent) {
+	switch event.Type {
+	case EventHit, EventAmbush:
+		r.lastHits[event.OtherID] = hitRecord{attacker: event.UnitID, time: event.Time}
+		if isLeader(bm, event.UnitID) && isLeader(bm, event.OtherID) {
+			r.leaderHits[[2]int{event.UnitID, event.OtherID}] = event.Time
+		}
+	case EventDeath:
+		hit, ok := r.lastHits[event.UnitID]
+		delete(r.lastHits, event.UnitID)
+		if ok && event.Time-hit.time <= killCreditWindow {
+			r.creditKill(bm, hit.attacker, event)
+		}
+	case EventCapture:
+		owner, held := r.owners[event.Position]
+		r.owners[event.Position] = event.Amount
+		if held && !bm.AreAllied(owner, event.Amount) {
+			r.add(Highlight{Kind: HighlightObjectiveFlip, Time: event.Time, ArmyID: event.Amount, Position: event.Position})
+		}
+	}
+}
+
+// creditKill counts the death toward the attacker's streak and checks for a leader felling another
+// after trading blows
+func (r *HighlightRecorder) creditKill(bm *BattleManager, attackerID int, death BattleEvent) {
+	if isLeader(bm, attackerID) && isLeader(bm, death.UnitID) {
+		if struck, ok := r.leaderHits[[2]int{death.UnitID, attackerID}]; ok && death.Time-struck <= duelWindow {
+			r.add(Highlight{Kind: HighlightLeaderDuel, Time: death.Time, UnitID: attackerID, OtherID: death.UnitID})
+		}
+	}
+	
+	kills := append(r.kills[attackerID], death.Time)
+	for len(kills) > 0 && kills[0] < death.Time-multiKillWindow {
+		kills = kills[1:]
+	}
+	r.kills[attackerID] = kills
+	if len(kills) >= multiKillCount {
+		r.add(Highlight{Kind: HighlightMultiKill, Time: death.Time, UnitID: attackerID, Count: len(kills)})
+	}
+}
+
+// add starts filming the highlight from the recent snapshots; a streak still being filmed grows
+// instead, and the lowest scoring clip makes way once there are too many
+func (r *HighlightRecorder) add(highlight Highlight) {
+	for _, clip := range r.Clips {
+		if highlight.Kind == HighlightMultiKill && clip.Kind == highlight.Kind && clip.UnitID == highlight.UnitID && clip.end >= highlight.Time {
+			clip.Highlight = highlight
+			clip.end = highlight.Time + highlightPostroll
+			return
+		}
+	}
+	
+	clip := &HighlightClip{Highlight: highlight, end: highlight.Time + highlightPostroll}
+	for _, snapshot := range r.recent {
+		if snapshot.BattleTime >= highlight.Time-highlightPreroll {
+			clip.Frames = append(clip.Frames, snapshot)
+		}
+	}
+	r.Clips = append(r.Clips, clip)
+	if len(r.Clips) <= maxHighlights {
+		return
+	}
+	
+	lowest := 0
+	for i, clip := range r.Clips {
+		if clip.Score() < r.Clips[lowest].Score() {
+			lowest = i
+		}
+	}
+	r.Clips = append(r.Clips[:lowest], r.Clips[lowest+1:]...)
+}
+
+// isLeader reports whether the unit with the ID leads a group
+func isLeader(bm *BattleManager, id int) bool {
+	unit := bm.FindUnit(id)
+	return unit != nil && unit.IsLeader
+}
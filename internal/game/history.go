@@ -0,0 +1,345 @@
+package game
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/shirou/tinygocha/internal/graphics"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// BattleSnapshot is a copy of the simulation state at one tick, restored in place to step back in time
+// Threat maps and the random source are rewound with the rest, so a restored battle plays on the same way;
+// flow fields only cache paths and are rebuilt
+type BattleSnapshot struct {
+	BattleTime float64
+	Ticks      int
+	
+	isActive      bool
+	winner        int
+	nextUnitID    int
+	scores        []float64
+	announcements []Announcement
+	queuedOrders  []Order
+	commandPoints *CommandPoints
+	events        int    // 戦闘ログの件数
+	randomDraws   uint64 // 乱数を引いた回数
+	
+	armies         []armySnapshot
+	groups         []groupSnapshot
+	units          []unitSnapshot
+	objectives     []Objective
+	capturePoints  []CapturePoint
+	reinforcements []Reinforcement
+	triggers       []Trigger
+	wind           gamemath.Vector2D
+	foggy          bool
+	commanders     []ArmyCommander
+	structures     []Structure
+	fire           []float64
+	burnt          []bool
+	traps          []Trap
+	projectiles    []SpellProjectile
+	threatMaps     []ThreatMap
+}
+
+type armySnapshot struct {
+	army   *Army
+	state  Army
+	fallen map[int]bool
+}
+
+type groupSnapshot struct {
+	group     *Group
+	state     Group
+	objective *GroupObjective
+	order     *Order
+}
+
+type unitSnapshot struct {
+	unit      *Unit
+	state     Unit
+	animation graphics.AnimationState
+	ai        *AIBehavior
+	cooldowns []float64
+}
+
+// TakeSnapshot copies the current simulation state
+func (bm *BattleManager) TakeSnapshot() *BattleSnapshot {
+	snapshot := &BattleSnapshot{
+		BattleTime:    bm.BattleTime,
+		Ticks:         bm.Ticks,
+		isActive:      bm.IsActive,
+		winner:        bm.Winner,
+		nextUnitID:    bm.nextUnitID,
+		scores:        append([]float64(nil), bm.Scores...),
+		announcements: append([]Announcement(nil), bm.Announcements...),
+		queuedOrders:  append([]Order(nil), bm.QueuedOrders...),
+		events:        len(bm.Events),
+		randomDraws:   bm.random.draws,
+	}
+	if bm.CommandPoints != nil {
+		commandPoints := *bm.CommandPoints
+		snapshot.commandPoints = &commandPoints
+	}
+	
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		snapshot.armies = append(snapshot.armies, armySnapshot{army: army, state: *army, fallen: maps.Clone(army.fallenUnits)})
+		for _, group := range army.Groups {
+			snapshot.groups = append(snapshot.groups, takeGroupSnapshot(group))
+		}
+		for _, unit := range army.GetAllUnits() {
+			snapshot.units = append(snapshot.units, takeUnitSnapshot(unit))
+		}
+	}
+	
+	for _, objective := range bm.Objectives {
+		snapshot.objectives = append(snapshot.objectives, *objective)
+	}
+	for _, point := range bm.CapturePoints {
+		snapshot.capturePoints = append(snapshot.capturePoints, *point)
+	}
+	for _, wave := range bm.Reinforcements {
+		snapshot.reinforcements = append(snapshot.reinforcements, *wave)
+	}
+	for _, trigger := range bm.Triggers {
+		snapshot.triggers = append(snapshot.triggers, *trigger)
+	}
+	snapshot.wind, snapshot.foggy = bm.Wind, bm.Foggy
+	for _, commander := range bm.Commanders {
+		snapshot.commanders = append(snapshot.commanders, *commander)
+	}
+	for _, structure := range bm.Structures {
+		snapshot.structures = append(snapshot.structures, *structure)
+	}
+	snapshot.fire = append([]float64(nil), bm.Terrain.Fire...)
+	snapshot.burnt = append([]bool(nil), bm.Terrain.Burnt...)
+	for _, trap := range bm.Traps {
+		snapshot.traps = append(snapshot.traps, *trap)
+	}
+	for _, projectile := range bm.Projectiles {
+		snapshot.projectiles = append(snapshot.projectiles, *projectile)
+	}
+	
+	// The AI reads the threat maps between rebuilds, and the timer decides when the next one comes
+	for _, threatMap := range bm.ThreatMaps {
+		saved := *threatMap
+		saved.MeleeThreat = slices.Clone(threatMap.MeleeThreat)
+		saved.RangedThreat = slices.Clone(threatMap.RangedThreat)
+		saved.Support = slices.Clone(threatMap.Support)
+		snapshot.threatMaps = append(snapshot.threatMaps, saved)
+	}
+	return snapshot
+}
+
+// takeGroupSnapshot copies a group with its current objective and order
+func takeGroupSnapshot(group *Group) groupSnapshot {
+	snapshot := groupSnapshot{group: group, state: *group}
+	if group.Objective != nil {
+		objective := *group.Objective
+		snapshot.objective = &objective
+	}
+	if group.CurrentOrder != nil {
+		order := *group.CurrentOrder
+		snapshot.order = &order
+	}
+	return snapshot
+}
+
+// takeUnitSnapshot copies a unit with its animation and AI state
+func takeUnitSnapshot(unit *Unit) unitSnapshot {
+	snapshot := unitSnapshot{unit: unit, state: *unit, animation: *unit.Animation, cooldowns: slices.Clone(unit.abilityCooldowns)}
+	if unit.AI != nil {
+		ai := *unit.AI
+		snapshot.ai = &ai
+	}
+	return snapshot
+}
+
+// RestoreSnapshot puts the simulation back into the snapshot's state
+// Units and groups that appeared after the snapshot drop out with their army's group list
+func (bm *BattleManager) RestoreSnapshot(snapshot *BattleSnapshot) {
+	bm.BattleTime = snapshot.BattleTime
+	bm.Ticks = snapshot.Ticks
+	bm.IsActive = snapshot.isActive
+	bm.Winner = snapshot.winner
+	bm.nextUnitID = snapshot.nextUnitID
+	bm.Scores = append(bm.Scores[:0], snapshot.scores...)
+	bm.Announcements = append([]Announcement(nil), snapshot.announcements...)
+	bm.QueuedOrders = append([]Order(nil), snapshot.queuedOrders...)
+	if snapshot.commandPoints != nil && bm.CommandPoints != nil {
+		*bm.CommandPoints = *snapshot.commandPoints
+	}
+	if snapshot.events < len(bm.Events) {
+		bm.Events = bm.Events[:snapshot.events]
+	}
+	
+	for _, saved := range snapshot.armies {
+		*saved.army = saved.state
+		saved.army.fallenUnits = maps.Clone(saved.fallen)
+	}
+	for _, saved := range snapshot.groups {
+		*saved.group = saved.state
+		if saved.objective != nil {
+			*saved.group.Objective = *saved.objective
+		}
+		if saved.order != nil {
+			*saved.group.CurrentOrder = *saved.order
+		}
+	}
+	for _, saved := range snapshot.units {
+		*saved.unit = saved.state
+		*saved.unit.Animation = saved.animation
+		if saved.ai != nil {
+			*saved.unit.AI = *saved.ai
+		}
+		copy(saved.unit.abilityCooldowns, saved.cooldowns)
+	}
+	
+	for i, objective := range snapshot.objectives {
+		*bm.Objectives[i] = objective
+	}
+	for i, point := range snapshot.capturePoints {
+		*bm.CapturePoints[i] = point
+	}
+	for i, wave := range snapshot.reinforcements {
+		*bm.Reinforcements[i] = wave
+	}
+	for i, trigger := range snapshot.triggers {
+		*bm.Triggers[i] = trigger
+	}
+	bm.Wind, bm.Foggy = snapshot.wind, snapshot.foggy
+	for i, commander := range snapshot.commanders {
+		*bm.Commanders[i] = commander
+	}
+	
+	// Rebuilt walls close their cells again, and unburnt thickets slow units down again
+	for i, structure := range snapshot.structures {
+		*bm.Structures[i] = structure
+	}
+	copy(bm.Terrain.Fire, snapshot.fire)
+	copy(bm.Terrain.Burnt, snapshot.burnt)
+	for i, trap := range snapshot.traps {
+		*bm.Traps[i] = trap
+	}
+	bm.Projectiles = nil
+	for _, projectile := range snapshot.projectiles {
+		bm.Projectiles = append(bm.Projectiles, &projectile)
+	}
+	bm.Terrain.refreshMovement()
+	bm.flowFields = make(map[int]*FlowField)
+	
+	for i, saved := range snapshot.threatMaps {
+		threatMap := bm.ThreatMaps[i]
+		copy(threatMap.MeleeThreat, saved.MeleeThreat)
+		copy(threatMap.RangedThreat, saved.RangedThreat)
+		copy(threatMap.Support, saved.Support)
+		threatMap.sinceUpdate = saved.sinceUpdate
+	}
+	bm.random.seek(snapshot.randomDraws)
+}
+
+// BattleHistory keeps a ring buffer of recent snapshots for stepping the simulation back and forth
+type BattleHistory struct {
+	snapshots []*BattleSnapshot
+	interval  float64 // スナップショットを残す間隔（戦闘時間の秒数、0: 毎ティック）
+	start     int     // 最も古いスナップショットの位置
+	count     int
+	cursor    int // 表示中のスナップショット（古い順、count-1 が最新）
+}
+
+// NewBattleHistory creates a history holding up to capacity snapshots, taken at most once per interval of battle time
+func NewBattleHistory(capacity int, interval float64) *BattleHistory {
+	return &BattleHistory{snapshots: make([]*BattleSnapshot, max(capacity, 1)), interval: interval}
+}
+
+// at returns the snapshot at the index counted from the oldest
+func (h *BattleHistory) at(index int) *BattleSnapshot {
+	return h.snapshots[(h.start+index)%len(h.snapshots)]
+}
+
+// Record stores the state after a simulated tick, unless the latest snapshot is less than an interval old
+// Recording after stepping back drops the ticks that were stepped over
+func (h *BattleHistory) Record(bm *BattleManager) {
+	if h.count > 0 {
+		h.count = h.cursor + 1
+		if bm.BattleTime-h.at(h.count-1).BattleTime < h.interval {
+			return
+		}
+	}
+	
+	if h.count == len(h.snapshots) {
+		h.start = (h.start + 1) % len(h.snapshots)
+		h.count--
+	}
+	h.snapshots[(h.start+h.count)%len(h.snapshots)] = bm.TakeSnapshot()
+	h.count++
+	h.cursor = h.count - 1
+}
+
+// StepBack restores the previous tick; returns false at the oldest one
+func (h *BattleHistory) StepBack(bm *BattleManager) bool {
+	if h.cursor <= 0 {
+		return false
+	}
+	h.cursor--
+	bm.RestoreSnapshot(h.at(h.cursor))
+	return true
+}
+
+// StepForward restores the next recorded tick; returns false at the latest one
+func (h *BattleHistory) StepForward(bm *BattleManager) bool {
+	if h.cursor >= h.count-1 {
+		return false
+	}
+	h.cursor++
+	bm.RestoreSnapshot(h.at(h.cursor))
+	return true
+}
+
+// Rewind restores the latest snapshot at least seconds older than the shown one, or the oldest;
+// returns false when the oldest one is already shown
+func (h *BattleHistory) Rewind(bm *BattleManager, seconds float64) bool {
+	if h.cursor <= 0 {
+		return false
+	}
+	target := h.at(h.cursor).BattleTime - seconds
+	for h.cursor > 0 && h.at(h.cursor).BattleTime > target {
+		h.cursor--
+	}
+	bm.RestoreSnapshot(h.at(h.cursor))
+	return true
+}
+
+// NextTime returns the battle time of the snapshot after the shown one; false at the latest
+func (h *BattleHistory) NextTime() (float64, bool) {
+	if h.cursor >= h.count-1 {
+		return 0, false
+	}
+	return h.at(h.cursor + 1).BattleTime, true
+}
+
+// Branch drops the snapshots after the shown one, so the battle goes on from there
+func (h *BattleHistory) Branch() {
+	if h.count > 0 {
+		h.count = h.cursor + 1
+	}
+}
+
+// IsRewound reports whether the battle shows a tick older than the latest
+func (h *BattleHistory) IsRewound() bool {
+	return h.cursor < h.count-1
+}
+
+// Position returns the shown tick and the number of recorded ticks
+func (h *BattleHistory) Position() (int, int) {
+	return h.cursor + 1, h.count
+}
+
+// Rewound returns how many seconds the shown tick is behind the latest
+func (h *BattleHistory) Rewound() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	return h.at(h.count-1).BattleTime - h.at(h.cursor).BattleTime
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// replayTimeStep is the tick length of the replay tests, coarse enough to reach the fighting quickly
+const replayTimeStep = 0.1
+
+// newTestBattle starts a seeded battle on the stage with the game's own data
+func newTestBattle(t *testing.T, stageID, playerPreset, enemyPreset string) *BattleManager {
+	t.Helper()
+	// The data files name each other from the repository root
+	t.Chdir("../..")
+	dataManager := data.NewDataManager()
+	if err := dataManager.LoadAll(); err != nil {
+		t.Fatal(err)
+	}
+	
+	stage, err := dataManager.GetStageConfig(stageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terrain, err := dataManager.GetTerrainConfig(stage.Terrain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bm := NewBattleManager(stage, terrain)
+	bm.SetRandomSeed(7)
+	if err := bm.CreateArmies(playerPreset, enemyPreset, dataManager); err != nil {
+		t.Fatal(err)
+	}
+	bm.StartBattle()
+	t.Cleanup(bm.Close)
+	return bm
+}
+
+// replayCases cover AI target choices made from the threat maps and summons placed by the random source
+var replayCases = []struct {
+	stage        string
+	playerPreset string
+	enemyPreset  string
+}{
+	{stage: "plain_battle", playerPreset: "防御重視", enemyPreset: "バランス型"},
+	{stage: "forest_battle", playerPreset: "召喚型", enemyPreset: "攻撃重視"},
+	{stage: "river_crossing", playerPreset: "防御重視", enemyPreset: "バランス型"},
+}
+
+func TestReplayAfterRestoreMatchesRecordedHashes(t *testing.T) {
+	for _, tt := range replayCases {
+		t.Run(tt.stage+" "+tt.playerPreset, func(t *testing.T) {
+			bm := newTestBattle(t, tt.stage, tt.playerPreset, tt.enemyPreset)
+			for tick := 0; tick < 600; tick++ {
+				bm.Update(replayTimeStep)
+			}
+			
+			snapshot := bm.TakeSnapshot()
+			var hashes []uint64
+			for tick := 0; tick < 900; tick++ {
+				bm.Update(replayTimeStep)
+				hashes = append(hashes, bm.StateHash())
+			}
+			
+			bm.RestoreSnapshot(snapshot)
+			for tick, want := range hashes {
+				bm.Update(replayTimeStep)
+				if got := bm.StateHash(); got != want {
+					t.Fatalf("tick %d after the snapshot: hash %x, want %x", tick+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryStepBackThenOnPlaysTheSameFuture(t *testing.T) {
+	bm := newTestBattle(t, "plain_battle", "防御重視", "バランス型")
+	history := NewBattleHistory(100, 0)
+	hashes := make(map[int]uint64)
+	for tick := 0; tick < 700; tick++ {
+		bm.Update(replayTimeStep)
+		history.Record(bm)
+		hashes[bm.Ticks] = bm.StateHash()
+	}
+	
+	// Stepping back a tick at a time, then forward again, shows the recorded ticks
+	for step := 0; step < 50; step++ {
+		if !history.StepBack(bm) {
+			t.Fatalf("step %d: no older tick", step)
+		}
+	}
+	if !history.StepForward(bm) || bm.StateHash() != hashes[bm.Ticks] {
+		t.Fatalf("tick %d: stepping forward does not show the recorded tick", bm.Ticks)
+	}
+	
+	// Going on from the stepped-back tick simulates the ticks that were recorded
+	history.Branch()
+	for bm.Ticks < 700 {
+		bm.Update(replayTimeStep)
+		history.Record(bm)
+		if got := bm.StateHash(); got != hashes[bm.Ticks] {
+			t.Fatalf("tick %d: hash %x, want %x", bm.Ticks, got, hashes[bm.Ticks])
+		}
+	}
+}
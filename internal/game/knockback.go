@@ -0,0 +1,87 @@
+package game
+
+import (
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Knockback tuning
+const (
+	knockbackDuration   = 0.2 // 押し下げの大半が済むまでの秒数
+	knockbackMinimum    = 1.0 // 残りがこれより短い押しは打ち切る（px）
+	heavyArmorKnockback = 0.5 // 重装備のユニットが押し下げられる距離の倍率
+	knockbackShare      = 0.5 // ぶつかったユニットに渡す残りの押しの割合
+	slamDamage          = 8   // 壁・崖・障害物に叩きつけられたときのダメージ
+	slamStun            = 0.8 // 叩きつけられて気絶する秒数
+)
+
+// knockBack shoves the target away from the unit by the unit's knockback distance; the shove plays out
+// over a short slide in updateKnockback. Flyers, units in towers and convoy wagons stand firm
+func (u *Unit) knockBack(target *Unit) {
+	if u.Knockback <= 0 || !target.IsAlive || target.Flying || target.Garrison != nil || target.Convoy {
+		return
+	}
+	
+	distance := u.Knockback
+	if target.HeavyArmor {
+		distance *= heavyArmorKnockback
+	}
+	direction := target.Position.Sub(u.Position).Normalize()
+	target.knockback = target.knockback.Add(direction.Mul(distance))
+}
+
+// IsKnockedBack reports whether the unit is being shoved back by a blow
+func (u *Unit) IsKnockedBack() bool {
+	return u.knockback.Length() >= knockbackMinimum
+}
+
+// updateKnockback slides the shoved units a step further back
+func (bm *BattleManager) updateKnockback(deltaTime float64) {
+	units := bm.getAllAliveUnits()
+	for _, unit := range units {
+		if !unit.IsKnockedBack() {
+			unit.knockback = gamemath.Vector2D{}
+			continue
+		}
+		step := unit.knockback.Mul(min(deltaTime/knockbackDuration, 1))
+		unit.knockback = unit.knockback.Sub(step)
+		bm.slide(unit, step, units)
+	}
+}
+
+// slide moves a shoved unit by the step unless something is in the way: troops it runs into stop it and
+// take a share of the shove, the stage edge stops it, and walls, cliffs and obstacles stop and stun it
+func (bm *BattleManager) slide(unit *Unit, step gamemath.Vector2D, units []*Unit) {
+	next := unit.Position.Add(step)
+	radius := unit.GetCollisionRadius()
+	
+	for _, other := range units {
+		if other == unit || other.Flying || other.Garrison != nil {
+			continue
+		}
+		reach := radius + other.GetCollisionRadius()
+		if next.Distance(other.Position) >= reach || next.Distance(other.Position) >= unit.Position.Distance(other.Position) {
+			continue
+		}
+		if !other.Convoy {
+			other.knockback = other.knockback.Add(unit.knockback.Add(step).Mul(knockbackShare))
+		}
+		unit.knockback = gamemath.Vector2D{}
+		return
+	}
+	
+	width, height := bm.getWorldSize()
+	if next.X < radius || next.Y < radius || next.X > width-radius || next.Y > height-radius {
+		unit.knockback = gamemath.Vector2D{}
+		return
+	}
+	
+	if unit.Terrain != nil && (!unit.Terrain.isOpenTo(next, unit.ArmyID) || unit.Terrain.pushOutOfObstacles(next, radius) != next) {
+		unit.knockback = gamemath.Vector2D{}
+		unit.TakeDamage(slamDamage)
+		unit.Stun(slamStun)
+		bm.recordEvent(BattleEvent{Type: EventSlam, UnitID: unit.ID, GroupID: unit.GroupID, Amount: slamDamage})
+		bm.Heatmap.add(HeatmapDamage, unit.Position, float64(slamDamage))
+		return
+	}
+	unit.Position = next
+}
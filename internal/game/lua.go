@@ -0,0 +1,308 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// neutralArmyLabel names the neutral creatures in scripts
+const neutralArmyLabel = "neutral"
+
+// scriptStepLimit is how many Lua instructions one call into a script may run before it is stopped, so a
+// script stuck in a loop cannot hang the battle. Counting instructions rather than time stops the same
+// scripts on every machine, which keeps co-op peers and replays in step
+const scriptStepLimit = 1_000_000
+
+// errScriptSteps is the error a script stopped by its step budget raises
+var errScriptSteps = errors.New("ran more than the step limit")
+
+// scriptBudget counts down the instructions left to the running call. The Lua VM asks the state's
+// context for its Done channel before every instruction, so the budget stands in for a context
+// and hands out the closed channel once the count runs out
+type scriptBudget struct {
+	left     int
+	exceeded bool
+	stop     chan struct{}
+}
+
+// newScriptBudget creates an exhausted budget; call resets it before each call into a script
+func newScriptBudget() *scriptBudget {
+	stop := make(chan struct{})
+	close(stop)
+	return &scriptBudget{stop: stop}
+}
+
+// Done spends one instruction; it returns the closed channel once the budget is spent, nil before
+func (b *scriptBudget) Done() <-chan struct{} {
+	if b.left <= 0 {
+		b.exceeded = true
+		return b.stop
+	}
+	b.left--
+	return nil
+}
+
+// Err reports errScriptSteps once the budget is spent
+func (b *scriptBudget) Err() error {
+	if b.exceeded {
+		return errScriptSteps
+	}
+	return nil
+}
+
+// Deadline reports that the budget has no deadline in time
+func (b *scriptBudget) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Value carries no values
+func (b *scriptBudget) Value(key any) any {
+	return nil
+}
+
+// scriptEngine runs the battle's Lua scripts: the stage's scenario script and the unit types' AI scripts
+// Each file runs once in its own environment, so two scripts may both define think or on_tick
+// Script globals are not part of the battle snapshots and keep their values when the battle is rewound
+type scriptEngine struct {
+	state   *lua.LState
+	budget  *scriptBudget
+	envs    map[string]*lua.LTable // 読み込んだスクリプトの環境（nil: 読み込めないか実行中にエラーが出た）
+	started bool                   // ステージのスクリプトの on_start を呼んだ
+}
+
+// scripts returns the battle's script engine, starting Lua the first time a script is needed
+func (bm *BattleManager) scripts() *scriptEngine {
+	if bm.scriptEngine == nil {
+		bm.scriptEngine = newScriptEngine(bm)
+	}
+	return bm.scriptEngine
+}
+
+// Close shuts the battle's Lua down; whoever runs the battle calls it once done with it, since a battle
+// that has ended may still be rewound and played on with its scripts as they were
+func (bm *BattleManager) Close() {
+	if bm.scriptEngine == nil {
+		return
+	}
+	bm.scriptEngine.state.Close()
+	bm.scriptEngine = nil
+}
+
+// newScriptEngine starts a Lua state with the base, table, string and math libraries and the battle API
+// Scripts cannot read files, and math.random is replaced by battle.random so replays stay in step
+func newScriptEngine(bm *BattleManager) *scriptEngine {
+	state := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		state.Push(state.NewFunction(lib.open))
+		state.Push(lua.LString(lib.name))
+		state.Call(1, 0)
+	}
+	state.SetGlobal("dofile", lua.LNil)
+	state.SetGlobal("loadfile", lua.LNil)
+	if math, ok := state.GetGlobal("math").(*lua.LTable); ok {
+		math.RawSetString("random", lua.LNil)
+		math.RawSetString("randomseed", lua.LNil)
+	}
+	
+	se := &scriptEngine{state: state, budget: newScriptBudget(), envs: make(map[string]*lua.LTable)}
+	state.SetContext(se.budget)
+	state.SetGlobal("battle", state.SetFuncs(state.NewTable(), se.battleAPI(bm)))
+	return se
+}
+
+// load runs a script file the first time it is asked for and returns its environment (nil: it cannot run)
+func (se *scriptEngine) load(path string) *lua.LTable {
+	if env, loaded := se.envs[path]; loaded {
+		return env
+	}
+	se.envs[path] = nil
+	
+	chunk, err := se.state.LoadFile(path)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load Lua script %s: %v\n", path, err)
+		return nil
+	}
+	env := se.state.NewTable()
+	meta := se.state.NewTable()
+	meta.RawSetString("__index", se.state.G.Global)
+	se.state.SetMetatable(env, meta)
+	chunk.Env = env
+	
+	se.envs[path] = env
+	if !se.call(path, chunk, 0) {
+		return nil
+	}
+	return env
+}
+
+// function returns the function the script defines under the name, or nil
+func (se *scriptEngine) function(path, name string) *lua.LFunction {
+	env := se.load(path)
+	if env == nil {
+		return nil
+	}
+	fn, _ := env.RawGetString(name).(*lua.LFunction)
+	return fn
+}
+
+// call calls a script function, leaving nret results on the stack; a script that fails or runs
+// more than scriptStepLimit instructions is reported once and not run again
+func (se *scriptEngine) call(path string, fn *lua.LFunction, nret int, args ...lua.LValue) bool {
+	se.budget.left, se.budget.exceeded = scriptStepLimit, false
+	if err := se.state.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...); err != nil {
+		if se.budget.exceeded {
+			fmt.Printf("Warning: Lua script %s stopped: ran more than %d steps\n", path, scriptStepLimit)
+		} else {
+			fmt.Printf("Warning: Lua script %s stopped: %v\n", path, err)
+		}
+		se.envs[path] = nil
+		return false
+	}
+	return true
+}
+
+// updateScript runs the stage's script: on_start on the first tick, then on_tick(delta_time) every tick
+func (bm *BattleManager) updateScript(deltaTime float64) {
+	path := bm.Stage.Script
+	if path == "" {
+		return
+	}
+	
+	se := bm.scripts()
+	if !se.started {
+		se.started = true
+		if fn := se.function(path, "on_start"); fn != nil {
+			se.call(path, fn, 0)
+		}
+	}
+	if fn := se.function(path, "on_tick"); fn != nil {
+		se.call(path, fn, 0, lua.LNumber(deltaTime))
+	}
+}
+
+// armyLabel returns the name of an army in scripts: "a", "b", ... as in stages.toml, or "neutral"
+func armyLabel(armyID int) string {
+	if armyID == NeutralArmyID {
+		return neutralArmyLabel
+	}
+	return string(rune('a' + armyID))
+}
+
+// scriptArmy returns the army a script names, or nil
+func (bm *BattleManager) scriptArmy(label string) *Army {
+	if label == neutralArmyLabel {
+		return bm.Neutrals
+	}
+	return bm.GetArmy(data.ArmyIndex(label))
+}
+
+// unitTable describes a unit to scripts
+func (se *scriptEngine) unitTable(unit *Unit) *lua.LTable {
+	table := se.state.NewTable()
+	table.RawSetString("id", lua.LNumber(unit.ID))
+	table.RawSetString("type", lua.LString(unit.Type))
+	table.RawSetString("army", lua.LString(armyLabel(unit.ArmyID)))
+	table.RawSetString("group", lua.LNumber(unit.GroupID))
+	table.RawSetString("leader", lua.LBool(unit.IsLeader))
+	table.RawSetString("x", lua.LNumber(unit.Position.X))
+	table.RawSetString("y", lua.LNumber(unit.Position.Y))
+	table.RawSetString("hp", lua.LNumber(unit.HP))
+	table.RawSetString("max_hp", lua.LNumber(unit.MaxHP))
+	table.RawSetString("morale", lua.LNumber(unit.Morale))
+	table.RawSetString("range", lua.LNumber(unit.Range))
+	table.RawSetString("hidden", lua.LBool(unit.Hidden))
+	if unit.AI != nil && unit.AI.TargetEnemy != nil {
+		table.RawSetString("target", lua.LNumber(unit.AI.TargetEnemy.ID))
+	}
+	return table
+}
+
+// battleAPI returns the functions of the battle table scripts call
+func (se *scriptEngine) battleAPI(bm *BattleManager) map[string]lua.LGFunction {
+	return map[string]lua.LGFunction{
+		// battle.time() returns the seconds since the battle began
+		"time": func(L *lua.LState) int {
+			L.Push(lua.LNumber(bm.BattleTime))
+			return 1
+		},
+		// battle.random() returns a number in [0, 1) from the battle's seed
+		"random": func(L *lua.LState) int {
+			L.Push(lua.LNumber(bm.rng.Float64()))
+			return 1
+		},
+		// battle.units(army) returns the army's living units
+		"units": func(L *lua.LState) int {
+			army := bm.scriptArmy(L.CheckString(1))
+			units := L.NewTable()
+			if army != nil {
+				for _, unit := range army.GetAliveUnits() {
+					units.Append(se.unitTable(unit))
+				}
+			}
+			L.Push(units)
+			return 1
+		},
+		// battle.army(army) returns the army's name, living units, health and morale (0-1), or nil
+		"army": func(L *lua.LState) int {
+			army := bm.scriptArmy(L.CheckString(1))
+			if army == nil {
+				L.Push(lua.LNil)
+				return 1
+			}
+			table := L.NewTable()
+			table.RawSetString("name", lua.LString(army.Name))
+			table.RawSetString("alive", lua.LNumber(army.GetAliveCount()))
+			table.RawSetString("health", lua.LNumber(army.GetTotalHealth()))
+			table.RawSetString("morale", lua.LNumber(army.GetMorale()))
+			table.RawSetString("routed", lua.LBool(army.IsRouted))
+			L.Push(table)
+			return 1
+		},
+		// battle.announce(text) shows a message on the battle screen
+		"announce": func(L *lua.LState) int {
+			bm.Announce(L.CheckString(1))
+			return 0
+		},
+		// battle.spawn(army, x, y, leader, member, count) fields a group for the army
+		"spawn": func(L *lua.LState) int {
+			army := bm.scriptArmy(L.CheckString(1))
+			position := gamemath.Vector2D{X: float64(L.CheckNumber(2)), Y: float64(L.CheckNumber(3))}
+			group := data.ReinforcementGroupConfig{Leader: L.CheckString(4), Member: L.CheckString(5), Count: L.OptInt(6, 0)}
+			if army != nil && !army.IsRouted && bm.dataManager != nil {
+				bm.spawnGroups(army, position, []data.ReinforcementGroupConfig{group})
+			}
+			return 0
+		},
+		// battle.weather(kind, wind_from, wind_speed) changes the weather as a stage trigger does
+		"weather": func(L *lua.LState) int {
+			bm.changeWeather(data.TriggerActionConfig{
+				Weather:   L.CheckString(1),
+				WindFrom:  float64(L.OptNumber(2, 0)),
+				WindSpeed: float64(L.OptNumber(3, 0)),
+			})
+			return 0
+		},
+		// battle.end_battle(army) ends the battle won by the army, or drawn without one
+		"end_battle": func(L *lua.LState) int {
+			winner := WinnerDraw
+			if army := bm.GetArmy(data.ArmyIndex(L.OptString(1, ""))); army != nil {
+				winner = army.ID
+			}
+			bm.endBattle(winner)
+			return 0
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Actions a script's think function returns
+const (
+	scriptActionAttack  = "attack"  // "attack", enemy_id: その敵と戦う（近づく・撃つは行動ツリーと同じ）
+	scriptActionRetreat = "retreat" // "retreat"[, enemy_id]: その敵（省略: 今の目標）から離れる
+	scriptActionMove    = "move"    // "move", x, y: その地点へ向かう
+	scriptActionHold    = "hold"    // "hold": その場に留まる
+)
+
+// btScript asks the unit type's script what to do; it fails, leaving the choice to the behavior tree,
+// when think returns nothing or the script cannot run. Targets picked by the script are fought with combat
+func (bm *BattleManager) btScript(path string, combat BTNode) BTNode {
+	return NewAction("スクリプト", func(ctx *BTContext) BTStatus {
+		return bm.scripts().think(path, ctx, combat)
+	})
+}
+
+// think calls the script's think(unit, enemies) and carries out the action it returns
+func (se *scriptEngine) think(path string, ctx *BTContext, combat BTNode) BTStatus {
+	fn := se.function(path, "think")
+	if fn == nil {
+		return BTFailure
+	}
+	enemies := se.state.NewTable()
+	for _, enemy := range ctx.Enemies {
+		enemies.Append(se.unitTable(enemy))
+	}
+	if !se.call(path, fn, 3, se.unitTable(ctx.Unit), enemies) {
+		return BTFailure
+	}
+	action, first, second := se.state.Get(-3), se.state.Get(-2), se.state.Get(-1)
+	se.state.Pop(3)
+	
+	switch action {
+	case lua.LNil, lua.LFalse:
+		return BTFailure
+	case lua.LString(scriptActionAttack):
+		target := findEnemy(ctx, first)
+		if target == nil {
+			return BTFailure
+		}
+		ctx.AI.TargetEnemy = target
+		return combat.Tick(ctx)
+	case lua.LString(scriptActionRetreat):
+		if first != lua.LNil {
+			ctx.AI.TargetEnemy = findEnemy(ctx, first)
+		}
+		if ctx.AI.TargetEnemy == nil {
+			return BTFailure
+		}
+		ctx.AI.CurrentAction = AIActionRetreat
+		ctx.AI.moveAwayFromTarget(ctx.Unit, 1.0)
+		return BTRunning
+	case lua.LString(scriptActionMove):
+		ctx.AI.CurrentAction = AIActionApproach
+		ctx.AI.TargetEnemy = nil
+		ctx.Unit.MoveTo(gamemath.Vector2D{X: float64(lua.LVAsNumber(first)), Y: float64(lua.LVAsNumber(second))})
+		return BTRunning
+	case lua.LString(scriptActionHold):
+		ctx.AI.CurrentAction = AIActionHold
+		ctx.Unit.Target = ctx.Unit.Position
+		return BTSuccess
+	default:
+		fmt.Printf("Warning: Lua script %s returned unknown action %s\n", path, action)
+		se.envs[path] = nil
+		return BTFailure
+	}
+}
+
+// findEnemy returns the enemy with the ID a script gave among those the unit may engage, or nil
+func findEnemy(ctx *BTContext, id lua.LValue) *Unit {
+	for _, enemy := range ctx.Enemies {
+		if lua.LNumber(enemy.ID) == id && isValidTarget(ctx.Unit, enemy) {
+			return enemy
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// writeScript writes a Lua script for a test and returns its path
+func writeScript(t *testing.T, source string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.lua")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestScriptStepLimitStopsRunawayScripts(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "endless loop", source: "steps = 0\nfunction run() while true do steps = steps + 1 end end\n"},
+		{name: "loop catching its own stop", source: "steps = 0\nfunction spin() while true do steps = steps + 1 end end\nfunction run() while true do pcall(spin) end end\n"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScript(t, tt.source)
+			
+			// Two engines stand in for two co-op peers: both must stop the script at the same step
+			var stoppedAt []lua.LValue
+			for peer := 0; peer < 2; peer++ {
+				bm := &BattleManager{}
+				se := bm.scripts()
+				env := se.load(path)
+				if se.call(path, se.function(path, "run"), 0) {
+					t.Fatal("the runaway script was not stopped")
+				}
+				if se.load(path) != nil {
+					t.Error("the stopped script is still run")
+				}
+				stoppedAt = append(stoppedAt, env.RawGetString("steps"))
+				bm.Close()
+			}
+			if stoppedAt[0] != stoppedAt[1] {
+				t.Errorf("stopped after %v and %v steps", stoppedAt[0], stoppedAt[1])
+			}
+		})
+	}
+}
+
+func TestScriptCallsGetAFreshBudget(t *testing.T) {
+	path := writeScript(t, "function run() local sum = 0 for i = 1, 200000 do sum = sum + i end return sum end\n")
+	bm := &BattleManager{}
+	defer bm.Close()
+	se := bm.scripts()
+	
+	// Each call runs well within the limit, though together they go past it
+	for call := 0; call < 5; call++ {
+		if !se.call(path, se.function(path, "run"), 1) {
+			t.Fatalf("call %d was stopped", call)
+		}
+		se.state.Pop(1)
+	}
+}
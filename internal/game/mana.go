@@ -0,0 +1,39 @@
+package game
+
+// SpellAbility describes a magic user's mana pool and the abilities it pays for with it
+type SpellAbility struct {
+	MaxMana   float64   // 魔力の最大値（0: 魔力を使わない）
+	Regen     float64   // 1秒で回復する魔力
+	Abilities []Ability // 唱える魔法（abilities.toml、並べた順に優先する）
+}
+
+// UsesMana reports whether the unit's spells draw on a mana pool
+func (u *Unit) UsesMana() bool {
+	return u.Spells.MaxMana > 0
+}
+
+// GetManaRatio returns the unit's mana as a fraction of its pool (0.0-1.0)
+func (u *Unit) GetManaRatio() float64 {
+	if !u.UsesMana() {
+		return 0
+	}
+	return u.Mana / u.Spells.MaxMana
+}
+
+// regenerateMana recovers mana over time
+func (u *Unit) regenerateMana(deltaTime float64) {
+	if u.UsesMana() {
+		u.Mana = min(u.Spells.MaxMana, u.Mana+u.Spells.Regen*deltaTime)
+	}
+}
+
+// spendMana pays for the spell the unit is winding up and starts its cooldown
+func (u *Unit) spendMana() {
+	if u.casting == nil {
+		return
+	}
+	if u.UsesMana() {
+		u.Mana -= u.casting.ManaCost
+	}
+	u.abilityCooldowns[u.casting.slot] = u.casting.Cooldown
+}
@@ -0,0 +1,92 @@
+package game
+
+// Morale tuning
+const (
+	MoraleRegenRate         = 1.0  // 毎秒の士気回復量
+	MoraleLossPerAllyDeath  = 3.0  // 味方1体の戦死による士気低下（軍勢全体）
+	MoraleLossOnLeaderDeath = 20.0 // リーダー戦死による士気低下（部隊メンバー）
+	ArmyMoraleCollapse      = 0.25 // 軍勢士気がこの値を下回ると総崩れ
+)
+
+// GetMorale returns the army morale as a ratio (0.0-1.0)
+// Fallen and retreating units count as zero morale; summoned minions are left out
+func (a *Army) GetMorale() float64 {
+	units := a.getEnlistedUnits()
+	if len(units) == 0 {
+		return 0
+	}
+	
+	total := 0.0
+	for _, unit := range units {
+		if unit.IsAlive && !unit.IsRetreating && unit.MaxMorale > 0 {
+			total += unit.Morale / unit.MaxMorale
+		}
+	}
+	
+	return total / float64(len(units))
+}
+
+// Rout makes every remaining unit of the army flee the battlefield
+func (a *Army) Rout() {
+	a.IsRouted = true
+	for _, unit := range a.GetAllUnits() {
+		if unit.IsAlive && !unit.IsRetreating {
+			unit.StartRetreating(retreatPoint(unit.Position, a.Facing))
+		}
+	}
+}
+
+// updateMorale applies casualty shocks and recovery to unit morale
+func (a *Army) updateMorale(deltaTime float64) {
+	if a.fallenUnits == nil {
+		a.fallenUnits = make(map[int]bool)
+	}
+	
+	for _, group := range a.Groups {
+		for _, unit := range group.GetAllUnits() {
+			if unit.IsAlive || a.fallenUnits[unit.ID] {
+				continue
+			}
+			a.fallenUnits[unit.ID] = true
+			
+			// Losing a summoned minion shakes no one
+			if unit.IsSummoned() {
+				continue
+			}
+			
+			// Every casualty shakes the whole army
+			for _, ally := range a.GetAliveUnits() {
+				ally.ChangeMorale(-MoraleLossPerAllyDeath)
+			}
+			
+			// Losing the leader hits the group hardest
+			if unit.IsLeader {
+				for _, member := range group.Members {
+					if member.IsAlive {
+						member.ChangeMorale(-MoraleLossOnLeaderDeath)
+					}
+				}
+			}
+		}
+	}
+	
+	// Gradual recovery
+	for _, unit := range a.GetAliveUnits() {
+		unit.ChangeMorale(MoraleRegenRate * deltaTime)
+	}
+}
+
+// updateMorale updates army morale and routs armies whose morale collapsed
+func (bm *BattleManager) updateMorale(deltaTime float64) {
+	for _, army := range bm.Armies {
+		if army.IsRouted || len(army.GetAllUnits()) == 0 {
+			continue
+		}
+		
+		army.updateMorale(deltaTime)
+		
+		if army.GetMorale() < ArmyMoraleCollapse {
+			army.Rout()
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// Mutator changes the rules of a battle; each hook is optional
+type Mutator struct {
+	ID          string
+	Name        string
+	Description string
+	
+	// Unit creation: replaces the unit type before the unit is built
+	UnitType func(unitType string, config data.UnitTypeConfig) string
+	// Unit creation: adjusts the stats of a newly created unit
+	Unit func(unit *Unit)
+	// Unit creation: rolls the stats of a newly created unit from the battle's seeded random source
+	Roll func(unit *Unit, rng *rand.Rand)
+	// Combat resolution: called after an attack dealt damage
+	Hit func(attacker, target *Unit, damage int)
+	
+	// Scene rules
+	NoPause bool // 一時停止と作戦タイムを禁止
+	Fog     bool // 味方の視界外の敵を隠す
+	Wind    bool // 風が吹き、矢が流される
+}
+
+// Mutator tuning
+const (
+	mutatorSpeedMultiplier = 2.0        // 倍速: 移動速度の倍率
+	vampiricDrain          = 0.5        // 吸血: 与ダメージのうち回復する割合
+	fogSightRange          = 600.0      // 戦場の霧: 味方ユニットから見える距離
+	replacementUnitType    = "infantry" // 飛び道具禁止: 置き換え先のユニット
+	statVariance           = 0.05       // 個体差: 体力・攻撃力がばらつく幅（±5%）
+)
+
+// mutators are the available battle mutators in display order
+var mutators = []*Mutator{
+	{
+		ID:          "double_speed",
+		Name:        "倍速",
+		Description: "全ユニットの移動速度が2倍",
+		Unit: func(unit *Unit) {
+			unit.Speed *= mutatorSpeedMultiplier
+		},
+	},
+	{
+		ID:          "no_ranged",
+		Name:        "飛び道具禁止",
+		Description: "弓兵・魔術師が歩兵に置き換わる",
+		UnitType: func(unitType string, config data.UnitTypeConfig) string {
+			if config.Range > rangedThreatRange {
+				return replacementUnitType
+			}
+			return unitType
+		},
+	},
+	{
+		ID:          "iron_man",
+		Name:        "鉄人",
+		Description: "一時停止・作戦タイム・巻き戻しを使えない",
+		NoPause:     true,
+	},
+	{
+		ID:          "fog",
+		Name:        "戦場の霧",
+		Description: "味方から見えない敵は表示されない",
+		Fog:         true,
+	},
+	{
+		ID:          "vampiric",
+		Name:        "吸血",
+		Description: "与えたダメージの半分だけ体力が回復する",
+		Hit: func(attacker, target *Unit, damage int) {
+			attacker.Heal(int(float64(damage) * vampiricDrain))
+		},
+	},
+	{
+		ID:          "variance",
+		Name:        "個体差",
+		Description: "体力・攻撃力が乱数シードに応じて±5%ばらつく",
+		Roll: func(unit *Unit, rng *rand.Rand) {
+			hp := 1 + (rng.Float64()*2-1)*statVariance
+			attack := 1 + (rng.Float64()*2-1)*statVariance
+			unit.MaxHP = max(int(math.Round(float64(unit.MaxHP)*hp)), 1)
+			unit.HP = unit.MaxHP
+			unit.AttackPower = int(math.Round(float64(unit.AttackPower) * attack))
+			unit.MagicPower = int(math.Round(float64(unit.MagicPower) * attack))
+		},
+	},
+	{
+		ID:          "strong_wind",
+		Name:        "強風",
+		Description: "風が吹き、遠くへ射た矢ほど風下へ流されて外れる",
+		Wind:        true,
+	},
+}
+
+// GetMutators returns the available mutators in display order
+func GetMutators() []*Mutator {
+	return mutators
+}
+
+// RegisterMutator adds a mutator to the available ones
+func RegisterMutator(mutator *Mutator) {
+	mutators = append(mutators, mutator)
+}
+
+// GetMutator returns the mutator with the given ID, or nil if there is none
+func GetMutator(id string) *Mutator {
+	for _, mutator := range mutators {
+		if mutator.ID == id {
+			return mutator
+		}
+	}
+	return nil
+}
+
+// SetMutators enables mutators by ID; call it before CreateArmies
+func (bm *BattleManager) SetMutators(ids []string) {
+	bm.Mutators = nil
+	for _, id := range ids {
+		if mutator := GetMutator(id); mutator != nil {
+			bm.Mutators = append(bm.Mutators, mutator)
+		}
+	}
+	bm.rollWind()
+}
+
+// IsPauseDisabled reports whether an active mutator forbids pausing
+func (bm *BattleManager) IsPauseDisabled() bool {
+	for _, mutator := range bm.Mutators {
+		if mutator.NoPause {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFogEnabled reports whether an active mutator or the fog of a stage trigger hides enemies out of sight
+func (bm *BattleManager) IsFogEnabled() bool {
+	if bm.Foggy {
+		return true
+	}
+	for _, mutator := range bm.Mutators {
+		if mutator.Fog {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVisibleTo reports whether the army can see the unit; without fog only hidden scouts are out of sight
+func (bm *BattleManager) IsVisibleTo(armyID int, unit *Unit) bool {
+	if bm.AreAllied(armyID, unit.ArmyID) {
+		return true
+	}
+	if !bm.isDetected(unit, armyID) {
+		return false
+	}
+	if !bm.IsFogEnabled() {
+		return true
+	}
+	
+	sightRange := fogSightRange * bm.getNightFactor(nightSightFactor)
+	for _, army := range bm.Armies {
+		if !bm.AreAllied(armyID, army.ID) {
+			continue
+		}
+		for _, ally := range army.GetAliveUnits() {
+			if ally.Position.Distance(unit.Position) <= sightRange {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mutateUnitType passes a unit type through the active mutators
+func (bm *BattleManager) mutateUnitType(unitType string, dataManager *data.DataManager) (string, data.UnitTypeConfig, error) {
+	config, err := dataManager.GetUnitConfig(unitType)
+	if err != nil {
+		return unitType, config, err
+	}
+	
+	for _, mutator := range bm.Mutators {
+		if mutator.UnitType == nil {
+			continue
+		}
+		if replaced := mutator.UnitType(unitType, config); replaced != unitType {
+			replacedConfig, err := dataManager.GetUnitConfig(replaced)
+			if err != nil {
+				continue
+			}
+			unitType, config = replaced, replacedConfig
+		}
+	}
+	return unitType, config, nil
+}
+
+// mutateUnit applies the active mutators to a newly created unit
+// Rolled stats draw on the battle's random source, so the same seed gives the same armies
+func (bm *BattleManager) mutateUnit(unit *Unit) {
+	for _, mutator := range bm.Mutators {
+		if mutator.Unit != nil {
+			mutator.Unit(unit)
+		}
+		if mutator.Roll != nil {
+			mutator.Roll(unit, bm.rng)
+		}
+	}
+}
+
+// onHit applies the active mutators after an attack dealt damage
+func (bm *BattleManager) onHit(attacker, target *Unit, damage int) {
+	for _, mutator := range bm.Mutators {
+		if mutator.Hit != nil {
+			mutator.Hit(attacker, target, damage)
+		}
+	}
+}
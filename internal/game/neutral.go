@@ -0,0 +1,90 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// NeutralArmyID is the army ID of neutral creatures hostile to every army
+const NeutralArmyID = -1
+
+// neutralLeashFactor limits how far beyond the guard radius neutrals chase intruders
+const neutralLeashFactor = 1.5
+
+// NeutralCamp tracks monsters or bandits guarding a spot on the battlefield
+type NeutralCamp struct {
+	Config data.NeutralCampConfig
+	Groups []*Group
+}
+
+// NewNeutralCamps creates neutral camps from the stage configuration
+func NewNeutralCamps(configs []data.NeutralCampConfig) []*NeutralCamp {
+	camps := make([]*NeutralCamp, 0, len(configs))
+	for _, config := range configs {
+		if config.GuardRadius <= 0 {
+			config.GuardRadius = 300.0
+		}
+		camps = append(camps, &NeutralCamp{Config: config})
+	}
+	return camps
+}
+
+// Center returns the guarded spot
+func (nc *NeutralCamp) Center() gamemath.Vector2D {
+	return gamemath.Vector2D{X: nc.Config.X, Y: nc.Config.Y}
+}
+
+// spawnNeutralCamps places the groups of every neutral camp
+func (bm *BattleManager) spawnNeutralCamps(dataManager *data.DataManager) {
+	for _, camp := range bm.NeutralCamps {
+		for i, groupConfig := range camp.Config.Groups {
+			// Spread groups around the guarded spot
+			position := camp.Center().Add(gamemath.Vector2D{X: float64(i) * 80})
+			
+			group := bm.createGroup(NeutralArmyID, groupConfig.Leader, groupConfig.Member, groupConfig.Count, position, dataManager)
+			if group == nil {
+				continue
+			}
+			bm.equip(group, groupConfig.Equipment, dataManager)
+			bm.Neutrals.AddGroup(group)
+			camp.Groups = append(camp.Groups, group)
+		}
+	}
+}
+
+// getArmyUnitsNear returns alive units of all armies within radius of the position
+func (bm *BattleManager) getArmyUnitsNear(position gamemath.Vector2D, radius float64) []*Unit {
+	var units []*Unit
+	for _, army := range bm.Armies {
+		for _, unit := range army.GetAliveUnits() {
+			if unit.Position.Distance(position) <= radius {
+				units = append(units, unit)
+			}
+		}
+	}
+	return units
+}
+
+// updateNeutrals runs the guard AI: attack intruders near the camp, otherwise return to it
+func (bm *BattleManager) updateNeutrals(deltaTime float64) {
+	for _, camp := range bm.NeutralCamps {
+		center := camp.Center()
+		intruders := bm.getArmyUnitsNear(center, camp.Config.GuardRadius)
+		leash := camp.Config.GuardRadius * neutralLeashFactor
+		
+		for _, group := range camp.Groups {
+			for _, unit := range group.GetAllUnits() {
+				if !unit.IsAlive || unit.IsRetreating || unit.AI == nil {
+					continue
+				}
+				
+				if len(intruders) > 0 && unit.Position.Distance(center) <= leash {
+					unit.AI.Update(unit, intruders, deltaTime)
+				} else if unit.IsLeader && unit.Position.Distance(center) > 5.0 {
+					// Members follow the leader back in formation
+					unit.MoveTo(center)
+				}
+			}
+		}
+	}
+}
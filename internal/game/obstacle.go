@@ -0,0 +1,103 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Obstacle tuning
+const (
+	defaultObstacleRadius = 30.0 // 半径を省略した障害物（3m）
+	obstacleAvoidMargin   = 30.0 // 障害物の縁からこの距離（3m）以内に入ると避け始める
+	obstacleAvoidWeight   = 2.0  // 回避の強さ（移動速度に対する倍率）
+)
+
+// Obstacle is a tree or boulder with a solid collision circle
+type Obstacle struct {
+	Kind     string
+	Position gamemath.Vector2D
+	Radius   float64
+}
+
+// NewObstacles creates the stage's obstacles from their configuration
+func NewObstacles(configs []data.ObstacleConfig) []Obstacle {
+	obstacles := make([]Obstacle, 0, len(configs))
+	for _, config := range configs {
+		radius := config.Radius
+		if radius <= 0 {
+			radius = defaultObstacleRadius
+		}
+		obstacles = append(obstacles, Obstacle{
+			Kind:     config.Kind,
+			Position: gamemath.Vector2D{X: config.X, Y: config.Y},
+			Radius:   radius,
+		})
+	}
+	return obstacles
+}
+
+// pushOutOfObstacles moves the circle to the edge of any obstacle it overlaps
+func (tg *TerrainGrid) pushOutOfObstacles(position gamemath.Vector2D, radius float64) gamemath.Vector2D {
+	for _, obstacle := range tg.Obstacles {
+		offset := position.Sub(obstacle.Position)
+		reach := obstacle.Radius + radius
+		distance := offset.Length()
+		if distance >= reach {
+			continue
+		}
+		
+		direction := offset.Normalize()
+		if distance == 0 {
+			direction = gamemath.Vector2D{X: 1}
+		}
+		position = obstacle.Position.Add(direction.Mul(reach))
+	}
+	return position
+}
+
+// IsShotBlocked reports whether an obstacle stands between the shooter and the target
+func (tg *TerrainGrid) IsShotBlocked(from, to gamemath.Vector2D) bool {
+	segment := to.Sub(from)
+	lengthSquared := segment.Dot(segment)
+	for _, obstacle := range tg.Obstacles {
+		// Closest point of the line of fire to the obstacle's center
+		t := 0.0
+		if lengthSquared > 0 {
+			t = max(0, min(1, obstacle.Position.Sub(from).Dot(segment)/lengthSquared))
+		}
+		if from.Add(segment.Mul(t)).Distance(obstacle.Position) < obstacle.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+// avoidObstacles steers the unit away from nearby obstacles, sidestepping those in its path
+func (u *Unit) avoidObstacles() {
+	if u.Terrain == nil {
+		return
+	}
+	
+	heading := u.Target.Sub(u.Position).Normalize()
+	radius := u.GetCollisionRadius()
+	for _, obstacle := range u.Terrain.Obstacles {
+		offset := u.Position.Sub(obstacle.Position)
+		gap := offset.Length() - obstacle.Radius - radius
+		if gap >= obstacleAvoidMargin {
+			continue
+		}
+		
+		// Obstacles ahead are passed on the side the unit already leans toward, so it does not stall head-on
+		away := offset.Normalize()
+		if heading.Dot(away) < 0 {
+			side := gamemath.Vector2D{X: -heading.Y, Y: heading.X}
+			if side.Dot(away) < 0 {
+				side = side.Mul(-1)
+			}
+			away = away.Add(side).Normalize()
+		}
+		
+		strength := (1 - max(gap, 0)/obstacleAvoidMargin) * obstacleAvoidWeight
+		u.Steering = u.Steering.Add(away.Mul(strength))
+	}
+}
@@ -0,0 +1,242 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// OrderType represents the kind of player order
+type OrderType int
+
+const (
+	OrderMove     OrderType = iota // 移動
+	OrderGarrison                  // 櫓に入る（StructureID の櫓）
+	OrderEject                     // 櫓から出る
+)
+
+// orderArrivalDistance is how close the leader must get to complete a move order
+const orderArrivalDistance = 20.0
+
+// Order represents a player order issued to a group
+type Order struct {
+	Type        OrderType
+	ArmyID      int
+	GroupID     int
+	Target      gamemath.Vector2D
+	Player      int // 命令した協力プレイのプレイヤー（0: 1人プレイ）
+	StructureID int // 入る櫓（OrderGarrison のみ）
+}
+
+// IssueOrder pays for an order and applies it to its group immediately
+func (bm *BattleManager) IssueOrder(order Order) bool {
+	if !bm.canReceiveOrder(order) || !bm.spendCommandPoints(order) {
+		return false
+	}
+	return bm.applyOrder(order)
+}
+
+// canReceiveOrder reports whether the ordered group has a leader able to act
+// and is commanded by the player who gave the order
+func (bm *BattleManager) canReceiveOrder(order Order) bool {
+	group := bm.FindGroup(order.ArmyID, order.GroupID)
+	if group == nil || (group.Controller != 0 && group.Controller != order.Player) {
+		return false
+	}
+	return group.Leader != nil && group.Leader.IsAlive && !group.Leader.IsRetreating && !group.Leader.Convoy
+}
+
+// applyOrder applies an already paid order to its group
+func (bm *BattleManager) applyOrder(order Order) bool {
+	if !bm.canReceiveOrder(order) {
+		return false
+	}
+	group := bm.FindGroup(order.ArmyID, order.GroupID)
+	
+	switch order.Type {
+	case OrderMove:
+		// Moving out means leaving the tower first
+		ejectGroup(group)
+		group.CurrentOrder = &order
+		group.MoveGroup(order.Target)
+		bm.recordEvent(BattleEvent{Type: EventOrder, GroupID: group.ID, Position: order.Target, Player: order.Player})
+	case OrderGarrison:
+		tower := bm.getStructure(order.StructureID)
+		if tower == nil || !bm.CanGarrison(tower, group.ArmyID) {
+			return false
+		}
+		order.Target = tower.Center()
+		group.CurrentOrder = &order
+		group.MoveGroup(order.Target)
+		bm.recordEvent(BattleEvent{Type: EventOrder, GroupID: group.ID, Position: order.Target, Player: order.Player})
+	case OrderEject:
+		ejectGroup(group)
+	}
+	
+	return true
+}
+
+// QueueOrder pays for an order and stores it to be issued later
+// A queued order for the same group is replaced and its cost refunded
+func (bm *BattleManager) QueueOrder(order Order) bool {
+	for i, queued := range bm.QueuedOrders {
+		if queued.ArmyID != order.ArmyID || queued.GroupID != order.GroupID {
+			continue
+		}
+		
+		// A refused order leaves the queued one and its payment as they were
+		if !bm.canReceiveOrder(order) {
+			return false
+		}
+		if bm.CommandPoints != nil {
+			bm.CommandPoints.refund(GetOrderCost(queued))
+		}
+		if !bm.spendCommandPoints(order) {
+			if bm.CommandPoints != nil {
+				bm.CommandPoints.Spend(GetOrderCost(queued))
+			}
+			return false
+		}
+		bm.QueuedOrders[i] = order
+		return true
+	}
+	
+	if !bm.canReceiveOrder(order) || !bm.spendCommandPoints(order) {
+		return false
+	}
+	bm.QueuedOrders = append(bm.QueuedOrders, order)
+	return true
+}
+
+// ExecuteQueuedOrders issues all queued orders, which were paid when queued
+func (bm *BattleManager) ExecuteQueuedOrders() {
+	for _, order := range bm.QueuedOrders {
+		bm.applyOrder(order)
+	}
+	bm.QueuedOrders = nil
+}
+
+// AssignGroup hands command of a group to a co-op player (0: any player)
+func (bm *BattleManager) AssignGroup(armyID, groupID, player int) bool {
+	group := bm.FindGroup(armyID, groupID)
+	if group == nil {
+		return false
+	}
+	group.Controller = player
+	bm.recordEvent(BattleEvent{Type: EventAssign, GroupID: group.ID, Player: player})
+	return true
+}
+
+// ValidateOrder checks an order a co-op player sent against what that player could have issued
+// Orders that only failed on timing (a leader fell, points ran out) pass; IssueOrder refuses those
+func (bm *BattleManager) ValidateOrder(order Order, player int) error {
+	if order.Player != player {
+		return fmt.Errorf("order signed by player %d", order.Player)
+	}
+	
+	group := bm.FindGroup(order.ArmyID, order.GroupID)
+	if group == nil {
+		return fmt.Errorf("no group %d in army %d", order.GroupID, order.ArmyID)
+	}
+	if group.Controller != 0 && group.Controller != player {
+		return fmt.Errorf("group %d is commanded by player %d", group.ID, group.Controller)
+	}
+	
+	switch order.Type {
+	case OrderMove:
+		width, height := bm.getWorldSize()
+		if order.Target.X < 0 || order.Target.Y < 0 || order.Target.X > width || order.Target.Y > height {
+			return fmt.Errorf("move target (%.0f, %.0f) is off the battlefield", order.Target.X, order.Target.Y)
+		}
+	case OrderGarrison:
+		if tower := bm.getStructure(order.StructureID); tower == nil || tower.Kind != data.StructureTower {
+			return fmt.Errorf("no tower %d", order.StructureID)
+		}
+	case OrderEject:
+	default:
+		return fmt.Errorf("unknown order type %d", order.Type)
+	}
+	
+	if bm.CommandPoints != nil && GetOrderCost(order) > bm.CommandPoints.Max {
+		return fmt.Errorf("order costs more than the whole budget")
+	}
+	return nil
+}
+
+// ValidateAssignment checks that a co-op player may hand the group to the assignee
+func (bm *BattleManager) ValidateAssignment(armyID, groupID, player, assignee, players int) error {
+	group := bm.FindGroup(armyID, groupID)
+	if group == nil {
+		return fmt.Errorf("no group %d in army %d", groupID, armyID)
+	}
+	if group.Controller != 0 && group.Controller != player {
+		return fmt.Errorf("group %d is commanded by player %d", group.ID, group.Controller)
+	}
+	if assignee < 0 || assignee > players {
+		return fmt.Errorf("no player %d", assignee)
+	}
+	return nil
+}
+
+// SplitGroups shares an army's groups between co-op players in turn, starting with player 1
+func (bm *BattleManager) SplitGroups(armyID, players int) {
+	army := bm.GetArmy(armyID)
+	if army == nil || players <= 0 {
+		return
+	}
+	for i, group := range army.Groups {
+		group.Controller = i%players + 1
+	}
+}
+
+// FindGroup returns the group with the given ID in the army
+func (bm *BattleManager) FindGroup(armyID, groupID int) *Group {
+	army := bm.GetArmy(armyID)
+	if army == nil {
+		return nil
+	}
+	
+	for _, group := range army.Groups {
+		if group.ID == groupID {
+			return group
+		}
+	}
+	return nil
+}
+
+// updateOrders completes or cancels active group orders
+func (bm *BattleManager) updateOrders() {
+	for _, army := range bm.Armies {
+		for _, group := range army.Groups {
+			if group.CurrentOrder == nil {
+				continue
+			}
+			
+			leader := group.Leader
+			if leader == nil || !leader.IsAlive || leader.IsRetreating {
+				group.CurrentOrder = nil
+				continue
+			}
+			
+			// Garrison orders end inside the tower (see updateGarrisons)
+			order := group.CurrentOrder
+			if order.Type == OrderMove && leader.Position.Distance(order.Target) <= orderArrivalDistance {
+				group.CurrentOrder = nil
+			}
+		}
+	}
+}
+
+// getOrderedGroupIDs returns the IDs of groups currently executing an order
+func (bm *BattleManager) getOrderedGroupIDs() map[int]bool {
+	ordered := make(map[int]bool)
+	for _, army := range bm.Armies {
+		for _, group := range army.Groups {
+			if group.CurrentOrder != nil {
+				ordered[group.ID] = true
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,54 @@
+package game
+
+import (
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// SpellProjectile is a spell flying towards its target; it strikes on reaching the target
+// and fizzles out if the target falls first
+type SpellProjectile struct {
+	Position gamemath.Vector2D
+	Caster   *Unit
+	Target   *Unit
+	Ability  *Ability
+	damage   int             // 当たったときのダメージ（放った時点で防御を差し引いたもの）
+	event    BattleEventType // 戦闘ログに残す命中の種類（潜伏からの不意打ちなど）
+}
+
+// launchSpell lets the spell the unit cast on its hit frame fly at the target
+func (bm *BattleManager) launchSpell(caster *Unit, event BattleEventType) {
+	target, damage := caster.releaseAttack()
+	if damage <= 0 {
+		return
+	}
+	bm.Projectiles = append(bm.Projectiles, &SpellProjectile{
+		Position: caster.Position,
+		Caster:   caster,
+		Target:   target,
+		Ability:  caster.casting,
+		damage:   damage,
+		event:    event,
+	})
+}
+
+// updateProjectiles moves the spells in flight after their targets and lets those that reach them strike
+func (bm *BattleManager) updateProjectiles(deltaTime float64) {
+	flying := bm.Projectiles[:0]
+	for _, projectile := range bm.Projectiles {
+		target := projectile.Target
+		if !target.IsAlive {
+			continue
+		}
+		
+		offset := target.Position.Sub(projectile.Position)
+		step := projectile.Ability.ProjectileSpeed * deltaTime
+		if offset.Length() > step+target.GetCollisionRadius() {
+			projectile.Position = projectile.Position.Add(offset.Normalize().Mul(step))
+			flying = append(flying, projectile)
+			continue
+		}
+		target.TakeDamage(projectile.damage)
+		bm.recordStrike(projectile.Caster, target, projectile.damage, projectile.event, projectile.Ability)
+	}
+	bm.Projectiles = flying
+}
@@ -0,0 +1,47 @@
+package game
+
+import "math/rand"
+
+// countingSource is a seeded random source that counts its draws, so a rewound battle can put it
+// back by seeding it again and drawing as many values as it had
+type countingSource struct {
+	seed   int64
+	source rand.Source64
+	draws  uint64
+}
+
+// newRandom returns a random generator seeded with the seed and the counting source behind it
+func newRandom(seed int64) (*rand.Rand, *countingSource) {
+	source := &countingSource{seed: seed, source: rand.NewSource(seed).(rand.Source64)}
+	return rand.New(source), source
+}
+
+// Int63 draws a non-negative 63-bit value
+func (cs *countingSource) Int63() int64 {
+	cs.draws++
+	return cs.source.Int63()
+}
+
+// Uint64 draws a 64-bit value
+func (cs *countingSource) Uint64() uint64 {
+	cs.draws++
+	return cs.source.Uint64()
+}
+
+// Seed starts the source over from the seed
+func (cs *countingSource) Seed(seed int64) {
+	cs.seed = seed
+	cs.draws = 0
+	cs.source.Seed(seed)
+}
+
+// seek puts the source where it was after the given number of draws
+func (cs *countingSource) seek(draws uint64) {
+	if draws < cs.draws {
+		cs.source.Seed(cs.seed)
+		cs.draws = 0
+	}
+	for cs.draws < draws {
+		cs.Uint64()
+	}
+}
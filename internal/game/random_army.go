@@ -0,0 +1,49 @@
+package game
+
+import (
+	"math/rand"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// RandomArmyPreset makes CreateArmies field a random composition of the hireable groups
+const RandomArmyPreset = "ランダム編成"
+
+// randomArmyGroups draws hireable groups at random strengths until the point budget, the group limit
+// or the deployment points run out; the composition is one the recruitment screen could deploy
+func randomArmyGroups(recruitment *data.RecruitmentConfig, rng *rand.Rand, slots int) []PresetGroup {
+	if recruitment == nil {
+		return nil
+	}
+	if recruitment.MaxGroups > 0 {
+		slots = min(slots, recruitment.MaxGroups)
+	}
+	
+	var groups []PresetGroup
+	remaining := recruitment.PointBudget
+	for len(groups) < slots {
+		// Only the groups the remaining points can field at their hired strength
+		var affordable []data.MercenaryConfig
+		for _, recruit := range recruitment.Recruits {
+			if recruit.GroupPoints(recruit.Count) <= remaining {
+				affordable = append(affordable, recruit)
+			}
+		}
+		if len(affordable) == 0 {
+			break
+		}
+		
+		recruit := affordable[rng.Intn(len(affordable))]
+		count := recruit.Count
+		if recruit.MaxCount > recruit.Count {
+			count += rng.Intn(recruit.MaxCount - recruit.Count + 1)
+		}
+		for count > recruit.Count && recruit.GroupPoints(count) > remaining {
+			count--
+		}
+		
+		groups = append(groups, PresetGroup{recruit.Leader, recruit.Member, count})
+		remaining -= recruit.GroupPoints(count)
+	}
+	return groups
+}
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Reinforcement tracks a scripted reinforcement wave
+type Reinforcement struct {
+	Config  data.ReinforcementConfig
+	Arrived bool
+}
+
+// Announcement is a battle message shown to the player
+type Announcement struct {
+	Text string
+	Time float64 // Battle time when the message was posted
+}
+
+// NewReinforcements creates reinforcement waves from the stage configuration
+func NewReinforcements(configs []data.ReinforcementConfig) []*Reinforcement {
+	waves := make([]*Reinforcement, 0, len(configs))
+	for _, config := range configs {
+		waves = append(waves, &Reinforcement{Config: config})
+	}
+	return waves
+}
+
+// Announce posts a battle message
+func (bm *BattleManager) Announce(text string) {
+	bm.Announcements = append(bm.Announcements, Announcement{Text: text, Time: bm.BattleTime})
+}
+
+// updateReinforcements spawns reinforcement waves whose trigger is met
+func (bm *BattleManager) updateReinforcements() {
+	if bm.dataManager == nil {
+		return
+	}
+	
+	for _, wave := range bm.Reinforcements {
+		if wave.Arrived || !bm.isReinforcementTriggered(wave) {
+			continue
+		}
+		
+		bm.spawnReinforcement(wave)
+	}
+}
+
+// isReinforcementTriggered checks the trigger condition of a wave
+func (bm *BattleManager) isReinforcementTriggered(wave *Reinforcement) bool {
+	if bm.BattleTime < wave.Config.TriggerTime {
+		return false
+	}
+	
+	army := bm.GetArmy(wave.Config.ArmyID())
+	if army == nil || army.IsRouted {
+		return false
+	}
+	
+	switch wave.Config.Trigger {
+	case data.TriggerHealthBelow:
+		return army.GetTotalHealth() < wave.Config.Threshold
+	case data.TriggerCommanderLost:
+		commander := army.GetCommander()
+		return commander != nil && !commander.IsAlive
+	default:
+		return true
+	}
+}
+
+// spawnReinforcement creates the groups of a wave at its spawn point
+func (bm *BattleManager) spawnReinforcement(wave *Reinforcement) {
+	wave.Arrived = true
+	
+	army := bm.GetArmy(wave.Config.ArmyID())
+	bm.spawnGroups(army, gamemath.Vector2D{X: wave.Config.X, Y: wave.Config.Y}, wave.Config.Groups)
+	
+	name := wave.Config.Name
+	if name == "" {
+		name = "増援"
+	}
+	bm.Announce(fmt.Sprintf("%sに%sが到着！", army.Name, name))
+}
+
+// spawnGroups creates the groups for the army at the spawn point, stacked vertically around it
+func (bm *BattleManager) spawnGroups(army *Army, spawnPoint gamemath.Vector2D, groups []data.ReinforcementGroupConfig) {
+	for i, groupConfig := range groups {
+		position := spawnPoint.Add(gamemath.Vector2D{Y: float64(i) * 80})
+		
+		group := bm.createGroup(army.ID, groupConfig.Leader, groupConfig.Member, groupConfig.Count, position, bm.dataManager)
+		if group == nil {
+			continue
+		}
+		bm.equip(group, groupConfig.Equipment, bm.dataManager)
+		army.AddGroup(group)
+	}
+}
@@ -0,0 +1,249 @@
+package game
+
+import (
+	"fmt"
+	stdmath "math"
+	"sort"
+	"strings"
+
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// GetRulesSummary returns the lines shown on the pre-battle rules card
+func (bm *BattleManager) GetRulesSummary() []string {
+	lines := []string{
+		fmt.Sprintf("ステージ: %s (%s)", bm.Stage.Name, bm.TerrainData.Name),
+	}
+	
+	// Sides of multi-faction battles
+	if len(bm.Armies) > 2 {
+		lines = append(lines, "陣営: "+strings.Join(bm.allianceNames(), " / "))
+	}
+	
+	lines = append(lines,
+		"",
+		"勝利条件:",
+		"・敵軍を全滅させる",
+		fmt.Sprintf("・敵軍の士気を%d%%未満に下げて総崩れさせる", int(ArmyMoraleCollapse*100)),
+	)
+	
+	// Stage-specific victory conditions
+	for _, objective := range bm.Objectives {
+		lines = append(lines, "・"+objective.Description())
+	}
+	
+	// Territory control
+	if len(bm.CapturePoints) > 0 && bm.Stage.ScoreLimit > 0 {
+		lines = append(lines, fmt.Sprintf("・拠点%d箇所を確保し戦果%.0fに到達する", len(bm.CapturePoints), bm.Stage.ScoreLimit))
+	}
+	
+	// Neutral creatures
+	if len(bm.NeutralCamps) > 0 {
+		var names []string
+		for _, camp := range bm.NeutralCamps {
+			names = append(names, camp.Config.Name)
+		}
+		lines = append(lines, fmt.Sprintf("・中立勢力（%s）は近づく軍勢をすべて攻撃する", strings.Join(names, "、")))
+	}
+	
+	// Time limit
+	if bm.TimeLimit > 0 {
+		minutes := int(bm.TimeLimit) / 60
+		seconds := int(bm.TimeLimit) % 60
+		lines = append(lines, fmt.Sprintf("・制限時間 %02d:%02d 経過時は残存戦力で判定", minutes, seconds))
+	}
+	
+	// Command points
+	if bm.CommandPoints != nil {
+		lines = append(lines, "", fmt.Sprintf("指揮力: 移動命令1回につき%.0f消費（最大%.0f、%.0f秒で1回復）",
+			OrderMoveCost, bm.CommandPoints.Max, 1/bm.CommandPoints.RegenRate))
+	}
+	
+	// Chain of command
+	if bm.CommandDelay {
+		lines = append(lines, "", "命令伝達: 命令はリーダーから離れたメンバーほど遅れて届く（リーダーの気絶中は止まる）")
+	}
+	
+	// Supply points
+	var supplyLines []string
+	for _, point := range bm.Stage.SupplyPoints {
+		if armyID := point.ArmyID(); armyID < len(bm.Armies) {
+			supplyLines = append(supplyLines, fmt.Sprintf("・%s: %sの矢弾を補充できる", point.Name, bm.Armies[armyID].Name))
+		}
+	}
+	if len(supplyLines) > 0 {
+		lines = append(lines, "", "補給地点:")
+		lines = append(lines, supplyLines...)
+	}
+	
+	// Gates, walls and towers
+	if len(bm.Structures) > 0 {
+		lines = append(lines, "", "建造物（崩すまで通れない。自軍の門は通行可）:")
+		for _, structure := range bm.Structures {
+			owner := "所有者なし"
+			if army := bm.GetArmy(structure.ArmyID); army != nil {
+				owner = army.Name
+			}
+			line := fmt.Sprintf("・%s（%s、%s）: 耐久%d", structure.Name, structure.KindName(), owner, structure.MaxHP)
+			if structure.Capacity > 0 {
+				line += fmt.Sprintf("、%d人まで駐留可", structure.Capacity)
+			}
+			lines = append(lines, line)
+		}
+	}
+	
+	// Battle mutators
+	if len(bm.Mutators) > 0 {
+		lines = append(lines, "", "特殊ルール:")
+		for _, mutator := range bm.Mutators {
+			lines = append(lines, fmt.Sprintf("・%s: %s", mutator.Name, mutator.Description))
+		}
+	}
+	
+	// Weather at the start and the changes the stage's triggers bring
+	var weatherLines []string
+	if bm.HasWind() {
+		weatherLines = append(weatherLines, "・"+bm.WindText())
+	}
+	for _, trigger := range bm.Triggers {
+		if trigger.Fired {
+			continue
+		}
+		for _, action := range trigger.Config.Actions {
+			if action.Type == data.ActionWeather {
+				weatherLines = append(weatherLines, fmt.Sprintf("・%s: %s", bm.triggerWhen(trigger.Config), weatherChange(action)))
+			}
+		}
+	}
+	if len(weatherLines) > 0 {
+		lines = append(lines, "", "天候:")
+		lines = append(lines, weatherLines...)
+	}
+	
+	// Army doctrines
+	var doctrineLines []string
+	for _, army := range bm.Armies {
+		if army.Doctrine != nil {
+			doctrineLines = append(doctrineLines, fmt.Sprintf("・%s: %s（%s）", army.Name, army.Doctrine.Name, army.Doctrine.Description))
+		}
+	}
+	if len(doctrineLines) > 0 {
+		lines = append(lines, "", "ドクトリン:")
+		lines = append(lines, doctrineLines...)
+	}
+	
+	// Terrain modifiers
+	lines = append(lines, "", "地形効果:")
+	lines = append(lines, TerrainModifierLines(bm.TerrainData, bm.dataManager)...)
+	
+	return lines
+}
+
+// TerrainModifierLines describes the terrain modifiers that differ from 100%, naming unit types
+// from the data manager (nil: by their type)
+func TerrainModifierLines(terrain data.TerrainConfig, dataManager *data.DataManager) []string {
+	type modifier struct {
+		label string
+		value float64
+	}
+	modifiers := []modifier{
+		{"移動速度", terrain.MovementModifier},
+		{"防御力", terrain.DefenseModifier},
+	}
+	
+	// Attack bonuses per unit type, then the default for the unlisted ones
+	unitTypes := make([]string, 0, len(terrain.AttackBonuses))
+	for unitType := range terrain.AttackBonuses {
+		unitTypes = append(unitTypes, unitType)
+	}
+	sort.Strings(unitTypes)
+	for _, unitType := range unitTypes {
+		modifiers = append(modifiers, modifier{unitTypeName(dataManager, unitType) + "攻撃", terrain.AttackBonuses[unitType]})
+	}
+	if terrain.DefaultAttackBonus > 0 {
+		modifiers = append(modifiers, modifier{"その他の攻撃", terrain.DefaultAttackBonus})
+	}
+	
+	var lines []string
+	for _, m := range modifiers {
+		if m.value == 0 || m.value == 1.0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("・%s %+d%%", m.label, int(stdmath.Round((m.value-1.0)*100))))
+	}
+	
+	if len(lines) == 0 {
+		lines = append(lines, "・なし")
+	}
+	return lines
+}
+
+// triggerWhen describes when a stage trigger fires, like "03:00" or "軍勢Bが10体を下回ると"
+func (bm *BattleManager) triggerWhen(config data.TriggerConfig) string {
+	clock := fmt.Sprintf("%02d:%02d", int(config.Time)/60, int(config.Time)%60)
+	who := "いずれかの軍勢"
+	if army := bm.GetArmy(config.ArmyID()); army != nil {
+		who = army.Name
+	}
+	
+	var when string
+	switch config.Condition {
+	case data.ConditionUnitsBelow:
+		when = fmt.Sprintf("%sが%d体を下回ると", who, config.Count)
+	case data.ConditionZoneEntered:
+		when = fmt.Sprintf("%sが (%.0fm, %.0fm) 付近に入ると", who, config.X/10, config.Y/10)
+	default:
+		return clock
+	}
+	if config.Time > 0 {
+		when = clock + "以降、" + when
+	}
+	return when
+}
+
+// weatherChange describes the weather a trigger's weather action brings
+func weatherChange(action data.TriggerActionConfig) string {
+	switch action.Weather {
+	case data.WeatherWind:
+		return windText(triggerWind(action)) + "が吹き始める"
+	case data.WeatherCalm:
+		return "風がやむ"
+	case data.WeatherFog:
+		return "霧が立ち込め、味方の視界外の敵が見えなくなる"
+	case data.WeatherClear:
+		return "霧が晴れる"
+	default:
+		return action.Weather
+	}
+}
+
+// unitTypeName returns the display name of a unit type, or the type itself when it is unknown
+func unitTypeName(dataManager *data.DataManager, unitType string) string {
+	if dataManager != nil {
+		if config, err := dataManager.GetUnitConfig(unitType); err == nil && config.Name != "" {
+			return config.Name
+		}
+	}
+	return unitType
+}
+
+// allianceNames returns one entry per alliance listing its army names
+func (bm *BattleManager) allianceNames() []string {
+	var alliances []string
+	listed := make(map[int]bool)
+	for _, army := range bm.Armies {
+		if listed[army.ID] {
+			continue
+		}
+		
+		var names []string
+		for _, other := range bm.Armies {
+			if !listed[other.ID] && army.IsAlliedWith(other.ID) {
+				listed[other.ID] = true
+				names = append(names, other.Name)
+			}
+		}
+		alliances = append(alliances, strings.Join(names, "・"))
+	}
+	return alliances
+}
@@ -0,0 +1,87 @@
+package game
+
+// Stamina tuning
+const (
+	defaultMaxStamina     = 100.0
+	sprintSpeedRatio      = 0.6  // 移動速度のこの割合を超えて動くと全力疾走とみなす
+	staminaSprintDrain    = 5.0  // 全力疾走中に1秒で失うスタミナ
+	staminaAttackCost     = 3.0  // 攻撃1回で失うスタミナ
+	staminaRegenRate      = 8.0  // 待機中に1秒で回復するスタミナ
+	heavyArmorDrain       = 1.5  // 重装備のユニットの消耗の倍率
+	exhaustedThreshold    = 0.25 // スタミナがこの割合を下回ると疲労困憊
+	exhaustedSpeed        = 0.7  // 疲労困憊中の移動速度の倍率
+	exhaustedCooldown     = 1.5  // 疲労困憊中の攻撃間隔の倍率
+	groupFatigueThreshold = 0.5  // 部隊の平均スタミナがこの割合を下回ると部隊全体が息を整える
+	fatiguedSlotSlack     = 30.0 // 疲れた部隊のメンバーは隊形の位置からこの距離（3m）以内なら動かない
+)
+
+// IsExhausted reports whether the unit is too tired to fight and move at full strength
+func (u *Unit) IsExhausted() bool {
+	return u.MaxStamina > 0 && u.Stamina < u.MaxStamina*exhaustedThreshold
+}
+
+// fatigueSpeed returns the movement multiplier from the unit's fatigue
+func (u *Unit) fatigueSpeed() float64 {
+	if u.IsExhausted() {
+		return exhaustedSpeed
+	}
+	return 1.0
+}
+
+// fatigueCooldown returns the attack interval multiplier from the unit's fatigue
+func (u *Unit) fatigueCooldown() float64 {
+	if u.IsExhausted() {
+		return exhaustedCooldown
+	}
+	return 1.0
+}
+
+// drainStamina spends stamina, faster for units in heavy armor
+func (u *Unit) drainStamina(amount float64) {
+	if u.HeavyArmor {
+		amount *= heavyArmorDrain
+	}
+	u.Stamina = max(0, u.Stamina-amount)
+}
+
+// updateStamina drains stamina while sprinting and recovers it while idle
+func (u *Unit) updateStamina(deltaTime float64, isMoving bool) {
+	switch {
+	case u.Velocity.Length() > u.Speed*sprintSpeedRatio:
+		u.drainStamina(staminaSprintDrain * deltaTime)
+	case !isMoving && u.SwingTarget == nil && u.LastAttackTime <= 0:
+		u.Stamina = min(u.MaxStamina, u.Stamina+staminaRegenRate*deltaTime)
+	}
+}
+
+// GetStaminaRatio returns the average stamina of the group's fighting units (0.0-1.0)
+func (g *Group) GetStaminaRatio() float64 {
+	units := g.getAliveMembers()
+	if g.Leader != nil && g.Leader.IsAlive && !g.Leader.IsRetreating {
+		units = append(units, g.Leader)
+	}
+	
+	total, capacity := 0.0, 0.0
+	for _, unit := range units {
+		total += unit.Stamina
+		capacity += unit.MaxStamina
+	}
+	if capacity == 0 {
+		return 1.0
+	}
+	return total / capacity
+}
+
+// updateFatigue lets tired groups ease up: members stop chasing formation slots and the AI stops pressing
+func (bm *BattleManager) updateFatigue() {
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, group := range army.Groups {
+			group.fatigued = group.GetStaminaRatio() < groupFatigueThreshold
+			for _, unit := range group.GetAllUnits() {
+				if unit.AI != nil {
+					unit.AI.Fatigued = group.fatigued
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+)
+
+// StateHash digests the simulation state so two lockstep peers can check they still agree
+// It covers what decides the battle: unit positions and health, structures, fires, sprung traps and the order budget
+func (bm *BattleManager) StateHash() uint64 {
+	h := fnv.New64a()
+	writeHashValue(h, uint64(bm.nextUnitID))
+	writeHashFloat(h, bm.BattleTime)
+	
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, unit := range army.GetAllUnits() {
+			writeHashValue(h, uint64(unit.ID))
+			writeHashFloat(h, unit.Position.X)
+			writeHashFloat(h, unit.Position.Y)
+			writeHashValue(h, uint64(int64(unit.HP)))
+			writeHashBool(h, unit.IsAlive)
+			writeHashBool(h, unit.IsRetreating)
+		}
+	}
+	
+	for _, structure := range bm.Structures {
+		writeHashValue(h, uint64(int64(structure.HP)))
+	}
+	for index, fire := range bm.Terrain.Fire {
+		if fire > 0 || bm.Terrain.Burnt[index] {
+			writeHashValue(h, uint64(index))
+			writeHashFloat(h, fire)
+		}
+	}
+	for _, trap := range bm.Traps {
+		writeHashBool(h, trap.Triggered)
+		writeHashBool(h, trap.Revealed)
+	}
+	
+	if bm.CommandPoints != nil {
+		writeHashFloat(h, bm.CommandPoints.Current)
+	}
+	return h.Sum64()
+}
+
+// writeHashValue feeds a value to the hash in a fixed byte order
+func writeHashValue(h hash.Hash64, value uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], value)
+	h.Write(buf[:])
+}
+
+// writeHashFloat feeds the exact bits of a float to the hash
+func writeHashFloat(h hash.Hash64, value float64) {
+	writeHashValue(h, math.Float64bits(value))
+}
+
+// writeHashBool feeds a flag to the hash
+func writeHashBool(h hash.Hash64, value bool) {
+	if value {
+		writeHashValue(h, 1)
+	} else {
+		writeHashValue(h, 0)
+	}
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Stealth tuning
+const (
+	stealthDetectRange     = 100.0 // 潜伏ユニットに気付ける距離（10m）
+	coverDetectFactor      = 0.5   // 森・藪の中では気付ける距離がこの倍率になる
+	treeCoverMargin        = 30.0  // 木の幹からこの距離までは森と同じく身を隠せる
+	ambushDamageMultiplier = 2.0   // 潜伏中の一撃の基本ダメージ倍率
+	restealthDelay         = 6.0   // 戦闘をやめてから再び潜伏するまでの秒数
+)
+
+// IsCover reports whether the position lies in a forest, a thicket or among trees; burnt ground hides nobody
+func (tg *TerrainGrid) IsCover(position gamemath.Vector2D) bool {
+	if tg == nil {
+		return false
+	}
+	col, row := tg.cellAt(position)
+	if index := row*tg.Cols + col; tg.Cover[index] && !tg.Burnt[index] {
+		return true
+	}
+	for _, obstacle := range tg.Obstacles {
+		if obstacle.Kind == data.ObstacleTree && obstacle.Position.Distance(position) <= obstacle.Radius+treeCoverMargin {
+			return true
+		}
+	}
+	return false
+}
+
+// reveal breaks the unit's stealth until it has stayed out of the fight for a while
+func (u *Unit) reveal() {
+	if !u.Stealth {
+		return
+	}
+	u.Hidden = false
+	u.stealthCooldown = restealthDelay
+}
+
+// getDetectRange returns how close a unit of another alliance must come to spot the hidden unit
+func (bm *BattleManager) getDetectRange(unit *Unit) float64 {
+	detectRange := stealthDetectRange * bm.getNightFactor(nightDetectFactor)
+	if bm.Terrain.IsCover(unit.Position) {
+		return detectRange * coverDetectFactor
+	}
+	return detectRange
+}
+
+// isDetected reports whether the army or its allies can see the unit, hidden or not
+func (bm *BattleManager) isDetected(unit *Unit, armyID int) bool {
+	if !unit.Hidden {
+		return true
+	}
+	
+	detectRange := bm.getDetectRange(unit)
+	var watchers []*Unit
+	if armyID == NeutralArmyID {
+		watchers = bm.Neutrals.GetAliveUnits()
+	} else {
+		for _, army := range bm.Armies {
+			if bm.AreAllied(armyID, army.ID) {
+				watchers = append(watchers, army.GetAliveUnits()...)
+			}
+		}
+	}
+	for _, watcher := range watchers {
+		if watcher.Position.Distance(unit.Position) <= detectRange {
+			return true
+		}
+	}
+	return false
+}
+
+// updateStealth lets scouts that stayed out of the fight slip back into hiding
+func (bm *BattleManager) updateStealth(deltaTime float64) {
+	for _, unit := range bm.getAllAliveUnits() {
+		if !unit.Stealth || unit.Hidden {
+			continue
+		}
+		if unit.isSwinging() || unit.IsRetreating {
+			unit.stealthCooldown = restealthDelay
+			continue
+		}
+		
+		unit.stealthCooldown -= deltaTime
+		if unit.stealthCooldown <= 0 {
+			unit.Hidden = true
+		}
+	}
+}
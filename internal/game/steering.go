@@ -0,0 +1,157 @@
+package game
+
+import (
+	stdmath "math"
+
+	"github.com/shirou/tinygocha/internal/math"
+)
+
+// Steering tuning
+const (
+	separationRange   = 1.5  // 衝突半径の合計のこの倍率以内の味方・敵から離れる
+	separationWeight  = 1.5  // 分離の強さ（移動速度に対する倍率）
+	cohesionWeight    = 0.3  // 部隊の結束の強さ（移動速度に対する倍率）
+	cohesionSlack     = 1.5  // 隊形半径のこの倍率を超えて離れたメンバーだけがリーダーへ寄る
+	arrivalRadius     = 40.0 // 目標地点の手前4mから減速する
+	steeringResponse  = 8.0  // 速度が目標の速度に追従する速さ（1/秒）
+	maxSteeringFactor = 1.5  // 回避を含めた最高速度（移動速度に対する倍率）
+)
+
+// updateSteering computes the separation and cohesion forces each unit applies on its next move
+func (bm *BattleManager) updateSteering() {
+	units := bm.getAllAliveUnits()
+	for _, unit := range units {
+		unit.Steering = math.Vector2D{}
+	}
+	
+	// Separation: neighbours push each other apart, stronger the closer they are
+	for i := 0; i < len(units); i++ {
+		for j := i + 1; j < len(units); j++ {
+			unit1, unit2 := units[i], units[j]
+			
+			// Flyers pass over troops on the ground
+			if unit1.Flying != unit2.Flying {
+				continue
+			}
+			reach := (unit1.GetCollisionRadius() + unit2.GetCollisionRadius()) * separationRange
+			offset := unit1.Position.Sub(unit2.Position)
+			distance := offset.Length()
+			if distance >= reach {
+				continue
+			}
+			
+			// Units on the same spot separate along a direction fixed by their IDs
+			direction := offset.Normalize()
+			if distance == 0 {
+				angle := float64(unit1.ID*7+unit2.ID) * 0.618 * 2 * stdmath.Pi
+				direction = math.Vector2D{X: stdmath.Cos(angle), Y: stdmath.Sin(angle)}
+			}
+			
+			push := direction.Mul((1 - distance/reach) * separationWeight)
+			unit1.Steering = unit1.Steering.Add(push)
+			unit2.Steering = unit2.Steering.Sub(push)
+		}
+	}
+	
+	// Trees and boulders: units swerve around them
+	for _, unit := range units {
+		if !unit.Flying {
+			unit.avoidObstacles()
+		}
+	}
+	
+	// Cohesion: stragglers drift back toward their leader
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, group := range army.Groups {
+			group.applyCohesion()
+		}
+	}
+}
+
+// applyCohesion steers members that strayed from the formation back toward the leader
+func (g *Group) applyCohesion() {
+	if g.Leader == nil || !g.Leader.IsAlive {
+		return
+	}
+	
+	slack := g.Formation.Radius * cohesionSlack
+	for _, member := range g.getAliveMembers() {
+		offset := g.Leader.Position.Sub(member.Position)
+		distance := offset.Length()
+		if distance <= slack {
+			continue
+		}
+		
+		strength := stdmath.Min((distance-slack)/slack, 1.0) * cohesionWeight
+		member.Steering = member.Steering.Add(offset.Normalize().Mul(strength))
+	}
+}
+
+// steer moves the unit toward its target, slowing on arrival and blending in the steering forces
+func (u *Unit) steer(deltaTime float64, isMoving bool) {
+	// Terrain areas slow the unit down; a unit caught on impassable ground moves freely to get out
+	terrainSpeed := u.GetSpeed()
+	if u.Terrain != nil && !u.Flying {
+		if movement := u.Terrain.GetMovement(u.Position); movement > 0 {
+			terrainSpeed *= movement
+		}
+	}
+	
+	var desired math.Vector2D
+	if isMoving {
+		toTarget := u.Target.Sub(u.Position)
+		direction := toTarget.Normalize()
+		distance := toTarget.Length()
+		
+		// Follow the shared route around terrain until the target is close
+		if !u.Flying && u.FlowField != nil && distance > flowFieldDirectRange && u.FlowField.Covers(u.Target) {
+			if flow, ok := u.FlowField.GetDirection(u.Position); ok {
+				direction = flow
+			}
+		}
+		
+		speed := terrainSpeed
+		if distance < arrivalRadius {
+			speed *= distance / arrivalRadius
+		}
+		desired = direction.Mul(speed)
+	}
+	desired = desired.Add(u.Steering.Mul(terrainSpeed))
+	
+	// Cap the speed so crowds do not shove units faster than they can move
+	maxSpeed := terrainSpeed * maxSteeringFactor
+	if speed := desired.Length(); speed > maxSpeed {
+		desired = desired.Mul(maxSpeed / speed)
+	}
+	
+	// Ease the velocity toward the desired one to avoid jitter in packed formations
+	blend := stdmath.Min(steeringResponse*deltaTime, 1.0)
+	u.Velocity = u.Velocity.Add(desired.Sub(u.Velocity).Mul(blend))
+	u.moveBy(u.Velocity.Mul(deltaTime))
+	
+	// Obstacles are solid: a unit that still bumped into one stops at its edge
+	if u.Terrain != nil && !u.Flying {
+		u.Position = u.Terrain.pushOutOfObstacles(u.Position, u.GetCollisionRadius())
+	}
+}
+
+// moveBy moves the unit, sliding along impassable terrain instead of entering it
+// Gates of the unit's own alliance stay open to it, and flyers go anywhere
+func (u *Unit) moveBy(offset math.Vector2D) {
+	next := u.Position.Add(offset)
+	if u.Terrain == nil || u.Flying || u.Terrain.isOpenTo(next, u.ArmyID) || !u.Terrain.isOpenTo(u.Position, u.ArmyID) {
+		u.Position = next
+		return
+	}
+	
+	// Keep the axis that stays on passable ground
+	if alongX := u.Position.Add(math.Vector2D{X: offset.X}); u.Terrain.isOpenTo(alongX, u.ArmyID) {
+		u.Position = alongX
+		u.Velocity.Y = 0
+	} else if alongY := u.Position.Add(math.Vector2D{Y: offset.Y}); u.Terrain.isOpenTo(alongY, u.ArmyID) {
+		u.Position = alongY
+		u.Velocity.X = 0
+	} else {
+		u.Velocity = math.Vector2D{}
+	}
+}
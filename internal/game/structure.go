@@ -0,0 +1,324 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Structure tuning
+const (
+	towerRange    = 800.0 // 櫓の射程（80m）
+	towerAttack   = 15    // 櫓の矢の攻撃力
+	towerCooldown = 2.0   // 櫓が矢を放つ間隔（秒）
+)
+
+// structureDefaults returns the durability and defense of a structure kind
+func structureDefaults(kind string) (int, int) {
+	switch kind {
+	case data.StructureGate:
+		return 800, 10
+	case data.StructureTower:
+		return 1000, 15
+	default: // 城壁
+		return 1500, 20
+	}
+}
+
+// Structure is a gate, wall or tower placed by the stage
+// Standing structures make their terrain cells impassable; towers shoot arrows at nearby enemies
+// and can be garrisoned by allied troops
+type Structure struct {
+	ID          int
+	Kind        string
+	Name        string
+	ArmyID      int    // 所有する軍勢（NeutralArmyID: 所有者なし）
+	Alliance    uint32 // 門を通れる軍勢（所有する軍勢の同盟）
+	Min, Max    gamemath.Vector2D
+	HP          int
+	MaxHP       int
+	Defense     int
+	Range       float64
+	Attack      int
+	Capacity    int // 入れるユニット数（櫓のみ、0: 入れない）
+	IsDestroyed bool
+	
+	reload float64 // 次の矢までの秒数
+}
+
+// NewStructures creates the stage's structures from their configuration
+func NewStructures(configs []data.StructureConfig) []*Structure {
+	structures := make([]*Structure, 0, len(configs))
+	for i, config := range configs {
+		hp, defense := structureDefaults(config.Kind)
+		if config.HP > 0 {
+			hp = config.HP
+		}
+		if config.Defense > 0 {
+			defense = config.Defense
+		}
+		
+		structure := &Structure{
+			ID:      i + 1,
+			Kind:    config.Kind,
+			Name:    config.Name,
+			ArmyID:  NeutralArmyID,
+			Min:     gamemath.Vector2D{X: math.Min(config.X1, config.X2), Y: math.Min(config.Y1, config.Y2)},
+			Max:     gamemath.Vector2D{X: math.Max(config.X1, config.X2), Y: math.Max(config.Y1, config.Y2)},
+			HP:      hp,
+			MaxHP:   hp,
+			Defense: defense,
+		}
+		if armyID := config.ArmyID(); armyID >= 0 {
+			structure.ArmyID = armyID
+		}
+		if config.Kind == data.StructureTower {
+			structure.Range = config.Range
+			if structure.Range <= 0 {
+				structure.Range = towerRange
+			}
+			structure.Attack = config.Attack
+			if structure.Attack <= 0 {
+				structure.Attack = towerAttack
+			}
+			structure.Capacity = max(config.Capacity, 0)
+			if config.Capacity == 0 {
+				structure.Capacity = garrisonCapacity
+			}
+		}
+		if structure.Name == "" {
+			structure.Name = structure.KindName()
+		}
+		structures = append(structures, structure)
+	}
+	return structures
+}
+
+// KindName returns the display name of the structure's kind
+func (s *Structure) KindName() string {
+	switch s.Kind {
+	case data.StructureGate:
+		return "門"
+	case data.StructureTower:
+		return "櫓"
+	default:
+		return "城壁"
+	}
+}
+
+// Center returns the middle of the structure
+func (s *Structure) Center() gamemath.Vector2D {
+	return s.Min.Add(s.Max).Mul(0.5)
+}
+
+// ClosestPoint returns the point of the structure nearest to the position
+func (s *Structure) ClosestPoint(position gamemath.Vector2D) gamemath.Vector2D {
+	return gamemath.Vector2D{
+		X: math.Max(s.Min.X, math.Min(s.Max.X, position.X)),
+		Y: math.Max(s.Min.Y, math.Min(s.Max.Y, position.Y)),
+	}
+}
+
+// DistanceTo returns the distance from the position to the structure's edge (0 inside)
+func (s *Structure) DistanceTo(position gamemath.Vector2D) float64 {
+	return position.Distance(s.ClosestPoint(position))
+}
+
+// Contains reports whether the position lies inside the structure
+func (s *Structure) Contains(position gamemath.Vector2D) bool {
+	return position.X >= s.Min.X && position.X < s.Max.X && position.Y >= s.Min.Y && position.Y < s.Max.Y
+}
+
+// GetHealthPercentage returns the structure's durability as a ratio
+func (s *Structure) GetHealthPercentage() float64 {
+	if s.MaxHP == 0 {
+		return 0
+	}
+	return float64(s.HP) / float64(s.MaxHP)
+}
+
+// LetsThrough reports whether units of the army may walk through the structure
+func (s *Structure) LetsThrough(armyID int) bool {
+	if s.IsDestroyed {
+		return true
+	}
+	return s.Kind == data.StructureGate && armyID >= 0 && armyID < 32 && s.Alliance&(1<<uint(armyID)) != 0
+}
+
+// TakeDamage wears the structure down and reports whether the hit destroyed it
+func (s *Structure) TakeDamage(damage int) bool {
+	if s.IsDestroyed {
+		return false
+	}
+	
+	s.HP -= damage
+	if s.HP > 0 {
+		return false
+	}
+	s.HP = 0
+	s.IsDestroyed = true
+	return true
+}
+
+// isHostileStructure reports whether units of the army attack the structure
+func (bm *BattleManager) isHostileStructure(structure *Structure, armyID int) bool {
+	if structure.IsDestroyed || armyID == NeutralArmyID {
+		return false
+	}
+	return structure.ArmyID == NeutralArmyID || !bm.AreAllied(armyID, structure.ArmyID)
+}
+
+// getStructureInRange returns the closest hostile structure within the unit's range, or nil
+func (bm *BattleManager) getStructureInRange(unit *Unit) *Structure {
+	var closest *Structure
+	minDistance := unit.Range + unit.GetCollisionRadius()
+	for _, structure := range bm.Structures {
+		if !bm.isHostileStructure(structure, unit.ArmyID) {
+			continue
+		}
+		if distance := structure.DistanceTo(unit.Position); distance <= minDistance {
+			closest = structure
+			minDistance = distance
+		}
+	}
+	return closest
+}
+
+// getSiegeTarget returns the hostile structure closest to the position, or nil
+func (bm *BattleManager) getSiegeTarget(armyID int, position gamemath.Vector2D) *Structure {
+	var closest *Structure
+	for _, structure := range bm.Structures {
+		if !bm.isHostileStructure(structure, armyID) {
+			continue
+		}
+		if closest == nil || structure.DistanceTo(position) < closest.DistanceTo(position) {
+			closest = structure
+		}
+	}
+	return closest
+}
+
+// updateSiegeTargets points siege engines at the nearest hostile structure
+func (bm *BattleManager) updateSiegeTargets() {
+	for _, army := range bm.Armies {
+		for _, unit := range army.GetAliveUnits() {
+			if unit.AI == nil {
+				continue
+			}
+			unit.AI.SiegeTarget = nil
+			if unit.IsSiegeEngine() {
+				unit.AI.SiegeTarget = bm.getSiegeTarget(army.ID, unit.Position)
+			}
+		}
+	}
+}
+
+// updateStructures lets towers shoot the closest enemy in range
+func (bm *BattleManager) updateStructures(deltaTime float64) {
+	for _, structure := range bm.Structures {
+		if structure.IsDestroyed || structure.Kind != data.StructureTower {
+			continue
+		}
+		
+		structure.reload -= deltaTime
+		if structure.reload > 0 {
+			continue
+		}
+		
+		var target *Unit
+		minDistance := structure.Range
+		for _, army := range bm.Armies {
+			if structure.ArmyID != NeutralArmyID && bm.AreAllied(structure.ArmyID, army.ID) {
+				continue
+			}
+			for _, enemy := range army.GetAliveUnits() {
+				if !bm.isDetected(enemy, structure.ArmyID) {
+					continue
+				}
+				if distance := structure.DistanceTo(enemy.Position); distance <= minDistance {
+					target = enemy
+					minDistance = distance
+				}
+			}
+		}
+		if target == nil {
+			continue
+		}
+		
+		damage := max(1, structure.Attack-target.GetDefense())
+		target.TakeDamage(damage)
+		structure.reload = towerCooldown
+		bm.recordEvent(BattleEvent{Type: EventTowerShot, UnitID: target.ID, GroupID: target.GroupID, OtherID: structure.ID, Amount: damage})
+		bm.Heatmap.add(HeatmapDamage, target.Position, float64(damage))
+	}
+}
+
+// damageStructure applies a unit's hit to a structure, opening the way when it falls
+func (bm *BattleManager) damageStructure(attacker *Unit, structure *Structure, damage int) {
+	bm.recordEvent(BattleEvent{Type: EventSiegeHit, UnitID: attacker.ID, GroupID: attacker.GroupID, OtherID: structure.ID, Amount: damage})
+	if !structure.TakeDamage(damage) {
+		return
+	}
+	
+	bm.Terrain.refreshMovement()
+	bm.flowFields = make(map[int]*FlowField)
+	bm.collapseGarrison(structure)
+	bm.recordEvent(BattleEvent{Type: EventStructureDestroyed, UnitID: attacker.ID, GroupID: attacker.GroupID, OtherID: structure.ID})
+	bm.Announce(fmt.Sprintf("%sが破壊された", structure.Name))
+}
+
+// refreshMovement rebuilds the movement costs: burnt ground clears the stage's slow terrain,
+// and the cells under standing structures are impassable
+func (tg *TerrainGrid) refreshMovement() {
+	copy(tg.Movement, tg.baseMovement)
+	for index, burnt := range tg.Burnt {
+		if burnt && tg.baseMovement[index] > 0 {
+			tg.Movement[index] = burntGroundMovement
+		}
+	}
+	
+	for _, structure := range tg.Structures {
+		if structure.IsDestroyed {
+			continue
+		}
+		
+		// Every cell the rectangle touches, so thin walls still block
+		minCol, minRow := tg.cellAt(structure.Min)
+		maxCol := int(math.Ceil(structure.Max.X/terrainCellSize)) - 1
+		maxRow := int(math.Ceil(structure.Max.Y/terrainCellSize)) - 1
+		for row := minRow; row <= min(maxRow, tg.Rows-1); row++ {
+			for col := minCol; col <= min(maxCol, tg.Cols-1); col++ {
+				tg.Movement[row*tg.Cols+col] = 0
+			}
+		}
+	}
+	
+	tg.uniform = true
+	for _, movement := range tg.Movement {
+		if movement != 1.0 {
+			tg.uniform = false
+			break
+		}
+	}
+}
+
+// isOpenTo reports whether the position is passable for units of the army, counting their own gates
+func (tg *TerrainGrid) isOpenTo(position gamemath.Vector2D, armyID int) bool {
+	if tg.IsPassable(position) {
+		return true
+	}
+	col, row := tg.cellAt(position)
+	if tg.baseMovement[row*tg.Cols+col] <= 0 {
+		return false
+	}
+	
+	// The cell is only closed by structures; allied gates let the unit through
+	for _, structure := range tg.Structures {
+		if !structure.IsDestroyed && structure.Contains(position) {
+			return structure.LetsThrough(armyID)
+		}
+	}
+	return false
+}
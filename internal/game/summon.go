@@ -0,0 +1,175 @@
+package game
+
+import (
+	"fmt"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Summoning tuning
+const (
+	summonSpread          = 30.0 // 召喚したユニットは召喚者からこの距離以内に現れる
+	defaultSummonInterval = 8.0  // 召喚の間隔（秒、未設定時）
+	defaultSummonLifetime = 20.0 // 召喚したユニットが消えるまでの秒数（未設定時）
+)
+
+// SummonAbility describes a unit that periodically calls minions into its group
+type SummonAbility struct {
+	UnitType string  // 召喚するユニット種別（空: 召喚しない）
+	Interval float64 // 召喚の間隔（秒）
+	Count    int     // 1回に召喚する数
+	Lifetime float64 // 召喚したユニットが消えるまでの秒数
+	Max      int     // 同時に従える数（0: 制限なし）
+	
+	cooldown float64
+}
+
+// CanSummon returns true if the unit has a summoning ability
+func (u *Unit) CanSummon() bool {
+	return u.Summon.UnitType != ""
+}
+
+// IsSummoned returns true if the unit was called up by a summoner
+func (u *Unit) IsSummoned() bool {
+	return u.SummonerID != 0
+}
+
+// updateSummons expires minions and lets summoners in combat call new ones
+func (bm *BattleManager) updateSummons(deltaTime float64) {
+	if bm.dataManager == nil {
+		return
+	}
+	
+	for _, army := range append(append([]*Army{}, bm.Armies...), bm.Neutrals) {
+		for _, group := range army.Groups {
+			bm.expireMinions(group, deltaTime)
+			
+			for _, unit := range group.GetAllUnits() {
+				if unit.IsAlive && !unit.IsRetreating && unit.CanSummon() {
+					bm.updateSummoner(group, unit, deltaTime)
+				}
+			}
+		}
+	}
+}
+
+// expireMinions counts down minion lifetimes and drops fallen minions from the group
+func (bm *BattleManager) expireMinions(group *Group, deltaTime float64) {
+	pruned := false
+	for _, member := range group.Members {
+		if !member.IsSummoned() {
+			continue
+		}
+		if !member.IsAlive {
+			// Keep the body until the battle log has recorded the death
+			pruned = pruned || member.logged.dead
+			continue
+		}
+		
+		// Minions vanish with their summoner
+		summoner := group.getUnit(member.SummonerID)
+		member.Lifetime -= deltaTime
+		if member.Lifetime <= 0 || summoner == nil || !summoner.IsAlive {
+			member.Lifetime = 0
+			member.HP = 0
+			member.IsAlive = false
+			member.leaveGarrison()
+		}
+	}
+	if !pruned {
+		return
+	}
+	
+	// Build a new slice so history snapshots keep their member lists
+	members := make([]*Unit, 0, len(group.Members))
+	for _, member := range group.Members {
+		if member.IsSummoned() && !member.IsAlive && member.logged.dead {
+			continue
+		}
+		members = append(members, member)
+	}
+	group.Members = members
+}
+
+// updateSummoner calls new minions once the summoner's cooldown is over
+func (bm *BattleManager) updateSummoner(group *Group, summoner *Unit, deltaTime float64) {
+	ability := &summoner.Summon
+	if ability.cooldown > 0 {
+		ability.cooldown -= deltaTime
+		return
+	}
+	
+	// Summoners only call minions once they have an enemy to fight
+	if summoner.AI == nil || summoner.AI.TargetEnemy == nil {
+		return
+	}
+	
+	count := ability.Count
+	if count <= 0 {
+		count = 1
+	}
+	if ability.Max > 0 {
+		count = min(count, ability.Max-bm.countMinions(group, summoner))
+	}
+	if count <= 0 {
+		return
+	}
+	
+	ability.cooldown = ability.Interval
+	if ability.cooldown <= 0 {
+		ability.cooldown = defaultSummonInterval
+	}
+	
+	unitType, config, err := bm.mutateUnitType(ability.UnitType, bm.dataManager)
+	if err != nil {
+		fmt.Printf("Error getting summon config for %s: %v\n", ability.UnitType, err)
+		return
+	}
+	
+	lifetime := ability.Lifetime
+	if lifetime <= 0 {
+		lifetime = defaultSummonLifetime
+	}
+	
+	members := group.Members
+	for i := 0; i < count; i++ {
+		minion := bm.createUnit(UnitType(unitType), newUnitTypeConfig(config, bm.dataManager), false, summoner.ArmyID)
+		minion.GroupID = group.ID
+		minion.SummonerID = summoner.ID
+		minion.Lifetime = lifetime
+		minion.Position = summoner.Position.Add(gamemath.Vector2D{
+			X: (bm.rng.Float64()*2 - 1) * summonSpread,
+			Y: (bm.rng.Float64()*2 - 1) * summonSpread,
+		})
+		minion.Target = minion.Position
+		if minion.AI != nil {
+			minion.AI.Objective = group.Objective
+			minion.AI.FocusTarget = group.FocusTarget
+		}
+		members = append(members, minion)
+		
+		bm.recordEvent(BattleEvent{Type: EventSummon, UnitID: summoner.ID, GroupID: group.ID, OtherID: minion.ID})
+	}
+	group.Members = members
+}
+
+// countMinions returns the number of the summoner's minions still on the field
+func (bm *BattleManager) countMinions(group *Group, summoner *Unit) int {
+	count := 0
+	for _, member := range group.Members {
+		if member.IsAlive && member.SummonerID == summoner.ID {
+			count++
+		}
+	}
+	return count
+}
+
+// getUnit returns the group's unit with the given ID, or nil
+func (g *Group) getUnit(id int) *Unit {
+	for _, unit := range g.GetAllUnits() {
+		if unit.ID == id {
+			return unit
+		}
+	}
+	return nil
+}
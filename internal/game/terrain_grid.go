@@ -0,0 +1,132 @@
+package game
+
+import (
+	"math"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// terrainCellSize is the side of a terrain grid cell (1マス10m四方)
+const terrainCellSize = 100.0
+
+// TerrainGrid holds the movement cost of the stage's terrain areas on a grid
+type TerrainGrid struct {
+	Cols, Rows int
+	Movement   []float64    // 移動速度の倍率（0: 通行不可）
+	Cover      []bool       // 森・藪（潜伏ユニットが見つかりにくく、魔術師の攻撃で燃える）
+	Fire       []float64    // 燃え尽きるまでの残り秒数（0: 燃えていない）
+	Burnt      []bool       // 燃え尽きた焼け野原
+	Obstacles  []Obstacle   // 木や岩（マスとは別に円で衝突判定）
+	Structures []*Structure // 門・城壁・櫓（建っている間はマスを塞ぐ）
+	
+	baseMovement []float64 // 建造物を除いた地形の倍率
+	uniform      bool      // 全マスが通常の地形
+}
+
+// NewTerrainGrid rasterizes the terrain areas onto a grid covering a world of the given size
+// Later areas override earlier ones where they overlap
+func NewTerrainGrid(width, height float64, areas []data.TerrainAreaConfig, obstacles []data.ObstacleConfig) *TerrainGrid {
+	if width <= 0 {
+		width = defaultWorldSize
+	}
+	if height <= 0 {
+		height = defaultWorldSize
+	}
+	
+	cols := int(math.Ceil(width / terrainCellSize))
+	rows := int(math.Ceil(height / terrainCellSize))
+	tg := &TerrainGrid{
+		Cols:     cols,
+		Rows:     rows,
+		Movement:  make([]float64, cols*rows),
+		Cover:     make([]bool, cols*rows),
+		Fire:      make([]float64, cols*rows),
+		Burnt:     make([]bool, cols*rows),
+		Obstacles: NewObstacles(obstacles),
+		uniform:   true,
+	}
+	
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			movement := 1.0
+			cover := false
+			center := tg.cellCenter(col, row)
+			for _, area := range areas {
+				if area.Contains(center.X, center.Y) {
+					movement = math.Max(area.MovementModifier, 0)
+					cover = area.Cover
+				}
+			}
+			tg.Cover[row*cols+col] = cover
+			if movement != 1.0 {
+				tg.uniform = false
+			}
+			tg.Movement[row*cols+col] = movement
+		}
+	}
+	tg.baseMovement = append([]float64(nil), tg.Movement...)
+	return tg
+}
+
+// cellAt returns the cell containing the position, clamped to the grid
+func (tg *TerrainGrid) cellAt(position gamemath.Vector2D) (int, int) {
+	col := int(math.Floor(position.X / terrainCellSize))
+	row := int(math.Floor(position.Y / terrainCellSize))
+	col = max(0, min(tg.Cols-1, col))
+	row = max(0, min(tg.Rows-1, row))
+	return col, row
+}
+
+// cellCenter returns the world position of the center of a cell
+func (tg *TerrainGrid) cellCenter(col, row int) gamemath.Vector2D {
+	return gamemath.Vector2D{
+		X: (float64(col) + 0.5) * terrainCellSize,
+		Y: (float64(row) + 0.5) * terrainCellSize,
+	}
+}
+
+// CellOrigin returns the top-left corner of the cell at the index in world coordinates, and the cell's side
+func (tg *TerrainGrid) CellOrigin(index int) (gamemath.Vector2D, float64) {
+	col, row := index%tg.Cols, index/tg.Cols
+	origin := gamemath.Vector2D{X: float64(col) * terrainCellSize, Y: float64(row) * terrainCellSize}
+	return origin, terrainCellSize
+}
+
+// isCellPassable reports whether the cell exists and can be entered
+func (tg *TerrainGrid) isCellPassable(col, row int) bool {
+	return col >= 0 && col < tg.Cols && row >= 0 && row < tg.Rows && tg.Movement[row*tg.Cols+col] > 0
+}
+
+// GetMovement returns the speed multiplier of the terrain at the position
+func (tg *TerrainGrid) GetMovement(position gamemath.Vector2D) float64 {
+	col, row := tg.cellAt(position)
+	return tg.Movement[row*tg.Cols+col]
+}
+
+// IsPassable reports whether units can enter the position
+func (tg *TerrainGrid) IsPassable(position gamemath.Vector2D) bool {
+	return tg.GetMovement(position) > 0
+}
+
+// IsUniform reports whether the whole stage is ordinary terrain, so straight lines are always best
+func (tg *TerrainGrid) IsUniform() bool {
+	return tg.uniform
+}
+
+// IsLineClear reports whether the straight line crosses only ordinary or faster terrain
+func (tg *TerrainGrid) IsLineClear(from, to gamemath.Vector2D) bool {
+	if tg.uniform {
+		return true
+	}
+	
+	// Sample twice per cell so corners are not skipped
+	steps := int(math.Ceil(from.Distance(to)/(terrainCellSize/2))) + 1
+	for i := 0; i <= steps; i++ {
+		point := from.Add(to.Sub(from).Mul(float64(i) / float64(steps)))
+		if tg.GetMovement(point) < 1.0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"math"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Threat map tuning
+const (
+	threatCellSize       = 250.0 // 1マス25m四方
+	threatUpdateInterval = 0.5   // 更新間隔（秒）
+	threatSpread         = 0.5   // 隣接マスへ及ぼす影響の割合
+	rangedThreatRange    = 100.0 // これより射程の長いユニットは遠隔戦力とみなす
+	defaultWorldSize     = 5000.0
+)
+
+// ThreatMap is an army's picture of enemy and friendly strength across the battlefield
+type ThreatMap struct {
+	ArmyID     int
+	Cols, Rows int
+	
+	MeleeThreat  []float64 // 敵の近接戦力
+	RangedThreat []float64 // 敵の遠隔戦力
+	Support      []float64 // 味方（同盟軍を含む）の戦力
+	
+	sinceUpdate float64
+}
+
+// NewThreatMap creates an empty threat map covering a world of the given size
+func NewThreatMap(armyID int, width, height float64) *ThreatMap {
+	if width <= 0 {
+		width = defaultWorldSize
+	}
+	if height <= 0 {
+		height = defaultWorldSize
+	}
+	
+	cols := int(math.Ceil(width / threatCellSize))
+	rows := int(math.Ceil(height / threatCellSize))
+	return &ThreatMap{
+		ArmyID:       armyID,
+		Cols:         cols,
+		Rows:         rows,
+		MeleeThreat:  make([]float64, cols*rows),
+		RangedThreat: make([]float64, cols*rows),
+		Support:      make([]float64, cols*rows),
+		sinceUpdate:  threatUpdateInterval, // Build on the first update
+	}
+}
+
+// Update rebuilds the map every few ticks
+func (tm *ThreatMap) Update(bm *BattleManager, deltaTime float64) {
+	tm.sinceUpdate += deltaTime
+	if tm.sinceUpdate < threatUpdateInterval {
+		return
+	}
+	tm.sinceUpdate = 0
+	tm.rebuild(bm)
+}
+
+// rebuild recomputes every cell from the current unit positions
+func (tm *ThreatMap) rebuild(bm *BattleManager) {
+	for i := range tm.MeleeThreat {
+		tm.MeleeThreat[i] = 0
+		tm.RangedThreat[i] = 0
+		tm.Support[i] = 0
+	}
+	
+	for _, enemy := range bm.GetEnemyUnits(tm.ArmyID) {
+		if enemy.IsRetreating {
+			continue
+		}
+		if enemy.Range > rangedThreatRange {
+			tm.spread(tm.RangedThreat, enemy.Position, unitStrength(enemy))
+		} else {
+			tm.spread(tm.MeleeThreat, enemy.Position, unitStrength(enemy))
+		}
+	}
+	
+	for _, army := range bm.Armies {
+		if !bm.AreAllied(tm.ArmyID, army.ID) {
+			continue
+		}
+		for _, unit := range army.GetAliveUnits() {
+			if !unit.IsRetreating {
+				tm.spread(tm.Support, unit.Position, unitStrength(unit))
+			}
+		}
+	}
+}
+
+// spread adds a unit's strength to its cell and, weakened, to the neighbouring cells
+func (tm *ThreatMap) spread(grid []float64, position gamemath.Vector2D, strength float64) {
+	col, row := tm.cellAt(position)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			c, r := col+dx, row+dy
+			if c < 0 || c >= tm.Cols || r < 0 || r >= tm.Rows {
+				continue
+			}
+			
+			weight := threatSpread
+			if dx == 0 && dy == 0 {
+				weight = 1.0
+			}
+			grid[r*tm.Cols+c] += strength * weight
+		}
+	}
+}
+
+// cellAt returns the cell containing the position, clamped to the map
+func (tm *ThreatMap) cellAt(position gamemath.Vector2D) (int, int) {
+	col := int(position.X / threatCellSize)
+	row := int(position.Y / threatCellSize)
+	col = max(0, min(tm.Cols-1, col))
+	row = max(0, min(tm.Rows-1, row))
+	return col, row
+}
+
+// valueAt returns the grid value at the position
+func (tm *ThreatMap) valueAt(grid []float64, position gamemath.Vector2D) float64 {
+	col, row := tm.cellAt(position)
+	return grid[row*tm.Cols+col]
+}
+
+// GetMeleeThreat returns the enemy melee strength around the position
+func (tm *ThreatMap) GetMeleeThreat(position gamemath.Vector2D) float64 {
+	return tm.valueAt(tm.MeleeThreat, position)
+}
+
+// GetThreat returns the total enemy strength around the position
+func (tm *ThreatMap) GetThreat(position gamemath.Vector2D) float64 {
+	return tm.valueAt(tm.MeleeThreat, position) + tm.valueAt(tm.RangedThreat, position)
+}
+
+// GetSupport returns the friendly strength around the position
+func (tm *ThreatMap) GetSupport(position gamemath.Vector2D) float64 {
+	return tm.valueAt(tm.Support, position)
+}
+
+// unitStrength estimates how much damage a unit can still deal
+func unitStrength(unit *Unit) float64 {
+	power := math.Max(float64(unit.AttackPower), float64(unit.MagicPower))
+	return power * unit.GetHealthPercentage()
+}
+
+// GetThreatMap returns the threat map of the army, or nil if there is none
+func (bm *BattleManager) GetThreatMap(armyID int) *ThreatMap {
+	if armyID < 0 || armyID >= len(bm.ThreatMaps) {
+		return nil
+	}
+	return bm.ThreatMaps[armyID]
+}
+
+// updateThreatMaps refreshes the threat map of every army
+func (bm *BattleManager) updateThreatMaps(deltaTime float64) {
+	for _, threatMap := range bm.ThreatMaps {
+		threatMap.Update(bm, deltaTime)
+	}
+}
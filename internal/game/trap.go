@@ -0,0 +1,231 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Trap kinds
+const (
+	TrapSpikePit = "spike_pit" // 落とし穴（踏んだ1体に大ダメージ、一度きり）
+	TrapCaltrops = "caltrops"  // まきびし（踏んだユニットの足を鈍らせる、何度でも）
+)
+
+// Trap tuning
+const (
+	trapRadius         = 30.0  // 罠を踏む距離（3m）
+	trapSpacing        = 60.0  // 罠同士を離す距離
+	trapEnemyDistance  = 800.0 // 敵の布陣地点からこの距離より内側には仕掛けられない
+	spikePitDamage     = 40    // 落とし穴のダメージ（防御無視）
+	spikePitStun       = 2.0   // 落とし穴にはまったユニットが気絶する秒数
+	caltropsSlowFactor = 0.5   // まきびしを踏んだユニットの移動速度の倍率
+	caltropsSlowTime   = 5.0   // まきびしを抜けてから足が戻るまでの秒数
+	trapLayAttempts    = 10    // AIが1つの罠の置き場所を探す回数
+	trapLayMinDistance = 200.0 // AIが自陣の前に罠を置く距離の下限
+	trapLayDepth       = 300.0 // AIが罠を置く距離の幅
+	trapLayWidth       = 400.0 // AIが罠を散らす横幅
+)
+
+// Trap is a spike pit or caltrops laid before the battle, hidden from the armies of other alliances
+// until it springs
+type Trap struct {
+	Kind      string
+	ArmyID    int
+	Position  gamemath.Vector2D
+	Triggered bool // 落とし穴が作動済み
+	Revealed  bool // 踏まれて敵にも知られた
+}
+
+// TrapKit is how many traps of each kind an army may lay
+type TrapKit struct {
+	SpikePits int
+	Caltrops  int
+}
+
+// GetPresetTraps returns the traps a preset army brings; only the defensive preset lays traps,
+// unless a mod's preset brings its own
+func GetPresetTraps(presetType string, dataManager *data.DataManager) TrapKit {
+	if preset, exists := modPreset(presetType, dataManager); exists {
+		return TrapKit{SpikePits: preset.SpikePits, Caltrops: preset.Caltrops}
+	}
+	
+	switch presetType {
+	case "防御重視":
+		return TrapKit{SpikePits: 3, Caltrops: 4}
+	default:
+		return TrapKit{}
+	}
+}
+
+// TrapName returns the display name of a trap kind
+func TrapName(kind string) string {
+	switch kind {
+	case TrapSpikePit:
+		return "落とし穴"
+	case TrapCaltrops:
+		return "まきびし"
+	default:
+		return kind
+	}
+}
+
+// GetTrapKit returns the traps the army brings to the battle
+func (bm *BattleManager) GetTrapKit(armyID int) TrapKit {
+	return bm.trapKits[armyID]
+}
+
+// TrapsLeft returns how many more traps of the kind the army may lay
+func (bm *BattleManager) TrapsLeft(armyID int, kind string) int {
+	kit := bm.GetTrapKit(armyID)
+	left := kit.Caltrops
+	if kind == TrapSpikePit {
+		left = kit.SpikePits
+	}
+	for _, trap := range bm.Traps {
+		if trap.ArmyID == armyID && trap.Kind == kind {
+			left--
+		}
+	}
+	return left
+}
+
+// PlaceTrap lays a trap for the army before the battle starts
+// Traps go on open ground, apart from each other and away from hostile deployment points
+func (bm *BattleManager) PlaceTrap(armyID int, kind string, position gamemath.Vector2D) error {
+	if bm.IsActive || bm.BattleTime > 0 {
+		return fmt.Errorf("罠は開戦前にしか仕掛けられません")
+	}
+	if bm.TrapsLeft(armyID, kind) <= 0 {
+		return fmt.Errorf("%sはもう残っていません", TrapName(kind))
+	}
+	
+	width, height := bm.getWorldSize()
+	if position.X < 0 || position.Y < 0 || position.X >= width || position.Y >= height || !bm.Terrain.IsPassable(position) {
+		return fmt.Errorf("そこには仕掛けられません")
+	}
+	for _, trap := range bm.Traps {
+		if trap.Position.Distance(position) < trapSpacing {
+			return fmt.Errorf("ほかの罠に近すぎます")
+		}
+	}
+	for i, config := range bm.armyConfigs {
+		if bm.AreAllied(armyID, i) {
+			continue
+		}
+		for _, point := range config.GetDeploymentPoints() {
+			if point.Distance(position) < trapEnemyDistance {
+				return fmt.Errorf("敵陣に近すぎます")
+			}
+		}
+	}
+	
+	bm.Traps = append(bm.Traps, &Trap{Kind: kind, ArmyID: armyID, Position: position})
+	return nil
+}
+
+// RemoveTrap takes back the army's trap at the position before the battle starts, reporting whether one was there
+func (bm *BattleManager) RemoveTrap(armyID int, position gamemath.Vector2D) bool {
+	if bm.IsActive || bm.BattleTime > 0 {
+		return false
+	}
+	for i, trap := range bm.Traps {
+		if trap.ArmyID == armyID && trap.Position.Distance(position) <= trapRadius {
+			bm.Traps = append(bm.Traps[:i], bm.Traps[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrapVisibleTo reports whether the army knows of the trap: its own alliance's traps and sprung ones
+func (bm *BattleManager) IsTrapVisibleTo(armyID int, trap *Trap) bool {
+	return trap.Revealed || bm.AreAllied(armyID, trap.ArmyID)
+}
+
+// layTraps lets the army's AI lay its whole kit across the ground in front of its deployment
+func (bm *BattleManager) layTraps(armyID int) {
+	kit := bm.GetTrapKit(armyID)
+	if kit.SpikePits+kit.Caltrops == 0 {
+		return
+	}
+	
+	own := bm.armyConfigs[armyID].GetDeploymentPoints()
+	if len(own) == 0 {
+		return
+	}
+	home := centroid(own)
+	front := bm.armyFacing(armyID)
+	side := gamemath.Vector2D{X: -front.Y, Y: front.X}
+	
+	for _, kind := range []string{TrapSpikePit, TrapCaltrops} {
+		for bm.TrapsLeft(armyID, kind) > 0 {
+			placed := false
+			for attempt := 0; attempt < trapLayAttempts && !placed; attempt++ {
+				ahead := trapLayMinDistance + bm.rng.Float64()*trapLayDepth
+				across := (bm.rng.Float64() - 0.5) * trapLayWidth
+				position := home.Add(front.Mul(ahead)).Add(side.Mul(across))
+				placed = bm.PlaceTrap(armyID, kind, position) == nil
+			}
+			if !placed {
+				break
+			}
+		}
+	}
+}
+
+// centroid returns the average of the points
+func centroid(points []gamemath.Vector2D) gamemath.Vector2D {
+	var sum gamemath.Vector2D
+	for _, point := range points {
+		sum = sum.Add(point)
+	}
+	return sum.Mul(1 / float64(len(points)))
+}
+
+// updateTraps springs traps under ground units of other alliances and lets slowed units recover
+func (bm *BattleManager) updateTraps(deltaTime float64) {
+	units := bm.getAllAliveUnits()
+	for _, unit := range units {
+		if unit.slowed > 0 {
+			unit.slowed = max(unit.slowed-deltaTime, 0)
+		}
+	}
+	
+	for _, trap := range bm.Traps {
+		if trap.Triggered {
+			continue
+		}
+		for _, unit := range units {
+			if !unit.IsAlive || unit.Flying || unit.Garrison != nil || bm.AreAllied(unit.ArmyID, trap.ArmyID) {
+				continue
+			}
+			if unit.Position.Distance(trap.Position) > trapRadius {
+				continue
+			}
+			
+			trap.Revealed = true
+			if trap.Kind == TrapSpikePit {
+				trap.Triggered = true
+				unit.TakeDamage(spikePitDamage)
+				unit.Stun(spikePitStun)
+				bm.recordEvent(BattleEvent{Type: EventSpikePit, UnitID: unit.ID, GroupID: unit.GroupID, Amount: spikePitDamage})
+				bm.Heatmap.add(HeatmapDamage, unit.Position, float64(spikePitDamage))
+				break
+			}
+			if unit.slowed <= 0 {
+				bm.recordEvent(BattleEvent{Type: EventCaltrops, UnitID: unit.ID, GroupID: unit.GroupID})
+			}
+			unit.slowed = caltropsSlowTime
+		}
+	}
+}
+
+// trapSpeed returns the movement multiplier from caltrops the unit stepped on
+func (u *Unit) trapSpeed() float64 {
+	if u.slowed > 0 {
+		return caltropsSlowFactor
+	}
+	return 1.0
+}
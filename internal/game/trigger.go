@@ -0,0 +1,122 @@
+package game
+
+import (
+	"math"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Trigger tracks a scripted stage event; it fires once, the first tick its condition is met
+type Trigger struct {
+	Config data.TriggerConfig
+	Fired  bool
+}
+
+// NewTriggers creates the stage's scripted events from its configuration
+func NewTriggers(configs []data.TriggerConfig) []*Trigger {
+	triggers := make([]*Trigger, 0, len(configs))
+	for _, config := range configs {
+		triggers = append(triggers, &Trigger{Config: config})
+	}
+	return triggers
+}
+
+// updateTriggers fires the stage triggers whose condition is met, running their actions in order
+func (bm *BattleManager) updateTriggers() {
+	for _, trigger := range bm.Triggers {
+		if trigger.Fired || !bm.isTriggerMet(trigger) {
+			continue
+		}
+		trigger.Fired = true
+		for _, action := range trigger.Config.Actions {
+			bm.runTriggerAction(action)
+		}
+	}
+}
+
+// isTriggerMet checks the condition of a trigger; conditions other than time wait for its time as well
+func (bm *BattleManager) isTriggerMet(trigger *Trigger) bool {
+	config := trigger.Config
+	if bm.BattleTime < config.Time {
+		return false
+	}
+	
+	switch config.Condition {
+	case data.ConditionUnitsBelow:
+		army := bm.GetArmy(config.ArmyID())
+		return army != nil && len(army.GetAliveUnits()) < config.Count
+	case data.ConditionZoneEntered:
+		center := gamemath.Vector2D{X: config.X, Y: config.Y}
+		for _, army := range bm.Armies {
+			if config.Army != "" && army.ID != config.ArmyID() {
+				continue
+			}
+			for _, unit := range army.GetAliveUnits() {
+				if unit.Position.Distance(center) <= config.Radius {
+					return true
+				}
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// runTriggerAction carries out one action of a fired trigger
+func (bm *BattleManager) runTriggerAction(action data.TriggerActionConfig) {
+	switch action.Type {
+	case data.ActionSpawn:
+		if bm.dataManager == nil {
+			return
+		}
+		army := bm.GetArmy(action.ArmyID())
+		if army == nil || army.IsRouted {
+			return
+		}
+		bm.spawnGroups(army, gamemath.Vector2D{X: action.X, Y: action.Y}, action.Groups)
+	case data.ActionWeather:
+		bm.changeWeather(action)
+	case data.ActionMessage:
+		bm.Announce(action.Text)
+	case data.ActionEndBattle:
+		winner := WinnerDraw
+		if army := bm.GetArmy(action.ArmyID()); army != nil {
+			winner = army.ID
+		}
+		if action.Text != "" {
+			bm.Announce(action.Text)
+		}
+		bm.endBattle(winner)
+	}
+}
+
+// triggerWind returns the wind a weather action brings
+func triggerWind(action data.TriggerActionConfig) gamemath.Vector2D {
+	// wind_from is the bearing the wind blows from, clockwise from screen north (-Y)
+	bearing := action.WindFrom * math.Pi / 180
+	speed := action.WindSpeed * 10
+	if speed <= 0 {
+		speed = (windMinSpeed + windMaxSpeed) / 2
+	}
+	return gamemath.NewVector2D(-math.Sin(bearing)*speed, math.Cos(bearing)*speed)
+}
+
+// changeWeather lets the wind rise or drop and the fog come in or lift, announcing the change
+func (bm *BattleManager) changeWeather(action data.TriggerActionConfig) {
+	switch action.Weather {
+	case data.WeatherWind:
+		bm.Wind = triggerWind(action)
+		bm.Announce("風が吹き始めた（" + bm.WindText() + "）")
+	case data.WeatherCalm:
+		bm.Wind = gamemath.Vector2D{}
+		bm.Announce("風がやんだ")
+	case data.WeatherFog:
+		bm.Foggy = true
+		bm.Announce("霧が立ち込めてきた")
+	case data.WeatherClear:
+		bm.Foggy = false
+		bm.Announce("霧が晴れた")
+	}
+}
@@ -16,6 +16,9 @@ const (
 	UnitTypeMage     UnitType = "mage"
 )
 
+// defaultSightRange is the sight of a unit type without sight_range: the whole 500m battlefield
+const defaultSightRange = 5000.0
+
 // Unit represents an individual unit in the game
 type Unit struct {
 	ID           int
@@ -27,6 +30,7 @@ type Unit struct {
 	Defense      int
 	Speed        float64
 	Range        float64
+	SightRange   float64  // 知覚範囲（夜の短縮前）
 	MagicPower   int
 	Size         float64  // ユニットの大きさ（衝突判定用）
 	Position     math.Vector2D
@@ -40,12 +44,95 @@ type Unit struct {
 	// Combat state
 	LastAttackTime float64
 	AttackCooldown float64
+	SwingTarget    *Unit      // 振りかぶり中の攻撃の目標（命中フレームでダメージを与える）
+	SwingStructure *Structure // 振りかぶり中の建造物への攻撃の目標
+	SiegeBonus     float64    // 建造物への攻撃力の倍率
+	
+	// Ammunition state (MaxAmmo 0: 無制限)
+	Ammo          int
+	MaxAmmo       int
+	Resupplying   bool    // 補給地点へ後退中
+	MeleeFallback bool    // 矢弾が尽きて白兵戦に切り替えた
+	resupplied    float64 // 補給中の端数
+	
+	// Morale state
+	Morale    float64 // 士気 (0-MaxMorale)
+	MaxMorale float64
+	
+	// Stamina state
+	Stamina    float64 // スタミナ (0-MaxStamina)
+	MaxStamina float64
+	HeavyArmor bool    // 重装備（スタミナの消耗が大きい）
+	
+	// Army-wide doctrine effects
+	Effects StatusEffects
+	
+	// Knockback: heavy blows shove the target back, and a shove against a wall stuns it
+	Knockback float64       // 命中で敵を押し下げる距離（px、0: 押さない）
+	knockback math.Vector2D // これから押し下げられる残りの距離
+	
+	// Equipment carried by a leader
+	Items        []string // 装備のアイテムID
+	Regeneration float64  // 毎秒の体力回復量
+	regenerated  float64  // 回復量の端数
+	
+	// Garrison state
+	Garrison *Structure // 中にいる櫓（nil: 地上）
+	
+	// Flying units pass over terrain and ground troops, and only missiles and spells reach them
+	Flying bool
+	
+	// Stealth state
+	Stealth         bool    // 潜伏能力
+	Hidden          bool    // 潜伏中（敵は近づかないと見つけられない）
+	stealthCooldown float64 // 再び潜伏できるまでの秒数
+	
+	// The unit's group is attacked from three or more sides
+	Surrounded bool
+	
+	// A convoy's wagons follow its route instead of an AI or orders, and never fight back
+	Convoy bool
+	
+	// Caltrops slow the unit down for a while
+	slowed float64 // 足が鈍っている残り秒数
+	
+	// Mana state (Spells.MaxMana 0: 魔力を使わない)
+	Mana    float64
+	Spells  SpellAbility
+	casting *Ability // 振りかぶり中の攻撃で唱えている魔法（nil: 武器や杖で攻撃する）
+	
+	abilityCooldowns []float64 // 魔法ごとの次に唱えられるまでの秒数
+	
+	// Channeling state: a channeled spell builds up in place and breaks off when the caster is hurt
+	Channeled     float64 // 詠唱の経過秒数
+	channelTarget *Unit   // 詠唱中の大魔法の目標（nil: 詠唱していない）
+	interrupted   bool    // 詠唱が途切れた（戦闘ログへの記録待ち）
+	Stunned       float64 // 気絶の残り秒数（移動も攻撃もできない）
+	
+	// Summoning state
+	Summon     SummonAbility // 召喚能力（UnitType 空: なし）
+	SummonerID int           // 召喚したユニット（0: 召喚されたユニットではない）
+	Lifetime   float64       // 召喚されたユニットが消えるまでの秒数
+	
+	// Chain of command: the formation center the unit last heard of from its leader
+	heardTarget math.Vector2D
+	orderHeard  bool
+	orderDelay  float64 // 新しい命令が届くまでの残り秒数（0: 届いている）
+	
+	// Movement state
+	Velocity  math.Vector2D
+	Steering  math.Vector2D // 周囲のユニットからの回避と部隊の結束（毎フレーム更新）
+	Terrain   *TerrainGrid  // ステージの地形（移動速度と通行可否）
+	FlowField *FlowField    // 地形を迂回する共有の経路（nil: 目標へ直進）
 	
 	// Animation state
 	Animation *graphics.AnimationState
 	
 	// AI behavior
 	AI *AIBehavior
+	
+	// State last written to the battle log
+	logged unitLogState
 }
 
 // NewUnit creates a new unit with the given configuration
@@ -60,8 +147,10 @@ func NewUnit(id int, unitType UnitType, config UnitTypeConfig, isLeader bool, gr
 		Defense:        config.Defense,
 		Speed:          config.Speed,
 		Range:          config.Range,
+		SightRange:     defaultSightRange,
 		MagicPower:     config.MagicPower,
 		Size:           config.Size,  // サイズを設定
+		Knockback:      config.Knockback,
 		Position:       math.Vector2D{},
 		Target:         math.Vector2D{},
 		IsLeader:       isLeader,
@@ -71,9 +160,31 @@ func NewUnit(id int, unitType UnitType, config UnitTypeConfig, isLeader bool, gr
 		ArmyID:         armyID,
 		LastAttackTime: 0,
 		AttackCooldown: 1.0, // 1 second cooldown
+		Ammo:           config.Ammo,
+		MaxAmmo:        config.Ammo,
+		Morale:         100.0,
+		MaxMorale:      100.0,
+		Stamina:        defaultMaxStamina,
+		MaxStamina:     defaultMaxStamina,
+		HeavyArmor:     config.HeavyArmor,
+		SiegeBonus:     1.0,
+		Summon:         config.Summon,
+		Mana:           config.Spells.MaxMana,
+		Spells:         config.Spells,
+		Flying:         config.Flying,
+		Stealth:        config.Stealth,
+		Hidden:         config.Stealth,
+		Effects:        NewStatusEffects(),
 		Animation:      graphics.NewAnimationState(graphics.AnimationIdle),
 		AI:             NewAIBehavior(unitType),
 	}
+	unit.abilityCooldowns = make([]float64, len(config.Spells.Abilities))
+	if config.SiegeBonus > 0 {
+		unit.SiegeBonus = config.SiegeBonus
+	}
+	if config.SightRange > 0 {
+		unit.SightRange = config.SightRange
+	}
 	
 	// デバッグ: ユニット作成確認
 	fmt.Printf("Created Unit ID=%d, Type=%s, HP=%d/%d, Alive=%t, Army=%d, Size=%.1f\n", 
@@ -101,10 +212,15 @@ func (u *Unit) Update(deltaTime float64) {
 		}
 	}
 	
+	// Units inside a tower hold their post, as do stunned units and casters channeling a spell
+	if u.Garrison != nil || u.IsStunned() || u.IsChanneling() {
+		u.Target = u.Position
+	}
+	
 	// Determine animation based on state
 	isMoving := u.Position.Distance(u.Target) > u.GetCollisionRadius()  // 衝突半径を考慮した移動判定
 	
-	if u.LastAttackTime > u.AttackCooldown * 0.7 { // Recently attacked
+	if u.isSwinging() || u.LastAttackTime > u.AttackCooldown * 0.7 { // Swinging or recently attacked
 		if u.Animation.Type != graphics.AnimationAttack {
 			u.Animation.SetAnimation(graphics.AnimationAttack)
 		}
@@ -121,12 +237,20 @@ func (u *Unit) Update(deltaTime float64) {
 	// Update animation
 	u.Animation.Update(deltaTime)
 	
-	// Move towards target if not at target
-	if isMoving {
-		direction := u.Target.Sub(u.Position).Normalize()
-		movement := direction.Mul(u.Speed * deltaTime)
-		u.Position = u.Position.Add(movement)
+	// Move towards target while steering around nearby units
+	if u.Garrison == nil {
+		u.steer(deltaTime, isMoving)
 	}
+	
+	// Sprinting tires the unit; standing still lets it recover
+	u.updateStamina(deltaTime, isMoving)
+	
+	// Blessed equipment mends the unit over time
+	u.regenerate(deltaTime)
+	
+	// Magic users gather mana again and their spells come off cooldown
+	u.regenerateMana(deltaTime)
+	u.coolAbilities(deltaTime)
 }
 
 // MoveTo sets the unit's target position
@@ -136,44 +260,137 @@ func (u *Unit) MoveTo(target math.Vector2D) {
 
 // CanAttack checks if the unit can attack
 func (u *Unit) CanAttack() bool {
-	return u.IsAlive && u.LastAttackTime <= 0
+	return u.IsAlive && !u.Convoy && u.LastAttackTime <= 0 && !u.isSwinging() && u.HasAmmo() && !u.IsChanneling() && !u.IsStunned()
 }
 
-// Attack performs an attack on the target unit
-func (u *Unit) Attack(target *Unit) int {
-	if !u.CanAttack() || !target.IsAlive {
-		return 0
+// IsSiegeEngine reports whether the unit is built to batter structures
+func (u *Unit) IsSiegeEngine() bool {
+	return u.SiegeBonus > 1
+}
+
+// isSwinging reports whether the unit is winding up an attack
+func (u *Unit) isSwinging() bool {
+	return u.SwingTarget != nil || u.SwingStructure != nil
+}
+
+// StartAttack winds up an attack on the target; damage lands later on the animation's hit frame
+// A magic user casts the spell chosen for the target beforehand
+func (u *Unit) StartAttack(target *Unit) bool {
+	if !u.CanAttack() || !target.IsAlive || !u.CanHit(target) {
+		return false
 	}
 	
 	// Check range (攻撃範囲 + 両方の衝突半径を考慮)
 	distance := u.Position.Distance(target.Position)
 	effectiveRange := u.Range + u.GetCollisionRadius() + target.GetCollisionRadius()
 	if distance > effectiveRange {
-		return 0
+		return false
 	}
 	
-	// Trigger attack animation
+	u.windUp()
+	u.SwingTarget = target
+	return true
+}
+
+// StartStructureAttack winds up an attack on a structure within range
+func (u *Unit) StartStructureAttack(structure *Structure) bool {
+	if !u.CanAttack() || structure.IsDestroyed {
+		return false
+	}
+	if structure.DistanceTo(u.Position) > u.Range+u.GetCollisionRadius() {
+		return false
+	}
+	
+	// Magic users keep their spells for troops
+	u.casting = nil
+	u.windUp()
+	u.SwingStructure = structure
+	return true
+}
+
+// windUp starts the attack animation and pays the attack's costs
+func (u *Unit) windUp() {
+	// Start the attack animation from its first frame
 	u.Animation.SetAnimation(graphics.AnimationAttack)
+	u.Animation.Reset()
+	
+	// Set cooldown; exhausted units swing slower
+	u.LastAttackTime = u.AttackCooldown * u.fatigueCooldown()
+	u.drainStamina(staminaAttackCost)
 	
-	// Calculate damage
-	baseDamage := u.AttackPower
-	if u.Type == UnitTypeMage {
-		baseDamage += u.MagicPower
+	// Ranged units spend a shot, and magic users the mana for their spell
+	if u.MaxAmmo > 0 && !u.MeleeFallback {
+		u.Ammo--
+	}
+	u.spendMana()
+}
+
+// IsSwingAtHitFrame reports whether the pending attack has reached its hit frame
+func (u *Unit) IsSwingAtHitFrame() bool {
+	return u.isSwinging() && u.Animation.HasReachedFrame(graphics.AnimationAttack, graphics.AttackHitFrame)
+}
+
+// CancelAttack interrupts the pending attack without dealing damage
+func (u *Unit) CancelAttack() {
+	u.SwingTarget = nil
+	u.SwingStructure = nil
+}
+
+// LandAttack deals the pending attack's damage and returns the target and the damage dealt
+func (u *Unit) LandAttack() (*Unit, int) {
+	target, damage := u.releaseAttack()
+	if damage > 0 {
+		target.TakeDamage(damage)
+	}
+	return target, damage
+}
+
+// releaseAttack resolves the pending attack and returns the target and the damage it will deal, without dealing it
+func (u *Unit) releaseAttack() (*Unit, int) {
+	target := u.SwingTarget
+	u.SwingTarget = nil
+	if target == nil || !target.IsAlive {
+		return target, 0
 	}
 	
-	// Apply defense
-	damage := baseDamage - target.Defense
+	// Apply defense; a blow from hiding hits much harder
+	baseDamage := u.getBaseDamage()
+	if u.Hidden {
+		baseDamage = int(float64(baseDamage) * ambushDamageMultiplier)
+	}
+	damage := baseDamage - target.GetDefense()
 	if damage < 1 {
 		damage = 1 // Minimum damage
 	}
+	u.reveal()
+	return target, damage
+}
+
+// LandStructureAttack resolves the pending attack on a structure and returns the damage to deal,
+// multiplied by the siege bonus; the battle applies it since a falling structure reopens the terrain
+func (u *Unit) LandStructureAttack() (*Structure, int) {
+	structure := u.SwingStructure
+	u.SwingStructure = nil
+	if structure == nil || structure.IsDestroyed {
+		return structure, 0
+	}
 	
-	// Apply damage
-	target.TakeDamage(damage)
-	
-	// Set cooldown
-	u.LastAttackTime = u.AttackCooldown
-	
-	return damage
+	damage := max(1, int(float64(u.getBaseDamage())*u.SiegeBonus)-structure.Defense)
+	return structure, damage
+}
+
+// getBaseDamage returns the damage of one attack before defense; magic power only adds to spells
+func (u *Unit) getBaseDamage() int {
+	if u.casting != nil {
+		return u.spellDamage(u.casting)
+	}
+	return u.AttackPower
+}
+
+// CanHit reports whether the unit's attacks reach the target
+// Flying targets are out of reach of melee blows, except from other flyers
+func (u *Unit) CanHit(target *Unit) bool {
+	return !target.Flying || u.Flying || u.Range > rangedThreatRange
 }
 
 // TakeDamage applies damage to the unit
@@ -183,14 +400,46 @@ func (u *Unit) TakeDamage(damage int) {
 	}
 	
 	u.HP -= damage
+	u.reveal()
+	u.interruptChannel()
+	
+	// Taking hits shakes the unit's morale
+	if u.MaxHP > 0 {
+		u.ChangeMorale(-float64(damage) / float64(u.MaxHP) * 50.0)
+	}
+	
 	if u.HP <= 0 {
 		u.HP = 0
 		u.IsAlive = false
 	}
 }
 
+// Heal restores HP, capped at MaxHP
+func (u *Unit) Heal(amount int) {
+	if !u.IsAlive || amount <= 0 {
+		return
+	}
+	
+	u.HP += amount
+	if u.HP > u.MaxHP {
+		u.HP = u.MaxHP
+	}
+}
+
+// ChangeMorale adjusts the unit's morale, clamped to [0, MaxMorale]
+func (u *Unit) ChangeMorale(delta float64) {
+	u.Morale += delta
+	if u.Morale < 0 {
+		u.Morale = 0
+	}
+	if u.Morale > u.MaxMorale {
+		u.Morale = u.MaxMorale
+	}
+}
+
 // StartRetreating makes the unit start retreating
 func (u *Unit) StartRetreating(exitPoint math.Vector2D) {
+	u.leaveGarrison()
 	u.IsRetreating = true
 	u.Target = exitPoint
 }
@@ -210,11 +459,9 @@ func (u *Unit) GetCollisionRadius() float64 {
 	return baseRadius * u.Size
 }
 
-// GetSightRange returns the sight range for this unit
+// GetSightRange returns the sight range for this unit, shortened at night
 func (u *Unit) GetSightRange() float64 {
-	// デフォルトで500m（5000px）の知覚範囲
-	// 実際の実装では、ユニット設定から取得する
-	return 5000.0
+	return u.SightRange * u.Effects.Sight
 }
 
 // IsCollidingWith checks if this unit is colliding with another unit
@@ -228,24 +475,3 @@ func (u *Unit) IsCollidingWith(other *Unit) bool {
 	
 	return distance < combinedRadius
 }
-
-// ResolveCollision resolves collision with another unit by pushing them apart
-func (u *Unit) ResolveCollision(other *Unit) {
-	if !u.IsAlive || !other.IsAlive {
-		return
-	}
-	
-	distance := u.Position.Distance(other.Position)
-	combinedRadius := u.GetCollisionRadius() + other.GetCollisionRadius()
-	
-	if distance < combinedRadius && distance > 0 {
-		// 重なりを解消するために押し出す
-		overlap := combinedRadius - distance
-		direction := other.Position.Sub(u.Position).Normalize()
-		
-		// 両方のユニットを半分ずつ押し出す
-		pushDistance := overlap * 0.5
-		u.Position = u.Position.Sub(direction.Mul(pushDistance))
-		other.Position = other.Position.Add(direction.Mul(pushDistance))
-	}
-}
@@ -0,0 +1,232 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/data"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Objective tracks the runtime state of a stage victory condition
+type Objective struct {
+	Config   data.VictoryConditionConfig
+	Holder   int     // capture_zone: army currently holding the zone (-1: none)
+	Progress float64 // capture_zone: seconds the holder has held the zone
+	Taker    int     // capture_zone: army that last took the zone from another (-1: none)
+	Winner   int     // -1 until the condition is fulfilled, WinnerDraw when it ends the battle undecided
+	Convoy   *Unit   // convoy: the escorted wagons (nil until spawned)
+	Waypoint int     // convoy: index of the route point the convoy heads for
+}
+
+// NewObjectives creates objectives from the stage victory conditions
+func NewObjectives(configs []data.VictoryConditionConfig) []*Objective {
+	objectives := make([]*Objective, 0, len(configs))
+	for _, config := range configs {
+		objectives = append(objectives, &Objective{
+			Config: config,
+			Holder: -1,
+			Taker:  -1,
+			Winner: -1,
+		})
+	}
+	return objectives
+}
+
+// Center returns the zone center of the objective
+func (o *Objective) Center() gamemath.Vector2D {
+	return gamemath.Vector2D{X: o.Config.X, Y: o.Config.Y}
+}
+
+// HasZone reports whether the objective uses a zone on the battlefield
+func (o *Objective) HasZone() bool {
+	return (o.Config.Type == data.VictoryCaptureZone || o.Config.Type == data.VictoryEscort) && o.Config.Radius > 0
+}
+
+// Description returns a human-readable description of the objective
+func (o *Objective) Description() string {
+	var who string
+	if armyID := data.ArmyIndex(o.Config.Army); armyID >= 0 {
+		who = "軍勢" + data.ArmyLabel(armyID) + ": "
+	}
+	
+	switch o.Config.Type {
+	case data.VictoryCommander:
+		return who + "敵将を撃破する"
+	case data.VictoryCaptureZone:
+		return fmt.Sprintf("%s拠点を%.0f秒間確保する", who, o.Config.Duration)
+	case data.VictorySurvive:
+		return fmt.Sprintf("%s%.0f秒間生き残る", who, o.Config.Duration)
+	case data.VictoryEscort:
+		return who + "総大将を脱出地点まで護衛する"
+	case data.VictoryConvoy:
+		return who + "輸送隊を目的地まで護送する"
+	default:
+		return who + o.Config.Type
+	}
+}
+
+// Status describes how far the objective has come for the objectives panel (empty: nothing to show)
+func (o *Objective) Status(battleTime float64) string {
+	switch o.Config.Type {
+	case data.VictoryCaptureZone:
+		if o.Holder < 0 {
+			return "確保なし"
+		}
+		return fmt.Sprintf("軍勢%s 確保中 %.0f/%.0f秒", data.ArmyLabel(o.Holder), o.Progress, o.Config.Duration)
+	case data.VictorySurvive:
+		return fmt.Sprintf("残り%.0f秒", max(o.Config.Duration-battleTime, 0))
+	case data.VictoryConvoy:
+		if o.Convoy == nil {
+			return ""
+		}
+		if !o.Convoy.IsAlive {
+			return "輸送隊が全滅"
+		}
+		return fmt.Sprintf("進捗 %.0f%%  耐久 %d/%d", o.ConvoyProgress()*100, o.Convoy.HP, o.Convoy.MaxHP)
+	default:
+		return ""
+	}
+}
+
+// updateObjectives evaluates all stage victory conditions
+func (bm *BattleManager) updateObjectives(deltaTime float64) {
+	for _, objective := range bm.Objectives {
+		if objective.Winner != WinnerUndecided {
+			continue
+		}
+		
+		switch objective.Config.Type {
+		case data.VictoryCommander:
+			bm.updateCommanderObjective(objective)
+		case data.VictoryCaptureZone:
+			bm.updateCaptureObjective(objective, deltaTime)
+		case data.VictorySurvive:
+			bm.updateSurviveObjective(objective)
+		case data.VictoryEscort:
+			bm.updateEscortObjective(objective)
+		case data.VictoryConvoy:
+			bm.updateConvoyObjective(objective)
+		}
+	}
+}
+
+// updateCommanderObjective checks whether an enemy commander has fallen
+func (bm *BattleManager) updateCommanderObjective(objective *Objective) {
+	for _, army := range bm.Armies {
+		if !objective.Config.AppliesTo(army.ID) {
+			continue
+		}
+		
+		for _, enemy := range bm.getHostileArmies(army.ID) {
+			commander := enemy.GetCommander()
+			if commander != nil && !commander.IsAlive {
+				objective.Winner = army.ID
+				return
+			}
+		}
+	}
+}
+
+// updateCaptureObjective accumulates hold time for the army controlling the zone
+func (bm *BattleManager) updateCaptureObjective(objective *Objective, deltaTime float64) {
+	holder := bm.getZoneHolder(objective.Center(), objective.Config.Radius)
+	
+	// Allies relieving each other keep the timer running
+	if holder >= 0 && objective.Holder >= 0 && bm.AreAllied(holder, objective.Holder) {
+		holder = objective.Holder
+	}
+	
+	// Contested or empty zones reset the hold timer
+	if holder != objective.Holder {
+		objective.Holder = holder
+		objective.Progress = 0
+		
+		// A zone changing hands is logged; one side retaking it after a scuffle is not
+		if holder >= 0 && (objective.Taker < 0 || !bm.AreAllied(holder, objective.Taker)) {
+			objective.Taker = holder
+			bm.recordEvent(BattleEvent{Type: EventCapture, Amount: holder, Position: objective.Center()})
+		}
+	}
+	
+	if holder < 0 || !objective.Config.AppliesTo(holder) {
+		return
+	}
+	
+	objective.Progress += deltaTime
+	if objective.Progress >= objective.Config.Duration {
+		objective.Winner = holder
+	}
+}
+
+// updateSurviveObjective declares the surviving army the winner once the duration elapses
+// A condition naming no army has no one holding out, so it ends the battle in a draw
+func (bm *BattleManager) updateSurviveObjective(objective *Objective) {
+	if bm.BattleTime < objective.Config.Duration {
+		return
+	}
+	if data.ArmyIndex(objective.Config.Army) < 0 {
+		objective.Winner = WinnerDraw
+		return
+	}
+	
+	for _, army := range bm.Armies {
+		if objective.Config.AppliesTo(army.ID) && !army.IsDefeated() {
+			objective.Winner = army.ID
+			return
+		}
+	}
+}
+
+// updateEscortObjective checks whether the escorted commander reached the exit or fell
+func (bm *BattleManager) updateEscortObjective(objective *Objective) {
+	for _, army := range bm.Armies {
+		if !objective.Config.AppliesTo(army.ID) {
+			continue
+		}
+		
+		escort := army.GetCommander()
+		if escort == nil {
+			continue
+		}
+		
+		if !escort.IsAlive {
+			if enemies := bm.getHostileArmies(army.ID); len(enemies) > 0 {
+				objective.Winner = enemies[0].ID
+			}
+			return
+		}
+		
+		if escort.Position.Distance(objective.Center()) <= objective.Config.Radius {
+			objective.Winner = army.ID
+			return
+		}
+	}
+}
+
+// getHostileArmies returns the armies outside the given army's alliance
+func (bm *BattleManager) getHostileArmies(armyID int) []*Army {
+	var hostile []*Army
+	for _, army := range bm.Armies {
+		if !bm.AreAllied(armyID, army.ID) {
+			hostile = append(hostile, army)
+		}
+	}
+	return hostile
+}
+
+// getZoneHolder returns the army holding a zone, or -1 when it is empty or contested
+// Allied armies may share a zone; the first one present is reported
+func (bm *BattleManager) getZoneHolder(center gamemath.Vector2D, radius float64) int {
+	holder := -1
+	for _, army := range bm.Armies {
+		if bm.countUnitsNear(army, center, radius) == 0 {
+			continue
+		}
+		if holder < 0 {
+			holder = army.ID
+		} else if !bm.AreAllied(holder, army.ID) {
+			return -1
+		}
+	}
+	return holder
+}
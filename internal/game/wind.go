@@ -0,0 +1,104 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Wind tuning
+const (
+	windMinSpeed     = 20.0   // 強風: 風速の下限（px/秒、2m/s）
+	windMaxSpeed     = 40.0   // 強風: 風速の上限（px/秒、4m/s）
+	arrowSpeed       = 1000.0 // 矢の飛ぶ速さ（px/秒）。遠くを射るほど長く風に流される
+	windHitTolerance = 6.0    // 流された矢が当たる、着弾点と的の衝突半径の外側の余裕（px）
+)
+
+// windDirections names the eight directions the wind blows from, clockwise from the north
+var windDirections = []string{"北", "北東", "東", "南東", "南", "南西", "西", "北西"}
+
+// rollWind picks the wind's direction and strength from the battle's seed when a mutator brings wind
+func (bm *BattleManager) rollWind() {
+	bm.Wind = gamemath.Vector2D{}
+	if !bm.hasWindMutator() {
+		return
+	}
+	angle := bm.rng.Float64() * 2 * math.Pi
+	speed := windMinSpeed + bm.rng.Float64()*(windMaxSpeed-windMinSpeed)
+	bm.Wind = gamemath.NewVector2D(math.Cos(angle)*speed, math.Sin(angle)*speed)
+}
+
+// hasWindMutator reports whether an active mutator brings wind
+func (bm *BattleManager) hasWindMutator() bool {
+	for _, mutator := range bm.Mutators {
+		if mutator.Wind {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWind reports whether wind blows over the battlefield
+func (bm *BattleManager) HasWind() bool {
+	return bm.Wind.Length() > 0
+}
+
+// WindText describes the wind like "北東の風 3m/s", naming where it blows from
+func (bm *BattleManager) WindText() string {
+	return windText(bm.Wind)
+}
+
+// windText describes a wind vector the way WindText does
+func windText(wind gamemath.Vector2D) string {
+	// The wind vector points where the wind blows to; screen north is -Y
+	from := wind.Mul(-1)
+	bearing := math.Atan2(from.X, -from.Y)
+	sector := int(math.Round(bearing/(math.Pi/4))+8) % 8
+	return fmt.Sprintf("%sの風 %.0fm/s", windDirections[sector], wind.Length()/10)
+}
+
+// firesArrows reports whether the unit's shots fly light enough for the wind to carry them
+func (u *Unit) firesArrows() bool {
+	return u.MaxAmmo > 0 && !u.MeleeFallback && !u.IsSiegeEngine()
+}
+
+// driftShot blows the unit's arrow off course just before it lands: crosswind carries it sideways
+// and a headwind makes it fall short, the further the longer it flies. An arrow blown off its target
+// hits whichever enemy stands where it comes down, or no one
+func (bm *BattleManager) driftShot(unit *Unit) {
+	target := unit.SwingTarget
+	if !bm.HasWind() || target == nil || !unit.firesArrows() {
+		return
+	}
+	
+	shot := target.Position.Sub(unit.Position)
+	distance := shot.Length()
+	if distance == 0 {
+		return
+	}
+	direction := shot.Normalize()
+	drift := bm.Wind.Mul(distance / arrowSpeed)
+	
+	// The archer aims for a tailwind, so only a headwind shortens the shot
+	if along := drift.Dot(direction); along > 0 {
+		drift = drift.Sub(direction.Mul(along))
+	}
+	landing := target.Position.Add(drift)
+	if landing.Distance(target.Position) <= target.GetCollisionRadius()+windHitTolerance {
+		return
+	}
+	
+	unit.SwingTarget = nil
+	closest := math.MaxFloat64
+	for _, enemy := range bm.GetEnemyUnits(unit.ArmyID) {
+		distance := landing.Distance(enemy.Position)
+		if distance <= enemy.GetCollisionRadius()+windHitTolerance && distance < closest && unit.CanHit(enemy) {
+			unit.SwingTarget = enemy
+			closest = distance
+		}
+	}
+	if unit.SwingTarget == nil {
+		bm.recordEvent(BattleEvent{Type: EventWindMiss, UnitID: unit.ID, GroupID: unit.GroupID, OtherID: target.ID, Position: landing})
+	}
+}
@@ -0,0 +1,25 @@
+package graphics
+
+// Anchor is the corner of the screen a UI element is laid out from
+type Anchor int
+
+// Screen anchors
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+)
+
+// Place returns the top-left position of a width x height element kept offsetX and offsetY
+// in from the anchor's edges of a screenWidth x screenHeight screen
+func (a Anchor) Place(screenWidth, screenHeight, offsetX, offsetY, width, height int) (int, int) {
+	x, y := offsetX, offsetY
+	if a == AnchorTopRight || a == AnchorBottomRight {
+		x = screenWidth - offsetX - width
+	}
+	if a == AnchorBottomLeft || a == AnchorBottomRight {
+		y = screenHeight - offsetY - height
+	}
+	return x, y
+}
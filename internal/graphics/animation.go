@@ -14,6 +14,9 @@ const (
 	AnimationDeath
 )
 
+// AttackHitFrame is the attack animation frame on which the blow lands
+const AttackHitFrame = 1
+
 // AnimationState holds the current animation state
 type AnimationState struct {
 	Type          AnimationType
@@ -57,7 +60,8 @@ func NewAnimationState(animType AnimationType) *AnimationState {
 	return state
 }
 
-// Update updates the animation state
+// Update advances the animation by the elapsed time; time left over from a frame carries into the next,
+// and a long step passes several frames, so the animation keeps the same pace at any frame rate or battle speed
 func (as *AnimationState) Update(deltaTime float64) {
 	if as.Finished && !as.Loop {
 		return
@@ -65,8 +69,8 @@ func (as *AnimationState) Update(deltaTime float64) {
 	
 	as.FrameTime += deltaTime
 	
-	if as.FrameTime >= as.FrameDuration {
-		as.FrameTime = 0
+	for as.FrameDuration > 0 && as.FrameTime >= as.FrameDuration {
+		as.FrameTime -= as.FrameDuration
 		as.Frame++
 		
 		if as.Frame >= as.TotalFrames {
@@ -74,7 +78,9 @@ func (as *AnimationState) Update(deltaTime float64) {
 				as.Frame = 0
 			} else {
 				as.Frame = as.TotalFrames - 1
+				as.FrameTime = 0
 				as.Finished = true
+				return
 			}
 		}
 	}
@@ -87,6 +93,11 @@ func (as *AnimationState) Reset() {
 	as.Finished = false
 }
 
+// HasReachedFrame reports whether the animation is of the given type and at or past the frame
+func (as *AnimationState) HasReachedFrame(animType AnimationType, frame int) bool {
+	return as.Type == animType && as.Frame >= frame
+}
+
 // SetAnimation changes the current animation type
 func (as *AnimationState) SetAnimation(animType AnimationType) {
 	if as.Type == animType {
@@ -0,0 +1,65 @@
+package graphics
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
+)
+
+// Button represents a clickable on-screen button
+type Button struct {
+	X, Y          int
+	Width, Height int
+	Label         string
+	Active        bool // Highlighted while a toggled state is on
+}
+
+// NewButton creates a new button
+func NewButton(x, y, width, height int, label string) *Button {
+	return &Button{
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: height,
+		Label:  label,
+	}
+}
+
+// Contains reports whether the screen position is inside the button
+func (b *Button) Contains(x, y int) bool {
+	return x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height
+}
+
+// IsHovered reports whether the mouse cursor is over the button
+func (b *Button) IsHovered() bool {
+	return b.Contains(controls.CursorPosition())
+}
+
+// IsClicked reports whether the button was clicked this frame
+func (b *Button) IsClicked() bool {
+	return controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && b.IsHovered()
+}
+
+// IsHeld reports whether the button is being held down
+func (b *Button) IsHeld() bool {
+	return controls.IsMouseButtonPressed(ebiten.MouseButtonLeft) && b.IsHovered()
+}
+
+// Draw draws the button with its centered label in the current theme's colors
+func (b *Button) Draw(screen *ebiten.Image, textRenderer *TextRenderer) {
+	theme := CurrentTheme()
+	fillColor := WithAlpha(theme.Panel, 220)
+	if b.Active {
+		fillColor = WithAlpha(theme.Warning, 230)
+	}
+	if b.IsHovered() {
+		fillColor = WithAlpha(theme.Accent, 230)
+	}
+	
+	x, y := float32(b.X), float32(b.Y)
+	w, h := float32(b.Width), float32(b.Height)
+	vector.DrawFilledRect(screen, x, y, w, h, fillColor, false)
+	vector.StrokeRect(screen, x, y, w, h, 1, theme.Text, false)
+	
+	textRenderer.DrawCenteredText(screen, b.Label, float64(b.X)+float64(b.Width)/2, float64(b.Y)+float64(b.Height)/2, theme.Text)
+}
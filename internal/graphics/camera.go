@@ -27,6 +27,10 @@ type CameraManager struct {
 	// World size
 	WorldWidth, WorldHeight float64
 	
+	// Area the view is kept inside (the whole world by default)
+	boundsLeft, boundsTop     float64
+	boundsRight, boundsBottom float64
+	
 	// Settings
 	ScrollSpeed float64
 	ZoomSpeed   float64
@@ -52,6 +56,8 @@ func NewCameraManager(worldWidth, worldHeight float64, viewportWidth, viewportHe
 		ViewportHeight: viewportHeight,
 		WorldWidth:     worldWidth,
 		WorldHeight:    worldHeight,
+		boundsRight:    worldWidth,
+		boundsBottom:   worldHeight,
 		ScrollSpeed:    800.0, // 100.0 -> 800.0 (8倍速)
 		ZoomSpeed:      4.0,   // 2.0 -> 4.0 (2倍速)
 		SmoothMove:     false, // true -> false (即座に移動)
@@ -120,6 +126,41 @@ func (c *CameraManager) SetPosition(x, y float64) {
 	c.applyConstraints()
 }
 
+// CenterOn moves the camera immediately so the world point is at the center of the view
+func (c *CameraManager) CenterOn(worldX, worldY float64) {
+	viewWidth := float64(c.ViewportWidth) / c.Zoom
+	viewHeight := float64(c.ViewportHeight) / c.Zoom
+	c.SetPosition(worldX-viewWidth/2, worldY-viewHeight/2)
+}
+
+// SetWorldSize resizes the world for a new battlefield and lets the camera scroll over all of it
+func (c *CameraManager) SetWorldSize(worldWidth, worldHeight float64) {
+	c.WorldWidth = worldWidth
+	c.WorldHeight = worldHeight
+	c.ResetBounds()
+}
+
+// SetBounds limits scrolling to a world area, clamped to the world
+func (c *CameraManager) SetBounds(left, top, right, bottom float64) {
+	c.boundsLeft = math.Max(0, left)
+	c.boundsTop = math.Max(0, top)
+	c.boundsRight = math.Min(c.WorldWidth, right)
+	c.boundsBottom = math.Min(c.WorldHeight, bottom)
+	c.updateConstraints()
+	c.applyConstraints()
+	c.applyTargetConstraints()
+}
+
+// ResetBounds lets the camera scroll over the whole world again
+func (c *CameraManager) ResetBounds() {
+	c.SetBounds(0, 0, c.WorldWidth, c.WorldHeight)
+}
+
+// GetBounds returns the world area the view is kept inside
+func (c *CameraManager) GetBounds() (left, top, right, bottom float64) {
+	return c.boundsLeft, c.boundsTop, c.boundsRight, c.boundsBottom
+}
+
 // SetTargetPosition sets the target position for smooth movement
 func (c *CameraManager) SetTargetPosition(x, y float64) {
 	c.TargetX = x
@@ -215,14 +256,18 @@ func (c *CameraManager) updateConstraints() {
 	viewWidth := float64(c.ViewportWidth) / c.Zoom
 	viewHeight := float64(c.ViewportHeight) / c.Zoom
 	
-	c.MaxX = c.WorldWidth - viewWidth
-	c.MaxY = c.WorldHeight - viewHeight
+	c.MinX = c.boundsLeft
+	c.MinY = c.boundsTop
+	c.MaxX = c.boundsRight - viewWidth
+	c.MaxY = c.boundsBottom - viewHeight
 	
-	// Ensure min constraints don't exceed max
+	// Center the view on bounds smaller than the view
 	if c.MaxX < c.MinX {
+		c.MinX = (c.boundsLeft + c.boundsRight - viewWidth) / 2
 		c.MaxX = c.MinX
 	}
 	if c.MaxY < c.MinY {
+		c.MinY = (c.boundsTop + c.boundsBottom - viewHeight) / 2
 		c.MaxY = c.MinY
 	}
 }
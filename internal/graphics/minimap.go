@@ -6,9 +6,36 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
 )
 
+// MinimapMarkerShape selects how a marker is drawn
+type MinimapMarkerShape int
+
+const (
+	MarkerDot     MinimapMarkerShape = iota // Filled square (units)
+	MarkerDiamond                           // Outlined diamond (objectives)
+	MarkerRing                              // Zone outline with a progress arc (capture progress)
+	MarkerArrow                             // Arrow from where something will arrive (reinforcements)
+)
+
+// MinimapMarker represents a point of interest drawn on the minimap
+type MinimapMarker struct {
+	X, Y  float64 // World coordinates
+	Size  int     // Marker size in minimap pixels
+	Color color.Color
+	Shape MinimapMarkerShape
+	
+	// MarkerRing: zone radius in world units and progress 0.0 .. 1.0 drawn in ProgressColor
+	Radius        float64
+	Progress      float64
+	ProgressColor color.Color
+	
+	// MarkerArrow: direction the arrow points in radians
+	Angle float64
+}
+
 // Minimap represents the minimap display
 type Minimap struct {
 	camera *CameraManager
@@ -28,6 +55,9 @@ type Minimap struct {
 	backgroundImage *ebiten.Image
 	minimapImage    *ebiten.Image
 	
+	// Markers supplied by the scene
+	markers []MinimapMarker
+	
 	// Update control
 	needUpdate    bool
 	updateCounter int
@@ -48,21 +78,12 @@ type Minimap struct {
 
 // NewMinimap creates a new minimap
 func NewMinimap(camera *CameraManager, x, y, width, height int) *Minimap {
-	worldWidth := camera.WorldWidth
-	worldHeight := camera.WorldHeight
-	
-	// Calculate scale to fit world in minimap
-	scaleX := float64(width) / worldWidth
-	scaleY := float64(height) / worldHeight
-	scale := math.Min(scaleX, scaleY)
-	
 	minimap := &Minimap{
 		camera:            camera,
 		X:                 x,
 		Y:                 y,
 		Width:             width,
 		Height:            height,
-		Scale:             scale,
 		Visible:           true,
 		ShowUnits:         true,
 		ShowTerrain:       true,
@@ -90,9 +111,18 @@ func NewMinimap(camera *CameraManager, x, y, width, height int) *Minimap {
 	// Fill background
 	minimap.backgroundImage.Fill(minimap.backgroundColor)
 	
+	minimap.FitWorld()
 	return minimap
 }
 
+// FitWorld scales the camera's world to fit the minimap, after the world was resized
+func (m *Minimap) FitWorld() {
+	scaleX := float64(m.Width) / m.camera.WorldWidth
+	scaleY := float64(m.Height) / m.camera.WorldHeight
+	m.Scale = math.Min(scaleX, scaleY)
+	m.needUpdate = true
+}
+
 // Update updates the minimap
 func (m *Minimap) Update() {
 	if !m.Visible {
@@ -125,6 +155,9 @@ func (m *Minimap) Draw(screen *ebiten.Image) {
 	// Draw minimap content
 	screen.DrawImage(m.minimapImage, op)
 	
+	// Draw markers
+	m.drawMarkers(screen)
+	
 	// Draw viewport rectangle
 	if m.ShowViewport {
 		m.drawViewport(screen)
@@ -217,6 +250,80 @@ func (m *Minimap) drawViewport(screen *ebiten.Image) {
 	}
 }
 
+// drawMarkers draws the scene-supplied markers
+func (m *Minimap) drawMarkers(screen *ebiten.Image) {
+	for _, marker := range m.markers {
+		x, y := m.WorldToMinimap(marker.X, marker.Y)
+		if x < m.X || x >= m.X+m.Width || y < m.Y || y >= m.Y+m.Height {
+			continue
+		}
+		
+		switch marker.Shape {
+		case MarkerDiamond:
+			m.drawDiamond(screen, float32(x), float32(y), marker)
+		case MarkerRing:
+			m.drawRing(screen, float32(x), float32(y), marker)
+		case MarkerArrow:
+			m.drawArrow(screen, float32(x), float32(y), marker)
+		default:
+			half := marker.Size / 2
+			ebitenutil.DrawRect(screen, float64(x-half), float64(y-half), float64(marker.Size), float64(marker.Size), marker.Color)
+		}
+	}
+}
+
+// drawDiamond draws an objective marker
+func (m *Minimap) drawDiamond(screen *ebiten.Image, x, y float32, marker MinimapMarker) {
+	half := float32(marker.Size) / 2
+	corners := [][2]float32{{x, y - half}, {x + half, y}, {x, y + half}, {x - half, y}}
+	for i, corner := range corners {
+		next := corners[(i+1)%len(corners)]
+		vector.StrokeLine(screen, corner[0], corner[1], next[0], next[1], 1.5, marker.Color, true)
+	}
+}
+
+// drawRing draws a zone outline with its progress as an arc starting at the top
+func (m *Minimap) drawRing(screen *ebiten.Image, x, y float32, marker MinimapMarker) {
+	radius := float32(marker.Radius * m.Scale)
+	if radius < float32(marker.Size)/2 {
+		radius = float32(marker.Size) / 2
+	}
+	vector.StrokeCircle(screen, x, y, radius, 1, marker.Color, true)
+	
+	if marker.Progress <= 0 || marker.ProgressColor == nil {
+		return
+	}
+	
+	// Approximate the arc with short segments
+	const segments = 24
+	progress := math.Min(marker.Progress, 1.0)
+	steps := int(math.Ceil(segments * progress))
+	prevX, prevY := x, y-radius
+	for i := 1; i <= steps; i++ {
+		angle := 2*math.Pi*progress*float64(i)/float64(steps) - math.Pi/2
+		nextX := x + radius*float32(math.Cos(angle))
+		nextY := y + radius*float32(math.Sin(angle))
+		vector.StrokeLine(screen, prevX, prevY, nextX, nextY, 3, marker.ProgressColor, true)
+		prevX, prevY = nextX, nextY
+	}
+}
+
+// drawArrow draws an arrow leaving the marker position in its direction
+func (m *Minimap) drawArrow(screen *ebiten.Image, x, y float32, marker MinimapMarker) {
+	length := float64(marker.Size)
+	tipX := x + float32(math.Cos(marker.Angle)*length)
+	tipY := y + float32(math.Sin(marker.Angle)*length)
+	vector.StrokeLine(screen, x, y, tipX, tipY, 2, marker.Color, true)
+	
+	// Arrow head
+	for _, side := range []float64{-1, 1} {
+		headAngle := marker.Angle + math.Pi + side*math.Pi/6
+		headX := tipX + float32(math.Cos(headAngle)*length/2)
+		headY := tipY + float32(math.Sin(headAngle)*length/2)
+		vector.StrokeLine(screen, tipX, tipY, headX, headY, 2, marker.Color, true)
+	}
+}
+
 // drawBorder draws the minimap border
 func (m *Minimap) drawBorder(screen *ebiten.Image) {
 	borderColor := color.RGBA{200, 200, 200, 255}
@@ -230,17 +337,17 @@ func (m *Minimap) drawBorder(screen *ebiten.Image) {
 
 // handleInput handles minimap input
 func (m *Minimap) handleInput() {
-	mouseX, mouseY := ebiten.CursorPosition()
+	mouseX, mouseY := controls.CursorPosition()
 	
 	// Check if mouse is over minimap
 	if mouseX >= m.X && mouseX < m.X+m.Width && mouseY >= m.Y && mouseY < m.Y+m.Height {
 		// Handle left click - move camera to clicked position
-		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 			m.handleMinimapClick(mouseX, mouseY)
 		}
 		
 		// Handle drag - start dragging viewport
-		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 			m.isDragging = true
 			m.dragStartX = mouseX
 			m.dragStartY = mouseY
@@ -249,7 +356,7 @@ func (m *Minimap) handleInput() {
 	
 	// Handle dragging
 	if m.isDragging {
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if controls.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 			m.handleMinimapDrag(mouseX, mouseY)
 		} else {
 			m.isDragging = false
@@ -257,7 +364,7 @@ func (m *Minimap) handleInput() {
 	}
 	
 	// Handle right click - toggle minimap visibility
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
 		if mouseX >= m.X && mouseX < m.X+m.Width && mouseY >= m.Y && mouseY < m.Y+m.Height {
 			m.Visible = !m.Visible
 		}
@@ -338,6 +445,11 @@ func (m *Minimap) SetShowTerrain(show bool) {
 	m.needUpdate = true
 }
 
+// SetMarkers replaces the markers drawn on the minimap
+func (m *Minimap) SetMarkers(markers []MinimapMarker) {
+	m.markers = markers
+}
+
 // SetPosition sets the minimap position
 func (m *Minimap) SetPosition(x, y int) {
 	m.X = x
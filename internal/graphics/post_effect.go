@@ -0,0 +1,140 @@
+package graphics
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// postEffectShader composites the battlefield in one pass: it blooms the light sources of the
+// second image, shades dusk and night, drains the colors and darkens the edges towards a deep red
+// Colors are premultiplied by alpha, so the world layer is expected to be opaque
+var postEffectShader = []byte(`//kage:unit pixels
+
+package main
+
+var Size vec2
+var Night float
+var Grayscale float
+var Vignette float
+var Bloom float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	clr := imageSrc0At(srcPos)
+	
+	// 光の層をぼかして足す
+	if Bloom > 0 {
+		glow := vec4(0)
+		total := 0.0
+		for i := -3; i <= 3; i++ {
+			for j := -3; j <= 3; j++ {
+				w := exp(-float(i*i+j*j) / 4.5)
+				glow += imageSrc1At(srcPos+vec2(float(i), float(j))*3) * w
+				total += w
+			}
+		}
+		clr.rgb = min(clr.rgb+glow.rgb/total*Bloom*2, vec3(clr.a))
+	}
+	
+	// 夕焼けの橙から夜の青へ、暗くなるほど色も薄れる
+	if Night > 0 {
+		lum := dot(clr.rgb, vec3(0.299, 0.587, 0.114))
+		tint := mix(vec3(1.0, 0.6, 0.35), vec3(0.3, 0.4, 0.85), Night)
+		clr.rgb = mix(clr.rgb, vec3(lum), Night*0.5) * mix(vec3(1), tint, Night*0.8)
+	}
+	
+	// 彩度を落とす
+	gray := dot(clr.rgb, vec3(0.299, 0.587, 0.114))
+	clr.rgb = mix(clr.rgb, vec3(gray), Grayscale)
+	
+	// 画面の端ほど暗い赤に沈める
+	pos := (dstPos.xy - imageDstOrigin()) / Size
+	edge := smoothstep(0.35, 0.75, distance(pos, vec2(0.5)))
+	clr.rgb = mix(clr.rgb, vec3(0.2, 0, 0)*clr.a, edge*Vignette)
+	
+	return clr
+}
+`)
+
+// PostEffectParams are the strengths (0.0-1.0) of the post-processing passes
+type PostEffectParams struct {
+	Night     float64 // 夕暮れから夜への色調
+	Grayscale float64 // 彩度を落とす量
+	Vignette  float64 // 画面の端を沈める量
+	Bloom     float64 // 光の層をにじませて足す量
+}
+
+// isZero reports whether none of the passes change the image
+func (pp PostEffectParams) isZero() bool {
+	return pp.Night <= 0 && pp.Grayscale <= 0 && pp.Vignette <= 0 && pp.Bloom <= 0
+}
+
+// PostEffect composites an offscreen layer onto the screen with full-screen shader effects
+type PostEffect struct {
+	shader *ebiten.Shader // nil: シェーダーが使えず、そのまま描く
+}
+
+// NewPostEffect compiles the post-processing shader
+// If the shader cannot be compiled, the layer is drawn without effects
+func NewPostEffect() *PostEffect {
+	shader, err := ebiten.NewShader(postEffectShader)
+	if err != nil {
+		fmt.Printf("Post effects disabled: %v\n", err)
+	}
+	return &PostEffect{shader: shader}
+}
+
+// Supported reports whether the shader compiled; without it Draw only copies the layer
+// and the caller draws its own stand-ins for the effects
+func (pe *PostEffect) Supported() bool {
+	return pe.shader != nil
+}
+
+// Draw draws src onto dst with the post-processing passes
+// glow holds the light sources to bloom, the same size as src (nil: nothing glows)
+func (pe *PostEffect) Draw(dst, src, glow *ebiten.Image, params PostEffectParams) {
+	if glow == nil {
+		params.Bloom = 0
+	}
+	if pe.shader == nil || params.isZero() {
+		dst.DrawImage(src, nil)
+		return
+	}
+	
+	bounds := src.Bounds()
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Images[0] = src
+	op.Images[1] = glow
+	op.Uniforms = map[string]interface{}{
+		"Size":      []float32{float32(bounds.Dx()), float32(bounds.Dy())},
+		"Night":     float32(min(params.Night, 1)),
+		"Grayscale": float32(min(params.Grayscale, 1)),
+		"Vignette":  float32(min(params.Vignette, 1)),
+		"Bloom":     float32(min(params.Bloom, 1)),
+	}
+	dst.DrawRectShader(bounds.Dx(), bounds.Dy(), pe.shader, op)
+}
+
+// SaveScreenshot writes the image to a timestamped PNG file in dir and returns its path
+// The image can only be read once the game is running, from Update or Draw
+func SaveScreenshot(img *ebiten.Image, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	
+	filename := filepath.Join(dir, time.Now().Format("20060102-150405.000")+".png")
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	
+	if err := png.Encode(file, img); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
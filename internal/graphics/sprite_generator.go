@@ -10,6 +10,9 @@ import (
 // SpriteGenerator generates unit sprites programmatically
 type SpriteGenerator struct {
 	cache map[string]*ebiten.Image
+	
+	// ReduceFlashing disables bright flash effects for accessibility
+	ReduceFlashing bool
 }
 
 // NewSpriteGenerator creates a new sprite generator
@@ -19,6 +22,11 @@ func NewSpriteGenerator() *SpriteGenerator {
 	}
 }
 
+// SetReduceFlashing enables or disables the reduced flashing mode
+func (sg *SpriteGenerator) SetReduceFlashing(reduce bool) {
+	sg.ReduceFlashing = reduce
+}
+
 // GenerateUnitSprite generates an animated sprite for a unit
 func (sg *SpriteGenerator) GenerateUnitSprite(unitType string, baseColor color.RGBA, isLeader bool, animState *AnimationState) *ebiten.Image {
 	size := 16
@@ -50,7 +58,7 @@ func (sg *SpriteGenerator) GenerateUnitSprite(unitType string, baseColor color.R
 		sg.drawAnimatedSquare(img, centerX, centerY, actualSize/2, baseColor, isLeader, animState, rotation)
 	case "archer":
 		sg.drawAnimatedTriangle(img, centerX, centerY, actualSize/2, baseColor, isLeader, animState, rotation)
-	case "mage":
+	case "mage", "summoner":
 		sg.drawAnimatedDiamond(img, centerX, centerY, actualSize/2, baseColor, isLeader, animState, rotation)
 	default:
 		sg.drawAnimatedCircle(img, centerX, centerY, actualSize/2, baseColor, isLeader, animState, rotation)
@@ -162,7 +170,7 @@ func (sg *SpriteGenerator) drawAnimatedDiamond(img *ebiten.Image, centerX, cente
 		pulseMod = 1.0 + math.Sin(float64(animState.Frame)*math.Pi/2)*0.1
 	case AnimationAttack:
 		// Bright flash during attack
-		if animState.Frame == 1 {
+		if animState.Frame == 1 && !sg.ReduceFlashing {
 			pulseMod = 1.3
 			// Make color brighter
 			baseColor.R = uint8(math.Min(255, float64(baseColor.R)*1.2))
@@ -243,7 +251,7 @@ func (sg *SpriteGenerator) drawAnimatedCircle(img *ebiten.Image, centerX, center
 func (sg *SpriteGenerator) addAnimationEffects(img *ebiten.Image, centerX, centerY, size int, animState *AnimationState) {
 	switch animState.Type {
 	case AnimationAttack:
-		if animState.Frame == 1 {
+		if animState.Frame == 1 && !sg.ReduceFlashing {
 			// Add attack flash effect
 			flashColor := color.RGBA{255, 255, 0, 128} // Yellow flash
 			for i := 0; i < 3; i++ {
@@ -0,0 +1,186 @@
+package graphics
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/clipboard"
+	"github.com/shirou/tinygocha/internal/controls"
+)
+
+// Text input timing, in ticks
+const (
+	caretBlinkTicks     = 30 // キャレットの点滅の半周期
+	backspaceDelayTicks = 30 // Backspaceを押し続けてから連続で消し始めるまで
+	backspaceRepeatTick = 3  // 連続で消す間隔
+)
+
+// textInputPadding is the space between the box and its text
+const textInputPadding = 8
+
+// TextInput is a single-line text field for names, codes and commands
+// Clicking it takes the keyboard focus; while focused it reads typed text through the IME,
+// shows the text being composed underlined, and reports Enter as a submission
+type TextInput struct {
+	X, Y          int
+	Width, Height int
+	Text          string
+	Placeholder   string // 空のときに薄く表示する説明
+	MaxLength     int    // 文字数の上限（0: なし）
+	
+	focused        bool
+	submitted      bool
+	ticks          int
+	backspaceTicks int
+}
+
+// NewTextInput creates a new text field
+func NewTextInput(x, y, width, height int, placeholder string, maxLength int) *TextInput {
+	return &TextInput{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Height:      height,
+		Placeholder: placeholder,
+		MaxLength:   maxLength,
+	}
+}
+
+// Contains reports whether the screen position is inside the field
+func (t *TextInput) Contains(x, y int) bool {
+	return x >= t.X && x < t.X+t.Width && y >= t.Y && y < t.Y+t.Height
+}
+
+// Focus gives the field the keyboard focus
+func (t *TextInput) Focus() {
+	t.focused = true
+	t.ticks = 0
+}
+
+// Blur takes the keyboard focus away from the field
+func (t *TextInput) Blur() {
+	t.focused = false
+}
+
+// IsFocused reports whether the field has the keyboard focus
+func (t *TextInput) IsFocused() bool {
+	return t.focused
+}
+
+// IsSubmitted reports whether Enter was pressed in the field this tick
+func (t *TextInput) IsSubmitted() bool {
+	return t.submitted
+}
+
+// Update handles focus clicks and, while focused, typing, Backspace, Enter and Escape (which drops the focus)
+// Scenes should skip their own key shortcuts while the field is focused
+func (t *TextInput) Update() {
+	t.submitted = false
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if t.Contains(controls.CursorPosition()) {
+			t.Focus()
+		} else {
+			t.Blur()
+		}
+	}
+	if !t.focused {
+		return
+	}
+	t.ticks++
+	
+	t.insert(controls.TypedText())
+	
+	// The IME keeps Backspace and Enter while it is composing
+	if controls.Composition() != "" {
+		t.backspaceTicks = 0
+	} else {
+		t.handleKeys()
+	}
+	
+	// The IME candidates open under the field
+	controls.StartTextInput(t.X+textInputPadding, t.Y+t.Height)
+}
+
+// insert appends the printable characters of the text, up to MaxLength
+func (t *TextInput) insert(text string) {
+	for _, r := range text {
+		if !unicode.IsPrint(r) || (t.MaxLength > 0 && utf8.RuneCountInString(t.Text) >= t.MaxLength) {
+			continue
+		}
+		t.Text += string(r)
+	}
+}
+
+// handleKeys deletes with Backspace, repeating while held, pastes the first line of the clipboard
+// with Ctrl+V and submits with Enter
+func (t *TextInput) handleKeys() {
+	if controls.IsShortcutJustPressed(ebiten.KeyV) {
+		if text, err := clipboard.ReadText(); err != nil {
+			fmt.Printf("Warning: Failed to paste from the clipboard: %v\n", err)
+		} else {
+			line, _, _ := strings.Cut(text, "\n")
+			t.insert(line)
+		}
+	}
+	
+	if controls.IsKeyPressed(ebiten.KeyBackspace) {
+		t.backspaceTicks++
+		repeat := t.backspaceTicks > backspaceDelayTicks && (t.backspaceTicks-backspaceDelayTicks)%backspaceRepeatTick == 0
+		if t.backspaceTicks == 1 || repeat {
+			_, size := utf8.DecodeLastRuneInString(t.Text)
+			t.Text = t.Text[:len(t.Text)-size]
+		}
+	} else {
+		t.backspaceTicks = 0
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) {
+		t.submitted = true
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		t.Blur()
+	}
+}
+
+// Draw draws the field with its text, the IME composition underlined after it and a blinking caret
+func (t *TextInput) Draw(screen *ebiten.Image, textRenderer *TextRenderer) {
+	theme := CurrentTheme()
+	x, y := float32(t.X), float32(t.Y)
+	w, h := float32(t.Width), float32(t.Height)
+	borderColor := theme.TextMuted
+	if t.focused {
+		borderColor = theme.Accent
+	}
+	vector.DrawFilledRect(screen, x, y, w, h, WithAlpha(theme.PanelDark, 230), false)
+	vector.StrokeRect(screen, x, y, w, h, 1, borderColor, false)
+	
+	textX := float64(t.X + textInputPadding)
+	_, lineHeight := textRenderer.MeasureText("あ")
+	textY := float64(t.Y) + (float64(t.Height)-lineHeight)/2
+	if t.Text == "" && !t.focused {
+		textRenderer.DrawText(screen, t.Placeholder, textX, textY, theme.TextDisabled)
+		return
+	}
+	
+	textRenderer.DrawText(screen, t.Text, textX, textY, theme.Text)
+	if !t.focused {
+		return
+	}
+	
+	caretX, _ := textRenderer.MeasureText(t.Text)
+	caretX += textX
+	if composing := controls.Composition(); composing != "" {
+		width, _ := textRenderer.MeasureText(composing)
+		textRenderer.DrawText(screen, composing, caretX, textY, theme.Accent)
+		underlineY := float32(textY + lineHeight)
+		vector.StrokeLine(screen, float32(caretX), underlineY, float32(caretX+width), underlineY, 1, theme.Accent, false)
+		caretX += width
+	}
+	if (t.ticks/caretBlinkTicks)%2 == 0 {
+		vector.StrokeLine(screen, float32(caretX)+1, float32(textY), float32(caretX)+1, float32(textY+lineHeight), 1, theme.Text, false)
+	}
+}
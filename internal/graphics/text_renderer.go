@@ -10,18 +10,69 @@ import (
 // TextRenderer handles text rendering with proper fonts
 type TextRenderer struct {
 	fontManager *FontManager
+	scale       float64 // Global text scale for accessibility
+	
+	// Metrics of the UI language
+	languageScale float64 // 言語ごとのフォントサイズの倍率
+	lineSpacing   float64 // 言語ごとの行の間隔の倍率
 }
 
 // NewTextRenderer creates a new text renderer
 func NewTextRenderer(fontManager *FontManager) *TextRenderer {
 	return &TextRenderer{
-		fontManager: fontManager,
+		fontManager:   fontManager,
+		scale:         1.0,
+		languageScale: 1.0,
+		lineSpacing:   1.0,
+	}
+}
+
+// SetLanguageMetrics sets the font size and row spacing multipliers of the UI language
+// Non-positive values leave the metric unchanged
+func (tr *TextRenderer) SetLanguageMetrics(fontScale, lineSpacing float64) {
+	if fontScale <= 0 {
+		fontScale = 1.0
+	}
+	if lineSpacing <= 0 {
+		lineSpacing = 1.0
+	}
+	tr.languageScale = fontScale
+	tr.lineSpacing = lineSpacing
+}
+
+// Spacing returns a layout row step adjusted to the UI language's line spacing
+func (tr *TextRenderer) Spacing(step float64) float64 {
+	return step * tr.lineSpacing
+}
+
+// SetScale sets the global text scale applied to all drawn and measured text
+func (tr *TextRenderer) SetScale(scale float64) {
+	if scale <= 0 {
+		scale = 1.0
+	}
+	tr.scale = scale
+}
+
+// GetScale returns the global text scale
+func (tr *TextRenderer) GetScale() float64 {
+	return tr.scale
+}
+
+// scaledFace returns the font face resized by the global text scale and the UI language
+func (tr *TextRenderer) scaledFace(face *text.GoTextFace) *text.GoTextFace {
+	scale := tr.scale * tr.languageScale
+	if face == nil || scale == 1.0 {
+		return face
+	}
+	return &text.GoTextFace{
+		Source: face.Source,
+		Size:   face.Size * scale,
 	}
 }
 
 // DrawText draws text at the specified position
 func (tr *TextRenderer) DrawText(screen *ebiten.Image, str string, x, y float64, clr color.Color) {
-	font := tr.fontManager.GetDefaultFont()
+	font := tr.scaledFace(tr.fontManager.GetDefaultFont())
 	if font == nil {
 		return
 	}
@@ -43,6 +94,7 @@ func (tr *TextRenderer) DrawTextWithFont(screen *ebiten.Image, str string, x, y
 	if font == nil {
 		return
 	}
+	font = tr.scaledFace(font)
 	
 	op := &text.DrawOptions{}
 	op.GeoM.Translate(x, y)
@@ -61,6 +113,7 @@ func (tr *TextRenderer) DrawTextWithSize(screen *ebiten.Image, str string, x, y
 	if font == nil {
 		return
 	}
+	font = tr.scaledFace(font)
 	
 	op := &text.DrawOptions{}
 	op.GeoM.Translate(x, y)
@@ -71,7 +124,7 @@ func (tr *TextRenderer) DrawTextWithSize(screen *ebiten.Image, str string, x, y
 
 // MeasureText measures the size of text
 func (tr *TextRenderer) MeasureText(str string) (float64, float64) {
-	font := tr.fontManager.GetDefaultFont()
+	font := tr.scaledFace(tr.fontManager.GetDefaultFont())
 	if font == nil {
 		return 0, 0
 	}
@@ -90,6 +143,7 @@ func (tr *TextRenderer) MeasureTextWithFont(str string, fontName string) (float6
 	if font == nil {
 		return 0, 0
 	}
+	font = tr.scaledFace(font)
 	
 	width, height := text.Measure(str, font, 0)
 	return width, height
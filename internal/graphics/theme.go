@@ -0,0 +1,135 @@
+package graphics
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Theme is the palette the UI is drawn with
+// Scenes look their colors up here when drawing, so switching the theme recolors every screen at once
+type Theme struct {
+	Name string
+	
+	Background   color.RGBA // 画面の背景
+	Panel        color.RGBA // パネル・ステータスバー
+	PanelDark    color.RGBA // 入力欄・説明の枠
+	Inset        color.RGBA // 配置プレビュー・ヒートマップの下地
+	Overlay      color.RGBA // 一時停止やバナーで戦場に重ねる幕
+	Shadow       color.RGBA // 選択中の文字の影
+	Text         color.RGBA // 本文
+	TextMuted    color.RGBA // 補足
+	TextDisabled color.RGBA // 選べない項目
+	TextHint     color.RGBA // 操作の案内
+	TextBright   color.RGBA // 幕の上の見出し
+	Highlight    color.RGBA // 強調・お知らせ
+	Accent       color.RGBA // 選択中の項目・フォーカス
+	Success      color.RGBA
+	Warning      color.RGBA
+	Danger       color.RGBA
+	Magic        color.RGBA // 魔力・指揮力
+	Debug        color.RGBA // デバッグ表示
+}
+
+// DefaultTheme is the dark palette the game was designed with, used until a theme is loaded
+// and for the colors a theme leaves out
+var DefaultTheme = Theme{
+	Name:         "ダーク",
+	Background:   color.RGBA{44, 62, 80, 255},    // #2C3E50
+	Panel:        color.RGBA{52, 73, 94, 255},    // #34495E
+	PanelDark:    color.RGBA{30, 39, 46, 240},    // #1E272E
+	Inset:        color.RGBA{39, 55, 70, 255},    // #273746
+	Overlay:      color.RGBA{0, 0, 0, 160},       // #000000
+	Shadow:       color.RGBA{0, 0, 0, 128},       // #000000
+	Text:         color.RGBA{236, 240, 241, 255}, // #ECF0F1
+	TextMuted:    color.RGBA{149, 165, 166, 255}, // #95A5A6
+	TextDisabled: color.RGBA{127, 140, 141, 255}, // #7F8C8D
+	TextHint:     color.RGBA{189, 195, 199, 255}, // #BDC3C7
+	TextBright:   color.RGBA{255, 255, 255, 255}, // #FFFFFF
+	Highlight:    color.RGBA{241, 196, 15, 255},  // #F1C40F
+	Accent:       color.RGBA{52, 152, 219, 255},  // #3498DB
+	Success:      color.RGBA{46, 204, 113, 255},  // #2ECC71
+	Warning:      color.RGBA{243, 156, 18, 255},  // #F39C12
+	Danger:       color.RGBA{231, 76, 60, 255},   // #E74C3C
+	Magic:        color.RGBA{155, 89, 182, 255},  // #9B59B6
+	Debug:        color.RGBA{255, 255, 0, 255},   // #FFFF00
+}
+
+var currentTheme = &DefaultTheme
+
+// CurrentTheme returns the theme the UI is drawn with
+func CurrentTheme() *Theme {
+	return currentTheme
+}
+
+// SetTheme switches the UI to the theme from the next frame on
+func SetTheme(theme *Theme) {
+	currentTheme = theme
+}
+
+// NewTheme builds a theme from the "#RRGGBB" or "#RRGGBBAA" colors of a theme definition,
+// keyed by role as in themes.toml; roles left out keep DefaultTheme's colors
+func NewTheme(name string, colors map[string]string) (*Theme, error) {
+	theme := DefaultTheme
+	theme.Name = name
+	roles := theme.roles()
+	for role, hex := range colors {
+		target, ok := roles[role]
+		if !ok {
+			return nil, fmt.Errorf("unknown theme color %q", role)
+		}
+		c, err := ParseHexColor(hex)
+		if err != nil {
+			return nil, fmt.Errorf("theme color %s: %w", role, err)
+		}
+		*target = c
+	}
+	return &theme, nil
+}
+
+// roles maps the themes.toml keys to the theme's colors
+func (t *Theme) roles() map[string]*color.RGBA {
+	return map[string]*color.RGBA{
+		"background":    &t.Background,
+		"panel":         &t.Panel,
+		"panel_dark":    &t.PanelDark,
+		"inset":         &t.Inset,
+		"overlay":       &t.Overlay,
+		"shadow":        &t.Shadow,
+		"text":          &t.Text,
+		"text_muted":    &t.TextMuted,
+		"text_disabled": &t.TextDisabled,
+		"text_hint":     &t.TextHint,
+		"text_bright":   &t.TextBright,
+		"highlight":     &t.Highlight,
+		"accent":        &t.Accent,
+		"success":       &t.Success,
+		"warning":       &t.Warning,
+		"danger":        &t.Danger,
+		"magic":         &t.Magic,
+		"debug":         &t.Debug,
+	}
+}
+
+// ParseHexColor parses "#RRGGBB" or "#RRGGBBAA"
+func ParseHexColor(hex string) (color.RGBA, error) {
+	digits := strings.TrimPrefix(hex, "#")
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	if len(digits) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", hex)
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", hex)
+	}
+	return color.RGBA{uint8(value >> 24), uint8(value >> 16), uint8(value >> 8), uint8(value)}, nil
+}
+
+// WithAlpha returns the color at another opacity, for fills that let more or less of the screen show through
+func WithAlpha(c color.RGBA, alpha uint8) color.RGBA {
+	c.A = alpha
+	return c
+}
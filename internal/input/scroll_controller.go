@@ -3,7 +3,7 @@ package input
 import (
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/shirou/tinygocha/internal/controls"
 	"github.com/shirou/tinygocha/internal/graphics"
 )
 
@@ -86,7 +86,12 @@ func (sc *ScrollController) Update(deltaTime float64) {
 
 // handleEdgeScrolling processes mouse edge scrolling
 func (sc *ScrollController) handleEdgeScrolling(deltaTime float64) {
-	mouseX, mouseY := ebiten.CursorPosition()
+	// The touch screen leaves the cursor where the finger lifted, which is no reason to scroll
+	if controls.IsTouch() {
+		return
+	}
+	
+	mouseX, mouseY := controls.CursorPosition()
 	screenWidth, screenHeight := ebiten.WindowSize()
 	
 	var scrollX, scrollY float64
@@ -128,7 +133,7 @@ func (sc *ScrollController) handleKeyboardScrolling(deltaTime float64) {
 	// Check if any movement keys are pressed
 	anyKeyPressed := false
 	for _, key := range keys {
-		if ebiten.IsKeyPressed(key) {
+		if controls.IsKeyPressed(key) {
 			anyKeyPressed = true
 			break
 		}
@@ -140,7 +145,7 @@ func (sc *ScrollController) handleKeyboardScrolling(deltaTime float64) {
 	
 	// Update key states
 	for _, key := range keys {
-		if ebiten.IsKeyPressed(key) {
+		if controls.IsKeyPressed(key) {
 			sc.keyStates[key] += deltaTime
 		} else {
 			sc.keyStates[key] = 0
@@ -182,18 +187,18 @@ func (sc *ScrollController) handleKeyboardScrolling(deltaTime float64) {
 // handleDragScrolling processes middle mouse button drag scrolling
 func (sc *ScrollController) handleDragScrolling() {
 	// Check for middle mouse button
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) {
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) {
 		sc.isDragging = true
-		sc.dragStartX, sc.dragStartY = ebiten.CursorPosition()
+		sc.dragStartX, sc.dragStartY = controls.CursorPosition()
 		sc.dragLastX, sc.dragLastY = sc.dragStartX, sc.dragStartY
 	}
 	
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonMiddle) {
+	if controls.IsMouseButtonJustReleased(ebiten.MouseButtonMiddle) {
 		sc.isDragging = false
 	}
 	
 	if sc.isDragging {
-		mouseX, mouseY := ebiten.CursorPosition()
+		mouseX, mouseY := controls.CursorPosition()
 		
 		// Calculate movement delta
 		deltaX := float64(sc.dragLastX - mouseX)
@@ -202,6 +207,9 @@ func (sc *ScrollController) handleDragScrolling() {
 		// Apply zoom factor and sensitivity multiplier for faster drag scrolling
 		zoomFactor := 1.0 / sc.camera.GetZoom()
 		sensitivity := 2.0 // 2倍の感度
+		if controls.IsTouch() {
+			sensitivity = 1.0 // 戦場が指についてくる
+		}
 		
 		if deltaX != 0 || deltaY != 0 {
 			sc.camera.Move(deltaX*zoomFactor*sensitivity, deltaY*zoomFactor*sensitivity)
@@ -213,25 +221,25 @@ func (sc *ScrollController) handleDragScrolling() {
 
 // handleZoom processes mouse wheel zoom
 func (sc *ScrollController) handleZoom() {
-	_, wheelY := ebiten.Wheel()
+	_, wheelY := controls.Wheel()
 	
 	if wheelY != 0 {
 		fmt.Printf("Mouse wheel detected: wheelY=%.2f\n", wheelY)
-		mouseX, mouseY := ebiten.CursorPosition()
+		mouseX, mouseY := controls.CursorPosition()
 		zoomDelta := wheelY * sc.ZoomStep
 		fmt.Printf("Applying zoom: delta=%.2f at (%d, %d)\n", zoomDelta, mouseX, mouseY)
 		sc.camera.ZoomAt(mouseX, mouseY, zoomDelta)
 	}
 	
 	// Handle keyboard zoom
-	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyKPAdd) {
+	if controls.IsKeyJustPressed(ebiten.KeyEqual) || controls.IsKeyJustPressed(ebiten.KeyKPAdd) {
 		fmt.Println("Zoom in key pressed")
 		// Zoom in at screen center
 		screenWidth, screenHeight := ebiten.WindowSize()
 		sc.camera.ZoomAt(screenWidth/2, screenHeight/2, sc.ZoomStep)
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyKPSubtract) {
+	if controls.IsKeyJustPressed(ebiten.KeyMinus) || controls.IsKeyJustPressed(ebiten.KeyKPSubtract) {
 		fmt.Println("Zoom out key pressed")
 		// Zoom out at screen center
 		screenWidth, screenHeight := ebiten.WindowSize()
@@ -279,7 +287,7 @@ func (sc *ScrollController) IsScrolling() bool {
 	}
 	
 	for _, key := range scrollKeys {
-		if ebiten.IsKeyPressed(key) {
+		if controls.IsKeyPressed(key) {
 			return true
 		}
 	}
@@ -291,7 +299,7 @@ func (sc *ScrollController) IsScrolling() bool {
 	
 	// Check edge scrolling
 	if sc.EdgeScrolling {
-		mouseX, mouseY := ebiten.CursorPosition()
+		mouseX, mouseY := controls.CursorPosition()
 		screenWidth, screenHeight := ebiten.WindowSize()
 		
 		if mouseX < sc.EdgeWidth || mouseX > screenWidth-sc.EdgeWidth ||
@@ -0,0 +1,168 @@
+package netplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LAN discovery ports
+const (
+	DefaultPort   = 7777 // 協力プレイの TCP ポート
+	DiscoveryPort = 7778 // ホストが告知を流す UDP ポート
+)
+
+// Discovery timing
+const (
+	beaconInterval = time.Second     // ホストが告知を流す間隔
+	hostTimeout    = 3 * time.Second // 告知が途絶えたホストを一覧から外すまでの時間
+)
+
+// Beacon is what a host broadcasts on the LAN while its lobby is open
+type Beacon struct {
+	Version  int    `json:"version"`
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Settings Setup  `json:"settings"`
+	Full     bool   `json:"full"` // 相方が決まっている
+}
+
+// HostInfo is a host found on the LAN
+type HostInfo struct {
+	Beacon
+	Address string // 接続先（IP:ポート）
+	
+	seen time.Time
+}
+
+// HostName returns a name for this machine to show in the lobby list
+func HostName() string {
+	if name, err := os.Hostname(); err == nil && name != "" {
+		return name
+	}
+	return "ホスト"
+}
+
+// Advertiser broadcasts a host's beacon until closed
+type Advertiser struct {
+	conn *net.UDPConn
+	stop chan struct{}
+	
+	mu     sync.Mutex
+	beacon Beacon
+}
+
+// Advertise starts broadcasting the beacon on the LAN
+func Advertise(beacon Beacon) (*Advertiser, error) {
+	conn, err := net.DialUDP("udp4", nil, &net.UDPAddr{IP: net.IPv4bcast, Port: DiscoveryPort})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open broadcast socket: %w", err)
+	}
+	
+	beacon.Version = ProtocolVersion
+	a := &Advertiser{conn: conn, stop: make(chan struct{}), beacon: beacon}
+	go a.run()
+	return a, nil
+}
+
+// run sends the beacon every interval
+func (a *Advertiser) run() {
+	ticker := time.NewTicker(beaconInterval)
+	defer ticker.Stop()
+	for {
+		a.mu.Lock()
+		packet, err := json.Marshal(a.beacon)
+		a.mu.Unlock()
+		if err == nil {
+			a.conn.Write(packet)
+		}
+		
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Update changes the broadcast beacon, e.g. after the host changed its settings
+func (a *Advertiser) Update(beacon Beacon) {
+	beacon.Version = ProtocolVersion
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.beacon = beacon
+}
+
+// Close stops broadcasting
+func (a *Advertiser) Close() error {
+	close(a.stop)
+	return a.conn.Close()
+}
+
+// Browser collects the beacons of hosts on the LAN
+type Browser struct {
+	conn *net.UDPConn
+	
+	mu    sync.Mutex
+	hosts map[string]HostInfo
+}
+
+// Browse starts listening for host beacons
+func Browse() (*Browser, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: DiscoveryPort})
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for hosts on port %d: %w", DiscoveryPort, err)
+	}
+	
+	b := &Browser{conn: conn, hosts: make(map[string]HostInfo)}
+	go b.run()
+	return b, nil
+}
+
+// run reads beacons until the browser is closed
+func (b *Browser) run() {
+	buffer := make([]byte, 4096)
+	for {
+		n, from, err := b.conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+		
+		var beacon Beacon
+		if json.Unmarshal(buffer[:n], &beacon) != nil || beacon.Version != ProtocolVersion || beacon.Port <= 0 {
+			continue
+		}
+		address := net.JoinHostPort(from.IP.String(), fmt.Sprint(beacon.Port))
+		b.mu.Lock()
+		b.hosts[address] = HostInfo{Beacon: beacon, Address: address, seen: time.Now()}
+		b.mu.Unlock()
+	}
+}
+
+// Hosts returns the hosts heard from recently, ordered by address
+func (b *Browser) Hosts() []HostInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	hosts := make([]HostInfo, 0, len(b.hosts))
+	for address, host := range b.hosts {
+		if time.Since(host.seen) > hostTimeout {
+			delete(b.hosts, address)
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Address < hosts[j].Address
+	})
+	return hosts
+}
+
+// Close stops listening
+func (b *Browser) Close() error {
+	return b.conn.Close()
+}
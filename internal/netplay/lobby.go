@@ -0,0 +1,97 @@
+package netplay
+
+import (
+	"fmt"
+)
+
+// receiveLobby stores the lobby messages of the partner
+func (s *Session) receiveLobby(msg message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	switch msg.Type {
+	case "settings":
+		if msg.Setup != nil {
+			settings := *msg.Setup
+			s.settings = &settings
+			s.revision = msg.Revision
+		}
+	case "ready":
+		s.partnerReady = 0
+		if msg.Ready {
+			s.partnerReady = msg.Revision
+		}
+	case "start":
+		s.started = true
+	}
+}
+
+// SendSettings shows the host's battle settings to the guest; the guest has to get ready again
+func (s *Session) SendSettings(settings Setup) error {
+	s.mu.Lock()
+	s.revision++
+	s.settings = &settings
+	revision := s.revision
+	s.mu.Unlock()
+	
+	if err := s.send(message{Type: "settings", Setup: &settings, Revision: revision}); err != nil {
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+// Settings returns the latest host settings and their revision, false before any arrived
+func (s *Session) Settings() (Setup, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.settings == nil {
+		return Setup{}, 0, false
+	}
+	return *s.settings, s.revision, true
+}
+
+// SetReady tells the partner whether this player is ready with the current settings
+func (s *Session) SetReady(ready bool) error {
+	s.mu.Lock()
+	revision := s.revision
+	s.mu.Unlock()
+	
+	if err := s.send(message{Type: "ready", Ready: ready, Revision: revision}); err != nil {
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+// PartnerReady reports whether the partner is ready with the current settings
+func (s *Session) PartnerReady() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revision > 0 && s.partnerReady == s.revision
+}
+
+// SendStart tells the guest that both players are ready and the battle begins; call it on the host only
+func (s *Session) SendStart() error {
+	s.mu.Lock()
+	ready := s.revision > 0 && s.partnerReady == s.revision
+	s.mu.Unlock()
+	if !ready {
+		return fmt.Errorf("partner is not ready")
+	}
+	
+	if err := s.send(message{Type: "start"}); err != nil {
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+// Started reports once that the host started the battle; call it on the guest only
+func (s *Session) Started() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	started := s.started
+	s.started = false
+	return started
+}
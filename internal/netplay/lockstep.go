@@ -0,0 +1,162 @@
+package netplay
+
+import (
+	"fmt"
+
+	"github.com/shirou/tinygocha/internal/game"
+)
+
+// Lockstep tuning
+const (
+	InputDelay = 6        // 命令を実行するまでの遅延（tick、60tick/秒で0.1秒）
+	TickDelta  = 1.0 / 60 // 1tickで進める戦闘時間（両者で同じ値を使う）
+	
+	HashInterval       = 60 // 状態ハッシュを相方と照合する間隔（tick、1秒）
+	MaxCommandsPerTick = 8  // 1人が1tickに送れる命令の数
+)
+
+// Command is one player input shared with the partner and applied on both sides in the same tick
+type Command struct {
+	Order  *game.Order `json:"order,omitempty"`
+	Assign *Assignment `json:"assign,omitempty"`
+	
+	// Player is the player who sent the command, stamped on arrival rather than trusted from the wire
+	Player int `json:"-"`
+}
+
+// Assignment hands command of a group to a player
+type Assignment struct {
+	ArmyID  int `json:"army_id"`
+	GroupID int `json:"group_id"`
+	Player  int `json:"player"`
+}
+
+// Lockstep advances a battle only when both players' commands for the tick are known
+// Both sides run the same deterministic simulation from the same seed, so only commands travel
+type Lockstep struct {
+	session *Session
+	tick    int
+	sent    bool              // 現在の tick の命令を送信済み
+	pending []Command         // 次に送る自分の命令
+	local   map[int][]Command // 送信済みで実行待ちの自分の命令
+	hashes  map[int]uint64    // 相方との照合待ちの自分の状態ハッシュ
+}
+
+// NewLockstep starts a battle at tick 0
+func NewLockstep(session *Session) *Lockstep {
+	return &Lockstep{
+		session: session,
+		local:   make(map[int][]Command),
+		hashes:  make(map[int]uint64),
+	}
+}
+
+// Queue adds a local command to be sent with the next tick
+// It returns false once the tick holds as many commands as the partner accepts
+func (l *Lockstep) Queue(command Command) bool {
+	if len(l.pending) >= MaxCommandsPerTick {
+		return false
+	}
+	l.pending = append(l.pending, command)
+	return true
+}
+
+// PendingCost returns the command points the orders waiting for the next tick will spend
+func (l *Lockstep) PendingCost() float64 {
+	cost := 0.0
+	for _, command := range l.pending {
+		if command.Order != nil {
+			cost += game.GetOrderCost(*command.Order)
+		}
+	}
+	return cost
+}
+
+// Tick returns the next tick to simulate
+func (l *Lockstep) Tick() int {
+	return l.tick
+}
+
+// Advance returns the commands of both players for the next tick, host first
+// It returns false while the partner's commands have not arrived yet; call it again next frame
+func (l *Lockstep) Advance() ([]Command, bool) {
+	// Local commands run InputDelay ticks later, giving them time to reach the partner
+	if !l.sent {
+		scheduled := l.tick + InputDelay
+		if err := l.session.sendTick(scheduled, l.pending); err != nil {
+			return nil, false
+		}
+		l.local[scheduled] = l.pending
+		l.pending = nil
+		l.sent = true
+	}
+	
+	var remote []Command
+	if l.tick >= InputDelay {
+		commands, ok := l.session.takeTick(l.tick)
+		if !ok {
+			return nil, false
+		}
+		remote = commands
+	}
+	
+	local := l.local[l.tick]
+	delete(l.local, l.tick)
+	l.tick++
+	l.sent = false
+	
+	// Commands carry the player they came from, whatever they claim
+	for i := range local {
+		local[i].Player = l.session.Player
+	}
+	for i := range remote {
+		remote[i].Player = l.Partner()
+	}
+	
+	if l.session.Player == PlayerHost {
+		return append(local, remote...), true
+	}
+	return append(remote, local...), true
+}
+
+// Verify sends the state hash of the tick just simulated every HashInterval ticks
+// and compares the partner's hashes as they arrive; an error means the two battles diverged
+func (l *Lockstep) Verify(stateHash func() uint64) error {
+	if tick := l.tick - 1; tick%HashInterval == 0 {
+		hash := stateHash()
+		l.hashes[tick] = hash
+		if err := l.session.sendHash(tick, hash); err != nil {
+			return nil // 接続エラーは Err で報告する
+		}
+	}
+	
+	for tick, hash := range l.hashes {
+		remote, ok := l.session.takeHash(tick)
+		if !ok {
+			continue
+		}
+		delete(l.hashes, tick)
+		if remote != hash {
+			return fmt.Errorf("battle state differs from the partner's at tick %d", tick)
+		}
+	}
+	return nil
+}
+
+// Err returns the connection error, or nil while the partner is connected
+func (l *Lockstep) Err() error {
+	return l.session.Err()
+}
+
+// Player returns this player's number
+func (l *Lockstep) Player() int {
+	return l.session.Player
+}
+
+// Partner returns the other player's number
+func (l *Lockstep) Partner() int {
+	if l.session.Player == PlayerHost {
+		return PlayerGuest
+	}
+	return PlayerHost
+}
@@ -0,0 +1,296 @@
+package netplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ObserverPort is the TCP port a co-op host streams its battles on
+const ObserverPort = 7779
+
+// Observer stream timing
+const (
+	streamInterval = 50 * time.Millisecond // 観戦者へ溜まった tick を送る間隔
+	observeTimeout = 10 * time.Second      // 観戦者の書き込みが詰まったら切断するまでの時間
+)
+
+// Broadcaster streams the host's battles to any number of observers
+// Observers get the setup and every tick's accepted commands, and replay the battle themselves;
+// the stream lags the battle by a fixed delay so observers cannot pass on what they see to a player
+type Broadcaster struct {
+	listener net.Listener
+	delay    int // 遅延（tick）
+	
+	mu        sync.Mutex
+	battle    int
+	setup     *Setup      // 配信中の戦闘（nil: まだない）
+	ticks     [][]Command // tick ごとの命令（添字が tick）
+	finished  bool        // 戦闘が終わり、遅延なしで最後まで送ってよい
+	observers map[net.Conn]bool
+	closed    bool
+}
+
+// Broadcast starts accepting observers on the address, streaming with the given delay in ticks
+func Broadcast(address string, delay int) (*Broadcaster, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for observers on %s: %w", address, err)
+	}
+	
+	b := &Broadcaster{
+		listener:  listener,
+		delay:     max(delay, 0),
+		observers: make(map[net.Conn]bool),
+	}
+	go b.acceptLoop()
+	fmt.Printf("Co-op: streaming to observers on %s (%d ticks behind)\n", listener.Addr(), b.delay)
+	return b, nil
+}
+
+// acceptLoop greets each observer and starts streaming to it
+func (b *Broadcaster) acceptLoop() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.serve(conn)
+	}
+}
+
+// serve streams the current battle to one observer until it disconnects
+func (b *Broadcaster) serve(conn net.Conn) {
+	defer conn.Close()
+	
+	encoder := json.NewEncoder(conn)
+	decoder := json.NewDecoder(conn)
+	if err := encoder.Encode(message{Type: "hello", Version: ProtocolVersion}); err != nil {
+		return
+	}
+	var hello message
+	if err := decoder.Decode(&hello); err != nil || hello.Type != "hello" || hello.Version != ProtocolVersion {
+		return
+	}
+	
+	b.mu.Lock()
+	b.observers[conn] = true
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.observers, conn)
+		b.mu.Unlock()
+	}()
+	fmt.Printf("Co-op: observer %s joined\n", conn.RemoteAddr())
+	
+	battle, sent := 0, 0
+	for {
+		messages, closed := b.pending(&battle, &sent)
+		if closed {
+			return
+		}
+		
+		conn.SetWriteDeadline(time.Now().Add(observeTimeout))
+		for _, msg := range messages {
+			if err := encoder.Encode(msg); err != nil {
+				fmt.Printf("Co-op: observer %s left: %v\n", conn.RemoteAddr(), err)
+				return
+			}
+		}
+		time.Sleep(streamInterval)
+	}
+}
+
+// pending returns the messages an observer that has seen the battle up to sent ticks may get now
+func (b *Broadcaster) pending(battle, sent *int) ([]message, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, true
+	}
+	if b.setup == nil {
+		return nil, false
+	}
+	
+	var messages []message
+	if *battle != b.battle {
+		setup := *b.setup
+		messages = append(messages, message{Type: "setup", Setup: &setup})
+		*battle, *sent = b.battle, 0
+	}
+	
+	visible := len(b.ticks)
+	if !b.finished {
+		visible -= b.delay
+	}
+	for ; *sent < visible; *sent++ {
+		messages = append(messages, message{Type: "tick", Battle: b.setup.Battle, Tick: *sent, Commands: b.ticks[*sent]})
+	}
+	return messages, false
+}
+
+// StartBattle begins streaming a new battle
+func (b *Broadcaster) StartBattle(setup Setup) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.battle++
+	b.setup = &setup
+	b.ticks = nil
+	b.finished = false
+}
+
+// Record appends the commands applied in the next tick of the battle
+func (b *Broadcaster) Record(commands []Command) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ticks = append(b.ticks, commands)
+}
+
+// Finish lifts the delay once the battle is over, so observers see its end
+func (b *Broadcaster) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.finished = true
+}
+
+// Observers returns the number of connected observers
+func (b *Broadcaster) Observers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.observers)
+}
+
+// Close stops accepting observers and disconnects them
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	return b.listener.Close()
+}
+
+// Watcher receives a host's battle stream as an observer
+type Watcher struct {
+	conn   net.Conn
+	setups chan Setup
+	
+	mu      sync.Mutex
+	battle  int
+	batches map[int][]Command // 届いた tick ごとの命令
+	next    int               // 次に進める tick
+	err     error
+}
+
+// Watch connects to a host's observer port
+func Watch(address string) (*Watcher, error) {
+	conn, err := net.DialTimeout("tcp", address, joinTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	
+	encoder := json.NewEncoder(conn)
+	decoder := json.NewDecoder(conn)
+	if err := encoder.Encode(message{Type: "hello", Version: ProtocolVersion}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send hello: %w", err)
+	}
+	var hello message
+	if err := decoder.Decode(&hello); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read host hello: %w", err)
+	}
+	if hello.Type != "hello" || hello.Version != ProtocolVersion {
+		conn.Close()
+		return nil, fmt.Errorf("host speaks protocol version %d, expected %d", hello.Version, ProtocolVersion)
+	}
+	
+	w := &Watcher{
+		conn:    conn,
+		setups:  make(chan Setup, 1),
+		batches: make(map[int][]Command),
+	}
+	fmt.Printf("Co-op: observing %s\n", conn.RemoteAddr())
+	go w.readLoop(decoder)
+	return w, nil
+}
+
+// readLoop stores the streamed setups and ticks until the connection closes
+func (w *Watcher) readLoop(decoder *json.Decoder) {
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			w.mu.Lock()
+			if w.err == nil {
+				w.err = fmt.Errorf("connection to host lost: %w", err)
+			}
+			w.mu.Unlock()
+			return
+		}
+		
+		switch msg.Type {
+		case "setup":
+			if msg.Setup == nil {
+				continue
+			}
+			w.mu.Lock()
+			w.battle = msg.Setup.Battle
+			w.batches = make(map[int][]Command)
+			w.next = 0
+			w.mu.Unlock()
+			select {
+			case <-w.setups:
+			default:
+			}
+			w.setups <- *msg.Setup
+		case "tick":
+			w.mu.Lock()
+			if msg.Battle == w.battle {
+				w.batches[msg.Tick] = msg.Commands
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// WaitBattle blocks until the host streams a battle
+func (w *Watcher) WaitBattle() (Setup, error) {
+	select {
+	case setup := <-w.setups:
+		return setup, nil
+	case <-time.After(setupTimeout):
+		return Setup{}, fmt.Errorf("host did not stream a battle within %s", setupTimeout)
+	}
+}
+
+// Next returns the commands of the next tick to replay, false until it arrives
+func (w *Watcher) Next() ([]Command, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	commands, ok := w.batches[w.next]
+	if !ok {
+		return nil, false
+	}
+	delete(w.batches, w.next)
+	w.next++
+	return commands, true
+}
+
+// Buffered returns how many streamed ticks are waiting to be replayed
+func (w *Watcher) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.batches)
+}
+
+// Err returns the connection error, or nil while the stream is up
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Close disconnects from the host
+func (w *Watcher) Close() error {
+	return w.conn.Close()
+}
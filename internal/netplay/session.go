@@ -0,0 +1,336 @@
+package netplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/shirou/tinygocha/internal/game"
+)
+
+// Co-op players; the host is always player 1
+const (
+	PlayerHost  = 1
+	PlayerGuest = 2
+	Players     = 2
+)
+
+// ProtocolVersion is checked when the partner connects
+const ProtocolVersion = 2
+
+// setupTimeout is how long the guest waits for the host to start a battle
+const setupTimeout = 60 * time.Second
+
+// joinTimeout is how long joining waits for the host to answer
+const joinTimeout = 5 * time.Second
+
+// Setup is the battle both players fight, chosen by the host
+type Setup struct {
+	Battle        int             `json:"battle"` // 接続してから何戦目か
+	Seed          int64           `json:"seed"`
+	Stage         string          `json:"stage"`
+	Preset        string          `json:"preset"`
+	EnemyPreset   string          `json:"enemy_preset,omitempty"` // 空: 敵軍も Preset
+	Mutators      []string        `json:"mutators"`
+	Doctrines     []string        `json:"doctrines"`
+	Handicaps     []game.Handicap `json:"handicaps"`
+	CommandPoints bool            `json:"command_points"`
+	CommandDelay  bool            `json:"command_delay,omitempty"`
+}
+
+// message is one line of the protocol
+type message struct {
+	Type     string    `json:"type"` // "hello", "settings", "ready", "start", "setup", "tick", "hash"
+	Version  int       `json:"version,omitempty"`
+	Setup    *Setup    `json:"setup,omitempty"`
+	Revision int       `json:"revision,omitempty"`
+	Ready    bool      `json:"ready,omitempty"`
+	Battle   int       `json:"battle,omitempty"`
+	Tick     int       `json:"tick,omitempty"`
+	Commands []Command `json:"commands,omitempty"`
+	Hash     uint64    `json:"hash,omitempty"`
+}
+
+// Session is the connection between the two co-op players
+type Session struct {
+	Player int // このプレイヤーの番号（PlayerHost / PlayerGuest）
+	
+	conn    net.Conn
+	encoder *json.Encoder
+	setups  chan Setup
+	
+	mu      sync.Mutex
+	battle  int               // 進行中の戦闘
+	batches map[int][]Command // 相方から届いた tick ごとの命令
+	arrived map[int]bool      // 命令が届いた tick（空の命令も含む）
+	hashes  map[int]uint64    // 相方から届いた tick ごとの状態ハッシュ
+	err     error
+	
+	// Lobby state (see lobby.go)
+	settings     *Setup // ホストのロビー設定（nil: まだない）
+	revision     int    // settings の版（ホストが変更するたびに増える）
+	partnerReady int    // 相方が準備完了した設定の版（0: 準備中）
+	started      bool   // ホストが開始を告げた（ゲストのみ）
+}
+
+// Host waits for a partner to connect on the address and becomes player 1
+func Host(address string) (*Session, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	defer listener.Close()
+	
+	fmt.Printf("Co-op: waiting for a partner on %s\n", listener.Addr())
+	conn, err := listener.Accept()
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept partner: %w", err)
+	}
+	return newSession(conn, PlayerHost)
+}
+
+// Listener waits for a partner in the background so the lobby keeps running
+type Listener struct {
+	listener net.Listener
+	
+	mu      sync.Mutex
+	session *Session
+	err     error
+}
+
+// Listen starts accepting one partner on the address
+func Listen(address string) (*Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	
+	l := &Listener{listener: listener}
+	go func() {
+		conn, err := listener.Accept()
+		var session *Session
+		if err == nil {
+			session, err = newSession(conn, PlayerHost)
+		}
+		l.mu.Lock()
+		l.session, l.err = session, err
+		l.mu.Unlock()
+		listener.Close()
+	}()
+	return l, nil
+}
+
+// Accept returns the connected partner, or nil while still waiting
+func (l *Listener) Accept() (*Session, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	session := l.session
+	l.session = nil
+	return session, l.err
+}
+
+// Port returns the TCP port partners connect to
+func (l *Listener) Port() int {
+	if addr, ok := l.listener.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// Close stops waiting for a partner
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
+
+// Join connects to a hosting partner and becomes player 2
+func Join(address string) (*Session, error) {
+	conn, err := net.DialTimeout("tcp", address, joinTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return newSession(conn, PlayerGuest)
+}
+
+// newSession exchanges protocol versions and starts reading the partner's messages
+func newSession(conn net.Conn, player int) (*Session, error) {
+	s := &Session{
+		Player:  player,
+		conn:    conn,
+		encoder: json.NewEncoder(conn),
+		setups:  make(chan Setup, 1),
+		batches: make(map[int][]Command),
+		arrived: make(map[int]bool),
+		hashes:  make(map[int]uint64),
+	}
+	
+	decoder := json.NewDecoder(conn)
+	if err := s.send(message{Type: "hello", Version: ProtocolVersion}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	var hello message
+	if err := decoder.Decode(&hello); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read partner hello: %w", err)
+	}
+	if hello.Type != "hello" || hello.Version != ProtocolVersion {
+		conn.Close()
+		return nil, fmt.Errorf("partner speaks protocol version %d, expected %d", hello.Version, ProtocolVersion)
+	}
+	
+	fmt.Printf("Co-op: connected to %s as player %d\n", conn.RemoteAddr(), player)
+	go s.readLoop(decoder)
+	return s, nil
+}
+
+// send writes one message to the partner
+func (s *Session) send(msg message) error {
+	if err := s.encoder.Encode(msg); err != nil {
+		return fmt.Errorf("failed to send %s: %w", msg.Type, err)
+	}
+	return nil
+}
+
+// readLoop stores the partner's setups and tick batches until the connection closes
+func (s *Session) readLoop(decoder *json.Decoder) {
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			s.fail(fmt.Errorf("connection to partner lost: %w", err))
+			return
+		}
+		
+		switch msg.Type {
+		case "settings", "ready", "start":
+			s.receiveLobby(msg)
+		case "setup":
+			if msg.Setup != nil {
+				s.beginBattle(msg.Setup.Battle)
+				// Only the latest setup matters
+				select {
+				case <-s.setups:
+				default:
+				}
+				s.setups <- *msg.Setup
+			}
+		case "tick":
+			s.mu.Lock()
+			if msg.Battle == s.battle {
+				s.batches[msg.Tick] = msg.Commands
+				s.arrived[msg.Tick] = true
+			}
+			s.mu.Unlock()
+		case "hash":
+			s.mu.Lock()
+			if msg.Battle == s.battle {
+				s.hashes[msg.Tick] = msg.Hash
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// fail records the first connection error
+func (s *Session) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the connection error, or nil while the partner is connected
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// beginBattle drops the batches of the previous battle
+func (s *Session) beginBattle(battle int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.battle = battle
+	s.batches = make(map[int][]Command)
+	s.arrived = make(map[int]bool)
+	s.hashes = make(map[int]uint64)
+}
+
+// StartBattle sends the host's battle to the guest; call it on the host only
+func (s *Session) StartBattle(setup Setup) (Setup, error) {
+	s.mu.Lock()
+	setup.Battle = s.battle + 1
+	s.mu.Unlock()
+	
+	s.beginBattle(setup.Battle)
+	if err := s.send(message{Type: "setup", Setup: &setup}); err != nil {
+		s.fail(err)
+		return setup, err
+	}
+	return setup, nil
+}
+
+// WaitBattle blocks until the host starts a battle; call it on the guest only
+func (s *Session) WaitBattle() (Setup, error) {
+	select {
+	case setup := <-s.setups:
+		return setup, nil
+	case <-time.After(setupTimeout):
+		return Setup{}, fmt.Errorf("host did not start a battle within %s", setupTimeout)
+	}
+}
+
+// sendTick sends this player's commands for a tick of the current battle
+func (s *Session) sendTick(tick int, commands []Command) error {
+	s.mu.Lock()
+	battle := s.battle
+	s.mu.Unlock()
+	
+	if err := s.send(message{Type: "tick", Battle: battle, Tick: tick, Commands: commands}); err != nil {
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+// takeTick returns and forgets the partner's commands for a tick, false until they arrive
+func (s *Session) takeTick(tick int) ([]Command, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.arrived[tick] {
+		return nil, false
+	}
+	commands := s.batches[tick]
+	delete(s.batches, tick)
+	delete(s.arrived, tick)
+	return commands, true
+}
+
+// sendHash sends this player's state hash for a tick of the current battle
+func (s *Session) sendHash(tick int, hash uint64) error {
+	s.mu.Lock()
+	battle := s.battle
+	s.mu.Unlock()
+	
+	if err := s.send(message{Type: "hash", Battle: battle, Tick: tick, Hash: hash}); err != nil {
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+// takeHash returns and forgets the partner's state hash for a tick, false until it arrives
+func (s *Session) takeHash(tick int) (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hash, ok := s.hashes[tick]
+	delete(s.hashes, tick)
+	return hash, ok
+}
+
+// Close disconnects from the partner
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,58 @@
+package netplay
+
+import (
+	"github.com/shirou/tinygocha/internal/game"
+)
+
+// Rejection is a command CheckCommands refused, with the reason
+type Rejection struct {
+	Player int
+	Reason string
+}
+
+// CheckCommands drops the commands of a tick that their player could not have issued:
+// orders for another army or for the partner's groups, floods of commands and orders past the budget
+// Both sides check the same commands against the same state, so both drop the same ones
+func CheckCommands(bm *game.BattleManager, armyID int, commands []Command) ([]Command, []Rejection) {
+	var accepted []Command
+	var rejected []Rejection
+	counts := make(map[int]int)
+	costs := make(map[int]float64)
+	
+	for _, command := range commands {
+		player := command.Player
+		reason := ""
+		counts[player]++
+		
+		switch {
+		case counts[player] > MaxCommandsPerTick:
+			reason = "too many commands in one tick"
+		case command.Order != nil:
+			order := *command.Order
+			costs[player] += game.GetOrderCost(order)
+			if order.ArmyID != armyID {
+				reason = "order for another army"
+			} else if err := bm.ValidateOrder(order, player); err != nil {
+				reason = err.Error()
+			} else if bm.CommandPoints != nil && costs[player] > bm.CommandPoints.Max {
+				reason = "orders in one tick cost more than the whole budget"
+			}
+		case command.Assign != nil:
+			assign := command.Assign
+			if assign.ArmyID != armyID {
+				reason = "assignment in another army"
+			} else if err := bm.ValidateAssignment(assign.ArmyID, assign.GroupID, player, assign.Player, Players); err != nil {
+				reason = err.Error()
+			}
+		default:
+			reason = "empty command"
+		}
+		
+		if reason != "" {
+			rejected = append(rejected, Rejection{Player: player, Reason: reason})
+			continue
+		}
+		accepted = append(accepted, command)
+	}
+	return accepted, rejected
+}
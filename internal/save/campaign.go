@@ -0,0 +1,93 @@
+package save
+
+// CampaignVersion is the current campaign format
+// Version 2 added the overworld map, version 3 its gold and the groups of each army, version 4 the event deck,
+// version 5 the leaders' equipment, version 6 the seed of the map's random draws
+const CampaignVersion = 6
+
+// Campaign holds the player's progress through the stages
+type Campaign struct {
+	Kind    Kind `toml:"kind"`
+	Version int  `toml:"version"`
+	
+	ClearedStages []string `toml:"cleared_stages"` // 勝利したステージ（ステージ設定ID）
+	LastStage     string   `toml:"last_stage"`     // 最後に遊んだステージ
+	LastPreset    string   `toml:"last_preset"`    // 最後に選んだ編成
+	
+	// Overworld map; a zero turn means the map has not been started
+	Turn      int               `toml:"turn"`      // 戦略マップの現在のターン
+	Provinces map[string]string `toml:"provinces"` // 地方ごとの支配勢力（"player"/"enemy"）
+	Armies    []CampaignArmy    `toml:"armies"`    // 戦略マップ上の軍勢
+	Gold      map[string]int    `toml:"gold"`      // 勢力ごとの軍資金
+	Deck      []string          `toml:"deck"`      // イベントの山札の残り（上から引く）
+	Event     string            `toml:"event"`     // 選択を待っているイベント（空: なし）
+	Seed      int64             `toml:"seed"`      // 山札の切り直しと反乱の乱数の種
+}
+
+// CampaignArmy is an army on the overworld map
+type CampaignArmy struct {
+	ID       string `toml:"id"`
+	Name     string `toml:"name"`
+	Side     string `toml:"side"`
+	Preset   string `toml:"preset"`
+	Province string `toml:"province"`
+	From     string `toml:"from"`  // このターンに移動してくる前の地方
+	Moved    bool   `toml:"moved"` // このターンは移動済み
+	
+	Groups []CampaignGroup `toml:"groups"` // 合戦に出る部隊
+}
+
+// CampaignGroup is one group of an army on the overworld map
+type CampaignGroup struct {
+	Leader string `toml:"leader"`
+	Member string `toml:"member"`
+	Count  int    `toml:"count"`
+	
+	// Leader's equipment by item ID (empty: nothing in the slot)
+	Weapon string `toml:"weapon"`
+	Armor  string `toml:"armor"`
+	Banner string `toml:"banner"`
+}
+
+// NewCampaign creates a campaign with no progress
+func NewCampaign() *Campaign {
+	return &Campaign{
+		Kind:    KindCampaign,
+		Version: CampaignVersion,
+	}
+}
+
+// LoadCampaign loads the campaign, returning a new one if the file does not exist
+func LoadCampaign(filename string) (*Campaign, error) {
+	campaign := NewCampaign()
+	if err := load(filename, KindCampaign, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// Save writes the campaign in the current format
+func (c *Campaign) Save(filename string) error {
+	c.Kind = KindCampaign
+	c.Version = CampaignVersion
+	return write(filename, c)
+}
+
+// IsCleared reports whether the stage has been won
+func (c *Campaign) IsCleared(stageID string) bool {
+	for _, cleared := range c.ClearedStages {
+		if cleared == stageID {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordBattle updates the progress after a finished battle
+func (c *Campaign) RecordBattle(stageID, preset, result string) {
+	c.LastStage = stageID
+	c.LastPreset = preset
+	if result == ResultWin && !c.IsCleared(stageID) {
+		c.ClearedStages = append(c.ClearedStages, stageID)
+	}
+}
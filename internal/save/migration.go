@@ -0,0 +1,114 @@
+package save
+
+import (
+	"fmt"
+)
+
+// Migration upgrades the decoded content of a save file by one version
+type Migration func(raw map[string]interface{}) error
+
+// currentVersions is the format version written for each kind of save file
+var currentVersions = map[Kind]int{
+	KindProfile:  ProfileVersion,
+	KindCampaign: CampaignVersion,
+	KindPreset:   PresetVersion,
+}
+
+// migrations[kind][v] upgrades a file of that kind from version v to v+1
+//
+// When a format changes, bump its version constant and register the step here:
+//
+//	KindProfile: {
+//		1: func(raw map[string]interface{}) error {
+//			raw["draws"] = int64(0)
+//			return nil
+//		},
+//	},
+var migrations = map[Kind]map[int]Migration{
+	KindProfile: {
+		// Version 2 added the custom army; players start it with the same gold as a new profile
+		1: func(raw map[string]interface{}) error {
+			raw["gold"] = int64(StartingGold)
+			return nil
+		},
+		// Version 3 added the record of each army on each stage, kept from the next battle on
+		2: func(raw map[string]interface{}) error {
+			return nil
+		},
+		// Version 4 added the challenges, which start with none attempted
+		3: func(raw map[string]interface{}) error {
+			return nil
+		},
+	},
+	KindCampaign: {
+		// Version 2 added the overworld map, which starts fresh
+		1: func(raw map[string]interface{}) error {
+			raw["turn"] = int64(0)
+			return nil
+		},
+		// Version 3 added gold and army groups, which the campaign map fills in from its config on load
+		2: func(raw map[string]interface{}) error {
+			return nil
+		},
+		// Version 4 added the event deck, which is shuffled when first drawn from
+		3: func(raw map[string]interface{}) error {
+			return nil
+		},
+		// Version 5 added the leaders' equipment; groups saved before carry nothing
+		4: func(raw map[string]interface{}) error {
+			return nil
+		},
+		// Version 6 added the seed of the map's random draws; maps saved before draw from seed 0,
+		// keeping the rest of their deck as it was saved
+		5: func(raw map[string]interface{}) error {
+			raw["seed"] = int64(0)
+			return nil
+		},
+	},
+}
+
+// CurrentVersion returns the format version written for a kind of save file
+func CurrentVersion(kind Kind) int {
+	return currentVersions[kind]
+}
+
+// migrate upgrades raw save data step by step to the current version of its kind
+func migrate(kind Kind, raw map[string]interface{}) error {
+	current, exists := currentVersions[kind]
+	if !exists {
+		return fmt.Errorf("unknown save file kind %q", kind)
+	}
+	
+	version, err := getVersion(raw)
+	if err != nil {
+		return err
+	}
+	if version > current {
+		return fmt.Errorf("version %d is newer than the supported version %d", version, current)
+	}
+	
+	for ; version < current; version++ {
+		migration, exists := migrations[kind][version]
+		if !exists {
+			return fmt.Errorf("no migration from version %d to %d", version, version+1)
+		}
+		if err := migration(raw); err != nil {
+			return fmt.Errorf("migration from version %d to %d: %w", version, version+1, err)
+		}
+	}
+	
+	raw[versionKey] = int64(current)
+	return nil
+}
+
+// getVersion reads the format version of raw save data
+func getVersion(raw map[string]interface{}) (int, error) {
+	switch version := raw[versionKey].(type) {
+	case int64:
+		return int(version), nil
+	case nil:
+		return 0, fmt.Errorf("missing %s", versionKey)
+	default:
+		return 0, fmt.Errorf("invalid %s %v", versionKey, version)
+	}
+}
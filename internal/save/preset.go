@@ -0,0 +1,114 @@
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PresetVersion is the current army preset format
+const PresetVersion = 1
+
+// presetFilePrefix starts the name of every preset file; the player's name for the preset is kept inside,
+// so renaming never touches the file system
+const presetFilePrefix = "preset_"
+
+// ArmyPreset is a custom army composition the player saved to pick again in the army setup
+type ArmyPreset struct {
+	Kind    Kind `toml:"kind"`
+	Version int  `toml:"version"`
+	
+	Name   string   `toml:"name"`
+	Groups []string `toml:"groups"` // 出陣する部隊の名前（recruitment.toml、雇った順）
+	
+	filename string // 読み込んだ・保存したファイル（空: まだ保存していない）
+}
+
+// NewArmyPreset creates a preset that is not saved yet
+func NewArmyPreset(name string, groups []string) *ArmyPreset {
+	return &ArmyPreset{
+		Kind:    KindPreset,
+		Version: PresetVersion,
+		Name:    name,
+		Groups:  slices.Clone(groups),
+	}
+}
+
+// LoadArmyPresets loads every preset in the directory in the order they were saved
+// Unreadable files are skipped with a warning, so one broken preset does not hide the rest
+func LoadArmyPresets(dir string) ([]*ArmyPreset, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	var presets []*ArmyPreset
+	for _, entry := range entries {
+		if entry.IsDir() || !isPresetFile(entry.Name()) {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		preset := NewArmyPreset("", nil)
+		if err := load(filename, KindPreset, preset); err != nil {
+			fmt.Printf("Warning: Skipping army preset: %v\n", err)
+			continue
+		}
+		preset.filename = filename
+		presets = append(presets, preset)
+	}
+	return presets, nil
+}
+
+// Save writes the preset in the current format, to a new numbered file in dir the first time
+func (ap *ArmyPreset) Save(dir string) error {
+	if ap.filename == "" {
+		filename, err := nextPresetFile(dir)
+		if err != nil {
+			return err
+		}
+		ap.filename = filename
+	}
+	ap.Kind = KindPreset
+	ap.Version = PresetVersion
+	return write(ap.filename, ap)
+}
+
+// Delete removes the preset's file
+func (ap *ArmyPreset) Delete() error {
+	if ap.filename == "" {
+		return nil
+	}
+	if err := os.Remove(ap.filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	ap.filename = ""
+	return nil
+}
+
+// isPresetFile reports whether the file name is one the presets are saved under
+func isPresetFile(name string) bool {
+	return strings.HasPrefix(name, presetFilePrefix) && filepath.Ext(name) == ".toml"
+}
+
+// nextPresetFile returns an unused preset file name in dir, numbered after the highest one there
+// Zero-padded numbers keep the directory listing in the order the presets were saved
+func nextPresetFile(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	
+	next := 1
+	for _, entry := range entries {
+		var number int
+		if _, err := fmt.Sscanf(entry.Name(), presetFilePrefix+"%d.toml", &number); err == nil && number >= next {
+			next = number + 1
+		}
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s%03d.toml", presetFilePrefix, next)), nil
+}
@@ -0,0 +1,209 @@
+package save
+
+import (
+	"slices"
+)
+
+// ProfileVersion is the current profile format
+// Version 2 added the gold and the groups hired for the custom army, version 3 the record of each army on each stage,
+// version 4 the progress on the challenges
+const ProfileVersion = 4
+
+// StartingGold is the gold a new profile starts with to hire its first groups
+const StartingGold = 300
+
+// Battle outcomes from the player's side
+const (
+	ResultWin  = "win"
+	ResultLoss = "loss"
+	ResultDraw = "draw"
+)
+
+// Profile holds the player's battle record across all stages
+type Profile struct {
+	Kind    Kind `toml:"kind"`
+	Version int  `toml:"version"`
+	
+	Battles int `toml:"battles"`
+	Wins    int `toml:"wins"`
+	Losses  int `toml:"losses"`
+	Draws   int `toml:"draws"`
+	
+	// Records per stage, keyed by stage config ID
+	Stages map[string]*StageRecord `toml:"stages"`
+	
+	// Custom army hired between battles
+	Gold   int           `toml:"gold"`   // 所持金
+	Roster []RosterGroup `toml:"roster"` // 雇った部隊（雇った順）
+	
+	// Progress per challenge, keyed by challenge ID
+	Challenges map[string]*ChallengeRecord `toml:"challenges"`
+}
+
+// RosterGroup is one group hired for the custom army
+type RosterGroup struct {
+	Recruit  string `toml:"recruit"` // 雇った部隊の名前（recruitment.toml）
+	Leader   string `toml:"leader"`
+	Member   string `toml:"member"`
+	Count    int    `toml:"count"`
+	Spent    int    `toml:"spent"`    // この部隊に使った金貨（解散したときの払い戻しの元）
+	Deployed bool   `toml:"deployed"` // 自軍編成で出陣する
+	
+	// Leader's equipment by item ID (empty: nothing in the slot)
+	Weapon string `toml:"weapon"`
+	Armor  string `toml:"armor"`
+	Banner string `toml:"banner"`
+}
+
+// StageRecord is the player's record on one stage
+type StageRecord struct {
+	Battles     int     `toml:"battles"`
+	Wins        int     `toml:"wins"`
+	BestWinTime float64 `toml:"best_win_time"` // 最短勝利時間（秒、0: 未勝利）
+	
+	// Records of the armies the player fought with, for suggesting one
+	Armies []ArmyRecord `toml:"armies"`
+}
+
+// ChallengeRecord is the player's progress on one challenge
+type ChallengeRecord struct {
+	Attempts int `toml:"attempts"`
+	Stars    int `toml:"stars"` // 最高の星の数（0: 未達成）
+}
+
+// ArmyRecord is the player's record with one army on a stage: a preset, or the custom army's groups
+type ArmyRecord struct {
+	Preset  string   `toml:"preset"`
+	Groups  []string `toml:"groups"` // 自軍編成で出陣した部隊の名前（雇った順）
+	Battles int      `toml:"battles"`
+	Wins    int      `toml:"wins"`
+}
+
+// WinRate returns the share of the battles won with the army
+func (ar *ArmyRecord) WinRate() float64 {
+	if ar.Battles == 0 {
+		return 0
+	}
+	return float64(ar.Wins) / float64(ar.Battles)
+}
+
+// score ranks the army for suggestions: the win rate with one win and one loss added,
+// so that a single lucky win does not outrank a long winning record
+func (ar *ArmyRecord) score() float64 {
+	return float64(ar.Wins+1) / float64(ar.Battles+2)
+}
+
+// getArmy returns the record of the army, adding an empty one the first time
+func (sr *StageRecord) getArmy(preset string, groups []string) *ArmyRecord {
+	for i := range sr.Armies {
+		if sr.Armies[i].Preset == preset && slices.Equal(sr.Armies[i].Groups, groups) {
+			return &sr.Armies[i]
+		}
+	}
+	sr.Armies = append(sr.Armies, ArmyRecord{Preset: preset, Groups: slices.Clone(groups)})
+	return &sr.Armies[len(sr.Armies)-1]
+}
+
+// NewProfile creates an empty profile
+func NewProfile() *Profile {
+	return &Profile{
+		Kind:       KindProfile,
+		Version:    ProfileVersion,
+		Stages:     make(map[string]*StageRecord),
+		Gold:       StartingGold,
+		Challenges: make(map[string]*ChallengeRecord),
+	}
+}
+
+// LoadProfile loads the profile, returning an empty one if the file does not exist
+func LoadProfile(filename string) (*Profile, error) {
+	profile := NewProfile()
+	if err := load(filename, KindProfile, profile); err != nil {
+		return nil, err
+	}
+	if profile.Stages == nil {
+		profile.Stages = make(map[string]*StageRecord)
+	}
+	if profile.Challenges == nil {
+		profile.Challenges = make(map[string]*ChallengeRecord)
+	}
+	return profile, nil
+}
+
+// Save writes the profile in the current format
+func (p *Profile) Save(filename string) error {
+	p.Kind = KindProfile
+	p.Version = ProfileVersion
+	return write(filename, p)
+}
+
+// RecordBattle adds a finished battle to the profile
+// The army is the preset fought with and, for the custom army, its groups; an empty preset is not recorded
+func (p *Profile) RecordBattle(stageID, preset string, groups []string, result string, battleTime float64) {
+	record := p.Stages[stageID]
+	if record == nil {
+		record = &StageRecord{}
+		p.Stages[stageID] = record
+	}
+	
+	p.Battles++
+	record.Battles++
+	
+	var army *ArmyRecord
+	if preset != "" {
+		army = record.getArmy(preset, groups)
+		army.Battles++
+	}
+	
+	switch result {
+	case ResultWin:
+		p.Wins++
+		record.Wins++
+		if army != nil {
+			army.Wins++
+		}
+		if record.BestWinTime == 0 || battleTime < record.BestWinTime {
+			record.BestWinTime = battleTime
+		}
+	case ResultLoss:
+		p.Losses++
+	default:
+		p.Draws++
+	}
+}
+
+// RecordChallenge adds an attempt at the challenge, keeping the most stars earned
+func (p *Profile) RecordChallenge(challengeID string, stars int) {
+	record := p.Challenges[challengeID]
+	if record == nil {
+		record = &ChallengeRecord{}
+		p.Challenges[challengeID] = record
+	}
+	record.Attempts++
+	record.Stars = max(record.Stars, stars)
+}
+
+// ChallengeStars returns the most stars earned on the challenge (0: not cleared yet)
+func (p *Profile) ChallengeStars(challengeID string) int {
+	if record := p.Challenges[challengeID]; record != nil {
+		return record.Stars
+	}
+	return 0
+}
+
+// SuggestArmy returns the army with the best record on the stage among those that have won there
+func (p *Profile) SuggestArmy(stageID string) (*ArmyRecord, bool) {
+	record := p.Stages[stageID]
+	if record == nil {
+		return nil, false
+	}
+	
+	var best *ArmyRecord
+	for i := range record.Armies {
+		army := &record.Armies[i]
+		if army.Wins > 0 && (best == nil || army.score() > best.score()) {
+			best = army
+		}
+	}
+	return best, best != nil
+}
@@ -0,0 +1,85 @@
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Default save file locations
+const (
+	SaveDir             = "save"
+	DefaultProfilePath  = "save/profile.toml"
+	DefaultCampaignPath = "save/campaign.toml"
+	DefaultPresetDir    = "save/presets"
+)
+
+// Kind identifies the format of a save file
+type Kind string
+
+const (
+	KindProfile  Kind = "profile"  // プレイヤーの戦績
+	KindCampaign Kind = "campaign" // ステージの進行状況
+	KindPreset   Kind = "preset"   // 保存した自軍編成
+)
+
+// Every save file starts with its kind and format version, so that
+// older files can be migrated when the format changes
+const (
+	kindKey    = "kind"
+	versionKey = "version"
+)
+
+// load reads a save file, migrates it to the current format and decodes it into out
+// A missing file leaves out unchanged
+func load(filename string, kind Kind, out interface{}) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	
+	var raw map[string]interface{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	
+	if fileKind, _ := raw[kindKey].(string); Kind(fileKind) != kind {
+		return fmt.Errorf("%s is a %q save file, expected %q", filename, fileKind, kind)
+	}
+	if err := migrate(kind, raw); err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", filename, err)
+	}
+	
+	// Decode the migrated data into the current structure
+	data, err = toml.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := toml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", filename, err)
+	}
+	return nil
+}
+
+// write saves data to a file, replacing it only once the new content is complete
+func write(filename string, data interface{}) error {
+	encoded, err := toml.Marshal(data)
+	if err != nil {
+		return err
+	}
+	
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, encoded, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
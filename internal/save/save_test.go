@@ -0,0 +1,210 @@
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFile writes a save file for a test and returns its path
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "save.toml")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadProfileMigratesOlderVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantGold int
+		wantWins int
+	}{
+		{
+			name:     "version 1 starts with the new profile's gold",
+			content:  "kind = 'profile'\nversion = 1\nbattles = 3\nwins = 2\n",
+			wantGold: StartingGold,
+			wantWins: 2,
+		},
+		{
+			name:     "version 2 keeps its gold",
+			content:  "kind = 'profile'\nversion = 2\nwins = 1\ngold = 120\n",
+			wantGold: 120,
+			wantWins: 1,
+		},
+		{
+			name:     "version 3 keeps its gold",
+			content:  "kind = 'profile'\nversion = 3\nwins = 4\ngold = 80\n",
+			wantGold: 80,
+			wantWins: 4,
+		},
+		{
+			name:     "current version loads as is",
+			content:  fmt.Sprintf("kind = 'profile'\nversion = %d\nwins = 5\ngold = 10\n", ProfileVersion),
+			wantGold: 10,
+			wantWins: 5,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := LoadProfile(writeFile(t, tt.content))
+			if err != nil {
+				t.Fatalf("LoadProfile() error = %v", err)
+			}
+			if profile.Version != ProfileVersion {
+				t.Errorf("Version = %d, want %d", profile.Version, ProfileVersion)
+			}
+			if profile.Gold != tt.wantGold {
+				t.Errorf("Gold = %d, want %d", profile.Gold, tt.wantGold)
+			}
+			if profile.Wins != tt.wantWins {
+				t.Errorf("Wins = %d, want %d", profile.Wins, tt.wantWins)
+			}
+			if profile.Stages == nil || profile.Challenges == nil {
+				t.Errorf("Stages and Challenges must not be nil")
+			}
+		})
+	}
+}
+
+func TestLoadCampaignMigratesOlderVersions(t *testing.T) {
+	for version := 1; version <= CampaignVersion; version++ {
+		t.Run(fmt.Sprintf("version %d", version), func(t *testing.T) {
+			content := fmt.Sprintf("kind = 'campaign'\nversion = %d\ncleared_stages = ['plains']\nturn = 7\n", version)
+			campaign, err := LoadCampaign(writeFile(t, content))
+			if err != nil {
+				t.Fatalf("LoadCampaign() error = %v", err)
+			}
+			if campaign.Version != CampaignVersion {
+				t.Errorf("Version = %d, want %d", campaign.Version, CampaignVersion)
+			}
+			if !campaign.IsCleared("plains") {
+				t.Errorf("cleared stages lost: %v", campaign.ClearedStages)
+			}
+			
+			// Version 1 had no overworld map, so its turn starts over
+			wantTurn := 7
+			if version == 1 {
+				wantTurn = 0
+			}
+			if campaign.Turn != wantTurn {
+				t.Errorf("Turn = %d, want %d", campaign.Turn, wantTurn)
+			}
+		})
+	}
+}
+
+func TestMigrateCoversEveryVersion(t *testing.T) {
+	for kind, current := range currentVersions {
+		for version := 1; version <= current; version++ {
+			raw := map[string]interface{}{kindKey: string(kind), versionKey: int64(version)}
+			if err := migrate(kind, raw); err != nil {
+				t.Errorf("migrate(%s, version %d) error = %v", kind, version, err)
+				continue
+			}
+			if raw[versionKey] != int64(current) {
+				t.Errorf("migrate(%s, version %d) left version %v, want %d", kind, version, raw[versionKey], current)
+			}
+		}
+	}
+}
+
+func TestLoadRejectsUnreadableFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "newer version",
+			content: fmt.Sprintf("kind = 'profile'\nversion = %d\n", ProfileVersion+1),
+			wantErr: "newer than the supported version",
+		},
+		{
+			name:    "other kind",
+			content: fmt.Sprintf("kind = 'campaign'\nversion = %d\n", CampaignVersion),
+			wantErr: `is a "campaign" save file`,
+		},
+		{
+			name:    "missing kind",
+			content: fmt.Sprintf("version = %d\n", ProfileVersion),
+			wantErr: `is a "" save file`,
+		},
+		{
+			name:    "missing version",
+			content: "kind = 'profile'\n",
+			wantErr: "missing version",
+		},
+		{
+			name:    "broken file",
+			content: "kind = \n",
+			wantErr: "failed to parse",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadProfile(writeFile(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadProfile() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFileReturnsDefaults(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+	
+	profile, err := LoadProfile(missing)
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if !reflect.DeepEqual(profile, NewProfile()) {
+		t.Errorf("LoadProfile() = %+v, want %+v", profile, NewProfile())
+	}
+	
+	campaign, err := LoadCampaign(missing)
+	if err != nil {
+		t.Fatalf("LoadCampaign() error = %v", err)
+	}
+	if !reflect.DeepEqual(campaign, NewCampaign()) {
+		t.Errorf("LoadCampaign() = %+v, want %+v", campaign, NewCampaign())
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	// The save directory is created on the first save
+	filename := filepath.Join(t.TempDir(), SaveDir, "profile.toml")
+	
+	profile := NewProfile()
+	profile.RecordBattle("plains", "custom", []string{"spearmen"}, ResultWin, 95.5)
+	profile.Roster = []RosterGroup{{Recruit: "spearmen", Leader: "infantry", Member: "infantry", Count: 8, Spent: 60, Deployed: true, Weapon: "iron_spear"}}
+	profile.Challenges["no_archers"] = &ChallengeRecord{Attempts: 2, Stars: 1}
+	
+	// Saving over an older file replaces it whole
+	for i := 0; i < 2; i++ {
+		if err := profile.Save(filename); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		profile.Gold -= 50
+	}
+	profile.Gold += 50
+	
+	if _, err := os.Stat(filename + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+	loaded, err := LoadProfile(filename)
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, profile) {
+		t.Errorf("LoadProfile() = %+v, want %+v", loaded, profile)
+	}
+}
@@ -1,177 +1,841 @@
 package scenes
 
 import (
-	"image/color"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
 	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// Stage preview layout
+const (
+	stagePreviewX    = 620
+	stagePreviewY    = 110
+	stagePreviewSize = 340
+)
+
+// stageInfoX is the screen X of the selected stage's difficulty, beside its name
+const stageInfoX = 280
+
+// Selectable items after the stage row (0)
+const (
+	playerPresetItem = 1 // 自軍のプリセット
+	enemyPresetItem  = 2 // 敵軍のプリセット
+	startItem        = 3 // 戦闘開始ボタン
+	backItem         = 4 // 戻るボタン
+	autoResolveItem  = 5 // 自動解決ボタン
+	recruitItem      = 6 // 部隊編成ボタン
+	suggestItem      = 7 // おすすめ編成ボタン
+	firstMutatorRow  = 8 // 特殊ルールの最初の行（ボタンの後ろ）
+)
+
+// Enemy preset row position, beside the player's
+const (
+	enemyPresetX = 400
+	enemyPresetY = 330
+)
+
+// setupButtons are the buttons from startItem on, in item order
+var setupButtons = []struct {
+	label string
+	x, y  float64
+}{
+	{"戦闘開始", 400, 500},
+	{"戻る", 550, 500},
+	{"自動解決", 400, 540},
+	{"部隊編成", 550, 540},
+	{"おすすめを使う", 400, 300},
+}
+
+// Terrain effects layout: columns of rows between the stage row and the suggestion
+const (
+	stageEffectRows     = 3
+	stageEffectColumn   = 220 // 列の間隔
+	maxStageEffectLines = stageEffectRows * 2
+)
+
+// suggestionY is the screen Y of the army suggested from the player's record on the stage
+const suggestionY = 262
+
+// userPresetPrefix marks the custom army compositions the player saved among the preset choices
+const userPresetPrefix = "★"
+
+// doctrineSides label the doctrine rows: the player's army and its enemies
+var doctrineSides = []string{"自軍", "敵軍"}
+
+// Doctrine rows layout
+const (
+	doctrineListY   = 450
+	doctrineRowStep = 20
+)
+
+// handicapStats label the handicap rows of each side, in Handicap field order
+var handicapStats = []string{"体力", "攻撃力", "兵数"}
+
+// handicapSteps are the multipliers a handicap row cycles through
+var handicapSteps = []float64{0.5, 0.75, 1.0, 1.25, 1.5, 2.0}
+
+// defaultHandicapStep is the index of the neutral multiplier in handicapSteps
+const defaultHandicapStep = 2
+
+// Handicap rows layout: one column per side
+const (
+	handicapListY   = 520
+	handicapColumnX = 130
+	handicapRowStep = 20
+)
+
+// Mutator list layout
+const (
+	mutatorListX   = 620
+	mutatorListY   = 490
+	mutatorRowStep = 22
 )
 
 // ArmySetupScene represents the army setup screen
 type ArmySetupScene struct {
-	sceneManager     *SceneManager
-	textRenderer     *graphics.TextRenderer
-	selectedItem     int
-	presetArmies     []string
-	selectedPreset   int
-	selectedStage    int
-	stages           []string
+	sceneManager      *SceneManager
+	dataManager       *data.DataManager
+	textRenderer      *graphics.TextRenderer
+	selectedItem      int
+	presetArmies      []string
+	selectedPreset    int
+	enemyPresets      []string // 敵軍のプリセットの選択肢（enemyPresetChoices）
+	selectedEnemy     int      // 敵軍のプリセット（enemyPresetsの添字、0: 自軍と同じ）
+	selectedStage     int
+	stages            []data.StageListing
+	mutators          []*game.Mutator
+	enabledMutators   map[string]bool
+	doctrineIDs       []string
+	selectedDoctrines []int                           // 陣営ごとのドクトリン（0: なし、i: doctrineIDs[i-1]）
+	selectedHandicaps []int                           // 陣営ごと・能力ごとのhandicapStepsの添字（side*len(handicapStats)+stat）
+	seed              int64                           // 設定コードで共有する戦闘の乱数シード（0: 毎回ランダム）
+	message           string                          // 設定コードのコピー・貼り付けや出陣できない理由
+	customArmy        []data.ReinforcementGroupConfig // 自軍編成で出陣する部隊（プロフィールから読み込む）
+	gold              int                             // プロフィールの所持金
+	profile           *save.Profile                   // ステージごとの軍勢の戦績（nil: 読み込めなかった）
+	userPresets       []*save.ArmyPreset              // 保存した自軍編成（自軍編成の後ろに並ぶ）
+	editingPreset     *save.ArmyPreset                // 名前を入力している編成（nil: 入力していない）
+	presetNameInput   *graphics.TextInput
+	
+	autoResolve     *game.AutoResolveResult // 現在の設定での模擬戦の予測（nil: 計算中）
+	setupGeneration int                     // 設定を変えるたびに増える（古い予測を捨てる）
+	forecasts       chan setupForecast      // バックグラウンドで計算した予測
+	forecasting     bool
+	forecastFailed  bool
 }
 
 // NewArmySetupScene creates a new army setup scene
-func NewArmySetupScene(sceneManager *SceneManager, textRenderer *graphics.TextRenderer) *ArmySetupScene {
+func NewArmySetupScene(sceneManager *SceneManager, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) *ArmySetupScene {
 	return &ArmySetupScene{
-		sceneManager:   sceneManager,
-		textRenderer:   textRenderer,
-		selectedItem:   0,
-		presetArmies:   []string{"バランス型", "攻撃重視", "防御重視"},
-		selectedPreset: 0,
-		selectedStage:  0,
-		stages:         []string{"森の戦い", "山岳要塞", "平原決戦"},
+		sceneManager:      sceneManager,
+		dataManager:       dataManager,
+		textRenderer:      textRenderer,
+		selectedItem:      0,
+		presetArmies:      append(presetChoices(dataManager), customArmyChoice),
+		enemyPresets:      enemyPresetChoices(dataManager),
+		selectedPreset:    0,
+		selectedStage:     0,
+		stages:            stageChoices(dataManager),
+		mutators:          game.GetMutators(),
+		enabledMutators:   make(map[string]bool),
+		doctrineIDs:       dataManager.GetDoctrineIDs(),
+		selectedDoctrines: make([]int, len(doctrineSides)),
+		selectedHandicaps: newHandicapSelection(),
+		presetNameInput:   graphics.NewTextInput(100, 380, 300, 28, "編成の名前", 16),
+		forecasts:         make(chan setupForecast, 1),
+	}
+}
+
+// newHandicapSelection returns handicap rows that all leave the armies unchanged
+func newHandicapSelection() []int {
+	selection := make([]int, len(doctrineSides)*len(handicapStats))
+	for i := range selection {
+		selection[i] = defaultHandicapStep
 	}
+	return selection
 }
 
 // Update updates the army setup scene
 func (as *ArmySetupScene) Update() error {
+	// Naming a saved composition takes the keyboard
+	if as.editingPreset != nil {
+		as.updatePresetName()
+		return nil
+	}
+	
+	// Saved compositions are managed from the preset row
+	if as.selectedItem == playerPresetItem {
+		as.handlePresetKeys()
+	}
+	
+	// Setup codes go through the clipboard
+	if controls.IsShortcutJustPressed(ebiten.KeyC) {
+		as.copySetupCode()
+	}
+	if controls.IsShortcutJustPressed(ebiten.KeyV) {
+		as.pasteSetupCode()
+	}
+	
 	// Handle input
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
 		as.selectedItem--
 		if as.selectedItem < 0 {
-			as.selectedItem = 5 // Total number of selectable items - 1
+			as.selectedItem = as.lastItem()
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
 		as.selectedItem++
-		if as.selectedItem > 5 {
+		if as.selectedItem > as.lastItem() {
 			as.selectedItem = 0
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
-		switch as.selectedItem {
-		case 0: // Stage selection
-			as.selectedStage--
-			if as.selectedStage < 0 {
-				as.selectedStage = len(as.stages) - 1
-			}
-		case 1, 2, 3: // Preset army selection
-			as.selectedPreset--
-			if as.selectedPreset < 0 {
-				as.selectedPreset = len(as.presetArmies) - 1
-			}
-		}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		as.cycleSelection(-1)
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
-		switch as.selectedItem {
-		case 0: // Stage selection
-			as.selectedStage++
-			if as.selectedStage >= len(as.stages) {
-				as.selectedStage = 0
-			}
-		case 1, 2, 3: // Preset army selection
-			as.selectedPreset++
-			if as.selectedPreset >= len(as.presetArmies) {
-				as.selectedPreset = 0
-			}
-		}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		as.cycleSelection(1)
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		switch as.selectedItem {
-		case 4: // 戦闘開始
-			// Set selected stage and preset in game data
-			as.sceneManager.gameData.CurrentStage = as.stages[as.selectedStage]
-			// Pass both stage and preset information to battle scene
-			battleData := map[string]interface{}{
-				"stage":  as.stages[as.selectedStage],
-				"preset": as.presetArmies[as.selectedPreset],
-			}
-			as.sceneManager.TransitionTo(SceneBattle, battleData)
-		case 5: // 戻る
-			as.sceneManager.TransitionTo(SceneTitle, nil)
-		}
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) {
+		as.confirmSelection()
+	}
+	
+	// Pointing at a row selects it, and clicking changes or presses it
+	if updateMenuMouse(as.textRenderer, as.menuRegions(), &as.selectedItem) {
+		as.handleClick()
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
 		as.sceneManager.TransitionTo(SceneTitle, nil)
 	}
 	
+	// Keep the forecast in step with the setup
+	as.updateForecast()
+	
 	return nil
 }
 
 // Draw draws the army setup scene
 func (as *ArmySetupScene) Draw(screen *ebiten.Image) {
 	// Clear screen with dark background
-	screen.Fill(color.RGBA{44, 62, 80, 255}) // #2C3E50
+	screen.Fill(graphics.CurrentTheme().Background)
 	
 	// Draw title
 	titleText := "軍勢設定"
-	as.textRenderer.DrawTextWithSize(screen, titleText, 450, 50, color.RGBA{236, 240, 241, 255}, 24)
+	as.textRenderer.DrawTextWithSize(screen, titleText, 450, 50, graphics.CurrentTheme().Text, 24)
+	
+	// Draw the seed shared by setup codes
+	as.drawSetupCode(screen)
 	
 	// Draw stage selection
 	stageText := "ステージ選択:"
-	as.textRenderer.DrawText(screen, stageText, 100, 120, color.RGBA{236, 240, 241, 255})
+	as.textRenderer.DrawText(screen, stageText, 100, 120, graphics.CurrentTheme().Text)
 	
-	stageSelectionText := "< " + as.stages[as.selectedStage] + " >"
+	stageSelectionText := "< " + as.stages[as.selectedStage].Name + " >"
 	if as.selectedItem == 0 {
 		as.textRenderer.DrawTextWithShadow(screen, "> "+stageSelectionText, 80, 150, 
-			color.RGBA{52, 152, 219, 255}, color.RGBA{0, 0, 0, 128})
+			graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
 	} else {
-		as.textRenderer.DrawText(screen, stageSelectionText, 100, 150, color.RGBA{236, 240, 241, 255})
+		as.textRenderer.DrawText(screen, stageSelectionText, 100, 150, graphics.CurrentTheme().Text)
 	}
+	as.drawStageInfo(screen)
 	
 	// Draw stage effects
 	effectsText := "地形効果:"
-	as.textRenderer.DrawText(screen, effectsText, 100, 180, color.RGBA{149, 165, 166, 255})
+	as.textRenderer.DrawText(screen, effectsText, 100, 180, graphics.CurrentTheme().TextMuted)
 	
-	switch as.selectedStage {
-	case 0: // 森の戦い
-		as.textRenderer.DrawText(screen, "・移動速度-30%", 100, 200, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・弓兵攻撃+20%", 100, 220, color.RGBA{149, 165, 166, 255})
-	case 1: // 山岳要塞
-		as.textRenderer.DrawText(screen, "・移動速度-50%", 100, 200, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・防御力+30%", 100, 220, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・魔術師攻撃+30%", 100, 240, color.RGBA{149, 165, 166, 255})
-	case 2: // 平原決戦
-		as.textRenderer.DrawText(screen, "・移動速度+20%", 100, 200, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・全ユニット攻撃+10%", 100, 220, color.RGBA{149, 165, 166, 255})
+	for i, line := range as.stageEffectLines() {
+		x := 100 + float64(i/stageEffectRows)*stageEffectColumn
+		y := 200 + float64(i%stageEffectRows)*20
+		as.textRenderer.DrawText(screen, line, x, y, graphics.CurrentTheme().TextMuted)
 	}
 	
 	// Draw preset armies
-	presetText := "プリセット軍勢:"
-	as.textRenderer.DrawText(screen, presetText, 100, 300, color.RGBA{236, 240, 241, 255})
+	presetText := "プリセット軍勢（自軍 / 敵軍）:"
+	as.textRenderer.DrawText(screen, presetText, 100, 300, graphics.CurrentTheme().Text)
 	
 	// Show current selected preset
 	currentPresetText := "< " + as.presetArmies[as.selectedPreset] + " >"
-	if as.selectedItem >= 1 && as.selectedItem <= 3 {
+	if as.selectedItem == playerPresetItem {
 		as.textRenderer.DrawTextWithShadow(screen, "> "+currentPresetText, 80, 330, 
-			color.RGBA{52, 152, 219, 255}, color.RGBA{0, 0, 0, 128})
+			graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+	} else {
+		as.textRenderer.DrawText(screen, currentPresetText, 100, 330, graphics.CurrentTheme().Text)
+	}
+	
+	// Show the enemy's preset beside it
+	enemyPresetText := as.enemyPresetRowText()
+	if as.selectedItem == enemyPresetItem {
+		as.textRenderer.DrawTextWithShadow(screen, "> "+enemyPresetText, enemyPresetX-20, enemyPresetY,
+			graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+	} else {
+		as.textRenderer.DrawText(screen, enemyPresetText, enemyPresetX, enemyPresetY, graphics.CurrentTheme().Text)
+	}
+	
+	// Show preset details, or the name field of the composition being saved
+	if as.editingPreset != nil {
+		as.textRenderer.DrawText(screen, "編成の名前（Enter: 決定  Esc: やめる）:", 100, 360, graphics.CurrentTheme().TextMuted)
+		as.presetNameInput.Draw(screen, as.textRenderer)
 	} else {
-		as.textRenderer.DrawText(screen, currentPresetText, 100, 330, color.RGBA{236, 240, 241, 255})
+		as.drawPresetDetails(screen, as.selectedPreset)
 	}
+	if as.selectedItem == playerPresetItem {
+		presetKeysText := "S: 自軍編成を保存"
+		if as.selectedUserPreset() != nil {
+			presetKeysText += "  F2: 名前を変更  Delete: 削除"
+		}
+		as.textRenderer.DrawText(screen, presetKeysText, 100, 680, graphics.CurrentTheme().TextMuted)
+	}
+	
+	// Show the army that has done best on the stage
+	as.drawSuggestion(screen)
+	
+	// Show deployment preview of the selected stage
+	as.drawStagePreview(screen)
+	
+	// Show doctrine selection
+	as.drawDoctrines(screen)
 	
-	// Show preset details
-	as.drawPresetDetails(screen, as.selectedPreset)
+	// Show handicap selection
+	as.drawHandicaps(screen)
+	
+	// Show mutator toggles
+	as.drawMutators(screen)
 	
 	// Draw buttons
-	buttons := []string{"戦闘開始", "戻る"}
-	for i, button := range buttons {
-		x := 400.0 + float64(i*150)
-		y := 500.0
-		if as.selectedItem == i+4 {
-			as.textRenderer.DrawTextWithShadow(screen, "> "+button+" <", x-20, y, 
-				color.RGBA{52, 152, 219, 255}, color.RGBA{0, 0, 0, 128})
+	for i, button := range setupButtons {
+		if as.selectedItem == startItem+i {
+			as.textRenderer.DrawTextWithShadow(screen, "> "+button.label+" <", button.x-20, button.y, 
+				graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
 		} else {
-			as.textRenderer.DrawText(screen, button, x, y, color.RGBA{236, 240, 241, 255})
+			as.textRenderer.DrawText(screen, button.label, button.x, button.y, graphics.CurrentTheme().Text)
 		}
 	}
 	
+	// Show the auto-resolve estimate
+	as.drawAutoResolve(screen)
+	
 	// Draw controls hint
-	controlsText := "↑↓: 選択  ←→: ステージ・編成変更  Enter: 決定  Esc: 戻る"
-	as.textRenderer.DrawText(screen, controlsText, 200, 600, color.RGBA{149, 165, 166, 255})
+	controlsText := "↑↓/マウス: 選択  ←→/クリック: ステージ・編成・ドクトリン・ハンデ・特殊ルール変更  Enter: 決定  Esc: 戻る"
+	as.textRenderer.DrawText(screen, controlsText, 120, 700, graphics.CurrentTheme().TextMuted)
+}
+
+// drawDoctrines draws the doctrine chosen for each side
+func (as *ArmySetupScene) drawDoctrines(screen *ebiten.Image) {
+	as.textRenderer.DrawText(screen, "ドクトリン:", 100, doctrineListY, graphics.CurrentTheme().Text)
+	
+	for side := range doctrineSides {
+		text := as.doctrineRowText(side)
+		y := as.doctrineRowY(side)
+		if as.selectedItem == as.firstDoctrineRow()+side {
+			as.textRenderer.DrawTextWithShadow(screen, "> "+text, 80, y,
+				graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+		} else {
+			as.textRenderer.DrawText(screen, text, 100, y, graphics.CurrentTheme().Text)
+		}
+	}
+}
+
+// doctrineRowText returns the selector label of a side's doctrine
+func (as *ArmySetupScene) doctrineRowText(side int) string {
+	name := "なし"
+	if id := as.getDoctrineID(side); id != "" {
+		if doctrine, err := as.dataManager.GetDoctrineConfig(id); err == nil {
+			name = doctrine.Name
+		}
+	}
+	return doctrineSides[side] + ": < " + name + " >"
+}
+
+// doctrineRowY returns the screen Y of a side's doctrine row
+func (as *ArmySetupScene) doctrineRowY(side int) float64 {
+	return float64(doctrineListY + doctrineRowStep*(side+1))
+}
+
+// firstDoctrineRow returns the item index of the first doctrine row, after the mutators
+func (as *ArmySetupScene) firstDoctrineRow() int {
+	return firstMutatorRow + len(as.mutators)
+}
+
+// getDoctrineID returns the doctrine ID chosen for a side, or "" for none
+func (as *ArmySetupScene) getDoctrineID(side int) string {
+	index := as.selectedDoctrines[side]
+	if index <= 0 || index > len(as.doctrineIDs) {
+		return ""
+	}
+	return as.doctrineIDs[index-1]
+}
+
+// drawHandicaps draws the handicap multipliers chosen for each side, one column per side
+func (as *ArmySetupScene) drawHandicaps(screen *ebiten.Image) {
+	for side := range doctrineSides {
+		x := as.handicapColumnX(side)
+		as.textRenderer.DrawText(screen, doctrineSides[side]+"ハンデ:", x, handicapListY, graphics.CurrentTheme().Text)
+		
+		for stat := range handicapStats {
+			text := as.handicapRowText(side, stat)
+			y := as.handicapRowY(stat)
+			if as.selectedItem == as.firstHandicapRow()+side*len(handicapStats)+stat {
+				as.textRenderer.DrawTextWithShadow(screen, "> "+text, x-20, y,
+					graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+			} else {
+				as.textRenderer.DrawText(screen, text, x, y, graphics.CurrentTheme().Text)
+			}
+		}
+	}
+}
+
+// handicapRowText returns the selector label of a side's handicap on a stat
+func (as *ArmySetupScene) handicapRowText(side, stat int) string {
+	step := handicapSteps[as.selectedHandicaps[side*len(handicapStats)+stat]]
+	return fmt.Sprintf("%s < %.0f%% >", handicapStats[stat], step*100)
+}
+
+// handicapColumnX returns the screen X of a side's handicap column
+func (as *ArmySetupScene) handicapColumnX(side int) float64 {
+	return float64(100 + handicapColumnX*side)
+}
+
+// handicapRowY returns the screen Y of a stat's handicap row
+func (as *ArmySetupScene) handicapRowY(stat int) float64 {
+	return float64(handicapListY + handicapRowStep*(stat+1))
+}
+
+// firstHandicapRow returns the item index of the player's first handicap row, after the doctrines
+func (as *ArmySetupScene) firstHandicapRow() int {
+	return as.firstDoctrineRow() + len(doctrineSides)
+}
+
+// getHandicaps returns the handicap chosen for each side
+func (as *ArmySetupScene) getHandicaps() []game.Handicap {
+	handicaps := make([]game.Handicap, len(doctrineSides))
+	for side := range doctrineSides {
+		steps := as.selectedHandicaps[side*len(handicapStats):]
+		handicaps[side] = game.Handicap{
+			HP:     handicapSteps[steps[0]],
+			Attack: handicapSteps[steps[1]],
+			Count:  handicapSteps[steps[2]],
+		}
+	}
+	return handicaps
+}
+
+// drawMutators draws the mutator toggles and the description of the selected one
+func (as *ArmySetupScene) drawMutators(screen *ebiten.Image) {
+	as.textRenderer.DrawText(screen, "特殊ルール:", mutatorListX, mutatorListY, graphics.CurrentTheme().Text)
+	
+	for i, mutator := range as.mutators {
+		text := as.mutatorRowText(mutator)
+		y := as.mutatorRowY(i)
+		if as.selectedItem == firstMutatorRow+i {
+			as.textRenderer.DrawTextWithShadow(screen, "> "+text, mutatorListX-20, y,
+				graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+			as.textRenderer.DrawText(screen, mutator.Description, mutatorListX, as.mutatorRowY(len(as.mutators)), graphics.CurrentTheme().TextMuted)
+		} else {
+			as.textRenderer.DrawText(screen, text, mutatorListX, y, graphics.CurrentTheme().Text)
+		}
+	}
+}
+
+// mutatorRowText returns the toggle label of a mutator
+func (as *ArmySetupScene) mutatorRowText(mutator *game.Mutator) string {
+	if as.enabledMutators[mutator.ID] {
+		return "[x] " + mutator.Name
+	}
+	return "[ ] " + mutator.Name
+}
+
+// mutatorRowY returns the screen Y of the i-th mutator row
+func (as *ArmySetupScene) mutatorRowY(i int) float64 {
+	return float64(mutatorListY + mutatorRowStep*(i+1))
+}
+
+// lastItem returns the index of the last selectable item
+func (as *ArmySetupScene) lastItem() int {
+	return as.firstHandicapRow() + len(as.selectedHandicaps) - 1
+}
+
+// selectedMutator returns the mutator of the selected row, or nil
+func (as *ArmySetupScene) selectedMutator() *game.Mutator {
+	index := as.selectedItem - firstMutatorRow
+	if index < 0 || index >= len(as.mutators) {
+		return nil
+	}
+	return as.mutators[index]
+}
+
+// getDoctrineIDs returns the doctrine ID chosen for each side
+func (as *ArmySetupScene) getDoctrineIDs() []string {
+	ids := make([]string, len(doctrineSides))
+	for side := range doctrineSides {
+		ids[side] = as.getDoctrineID(side)
+	}
+	return ids
+}
+
+// getEnabledMutatorIDs returns the IDs of the enabled mutators in display order
+func (as *ArmySetupScene) getEnabledMutatorIDs() []string {
+	var ids []string
+	for _, mutator := range as.mutators {
+		if as.enabledMutators[mutator.ID] {
+			ids = append(ids, mutator.ID)
+		}
+	}
+	return ids
+}
+
+// cycleSelection steps the stage, preset, doctrine or handicap of the selected row, or toggles the selected mutator
+func (as *ArmySetupScene) cycleSelection(delta int) {
+	if as.selectedItem >= startItem && as.selectedItem <= suggestItem {
+		return // Buttons have nothing to step
+	}
+	
+	// Any change to the setup makes the forecast stale
+	as.invalidateForecast()
+	
+	switch as.selectedItem {
+	case 0: // Stage selection
+		as.selectedStage = (as.selectedStage + delta + len(as.stages)) % len(as.stages)
+	case playerPresetItem: // Preset army selection
+		as.selectedPreset = (as.selectedPreset + delta + len(as.presetArmies)) % len(as.presetArmies)
+	case enemyPresetItem: // Enemy preset selection
+		as.selectedEnemy = (as.selectedEnemy + delta + len(as.enemyPresets)) % len(as.enemyPresets)
+	default:
+		if mutator := as.selectedMutator(); mutator != nil {
+			as.enabledMutators[mutator.ID] = !as.enabledMutators[mutator.ID]
+		}
+		if side := as.selectedItem - as.firstDoctrineRow(); side >= 0 && side < len(doctrineSides) {
+			choices := len(as.doctrineIDs) + 1
+			as.selectedDoctrines[side] = (as.selectedDoctrines[side] + delta + choices) % choices
+		}
+		if row := as.selectedItem - as.firstHandicapRow(); row >= 0 && row < len(as.selectedHandicaps) {
+			as.selectedHandicaps[row] = (as.selectedHandicaps[row] + delta + len(handicapSteps)) % len(handicapSteps)
+		}
+	}
+}
+
+// confirmSelection activates the selected button or toggles the selected mutator
+func (as *ArmySetupScene) confirmSelection() {
+	if as.selectedMutator() != nil {
+		as.cycleSelection(1)
+		return
+	}
+	
+	switch as.selectedItem {
+	case startItem: // 戦闘開始
+		if !as.isArmyReady() {
+			return
+		}
+		as.deployUserPreset()
+		// Set selected stage and preset in game data
+		as.sceneManager.gameData.CurrentStage = as.stages[as.selectedStage].Name
+		// Pass both stage and preset information to battle scene
+		battleData := map[string]interface{}{
+			"stage":        as.stages[as.selectedStage].Name,
+			"preset":       as.getPresetName(),
+			"enemy_preset": as.getEnemyPreset(),
+			"army":         as.getCustomArmy(),
+			"mutators":     as.getEnabledMutatorIDs(),
+			"doctrines":    as.getDoctrineIDs(),
+			"handicaps":    as.getHandicaps(),
+			"seed":         as.seed,
+		}
+		as.sceneManager.TransitionTo(SceneBattle, battleData)
+	case backItem: // 戻る
+		as.sceneManager.TransitionTo(SceneTitle, nil)
+	case autoResolveItem: // 自動解決
+		as.autoResolveBattle()
+	case recruitItem: // 部隊編成
+		as.sceneManager.TransitionTo(SceneRecruitment, nil)
+	case suggestItem: // おすすめ編成
+		as.useSuggestedArmy()
+	}
+}
+
+// suggestedArmy returns the army with the best record on the selected stage
+func (as *ArmySetupScene) suggestedArmy() (*save.ArmyRecord, bool) {
+	if as.profile == nil {
+		return nil, false
+	}
+	return as.profile.SuggestArmy(as.stages[as.selectedStage].ID)
+}
+
+// useSuggestedArmy chooses the suggested army; a custom army is filled in by deploying
+// the hired groups it fought with, and the ones no longer on the roster are reported
+func (as *ArmySetupScene) useSuggestedArmy() {
+	army, ok := as.suggestedArmy()
+	if !ok {
+		as.message = "このステージで勝った編成がまだありません"
+		return
+	}
+	preset := slices.Index(as.presetArmies, army.Preset)
+	if preset < 0 {
+		as.message = "おすすめの編成「" + army.Preset + "」はもう選べません"
+		return
+	}
+	
+	as.invalidateForecast()
+	as.selectedPreset = preset
+	as.message = ""
+	if army.Preset != customArmyChoice {
+		return
+	}
+	
+	missing := deployRecruits(as.dataManager.Recruitment, as.profile.Roster, army.Groups)
+	if err := as.profile.Save(save.DefaultProfilePath); err != nil {
+		fmt.Printf("Warning: Failed to save profile: %v\n", err)
+	}
+	as.customArmy, as.gold = loadCustomArmy()
+	if len(missing) > 0 {
+		as.message = "出陣できない部隊があります: " + strings.Join(missing, "・")
+	}
+}
+
+// getPresetName returns the preset the battle is fought with; a saved composition fights as the custom army
+func (as *ArmySetupScene) getPresetName() string {
+	if as.selectedUserPreset() != nil {
+		return customArmyChoice
+	}
+	return as.presetArmies[as.selectedPreset]
+}
+
+// getEnemyPreset returns the preset the enemy armies field, or empty when they field the player's
+func (as *ArmySetupScene) getEnemyPreset() string {
+	if as.selectedEnemy == 0 {
+		return ""
+	}
+	return as.enemyPresets[as.selectedEnemy]
+}
+
+// enemyPresetRowText returns the enemy preset row as drawn, without the selection marker
+func (as *ArmySetupScene) enemyPresetRowText() string {
+	return "敵軍: < " + as.enemyPresets[as.selectedEnemy] + " >"
+}
+
+// getCustomArmy returns the groups the player deployed when the custom army is chosen,
+// the hired groups a saved composition names when one is chosen, or nil for a preset
+func (as *ArmySetupScene) getCustomArmy() []data.ReinforcementGroupConfig {
+	if preset := as.selectedUserPreset(); preset != nil {
+		groups, _ := as.userPresetArmy(preset)
+		return groups
+	}
+	if as.presetArmies[as.selectedPreset] != customArmyChoice {
+		return nil
+	}
+	return as.customArmy
+}
+
+// isArmyReady reports whether the chosen army can take the field; a custom army needs deployed groups
+func (as *ArmySetupScene) isArmyReady() bool {
+	if as.getPresetName() == customArmyChoice && len(as.getCustomArmy()) == 0 {
+		as.message = "出陣する部隊がありません（部隊編成で雇ってください）"
+		return false
+	}
+	return true
+}
+
+// selectedUserPreset returns the saved composition chosen as the preset, or nil
+func (as *ArmySetupScene) selectedUserPreset() *save.ArmyPreset {
+	index := as.selectedPreset - len(presetChoices(as.dataManager)) - 1
+	if index < 0 || index >= len(as.userPresets) {
+		return nil
+	}
+	return as.userPresets[index]
+}
+
+// userPresetArmy returns the hired groups a saved composition fields, chosen as the recruitment screen
+// would deploy them, and the names no hired group answers
+func (as *ArmySetupScene) userPresetArmy(preset *save.ArmyPreset) ([]data.ReinforcementGroupConfig, []string) {
+	if as.profile == nil {
+		return nil, preset.Groups
+	}
+	roster := slices.Clone(as.profile.Roster)
+	missing := deployRecruits(as.dataManager.Recruitment, roster, preset.Groups)
+	return deployedGroups(roster), missing
+}
+
+// deployUserPreset makes the chosen saved composition the deployed groups of the roster before it takes the field,
+// so the battle is recorded and rewarded as the custom army's
+func (as *ArmySetupScene) deployUserPreset() {
+	preset := as.selectedUserPreset()
+	if preset == nil || as.profile == nil {
+		return
+	}
+	deployRecruits(as.dataManager.Recruitment, as.profile.Roster, preset.Groups)
+	if err := as.profile.Save(save.DefaultProfilePath); err != nil {
+		fmt.Printf("Warning: Failed to save profile: %v\n", err)
+	}
+	as.customArmy, as.gold = loadCustomArmy()
+}
+
+// loadUserPresets reads the saved compositions and lists them after the custom army
+func (as *ArmySetupScene) loadUserPresets() {
+	presets, err := save.LoadArmyPresets(save.DefaultPresetDir)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load army presets: %v\n", err)
+	}
+	as.userPresets = presets
+	as.refreshPresetArmies()
+}
+
+// refreshPresetArmies rebuilds the preset choices after the saved compositions change
+func (as *ArmySetupScene) refreshPresetArmies() {
+	as.presetArmies = append(presetChoices(as.dataManager), customArmyChoice)
+	for _, preset := range as.userPresets {
+		as.presetArmies = append(as.presetArmies, userPresetPrefix+preset.Name)
+	}
+	as.selectedPreset = min(as.selectedPreset, len(as.presetArmies)-1)
+}
+
+// handlePresetKeys saves the chosen custom army or composition under a new name, renames or deletes a saved one
+func (as *ArmySetupScene) handlePresetKeys() {
+	if controls.IsKeyJustPressed(ebiten.KeyS) {
+		var groups []string
+		if preset := as.selectedUserPreset(); preset != nil {
+			groups = preset.Groups
+		} else if as.presetArmies[as.selectedPreset] == customArmyChoice && as.profile != nil {
+			groups = deployedRecruits(as.profile, customArmyChoice)
+		}
+		if len(groups) == 0 {
+			as.message = "保存できるのは出陣する部隊のある自軍編成だけです"
+			return
+		}
+		as.startPresetName(save.NewArmyPreset("", groups), fmt.Sprintf("編成%d", len(as.userPresets)+1))
+		return
+	}
+	
+	preset := as.selectedUserPreset()
+	if preset == nil {
+		return
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyF2) {
+		as.startPresetName(preset, preset.Name)
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyDelete) {
+		if err := preset.Delete(); err != nil {
+			fmt.Printf("Warning: Failed to delete army preset: %v\n", err)
+			as.message = "削除できませんでした: " + err.Error()
+			return
+		}
+		as.userPresets = slices.DeleteFunc(as.userPresets, func(other *save.ArmyPreset) bool { return other == preset })
+		as.invalidateForecast()
+		as.refreshPresetArmies()
+		as.message = "「" + preset.Name + "」を削除しました"
+	}
+}
+
+// startPresetName opens the name field for a composition to save or rename
+func (as *ArmySetupScene) startPresetName(preset *save.ArmyPreset, name string) {
+	as.editingPreset = preset
+	as.presetNameInput.Text = name
+	as.presetNameInput.Focus()
+	as.message = ""
+}
+
+// updatePresetName types the composition's name and saves it on Enter; Escape or a click elsewhere gives up
+func (as *ArmySetupScene) updatePresetName() {
+	as.presetNameInput.Update()
+	if !as.presetNameInput.IsFocused() {
+		as.editingPreset = nil
+		return
+	}
+	if !as.presetNameInput.IsSubmitted() {
+		return
+	}
+	
+	preset := as.editingPreset
+	name := strings.TrimSpace(as.presetNameInput.Text)
+	if name == "" {
+		as.message = "名前を入力してください"
+		return
+	}
+	for _, other := range as.userPresets {
+		if other != preset && other.Name == name {
+			as.message = "「" + name + "」という編成はもうあります"
+			return
+		}
+	}
+	
+	oldName := preset.Name
+	preset.Name = name
+	if err := preset.Save(save.DefaultPresetDir); err != nil {
+		fmt.Printf("Warning: Failed to save army preset: %v\n", err)
+		preset.Name = oldName
+		as.message = "保存できませんでした: " + err.Error()
+		return
+	}
+	
+	as.editingPreset = nil
+	as.presetNameInput.Blur()
+	if !slices.Contains(as.userPresets, preset) {
+		as.userPresets = append(as.userPresets, preset)
+		as.message = "「" + name + "」を保存しました"
+	} else {
+		as.message = "名前を「" + name + "」に変えました"
+	}
+	as.refreshPresetArmies()
+	as.selectedPreset = slices.Index(as.presetArmies, userPresetPrefix+name)
+	as.invalidateForecast()
+}
+
+// menuRegions returns where the rows and buttons are drawn
+func (as *ArmySetupScene) menuRegions() []menuRegion {
+	regions := []menuRegion{
+		{0, "> < " + as.stages[as.selectedStage].Name + " >", 80, 150},
+		{playerPresetItem, "> < " + as.presetArmies[as.selectedPreset] + " >", 80, 330},
+		{enemyPresetItem, "> " + as.enemyPresetRowText(), enemyPresetX - 20, enemyPresetY},
+	}
+	for side := range doctrineSides {
+		regions = append(regions, menuRegion{as.firstDoctrineRow() + side, "> " + as.doctrineRowText(side), 80, as.doctrineRowY(side)})
+	}
+	for side := range doctrineSides {
+		for stat := range handicapStats {
+			item := as.firstHandicapRow() + side*len(handicapStats) + stat
+			regions = append(regions, menuRegion{item, "> " + as.handicapRowText(side, stat), as.handicapColumnX(side) - 20, as.handicapRowY(stat)})
+		}
+	}
+	for i, mutator := range as.mutators {
+		regions = append(regions, menuRegion{firstMutatorRow + i, "> " + as.mutatorRowText(mutator), mutatorListX - 20, as.mutatorRowY(i)})
+	}
+	for i, button := range setupButtons {
+		regions = append(regions, menuRegion{startItem + i, "> " + button.label + " <", button.x - 20, button.y})
+	}
+	return regions
+}
+
+// handleClick acts on the clicked row, which updateMenuMouse has selected: buttons are pressed,
+// special rules toggled, and clicking the left or right half of a stage, preset, doctrine or
+// handicap row steps it back or forward
+func (as *ArmySetupScene) handleClick() {
+	region, ok := regionAt(as.textRenderer, as.menuRegions())
+	if !ok {
+		return
+	}
+	switch {
+	case region.item >= startItem && region.item < startItem+len(setupButtons):
+		as.confirmSelection()
+	case region.item >= firstMutatorRow && region.item < firstMutatorRow+len(as.mutators):
+		as.cycleSelection(1)
+	default:
+		mouseX, _ := controls.CursorPosition()
+		width, _ := as.textRenderer.MeasureText(region.text)
+		if float64(mouseX) < region.x+width/2 {
+			as.cycleSelection(-1)
+		} else {
+			as.cycleSelection(1)
+		}
+	}
 }
 
 // OnEnter is called when entering this scene
@@ -180,6 +844,27 @@ func (as *ArmySetupScene) OnEnter(data interface{}) {
 	as.selectedItem = 0
 	as.selectedStage = 0
 	as.selectedPreset = 0
+	as.selectedEnemy = 0
+	as.enabledMutators = make(map[string]bool)
+	as.selectedDoctrines = make([]int, len(doctrineSides))
+	as.selectedHandicaps = newHandicapSelection()
+	as.seed = 0
+	as.message = ""
+	as.customArmy, as.gold = loadCustomArmy()
+	as.loadProfile()
+	as.loadUserPresets()
+	as.editingPreset = nil
+	as.invalidateForecast()
+}
+
+// loadProfile reads the player's record for the suggestions
+func (as *ArmySetupScene) loadProfile() {
+	profile, err := save.LoadProfile(save.DefaultProfilePath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load profile: %v\n", err)
+		profile = nil
+	}
+	as.profile = profile
 }
 
 // OnExit is called when exiting this scene
@@ -190,20 +875,241 @@ func (as *ArmySetupScene) OnExit() {
 // drawPresetDetails draws details about the selected preset
 func (as *ArmySetupScene) drawPresetDetails(screen *ebiten.Image, presetIndex int) {
 	detailsText := "編成詳細:"
-	as.textRenderer.DrawText(screen, detailsText, 100, 360, color.RGBA{149, 165, 166, 255})
+	as.textRenderer.DrawText(screen, detailsText, 100, 360, graphics.CurrentTheme().TextMuted)
+	
+	if as.getPresetName() == customArmyChoice {
+		as.drawCustomArmyDetails(screen)
+		return
+	}
 	
 	switch presetIndex {
 	case 0: // バランス型
-		as.textRenderer.DrawText(screen, "・歩兵: 3部隊", 100, 380, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・弓兵: 2部隊", 100, 400, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・魔術師: 1部隊", 100, 420, color.RGBA{149, 165, 166, 255})
+		as.textRenderer.DrawText(screen, "・歩兵: 3部隊", 100, 380, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・弓兵: 2部隊", 100, 400, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・魔術師: 1部隊", 100, 420, graphics.CurrentTheme().TextMuted)
 	case 1: // 攻撃重視
-		as.textRenderer.DrawText(screen, "・歩兵: 2部隊", 100, 380, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・弓兵: 3部隊", 100, 400, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・魔術師: 2部隊", 100, 420, color.RGBA{149, 165, 166, 255})
+		as.textRenderer.DrawText(screen, "・歩兵: 2部隊", 100, 380, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・弓兵: 3部隊", 100, 400, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・魔術師: 2部隊", 100, 420, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・グリフォン: 1部隊（飛行）", 100, 440, graphics.CurrentTheme().TextMuted)
 	case 2: // 防御重視
-		as.textRenderer.DrawText(screen, "・歩兵: 4部隊", 100, 380, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・弓兵: 1部隊", 100, 400, color.RGBA{149, 165, 166, 255})
-		as.textRenderer.DrawText(screen, "・魔術師: 1部隊", 100, 420, color.RGBA{149, 165, 166, 255})
+		as.textRenderer.DrawText(screen, "・歩兵: 4部隊", 100, 380, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・弓兵: 1部隊", 100, 400, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・魔術師: 1部隊", 100, 420, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・斥候: 1部隊（潜伏）", 100, 440, graphics.CurrentTheme().TextMuted)
+	case 3: // 攻城型
+		as.textRenderer.DrawText(screen, "・歩兵: 1部隊", 100, 380, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・投石機・破城槌: 2部隊", 100, 400, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・弓兵: 1部隊", 100, 420, graphics.CurrentTheme().TextMuted)
+	case 4: // 召喚型
+		as.textRenderer.DrawText(screen, "・歩兵: 1部隊", 100, 380, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・召喚士: 1部隊（使い魔を呼ぶ）", 100, 400, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, "・弓兵: 1部隊", 100, 420, graphics.CurrentTheme().TextMuted)
+	case 5: // ランダム編成
+		recruitment := as.dataManager.Recruitment
+		as.textRenderer.DrawText(screen, "・雇える部隊から戦闘ごとに選ぶ", 100, 380, graphics.CurrentTheme().TextMuted)
+		as.textRenderer.DrawText(screen, fmt.Sprintf("・戦力点%d以内、%d部隊まで", recruitment.PointBudget, recruitment.MaxGroups), 100, 400, graphics.CurrentTheme().TextMuted)
+	}
+}
+
+// drawSuggestion draws the army with the best record on the selected stage and its win rate
+func (as *ArmySetupScene) drawSuggestion(screen *ebiten.Image) {
+	army, ok := as.suggestedArmy()
+	if !ok {
+		as.textRenderer.DrawText(screen, "おすすめ: なし（勝った編成を覚えます）", 100, suggestionY, graphics.CurrentTheme().TextMuted)
+		return
+	}
+	
+	name := army.Preset
+	if army.Preset == customArmyChoice && len(army.Groups) > 0 {
+		name += "（" + army.Groups[0]
+		if len(army.Groups) > 1 {
+			name += fmt.Sprintf(" ほか%d部隊", len(army.Groups)-1)
+		}
+		name += "）"
+	}
+	suggestionColor := graphics.CurrentTheme().Success
+	as.textRenderer.DrawText(screen, "おすすめ: "+name, 100, suggestionY, suggestionColor)
+	as.textRenderer.DrawText(screen, fmt.Sprintf("  勝率%.0f%%（%d戦%d勝）", army.WinRate()*100, army.Battles, army.Wins), 100, suggestionY+18, suggestionColor)
+}
+
+// drawCustomArmyDetails draws the deployed groups of the custom army or saved composition, three to a line,
+// the player's gold and the groups of a saved composition that cannot take the field
+func (as *ArmySetupScene) drawCustomArmyDetails(screen *ebiten.Image) {
+	textColor := graphics.CurrentTheme().TextMuted
+	army := as.getCustomArmy()
+	as.textRenderer.DrawText(screen, fmt.Sprintf("・出陣: %d部隊  所持金: %d金", len(army), as.gold), 100, 380, textColor)
+	
+	line := "・"
+	y := 400.0
+	for i, group := range army {
+		line += fmt.Sprintf("%s×%d ", unitTypeShortName(group.Member), group.Count)
+		if i%3 == 2 || i == len(army)-1 {
+			as.textRenderer.DrawText(screen, line, 100, y, textColor)
+			line = "・"
+			y += 20
+		}
+	}
+	if preset := as.selectedUserPreset(); preset != nil {
+		if _, missing := as.userPresetArmy(preset); len(missing) > 0 {
+			as.textRenderer.DrawText(screen, "・出陣できない: "+strings.Join(missing, "・"), 100, y, graphics.CurrentTheme().Highlight)
+		}
+	} else if len(army) == 0 {
+		as.textRenderer.DrawText(screen, "・部隊編成で部隊を雇ってください", 100, y, textColor)
+	}
+}
+
+// stageEffectLines describes the terrain modifiers of the selected stage, as many as fit above the presets
+func (as *ArmySetupScene) stageEffectLines() []string {
+	if as.dataManager == nil {
+		return nil
+	}
+	stage, err := as.dataManager.GetStageConfig(as.stages[as.selectedStage].ID)
+	if err != nil {
+		return nil
+	}
+	terrain, err := as.dataManager.GetTerrainConfig(stage.Terrain)
+	if err != nil {
+		return nil
+	}
+	lines := game.TerrainModifierLines(terrain, as.dataManager)
+	if len(lines) > maxStageEffectLines {
+		lines = lines[:maxStageEffectLines]
+	}
+	return lines
+}
+
+// drawStageInfo draws the selected stage's difficulty and recommended army size beside its name,
+// and its description under the preview
+func (as *ArmySetupScene) drawStageInfo(screen *ebiten.Image) {
+	if as.dataManager == nil {
+		return
+	}
+	stage, err := as.dataManager.GetStageConfig(as.stages[as.selectedStage].ID)
+	if err != nil {
+		return
+	}
+	
+	var info []string
+	if stage.Difficulty > 0 {
+		info = append(info, "難易度"+difficultyText(as.stages[as.selectedStage]))
+	}
+	if stage.RecommendedGroups > 0 {
+		info = append(info, fmt.Sprintf("推奨 %d部隊", stage.RecommendedGroups))
+	}
+	if len(info) > 0 {
+		as.textRenderer.DrawText(screen, strings.Join(info, "  "), stageInfoX, 150, graphics.CurrentTheme().Highlight)
+	}
+	if stage.Description != "" {
+		as.textRenderer.DrawText(screen, stage.Description, stagePreviewX, stagePreviewY+stagePreviewSize+8, graphics.CurrentTheme().TextMuted)
+	}
+}
+
+// difficultyText returns the stage's difficulty stars to follow its name (empty: not rated)
+func difficultyText(stage data.StageListing) string {
+	if stage.Difficulty <= 0 {
+		return ""
+	}
+	return " " + starText(min(stage.Difficulty, data.MaxStageDifficulty), data.MaxStageDifficulty)
+}
+
+// drawStagePreview draws a scaled-down schematic of the selected stage
+// with each army's groups at their deployment points
+func (as *ArmySetupScene) drawStagePreview(screen *ebiten.Image) {
+	if as.dataManager == nil {
+		return
+	}
+	
+	stage, err := as.dataManager.GetStageConfig(as.stages[as.selectedStage].ID)
+	if err != nil {
+		return
+	}
+	
+	as.textRenderer.DrawText(screen, "配置プレビュー:", stagePreviewX, stagePreviewY-20, graphics.CurrentTheme().Text)
+	
+	// Scale the stage to fit the preview square
+	width, height := stage.WorldSize()
+	scale := stagePreviewSize / width
+	if height > width {
+		scale = stagePreviewSize / height
+	}
+	toPreview := func(x, y float64) (float32, float32) {
+		return float32(stagePreviewX + x*scale), float32(stagePreviewY + y*scale)
+	}
+	
+	// Battlefield, with the cached thumbnail of its terrain and objectives
+	vector.DrawFilledRect(screen, stagePreviewX, stagePreviewY, float32(width*scale), float32(height*scale), graphics.CurrentTheme().Inset, false)
+	if thumbnail := stageThumbnail(as.dataManager, as.stages[as.selectedStage].ID); thumbnail != nil {
+		drawThumbnail(screen, thumbnail, stagePreviewX, stagePreviewY, width*scale, height*scale)
+	}
+	vector.StrokeRect(screen, stagePreviewX, stagePreviewY, float32(width*scale), float32(height*scale), 1, graphics.CurrentTheme().TextMuted, false)
+	
+	// Deployment points; occupied points show the group leader type
+	for armyID, army := range stage.GetArmyConfigs() {
+		preset := army.Preset
+		if preset == "" && armyID != playerArmyID && as.getEnemyPreset() != "" {
+			preset = as.getEnemyPreset()
+		} else if preset == "" {
+			preset = as.getPresetName()
+		}
+		groups := game.GetPresetGroups(preset, as.dataManager)
+		if preset == game.RandomPreset || preset == game.RandomArmyPreset {
+			groups = nil // 戦闘が始まるまで分からない
+		}
+		if army := as.getCustomArmy(); armyID == playerArmyID && army != nil {
+			groups = nil
+			for _, group := range army {
+				groups = append(groups, game.PresetGroup{LeaderType: group.Leader, MemberType: group.Member, Count: group.Count})
+			}
+		}
+		pointColor := armyColor(armyID)
+		
+		for i, point := range army.DeploymentPoints {
+			x, y := toPreview(point.X, point.Y)
+			if i >= len(groups) {
+				vector.StrokeRect(screen, x-2, y-2, 4, 4, 1, pointColor, false)
+				continue
+			}
+			
+			vector.DrawFilledRect(screen, x-4, y-4, 8, 8, pointColor, false)
+			if armyID == 0 {
+				as.textRenderer.DrawText(screen, unitTypeShortName(groups[i].LeaderType), float64(x+6), float64(y-8), graphics.CurrentTheme().Text)
+			}
+		}
+	}
+	
+	legendText := "■ 部隊  □ 予備配置  ○ 拠点・中立勢力・補給地点  ▮ 建造物"
+	as.textRenderer.DrawText(screen, legendText, stagePreviewX, stagePreviewY+stagePreviewSize+10, graphics.CurrentTheme().TextMuted)
+}
+
+// unitTypeShortName returns a one-character label for a unit type
+func unitTypeShortName(unitType string) string {
+	switch unitType {
+	case "infantry":
+		return "歩"
+	case "archer":
+		return "弓"
+	case "mage":
+		return "魔"
+	case "heavy_infantry":
+		return "重"
+	case "cavalry":
+		return "騎"
+	case "catapult":
+		return "投"
+	case "ram":
+		return "槌"
+	case "griffin":
+		return "鷲"
+	case "summoner":
+		return "召"
+	case "familiar":
+		return "使"
+	case "scout":
+		return "斥"
+	case "convoy":
+		return "輸"
+	default:
+		return "?"
 	}
 }
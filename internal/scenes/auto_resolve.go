@@ -0,0 +1,197 @@
+package scenes
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/campaign"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// autoResolveY is where the forecast is drawn, below the buttons and the handicap rows
+const autoResolveY = 650
+
+// resultNames are the display names of the battle results
+var resultNames = map[string]string{
+	save.ResultWin:  "勝利",
+	save.ResultDraw: "引き分け",
+	save.ResultLoss: "敗北",
+}
+
+// newSetupBattle builds the battle the setup describes the same way the battle scene does, without a screen
+// Overworld battles field the groups of the two armies; otherwise the player's army fields its preset,
+// or the custom army when one is given, and the enemy its own preset (empty: the player's)
+func newSetupBattle(dataManager *data.DataManager, stageName, presetName, enemyPreset string, army []data.ReinforcementGroupConfig, battle *campaign.Battle, mutatorIDs, doctrineIDs []string, handicaps []game.Handicap, seed int64) (*game.BattleManager, error) {
+	stage, err := dataManager.GetStageConfig(stageConfigID(dataManager, stageName))
+	if err != nil {
+		return nil, err
+	}
+	terrain, err := dataManager.GetTerrainConfig(stage.Terrain)
+	if err != nil {
+		return nil, err
+	}
+	
+	battleManager := game.NewBattleManager(stage, terrain)
+	battleManager.SetRandomSeed(seed)
+	battleManager.SetMutators(mutatorIDs)
+	if battle != nil {
+		setOverworldArmies(battleManager, *battle)
+	}
+	if len(army) > 0 {
+		battleManager.SetArmyGroups(playerArmyID, army)
+	}
+	applyHandicaps(battleManager, handicaps)
+	if err := battleManager.CreateArmies(presetName, enemyPreset, dataManager); err != nil {
+		return nil, err
+	}
+	applyDoctrines(battleManager, dataManager, doctrineIDs)
+	return battleManager, nil
+}
+
+// autoResolveOutcome returns the most frequent result of the simulated battles; even odds count as a draw
+func autoResolveOutcome(result game.AutoResolveResult) string {
+	switch {
+	case result.Wins > result.Losses && result.Wins >= result.Draws:
+		return save.ResultWin
+	case result.Losses > result.Wins && result.Losses >= result.Draws:
+		return save.ResultLoss
+	default:
+		return save.ResultDraw
+	}
+}
+
+// setupForecast is the auto-resolve estimate of one version of the setup, computed in the background
+type setupForecast struct {
+	generation int
+	result     game.AutoResolveResult
+	err        error
+}
+
+// autoResolveSimulation returns the simulated runs of the selected battle at reduced fidelity
+// The setup is copied, so the simulation can run while the player keeps changing it
+func (as *ArmySetupScene) autoResolveSimulation() func() (game.AutoResolveResult, error) {
+	stageName := as.stages[as.selectedStage].Name
+	presetName := as.getPresetName()
+	enemyPreset := as.getEnemyPreset()
+	army := as.getCustomArmy()
+	mutatorIDs := as.getEnabledMutatorIDs()
+	doctrineIDs := as.getDoctrineIDs()
+	handicaps := as.getHandicaps()
+	
+	return func() (game.AutoResolveResult, error) {
+		return game.AutoResolve(func(seed int64) (*game.BattleManager, error) {
+			return newSetupBattle(as.dataManager, stageName, presetName, enemyPreset, army, nil, mutatorIDs, doctrineIDs, handicaps, seed)
+		}, playerArmyID, game.AutoResolveTrials)
+	}
+}
+
+// updateForecast takes in a finished forecast and starts forecasting the setup whenever it has none
+// Forecasts of a setup changed in the meantime are dropped
+func (as *ArmySetupScene) updateForecast() {
+	select {
+	case forecast := <-as.forecasts:
+		as.forecasting = false
+		if forecast.generation == as.setupGeneration {
+			if forecast.err != nil {
+				fmt.Printf("Forecast failed: %v\n", forecast.err)
+				as.forecastFailed = true
+			} else {
+				as.autoResolve = &forecast.result
+			}
+		}
+	default:
+	}
+	
+	if as.autoResolve != nil || as.forecasting || as.forecastFailed {
+		return
+	}
+	as.forecasting = true
+	simulate := as.autoResolveSimulation()
+	generation := as.setupGeneration
+	go func() {
+		result, err := simulate()
+		as.forecasts <- setupForecast{generation, result, err}
+	}()
+}
+
+// invalidateForecast drops the forecast after a change to the setup
+func (as *ArmySetupScene) invalidateForecast() {
+	as.setupGeneration++
+	as.autoResolve = nil
+	as.forecastFailed = false
+}
+
+// autoResolveBattle skips the battle: the forecast's likely outcome is recorded in the profile and campaign
+// as if the battle had been fought, and the result screen shows it; a forecast still running is computed here
+func (as *ArmySetupScene) autoResolveBattle() {
+	if !as.isArmyReady() {
+		return
+	}
+	if as.autoResolve == nil {
+		result, err := as.autoResolveSimulation()()
+		if err != nil {
+			fmt.Printf("Auto-resolve failed: %v\n", err)
+			return
+		}
+		as.autoResolve = &result
+	}
+	as.deployUserPreset()
+	
+	stage := as.stages[as.selectedStage]
+	presetName := as.getPresetName()
+	outcome := autoResolveOutcome(*as.autoResolve)
+	recordBattleResult(stage.ID, presetName, outcome, as.autoResolve.BattleTime, as.dataManager.Recruitment.GetReward(outcome), true)
+	
+	// The result screen can fight the battle for real with 再戦
+	gameData := as.sceneManager.gameData
+	gameData.CurrentStage = stage.Name
+	gameData.CurrentPreset = presetName
+	gameData.EnemyPreset = as.getEnemyPreset()
+	gameData.Army = as.getCustomArmy()
+	gameData.Mutators = as.getEnabledMutatorIDs()
+	gameData.Doctrines = as.getDoctrineIDs()
+	gameData.Handicaps = as.getHandicaps()
+	gameData.Seed = as.seed
+	gameData.Province = ""
+	gameData.Challenge = ""
+	gameData.Rating = ""
+	gameData.Heatmap = nil
+	gameData.Highlights = nil
+	
+	winner := resultNames[save.ResultDraw]
+	switch outcome {
+	case save.ResultWin:
+		winner = as.autoResolve.WinName
+	case save.ResultLoss:
+		winner = as.autoResolve.LossName
+	}
+	gameData.Report = autoResolveReport(as.dataManager, gameData, winner, *as.autoResolve)
+	as.sceneManager.TransitionTo(SceneResult, winner)
+}
+
+// drawAutoResolve draws the forecast of the selected battle: win probability and expected casualties
+func (as *ArmySetupScene) drawAutoResolve(screen *ebiten.Image) {
+	if as.autoResolve == nil {
+		forecastText := "予測: 模擬戦を計算中…"
+		if as.forecastFailed {
+			forecastText = "予測: この設定では模擬戦を行えません"
+		}
+		as.textRenderer.DrawText(screen, forecastText, 100, autoResolveY, graphics.CurrentTheme().TextMuted)
+		return
+	}
+	
+	result := as.autoResolve
+	forecastText := fmt.Sprintf("予測: 勝率%.0f%%（%d勝%d分%d敗）  損害 自軍%.1f/%d体 敵軍%.1f/%d体",
+		result.WinRate()*100, result.Wins, result.Draws, result.Losses,
+		result.Casualties, result.Units, result.EnemyCasualties, result.EnemyUnits)
+	as.textRenderer.DrawText(screen, forecastText, 100, autoResolveY, graphics.CurrentTheme().Text)
+	
+	if as.selectedItem == autoResolveItem {
+		outcome := resultNames[autoResolveOutcome(*result)]
+		confirmText := fmt.Sprintf("自動解決: 戦わずに「%s」として結果画面へ", outcome)
+		as.textRenderer.DrawText(screen, confirmText, 100, autoResolveY+20, graphics.CurrentTheme().TextMuted)
+	}
+}
@@ -0,0 +1,144 @@
+package scenes
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+)
+
+// Bark tuning
+const (
+	barkDuration = 2.0  // 吹き出しを表示する秒数
+	barkFadeTime = 0.5  // 消える前に薄れていく秒数
+	maxBarks     = 4    // 同時に表示する吹き出しの数
+	barkPadding  = 4.0  // 吹き出しの余白
+	barkLift     = 26.0 // ユニットの中心から吹き出しの下端までの高さ
+)
+
+// speechBubble is a short line a unit says, shown above it for a moment
+// It runs on the battle clock, so it lingers in slow motion, stays while paused and goes with a rewind
+type speechBubble struct {
+	unit  *game.Unit
+	text  string
+	until float64 // 吹き出しが消える戦闘時間
+}
+
+// bark makes the unit say one of its lines for the occasion; a unit already talking changes its line
+// Barks are only shown, so they stay out of the simulation and co-op peers need not agree on them
+func (bs *BattleSceneUnified) bark(unit *game.Unit, occasion string) {
+	if unit == nil || !unit.IsAlive || bs.dataManager == nil {
+		return
+	}
+	lines := bs.dataManager.Barks.GetBarkLines(string(unit.Type), occasion)
+	if len(lines) == 0 {
+		return
+	}
+	
+	bubbles := bs.barks[:0]
+	for _, bubble := range bs.barks {
+		if bubble.unit != unit {
+			bubbles = append(bubbles, bubble)
+		}
+	}
+	if len(bubbles) >= maxBarks {
+		bubbles = bubbles[1:]
+	}
+	bs.barks = append(bubbles, speechBubble{unit: unit, text: lines[rand.Intn(len(lines))], until: bs.battleManager.BattleTime + barkDuration})
+}
+
+// barkOrder lets the ordered group answer through its leader, or its first soldier standing
+func (bs *BattleSceneUnified) barkOrder(order game.Order) {
+	group := bs.battleManager.FindGroup(order.ArmyID, order.GroupID)
+	if group == nil {
+		return
+	}
+	for _, unit := range group.GetAllUnits() {
+		if unit.IsAlive && !unit.IsRetreating {
+			bs.bark(unit, data.BarkOrder)
+			return
+		}
+	}
+}
+
+// updateBarks drops the speech bubbles that ran out, or were said after the time the battle was rewound to,
+// and lets one soldier of each allied group that starts to flee cry out
+func (bs *BattleSceneUnified) updateBarks() {
+	now := bs.battleManager.BattleTime
+	bubbles := bs.barks[:0]
+	for _, bubble := range bs.barks {
+		if bubble.until > now && bubble.until-barkDuration <= now && bubble.unit.IsAlive {
+			bubbles = append(bubbles, bubble)
+		}
+	}
+	bs.barks = bubbles
+	
+	// Rewinding drops the later records
+	events := bs.battleManager.Events
+	bs.barkedEvents = min(bs.barkedEvents, len(events))
+	for _, event := range events[bs.barkedEvents:] {
+		if event.Type != game.EventRetreat || bs.isGroupBarking(event.GroupID) {
+			continue
+		}
+		if unit := bs.findAlliedUnit(event.UnitID); unit != nil {
+			bs.bark(unit, data.BarkRout)
+		}
+	}
+	bs.barkedEvents = len(events)
+}
+
+// isGroupBarking reports whether a soldier of the group is already talking
+func (bs *BattleSceneUnified) isGroupBarking(groupID int) bool {
+	for _, bubble := range bs.barks {
+		if bubble.unit.GroupID == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// findAlliedUnit returns the unit of the player's alliance with the ID, or nil
+func (bs *BattleSceneUnified) findAlliedUnit(unitID int) *game.Unit {
+	for _, army := range bs.battleManager.Armies {
+		if !bs.battleManager.AreAllied(playerArmyID, army.ID) {
+			continue
+		}
+		for _, unit := range army.GetAllUnits() {
+			if unit.ID == unitID {
+				return unit
+			}
+		}
+	}
+	return nil
+}
+
+// drawBarks draws the speech bubbles above the units saying them, fading out at the end
+func (bs *BattleSceneUnified) drawBarks(screen *ebiten.Image, transform ebiten.GeoM) {
+	now := bs.battleManager.BattleTime
+	for _, bubble := range bs.barks {
+		if bubble.until <= now || !bs.isUnitVisible(bubble.unit) {
+			continue
+		}
+		alpha := min((bubble.until-now)/barkFadeTime, 1)
+		
+		width, height := bs.textRenderer.MeasureText(bubble.text)
+		x, y := transform.Apply(bubble.unit.Position.X, bubble.unit.Position.Y)
+		left := float32(x - width/2 - barkPadding)
+		top := float32(y - barkLift - height - barkPadding*2)
+		boxWidth, boxHeight := float32(width+barkPadding*2), float32(height+barkPadding*2)
+		
+		fill := color.RGBA{255, 255, 255, uint8(230 * alpha)}
+		vector.DrawFilledRect(screen, left, top, boxWidth, boxHeight, fill, false)
+		vector.StrokeRect(screen, left, top, boxWidth, boxHeight, 1, color.RGBA{40, 40, 40, uint8(255 * alpha)}, false)
+		
+		// 吹き出しの尾
+		tail := float32(x)
+		vector.StrokeLine(screen, tail-3, top+boxHeight, tail, top+boxHeight+6, 1, color.RGBA{40, 40, 40, uint8(255 * alpha)}, false)
+		vector.StrokeLine(screen, tail+3, top+boxHeight, tail, top+boxHeight+6, 1, color.RGBA{40, 40, 40, uint8(255 * alpha)}, false)
+		
+		bs.textRenderer.DrawText(screen, bubble.text, float64(left)+barkPadding, float64(top)+barkPadding, color.RGBA{20, 20, 20, uint8(255 * alpha)})
+	}
+}
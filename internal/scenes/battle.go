@@ -4,21 +4,61 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/campaign"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/config"
 	"github.com/shirou/tinygocha/internal/data"
 	"github.com/shirou/tinygocha/internal/game"
 	"github.com/shirou/tinygocha/internal/graphics"
 	"github.com/shirou/tinygocha/internal/input"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+	"github.com/shirou/tinygocha/internal/netplay"
+	"github.com/shirou/tinygocha/internal/save"
 )
 
+const (
+	// playerArmyID is the army controlled by the player's orders
+	playerArmyID = 0
+	
+	// limitedTacticalPauses is the number of tactical pauses per battle in limited mode
+	limitedTacticalPauses = 3
+)
+
+// Rewind history: the last 30 seconds of a single-player battle, snapshotted 20 times a second
+// (every tick in debug builds, for stepping back one tick at a time)
+const (
+	rewindSeconds  = 30
+	rewindInterval = 0.05
+	rewindStep     = 5.0 // Bキー1回で戻る秒数
+)
+
+// gameSpeedSteps are the battle speeds selectable during a battle
+var gameSpeedSteps = []float64{0.25, 0.5, 0.75, 1.0, 1.5, 2.0}
+
+// Post-processing of the battlefield: the screen edges close in once the player's army morale
+// falls below moraleVignetteStart, a lost battle is drained of color and a paused one partly,
+// and spells being cast glow
+const (
+	moraleVignetteStart = 0.5
+	pauseDesaturation   = 0.7
+	magicGlowRadius     = 14.0
+	screenshotDir       = "screenshots"
+)
+
+// magicGlowColor is the light of a spell being cast at full strength, for spells that set no color of their own
+var magicGlowColor = color.RGBA{155, 89, 182, 255}
+
 // BattleSceneUnified represents the unified battle screen with all features
 type BattleSceneUnified struct {
 	sceneManager     *SceneManager
 	battleManager    *game.BattleManager
 	dataManager      *data.DataManager
+	config           *config.Config
 	textRenderer     *graphics.TextRenderer
 	spriteGenerator  *graphics.SpriteGenerator
 	
@@ -27,22 +67,83 @@ type BattleSceneUnified struct {
 	scrollController *input.ScrollController
 	minimap          *graphics.Minimap
 	
+	// Battlefield drawn offscreen and composited under the HUD
+	worldLayer *ebiten.Image
+	glowLayer  *ebiten.Image // 光の源（ブルーム用）
+	frameLayer *ebiten.Image // 効果をかけた戦場
+	postEffect *graphics.PostEffect
+	
+	// On-screen buttons for mouse-only play
+	hud              *battleHUD
+	rulesStartButton *graphics.Button
+	rulesBackButton  *graphics.Button
+	trapsDoneButton  *graphics.Button
+	
 	// Game state
 	isPaused         bool
+	pause            *pauseScene    // 一時停止中に戦闘の上に重ねるシーン
+	help             *helpScene     // 操作方法を戦闘の上に重ねるシーン
+	settings         *SettingsScene // 一時停止中に開く設定（nil: 設定なし）
 	selectedUnit     *game.Unit
+	inspectedUnit    *game.Unit // 長押しで情報を見ているユニット
 	showDebugInfo    bool
-	showHelp         bool
+	showRulesCard    bool
+	
+	// Stage, preset and random seed of the current battle, for the save files and the report
+	stageID    string
+	presetName string
+	seed       int64
+	
+	// Trap placement before the battle starts
+	placingTraps bool
+	trapKind     string // 設置する罠の種類
+	trapMessage  string // 設置できなかった理由
+	
+	// Tactical pause (作戦タイム)
+	tacticalPause      bool
+	tacticalPausesLeft int
+	
+	// Battle simulation speed multiplier
+	gameSpeed          float64
+	
+	// Recent simulation states for rewinding and stepping back (nil in co-op)
+	history     *game.BattleHistory
+	replayClock float64 // 巻き戻した戦闘の再生で、表示中のスナップショットから進んだ時間
+	
+	// Highlight moments filmed for the reel after the battle, and the reel being watched (nil: fighting)
+	highlights *game.HighlightRecorder
+	reel       *highlightReel
+	reelClip   int     // 再生中の見どころ
+	reelFrame  int     // 再生中のスナップショット
+	reelClock  float64 // 表示中のスナップショットから進んだ時間
+	reelZoom   float64 // 再生前のカメラの拡大率
+	
+	// Co-op battle advancing in step with the partner (nil: single player)
+	lockstep    *netplay.Lockstep
+	coopWaiting bool // 相方の命令が届かず止まっている
+	
+	// Co-op battle replayed from the host's stream (nil: playing)
+	observer     *netplay.Watcher
+	observeLost  bool // 配信が切れた
+	spectateView int  // 観戦の視点（spectateFree またはプレイヤー番号）
+	
+	// Director mode: the camera follows the fiercest fighting by itself
+	director      bool
+	directorFocus gamemath.Vector2D // 映している見どころ
+	directorHold  float64           // 見どころを切り替えてからの秒数
+	
+	// Speech bubbles of units answering the player or fleeing
+	barks        []speechBubble
+	barkedEvents int // 敗走の掛け声を確認済みの戦闘ログの件数
 	
 	// Timing
-	lastUpdate       time.Time
 	deltaTime        float64
-	helpToggleTime   time.Time
 }
 
 // NewBattleSceneUnified creates a new unified battle scene
-func NewBattleSceneUnified(sceneManager *SceneManager, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) *BattleSceneUnified {
-	// Create camera for 5000x5000 world with 1024x768 viewport
-	camera := graphics.NewCameraManager(5000, 5000, 1024, 768)
+func NewBattleSceneUnified(sceneManager *SceneManager, dataManager *data.DataManager, cfg *config.Config, textRenderer *graphics.TextRenderer) *BattleSceneUnified {
+	// Create camera with 1024x768 viewport; each battle resizes the world to its stage
+	camera := graphics.NewCameraManager(data.DefaultStageSize, data.DefaultStageSize, 1024, 768)
 	
 	// Disable smooth movement for immediate response
 	camera.SetSmoothMove(false)
@@ -52,299 +153,1057 @@ func NewBattleSceneUnified(sceneManager *SceneManager, dataManager *data.DataMan
 	
 	fmt.Println("BattleSceneUnified: Camera and ScrollController initialized")
 	
-	return &BattleSceneUnified{
+	spriteGenerator := graphics.NewSpriteGenerator()
+	gameSpeed := 1.0
+	director := false
+	var settings *SettingsScene
+	if cfg != nil {
+		spriteGenerator.SetReduceFlashing(cfg.Graphics.ReduceFlashing)
+		gameSpeed = cfg.Game.GetGameSpeed()
+		director = cfg.Game.DirectorMode
+		settings = NewSettingsScene(sceneManager, NewSettingsMenu(cfg, dataManager, textRenderer))
+	}
+	
+	bs := &BattleSceneUnified{
 		sceneManager:     sceneManager,
 		dataManager:      dataManager,
+		config:           cfg,
 		textRenderer:     textRenderer,
-		spriteGenerator:  graphics.NewSpriteGenerator(),
+		spriteGenerator:  spriteGenerator,
 		camera:           camera,
 		scrollController: scrollController,
-		minimap:          graphics.NewMinimap(camera, 50, 620, 200, 150),
+		minimap:          graphics.NewMinimap(camera, 50, 618, minimapWidth, minimapHeight),
+		worldLayer:       ebiten.NewImage(camera.ViewportWidth, camera.ViewportHeight),
+		glowLayer:        ebiten.NewImage(camera.ViewportWidth, camera.ViewportHeight),
+		frameLayer:       ebiten.NewImage(camera.ViewportWidth, camera.ViewportHeight),
+		postEffect:       graphics.NewPostEffect(),
+		hud:              newBattleHUD(standardHUD),
+		rulesStartButton: graphics.NewButton(0, 0, 100, 28, "戦闘開始"),
+		rulesBackButton:  graphics.NewButton(0, 0, 100, 28, "戻る"),
+		trapsDoneButton:  graphics.NewButton(0, 0, 100, 24, "配置完了"),
 		isPaused:         false,
+		settings:         settings,
+		gameSpeed:        gameSpeed,
+		director:         director,
 		showDebugInfo:    false,
-		showHelp:         false,
-		lastUpdate:       time.Now(),
 	}
+	bs.pause = &pauseScene{battle: bs}
+	bs.help = &helpScene{battle: bs}
+	return bs
 }
 
 // OnEnter is called when entering the scene
 func (bs *BattleSceneUnified) OnEnter(data interface{}) {
-	bs.Initialize()
+	// The result screen can send the finished battle back to watch its highlights
+	if gameData := bs.sceneManager.gameData; gameData.WatchReel && gameData.Highlights != nil {
+		bs.startHighlights(gameData.Highlights)
+		return
+	}
+	
+	// Setting up a big battle takes a while, so it is done behind the loading screen
+	var prepared *preparedBattle
+	bs.sceneManager.PushScene(NewLoadingScene(bs.sceneManager, bs.textRenderer, LoadingTask{
+		Run: func(progress LoadingProgress) {
+			prepared = bs.prepareBattle(progress)
+		},
+		Finish: func() {
+			if prepared != nil {
+				bs.startBattle(prepared)
+			}
+		},
+	}), false)
 }
 
 // OnExit is called when exiting the scene
+// The battle's scripts close here rather than when it ends, since an ended battle can be rewound
 func (bs *BattleSceneUnified) OnExit() {
+	if bs.battleManager != nil {
+		bs.battleManager.Close()
+	}
 	bs.battleManager = nil
+	bs.history = nil
+	bs.highlights = nil
+	bs.reel = nil
+	bs.lockstep = nil
+	bs.observer = nil
+}
+
+// preparedBattle is a battle set up by prepareBattle, for the scene to start on the game loop
+type preparedBattle struct {
+	battleManager *game.BattleManager
+	stageID       string
+	presetName    string
+	seed          int64
+	lockstep      *netplay.Lockstep // 協力プレイの同期（nil: 協力プレイではない）
+	observer      *netplay.Watcher  // 観戦している配信（nil: 観戦ではない）
 }
 
-// Initialize initializes the battle scene
+// Initialize sets up and starts the battle at once, without the loading screen
 func (bs *BattleSceneUnified) Initialize() {
-	if bs.battleManager == nil {
-		fmt.Println("=== Battle Scene Initialize ===")
-		
-		// Get stage and preset from scene manager's game data
-		stageName := bs.sceneManager.gameData.CurrentStage
-		presetName := bs.sceneManager.gameData.CurrentPreset
-		
-		if stageName == "" {
-			stageName = "森の戦い" // Default
-		}
-		if presetName == "" {
-			presetName = "バランス型" // Default
-		}
-		
-		fmt.Printf("Selected Stage: %s\n", stageName)
-		fmt.Printf("Selected Preset: %s\n", presetName)
-		
-		// Map stage names to config names
-		stageConfigMap := map[string]string{
-			"森の戦い": "forest_battle",
-			"山岳要塞": "mountain_fortress", 
-			"平原決戦": "plain_battle",
-		}
-		
-		terrainConfigMap := map[string]string{
-			"森の戦い": "forest",
-			"山岳要塞": "mountain",
-			"平原決戦": "plain",
-		}
-		
-		stageConfigName := stageConfigMap[stageName]
-		terrainConfigName := terrainConfigMap[stageName]
-		
-		if stageConfigName == "" {
-			fmt.Printf("Warning: Unknown stage name '%s', using default\n", stageName)
-			stageConfigName = "forest_battle" // Default
+	if bs.battleManager != nil {
+		return
+	}
+	if prepared := bs.prepareBattle(nil); prepared != nil {
+		bs.startBattle(prepared)
+	}
+}
+
+// prepareBattle sets up the battle chosen in the game data, waiting for the co-op partner or the
+// host's stream first; it leaves the scene alone so it can run off the game loop (nil: it cannot be set up)
+func (bs *BattleSceneUnified) prepareBattle(progress LoadingProgress) *preparedBattle {
+	fmt.Println("=== Battle Scene Initialize ===")
+	
+	// Co-op partners fight the battle the host chose; observers replay it
+	coopSession := bs.sceneManager.gameData.Coop
+	watcher := bs.sceneManager.gameData.Watch
+	var coopSetup *netplay.Setup
+	if coopSession != nil && coopSession.Err() == nil {
+		progress.report(0, "協力プレイの相手を待っています")
+		if setup, err := bs.syncCoopBattle(coopSession); err != nil {
+			fmt.Printf("Co-op: %v, playing alone\n", err)
+		} else {
+			coopSetup = &setup
 		}
-		if terrainConfigName == "" {
-			fmt.Printf("Warning: Unknown terrain name for stage '%s', using default\n", stageName)
-			terrainConfigName = "forest" // Default
+	} else if watcher != nil && watcher.Err() == nil {
+		progress.report(0, "配信を待っています")
+		if setup, err := bs.syncObservedBattle(watcher); err != nil {
+			fmt.Printf("Observer: %v, playing alone\n", err)
+		} else {
+			coopSetup = &setup
 		}
-		
-		fmt.Printf("Looking for stage config: %s\n", stageConfigName)
-		fmt.Printf("Looking for terrain config: %s\n", terrainConfigName)
-		
-		// Debug: List all available stages
-		fmt.Println("Available stages in data manager:")
-		// This would require adding a method to list all stages, but for now let's try the configs directly
-		
-		// Set up stage
-		stageConfig, err := bs.dataManager.GetStageConfig(stageConfigName)
+	}
+	
+	// Get stage and preset from scene manager's game data
+	stageName := bs.sceneManager.gameData.CurrentStage
+	presetName := bs.sceneManager.gameData.CurrentPreset
+	
+	if stageName == "" {
+		stageName = "森の戦い" // Default
+	}
+	if presetName == "" {
+		presetName = "バランス型" // Default
+	}
+	
+	fmt.Printf("Selected Stage: %s\n", stageName)
+	fmt.Printf("Selected Preset: %s\n", presetName)
+	enemyPreset := bs.sceneManager.gameData.EnemyPreset
+	if enemyPreset != "" {
+		fmt.Printf("Selected Enemy Preset: %s\n", enemyPreset)
+	}
+	
+	// Map the stage name to its config name
+	progress.report(0.2, "ステージを読み込んでいます")
+	stageConfigName := stageConfigID(bs.dataManager, stageName)
+	if stageConfigName == "" {
+		fmt.Printf("Warning: Unknown stage name '%s', using default\n", stageName)
+		stageConfigName = "forest_battle" // Default
+	}
+	
+	fmt.Printf("Looking for stage config: %s\n", stageConfigName)
+	fmt.Printf("Available stages in data manager: %v\n", bs.dataManager.GetStageIDs())
+	
+	// Set up stage
+	stageConfig, err := bs.dataManager.GetStageConfig(stageConfigName)
+	if err != nil {
+		fmt.Printf("Error loading stage config '%s': %v\n", stageConfigName, err)
+		fmt.Println("Falling back to forest_battle")
+		stageConfigName = "forest_battle"
+		stageConfig, err = bs.dataManager.GetStageConfig(stageConfigName)
 		if err != nil {
-			fmt.Printf("Error loading stage config '%s': %v\n", stageConfigName, err)
-			fmt.Println("Falling back to forest_battle")
-			stageConfig, err = bs.dataManager.GetStageConfig("forest_battle")
-			if err != nil {
-				fmt.Printf("Error loading fallback stage config: %v\n", err)
-				return
-			}
+			fmt.Printf("Error loading fallback stage config: %v\n", err)
+			return nil
 		}
-		fmt.Printf("Stage loaded: %s\n", stageConfig.Name)
-		
-		terrainConfig, err := bs.dataManager.GetTerrainConfig(terrainConfigName)
+	}
+	fmt.Printf("Stage loaded: %s\n", stageConfig.Name)
+	
+	// The stage names its terrain
+	terrainConfigName := stageConfig.Terrain
+	if terrainConfigName == "" {
+		fmt.Printf("Warning: Stage '%s' has no terrain, using default\n", stageConfigName)
+		terrainConfigName = "forest" // Default
+	}
+	
+	terrainConfig, err := bs.dataManager.GetTerrainConfig(terrainConfigName)
+	if err != nil {
+		fmt.Printf("Error loading terrain config '%s': %v\n", terrainConfigName, err)
+		fmt.Println("Falling back to forest terrain")
+		terrainConfig, err = bs.dataManager.GetTerrainConfig("forest")
 		if err != nil {
-			fmt.Printf("Error loading terrain config '%s': %v\n", terrainConfigName, err)
-			fmt.Println("Falling back to forest terrain")
-			terrainConfig, err = bs.dataManager.GetTerrainConfig("forest")
-			if err != nil {
-				fmt.Printf("Error loading fallback terrain config: %v\n", err)
-				return
-			}
-		}
-		fmt.Printf("Terrain loaded: %s\n", terrainConfig.Name)
-		
-		// Create battle manager with stage and terrain
-		bs.battleManager = game.NewBattleManager(stageConfig, terrainConfig)
-		if bs.battleManager == nil {
-			fmt.Println("Error: Failed to create battle manager")
-			return
-		}
-		fmt.Println("Battle manager created successfully")
-		
-		// Create armies with selected preset
-		fmt.Printf("Creating armies with preset: %s\n", presetName)
-		err1 := bs.battleManager.CreatePresetArmy(0, presetName, bs.dataManager)
-		if err1 != nil {
-			fmt.Printf("Error creating army A: %v\n", err1)
+			fmt.Printf("Error loading fallback terrain config: %v\n", err)
+			return nil
 		}
+	}
+	fmt.Printf("Terrain loaded: %s\n", terrainConfig.Name)
+	
+	// Create battle manager with stage and terrain
+	progress.report(0.4, "戦場を作っています")
+	battleManager := game.NewBattleManager(stageConfig, terrainConfig)
+	if battleManager == nil {
+		fmt.Println("Error: Failed to create battle manager")
+		return nil
+	}
+	fmt.Println("Battle manager created successfully")
+	
+	// Battles from a setup code start from its seed, others from a fresh one
+	seed := bs.sceneManager.gameData.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	
+	// Recorded and replayed battles spawn identically
+	if recordedSeed, ok := controls.Seed(); ok {
+		seed = recordedSeed
+	}
+	
+	// Co-op partners must spawn identically too, since only their orders are exchanged
+	if coopSetup != nil {
+		seed = coopSetup.Seed
+	}
+	battleManager.SetRandomSeed(seed)
+	
+	// Mutators change how units are created, so set them first
+	battleManager.SetMutators(bs.sceneManager.gameData.Mutators)
+	
+	// Overworld battles field the enemy army standing in the province
+	if battle, ok := overworldBattle(bs.sceneManager.gameData); ok {
+		setOverworldArmies(battleManager, battle)
+	}
+	
+	// The custom army fields the groups the player deployed on the recruitment screen
+	if army := bs.sceneManager.gameData.Army; len(army) > 0 {
+		battleManager.SetArmyGroups(playerArmyID, army)
+	}
+	
+	// Handicaps chosen in setup scale the units as they are created
+	applyHandicaps(battleManager, bs.sceneManager.gameData.Handicaps)
+	
+	// Create armies with selected preset
+	progress.report(0.6, "軍勢を編成しています")
+	fmt.Printf("Creating armies with preset: %s\n", presetName)
+	if err := battleManager.CreateArmies(presetName, enemyPreset, bs.dataManager); err != nil {
+		fmt.Printf("Error creating armies: %v\n", err)
+		fmt.Printf("Army creation had errors, but continuing...\n")
+	}
+	
+	// Verify armies were created
+	for _, army := range battleManager.Armies {
+		unitCount := len(army.GetAllUnits())
+		fmt.Printf("%s has %d units\n", army.Name, unitCount)
 		
-		err2 := bs.battleManager.CreatePresetArmy(1, presetName, bs.dataManager)
-		if err2 != nil {
-			fmt.Printf("Error creating army B: %v\n", err2)
+		if unitCount == 0 {
+			fmt.Printf("Warning: %s has no units!\n", army.Name)
 		}
-		
-		if err1 != nil || err2 != nil {
-			fmt.Printf("Army creation had errors, but continuing...\n")
+	}
+	
+	// Doctrines chosen in setup
+	applyDoctrines(battleManager, bs.dataManager, bs.sceneManager.gameData.Doctrines)
+	
+	// Optional hardcore rule: orders cost command points; co-op battles follow the host's setting
+	commandPoints := bs.config != nil && bs.config.Game.CommandPoints
+	if coopSetup != nil {
+		commandPoints = coopSetup.CommandPoints
+	}
+	if commandPoints {
+		battleManager.EnableCommandPoints()
+	}
+	
+	// Optional realism rule: orders reach the members after their leader; co-op battles follow the host's setting
+	commandDelay := bs.config != nil && bs.config.Game.CommandDelay
+	if coopSetup != nil {
+		commandDelay = coopSetup.CommandDelay
+	}
+	if commandDelay {
+		battleManager.EnableCommandDelay()
+	}
+	
+	// Co-op players share the army, each commanding every other group
+	prepared := &preparedBattle{battleManager: battleManager, stageID: stageConfigName, presetName: presetName, seed: seed}
+	if coopSetup != nil {
+		battleManager.SplitGroups(playerArmyID, netplay.Players)
+		if coopSession != nil {
+			prepared.lockstep = netplay.NewLockstep(coopSession)
+		} else {
+			prepared.observer = watcher
 		}
-		
-		// Verify armies were created
-		armyAUnits := bs.battleManager.ArmyA.GetAllUnits()
-		armyBUnits := bs.battleManager.ArmyB.GetAllUnits()
-		fmt.Printf("Army A has %d units, Army B has %d units\n", len(armyAUnits), len(armyBUnits))
-		
-		if len(armyAUnits) == 0 || len(armyBUnits) == 0 {
-			fmt.Println("Warning: One or both armies have no units!")
+	}
+	progress.report(0.9, "戦闘の準備をしています")
+	return prepared
+}
+
+// startBattle puts the prepared battle on the field and readies the scene to fight it
+func (bs *BattleSceneUnified) startBattle(prepared *preparedBattle) {
+	bs.battleManager = prepared.battleManager
+	bs.stageID = prepared.stageID
+	bs.presetName = prepared.presetName
+	bs.seed = prepared.seed
+	bs.lockstep = prepared.lockstep
+	bs.observer = prepared.observer
+	bs.observeLost = false
+	
+	bs.barks = nil
+	bs.barkedEvents = 0
+	
+	// Every battle films its own highlights; the last one's reel is let go
+	bs.highlights = game.NewHighlightRecorder()
+	bs.sceneManager.gameData.Highlights = nil
+	
+	// Single-player battles can be rewound (not co-op, which cannot); debug builds keep every tick
+	bs.history = nil
+	bs.replayClock = 0
+	if !bs.isNetworked() {
+		if debugBuild {
+			bs.history = game.NewBattleHistory(rewindSeconds*60, 0)
+		} else {
+			bs.history = game.NewBattleHistory(int(rewindSeconds/rewindInterval), rewindInterval)
 		}
-		
-		// Start battle
-		bs.battleManager.StartBattle()
-		fmt.Println("Battle started!")
-		
-		// Center camera on battlefield
-		bs.camera.SetPosition(2500, 2500) // Center of 5000x5000 world
 	}
+	
+	// Show rules card; the battle starts once the player confirms it (observers follow the players)
+	bs.showRulesCard = bs.observer == nil
+	bs.placingTraps = false
+	bs.isPaused = false
+	bs.tacticalPause = false
+	bs.tacticalPausesLeft = limitedTacticalPauses
+	
+	// Start the camera where the stage wants it, usually on the player's army
+	stage := bs.battleManager.Stage
+	bs.camera.SetWorldSize(stage.WorldSize())
+	bs.minimap.FitWorld()
+	if stage.Camera.HasBounds() {
+		bs.camera.SetBounds(stage.Camera.BoundsLeft, stage.Camera.BoundsTop, stage.Camera.BoundsRight, stage.Camera.BoundsBottom)
+	} else {
+		bs.camera.ResetBounds()
+	}
+	start := stage.GetCameraStart(playerArmyID)
+	bs.camera.CenterOn(start.X, start.Y)
+	
+	// Director mode starts from where the camera does
+	bs.directorFocus = start
+	bs.directorHold = directorHoldTime
 }
 
 // Update updates the battle scene
 func (bs *BattleSceneUnified) Update() error {
-	// Calculate delta time
-	now := time.Now()
-	if !bs.lastUpdate.IsZero() {
-		bs.deltaTime = now.Sub(bs.lastUpdate).Seconds()
-	}
-	bs.lastUpdate = now
+	// Fixed while input is recorded or replayed
+	bs.deltaTime = controls.DeltaTime()
+	bs.updateHUDPreset()
 	
 	// Update camera first
 	if bs.camera != nil {
 		bs.camera.Update(bs.deltaTime)
 	}
 	
+	// The highlights reel only plays back the finished battle
+	if bs.reel != nil {
+		bs.updateHighlights()
+		return nil
+	}
+	
 	// Update scroll controller (after camera update)
-	if bs.scrollController != nil {
-		bs.scrollController.Update(bs.deltaTime)
+	bs.updateScrolling()
+	
+	// Wait for the rules card to be confirmed before starting
+	if bs.showRulesCard {
+		bs.handleRulesCardInput()
+		return nil
+	}
+	
+	// Minimap click moves the camera
+	if bs.minimap != nil {
+		bs.minimap.Update()
+	}
+	
+	// Traps are laid on the field before the battle starts
+	if bs.placingTraps {
+		bs.handleTrapPlacementInput()
+		return nil
 	}
 	
 	// Handle input
 	bs.handleInput()
+	bs.updateDirector()
+	bs.advance()
+	return nil
+}
+
+// updateScrolling scrolls the camera with the keys, the screen edges and the mouse at the speeds of the settings
+func (bs *BattleSceneUnified) updateScrolling() {
+	if bs.scrollController != nil {
+		if bs.config != nil {
+			bs.scrollController.SetScrollSpeed(bs.config.Controls.GetScrollSpeeds())
+		}
+		bs.scrollController.Update(bs.deltaTime)
+	}
+}
+
+// advance runs the battle for the frame unless it is stopped, and goes on to the result once it ends
+func (bs *BattleSceneUnified) advance() {
+	if bs.battleManager != nil {
+		bs.battleManager.TimeScale = bs.timeScale()
+	}
 	
-	// Update battle if not paused
-	if !bs.isPaused && bs.battleManager != nil {
-		bs.battleManager.Update(bs.deltaTime)
+	// Co-op battles advance only when the partner's orders for the tick have arrived
+	if bs.lockstep != nil && bs.battleManager != nil {
+		if !bs.stepCoop() {
+			return
+		}
+	} else if bs.observer != nil && bs.battleManager != nil {
+		if !bs.stepObserver() {
+			return
+		}
+	} else if bs.battleManager != nil && bs.battleManager.TimeScale > 0 {
+		if bs.history != nil && bs.history.IsRewound() {
+			bs.replayHistory()
+			return
+		}
+		bs.battleManager.Advance(bs.deltaTime)
+		if bs.history != nil {
+			bs.history.Record(bs.battleManager)
+		}
+		bs.recordHighlights()
+	} else {
+		return
+	}
+	
+	// A selected enemy that slipped into the fog is deselected
+	if bs.selectedUnit != nil && !bs.isUnitVisible(bs.selectedUnit) {
+		bs.selectedUnit = nil
+	}
+	bs.updateBarks()
+	
+	// Check if battle ended
+	if !bs.battleManager.IsActive {
+		// A challenge is rated on the result screen, and its stars saved with the result
+		challenge, stars := bs.sceneManager.gameData.Challenge, 0
+		bs.sceneManager.gameData.Rating = ""
+		if challenge != "" {
+			stars = bs.rateChallenge()
+		}
+		if bs.config != nil && bs.config.Game.AutoSave && controls.IsPlayed() && bs.observer == nil {
+			bs.saveBattleResult()
+			if challenge != "" {
+				recordChallengeResult(challenge, stars)
+			}
+		}
+		if bs.sceneManager.gameData.Province != "" && controls.IsPlayed() {
+			resolveOverworldBattle(bs.sceneManager.gameData, bs.battleResult())
+		}
+		if broadcast := bs.sceneManager.gameData.Broadcast; broadcast != nil && bs.lockstep != nil {
+			broadcast.Finish()
+		}
+		winner := bs.battleManager.GetWinnerName()
+		bs.sceneManager.gameData.Heatmap = bs.battleManager.Heatmap
+		bs.sceneManager.gameData.Highlights = newHighlightReel(bs.battleManager, bs.highlights)
+		bs.sceneManager.gameData.Report = bs.battleReport(winner)
+		bs.sceneManager.TransitionWith(SceneResult, winner, resultTransition)
+	}
+}
+
+// applyDoctrines gives the player's army and the hostile armies the doctrines chosen in setup
+func applyDoctrines(battleManager *game.BattleManager, dataManager *data.DataManager, doctrineIDs []string) {
+	for _, army := range battleManager.Armies {
+		side := 1 // 敵軍
+		if army.ID == playerArmyID {
+			side = 0
+		} else if battleManager.AreAllied(playerArmyID, army.ID) {
+			continue
+		}
+		if side >= len(doctrineIDs) || doctrineIDs[side] == "" {
+			continue
+		}
 		
-		// Check if battle ended
-		if !bs.battleManager.IsActive {
-			winner := bs.battleManager.GetWinnerName()
-			bs.sceneManager.TransitionTo(SceneResult, winner)
-			return nil
+		doctrine, err := dataManager.GetDoctrineConfig(doctrineIDs[side])
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		battleManager.SetDoctrine(army.ID, doctrine)
+	}
+}
+
+// applyHandicaps gives the player's army and the hostile armies the handicaps chosen in setup; call it before CreateArmies
+func applyHandicaps(battleManager *game.BattleManager, handicaps []game.Handicap) {
+	for _, army := range battleManager.Armies {
+		side := 1 // 敵軍
+		if army.ID == playerArmyID {
+			side = 0
+		} else if battleManager.AreAllied(playerArmyID, army.ID) {
+			continue
+		}
+		if side < len(handicaps) {
+			battleManager.SetHandicap(army.ID, handicaps[side])
+		}
+	}
+}
+
+// setOverworldArmies makes the player's side field the groups of the player's army in the battle,
+// and every army hostile to the player those of the enemy army
+func setOverworldArmies(battleManager *game.BattleManager, battle campaign.Battle) {
+	for _, army := range battleManager.Armies {
+		groups := battle.EnemyGroups
+		if battleManager.AreAllied(playerArmyID, army.ID) {
+			groups = battle.Player.Groups
+		}
+		battleManager.SetArmyGroups(army.ID, groups)
+	}
+}
+
+// battleResult returns the finished battle's result from the player's side
+func (bs *BattleSceneUnified) battleResult() string {
+	switch {
+	case bs.battleManager.Winner == game.WinnerDraw:
+		return save.ResultDraw
+	case bs.battleManager.AreAllied(bs.battleManager.Winner, playerArmyID):
+		return save.ResultWin
+	default:
+		return save.ResultLoss
+	}
+}
+
+// saveBattleResult records the finished battle in the player's profile and campaign
+func (bs *BattleSceneUnified) saveBattleResult() {
+	result := bs.battleResult()
+	reward := 0
+	overworld := bs.sceneManager.gameData.Province != ""
+	if !overworld {
+		reward = bs.dataManager.Recruitment.GetReward(result)
+	}
+	recordBattleResult(bs.stageID, bs.presetName, result, bs.battleManager.BattleTime, reward, !overworld)
+}
+
+// returnToSetup leaves the battle for the screen it was set up on; an unfought overworld battle stays pending
+func (bs *BattleSceneUnified) returnToSetup() {
+	bs.sceneManager.TransitionTo(setupScene(bs.sceneManager.gameData), nil)
+}
+
+// setupScene returns the screen the battle the game data describes was set up on
+func setupScene(gameData *GameData) SceneType {
+	switch {
+	case gameData.Province != "":
+		return SceneOverworld
+	case gameData.Challenge != "":
+		return SceneChallenge
+	default:
+		return SceneArmySetup
+	}
+}
+
+// recordBattleResult writes a finished or auto-resolved battle to the player's profile and campaign;
+// the gold the battle earned goes to the custom army's purse, and with recordArmy the army's record
+// on the stage is kept for suggestions (overworld armies fight with their own groups instead)
+func recordBattleResult(stageID, presetName, result string, battleTime float64, reward int, recordArmy bool) {
+	profile, err := save.LoadProfile(save.DefaultProfilePath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load profile: %v\n", err)
+	} else {
+		preset, groups := "", []string(nil)
+		if recordArmy {
+			preset, groups = presetName, deployedRecruits(profile, presetName)
+		}
+		profile.RecordBattle(stageID, preset, groups, result, battleTime)
+		profile.Gold += reward
+		if err := profile.Save(save.DefaultProfilePath); err != nil {
+			fmt.Printf("Warning: Failed to save profile: %v\n", err)
 		}
 	}
 	
-	return nil
+	campaign, err := save.LoadCampaign(save.DefaultCampaignPath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load campaign: %v\n", err)
+	} else {
+		campaign.RecordBattle(stageID, presetName, result)
+		if err := campaign.Save(save.DefaultCampaignPath); err != nil {
+			fmt.Printf("Warning: Failed to save campaign: %v\n", err)
+		}
+	}
 }
 
 // handleInput handles user input
 func (bs *BattleSceneUnified) handleInput() {
 	// Handle return to setup (works even if battleManager is nil)
-	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
-		bs.sceneManager.TransitionTo(SceneArmySetup, nil)
+	if controls.IsActionJustPressed(controls.ActionReturnToSetup) || bs.hud.backButton.IsClicked() {
+		bs.returnToSetup()
 		return
 	}
 	
-	// Handle force reinitialize (F5 key)
-	if inpututil.IsKeyJustPressed(ebiten.KeyF5) {
+	// Handle force reinitialize (F5 key); a co-op partner cannot follow a restart
+	if controls.IsKeyJustPressed(ebiten.KeyF5) && !bs.isNetworked() {
 		fmt.Println("Force reinitializing battle scene...")
+		if bs.battleManager != nil {
+			bs.battleManager.Close()
+		}
 		bs.battleManager = nil
 		bs.Initialize()
 		return
 	}
 	
+	bs.handleCameraInput()
+	
+	// Other input handling only if battleManager exists
+	if bs.battleManager == nil {
+		return
+	}
+	
+	// Handle pause (Escape too); iron man and co-op battles cannot pause
+	canPause := !bs.battleManager.IsPauseDisabled() && !bs.isNetworked()
+	pressed := controls.IsActionJustPressed(controls.ActionPause) || controls.IsKeyJustPressed(ebiten.KeyEscape)
+	if (pressed || bs.hud.pauseButton.IsClicked()) && canPause {
+		bs.sceneManager.PushScene(bs.pause, true)
+		return
+	}
+	
+	// Handle tactical pause toggle and battle speed; co-op battles run at a fixed pace
+	if !bs.isNetworked() {
+		if controls.IsActionJustPressed(controls.ActionTacticalPause) || bs.hud.tacticalButton.IsClicked() {
+			bs.toggleTacticalPause()
+		}
+		if controls.IsActionJustPressed(controls.ActionSlower) {
+			bs.stepGameSpeed(-1)
+		}
+		if controls.IsActionJustPressed(controls.ActionFaster) || bs.hud.speedButton.IsClicked() {
+			bs.stepGameSpeed(1)
+		}
+	}
+	
+	// Handle minimap toggle
+	if bs.hud.minimapButton.IsClicked() {
+		bs.minimap.SetVisible(!bs.minimap.IsVisible())
+	}
+	
+	// Handle debug info toggle
+	if controls.IsKeyJustPressed(ebiten.KeyF1) {
+		bs.showDebugInfo = !bs.showDebugInfo
+	}
+	
+	// Save the battlefield without the HUD
+	if controls.IsActionJustPressed(controls.ActionScreenshot) {
+		bs.saveScreenshot()
+	}
+	
+	// Let the camera follow the fighting by itself
+	bs.handleDirectorInput()
+	
+	// Rewind the last seconds of the battle and replay them, or go on from the moment shown (not in iron man)
+	if bs.history != nil && !bs.battleManager.IsPauseDisabled() {
+		if controls.IsActionJustPressed(controls.ActionRewind) {
+			bs.rewindBattle()
+		}
+		if controls.IsActionJustPressed(controls.ActionResume) && bs.history.IsRewound() {
+			bs.history.Branch()
+			bs.battleManager.Announce("ここから戦闘を再開します")
+		}
+	}
+	
+	// Advance the simulation a tick at a time in the tactical pause (the pause steps it by itself)
+	if bs.tacticalPause {
+		bs.handleStepInput()
+	}
+	
+	// Show the help over the battle
+	if controls.IsActionJustPressed(controls.ActionHelp) || bs.hud.helpButton.IsClicked() {
+		bs.sceneManager.PushScene(bs.help, true)
+		return
+	}
+	
+	// Handle unit selection (only left mouse button, middle button is for camera drag)
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && !bs.hud.IsHovered() && !bs.isCursorOverMinimap() {
+		bs.handleUnitSelection()
+	}
+	
+	// A long press on the touch screen shows the unit under the finger without selecting it
+	bs.inspectedUnit = nil
+	if controls.IsLongPressed() {
+		bs.inspectedUnit = bs.unitAt(controls.CursorPosition())
+	}
+	
+	// Observers watch without giving orders
+	if bs.observer != nil {
+		bs.handleSpectateInput()
+		return
+	}
+	
+	// Handle move orders for the player's army
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		bs.handleMoveOrder()
+	}
+	
+	// Order the selected group out of its tower
+	if controls.IsActionJustPressed(controls.ActionEject) {
+		bs.handleEjectOrder()
+	}
+	
+	// Hand the selected group to the co-op partner
+	if controls.IsActionJustPressed(controls.ActionAssignGroup) && bs.lockstep != nil {
+		bs.handleAssignGroup()
+	}
+}
+
+// handleStepInput steps the stopped battle a tick forward, or back through the rewind history
+func (bs *BattleSceneUnified) handleStepInput() {
+	if bs.isNetworked() {
+		return
+	}
+	if bs.history != nil && controls.IsActionJustPressed(controls.ActionStepBack) {
+		bs.history.StepBack(bs.battleManager)
+	}
+	if controls.IsActionJustPressed(controls.ActionStepForward) {
+		bs.stepTick()
+	}
+}
+
+// handleCameraInput moves and zooms the camera with the keys, the mouse wheel and the camera buttons
+func (bs *BattleSceneUnified) handleCameraInput() {
 	// Direct camera control test (temporary)
 	if bs.camera != nil {
 		moveSpeed := 200.0 * bs.deltaTime
 		
-		if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		if controls.IsKeyPressed(ebiten.KeyW) || controls.IsKeyPressed(ebiten.KeyArrowUp) {
 			fmt.Println("Direct camera move: UP")
 			bs.camera.Move(0, -moveSpeed)
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		if controls.IsKeyPressed(ebiten.KeyS) || controls.IsKeyPressed(ebiten.KeyArrowDown) {
 			fmt.Println("Direct camera move: DOWN")
 			bs.camera.Move(0, moveSpeed)
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		if controls.IsKeyPressed(ebiten.KeyA) || controls.IsKeyPressed(ebiten.KeyArrowLeft) {
 			fmt.Println("Direct camera move: LEFT")
 			bs.camera.Move(-moveSpeed, 0)
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		if controls.IsKeyPressed(ebiten.KeyD) || controls.IsKeyPressed(ebiten.KeyArrowRight) {
 			fmt.Println("Direct camera move: RIGHT")
 			bs.camera.Move(moveSpeed, 0)
 		}
 		
 		// Direct zoom test
-		_, wheelY := ebiten.Wheel()
+		_, wheelY := controls.Wheel()
 		if wheelY != 0 {
 			fmt.Printf("Direct zoom: wheelY=%.2f\n", wheelY)
-			mouseX, mouseY := ebiten.CursorPosition()
+			mouseX, mouseY := controls.CursorPosition()
 			bs.camera.ZoomAt(mouseX, mouseY, wheelY*0.25)
 		}
+		
+		bs.handleCameraButtons(moveSpeed)
+	}
+}
+
+// tacticalPauseMode returns the configured tactical pause mode
+func (bs *BattleSceneUnified) tacticalPauseMode() string {
+	if bs.config == nil {
+		return config.TacticalPauseLimited
+	}
+	return bs.config.Game.GetTacticalPauseMode()
+}
+
+// toggleTacticalPause enters or leaves the tactical pause, executing queued orders on leave
+func (bs *BattleSceneUnified) toggleTacticalPause() {
+	if bs.tacticalPause {
+		bs.tacticalPause = false
+		bs.battleManager.ExecuteQueuedOrders()
+		return
 	}
 	
-	// Other input handling only if battleManager exists
-	if bs.battleManager == nil {
+	if bs.battleManager.IsPauseDisabled() {
+		bs.battleManager.Announce("特殊ルール「鉄人」では作戦タイムを使えません")
 		return
 	}
 	
-	// Handle pause (but not Escape if it's used for camera)
-	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
-		bs.isPaused = !bs.isPaused
+	switch bs.tacticalPauseMode() {
+	case config.TacticalPauseDisabled:
+		return
+	case config.TacticalPauseLimited:
+		if bs.tacticalPausesLeft <= 0 {
+			return
+		}
+		bs.tacticalPausesLeft--
 	}
 	
-	// Handle pause with Escape only if not used for camera movement
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		bs.isPaused = !bs.isPaused
+	bs.tacticalPause = true
+}
+
+// stepGameSpeed moves the battle speed to the next or previous step, wrapping around
+func (bs *BattleSceneUnified) stepGameSpeed(delta int) {
+	// Find the step closest to the current speed
+	index := 0
+	for i, speed := range gameSpeedSteps {
+		if math.Abs(speed-bs.gameSpeed) < math.Abs(gameSpeedSteps[index]-bs.gameSpeed) {
+			index = i
+		}
 	}
 	
-	// Handle debug info toggle
-	if inpututil.IsKeyJustPressed(ebiten.KeyF1) {
-		bs.showDebugInfo = !bs.showDebugInfo
+	index = (index + delta + len(gameSpeedSteps)) % len(gameSpeedSteps)
+	bs.gameSpeed = gameSpeedSteps[index]
+}
+
+// handleMoveOrder orders the selected player group to move to the cursor
+func (bs *BattleSceneUnified) handleMoveOrder() {
+	unit := bs.selectedUnit
+	if unit == nil || !unit.IsAlive || unit.ArmyID != playerArmyID {
+		return
 	}
 	
-	// Handle help toggle
-	if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
-		now := time.Now()
-		if now.Sub(bs.helpToggleTime) > 200*time.Millisecond {
-			bs.showHelp = !bs.showHelp
-			bs.helpToggleTime = now
-		}
+	// Ignore clicks on the minimap and HUD
+	if bs.isCursorOverMinimap() || bs.hud.IsHovered() {
+		return
 	}
 	
-	// Handle unit selection (only left mouse button, middle button is for camera drag)
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		bs.handleUnitSelection()
+	mouseX, mouseY := controls.CursorPosition()
+	worldX, worldY := bs.camera.ScreenToWorld(mouseX, mouseY)
+	order := game.Order{
+		Type:    game.OrderMove,
+		ArmyID:  unit.ArmyID,
+		GroupID: unit.GroupID,
+		Target:  gamemath.Vector2D{X: worldX, Y: worldY},
 	}
+	
+	// Right-clicking an allied tower sends the group inside
+	if structure := bs.battleManager.GetStructureAt(order.Target); structure != nil && bs.battleManager.CanGarrison(structure, unit.ArmyID) {
+		order.Type = game.OrderGarrison
+		order.StructureID = structure.ID
+	}
+	bs.issuePlayerOrder(order)
 }
 
-// handleUnitSelection handles unit selection with mouse
-func (bs *BattleSceneUnified) handleUnitSelection() {
-	if bs.battleManager == nil {
+// handleEjectOrder orders the selected group out of its tower
+func (bs *BattleSceneUnified) handleEjectOrder() {
+	unit := bs.selectedUnit
+	if unit == nil || !unit.IsAlive || unit.ArmyID != playerArmyID {
+		return
+	}
+	bs.issuePlayerOrder(game.Order{Type: game.OrderEject, ArmyID: unit.ArmyID, GroupID: unit.GroupID})
+}
+
+// issuePlayerOrder gives an order now, queues it during tactical pause or sends it to the co-op partner
+func (bs *BattleSceneUnified) issuePlayerOrder(order game.Order) {
+	// Co-op orders travel to the partner and run on both sides a few ticks later
+	if bs.lockstep != nil {
+		bs.queueCoopOrder(order)
+		bs.barkOrder(order)
 		return
 	}
 	
-	// Get mouse position
-	mouseX, mouseY := ebiten.CursorPosition()
+	// Orders given while watching a rewind branch the battle from the moment shown
+	if bs.history != nil && bs.history.IsRewound() {
+		bs.history.Branch()
+	}
 	
-	// Convert screen coordinates to world coordinates
-	worldX, worldY := bs.camera.ScreenToWorld(mouseX, mouseY)
+	var accepted bool
+	if bs.tacticalPause {
+		accepted = bs.battleManager.QueueOrder(order)
+	} else {
+		accepted = bs.battleManager.IssueOrder(order)
+	}
 	
-	// Find unit at position
-	bs.selectedUnit = nil
+	if accepted {
+		bs.barkOrder(order)
+	}
 	
-	// Check Army A units
-	for _, unit := range bs.battleManager.ArmyA.GetAllUnits() {
-		if unit.IsAlive && bs.isUnitAtPosition(unit, worldX, worldY) {
-			bs.selectedUnit = unit
+	commandPoints := bs.battleManager.CommandPoints
+	if !accepted && commandPoints != nil && commandPoints.Current < game.GetOrderCost(order) {
+		bs.battleManager.Announce("指揮力が足りません")
+	}
+}
+
+// updateHUDPreset switches to the compact HUD while the window is small or the option asks for it,
+// and back to the standard one
+func (bs *BattleSceneUnified) updateHUDPreset() {
+	preset := standardHUD
+	if bs.sceneManager.gameData.CompactHUD {
+		preset = compactHUD
+	}
+	if bs.hud.preset == preset {
+		return
+	}
+	bs.hud = newBattleHUD(preset)
+	if bs.minimap != nil {
+		bs.minimap.SetPosition(preset.minimapPosition())
+	}
+}
+
+// rewindBattle takes the battle back a few seconds, up to the oldest recorded moment, and replays it from there
+func (bs *BattleSceneUnified) rewindBattle() {
+	if !bs.history.Rewind(bs.battleManager, rewindStep) {
+		return
+	}
+	bs.replayClock = 0
+	if bs.selectedUnit != nil && !bs.selectedUnit.IsAlive {
+		bs.selectedUnit = nil
+	}
+}
+
+// replayHistory plays the recorded snapshots after a rewind at the battle speed; once it catches up
+// with the latest, the simulation takes over again
+func (bs *BattleSceneUnified) replayHistory() {
+	bs.replayClock += bs.battleManager.ScaledTime(bs.deltaTime)
+	for {
+		next, ok := bs.history.NextTime()
+		if !ok {
+			bs.replayClock = 0
+			return
+		}
+		gap := next - bs.battleManager.BattleTime
+		if gap > bs.replayClock {
 			return
 		}
+		bs.replayClock -= gap
+		bs.history.StepForward(bs.battleManager)
+	}
+}
+
+// stepTick advances the paused battle by one tick, replaying the next recorded one if it was stepped back
+func (bs *BattleSceneUnified) stepTick() {
+	if bs.history != nil && bs.history.StepForward(bs.battleManager) {
+		return
+	}
+	bs.battleManager.Update(game.FrameSeconds(bs.deltaTime) * bs.gameSpeed)
+	if bs.history != nil {
+		bs.history.Record(bs.battleManager)
+	}
+	bs.recordHighlights()
+}
+
+// timeScale returns how fast battle time should run against real time: 0 while the battle is paused and
+// the battle speed otherwise. It is handed to the battle manager every frame, whose TimeScale everything
+// on the battle clock follows
+func (bs *BattleSceneUnified) timeScale() float64 {
+	if bs.isPaused || bs.tacticalPause {
+		return 0
+	}
+	return bs.gameSpeed
+}
+
+// isCursorOverMinimap reports whether the mouse cursor is over the visible minimap
+func (bs *BattleSceneUnified) isCursorOverMinimap() bool {
+	if bs.minimap == nil || !bs.minimap.IsVisible() {
+		return false
+	}
+	
+	mouseX, mouseY := controls.CursorPosition()
+	x, y, w, h := bs.minimap.GetBounds()
+	return mouseX >= x && mouseX < x+w && mouseY >= y && mouseY < y+h
+}
+
+// handleCameraButtons moves and zooms the camera with the HUD buttons
+func (bs *BattleSceneUnified) handleCameraButtons(moveSpeed float64) {
+	if bs.hud.preset.cameraPad {
+		if bs.hud.upButton.IsHeld() {
+			bs.camera.Move(0, -moveSpeed)
+		}
+		if bs.hud.downButton.IsHeld() {
+			bs.camera.Move(0, moveSpeed)
+		}
+		if bs.hud.leftButton.IsHeld() {
+			bs.camera.Move(-moveSpeed, 0)
+		}
+		if bs.hud.rightButton.IsHeld() {
+			bs.camera.Move(moveSpeed, 0)
+		}
+	}
+	
+	// Zoom around the screen center
+	if bs.hud.zoomInButton.IsClicked() {
+		bs.camera.ZoomAt(512, 384, 0.25)
+	}
+	if bs.hud.zoomOutButton.IsClicked() {
+		bs.camera.ZoomAt(512, 384, -0.25)
+	}
+}
+
+// rulesCardBounds returns the screen rectangle of the rules card and lays out its buttons
+func (bs *BattleSceneUnified) rulesCardBounds() (x, y, width, height int) {
+	lines := bs.battleManager.GetRulesSummary()
+	
+	width = 480
+	height = 120 + len(lines)*20
+	x = (1024 - width) / 2
+	y = (768 - height) / 2
+	
+	bs.rulesStartButton.X, bs.rulesStartButton.Y = x+width-230, y+height-42
+	bs.rulesBackButton.X, bs.rulesBackButton.Y = x+width-120, y+height-42
+	return x, y, width, height
+}
+
+// handleRulesCardInput handles confirmation of the pre-battle rules card
+func (bs *BattleSceneUnified) handleRulesCardInput() {
+	if bs.battleManager != nil {
+		bs.rulesCardBounds()
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) || controls.IsKeyJustPressed(ebiten.KeyR) || (bs.battleManager != nil && bs.rulesBackButton.IsClicked()) {
+		bs.showRulesCard = false
+		bs.returnToSetup()
+		return
 	}
 	
-	// Check Army B units
-	for _, unit := range bs.battleManager.ArmyB.GetAllUnits() {
-		if unit.IsAlive && bs.isUnitAtPosition(unit, worldX, worldY) {
-			bs.selectedUnit = unit
+	if bs.battleManager == nil {
+		return
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) || bs.rulesStartButton.IsClicked() {
+		bs.showRulesCard = false
+		if bs.canPlaceTraps() {
+			bs.startTrapPlacement()
 			return
 		}
+		bs.battleManager.StartBattle()
+		fmt.Println("Battle started!")
+	}
+}
+
+// handleUnitSelection handles unit selection with mouse
+// The touch screen has no right button, so a tap on open ground moves the selected player group
+func (bs *BattleSceneUnified) handleUnitSelection() {
+	if bs.battleManager == nil {
+		return
+	}
+	
+	unit := bs.unitAt(controls.CursorPosition())
+	selected := bs.selectedUnit
+	if unit == nil && controls.IsTouch() && bs.observer == nil && selected != nil && selected.IsAlive && selected.ArmyID == playerArmyID {
+		bs.handleMoveOrder()
+		return
+	}
+	
+	bs.selectedUnit = unit
+	if unit != nil && unit.ArmyID == playerArmyID {
+		bs.bark(unit, data.BarkSelect)
 	}
 }
 
+// unitAt returns the visible living unit at the screen position, or nil
+func (bs *BattleSceneUnified) unitAt(screenX, screenY int) *game.Unit {
+	if bs.battleManager == nil {
+		return nil
+	}
+	
+	// Convert screen coordinates to world coordinates
+	worldX, worldY := bs.camera.ScreenToWorld(screenX, screenY)
+	
+	// Check units of every army and the neutral creatures
+	armies := append([]*game.Army{}, bs.battleManager.Armies...)
+	for _, army := range append(armies, bs.battleManager.Neutrals) {
+		for _, unit := range army.GetAllUnits() {
+			if unit.IsAlive && bs.isUnitVisible(unit) && bs.isUnitAtPosition(unit, worldX, worldY) {
+				return unit
+			}
+		}
+	}
+	return nil
+}
+
+// isUnitVisible reports whether the player can see the unit through the fog of war
+// Observers on the free camera see the whole battlefield
+func (bs *BattleSceneUnified) isUnitVisible(unit *game.Unit) bool {
+	if bs.observer != nil && bs.spectateView == spectateFree {
+		return true
+	}
+	return bs.battleManager.IsVisibleTo(playerArmyID, unit)
+}
+
+// isNetworked reports whether the battle is shared over the network, which fixes its pace
+func (bs *BattleSceneUnified) isNetworked() bool {
+	return bs.lockstep != nil || bs.observer != nil
+}
+
 // isUnitAtPosition checks if a unit is at the given world position
 func (bs *BattleSceneUnified) isUnitAtPosition(unit *game.Unit, worldX, worldY float64) bool {
 	size := 16.0 // Default unit size
@@ -357,58 +1216,218 @@ func (bs *BattleSceneUnified) isUnitAtPosition(unit *game.Unit, worldX, worldY f
 func (bs *BattleSceneUnified) Draw(screen *ebiten.Image) {
 	if bs.battleManager == nil {
 		// Show loading message with more details
-		screen.Fill(color.RGBA{44, 62, 80, 255})
-		bs.textRenderer.DrawCenteredText(screen, "戦闘準備中...", 512, 300, color.RGBA{236, 240, 241, 255})
+		screen.Fill(graphics.CurrentTheme().Background)
+		bs.textRenderer.DrawCenteredText(screen, "戦闘準備中...", 512, 300, graphics.CurrentTheme().Text)
 		
 		// Show selected stage and preset
 		if bs.sceneManager.gameData.CurrentStage != "" {
 			stageText := fmt.Sprintf("ステージ: %s", bs.sceneManager.gameData.CurrentStage)
-			bs.textRenderer.DrawCenteredText(screen, stageText, 512, 350, color.RGBA{149, 165, 166, 255})
+			bs.textRenderer.DrawCenteredText(screen, stageText, 512, 350, graphics.CurrentTheme().TextMuted)
 		}
 		
 		if bs.sceneManager.gameData.CurrentPreset != "" {
-			presetText := fmt.Sprintf("編成: %s", bs.sceneManager.gameData.CurrentPreset)
-			bs.textRenderer.DrawCenteredText(screen, presetText, 512, 380, color.RGBA{149, 165, 166, 255})
+			presetText := "編成: " + presetSummary(bs.sceneManager.gameData)
+			bs.textRenderer.DrawCenteredText(screen, presetText, 512, 380, graphics.CurrentTheme().TextMuted)
 		}
 		
 		// Show hint to return
-		bs.textRenderer.DrawCenteredText(screen, "Rキーで設定に戻る  F5キーで再初期化", 512, 450, color.RGBA{149, 165, 166, 255})
+		bs.textRenderer.DrawCenteredText(screen, "Rキー/戻るボタンで設定に戻る  F5キーで再初期化", 512, 450, graphics.CurrentTheme().TextMuted)
+		bs.hud.backButton.Draw(screen, bs.textRenderer)
+		return
+	}
+	
+	// Draw the battlefield offscreen, then composite it with the post-processing passes
+	transform := bs.camera.GetTransform()
+	bs.drawWorld(bs.worldLayer)
+	bs.postEffect.Draw(bs.frameLayer, bs.worldLayer, bs.drawGlow(transform), bs.postEffectParams())
+	
+	// Speech bubbles stay readable at night
+	bs.drawBarks(bs.frameLayer, transform)
+	screen.DrawImage(bs.frameLayer, nil)
+	
+	// The highlights reel shows the battlefield with its caption only
+	if bs.reel != nil {
+		bs.drawHighlightBanner(screen)
 		return
 	}
 	
+	// Draw UI (not affected by camera transform)
+	bs.drawStatusBar(screen)
+	bs.drawUI(screen)
+	bs.drawAnnouncements(screen)
+	
+	// Draw overlays
+	if bs.showDebugInfo {
+		bs.drawDebugInfo(screen)
+	}
+	
+	if bs.tacticalPause && !bs.isPaused {
+		bs.drawTacticalPauseBanner(screen)
+	} else if bs.history != nil && bs.history.IsRewound() {
+		bs.drawRewindBanner(screen)
+	}
+	
+	if bs.placingTraps {
+		bs.drawTrapPlacementBanner(screen)
+	}
+	
+	if bs.showRulesCard {
+		bs.drawRulesCard(screen)
+	}
+}
+
+// drawWorld draws everything under the camera onto the world layer
+func (bs *BattleSceneUnified) drawWorld(world *ebiten.Image) {
 	// Clear screen
-	screen.Fill(color.RGBA{20, 40, 20, 255}) // Dark green background
+	world.Fill(color.RGBA{20, 40, 20, 255}) // Dark green background
 	
 	// Get camera transform
 	transform := bs.camera.GetTransform()
 	
 	// Draw battlefield
-	bs.drawBattlefield(screen, transform)
+	bs.drawBattlefield(world, transform)
+	
+	// Draw objective zones and capture points
+	bs.drawObjectives(world, transform)
+	bs.drawCapturePoints(world, transform)
+	bs.drawSupplyPoints(world, transform)
+	
+	// Draw gates, walls and towers
+	bs.drawStructures(world, transform)
+	
+	// Draw the traps the player knows of
+	bs.drawTraps(world, transform)
+	
+	// Draw units and the spells flying between them
+	bs.drawUnits(world, transform)
+	bs.drawProjectiles(world, transform)
 	
-	// Draw units
-	bs.drawUnits(screen, transform)
+	// Draw trees and boulders over the units passing behind them
+	bs.drawObstacles(world, transform)
 	
 	// Draw selected unit range
 	if bs.selectedUnit != nil && bs.selectedUnit.IsAlive {
-		bs.drawUnitRange(screen, transform)
+		bs.drawUnitRange(world, transform)
 	}
 	
-	// Draw UI (not affected by camera transform)
-	bs.drawStatusBar(screen)
-	bs.drawUI(screen)
+	// Draw active and queued orders
+	bs.drawOrders(world, transform)
+	
+	// Tint the battlefield at dusk and night, unless the shader shades it
+	if !bs.shadesNight() {
+		bs.drawNightTint(world)
+	}
+}
+
+// graphicsOptions returns the graphics settings, or the defaults without a config
+func (bs *BattleSceneUnified) graphicsOptions() config.GraphicsConfig {
+	if bs.config == nil {
+		return config.DefaultConfig().Graphics
+	}
+	return bs.config.Graphics
+}
+
+// shadesNight reports whether dusk and night are shaded by the post-processing shader
+func (bs *BattleSceneUnified) shadesNight() bool {
+	return bs.postEffect.Supported() && bs.graphicsOptions().NightShading
+}
+
+// postEffectParams returns the strengths of the post-processing passes the options turn on
+func (bs *BattleSceneUnified) postEffectParams() graphics.PostEffectParams {
+	options := bs.graphicsOptions()
+	params := graphics.PostEffectParams{}
+	if bs.shadesNight() {
+		params.Night = bs.battleManager.GetDarkness()
+	}
+	if options.Bloom {
+		params.Bloom = 1
+	}
+	if options.PauseDesaturate && bs.isPaused {
+		params.Grayscale = pauseDesaturation
+	}
+	if options.MoraleVignette {
+		params.Vignette = bs.moraleVignette()
+		params.Grayscale = max(params.Grayscale, bs.defeatGrayscale())
+	}
+	return params
+}
+
+// drawGlow draws the light of the spells being cast and in flight onto the glow layer, in each spell's color,
+// or returns nil when nothing blooms. Channeled spells brighten as they build up; with reduced flashing ordinary casts do not flash
+func (bs *BattleSceneUnified) drawGlow(transform ebiten.GeoM) *ebiten.Image {
+	if !bs.postEffect.Supported() || !bs.graphicsOptions().Bloom {
+		return nil
+	}
+	bs.glowLayer.Clear()
 	
-	// Draw overlays
-	if bs.showDebugInfo {
-		bs.drawDebugInfo(screen)
+	units := bs.battleManager.Neutrals.GetAllUnits()
+	for _, army := range bs.battleManager.Armies {
+		units = append(units, army.GetAllUnits()...)
 	}
 	
-	if bs.showHelp {
-		bs.drawHelp(screen)
+	zoom := bs.camera.GetZoom()
+	for _, unit := range units {
+		if !unit.IsAlive || unit.CastingAbility() == nil || !bs.isUnitVisible(unit) {
+			continue
+		}
+		
+		var strength float64
+		switch {
+		case unit.IsChanneling():
+			strength = 0.4 + 0.6*unit.GetChannelProgress()
+		case unit.Animation.Type == graphics.AnimationAttack && !bs.spriteGenerator.ReduceFlashing:
+			strength = 0.8
+		default:
+			continue
+		}
+		
+		x, y := transform.Apply(unit.Position.X, unit.Position.Y)
+		glow := scaleGlow(spellColor(unit.CastingAbility()), strength)
+		vector.DrawFilledCircle(bs.glowLayer, float32(x), float32(y), float32(magicGlowRadius*zoom), glow, true)
 	}
 	
-	if bs.isPaused {
-		bs.drawPauseOverlay(screen)
+	// Spells in flight trail a smaller light
+	for _, projectile := range bs.battleManager.Projectiles {
+		if !bs.isProjectileVisible(projectile) {
+			continue
+		}
+		x, y := transform.Apply(projectile.Position.X, projectile.Position.Y)
+		glow := scaleGlow(spellColor(projectile.Ability), 0.8)
+		vector.DrawFilledCircle(bs.glowLayer, float32(x), float32(y), float32(magicGlowRadius*0.6*zoom), glow, true)
+	}
+	return bs.glowLayer
+}
+
+// moraleVignette returns how far the screen edges close in as the player's army loses heart (0.0-1.0)
+// Observers on the free camera watch without it
+func (bs *BattleSceneUnified) moraleVignette() float64 {
+	if bs.observer != nil && bs.spectateView == spectateFree {
+		return 0
+	}
+	army := bs.battleManager.GetArmy(playerArmyID)
+	if army == nil || !bs.battleManager.IsActive {
+		return 0
+	}
+	return max(0, min((moraleVignetteStart-army.GetMorale())/(moraleVignetteStart-game.ArmyMoraleCollapse), 1))
+}
+
+// defeatGrayscale drains the color from the battlefield once the player's side has lost
+func (bs *BattleSceneUnified) defeatGrayscale() float64 {
+	if bs.battleManager.IsActive || bs.battleResult() != save.ResultLoss {
+		return 0
+	}
+	return 1
+}
+
+// saveScreenshot writes the battlefield as last drawn, without the HUD, to the screenshot folder
+func (bs *BattleSceneUnified) saveScreenshot() {
+	filename, err := graphics.SaveScreenshot(bs.frameLayer, screenshotDir)
+	if err != nil {
+		fmt.Printf("Failed to save screenshot: %v\n", err)
+		bs.battleManager.Announce("スクリーンショットを保存できませんでした")
+		return
 	}
+	bs.battleManager.Announce(fmt.Sprintf("スクリーンショットを保存しました: %s", filename))
 }
 
 // drawBattlefield draws the battlefield background
@@ -431,8 +1450,8 @@ func (bs *BattleSceneUnified) drawBattlefield(screen *ebiten.Image, transform eb
 		bgColor = color.RGBA{34, 139, 34, 255} // Default green
 	}
 	
-	// Create a large background image
-	bg := ebiten.NewImage(5000, 5000)
+	// Create a background image covering the stage
+	bg := ebiten.NewImage(int(bs.camera.WorldWidth), int(bs.camera.WorldHeight))
 	bg.Fill(bgColor)
 	
 	// Draw with camera transform
@@ -440,18 +1459,77 @@ func (bs *BattleSceneUnified) drawBattlefield(screen *ebiten.Image, transform eb
 	op.GeoM = transform
 	screen.DrawImage(bg, op)
 	
+	// Draw slow and impassable terrain areas
+	bs.drawTerrainAreas(screen, transform)
+	bs.drawFire(screen, transform)
+	
 	// Draw grid pattern for reference
 	bs.drawGrid(screen, transform)
 }
 
+// drawTerrainAreas shades the stage's terrain areas, darker the slower they are
+func (bs *BattleSceneUnified) drawTerrainAreas(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	for _, area := range bs.battleManager.Stage.TerrainAreas {
+		x, y := transform.Apply(area.X1, area.Y1)
+		width := float32((area.X2 - area.X1) * zoom)
+		height := float32((area.Y2 - area.Y1) * zoom)
+		vector.DrawFilledRect(screen, float32(x), float32(y), width, height, terrainAreaColor(area.MovementModifier), false)
+	}
+}
+
+// drawFire draws burnt ground dark and burning cells as flickering flames
+func (bs *BattleSceneUnified) drawFire(screen *ebiten.Image, transform ebiten.GeoM) {
+	terrain := bs.battleManager.Terrain
+	zoom := bs.camera.GetZoom()
+	for index, fire := range terrain.Fire {
+		if fire <= 0 && !terrain.Burnt[index] {
+			continue
+		}
+		origin, cellSize := terrain.CellOrigin(index)
+		x, y := transform.Apply(origin.X, origin.Y)
+		size := float32(cellSize * zoom)
+		
+		// 燃え尽きるまでは炎を揺らめかせ、燃え尽きたら焦げ跡を残す
+		tint := color.RGBA{30, 24, 20, 200}
+		if fire > 0 {
+			flicker := 0.75 + 0.25*math.Sin(bs.battleManager.BattleTime*12+float64(index))
+			tint = color.RGBA{uint8(230 * flicker), uint8(90 * flicker), 0, uint8(230 * flicker)}
+		}
+		vector.DrawFilledRect(screen, float32(x), float32(y), size, size, tint, false)
+	}
+}
+
+// drawObstacles draws the stage's trees and boulders as props
+func (bs *BattleSceneUnified) drawObstacles(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	for _, obstacle := range bs.battleManager.Terrain.Obstacles {
+		x, y := transform.Apply(obstacle.Position.X, obstacle.Position.Y)
+		radius := float32(obstacle.Radius * zoom)
+		switch obstacle.Kind {
+		case data.ObstacleRock:
+			vector.DrawFilledCircle(screen, float32(x), float32(y), radius, color.RGBA{127, 140, 141, 255}, true)
+			vector.DrawFilledCircle(screen, float32(x)-radius*0.25, float32(y)-radius*0.25, radius*0.5, color.RGBA{149, 165, 166, 255}, true)
+			vector.StrokeCircle(screen, float32(x), float32(y), radius, 2, color.RGBA{70, 80, 80, 255}, true)
+		default:
+			// 木: 幹の上に枝葉を重ねる
+			vector.DrawFilledCircle(screen, float32(x), float32(y), radius*0.4, color.RGBA{110, 70, 40, 255}, true)
+			vector.DrawFilledCircle(screen, float32(x), float32(y), radius*1.3, color.RGBA{30, 110, 50, 200}, true)
+			vector.StrokeCircle(screen, float32(x), float32(y), radius*1.3, 1, color.RGBA{20, 70, 30, 255}, true)
+		}
+	}
+}
+
 // drawGrid draws a reference grid
 func (bs *BattleSceneUnified) drawGrid(screen *ebiten.Image, transform ebiten.GeoM) {
 	gridSize := 100
 	gridColor := color.RGBA{255, 255, 255, 32} // Very transparent white
 	
+	worldWidth, worldHeight := int(bs.camera.WorldWidth), int(bs.camera.WorldHeight)
+	
 	// Draw vertical lines
-	for x := 0; x < 5000; x += gridSize {
-		line := ebiten.NewImage(1, 5000)
+	for x := 0; x < worldWidth; x += gridSize {
+		line := ebiten.NewImage(1, worldHeight)
 		line.Fill(gridColor)
 		
 		op := &ebiten.DrawImageOptions{}
@@ -461,8 +1539,8 @@ func (bs *BattleSceneUnified) drawGrid(screen *ebiten.Image, transform ebiten.Ge
 	}
 	
 	// Draw horizontal lines
-	for y := 0; y < 5000; y += gridSize {
-		line := ebiten.NewImage(5000, 1)
+	for y := 0; y < worldHeight; y += gridSize {
+		line := ebiten.NewImage(worldWidth, 1)
 		line.Fill(gridColor)
 		
 		op := &ebiten.DrawImageOptions{}
@@ -472,23 +1550,331 @@ func (bs *BattleSceneUnified) drawGrid(screen *ebiten.Image, transform ebiten.Ge
 	}
 }
 
+// drawObjectives draws the zones of the stage victory conditions
+func (bs *BattleSceneUnified) drawObjectives(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	
+	for _, objective := range bs.battleManager.Objectives {
+		if objective.Config.Type == data.VictoryConvoy {
+			bs.drawConvoyRoute(screen, transform, objective)
+			continue
+		}
+		if !objective.HasZone() {
+			continue
+		}
+		
+		// Color by the army holding the zone
+		zoneColor := color.RGBA{255, 255, 255, 160}
+		if objective.Holder >= 0 {
+			zoneColor = armyColor(objective.Holder)
+			zoneColor.A = 200
+		}
+		
+		center := objective.Center()
+		x, y := transform.Apply(center.X, center.Y)
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(objective.Config.Radius*zoom), 3, zoneColor, true)
+	}
+}
+
+// drawConvoyRoute draws the stretch of road the convoy has still to cover, ending in its destination
+func (bs *BattleSceneUnified) drawConvoyRoute(screen *ebiten.Image, transform ebiten.GeoM, objective *game.Objective) {
+	route := objective.ConvoyRoute()
+	if len(route) < 2 {
+		return
+	}
+	routeColor := armyColor(objective.Convoy.ArmyID)
+	routeColor.A = 160
+	
+	for i := 1; i < len(route); i++ {
+		bs.drawOrderLine(screen, transform, route[i-1], route[i], routeColor)
+	}
+	
+	destination := route[len(route)-1]
+	radius := max(objective.Config.Radius, 40)
+	x, y := transform.Apply(destination.X, destination.Y)
+	vector.StrokeCircle(screen, float32(x), float32(y), float32(radius*bs.camera.GetZoom()), 3, routeColor, true)
+}
+
+// drawSupplyPoints draws the supply points in their army's color
+func (bs *BattleSceneUnified) drawSupplyPoints(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	for _, point := range bs.battleManager.Stage.SupplyPoints {
+		x, y := transform.Apply(point.X, point.Y)
+		pointColor := armyColor(point.ArmyID())
+		
+		// Restocking area and a supply crate in the middle
+		areaColor := pointColor
+		areaColor.A = 60
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(point.GetRadius()*zoom), 2, areaColor, true)
+		size := float32(30 * zoom)
+		vector.DrawFilledRect(screen, float32(x)-size/2, float32(y)-size/2, size, size, color.RGBA{160, 110, 60, 255}, false)
+		vector.StrokeRect(screen, float32(x)-size/2, float32(y)-size/2, size, size, 2, pointColor, false)
+	}
+}
+
+// drawStructures draws standing structures with their durability, and rubble where they fell
+func (bs *BattleSceneUnified) drawStructures(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	for _, structure := range bs.battleManager.Structures {
+		x, y := transform.Apply(structure.Min.X, structure.Min.Y)
+		width := float32((structure.Max.X - structure.Min.X) * zoom)
+		height := float32((structure.Max.Y - structure.Min.Y) * zoom)
+		if structure.IsDestroyed {
+			vector.DrawFilledRect(screen, float32(x), float32(y), width, height, color.RGBA{90, 80, 70, 140}, false)
+			continue
+		}
+		
+		// 門は木、城壁と櫓は石。縁取りは所有する軍勢の色
+		fill := color.RGBA{120, 120, 125, 255}
+		if structure.Kind == data.StructureGate {
+			fill = color.RGBA{130, 85, 45, 255}
+		}
+		vector.DrawFilledRect(screen, float32(x), float32(y), width, height, fill, false)
+		vector.StrokeRect(screen, float32(x), float32(y), width, height, 2, armyColor(structure.ArmyID), false)
+		if structure.Kind == data.StructureTower {
+			center := structure.Center()
+			cx, cy := transform.Apply(center.X, center.Y)
+			rangeColor := armyColor(structure.ArmyID)
+			rangeColor.A = 50
+			vector.StrokeCircle(screen, float32(cx), float32(cy), float32(structure.Range*zoom), 1, rangeColor, true)
+			
+			// Garrison headcount above towers that can be manned
+			if structure.Capacity > 0 {
+				garrisonText := fmt.Sprintf("%d/%d", len(bs.battleManager.GetGarrison(structure)), structure.Capacity)
+				bs.textRenderer.DrawCenteredText(screen, garrisonText, cx, y-24, graphics.CurrentTheme().Text)
+			}
+		}
+		
+		// Durability bar above damaged structures
+		if health := structure.GetHealthPercentage(); health < 1 {
+			vector.DrawFilledRect(screen, float32(x), float32(y)-8, width, 4, color.RGBA{60, 60, 60, 200}, false)
+			vector.DrawFilledRect(screen, float32(x), float32(y)-8, width*float32(health), 4, color.RGBA{231, 76, 60, 255}, false)
+		}
+	}
+}
+
+// drawCapturePoints draws capture points with their ownership and control
+func (bs *BattleSceneUnified) drawCapturePoints(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	
+	for _, point := range bs.battleManager.CapturePoints {
+		center := point.Center()
+		x, y := transform.Apply(center.X, center.Y)
+		radius := float32(point.Config.Radius * zoom)
+		
+		// Fill shows the army building control and its progress
+		controlColor := armyColor(point.Controller)
+		controlColor.A = uint8(40 + 80*point.Control)
+		vector.DrawFilledCircle(screen, float32(x), float32(y), radius, controlColor, true)
+		
+		// Outline shows the owner
+		vector.StrokeCircle(screen, float32(x), float32(y), radius, 3, armyColor(point.Owner), true)
+		
+		if point.Config.Name != "" {
+			bs.textRenderer.DrawCenteredText(screen, point.Config.Name, x, y, graphics.CurrentTheme().TextBright)
+		}
+	}
+}
+
+// objectiveMarkerColor is the minimap color of victory condition zones
+var objectiveMarkerColor = color.RGBA{241, 196, 15, 255} // #F1C40F
+
+// reinforcementWarningTime is how many seconds before a timed wave arrives its minimap arrow blinks
+const reinforcementWarningTime = 10.0
+
+// neutralCreatureColor is the display color of neutral monsters and bandits
+var neutralCreatureColor = color.RGBA{142, 68, 173, 255} // #8E44AD
+
+// armyColor returns the display color of an army (-1: neutral)
+func armyColor(armyID int) color.RGBA {
+	switch armyID {
+	case 0:
+		return color.RGBA{231, 76, 60, 255}
+	case 1:
+		return color.RGBA{41, 128, 185, 255}
+	case 2:
+		return color.RGBA{39, 174, 96, 255}
+	case 3:
+		return color.RGBA{243, 156, 18, 255}
+	default:
+		return color.RGBA{236, 240, 241, 255}
+	}
+}
+
+// terrainAreaColor returns the shade of a terrain area; impassable ground is nearly opaque
+func terrainAreaColor(movement float64) color.RGBA {
+	if movement <= 0 {
+		return color.RGBA{40, 30, 20, 230}
+	}
+	alpha := math.Max(0, math.Min(1-movement, 1)) * 160
+	return color.RGBA{0, 0, 0, uint8(alpha)}
+}
+
+// updateMinimapMarkers pushes battle markers to the minimap
+func (bs *BattleSceneUnified) updateMinimapMarkers() {
+	var markers []graphics.MinimapMarker
+	
+	// Units in their army colors
+	for _, army := range bs.battleManager.Armies {
+		unitColor := armyColor(army.ID)
+		for _, unit := range army.GetAliveUnits() {
+			if !bs.isUnitVisible(unit) {
+				continue
+			}
+			markers = append(markers, graphics.MinimapMarker{
+				X:     unit.Position.X,
+				Y:     unit.Position.Y,
+				Size:  2,
+				Color: unitColor,
+			})
+		}
+	}
+	for _, unit := range bs.battleManager.Neutrals.GetAliveUnits() {
+		if !bs.isUnitVisible(unit) {
+			continue
+		}
+		markers = append(markers, graphics.MinimapMarker{
+			X:     unit.Position.X,
+			Y:     unit.Position.Y,
+			Size:  3,
+			Color: neutralCreatureColor,
+		})
+	}
+	
+	// Capture points: owner outline, control progress arc
+	for _, point := range bs.battleManager.CapturePoints {
+		markers = append(markers, graphics.MinimapMarker{
+			X:             point.Config.X,
+			Y:             point.Config.Y,
+			Size:          8,
+			Color:         armyColor(point.Owner),
+			Shape:         graphics.MarkerRing,
+			Radius:        point.Config.Radius,
+			Progress:      point.Control,
+			ProgressColor: armyColor(point.Controller),
+		})
+	}
+	
+	markers = append(markers, bs.getObjectiveMarkers()...)
+	markers = append(markers, bs.getReinforcementMarkers()...)
+	
+	bs.minimap.SetMarkers(markers)
+}
+
+// getObjectiveMarkers returns minimap markers for the stage victory conditions
+func (bs *BattleSceneUnified) getObjectiveMarkers() []graphics.MinimapMarker {
+	var markers []graphics.MinimapMarker
+	for _, objective := range bs.battleManager.Objectives {
+		switch objective.Config.Type {
+		case data.VictoryCaptureZone:
+			marker := graphics.MinimapMarker{
+				X:      objective.Config.X,
+				Y:      objective.Config.Y,
+				Size:   8,
+				Color:  objectiveMarkerColor,
+				Shape:  graphics.MarkerRing,
+				Radius: objective.Config.Radius,
+			}
+			if objective.Holder >= 0 && objective.Config.Duration > 0 {
+				marker.Progress = objective.Progress / objective.Config.Duration
+				marker.ProgressColor = armyColor(objective.Holder)
+			}
+			markers = append(markers, marker)
+		case data.VictoryEscort:
+			markers = append(markers, graphics.MinimapMarker{
+				X:     objective.Config.X,
+				Y:     objective.Config.Y,
+				Size:  10,
+				Color: objectiveMarkerColor,
+				Shape: graphics.MarkerDiamond,
+			})
+		case data.VictoryConvoy:
+			// Mark the destination the convoy is bound for
+			if route := objective.ConvoyRoute(); len(route) > 0 {
+				destination := route[len(route)-1]
+				markers = append(markers, graphics.MinimapMarker{
+					X:     destination.X,
+					Y:     destination.Y,
+					Size:  10,
+					Color: objectiveMarkerColor,
+					Shape: graphics.MarkerDiamond,
+				})
+			}
+		case data.VictoryCommander:
+			// Mark the commanders that must fall
+			for _, army := range bs.battleManager.Armies {
+				commander := army.GetCommander()
+				if commander == nil || !commander.IsAlive {
+					continue
+				}
+				markers = append(markers, graphics.MinimapMarker{
+					X:     commander.Position.X,
+					Y:     commander.Position.Y,
+					Size:  10,
+					Color: armyColor(army.ID),
+					Shape: graphics.MarkerDiamond,
+				})
+			}
+		}
+	}
+	return markers
+}
+
+// getReinforcementMarkers returns arrows at the arrival points of reinforcements still to come
+// Arrows blink once a timed wave is about to arrive
+func (bs *BattleSceneUnified) getReinforcementMarkers() []graphics.MinimapMarker {
+	var markers []graphics.MinimapMarker
+	bm := bs.battleManager
+	center := gamemath.Vector2D{X: float64(bm.Stage.Width) / 2, Y: float64(bm.Stage.Height) / 2}
+	blinkOff := int(bm.BattleTime*4)%2 == 1
+	
+	for _, wave := range bm.Reinforcements {
+		if wave.Arrived {
+			continue
+		}
+		
+		imminent := wave.Config.Trigger == "" || wave.Config.Trigger == data.TriggerTime
+		imminent = imminent && bm.BattleTime >= wave.Config.TriggerTime-reinforcementWarningTime
+		if imminent && blinkOff {
+			continue
+		}
+		
+		spawn := gamemath.Vector2D{X: wave.Config.X, Y: wave.Config.Y}
+		markers = append(markers, graphics.MinimapMarker{
+			X:     spawn.X,
+			Y:     spawn.Y,
+			Size:  10,
+			Color: armyColor(wave.Config.ArmyID()),
+			Shape: graphics.MarkerArrow,
+			Angle: center.Sub(spawn).Angle(),
+		})
+	}
+	return markers
+}
+
 // drawUnits draws all units
 func (bs *BattleSceneUnified) drawUnits(screen *ebiten.Image, transform ebiten.GeoM) {
-	// Draw Army A units (red)
-	for _, unit := range bs.battleManager.ArmyA.GetAllUnits() {
-		if unit.IsAlive {
-			bs.drawUnit(screen, unit, transform, color.RGBA{231, 76, 60, 255})
+	// Draw each army in its own color
+	for _, army := range bs.battleManager.Armies {
+		unitColor := armyColor(army.ID)
+		for _, unit := range army.GetAllUnits() {
+			if unit.IsAlive && bs.isUnitVisible(unit) {
+				bs.drawUnit(screen, unit, transform, unitColor)
+			}
 		}
 	}
 	
-	// Draw Army B units (blue)
-	for _, unit := range bs.battleManager.ArmyB.GetAllUnits() {
-		if unit.IsAlive {
-			bs.drawUnit(screen, unit, transform, color.RGBA{41, 128, 185, 255})
+	// Draw neutral creatures
+	for _, unit := range bs.battleManager.Neutrals.GetAllUnits() {
+		if unit.IsAlive && bs.isUnitVisible(unit) {
+			bs.drawUnit(screen, unit, transform, neutralCreatureColor)
 		}
 	}
 }
 
+// flyingSpriteLift is how far above its shadow a flying unit is drawn
+const flyingSpriteLift = 12.0
+
 // drawUnit draws a single unit
 func (bs *BattleSceneUnified) drawUnit(screen *ebiten.Image, unit *game.Unit, transform ebiten.GeoM, baseColor color.RGBA) {
 	// Determine unit color
@@ -511,14 +1897,51 @@ func (bs *BattleSceneUnified) drawUnit(screen *ebiten.Image, unit *game.Unit, tr
 	// Generate unit sprite
 	sprite := bs.spriteGenerator.GenerateUnitSprite(string(unit.Type), unitColor, unit.IsLeader, unit.Animation)
 	
+	// Flyers cast a shadow on the ground and are drawn lifted above it
+	spriteTransform := transform
+	if unit.Flying {
+		x, y := transform.Apply(unit.Position.X, unit.Position.Y)
+		radius := float32(unit.GetCollisionRadius() * bs.camera.GetZoom())
+		vector.DrawFilledCircle(screen, float32(x), float32(y), radius, color.RGBA{0, 0, 0, 90}, true)
+		
+		spriteTransform = ebiten.GeoM{}
+		spriteTransform.Translate(0, -flyingSpriteLift)
+		spriteTransform.Concat(transform)
+	}
+	
 	// Draw unit
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(unit.Position.X-8, unit.Position.Y-8) // Center the sprite
-	op.GeoM.Concat(transform)
+	op.GeoM.Concat(spriteTransform)
+	if unit.IsSummoned() {
+		op.ColorScale.ScaleAlpha(0.6) // 召喚されたユニットは半透明
+	}
+	if unit.Hidden {
+		op.ColorScale.ScaleAlpha(0.4) // 潜伏中のユニットは薄く表示
+	}
 	screen.DrawImage(sprite, op)
 	
 	// Draw health bar
-	bs.drawHealthBar(screen, unit, transform)
+	bs.drawHealthBar(screen, unit, spriteTransform)
+	
+	// The leader carries the mark of a surrounded group
+	if unit.IsLeader && unit.Surrounded {
+		bs.drawSurroundedIcon(screen, unit, spriteTransform)
+	}
+}
+
+// drawSurroundedIcon draws a red ring with arrows closing in above the unit
+func (bs *BattleSceneUnified) drawSurroundedIcon(screen *ebiten.Image, unit *game.Unit, transform ebiten.GeoM) {
+	x, y := transform.Apply(unit.Position.X, unit.Position.Y-30)
+	zoom := float32(bs.camera.GetZoom())
+	cx, cy := float32(x), float32(y)
+	iconColor := color.RGBA{231, 76, 60, 255}
+	
+	vector.DrawFilledCircle(screen, cx, cy, 5*zoom, color.RGBA{0, 0, 0, 160}, true)
+	vector.StrokeCircle(screen, cx, cy, 2*zoom, zoom, iconColor, true)
+	for _, dir := range [][2]float32{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		vector.StrokeLine(screen, cx+dir[0]*5*zoom, cy+dir[1]*5*zoom, cx+dir[0]*3*zoom, cy+dir[1]*3*zoom, zoom, iconColor, true)
+	}
 }
 
 // drawHealthBar draws a unit's health bar
@@ -560,6 +1983,22 @@ func (bs *BattleSceneUnified) drawHealthBar(screen *ebiten.Image, unit *game.Uni
 	op.GeoM.Translate(unit.Position.X-size/2, unit.Position.Y-size/2-8)
 	op.GeoM.Concat(transform)
 	screen.DrawImage(bgBar, op)
+	
+	// Magic users show their mana in a thinner bar below
+	if unit.UsesMana() {
+		x, y := transform.Apply(unit.Position.X-size/2, unit.Position.Y-size/2-8+float64(barHeight))
+		zoom := float32(bs.camera.GetZoom())
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(size)*zoom, 2*zoom, color.RGBA{40, 40, 60, 255}, false)
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(size*unit.GetManaRatio())*zoom, 2*zoom, color.RGBA{52, 152, 219, 255}, false)
+	}
+	
+	// A channeled spell shows how far it has built up above the health bar
+	if unit.IsChanneling() {
+		x, y := transform.Apply(unit.Position.X-size/2, unit.Position.Y-size/2-14)
+		zoom := float32(bs.camera.GetZoom())
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(size)*zoom, 3*zoom, color.RGBA{40, 20, 60, 255}, false)
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(size*unit.GetChannelProgress())*zoom, 3*zoom, color.RGBA{155, 89, 182, 255}, false)
+	}
 }
 
 // drawUnitRange draws the selected unit's attack range
@@ -596,7 +2035,7 @@ func (bs *BattleSceneUnified) drawStatusBar(screen *ebiten.Image) {
 	// Background for status bar
 	statusBarHeight := 60
 	statusBar := ebiten.NewImage(1024, statusBarHeight)
-	statusBar.Fill(color.RGBA{52, 73, 94, 255}) // #34495E
+	statusBar.Fill(graphics.CurrentTheme().Panel)
 	screen.DrawImage(statusBar, nil)
 	
 	// Time display
@@ -604,32 +2043,109 @@ func (bs *BattleSceneUnified) drawStatusBar(screen *ebiten.Image) {
 	minutes := int(remainingTime) / 60
 	seconds := int(remainingTime) % 60
 	timeText := fmt.Sprintf("時間: %02d:%02d", minutes, seconds)
-	bs.textRenderer.DrawText(screen, timeText, 20, 20, color.RGBA{236, 240, 241, 255})
+	bs.textRenderer.DrawText(screen, timeText, 20, 20, graphics.CurrentTheme().Text)
 	
 	// Stage name
 	stageText := bs.battleManager.Stage.Name + " (" + bs.battleManager.TerrainData.Name + ")"
-	bs.textRenderer.DrawText(screen, stageText, 200, 20, color.RGBA{236, 240, 241, 255})
+	if bs.battleManager.HasDayNight() {
+		stageText += "  " + timeOfDayText(bs.battleManager)
+	}
+	bs.textRenderer.DrawText(screen, stageText, 200, 20, graphics.CurrentTheme().Text)
+	
+	// Wind arrow and strength, so archers can be placed upwind
+	if bs.battleManager.HasWind() {
+		bs.drawWindIndicator(screen, 470, 22)
+	}
 	
-	// Army A info
-	armyAText := "軍勢A"
-	bs.textRenderer.DrawText(screen, armyAText, 500, 20, color.RGBA{236, 240, 241, 255})
-	bs.drawArmyHealthBar(screen, 580, 25, bs.battleManager.ArmyA.GetTotalHealth(), color.RGBA{231, 76, 60, 255})
+	// Army health and morale, one column per army in the right half of the bar
+	armies := bs.battleManager.Armies
+	moraleColor := graphics.CurrentTheme().Highlight
+	columnWidth := 500
+	if len(armies) > 0 {
+		columnWidth = 500 / len(armies)
+	}
+	barWidth := columnWidth - 80
+	if barWidth > 120 {
+		barWidth = 120
+	}
 	
-	// Army B info
-	armyBText := "軍勢B"
-	bs.textRenderer.DrawText(screen, armyBText, 750, 20, color.RGBA{236, 240, 241, 255})
-	bs.drawArmyHealthBar(screen, 830, 25, bs.battleManager.ArmyB.GetTotalHealth(), color.RGBA{41, 128, 185, 255})
+	var counts, scores []string
+	for i, army := range armies {
+		x := 500 + i*columnWidth
+		label := data.ArmyLabel(army.ID)
+		
+		bs.textRenderer.DrawText(screen, "軍勢"+label, float64(x), 20, graphics.CurrentTheme().Text)
+		bs.drawArmyHealthBar(screen, x+80, 25, barWidth, army.GetTotalHealth(), armyColor(army.ID))
+		bs.textRenderer.DrawText(screen, "士気", float64(x+30), 40, graphics.CurrentTheme().Text)
+		bs.drawArmyHealthBar(screen, x+80, 42, barWidth, army.GetMorale(), moraleColor)
+		
+		counts = append(counts, fmt.Sprintf("%s:%d", label, len(army.GetAllUnits())))
+		scores = append(scores, fmt.Sprintf("%s:%.0f", label, bs.battleManager.Scores[army.ID]))
+	}
 	
 	// Unit counts
-	armyACount := len(bs.battleManager.ArmyA.GetAllUnits())
-	armyBCount := len(bs.battleManager.ArmyB.GetAllUnits())
-	countText := fmt.Sprintf("ユニット数 A:%d B:%d", armyACount, armyBCount)
-	bs.textRenderer.DrawText(screen, countText, 200, 40, color.RGBA{255, 255, 0, 255})
+	countText := "ユニット数 " + strings.Join(counts, " ")
+	bs.textRenderer.DrawText(screen, countText, 200, 40, graphics.CurrentTheme().Debug)
+	
+	// Victory score from capture points
+	if len(bs.battleManager.CapturePoints) > 0 {
+		scoreText := "戦果 " + strings.Join(scores, " ")
+		bs.textRenderer.DrawText(screen, scoreText, 20, 40, graphics.CurrentTheme().Text)
+	}
+}
+
+// drawWindIndicator draws an arrow pointing where the wind blows, centered on (x, y), with its speed below
+func (bs *BattleSceneUnified) drawWindIndicator(screen *ebiten.Image, x, y float32) {
+	wind := bs.battleManager.Wind
+	direction := wind.Normalize()
+	windColor := graphics.CurrentTheme().Accent
+	
+	const half = 12
+	tipX, tipY := x+float32(direction.X)*half, y+float32(direction.Y)*half
+	vector.StrokeLine(screen, x-float32(direction.X)*half, y-float32(direction.Y)*half, tipX, tipY, 2, windColor, true)
+	
+	// Arrowhead wings swept back from the tip
+	for _, side := range []float64{-1, 1} {
+		angle := math.Atan2(direction.Y, direction.X) + math.Pi + side*math.Pi/6
+		vector.StrokeLine(screen, tipX, tipY, tipX+float32(math.Cos(angle))*6, tipY+float32(math.Sin(angle))*6, 2, windColor, true)
+	}
+	
+	windText := fmt.Sprintf("風%.0fm/s", wind.Length()/10)
+	bs.textRenderer.DrawCenteredText(screen, windText, float64(x), float64(y)+18, graphics.CurrentTheme().Text)
+}
+
+// timeOfDayText returns the clock and whether it is day or night, like "18:30 夕"
+func timeOfDayText(bm *game.BattleManager) string {
+	hour := bm.GetHour()
+	period := "昼"
+	switch darkness := bm.GetDarkness(); {
+	case darkness >= 1:
+		period = "夜"
+	case darkness > 0 && hour >= 12:
+		period = "夕"
+	case darkness > 0:
+		period = "暁"
+	}
+	return fmt.Sprintf("%02d:%02d %s", int(hour), int(hour*60)%60, period)
+}
+
+// drawNightTint darkens the battlefield, warm at dusk and dawn and deep blue at night
+func (bs *BattleSceneUnified) drawNightTint(screen *ebiten.Image) {
+	darkness := bs.battleManager.GetDarkness()
+	if darkness <= 0 {
+		return
+	}
+	
+	// 夕焼け色から夜の藍色へ移り変わる（色は乗算済みアルファ）
+	lerp := func(from, to float64) uint8 {
+		return uint8((from + (to-from)*darkness) * darkness * 150 / 255)
+	}
+	tint := color.RGBA{lerp(140, 10), lerp(70, 20), lerp(30, 70), uint8(darkness * 150)}
+	vector.DrawFilledRect(screen, 0, 0, 1024, 768, tint, false)
 }
 
 // drawArmyHealthBar draws an army's total health bar
-func (bs *BattleSceneUnified) drawArmyHealthBar(screen *ebiten.Image, x, y int, health float64, barColor color.Color) {
-	barWidth := 120
+func (bs *BattleSceneUnified) drawArmyHealthBar(screen *ebiten.Image, x, y, barWidth int, health float64, barColor color.Color) {
 	barHeight := 15
 	
 	// Background
@@ -653,7 +2169,7 @@ func (bs *BattleSceneUnified) drawArmyHealthBar(screen *ebiten.Image, x, y int,
 	
 	// Border
 	border := ebiten.NewImage(barWidth, 1)
-	border.Fill(color.RGBA{255, 255, 255, 255})
+	border.Fill(graphics.CurrentTheme().TextBright)
 	
 	// Top and bottom borders
 	op1 := &ebiten.DrawImageOptions{}
@@ -666,7 +2182,7 @@ func (bs *BattleSceneUnified) drawArmyHealthBar(screen *ebiten.Image, x, y int,
 	
 	// Side borders
 	sideBorder := ebiten.NewImage(1, barHeight)
-	sideBorder.Fill(color.RGBA{255, 255, 255, 255})
+	sideBorder.Fill(graphics.CurrentTheme().TextBright)
 	
 	op3 := &ebiten.DrawImageOptions{}
 	op3.GeoM.Translate(float64(x), float64(y))
@@ -679,19 +2195,126 @@ func (bs *BattleSceneUnified) drawArmyHealthBar(screen *ebiten.Image, x, y int,
 
 // drawUI draws the user interface
 func (bs *BattleSceneUnified) drawUI(screen *ebiten.Image) {
+	// The compact HUD draws its text larger
+	scale := bs.textRenderer.GetScale()
+	bs.textRenderer.SetScale(scale * bs.hud.preset.textScale)
+	defer bs.textRenderer.SetScale(scale)
+	
 	// Draw minimap
 	if bs.minimap != nil {
+		bs.updateMinimapMarkers()
 		bs.minimap.Draw(screen)
 	}
 	
 	// Draw selected unit info
 	if bs.selectedUnit != nil && bs.selectedUnit.IsAlive {
 		bs.drawSelectedUnitInfo(screen)
+		if bs.hud.preset.history {
+			bs.drawSelectedUnitHistory(screen)
+		}
+	}
+	if bs.inspectedUnit != nil && bs.inspectedUnit.IsAlive {
+		bs.drawInspectedUnitInfo(screen)
+	}
+	
+	// Draw command point meter
+	if bs.battleManager.CommandPoints != nil {
+		bs.drawCommandPoints(screen)
+	}
+	
+	// Draw the victory conditions and how far each has come
+	if len(bs.battleManager.Objectives) > 0 {
+		bs.drawObjectivePanel(screen)
+	}
+	
+	// Draw co-op connection state
+	if bs.lockstep != nil {
+		bs.drawCoopStatus(screen)
+	} else if bs.observer != nil {
+		bs.drawObserverStatus(screen)
+	}
+	if bs.director {
+		bs.drawDirectorStatus(screen)
+	}
+	
+	// Draw on-screen buttons
+	bs.hud.pauseButton.Active = bs.isPaused
+	bs.hud.tacticalButton.Active = bs.tacticalPause
+	bs.hud.minimapButton.Active = bs.minimap != nil && bs.minimap.IsVisible()
+	bs.hud.helpButton.Active = bs.sceneManager.IsPushed(bs.help)
+	bs.hud.speedButton.Label = fmt.Sprintf("x%g", bs.gameSpeed)
+	bs.hud.speedButton.Active = bs.gameSpeed != 1.0
+	bs.hud.Draw(screen, bs.textRenderer)
+	if !bs.hud.preset.hints || controls.GamepadActive() {
+		return
 	}
 	
 	// Draw controls
-	controlsText := "P/Esc: 一時停止  R: 設定に戻る  F1: デバッグ  F2: ヘルプ"
-	bs.textRenderer.DrawText(screen, controlsText, 300, 740, color.RGBA{255, 255, 255, 255})
+	common := fmt.Sprintf("%s: 設定に戻る  F1: デバッグ  %s: ヘルプ", keyName(controls.ActionReturnToSetup), keyName(controls.ActionHelp))
+	controlsText := fmt.Sprintf("%s: 作戦タイム  右クリック: 移動命令  %s/Esc: 一時停止  %s",
+		keyName(controls.ActionTacticalPause), keyName(controls.ActionPause), common)
+	if bs.lockstep != nil {
+		controlsText = fmt.Sprintf("右クリック: 自分の部隊に移動命令  %s: 部隊を相方に渡す  %s", keyName(controls.ActionAssignGroup), common)
+	} else if bs.observer != nil {
+		controlsText = "V: 視点切替  WASD: カメラ移動  " + common
+	}
+	bs.textRenderer.DrawText(screen, controlsText, 300, 740, graphics.CurrentTheme().TextBright)
+}
+
+// drawObjectivePanel lists the stage victory conditions with their progress below the status bar, on the right
+func (bs *BattleSceneUnified) drawObjectivePanel(screen *ebiten.Image) {
+	// Conditions without progress to report take a single row
+	objectives := bs.battleManager.Objectives
+	statuses := make([]string, len(objectives))
+	rows := len(objectives)
+	for i, objective := range objectives {
+		statuses[i] = objective.Status(bs.battleManager.BattleTime)
+		if statuses[i] != "" {
+			rows++
+		}
+	}
+	
+	row := bs.textRenderer.Spacing(18)
+	panelWidth := 300.0
+	panelHeight := 30 + row*float64(rows)
+	panelX, panelY := float64(hudScreenWidth)-panelWidth-10, 70.0
+	vector.DrawFilledRect(screen, float32(panelX), float32(panelY), float32(panelWidth), float32(panelHeight),
+		graphics.WithAlpha(graphics.CurrentTheme().Panel, 200), false)
+	
+	y := panelY + 5
+	bs.textRenderer.DrawText(screen, "勝利条件:", panelX+10, y, graphics.CurrentTheme().Text)
+	for i, objective := range objectives {
+		y += row
+		bs.textRenderer.DrawText(screen, objective.Description(), panelX+10, y, graphics.CurrentTheme().Text)
+		if statuses[i] != "" {
+			y += row
+			bs.textRenderer.DrawText(screen, statuses[i], panelX+24, y, graphics.CurrentTheme().Highlight)
+		}
+	}
+}
+
+// drawCommandPoints draws the command point meter
+func (bs *BattleSceneUnified) drawCommandPoints(screen *ebiten.Image) {
+	commandPoints := bs.battleManager.CommandPoints
+	
+	cpText := fmt.Sprintf("指揮力: %d/%.0f", int(commandPoints.Current), commandPoints.Max)
+	x, y := bs.hud.meterX, bs.hud.meterY
+	bs.textRenderer.DrawText(screen, cpText, float64(x), float64(y), graphics.CurrentTheme().Text)
+	bs.drawArmyHealthBar(screen, x, y+int(20*bs.hud.preset.textScale), 200, commandPoints.GetRatio(), graphics.CurrentTheme().Magic)
+}
+
+// drawAnnouncements draws recent battle messages below the status bar
+func (bs *BattleSceneUnified) drawAnnouncements(screen *ebiten.Image) {
+	const displayTime = 4.0
+	
+	y := 90.0
+	for _, announcement := range bs.battleManager.Announcements {
+		if bs.battleManager.BattleTime-announcement.Time > displayTime {
+			continue
+		}
+		bs.textRenderer.DrawCenteredText(screen, announcement.Text, 512, y, graphics.CurrentTheme().Highlight)
+		y += bs.textRenderer.Spacing(24)
+	}
 }
 
 // drawSelectedUnitInfo draws information about the selected unit
@@ -701,14 +2324,39 @@ func (bs *BattleSceneUnified) drawSelectedUnitInfo(screen *ebiten.Image) {
 		return
 	}
 	
-	// Background
-	infoX := 300
-	infoY := 620
-	infoWidth := 300
-	infoHeight := 100
+	headerText := "選択ユニット:"
+	if bs.lockstep != nil && unit.ArmyID == playerArmyID {
+		headerText += " " + bs.coopControllerText(unit)
+	}
+	infoWidth, infoHeight := bs.unitInfoSize()
+	infoX, infoY := graphics.AnchorBottomLeft.Place(hudScreenWidth, hudScreenHeight, 300, 18, infoWidth, infoHeight)
+	bs.drawUnitInfo(screen, unit, headerText, infoX, infoY)
+}
+
+// drawInspectedUnitInfo draws information about the long-pressed unit above the finger
+func (bs *BattleSceneUnified) drawInspectedUnitInfo(screen *ebiten.Image) {
+	infoWidth, infoHeight := bs.unitInfoSize()
+	x, y := controls.CursorPosition()
+	infoX := max(0, min(x-infoWidth/2, hudScreenWidth-infoWidth))
+	infoY := max(0, y-infoHeight-40)
+	bs.drawUnitInfo(screen, bs.inspectedUnit, "ユニット情報:", infoX, infoY)
+}
+
+// unitInfoSize returns the size of the unit information panel, grown with the HUD text
+func (bs *BattleSceneUnified) unitInfoSize() (int, int) {
+	scale := bs.hud.preset.textScale
+	return int(300 * scale), int(130 * scale)
+}
+
+// drawUnitInfo draws the unit information panel with the header at the screen position
+func (bs *BattleSceneUnified) drawUnitInfo(screen *ebiten.Image, unit *game.Unit, headerText string, infoX, infoY int) {
+	// Background, grown with the HUD text
+	scale := bs.hud.preset.textScale
+	row := int(15 * scale)
+	infoWidth, infoHeight := bs.unitInfoSize()
 	
 	infoBg := ebiten.NewImage(infoWidth, infoHeight)
-	infoBg.Fill(color.RGBA{52, 73, 94, 200}) // Semi-transparent
+	infoBg.Fill(graphics.WithAlpha(graphics.CurrentTheme().Panel, 200)) // Semi-transparent
 	
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(infoX), float64(infoY))
@@ -716,22 +2364,114 @@ func (bs *BattleSceneUnified) drawSelectedUnitInfo(screen *ebiten.Image) {
 	
 	// Unit info
 	y := infoY + 10
-	bs.textRenderer.DrawText(screen, "選択ユニット:", float64(infoX+10), float64(y), color.RGBA{236, 240, 241, 255})
-	y += 20
+	bs.textRenderer.DrawText(screen, headerText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	y += int(20 * scale)
 	
-	unitTypeText := fmt.Sprintf("種別: %s", unit.Type)
+	unitTypeText := fmt.Sprintf("種別: %s #%d", unit.Type, unit.ID)
 	if unit.IsLeader {
 		unitTypeText += " (リーダー)"
 	}
-	bs.textRenderer.DrawText(screen, unitTypeText, float64(infoX+10), float64(y), color.RGBA{236, 240, 241, 255})
-	y += 15
+	if unit.Flying {
+		unitTypeText += " (飛行)"
+	}
+	if unit.Hidden {
+		unitTypeText += " (潜伏中)"
+	}
+	if unit.IsChanneling() {
+		unitTypeText += " (詠唱中)"
+	}
+	if unit.IsStunned() {
+		unitTypeText += " (気絶)"
+	}
+	if unit.AwaitingOrder() {
+		unitTypeText += " (命令待ち)"
+	}
+	if unit.Surrounded {
+		unitTypeText += " (包囲)"
+	}
+	bs.textRenderer.DrawText(screen, unitTypeText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	y += row
 	
 	healthText := fmt.Sprintf("HP: %d/%d", unit.HP, unit.MaxHP)
-	bs.textRenderer.DrawText(screen, healthText, float64(infoX+10), float64(y), color.RGBA{236, 240, 241, 255})
-	y += 15
+	bs.textRenderer.DrawText(screen, healthText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	y += row
 	
 	attackText := fmt.Sprintf("攻撃力: %d  射程: %.0f", unit.AttackPower, unit.Range)
-	bs.textRenderer.DrawText(screen, attackText, float64(infoX+10), float64(y), color.RGBA{236, 240, 241, 255})
+	if unit.Garrison != nil {
+		attackText += "（櫓に駐留中）"
+	}
+	bs.textRenderer.DrawText(screen, attackText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	y += row
+	
+	staminaText := fmt.Sprintf("スタミナ: %.0f/%.0f", unit.Stamina, unit.MaxStamina)
+	if unit.IsExhausted() {
+		staminaText += "（疲労困憊）"
+	}
+	bs.textRenderer.DrawText(screen, staminaText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	y += row
+	
+	// Ammunition of ranged units
+	if unit.MaxAmmo > 0 {
+		ammoText := fmt.Sprintf("矢弾: %d/%d", unit.Ammo, unit.MaxAmmo)
+		if unit.MeleeFallback {
+			ammoText = "矢弾: なし（白兵戦）"
+		} else if unit.Resupplying {
+			ammoText += "（補給中）"
+		}
+		bs.textRenderer.DrawText(screen, ammoText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	} else if unit.UsesMana() {
+		manaText := fmt.Sprintf("魔力: %.0f/%.0f", unit.Mana, unit.Spells.MaxMana)
+		bs.textRenderer.DrawText(screen, manaText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	} else if unit.IsSummoned() {
+		summonText := fmt.Sprintf("召喚: 残り%.0f秒", unit.Lifetime)
+		bs.textRenderer.DrawText(screen, summonText, float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	}
+	
+	// Leader's equipment
+	if len(unit.Items) > 0 {
+		var names []string
+		for _, id := range unit.Items {
+			if item, err := bs.dataManager.GetItemConfig(id); err == nil {
+				id = item.Name
+			}
+			names = append(names, id)
+		}
+		y += row
+		bs.textRenderer.DrawText(screen, "装備: "+strings.Join(names, "・"), float64(infoX+10), float64(y), graphics.CurrentTheme().Text)
+	}
+}
+
+// unitHistoryLines is how many of the selected unit's latest battle log records are shown
+const unitHistoryLines = 6
+
+// drawSelectedUnitHistory draws the selected unit's latest battle log records above its info panel
+func (bs *BattleSceneUnified) drawSelectedUnitHistory(screen *ebiten.Image) {
+	unit := bs.selectedUnit
+	history := bs.battleManager.GetUnitHistory(unit)
+	if len(history) > unitHistoryLines {
+		history = history[len(history)-unitHistoryLines:]
+	}
+	
+	// Background
+	historyX := 300
+	historyY := 505
+	historyBg := ebiten.NewImage(470, 25+unitHistoryLines*15)
+	historyBg.Fill(graphics.WithAlpha(graphics.CurrentTheme().Panel, 200))
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(historyX), float64(historyY))
+	screen.DrawImage(historyBg, op)
+	
+	y := historyY + 5
+	bs.textRenderer.DrawText(screen, fmt.Sprintf("戦闘記録 #%d:", unit.ID), float64(historyX+10), float64(y), graphics.CurrentTheme().Text)
+	if len(history) == 0 {
+		bs.textRenderer.DrawText(screen, "記録なし", float64(historyX+10), float64(y+20), graphics.CurrentTheme().TextMuted)
+		return
+	}
+	for _, event := range history {
+		y += 15
+		line := fmt.Sprintf("%02d:%02d  %s", int(event.Time)/60, int(event.Time)%60, game.DescribeEvent(event, unit.ID))
+		bs.textRenderer.DrawText(screen, line, float64(historyX+10), float64(y+5), graphics.CurrentTheme().Text)
+	}
 }
 
 // drawDebugInfo draws debug information
@@ -740,39 +2480,53 @@ func (bs *BattleSceneUnified) drawDebugInfo(screen *ebiten.Image) {
 	zoom := bs.camera.GetZoom()
 	
 	debugText := fmt.Sprintf("Camera: (%.0f, %.0f) Zoom: %.2f", camX, camY, zoom)
-	bs.textRenderer.DrawText(screen, debugText, 10, 80, color.RGBA{255, 255, 0, 255})
+	bs.textRenderer.DrawText(screen, debugText, 10, 80, graphics.CurrentTheme().Debug)
 	
 	// Show mouse position for debugging
-	mouseX, mouseY := ebiten.CursorPosition()
+	mouseX, mouseY := controls.CursorPosition()
 	worldX, worldY := bs.camera.ScreenToWorld(mouseX, mouseY)
 	mouseText := fmt.Sprintf("Mouse: Screen(%d, %d) World(%.0f, %.0f)", mouseX, mouseY, worldX, worldY)
-	bs.textRenderer.DrawText(screen, mouseText, 10, 100, color.RGBA{255, 255, 0, 255})
+	bs.textRenderer.DrawText(screen, mouseText, 10, 100, graphics.CurrentTheme().Debug)
 	
 	if bs.selectedUnit != nil {
 		unitDebug := fmt.Sprintf("Selected: %s at (%.0f, %.0f)", 
 			bs.selectedUnit.Type, bs.selectedUnit.Position.X, bs.selectedUnit.Position.Y)
-		bs.textRenderer.DrawText(screen, unitDebug, 10, 120, color.RGBA{255, 255, 0, 255})
+		bs.textRenderer.DrawText(screen, unitDebug, 10, 120, graphics.CurrentTheme().Debug)
 	}
 	
 	fpsText := fmt.Sprintf("FPS: %.1f", 1.0/bs.deltaTime)
-	bs.textRenderer.DrawText(screen, fpsText, 10, 140, color.RGBA{255, 255, 0, 255})
+	bs.textRenderer.DrawText(screen, fpsText, 10, 140, graphics.CurrentTheme().Debug)
+	
+	// Simulated ticks, stepped one at a time with . while paused
+	tickText := fmt.Sprintf("Tick: %d  Time: %.2fs", bs.battleManager.Ticks, bs.battleManager.BattleTime)
+	if bs.isPaused || bs.tacticalPause {
+		tickText += "  (paused: . step)"
+	}
+	bs.textRenderer.DrawText(screen, tickText, 10, 180, graphics.CurrentTheme().Debug)
+	
+	// Time travel position (debug builds)
+	if bs.history != nil {
+		tick, recorded := bs.history.Position()
+		historyText := fmt.Sprintf("History: %d/%d ticks  -%.2fs  (paused: , back  . forward)", tick, recorded, bs.history.Rewound())
+		bs.textRenderer.DrawText(screen, historyText, 10, 200, graphics.CurrentTheme().Debug)
+	}
 	
 	// Show scroll controller status
 	if bs.scrollController != nil {
 		scrollText := fmt.Sprintf("Scroll: Edge=%t Key=%t Drag=%t", 
 			bs.scrollController.EdgeScrolling, bs.scrollController.KeyScrolling, bs.scrollController.DragScrolling)
-		bs.textRenderer.DrawText(screen, scrollText, 10, 160, color.RGBA{255, 255, 0, 255})
+		bs.textRenderer.DrawText(screen, scrollText, 10, 160, graphics.CurrentTheme().Debug)
 	}
 }
 
 // drawHelp draws help information
 func (bs *BattleSceneUnified) drawHelp(screen *ebiten.Image) {
 	// Semi-transparent background
-	helpBg := ebiten.NewImage(400, 300)
-	helpBg.Fill(color.RGBA{0, 0, 0, 200})
+	helpBg := ebiten.NewImage(420, 600)
+	helpBg.Fill(graphics.WithAlpha(graphics.CurrentTheme().Overlay, 200))
 	
 	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(312, 234) // Center on screen
+	op.GeoM.Translate(312, 144) // Center on screen
 	screen.DrawImage(helpBg, op)
 	
 	// Help text
@@ -780,38 +2534,141 @@ func (bs *BattleSceneUnified) drawHelp(screen *ebiten.Image) {
 		"=== 操作方法 ===",
 		"",
 		"マウス: ユニット選択",
+		"右クリック: 選択部隊に移動命令",
+		keyName(controls.ActionTacticalPause) + ": 作戦タイム（停止中に命令を予約）",
 		"WASD/矢印キー: カメラ移動",
 		"マウスホイール: ズーム",
 		"中ボタンドラッグ: カメラドラッグ",
 		"画面端: エッジスクロール",
 		"+/-キー: ズームイン/アウト",
-		"P: 一時停止",
-		"R: 設定画面に戻る",
+		fmt.Sprintf("%s / %s: 戦闘速度（遅く/速く）", keyName(controls.ActionSlower), keyName(controls.ActionFaster)),
+		"右下ボタン: 停止・作戦・地図・ズーム・速度・カメラ",
+		"ミニマップクリック: カメラ移動",
+		fmt.Sprintf("%s: 一時停止（停止中は %s でコマ送り、%s で設定）",
+			keyName(controls.ActionPause), keyName(controls.ActionStepForward), keyName(controls.ActionSettings)),
+		fmt.Sprintf("%s: 5秒巻き戻す（最大30秒）  %s: 巻き戻した所から再開", keyName(controls.ActionRewind), keyName(controls.ActionResume)),
+		keyName(controls.ActionReturnToSetup) + ": 設定画面に戻る",
 		"F1: デバッグ情報表示",
-		"F2: このヘルプ表示",
+		keyName(controls.ActionHelp) + ": このヘルプ表示",
 		"F5: 戦闘再初期化",
+		keyName(controls.ActionScreenshot) + ": 戦場のスクリーンショットを保存（screenshots/）",
+		keyName(controls.ActionDirector) + ": ディレクターモード（カメラが激戦地を自動で追う）",
+		keyName(controls.ActionEject) + ": 選択部隊を櫓から出す（味方の櫓を右クリックで入る）",
+		keyName(controls.ActionAssignGroup) + ": 部隊の指揮権を相方に渡す（協力プレイ）",
+		"ゲームパッド: 右スティックのカーソルをA/Bでクリック",
+		"タッチ: タップで選択・移動命令、ドラッグでカメラ移動",
+		"タッチ: ピンチでズーム、長押しでユニット情報",
 		"",
 		"=== ユニット記号 ===",
 		"□: 歩兵  △: 弓兵  ◇: 魔術師",
 		"",
-		"F2でヘルプを閉じる",
+		keyName(controls.ActionHelp) + "/Esc/ヘルプボタンで閉じる",
 	}
 	
-	y := 250
+	y := 160.0
 	for _, line := range helpLines {
-		bs.textRenderer.DrawText(screen, line, 330, float64(y), color.RGBA{255, 255, 255, 255})
-		y += 18
+		bs.textRenderer.DrawText(screen, line, 330, y, graphics.CurrentTheme().TextBright)
+		y += bs.textRenderer.Spacing(18)
+	}
+}
+
+// drawOrders draws lines from group leaders to their order targets
+func (bs *BattleSceneUnified) drawOrders(screen *ebiten.Image, transform ebiten.GeoM) {
+	activeColor := graphics.WithAlpha(graphics.CurrentTheme().Success, 200)
+	queuedColor := graphics.WithAlpha(graphics.CurrentTheme().Highlight, 220)
+	
+	for _, group := range bs.battleManager.GetArmy(playerArmyID).Groups {
+		if group.CurrentOrder != nil && group.Leader != nil {
+			bs.drawOrderLine(screen, transform, group.Leader.Position, group.CurrentOrder.Target, activeColor)
+		}
+	}
+	
+	for _, order := range bs.battleManager.QueuedOrders {
+		group := bs.battleManager.FindGroup(order.ArmyID, order.GroupID)
+		if group != nil && group.Leader != nil {
+			bs.drawOrderLine(screen, transform, group.Leader.Position, order.Target, queuedColor)
+		}
+	}
+}
+
+// drawOrderLine draws an order line with a target marker
+func (bs *BattleSceneUnified) drawOrderLine(screen *ebiten.Image, transform ebiten.GeoM, from, to gamemath.Vector2D, lineColor color.RGBA) {
+	x1, y1 := transform.Apply(from.X, from.Y)
+	x2, y2 := transform.Apply(to.X, to.Y)
+	vector.StrokeLine(screen, float32(x1), float32(y1), float32(x2), float32(y2), 2, lineColor, true)
+	vector.StrokeCircle(screen, float32(x2), float32(y2), 6, 2, lineColor, true)
+}
+
+// drawTacticalPauseBanner draws the tactical pause indicator
+func (bs *BattleSceneUnified) drawTacticalPauseBanner(screen *ebiten.Image) {
+	banner := ebiten.NewImage(1024, 30)
+	banner.Fill(graphics.CurrentTheme().Overlay)
+	
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(0, 60)
+	screen.DrawImage(banner, op)
+	
+	bannerText := "作戦タイム - 右クリックで移動命令を予約  " + keyName(controls.ActionTacticalPause) + ": 再開"
+	if bs.tacticalPauseMode() == config.TacticalPauseLimited {
+		bannerText += fmt.Sprintf("  (残り%d回)", bs.tacticalPausesLeft)
 	}
+	bs.textRenderer.DrawCenteredText(screen, bannerText, 512, 75, graphics.CurrentTheme().Highlight)
+}
+
+// drawRewindBanner shows how far back the rewound battle is and how to go on from there
+func (bs *BattleSceneUnified) drawRewindBanner(screen *ebiten.Image) {
+	banner := ebiten.NewImage(1024, 30)
+	banner.Fill(graphics.CurrentTheme().Overlay)
+	
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(0, 60)
+	screen.DrawImage(banner, op)
+	
+	bannerText := fmt.Sprintf("巻き戻し再生中 %.1f秒前 - %s/命令: ここから再開  %s: さらに戻る",
+		bs.history.Rewound(), keyName(controls.ActionResume), keyName(controls.ActionRewind))
+	bs.textRenderer.DrawCenteredText(screen, bannerText, 512, 75, graphics.CurrentTheme().Accent)
 }
 
-// drawPauseOverlay draws the pause overlay
+// drawPauseOverlay draws the pause over the battle the scene manager has dimmed
 func (bs *BattleSceneUnified) drawPauseOverlay(screen *ebiten.Image) {
-	// Semi-transparent overlay
+	// Pause text
+	bs.textRenderer.DrawCenteredText(screen, "一時停止", 512, 350, graphics.CurrentTheme().TextBright)
+	bs.textRenderer.DrawCenteredText(screen, keyName(controls.ActionPause)+"/Esc/クリックで再開", 512, 400, graphics.CurrentTheme().TextBright)
+	bs.textRenderer.DrawCenteredText(screen, keyName(controls.ActionStepForward)+"キーで1ティック進める", 512, 430, graphics.CurrentTheme().TextHint)
+	if bs.settings != nil {
+		bs.textRenderer.DrawCenteredText(screen, keyName(controls.ActionSettings)+"キーで設定", 512, 460, graphics.CurrentTheme().TextHint)
+	}
+	
+	// Keep the pause button reachable above the overlay
+	bs.hud.pauseButton.Draw(screen, bs.textRenderer)
+}
+
+// drawRulesCard draws the pre-battle rules summary
+func (bs *BattleSceneUnified) drawRulesCard(screen *ebiten.Image) {
+	// Dim the battlefield
 	overlay := ebiten.NewImage(1024, 768)
-	overlay.Fill(color.RGBA{0, 0, 0, 128})
+	overlay.Fill(graphics.CurrentTheme().Overlay)
 	screen.DrawImage(overlay, nil)
 	
-	// Pause text
-	bs.textRenderer.DrawCenteredText(screen, "一時停止", 512, 350, color.RGBA{255, 255, 255, 255})
-	bs.textRenderer.DrawCenteredText(screen, "P/Escで再開", 512, 400, color.RGBA{255, 255, 255, 255})
+	lines := bs.battleManager.GetRulesSummary()
+	cardX, cardY, cardWidth, cardHeight := bs.rulesCardBounds()
+	
+	card := ebiten.NewImage(cardWidth, cardHeight)
+	card.Fill(graphics.WithAlpha(graphics.CurrentTheme().Panel, 240))
+	
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(cardX), float64(cardY))
+	screen.DrawImage(card, op)
+	
+	bs.textRenderer.DrawTextWithSize(screen, "戦闘ルール", float64(cardX+20), float64(cardY+15), graphics.CurrentTheme().Text, 20)
+	
+	y := cardY + 55
+	for _, line := range lines {
+		bs.textRenderer.DrawText(screen, line, float64(cardX+20), float64(y), graphics.CurrentTheme().Text)
+		y += 20
+	}
+	
+	bs.textRenderer.DrawText(screen, "Enter/Space: 開始  Esc: 戻る", float64(cardX+20), float64(cardY+cardHeight-35), graphics.CurrentTheme().Accent)
+	bs.rulesStartButton.Draw(screen, bs.textRenderer)
+	bs.rulesBackButton.Draw(screen, bs.textRenderer)
 }
@@ -0,0 +1,357 @@
+package scenes
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// Challenge screen layout
+const (
+	challengeListX   = 100
+	challengeListY   = 150
+	challengeRowStep = 30
+	challengeDetailX = 480
+	challengeLineY   = 20 // 詳細の行の間隔
+	challengeButtonY = 600
+)
+
+// challengeButtons follow the challenges in item order
+var challengeButtons = []string{"部隊編成", "戻る"}
+
+// ChallengeScene lists the scenario challenges with the stars earned on each, and starts one
+// with the custom army once its deployed groups keep within the challenge's limits
+type ChallengeScene struct {
+	sceneManager *SceneManager
+	dataManager  *data.DataManager
+	textRenderer *graphics.TextRenderer
+	
+	challenges   []data.ChallengeListing
+	profile      *save.Profile // 星の記録と出陣する部隊（nil: 読み込めなかった）
+	selectedItem int           // チャレンジ、部隊編成ボタン、戻るボタンの順
+	status       string        // 挑戦できない理由など
+}
+
+// NewChallengeScene creates a new challenge scene
+func NewChallengeScene(sceneManager *SceneManager, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) *ChallengeScene {
+	return &ChallengeScene{
+		sceneManager: sceneManager,
+		dataManager:  dataManager,
+		textRenderer: textRenderer,
+		challenges:   dataManager.ListChallenges(),
+	}
+}
+
+// OnEnter reloads the stars earned and the deployed groups, which a battle or the recruitment screen may have changed
+func (cs *ChallengeScene) OnEnter(data interface{}) {
+	cs.status = ""
+	cs.selectedItem = min(cs.selectedItem, cs.lastItem())
+	profile, err := save.LoadProfile(save.DefaultProfilePath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load profile: %v\n", err)
+		cs.profile = nil
+		cs.status = "プロフィールを読み込めませんでした"
+		return
+	}
+	cs.profile = profile
+}
+
+// OnExit is called when exiting this scene
+func (cs *ChallengeScene) OnExit() {
+	// Nothing to clean up
+}
+
+// lastItem returns the index of the back button, after the challenges and the recruitment button
+func (cs *ChallengeScene) lastItem() int {
+	return len(cs.challenges) + len(challengeButtons) - 1
+}
+
+// Update handles choosing and starting a challenge
+func (cs *ChallengeScene) Update() error {
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		cs.sceneManager.TransitionTo(SceneTitle, nil)
+		return nil
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		cs.selectedItem = (cs.selectedItem + cs.lastItem()) % (cs.lastItem() + 1)
+		cs.status = ""
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		cs.selectedItem = (cs.selectedItem + 1) % (cs.lastItem() + 1)
+		cs.status = ""
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) {
+		cs.confirmSelection()
+	}
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		cs.handleClick()
+	}
+	return nil
+}
+
+// confirmSelection starts the selected challenge or activates the selected button
+func (cs *ChallengeScene) confirmSelection() {
+	if cs.selectedItem < len(cs.challenges) {
+		cs.start(cs.challenges[cs.selectedItem].ID)
+		return
+	}
+	switch challengeButtons[cs.selectedItem-len(cs.challenges)] {
+	case "部隊編成":
+		cs.sceneManager.TransitionTo(SceneRecruitment, nil)
+	case "戻る":
+		cs.sceneManager.TransitionTo(SceneTitle, nil)
+	}
+}
+
+// handleClick selects and confirms the clicked row
+func (cs *ChallengeScene) handleClick() {
+	for item := 0; item <= cs.lastItem(); item++ {
+		x, y, text := cs.rowPosition(item)
+		if isCursorOverText(cs.textRenderer, "> "+text, x-20, y) {
+			cs.selectedItem = item
+			cs.confirmSelection()
+			return
+		}
+	}
+}
+
+// rowPosition returns where a row is drawn and its text
+func (cs *ChallengeScene) rowPosition(item int) (float64, float64, string) {
+	if item < len(cs.challenges) {
+		listing := cs.challenges[item]
+		text := listing.Name
+		if challenge, err := cs.dataManager.GetChallengeConfig(listing.ID); err == nil {
+			text = starText(cs.bestStars(listing.ID), challenge.MaxStars()) + " " + text
+		}
+		return challengeListX, float64(challengeListY + challengeRowStep*item), text
+	}
+	button := item - len(cs.challenges)
+	return challengeListX + float64(button*150), challengeButtonY, challengeButtons[button]
+}
+
+// bestStars returns the most stars the player earned on the challenge
+func (cs *ChallengeScene) bestStars(challengeID string) int {
+	if cs.profile == nil {
+		return 0
+	}
+	return cs.profile.ChallengeStars(challengeID)
+}
+
+// start fights the challenge with the deployed groups, or says why they cannot take the field
+func (cs *ChallengeScene) start(challengeID string) {
+	challenge, err := cs.dataManager.GetChallengeConfig(challengeID)
+	if err != nil || cs.profile == nil {
+		return
+	}
+	if problems := challengeProblems(cs.dataManager, challenge, cs.profile.Roster); len(problems) > 0 {
+		cs.status = problems[0]
+		return
+	}
+	
+	enemyPreset := challenge.EnemyPreset
+	if enemyPreset == "" {
+		enemyPreset = game.PresetNames[0]
+	}
+	mutators := challenge.Mutators
+	if mutators == nil {
+		mutators = []string{}
+	}
+	cs.sceneManager.TransitionTo(SceneBattle, map[string]interface{}{
+		"stage":        stageDisplayName(cs.dataManager, challenge.Stage),
+		"preset":       customArmyChoice,
+		"enemy_preset": enemyPreset,
+		"army":         deployedGroups(cs.profile.Roster),
+		"mutators":     mutators,
+		"doctrines":    []string{},
+		"handicaps":    []game.Handicap{},
+		"challenge":    challengeID,
+	})
+}
+
+// challengeProblems lists why the deployed groups of the roster cannot take on the challenge (none: they can)
+func challengeProblems(dataManager *data.DataManager, challenge data.ChallengeConfig, roster []save.RosterGroup) []string {
+	recruitment := dataManager.Recruitment
+	var problems []string
+	deployed, points := 0, 0
+	for _, group := range roster {
+		if !group.Deployed {
+			continue
+		}
+		deployed++
+		points += groupPoints(recruitment, group)
+		if !challenge.AllowsUnit(group.Leader) || !challenge.AllowsUnit(group.Member) {
+			problems = append(problems, group.Recruit+"はこのチャレンジに出陣できません")
+		}
+	}
+	
+	if deployed == 0 {
+		return []string{"出陣する部隊がありません（部隊編成で雇ってください）"}
+	}
+	if limit := challenge.GroupLimit(recruitment); deployed > limit {
+		problems = append(problems, fmt.Sprintf("出陣できるのは%d部隊までです（%d部隊）", limit, deployed))
+	}
+	if budget := challenge.PointBudget(recruitment); points > budget {
+		problems = append(problems, fmt.Sprintf("戦力点が上限を超えています（%d / %d）", points, budget))
+	}
+	return problems
+}
+
+// starText draws a rating as filled and empty stars
+func starText(stars, maxStars int) string {
+	return strings.Repeat("★", stars) + strings.Repeat("☆", max(maxStars-stars, 0))
+}
+
+// Draw draws the challenge list and the selected challenge's limits, goals and the deployed groups' standing
+func (cs *ChallengeScene) Draw(screen *ebiten.Image) {
+	screen.Fill(graphics.CurrentTheme().Background)
+	textColor := graphics.CurrentTheme().Text
+	dimColor := graphics.CurrentTheme().TextMuted
+	
+	cs.textRenderer.DrawTextWithSize(screen, "チャレンジ", 430, 50, textColor, 24)
+	if len(cs.challenges) == 0 {
+		cs.textRenderer.DrawText(screen, "チャレンジがありません", challengeListX, challengeListY, dimColor)
+	}
+	for item := 0; item <= cs.lastItem(); item++ {
+		x, y, text := cs.rowPosition(item)
+		if item == cs.selectedItem {
+			cs.textRenderer.DrawTextWithShadow(screen, "> "+text, x-20, y, graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+		} else {
+			cs.textRenderer.DrawText(screen, text, x, y, textColor)
+		}
+	}
+	
+	if cs.selectedItem < len(cs.challenges) {
+		cs.drawDetails(screen, cs.challenges[cs.selectedItem].ID)
+	}
+	
+	if cs.status != "" {
+		cs.textRenderer.DrawText(screen, cs.status, challengeListX, challengeButtonY+40, graphics.CurrentTheme().Highlight)
+	}
+	
+	controlsText := "↑↓: 選択  Enter/クリック: 挑戦  Esc: 戻る"
+	cs.textRenderer.DrawText(screen, controlsText, 120, 700, dimColor)
+}
+
+// drawDetails draws the challenge's stage, limits and goals, then whether the deployed groups may take it on
+func (cs *ChallengeScene) drawDetails(screen *ebiten.Image, challengeID string) {
+	challenge, err := cs.dataManager.GetChallengeConfig(challengeID)
+	if err != nil {
+		return
+	}
+	textColor := graphics.CurrentTheme().Text
+	dimColor := graphics.CurrentTheme().TextMuted
+	recruitment := cs.dataManager.Recruitment
+	
+	y := float64(challengeListY)
+	line := func(text string, clr color.Color) {
+		cs.textRenderer.DrawText(screen, text, challengeDetailX, y, clr)
+		y += challengeLineY
+	}
+	line(challenge.Name, textColor)
+	line(challenge.Description, dimColor)
+	line("ステージ: "+stageDisplayName(cs.dataManager, challenge.Stage), dimColor)
+	enemyPreset := challenge.EnemyPreset
+	if enemyPreset == "" {
+		enemyPreset = game.PresetNames[0]
+	}
+	line("敵軍: "+enemyPreset, dimColor)
+	
+	// Limits on the custom army
+	y += challengeLineY / 2
+	line("出陣の制限:", textColor)
+	line(fmt.Sprintf("・戦力点 %d以下、%d部隊まで", challenge.PointBudget(recruitment), challenge.GroupLimit(recruitment)), dimColor)
+	if len(challenge.AllowedUnits) > 0 {
+		names := make([]string, len(challenge.AllowedUnits))
+		for i, unitType := range challenge.AllowedUnits {
+			names[i] = unitTypeDisplayName(cs.dataManager, unitType)
+		}
+		line("・"+strings.Join(names, "・")+"のみ", dimColor)
+	}
+	for _, id := range challenge.Mutators {
+		if mutator := game.GetMutator(id); mutator != nil {
+			line("・特殊ルール: "+mutator.Name, dimColor)
+		}
+	}
+	
+	// Stars
+	y += challengeLineY / 2
+	line(fmt.Sprintf("評価: %s", starText(cs.bestStars(challengeID), challenge.MaxStars())), textColor)
+	line("★ 勝利", dimColor)
+	for _, goal := range challenge.Goals {
+		line("★ "+goal.String(), dimColor)
+	}
+	if cs.profile != nil {
+		if record := cs.profile.Challenges[challengeID]; record != nil {
+			line(fmt.Sprintf("挑戦回数: %d", record.Attempts), dimColor)
+		}
+	}
+	
+	// Whether the deployed groups may take the challenge on
+	if cs.profile == nil {
+		return
+	}
+	y += challengeLineY / 2
+	problems := challengeProblems(cs.dataManager, challenge, cs.profile.Roster)
+	if len(problems) == 0 {
+		line("自軍編成: 出陣できます", graphics.CurrentTheme().Success)
+		return
+	}
+	line("自軍編成: 出陣できません", graphics.CurrentTheme().Danger)
+	for _, problem := range problems {
+		line("・"+problem, graphics.CurrentTheme().Danger)
+	}
+}
+
+// unitTypeDisplayName returns the display name of a unit type, or its ID if unknown
+func unitTypeDisplayName(dataManager *data.DataManager, unitType string) string {
+	if config, err := dataManager.GetUnitConfig(unitType); err == nil && config.Name != "" {
+		return config.Name
+	}
+	return unitType
+}
+
+// rateChallenge rates the finished challenge battle for the result screen and returns the stars earned
+func (bs *BattleSceneUnified) rateChallenge() int {
+	gameData := bs.sceneManager.gameData
+	challenge, err := bs.dataManager.GetChallengeConfig(gameData.Challenge)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		gameData.Rating = ""
+		return 0
+	}
+	
+	survivors := 0.0
+	for _, army := range bs.battleManager.Armies {
+		if units := army.GetAllUnits(); army.ID == playerArmyID && len(units) > 0 {
+			survivors = float64(army.GetAliveCount()) / float64(len(units))
+		}
+	}
+	stars := challenge.Stars(bs.battleResult() == save.ResultWin, bs.battleManager.BattleTime, survivors)
+	
+	gameData.Rating = fmt.Sprintf("チャレンジ「%s」 %s", challenge.Name, starText(stars, challenge.MaxStars()))
+	if stars == 0 {
+		gameData.Rating += " 失敗"
+	}
+	return stars
+}
+
+// recordChallengeResult adds the attempt at the challenge to the player's profile
+func recordChallengeResult(challengeID string, stars int) {
+	profile, err := save.LoadProfile(save.DefaultProfilePath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load profile: %v\n", err)
+		return
+	}
+	profile.RecordChallenge(challengeID, stars)
+	if err := profile.Save(save.DefaultProfilePath); err != nil {
+		fmt.Printf("Warning: Failed to save profile: %v\n", err)
+	}
+}
@@ -0,0 +1,213 @@
+package scenes
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/netplay"
+)
+
+// syncCoopBattle agrees on the battle with the partner: the host sends its setup, the guest adopts it
+func (bs *BattleSceneUnified) syncCoopBattle(session *netplay.Session) (netplay.Setup, error) {
+	gameData := bs.sceneManager.gameData
+	if session.Player == netplay.PlayerHost {
+		setup := netplay.Setup{
+			Seed:          time.Now().UnixNano(),
+			Stage:         gameData.CurrentStage,
+			Preset:        gameData.CurrentPreset,
+			EnemyPreset:   gameData.EnemyPreset,
+			Mutators:      gameData.Mutators,
+			Doctrines:     gameData.Doctrines,
+			Handicaps:     gameData.Handicaps,
+			CommandPoints: bs.config != nil && bs.config.Game.CommandPoints,
+			CommandDelay:  bs.config != nil && bs.config.Game.CommandDelay,
+		}
+		
+		// The budget agreed in the lobby overrides the config
+		if settings, _, ok := session.Settings(); ok {
+			setup.CommandPoints = settings.CommandPoints
+		}
+		setup, err := session.StartBattle(setup)
+		if err == nil {
+			bs.startBroadcast(setup)
+		}
+		return setup, err
+	}
+	
+	fmt.Println("Co-op: waiting for the host to start the battle")
+	setup, err := session.WaitBattle()
+	if err != nil {
+		return setup, err
+	}
+	bs.adoptSetup(setup)
+	return setup, nil
+}
+
+// adoptSetup fights the battle another machine chose
+func (bs *BattleSceneUnified) adoptSetup(setup netplay.Setup) {
+	gameData := bs.sceneManager.gameData
+	gameData.CurrentStage = setup.Stage
+	gameData.CurrentPreset = setup.Preset
+	gameData.EnemyPreset = setup.EnemyPreset
+	gameData.Army = nil
+	gameData.Mutators = setup.Mutators
+	gameData.Doctrines = setup.Doctrines
+	gameData.Handicaps = setup.Handicaps
+}
+
+// stepCoop runs the next lockstep tick and reports whether the battle advanced
+func (bs *BattleSceneUnified) stepCoop() bool {
+	commands, ok := bs.lockstep.Advance()
+	bs.coopWaiting = !ok
+	if !ok {
+		if err := bs.lockstep.Err(); err != nil {
+			bs.leaveCoop(err)
+		}
+		return false
+	}
+	
+	// Commands the sender could not have issued are dropped on both sides
+	commands, rejected := netplay.CheckCommands(bs.battleManager, playerArmyID, commands)
+	bs.flagRejectedCommands(rejected)
+	
+	// Observers replay the same ticks from the host's stream
+	if broadcast := bs.sceneManager.gameData.Broadcast; broadcast != nil && bs.lockstep.Player() == netplay.PlayerHost {
+		broadcast.Record(commands)
+	}
+	
+	bs.applyCommands(commands)
+	bs.battleManager.Update(netplay.TickDelta)
+	bs.recordHighlights()
+	
+	// Diverged battles cannot be brought back in step; each side continues alone
+	if err := bs.lockstep.Verify(bs.battleManager.StateHash); err != nil {
+		bs.battleManager.Announce("相方と戦闘の状態が食い違いました")
+		bs.leaveCoop(err)
+	}
+	return true
+}
+
+// applyCommands carries out the commands of a tick
+func (bs *BattleSceneUnified) applyCommands(commands []netplay.Command) {
+	for _, command := range commands {
+		switch {
+		case command.Order != nil:
+			bs.battleManager.IssueOrder(*command.Order)
+		case command.Assign != nil:
+			assign := command.Assign
+			bs.battleManager.AssignGroup(assign.ArmyID, assign.GroupID, assign.Player)
+		}
+	}
+}
+
+// flagRejectedCommands reports the commands CheckCommands refused, warning the player about the partner's
+func (bs *BattleSceneUnified) flagRejectedCommands(rejected []netplay.Rejection) {
+	flagged := false
+	for _, rejection := range rejected {
+		fmt.Printf("Co-op: rejected command from player %d: %s\n", rejection.Player, rejection.Reason)
+		if rejection.Player != bs.lockstep.Player() {
+			flagged = true
+		}
+	}
+	if flagged {
+		bs.battleManager.Announce("相方の不正な命令を拒否しました")
+	}
+}
+
+// leaveCoop continues the battle alone after the partner disconnected, taking over all groups
+func (bs *BattleSceneUnified) leaveCoop(err error) {
+	fmt.Printf("Co-op: %v\n", err)
+	bs.lockstep = nil
+	bs.coopWaiting = false
+	
+	// Observers get the rest of what was played together, but not the solo part
+	if broadcast := bs.sceneManager.gameData.Broadcast; broadcast != nil {
+		broadcast.Finish()
+	}
+	if army := bs.battleManager.GetArmy(playerArmyID); army != nil {
+		for _, group := range army.Groups {
+			group.Controller = 0
+		}
+	}
+	bs.battleManager.Announce("相方との接続が切れました")
+}
+
+// queueCoopOrder sends an order for one of this player's groups to the lockstep
+func (bs *BattleSceneUnified) queueCoopOrder(order game.Order) {
+	order.Player = bs.lockstep.Player()
+	group := bs.battleManager.FindGroup(order.ArmyID, order.GroupID)
+	if group == nil {
+		return
+	}
+	if group.Controller != 0 && group.Controller != order.Player {
+		bs.battleManager.Announce("相方が指揮している部隊です")
+		return
+	}
+	
+	// Orders sent together must fit the budget, or the partner rejects them
+	commandPoints := bs.battleManager.CommandPoints
+	if commandPoints != nil && commandPoints.Current < bs.lockstep.PendingCost()+game.GetOrderCost(order) {
+		bs.battleManager.Announce("指揮力が足りません")
+		return
+	}
+	if !bs.lockstep.Queue(netplay.Command{Order: &order}) {
+		bs.battleManager.Announce("命令が多すぎます")
+	}
+}
+
+// handleAssignGroup hands the selected group of this player to the partner
+func (bs *BattleSceneUnified) handleAssignGroup() {
+	unit := bs.selectedUnit
+	if unit == nil || !unit.IsAlive || unit.ArmyID != playerArmyID {
+		return
+	}
+	group := bs.battleManager.FindGroup(unit.ArmyID, unit.GroupID)
+	if group == nil {
+		return
+	}
+	
+	player := bs.lockstep.Player()
+	if group.Controller != 0 && group.Controller != player {
+		bs.battleManager.Announce("相方が指揮している部隊です")
+		return
+	}
+	partner := netplay.PlayerGuest
+	if player == netplay.PlayerGuest {
+		partner = netplay.PlayerHost
+	}
+	bs.lockstep.Queue(netplay.Command{Assign: &netplay.Assignment{
+		ArmyID:  group.ArmyID,
+		GroupID: group.ID,
+		Player:  partner,
+	}})
+}
+
+// coopControllerText names who commands the unit's group
+func (bs *BattleSceneUnified) coopControllerText(unit *game.Unit) string {
+	group := bs.battleManager.FindGroup(unit.ArmyID, unit.GroupID)
+	switch {
+	case group == nil || group.Controller == 0:
+		return "（共同指揮）"
+	case group.Controller == bs.lockstep.Player():
+		return "（自分の部隊）"
+	default:
+		return "（相方の部隊）"
+	}
+}
+
+// drawCoopStatus shows this player's number and whether the battle waits for the partner
+func (bs *BattleSceneUnified) drawCoopStatus(screen *ebiten.Image) {
+	statusText := fmt.Sprintf("協力プレイ P%d", bs.lockstep.Player())
+	if broadcast := bs.sceneManager.gameData.Broadcast; broadcast != nil && broadcast.Observers() > 0 {
+		statusText += fmt.Sprintf("  観戦 %d人", broadcast.Observers())
+	}
+	statusColor := graphics.CurrentTheme().Success
+	if bs.coopWaiting {
+		statusText += "  相方を待っています…"
+		statusColor = graphics.CurrentTheme().Highlight
+	}
+	bs.textRenderer.DrawText(screen, statusText, 800, 590, statusColor)
+}
@@ -0,0 +1,6 @@
+//go:build !debug
+
+package scenes
+
+// debugBuild enables the developer tools of debug builds (go build -tags debug)
+const debugBuild = false
@@ -0,0 +1,87 @@
+package scenes
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/graphics"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// Director mode tuning
+const (
+	directorMinIntensity = 30.0  // 直近のダメージがこれ以上の場所を見どころとみなす
+	directorHoldTime     = 3.0   // 見どころを映したら、この秒数は離れた別の見どころへ移らない
+	directorSwitchRange  = 300.0 // これより離れた見どころへの移動を切り替えとみなす
+	directorSightRange   = 150.0 // 見どころのこの距離内に見えるユニットがいなければ映さない（霧）
+	directorPanRate      = 2.0   // カメラが見どころへ寄る速さ（1秒あたり、残りの距離に対する割合）
+	directorFrameMargin  = 300.0 // 見どころの広がりに加えて画面に収める幅（px）
+	directorFrameSpread  = 3.0   // 見どころの広がりの何倍を画面に収めるか
+	directorMinZoom      = 0.6   // 戦闘が散らばっている、または静かなときの拡大率
+	directorMaxZoom      = 1.5   // 戦闘が1か所に固まっているときの拡大率
+)
+
+// handleDirectorInput turns director mode on and off with its key (C)
+func (bs *BattleSceneUnified) handleDirectorInput() {
+	if controls.IsActionJustPressed(controls.ActionDirector) {
+		bs.director = !bs.director
+		bs.directorHold = directorHoldTime
+		if bs.director {
+			bs.battleManager.Announce("ディレクターモード: カメラが激戦地を追います")
+		}
+	}
+}
+
+// updateDirector pans and zooms the camera toward the fiercest fighting of the last few seconds,
+// framing it tighter the more it is packed together; with nothing going on it eases back out.
+// It stays on a fight for a while before cutting to another one far away, and never shows
+// fighting the player cannot see through the fog
+func (bs *BattleSceneUnified) updateDirector() {
+	if !bs.director || bs.battleManager == nil || (bs.observer != nil && bs.spectateView != spectateFree) {
+		return
+	}
+	bs.directorHold += bs.deltaTime
+	
+	targetZoom := directorMinZoom
+	hotspot, spread, intensity := bs.battleManager.Heatmap.Hotspot()
+	if intensity >= directorMinIntensity && bs.directorCanSee(hotspot) {
+		if hotspot.Distance(bs.directorFocus) > directorSwitchRange {
+			if bs.directorHold >= directorHoldTime {
+				bs.directorFocus = hotspot
+				bs.directorHold = 0
+			}
+		} else {
+			bs.directorFocus = hotspot
+		}
+		frame := spread*directorFrameSpread + directorFrameMargin
+		viewport := float64(min(bs.camera.ViewportWidth, bs.camera.ViewportHeight))
+		targetZoom = math.Max(directorMinZoom, math.Min(directorMaxZoom, viewport/frame))
+	}
+	
+	// Ease in a little every frame so cuts look like camera moves
+	rate := 1 - math.Exp(-directorPanRate*bs.deltaTime)
+	zoom := bs.camera.GetZoom()
+	bs.camera.SetZoom(zoom + (targetZoom-zoom)*rate)
+	left, top, right, bottom := bs.camera.GetViewBounds()
+	center := gamemath.NewVector2D((left+right)/2, (top+bottom)/2)
+	center = center.Add(bs.directorFocus.Sub(center).Mul(rate))
+	bs.camera.CenterOn(center.X, center.Y)
+}
+
+// directorCanSee reports whether a unit the player can see stands near the position
+func (bs *BattleSceneUnified) directorCanSee(position gamemath.Vector2D) bool {
+	for _, army := range bs.battleManager.Armies {
+		for _, unit := range army.GetAliveUnits() {
+			if unit.Position.Distance(position) <= directorSightRange && bs.isUnitVisible(unit) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// drawDirectorStatus shows that the camera is following the fighting by itself
+func (bs *BattleSceneUnified) drawDirectorStatus(screen *ebiten.Image) {
+	bs.textRenderer.DrawText(screen, "ディレクターモード（C: 解除）", 800, 570, graphics.CurrentTheme().Accent)
+}
@@ -0,0 +1,112 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/graphics"
+)
+
+// Controller hint bar layout
+const (
+	padBarHeight  = 24
+	padGlyphSize  = 16
+	padHintGap    = 18
+	padPointerArm = 8 // 右スティックのカーソルの十字の長さ
+)
+
+// padHint is one entry of the controller hint bar: the glyph of a button and what it does
+type padHint struct {
+	glyph string // "A" "B" "X" "Y": 丸ボタン, "+": 十字キー, "L" "R": スティック, それ以外: 肩のボタン
+	label string
+}
+
+// Controller hints of the menus and the battle
+var (
+	menuPadHints = []padHint{
+		{"+", "選択"}, {"A", "決定"}, {"B", "戻る"}, {"R", "カーソル"},
+	}
+	battlePadHints = []padHint{
+		{"L", "カメラ"}, {"R", "カーソル"}, {"A", "選択"}, {"B", "移動命令"}, {"LT/RT", "ズーム"},
+		{"X", "作戦タイム"}, {"LB/RB", "速度"}, {"Y", "ヘルプ"}, {"START", "一時停止"},
+	}
+)
+
+// padFaceColors are the colors of the face buttons, as printed on the usual controllers
+var padFaceColors = map[string]color.RGBA{
+	"A": {96, 176, 72, 255},
+	"B": {208, 72, 64, 255},
+	"X": {64, 120, 208, 255},
+	"Y": {216, 176, 48, 255},
+}
+
+// DrawGamepadHints draws the controller hint bar at the bottom of the screen, and the cursor the
+// right stick moves, while a gamepad is in use; the battle shows its own hints, menus the common ones
+func (sm *SceneManager) DrawGamepadHints(screen *ebiten.Image, textRenderer *graphics.TextRenderer) {
+	if !controls.GamepadActive() || sm.transition.IsTransitioning {
+		return
+	}
+	if controls.GamepadPointer() {
+		drawPadPointer(screen)
+	}
+	
+	hints := menuPadHints
+	if sm.currentScene == SceneBattle && len(sm.stack) == 0 {
+		hints = battlePadHints
+	}
+	theme := graphics.CurrentTheme()
+	width := screen.Bounds().Dx()
+	top := float32(screen.Bounds().Dy() - padBarHeight)
+	vector.DrawFilledRect(screen, 0, top, float32(width), padBarHeight, graphics.WithAlpha(theme.Panel, 220), false)
+	
+	x := 10.0
+	y := float64(top) + (padBarHeight-padGlyphSize)/2
+	for _, hint := range hints {
+		x += drawPadGlyph(screen, textRenderer, hint.glyph, x, y) + 4
+		textRenderer.DrawText(screen, hint.label, x, y, theme.TextHint)
+		labelWidth, _ := textRenderer.MeasureText(hint.label)
+		x += labelWidth + padHintGap
+	}
+}
+
+// drawPadGlyph draws a controller button at the position and returns its width
+func drawPadGlyph(screen *ebiten.Image, textRenderer *graphics.TextRenderer, glyph string, x, y float64) float64 {
+	theme := graphics.CurrentTheme()
+	size := float32(padGlyphSize)
+	left, top := float32(x), float32(y)
+	
+	switch glyph {
+	case "A", "B", "X", "Y":
+		vector.DrawFilledCircle(screen, left+size/2, top+size/2, size/2, padFaceColors[glyph], true)
+		textRenderer.DrawCenteredText(screen, glyph, x+padGlyphSize/2, y+padGlyphSize/2, theme.TextBright)
+		return padGlyphSize
+	case "+":
+		arm := size / 3
+		vector.DrawFilledRect(screen, left+arm, top, arm, size, theme.TextBright, false)
+		vector.DrawFilledRect(screen, left, top+arm, size, arm, theme.TextBright, false)
+		return padGlyphSize
+	case "L", "R":
+		vector.StrokeCircle(screen, left+size/2, top+size/2, size/2-1, 2, theme.TextBright, true)
+		textRenderer.DrawCenteredText(screen, glyph, x+padGlyphSize/2, y+padGlyphSize/2, theme.TextBright)
+		return padGlyphSize
+	default:
+		textWidth, _ := textRenderer.MeasureText(glyph)
+		width := float32(textWidth) + 8
+		vector.DrawFilledRect(screen, left, top, width, size, theme.PanelDark, false)
+		vector.StrokeRect(screen, left, top, width, size, 1, theme.TextBright, false)
+		textRenderer.DrawText(screen, glyph, x+4, y, theme.TextBright)
+		return float64(width)
+	}
+}
+
+// drawPadPointer draws a crosshair where the right stick has moved the cursor
+func drawPadPointer(screen *ebiten.Image) {
+	x, y := controls.CursorPosition()
+	cx, cy := float32(x), float32(y)
+	accent := graphics.CurrentTheme().Accent
+	vector.StrokeLine(screen, cx-padPointerArm, cy, cx+padPointerArm, cy, 2, accent, true)
+	vector.StrokeLine(screen, cx, cy-padPointerArm, cx, cy+padPointerArm, 2, accent, true)
+	vector.StrokeCircle(screen, cx, cy, padPointerArm/2, 1, accent, true)
+}
@@ -0,0 +1,144 @@
+package scenes
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+)
+
+// Kill-cam tuning for the highlights reel
+const (
+	killCamZoom      = 1.6  // 見どころを映すカメラの拡大率
+	killCamSlowSpeed = 0.35 // 見どころの瞬間の前後の再生速度
+	killCamSlowTime  = 0.6  // 見どころの瞬間からこの秒数以内はスロー再生
+)
+
+// highlightReel is the last battle with the highlight clips recorded during it
+type highlightReel struct {
+	battleManager *game.BattleManager
+	clips         []*game.HighlightClip
+}
+
+// newHighlightReel keeps the clips of the recorder with at least one snapshot (nil: nothing to watch)
+func newHighlightReel(battleManager *game.BattleManager, recorder *game.HighlightRecorder) *highlightReel {
+	if recorder == nil {
+		return nil
+	}
+	var clips []*game.HighlightClip
+	for _, clip := range recorder.Clips {
+		if len(clip.Frames) > 0 {
+			clips = append(clips, clip)
+		}
+	}
+	if len(clips) == 0 {
+		return nil
+	}
+	return &highlightReel{battleManager: battleManager, clips: clips}
+}
+
+// recordHighlights films the highlight moments of the tick just simulated
+func (bs *BattleSceneUnified) recordHighlights() {
+	if bs.highlights != nil {
+		bs.highlights.Record(bs.battleManager)
+	}
+}
+
+// startHighlights shows the finished battle again to play the reel from its first clip
+func (bs *BattleSceneUnified) startHighlights(reel *highlightReel) {
+	bs.reel = reel
+	bs.reelZoom = bs.camera.GetZoom()
+	bs.battleManager = reel.battleManager
+	bs.history = nil
+	bs.highlights = nil
+	bs.selectedUnit = nil
+	bs.barks = nil
+	bs.showRulesCard = false
+	bs.placingTraps = false
+	bs.isPaused = false
+	bs.tacticalPause = false
+	bs.playHighlight(0)
+}
+
+// playHighlight jumps to the start of the clip
+func (bs *BattleSceneUnified) playHighlight(index int) {
+	bs.reelClip = index
+	bs.reelFrame = 0
+	bs.reelClock = 0
+	bs.battleManager.RestoreSnapshot(bs.reel.clips[index].Frames[0])
+	bs.camera.SetZoom(killCamZoom)
+	bs.followHighlight()
+}
+
+// finishHighlights returns to the result screen
+func (bs *BattleSceneUnified) finishHighlights() {
+	bs.camera.SetZoom(bs.reelZoom)
+	bs.sceneManager.gameData.WatchReel = false
+	bs.sceneManager.TransitionWith(SceneResult, nil, resultTransition)
+}
+
+// updateHighlights plays the reel's snapshots at the battle's pace, slowing down around each highlight
+// Space, Enter or a click skips to the next clip and Esc ends the reel
+func (bs *BattleSceneUnified) updateHighlights() {
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		bs.finishHighlights()
+		return
+	}
+	skip := controls.IsKeyJustPressed(ebiten.KeySpace) || controls.IsKeyJustPressed(ebiten.KeyEnter) ||
+		controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	
+	clip := bs.reel.clips[bs.reelClip]
+	speed := 1.0
+	if math.Abs(bs.battleManager.BattleTime-clip.Time) <= killCamSlowTime {
+		speed = killCamSlowSpeed
+	}
+	bs.reelClock += game.FrameSeconds(bs.deltaTime) * speed
+	for !skip && bs.reelFrame+1 < len(clip.Frames) {
+		gap := clip.Frames[bs.reelFrame+1].BattleTime - bs.battleManager.BattleTime
+		if gap > bs.reelClock {
+			break
+		}
+		bs.reelClock -= gap
+		bs.reelFrame++
+		bs.battleManager.RestoreSnapshot(clip.Frames[bs.reelFrame])
+	}
+	
+	// A clip played to its end moves on to the next one
+	if skip || bs.reelFrame+1 >= len(clip.Frames) {
+		if bs.reelClip+1 >= len(bs.reel.clips) {
+			bs.finishHighlights()
+			return
+		}
+		bs.playHighlight(bs.reelClip + 1)
+		return
+	}
+	bs.followHighlight()
+}
+
+// followHighlight keeps the kill-cam on the unit the clip is about, or on its spot
+func (bs *BattleSceneUnified) followHighlight() {
+	clip := bs.reel.clips[bs.reelClip]
+	focus := clip.Position
+	if unit := bs.battleManager.FindUnit(clip.UnitID); unit != nil {
+		focus = unit.Position
+	}
+	bs.camera.CenterOn(focus.X, focus.Y)
+}
+
+// drawHighlightBanner shows which highlight is playing and how to move through the reel
+func (bs *BattleSceneUnified) drawHighlightBanner(screen *ebiten.Image) {
+	banner := ebiten.NewImage(1024, 56)
+	banner.Fill(graphics.CurrentTheme().Overlay)
+	
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(0, 60)
+	screen.DrawImage(banner, op)
+	
+	clip := bs.reel.clips[bs.reelClip]
+	titleText := fmt.Sprintf("ハイライト %d/%d %s - %s", bs.reelClip+1, len(bs.reel.clips), clip.Kind, clip.Description())
+	bs.textRenderer.DrawCenteredText(screen, titleText, 512, 75, graphics.CurrentTheme().Highlight)
+	bs.textRenderer.DrawCenteredText(screen, "Space/クリック: 次へ  Esc: 結果画面へ", 512, 100, graphics.CurrentTheme().TextHint)
+}
@@ -0,0 +1,146 @@
+package scenes
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/graphics"
+)
+
+// HUD layout on the 1024x768 screen
+const (
+	hudScreenWidth  = 1024
+	hudScreenHeight = 768
+	hudColumns      = 4
+	hudRows         = 2
+	
+	// Camera pad sits above the buttons, clear of the edge scroll zone
+	cameraPadRight  = 58
+	cameraPadBottom = 182
+	cameraPadSize   = 26
+	
+	// Minimap size
+	minimapWidth  = 200
+	minimapHeight = 150
+)
+
+// hudPreset is a battle HUD layout: the standard one, or the compact one for small screens
+// with bigger text, larger buttons and fewer panels
+type hudPreset struct {
+	buttonWidth   int
+	buttonHeight  int
+	buttonGap     int
+	buttonsRight  int     // ボタン群と画面右端の距離
+	buttonsBottom int     // ボタン群と画面下端の距離
+	minimapLeft   int     // ミニマップと画面左端の距離
+	minimapBottom int     // ミニマップと画面下端の距離
+	textScale     float64 // HUDの文字の倍率
+	cameraPad     bool    // カメラ移動ボタンを出す
+	history       bool    // 選択ユニットの戦闘記録を出す
+	hints         bool    // 画面下の操作説明を出す
+}
+
+// HUD presets
+var (
+	standardHUD = hudPreset{
+		buttonWidth: 50, buttonHeight: 26, buttonGap: 4, buttonsRight: 32, buttonsBottom: 48,
+		minimapLeft: 50, minimapBottom: 0, textScale: 1.0, cameraPad: true, history: true, hints: true,
+	}
+	compactHUD = hudPreset{
+		buttonWidth: 72, buttonHeight: 40, buttonGap: 6, buttonsRight: 12, buttonsBottom: 12,
+		minimapLeft: 12, minimapBottom: 12, textScale: 1.3,
+	}
+)
+
+// battleHUD holds the on-screen buttons that make the battle playable by mouse alone
+type battleHUD struct {
+	preset hudPreset
+	
+	pauseButton    *graphics.Button
+	tacticalButton *graphics.Button
+	minimapButton  *graphics.Button
+	helpButton     *graphics.Button
+	zoomOutButton  *graphics.Button
+	zoomInButton   *graphics.Button
+	speedButton    *graphics.Button
+	backButton     *graphics.Button
+	
+	// Camera pad
+	upButton    *graphics.Button
+	downButton  *graphics.Button
+	leftButton  *graphics.Button
+	rightButton *graphics.Button
+	
+	// Command point meter, above the buttons
+	meterX int
+	meterY int
+}
+
+// newBattleHUD creates the battle HUD buttons, anchored to the bottom right of the screen
+func newBattleHUD(preset hudPreset) *battleHUD {
+	width := hudColumns*(preset.buttonWidth+preset.buttonGap) - preset.buttonGap
+	height := hudRows*(preset.buttonHeight+preset.buttonGap) - preset.buttonGap
+	buttonsX, buttonsY := graphics.AnchorBottomRight.Place(hudScreenWidth, hudScreenHeight, preset.buttonsRight, preset.buttonsBottom, width, height)
+	column := func(i int) int {
+		return buttonsX + i*(preset.buttonWidth+preset.buttonGap)
+	}
+	row := func(i int) int {
+		return buttonsY + i*(preset.buttonHeight+preset.buttonGap)
+	}
+	button := func(i, j int, label string) *graphics.Button {
+		return graphics.NewButton(column(i), row(j), preset.buttonWidth, preset.buttonHeight, label)
+	}
+	
+	padStep := cameraPadSize + 4
+	padX, padY := graphics.AnchorBottomRight.Place(hudScreenWidth, hudScreenHeight, cameraPadRight, cameraPadBottom, 3*padStep-4, 3*padStep-4)
+	
+	return &battleHUD{
+		preset:         preset,
+		pauseButton:    button(0, 0, "停止"),
+		tacticalButton: button(1, 0, "作戦"),
+		minimapButton:  button(2, 0, "地図"),
+		helpButton:     button(3, 0, "ヘルプ"),
+		zoomOutButton:  button(0, 1, "－"),
+		zoomInButton:   button(1, 1, "＋"),
+		speedButton:    button(2, 1, "x1"),
+		backButton:     button(3, 1, "戻る"),
+		upButton:       graphics.NewButton(padX+padStep, padY, cameraPadSize, cameraPadSize, "↑"),
+		leftButton:     graphics.NewButton(padX, padY+padStep, cameraPadSize, cameraPadSize, "←"),
+		rightButton:    graphics.NewButton(padX+2*padStep, padY+padStep, cameraPadSize, cameraPadSize, "→"),
+		downButton:     graphics.NewButton(padX+padStep, padY+2*padStep, cameraPadSize, cameraPadSize, "↓"),
+		meterX:         buttonsX + 20,
+		meterY:         buttonsY - 44,
+	}
+}
+
+// minimapPosition returns where the preset puts the minimap, anchored to the bottom left of the screen
+func (p hudPreset) minimapPosition() (int, int) {
+	return graphics.AnchorBottomLeft.Place(hudScreenWidth, hudScreenHeight, p.minimapLeft, p.minimapBottom, minimapWidth, minimapHeight)
+}
+
+// buttons returns every HUD button the preset shows
+func (h *battleHUD) buttons() []*graphics.Button {
+	buttons := []*graphics.Button{
+		h.pauseButton, h.tacticalButton, h.minimapButton, h.helpButton,
+		h.zoomOutButton, h.zoomInButton, h.speedButton, h.backButton,
+	}
+	if h.preset.cameraPad {
+		buttons = append(buttons, h.upButton, h.downButton, h.leftButton, h.rightButton)
+	}
+	return buttons
+}
+
+// IsHovered reports whether the mouse cursor is over any HUD button
+func (h *battleHUD) IsHovered() bool {
+	for _, button := range h.buttons() {
+		if button.IsHovered() {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw draws every HUD button
+func (h *battleHUD) Draw(screen *ebiten.Image, textRenderer *graphics.TextRenderer) {
+	for _, button := range h.buttons() {
+		button.Draw(screen, textRenderer)
+	}
+}
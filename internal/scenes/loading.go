@@ -0,0 +1,153 @@
+package scenes
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/graphics"
+)
+
+// Loading screen layout
+const (
+	loadingBarX      = 262
+	loadingBarY      = 380
+	loadingBarWidth  = 500
+	loadingBarHeight = 16
+	loadingTipTime   = 5.0 // 秒ごとに次のヒントに変わる
+)
+
+// loadingTips are shown one after another while the game loads
+var loadingTips = []string{
+	"ヒント: 作戦タイムでは、戦闘を止めたまま移動命令を予約できます",
+	"ヒント: リーダーが戦死すると部隊は逃げ出します。隊形の中で守りましょう",
+	"ヒント: 3方向以上から攻められた部隊は包囲され、防御力と士気が下がります",
+	"ヒント: 森では弓兵の攻撃力が上がり、騎兵は戦いにくくなります",
+	"ヒント: 斥候は森や藪に潜むと、すぐ近くまで来ないと見つかりません",
+	"ヒント: 1人プレイの戦闘は直近の5秒ずつ巻き戻して、別の命令を試せます",
+	"ヒント: 設定画面ではキー割り当てや画面の拡大率を変えられます",
+	"ヒント: ディレクターモードでは、カメラが激戦地を自動で追います",
+}
+
+// nextTip is the tip the next loading screen starts from, so each one shows another
+var nextTip int
+
+// LoadingProgress reports how far loading has got (0-1) and what it is doing now
+type LoadingProgress func(done float64, step string)
+
+// report passes the progress on, if anyone is listening
+func (progress LoadingProgress) report(done float64, step string) {
+	if progress != nil {
+		progress(done, step)
+	}
+}
+
+// LoadingTask is slow work the loading screen runs off the game loop
+type LoadingTask struct {
+	Run    func(progress LoadingProgress) // ゲームループとは別のゴルーチンで実行する
+	Finish func()                         // Run の後にゲームループで実行する（nil: なし）
+}
+
+// LoadingScene is pushed over a scene while a task runs in a goroutine, showing a progress bar and
+// tips, and pops itself once the task has finished
+// With recorded, replayed or scripted input the task runs within a single tick instead, so the
+// input lines up with the same ticks every time
+type LoadingScene struct {
+	sceneManager *SceneManager
+	textRenderer *graphics.TextRenderer
+	task         LoadingTask
+	started      bool
+	done         chan struct{}
+	
+	mutex    sync.Mutex
+	progress float64 // 0-1
+	step     string  // 今読み込んでいるもの
+	
+	tip     int
+	elapsed float64
+}
+
+// NewLoadingScene creates a loading screen for the task; push it over the scene that waits for it
+func NewLoadingScene(sceneManager *SceneManager, textRenderer *graphics.TextRenderer, task LoadingTask) *LoadingScene {
+	return &LoadingScene{
+		sceneManager: sceneManager,
+		textRenderer: textRenderer,
+		task:         task,
+		done:         make(chan struct{}),
+	}
+}
+
+// run runs the task and marks it done
+func (ls *LoadingScene) run() {
+	ls.task.Run(ls.setProgress)
+	ls.setProgress(1, "")
+	close(ls.done)
+}
+
+// setProgress records the progress reported by the task
+func (ls *LoadingScene) setProgress(done float64, step string) {
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+	ls.progress = max(ls.progress, min(done, 1))
+	ls.step = step
+}
+
+// Update starts the task, turns the tips and leaves once the task is done
+func (ls *LoadingScene) Update() error {
+	if !ls.started {
+		ls.started = true
+		if controls.GetMode() == controls.ModeLive {
+			go ls.run()
+		} else {
+			ls.run()
+		}
+	}
+	
+	ls.elapsed += controls.DeltaTime()
+	if ls.elapsed >= loadingTipTime {
+		ls.elapsed = 0
+		ls.tip = (ls.tip + 1) % len(loadingTips)
+	}
+	
+	select {
+	case <-ls.done:
+		if ls.task.Finish != nil {
+			ls.task.Finish()
+		}
+		ls.sceneManager.PopScene()
+	default:
+	}
+	return nil
+}
+
+// Draw draws the progress bar, what is being loaded and a tip
+func (ls *LoadingScene) Draw(screen *ebiten.Image) {
+	ls.mutex.Lock()
+	progress, step := ls.progress, ls.step
+	ls.mutex.Unlock()
+	
+	theme := graphics.CurrentTheme()
+	screen.Fill(theme.Background)
+	ls.textRenderer.DrawCenteredText(screen, "読み込み中...", 512, 330, theme.Text)
+	
+	vector.DrawFilledRect(screen, loadingBarX, loadingBarY, loadingBarWidth, loadingBarHeight, theme.Inset, false)
+	vector.DrawFilledRect(screen, loadingBarX, loadingBarY, float32(loadingBarWidth*progress), loadingBarHeight, theme.Accent, false)
+	vector.StrokeRect(screen, loadingBarX, loadingBarY, loadingBarWidth, loadingBarHeight, 1, theme.TextMuted, false)
+	
+	ls.textRenderer.DrawCenteredText(screen, fmt.Sprintf("%s %d%%", step, int(progress*100)), 512, 420, theme.TextMuted)
+	ls.textRenderer.DrawCenteredText(screen, loadingTips[ls.tip], 512, 520, theme.TextHint)
+}
+
+// OnEnter picks the tip to show first
+func (ls *LoadingScene) OnEnter(data interface{}) {
+	ls.tip = nextTip % len(loadingTips)
+	nextTip++
+	ls.elapsed = 0
+}
+
+// OnExit is called when the loading screen is gone
+func (ls *LoadingScene) OnExit() {
+	// Nothing to clean up; the task has finished
+}
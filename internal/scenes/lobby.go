@@ -0,0 +1,554 @@
+package scenes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/config"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/netplay"
+)
+
+// lobbyMode is what the lobby scene is doing
+type lobbyMode int
+
+const (
+	lobbyBrowse  lobbyMode = iota // LAN のホストを探す
+	lobbyHosting                  // ゲームを立てて相方を待つ・準備確認
+	lobbyJoined                   // ホストに接続して準備確認
+)
+
+// Host settings rows; the mutator rows follow, then the ready and back buttons
+const (
+	lobbyStageRow   = 0
+	lobbyPresetRow  = 1
+	lobbyBudgetRow  = 2
+	lobbyMutatorRow = 3
+)
+
+// Lobby layout
+const (
+	lobbyListX   = 120
+	lobbyListY   = 150
+	lobbyRowStep = 28
+)
+
+// LobbyScene lists co-op games on the LAN and runs the ready check before a networked battle
+type LobbyScene struct {
+	sceneManager *SceneManager
+	config       *config.Config
+	textRenderer *graphics.TextRenderer
+	
+	mode         lobbyMode
+	selectedItem int
+	status       string // 最後の接続エラーなど
+	
+	// Connections
+	browser    *netplay.Browser
+	listener   *netplay.Listener
+	advertiser *netplay.Advertiser
+	session    *netplay.Session
+	hosts      []netplay.HostInfo
+	
+	// Host settings
+	stages          []data.StageListing
+	selectedStage   int
+	presets         []string
+	selectedPreset  int
+	commandPoints   bool
+	mutators        []*game.Mutator
+	enabledMutators map[string]bool
+	
+	// Ready check
+	ready         bool
+	readyRevision int // 準備完了したときの設定の版（ゲスト）
+}
+
+// NewLobbyScene creates a new lobby scene
+func NewLobbyScene(sceneManager *SceneManager, dataManager *data.DataManager, cfg *config.Config, textRenderer *graphics.TextRenderer) *LobbyScene {
+	return &LobbyScene{
+		sceneManager:    sceneManager,
+		config:          cfg,
+		textRenderer:    textRenderer,
+		stages:          stageChoices(dataManager),
+		presets:         presetChoices(dataManager),
+		mutators:        game.GetMutators(),
+		enabledMutators: make(map[string]bool),
+	}
+}
+
+// OnEnter starts looking for hosts; a finished co-op session is dropped so a new pair can form
+func (ls *LobbyScene) OnEnter(data interface{}) {
+	if coop := ls.sceneManager.gameData.Coop; coop != nil {
+		coop.Close()
+		ls.sceneManager.SetCoopSession(nil)
+	}
+	
+	ls.selectedStage = 0
+	ls.selectedPreset = 0
+	ls.commandPoints = ls.config != nil && ls.config.Game.CommandPoints
+	ls.enabledMutators = make(map[string]bool)
+	ls.status = ""
+	ls.browse()
+}
+
+// OnExit closes every connection that was not handed to the battle
+func (ls *LobbyScene) OnExit() {
+	ls.closeAll()
+}
+
+// closeAll stops discovery and drops the partner
+func (ls *LobbyScene) closeAll() {
+	if ls.browser != nil {
+		ls.browser.Close()
+		ls.browser = nil
+	}
+	ls.stopHosting()
+	if ls.session != nil {
+		ls.session.Close()
+		ls.session = nil
+	}
+	ls.hosts = nil
+	ls.ready = false
+}
+
+// stopHosting stops accepting and advertising
+func (ls *LobbyScene) stopHosting() {
+	if ls.listener != nil {
+		ls.listener.Close()
+		ls.listener = nil
+	}
+	if ls.advertiser != nil {
+		ls.advertiser.Close()
+		ls.advertiser = nil
+	}
+}
+
+// browse switches to the host list
+func (ls *LobbyScene) browse() {
+	ls.closeAll()
+	ls.mode = lobbyBrowse
+	ls.selectedItem = 0
+	
+	browser, err := netplay.Browse()
+	if err != nil {
+		ls.status = "ホストを探せません: " + err.Error()
+		return
+	}
+	ls.browser = browser
+}
+
+// host opens a game on the LAN and waits for a partner
+func (ls *LobbyScene) host() {
+	ls.closeAll()
+	ls.mode = lobbyHosting
+	ls.selectedItem = 0
+	
+	listener, err := netplay.Listen(fmt.Sprintf(":%d", netplay.DefaultPort))
+	if err != nil {
+		ls.status = "ゲームを立てられません: " + err.Error()
+		ls.mode = lobbyBrowse
+		return
+	}
+	ls.listener = listener
+	
+	advertiser, err := netplay.Advertise(ls.beacon())
+	if err != nil {
+		// Partners can still join by address with -join
+		ls.status = "LANに告知できません: " + err.Error()
+	}
+	ls.advertiser = advertiser
+}
+
+// join connects to a host found on the LAN
+func (ls *LobbyScene) join(host netplay.HostInfo) {
+	session, err := netplay.Join(host.Address)
+	if err != nil {
+		ls.status = "接続できません: " + err.Error()
+		return
+	}
+	
+	ls.closeAll()
+	ls.session = session
+	ls.mode = lobbyJoined
+	ls.selectedItem = 0
+	ls.status = ""
+}
+
+// settings returns the host's current battle settings
+func (ls *LobbyScene) settings() netplay.Setup {
+	var mutatorIDs []string
+	for _, mutator := range ls.mutators {
+		if ls.enabledMutators[mutator.ID] {
+			mutatorIDs = append(mutatorIDs, mutator.ID)
+		}
+	}
+	return netplay.Setup{
+		Stage:         ls.stages[ls.selectedStage].Name,
+		Preset:        ls.presets[ls.selectedPreset],
+		Mutators:      mutatorIDs,
+		CommandPoints: ls.commandPoints,
+	}
+}
+
+// beacon returns what the host broadcasts on the LAN
+func (ls *LobbyScene) beacon() netplay.Beacon {
+	beacon := netplay.Beacon{
+		Name:     netplay.HostName(),
+		Settings: ls.settings(),
+		Full:     ls.session != nil,
+	}
+	if ls.listener != nil {
+		beacon.Port = ls.listener.Port()
+	}
+	return beacon
+}
+
+// settingsChanged shares new host settings; both players have to get ready again
+func (ls *LobbyScene) settingsChanged() {
+	ls.ready = false
+	if ls.advertiser != nil {
+		ls.advertiser.Update(ls.beacon())
+	}
+	if ls.session != nil {
+		ls.session.SendSettings(ls.settings())
+	}
+}
+
+// Update updates the lobby scene
+func (ls *LobbyScene) Update() error {
+	switch ls.mode {
+	case lobbyBrowse:
+		ls.updateBrowse()
+	case lobbyHosting:
+		ls.updateHosting()
+	case lobbyJoined:
+		ls.updateJoined()
+	}
+	return nil
+}
+
+// moveSelection steps the selected row with the arrow keys
+func (ls *LobbyScene) moveSelection(items int) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		ls.selectedItem = (ls.selectedItem - 1 + items) % items
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		ls.selectedItem = (ls.selectedItem + 1) % items
+	}
+}
+
+// confirmed reports whether the selected row was activated by key or by clicking it
+func (ls *LobbyScene) confirmed(rows []string) bool {
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) {
+		return true
+	}
+	if !controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return false
+	}
+	for i, row := range rows {
+		if isCursorOverText(ls.textRenderer, "> "+row, lobbyListX-20, ls.rowY(i)) {
+			ls.selectedItem = i
+			return true
+		}
+	}
+	return false
+}
+
+// rowY returns the screen Y of the i-th row
+func (ls *LobbyScene) rowY(i int) float64 {
+	return float64(lobbyListY + lobbyRowStep*i)
+}
+
+// updateBrowse refreshes the host list and joins or hosts
+func (ls *LobbyScene) updateBrowse() {
+	if ls.browser != nil {
+		ls.hosts = ls.browser.Hosts()
+	}
+	rows := ls.browseRows()
+	ls.moveSelection(len(rows))
+	ls.selectedItem = min(ls.selectedItem, len(rows)-1)
+	
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		ls.sceneManager.TransitionTo(SceneTitle, nil)
+		return
+	}
+	if !ls.confirmed(rows) {
+		return
+	}
+	
+	switch index := ls.selectedItem - 1; {
+	case ls.selectedItem == 0:
+		ls.host()
+	case index < len(ls.hosts):
+		if ls.hosts[index].Full {
+			ls.status = "このゲームは満員です"
+		} else {
+			ls.join(ls.hosts[index])
+		}
+	default: // 戻る
+		ls.sceneManager.TransitionTo(SceneTitle, nil)
+	}
+}
+
+// browseRows returns the rows of the host list: hosting, the hosts found, then back
+func (ls *LobbyScene) browseRows() []string {
+	rows := []string{"ゲームを立てる"}
+	for _, host := range ls.hosts {
+		row := fmt.Sprintf("%s（%s）  %s", host.Name, host.Address, describeSettings(host.Settings))
+		if host.Full {
+			row += "  [満員]"
+		}
+		rows = append(rows, row)
+	}
+	return append(rows, "戻る")
+}
+
+// updateHosting waits for a partner, edits the settings and starts once both are ready
+func (ls *LobbyScene) updateHosting() {
+	// A partner connecting takes the open slot
+	if ls.session == nil && ls.listener != nil {
+		session, err := ls.listener.Accept()
+		if err != nil {
+			ls.status = "接続に失敗しました: " + err.Error()
+			ls.host()
+			return
+		}
+		if session != nil {
+			ls.session = session
+			ls.status = ""
+			ls.settingsChanged()
+		}
+	}
+	
+	// A lost partner frees the slot again
+	if ls.session != nil && ls.session.Err() != nil {
+		ls.host()
+		ls.status = "相方との接続が切れました"
+		return
+	}
+	
+	rows := ls.hostingRows()
+	ls.moveSelection(len(rows))
+	if controls.IsKeyJustPressed(ebiten.KeyArrowLeft) {
+		ls.cycleSetting(-1)
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowRight) {
+		ls.cycleSetting(1)
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		ls.browse()
+		return
+	}
+	
+	if ls.confirmed(rows) {
+		switch ls.selectedItem {
+		case len(rows) - 2: // 準備完了
+			if ls.session != nil {
+				ls.ready = !ls.ready
+				ls.session.SetReady(ls.ready)
+			}
+		case len(rows) - 1: // 戻る
+			ls.browse()
+			return
+		default:
+			ls.cycleSetting(1)
+		}
+	}
+	
+	// Both ready: the host gives the signal and both sides enter the battle
+	if ls.ready && ls.session != nil && ls.session.PartnerReady() {
+		if err := ls.session.SendStart(); err == nil {
+			ls.startBattle(ls.settings())
+		}
+	}
+}
+
+// hostingRows returns the setting rows followed by the ready and back buttons
+func (ls *LobbyScene) hostingRows() []string {
+	rows := []string{
+		"ステージ: < " + ls.stages[ls.selectedStage].Name + " >" + difficultyText(ls.stages[ls.selectedStage]),
+		"編成: < " + ls.presets[ls.selectedPreset] + " >",
+		"指揮力（命令の予算）: " + onOff(ls.commandPoints),
+	}
+	for _, mutator := range ls.mutators {
+		if ls.enabledMutators[mutator.ID] {
+			rows = append(rows, "[x] "+mutator.Name)
+		} else {
+			rows = append(rows, "[ ] "+mutator.Name)
+		}
+	}
+	return append(rows, ls.readyText(), "戻る")
+}
+
+// cycleSetting steps the stage or preset, or toggles the budget or a mutator
+func (ls *LobbyScene) cycleSetting(delta int) {
+	switch ls.selectedItem {
+	case lobbyStageRow:
+		ls.selectedStage = (ls.selectedStage + delta + len(ls.stages)) % len(ls.stages)
+	case lobbyPresetRow:
+		ls.selectedPreset = (ls.selectedPreset + delta + len(ls.presets)) % len(ls.presets)
+	case lobbyBudgetRow:
+		ls.commandPoints = !ls.commandPoints
+	default:
+		index := ls.selectedItem - lobbyMutatorRow
+		if index < 0 || index >= len(ls.mutators) {
+			return
+		}
+		id := ls.mutators[index].ID
+		ls.enabledMutators[id] = !ls.enabledMutators[id]
+	}
+	ls.settingsChanged()
+}
+
+// updateJoined follows the host's settings and starts when the host does
+func (ls *LobbyScene) updateJoined() {
+	if ls.session == nil || ls.session.Err() != nil {
+		ls.browse()
+		ls.status = "ホストとの接続が切れました"
+		return
+	}
+	
+	// New settings need a new ready
+	settings, revision, ok := ls.session.Settings()
+	if ls.ready && revision != ls.readyRevision {
+		ls.ready = false
+	}
+	if ls.session.Started() {
+		ls.startBattle(settings)
+		return
+	}
+	
+	rows := []string{ls.readyText(), "戻る"}
+	ls.moveSelection(len(rows))
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		ls.browse()
+		return
+	}
+	if !ls.confirmed(rows) {
+		return
+	}
+	
+	switch ls.selectedItem {
+	case 0: // 準備完了
+		if ok {
+			ls.ready = !ls.ready
+			ls.readyRevision = revision
+			ls.session.SetReady(ls.ready)
+		}
+	case 1: // 戻る
+		ls.browse()
+	}
+}
+
+// readyText labels the ready toggle
+func (ls *LobbyScene) readyText() string {
+	if ls.ready {
+		return "準備完了 [x]"
+	}
+	return "準備完了 [ ]"
+}
+
+// startBattle hands the session to the battle scene, which agrees on the seed with the partner
+func (ls *LobbyScene) startBattle(settings netplay.Setup) {
+	ls.sceneManager.SetCoopSession(ls.session)
+	ls.session = nil
+	ls.ready = false
+	ls.sceneManager.TransitionTo(SceneBattle, map[string]interface{}{
+		"stage":     settings.Stage,
+		"preset":    settings.Preset,
+		"mutators":  settings.Mutators,
+		"doctrines": []string{},
+		"handicaps": []game.Handicap{},
+	})
+}
+
+// describeSettings summarizes battle settings for the host list and the guest
+func describeSettings(settings netplay.Setup) string {
+	parts := []string{settings.Stage, settings.Preset, "指揮力" + onOff(settings.CommandPoints)}
+	if len(settings.Mutators) > 0 {
+		var names []string
+		for _, id := range settings.Mutators {
+			if mutator := game.GetMutator(id); mutator != nil {
+				names = append(names, mutator.Name)
+			}
+		}
+		parts = append(parts, "特殊ルール: "+strings.Join(names, "・"))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// onOff labels a toggle
+func onOff(enabled bool) string {
+	if enabled {
+		return "あり"
+	}
+	return "なし"
+}
+
+// Draw draws the lobby scene
+func (ls *LobbyScene) Draw(screen *ebiten.Image) {
+	screen.Fill(graphics.CurrentTheme().Background)
+	
+	var title, hint, state string
+	var rows []string
+	switch ls.mode {
+	case lobbyBrowse:
+		title = "LAN協力プレイ"
+		hint = "↑↓: 選択  Enter/クリック: 決定  Esc: タイトルへ"
+		rows = ls.browseRows()
+		state = "同じLANのゲームを探しています…"
+		if len(ls.hosts) == 0 {
+			state += "（見つかったゲームはありません）"
+		}
+	case lobbyHosting:
+		title = "ゲームを立てる"
+		hint = "↑↓: 選択  ←→/Enter: 変更  Esc: やめる"
+		rows = ls.hostingRows()
+		state = fmt.Sprintf("%s でホスト中  相方を待っています…", netplay.HostName())
+		if ls.session != nil {
+			state = "相方: 接続済み  " + partnerReadyText(ls.session.PartnerReady())
+		}
+	case lobbyJoined:
+		title = "準備確認"
+		hint = "↑↓: 選択  Enter/クリック: 決定  Esc: 抜ける"
+		rows = []string{ls.readyText(), "戻る"}
+		state = "ホストの設定を待っています…"
+		if ls.session == nil {
+			break
+		}
+		if settings, _, ok := ls.session.Settings(); ok {
+			state = "ホストの設定: " + describeSettings(settings)
+			ls.textRenderer.DrawText(screen, "ホスト: "+partnerReadyText(ls.session.PartnerReady()), lobbyListX, 560, graphics.CurrentTheme().Text)
+		}
+	}
+	
+	ls.textRenderer.DrawTextWithSize(screen, title, 400, 50, graphics.CurrentTheme().Text, 24)
+	ls.textRenderer.DrawText(screen, state, lobbyListX, 110, graphics.CurrentTheme().TextMuted)
+	for i, row := range rows {
+		y := ls.rowY(i)
+		if i == ls.selectedItem {
+			ls.textRenderer.DrawTextWithShadow(screen, "> "+row, lobbyListX-20, y,
+				graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+		} else {
+			ls.textRenderer.DrawText(screen, row, lobbyListX, y, graphics.CurrentTheme().Text)
+		}
+	}
+	
+	if ls.status != "" {
+		ls.textRenderer.DrawText(screen, ls.status, lobbyListX, 600, graphics.CurrentTheme().Danger)
+	}
+	ls.textRenderer.DrawText(screen, hint, lobbyListX, 700, graphics.CurrentTheme().TextMuted)
+}
+
+// partnerReadyText labels the partner's ready state
+func partnerReadyText(ready bool) string {
+	if ready {
+		return "準備完了"
+	}
+	return "準備中"
+}
@@ -0,0 +1,137 @@
+package scenes
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/graphics"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+	"github.com/shirou/tinygocha/internal/netplay"
+)
+
+// Observer replay tuning
+const (
+	observeCatchUpThreshold = 30  // これより多くの tick が溜まったら早送りで追いつく
+	observeCatchUpTicks     = 240 // 早送り中に1フレームで進める tick の数
+)
+
+// spectateFree is the observer view with a free camera and no fog of war
+// Views 1 and up follow that co-op player's groups and see only what the player's army sees
+const spectateFree = 0
+
+// startBroadcast streams the host's new battle to observers, opening the observer port on first use
+func (bs *BattleSceneUnified) startBroadcast(setup netplay.Setup) {
+	gameData := bs.sceneManager.gameData
+	if gameData.Broadcast == nil {
+		delay := 0.0
+		if bs.config != nil {
+			delay = bs.config.Game.ObserverDelay
+		}
+		broadcast, err := netplay.Broadcast(fmt.Sprintf(":%d", netplay.ObserverPort), int(delay/netplay.TickDelta))
+		if err != nil {
+			fmt.Printf("Co-op: %v, observers cannot join\n", err)
+			return
+		}
+		gameData.Broadcast = broadcast
+	}
+	gameData.Broadcast.StartBattle(setup)
+}
+
+// syncObservedBattle waits for the host to stream a battle and sets it up the same way
+func (bs *BattleSceneUnified) syncObservedBattle(watcher *netplay.Watcher) (netplay.Setup, error) {
+	fmt.Println("Observer: waiting for the host to stream a battle")
+	setup, err := watcher.WaitBattle()
+	if err != nil {
+		return setup, err
+	}
+	bs.adoptSetup(setup)
+	return setup, nil
+}
+
+// stepObserver replays the streamed ticks and reports whether the battle advanced
+func (bs *BattleSceneUnified) stepObserver() bool {
+	// One tick per frame keeps pace with the players; a stream joined late is fast-forwarded
+	ticks := 1
+	if bs.observer.Buffered() > observeCatchUpThreshold {
+		ticks = observeCatchUpTicks
+	}
+	
+	advanced := false
+	for i := 0; i < ticks && bs.battleManager.IsActive; i++ {
+		commands, ok := bs.observer.Next()
+		if !ok {
+			break
+		}
+		bs.applyCommands(commands)
+		bs.battleManager.Update(netplay.TickDelta)
+		bs.recordHighlights()
+		advanced = true
+	}
+	
+	if !advanced && !bs.observeLost {
+		if err := bs.observer.Err(); err != nil {
+			fmt.Printf("Observer: %v\n", err)
+			bs.observeLost = true
+			bs.battleManager.Announce("配信が切れました")
+		}
+	}
+	bs.followSpectatedPlayer()
+	return advanced
+}
+
+// handleSpectateInput cycles the observer's view between the free camera and each player's groups
+func (bs *BattleSceneUnified) handleSpectateInput() {
+	if controls.IsKeyJustPressed(ebiten.KeyV) {
+		bs.spectateView = (bs.spectateView + 1) % (netplay.Players + 1)
+		bs.selectedUnit = nil
+	}
+}
+
+// followSpectatedPlayer centers the camera on the groups of the player being watched
+func (bs *BattleSceneUnified) followSpectatedPlayer() {
+	if bs.spectateView == spectateFree {
+		return
+	}
+	army := bs.battleManager.GetArmy(playerArmyID)
+	if army == nil {
+		return
+	}
+	
+	var center gamemath.Vector2D
+	count := 0
+	for _, group := range army.Groups {
+		if group.Controller != bs.spectateView {
+			continue
+		}
+		for _, unit := range group.GetAllUnits() {
+			if unit.IsAlive && !unit.IsRetreating {
+				center = center.Add(unit.Position)
+				count++
+			}
+		}
+	}
+	if count > 0 {
+		center = center.Mul(1 / float64(count))
+		bs.camera.CenterOn(center.X, center.Y)
+	}
+}
+
+// drawObserverStatus shows the view being watched and whether the stream is catching up
+func (bs *BattleSceneUnified) drawObserverStatus(screen *ebiten.Image) {
+	view := "自由カメラ"
+	if bs.spectateView != spectateFree {
+		view = fmt.Sprintf("P%d の視点", bs.spectateView)
+	}
+	statusText := fmt.Sprintf("観戦中 %s（V: 切替）", view)
+	statusColor := graphics.CurrentTheme().Accent
+	switch {
+	case bs.observeLost:
+		statusText += "  配信切断"
+		statusColor = graphics.CurrentTheme().Danger
+	case bs.observer.Buffered() > observeCatchUpThreshold:
+		statusText += "  早送り中…"
+		statusColor = graphics.CurrentTheme().Highlight
+	}
+	bs.textRenderer.DrawText(screen, statusText, 800, 590, statusColor)
+}
@@ -0,0 +1,666 @@
+package scenes
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/campaign"
+	"github.com/shirou/tinygocha/internal/config"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// Overworld layout
+const (
+	provinceRadius   = 22
+	overworldPanelX  = 780
+	overworldPanelY  = 80
+	overworldRowStep = 20
+	armyFlagWidth    = 10
+	armyFlagHeight   = 14
+	eventBoxX        = 100
+	eventBoxY        = 230
+	eventBoxWidth    = 620
+	eventBoxHeight   = 220
+	provinceThumb    = 140 // 地方にカーソルを合わせると出る戦場のサムネイルの長い辺
+)
+
+// unclaimedColor is the display color of provinces no side holds
+var unclaimedColor = color.RGBA{127, 140, 141, 255}
+
+// recruitKeys raise the recruits in the order of the campaign config, and answer events
+var recruitKeys = []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4}
+
+// equipmentKeys cycle the items in each equipment slot of the selected group's leader
+var equipmentKeys = []struct {
+	key   ebiten.Key
+	slot  string
+	label string
+}{
+	{ebiten.KeyZ, data.SlotWeapon, "武器"},
+	{ebiten.KeyX, data.SlotArmor, "防具"},
+	{ebiten.KeyC, data.SlotBanner, "旗"},
+}
+
+// OverworldScene is the campaign's strategic map: armies march between provinces turn by turn,
+// and provinces where both sides meet are fought over in the battle scene
+type OverworldScene struct {
+	sceneManager *SceneManager
+	dataManager  *data.DataManager
+	config       *config.Config
+	textRenderer *graphics.TextRenderer
+	
+	selectedArmy     *campaign.Army
+	selectedGroup    int                // 装備を選んでいる選択中の軍勢の部隊
+	selectedProvince *campaign.Province // 徴募する地方（nil: 未選択）
+	status           string
+	estimate         *game.AutoResolveResult // 最初の合戦の自動解決の見積もり（nil: 未計算）
+}
+
+// NewOverworldScene creates a new overworld scene
+func NewOverworldScene(sceneManager *SceneManager, dataManager *data.DataManager, cfg *config.Config, textRenderer *graphics.TextRenderer) *OverworldScene {
+	return &OverworldScene{
+		sceneManager: sceneManager,
+		dataManager:  dataManager,
+		config:       cfg,
+		textRenderer: textRenderer,
+	}
+}
+
+// OnEnter resumes the saved campaign map the first time it is opened
+func (ows *OverworldScene) OnEnter(data interface{}) {
+	if ows.sceneManager.gameData.Overworld == nil {
+		state, err := save.LoadCampaign(save.DefaultCampaignPath)
+		if err != nil {
+			fmt.Printf("Warning: Failed to load campaign: %v\n", err)
+			state = save.NewCampaign()
+		}
+		ows.sceneManager.gameData.Overworld = campaign.LoadOverworld(*ows.dataManager.Campaign, state)
+	}
+	if ows.config != nil {
+		ows.sceneManager.gameData.Overworld.SetDifficulty(ows.config.Game.Difficulty, ows.config.Game.RubberBanding)
+	}
+	
+	// Whatever battle was being fought is over or abandoned
+	ows.sceneManager.gameData.Province = ""
+	ows.selectedArmy = nil
+	ows.selectedProvince = nil
+	ows.estimate = nil
+	ows.status = ""
+}
+
+// OnExit is called when exiting this scene
+func (ows *OverworldScene) OnExit() {
+	// Nothing to clean up
+}
+
+// overworld returns the campaign map being played
+func (ows *OverworldScene) overworld() *campaign.Overworld {
+	return ows.sceneManager.gameData.Overworld
+}
+
+// Update handles marching, battles and the end of the turn
+func (ows *OverworldScene) Update() error {
+	overworld := ows.overworld()
+	
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		ows.sceneManager.TransitionTo(SceneTitle, nil)
+		return nil
+	}
+	
+	// A finished campaign can only be started over
+	if overworld.Winner() != "" {
+		if controls.IsKeyJustPressed(ebiten.KeyN) {
+			ows.sceneManager.gameData.Overworld = campaign.NewOverworld(*ows.dataManager.Campaign)
+			saveOverworld(ows.overworld())
+			ows.selectedArmy = nil
+			ows.selectedProvince = nil
+			ows.status = "新しいキャンペーンを開始しました"
+		}
+		return nil
+	}
+	
+	// The event of the turn is answered before anything else
+	if event := overworld.Event(); event != nil {
+		ows.answerEvent(event)
+		return nil
+	}
+	
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		if province := ows.provinceAtCursor(); province != nil {
+			ows.clickProvince(province)
+		}
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyTab) {
+		ows.cycleArmy()
+	}
+	
+	// Equip the leaders of the selected army
+	if army := ows.selectedArmy; army != nil && len(army.Groups) > 0 {
+		if controls.IsKeyJustPressed(ebiten.KeyG) {
+			ows.selectedGroup = (ows.selectedGroup + 1) % len(army.Groups)
+		}
+		for _, equipmentKey := range equipmentKeys {
+			if controls.IsKeyJustPressed(equipmentKey.key) {
+				ows.cycleItem(equipmentKey.slot)
+			}
+		}
+	}
+	
+	for i, key := range recruitKeys {
+		if i < len(overworld.Recruits()) && controls.IsKeyJustPressed(key) {
+			ows.recruit(overworld.Recruits()[i])
+		}
+	}
+	
+	battles := overworld.Battles()
+	if len(battles) > 0 {
+		if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) {
+			ows.fight(battles[0])
+			return nil
+		}
+		if controls.IsKeyJustPressed(ebiten.KeyA) {
+			ows.autoResolve(battles[0])
+		}
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyE) {
+		if err := overworld.EndTurn(); err != nil {
+			ows.status = err.Error()
+		} else {
+			ows.selectedArmy = nil
+			ows.selectedProvince = nil
+			ows.estimate = nil
+			ows.status = fmt.Sprintf("ターン%d: 敵軍が進軍しました", overworld.Turn)
+			if overworld.Disbanded > 0 {
+				ows.status = fmt.Sprintf("ターン%d: 維持費が払えず%d部隊が離散しました", overworld.Turn, overworld.Disbanded)
+			}
+			saveOverworld(overworld)
+		}
+	}
+	
+	return nil
+}
+
+// clickProvince marches the selected army to a neighbouring province, or selects the province and an army standing in it
+func (ows *OverworldScene) clickProvince(province *campaign.Province) {
+	overworld := ows.overworld()
+	if army := ows.selectedArmy; army != nil && overworld.IsLinked(army.Province, province.ID) {
+		if err := overworld.MoveArmy(army, province.ID); err != nil {
+			ows.status = err.Error()
+			return
+		}
+		ows.status = fmt.Sprintf("%sが%sへ進軍しました", army.Name, province.Name)
+		if overworld.IsContested(province.ID) {
+			ows.status += "（合戦）"
+		}
+		ows.selectedArmy = nil
+		ows.selectedProvince = nil
+		ows.estimate = nil
+		saveOverworld(overworld)
+		return
+	}
+	
+	// Prefer an army that can still march
+	ows.selectedProvince = province
+	ows.selectedArmy = nil
+	ows.selectedGroup = 0
+	for _, army := range overworld.ArmiesAt(province.ID, data.SidePlayer) {
+		if ows.selectedArmy == nil || (ows.selectedArmy.Moved && !army.Moved) {
+			ows.selectedArmy = army
+		}
+	}
+}
+
+// cycleArmy selects the next player army on the map
+func (ows *OverworldScene) cycleArmy() {
+	var playerArmies []*campaign.Army
+	for _, army := range ows.overworld().Armies {
+		if army.Side == data.SidePlayer {
+			playerArmies = append(playerArmies, army)
+		}
+	}
+	if len(playerArmies) == 0 {
+		return
+	}
+	
+	next := 0
+	for i, army := range playerArmies {
+		if army == ows.selectedArmy {
+			next = (i + 1) % len(playerArmies)
+		}
+	}
+	ows.selectedArmy = playerArmies[next]
+	ows.selectedGroup = 0
+	ows.selectedProvince = ows.overworld().GetProvince(ows.selectedArmy.Province)
+}
+
+// cycleItem equips the selected group's leader with the next item that fits the slot, or empties the slot after the last
+func (ows *OverworldScene) cycleItem(slot string) {
+	army := ows.selectedArmy
+	ows.selectedGroup = min(ows.selectedGroup, len(army.Groups)-1)
+	choices := append([]string{""}, ows.dataManager.GetSlotItemIDs(slot)...)
+	current := army.Groups[ows.selectedGroup].Equipment.Get(slot)
+	next := 0
+	for i, id := range choices {
+		if id == current {
+			next = (i + 1) % len(choices)
+		}
+	}
+	
+	if err := ows.overworld().Equip(army, ows.selectedGroup, slot, choices[next]); err != nil {
+		ows.status = err.Error()
+		return
+	}
+	ows.estimate = nil
+	ows.status = ""
+	saveOverworld(ows.overworld())
+}
+
+// itemName returns the display name of an item, or "なし" for an empty slot
+func (ows *OverworldScene) itemName(itemID string) string {
+	if itemID == "" {
+		return "なし"
+	}
+	if item, err := ows.dataManager.GetItemConfig(itemID); err == nil {
+		return item.Name
+	}
+	return itemID
+}
+
+// unitName returns the display name of a unit type, or its ID if unknown
+func (ows *OverworldScene) unitName(unitType string) string {
+	if config, err := ows.dataManager.GetUnitConfig(unitType); err == nil && config.Name != "" {
+		return config.Name
+	}
+	return unitType
+}
+
+// answerEvent applies the choice picked with the number keys; events without choices close with Enter
+func (ows *OverworldScene) answerEvent(event *data.CampaignEventConfig) {
+	choice := -1
+	for i, key := range recruitKeys {
+		if i < len(event.Choices) && controls.IsKeyJustPressed(key) {
+			choice = i
+		}
+	}
+	if len(event.Choices) == 0 && controls.IsKeyJustPressed(ebiten.KeyEnter) {
+		choice = 0
+	}
+	if choice < 0 {
+		return
+	}
+	
+	result, err := ows.overworld().ChooseEvent(choice)
+	if err != nil {
+		ows.status = err.Error()
+		return
+	}
+	ows.selectedArmy = nil
+	ows.estimate = nil
+	ows.status = result
+	saveOverworld(ows.overworld())
+}
+
+// eventChoiceText describes a choice with its effects, like "薬師を雇う（軍資金-80）"
+func eventChoiceText(choice data.EventChoiceConfig) string {
+	var effects []string
+	if choice.Gold != 0 {
+		effects = append(effects, fmt.Sprintf("軍資金%+d", choice.Gold))
+	}
+	if choice.Disband > 0 {
+		effects = append(effects, fmt.Sprintf("部隊-%d", choice.Disband))
+	}
+	if choice.Recruit != "" {
+		effects = append(effects, choice.Recruit+"が加わる")
+	}
+	if choice.Revolt > 0 {
+		effects = append(effects, fmt.Sprintf("守りのない地方が%dつ離反", choice.Revolt))
+	}
+	if len(effects) == 0 {
+		return choice.Label
+	}
+	return choice.Label + "（" + strings.Join(effects, "、") + "）"
+}
+
+// recruit raises the group in the selected province and selects the army it joined
+func (ows *OverworldScene) recruit(recruit data.RecruitConfig) {
+	if ows.selectedProvince == nil {
+		ows.status = "徴募する地方を選んでください"
+		return
+	}
+	army, err := ows.overworld().Recruit(data.SidePlayer, ows.selectedProvince.ID, recruit)
+	if err != nil {
+		ows.status = err.Error()
+		return
+	}
+	ows.selectedArmy = army
+	ows.estimate = nil
+	ows.status = fmt.Sprintf("%sで%sを徴募しました（%s）", ows.selectedProvince.Name, recruit.Name, army.Name)
+	saveOverworld(ows.overworld())
+}
+
+// fight opens the battle scene on the province's stage with the two armies' groups
+func (ows *OverworldScene) fight(battle campaign.Battle) {
+	ows.sceneManager.TransitionTo(SceneBattle, map[string]interface{}{
+		"stage":     stageDisplayName(ows.dataManager, battle.Province.Stage),
+		"preset":    battle.Player.Preset,
+		"mutators":  []string{},
+		"doctrines": []string{},
+		"handicaps": []game.Handicap{},
+		"province":  battle.Province.ID,
+	})
+}
+
+// autoResolve estimates the battle from simulated runs on the first press and applies the likely outcome on the second
+func (ows *OverworldScene) autoResolve(battle campaign.Battle) {
+	stageName := stageDisplayName(ows.dataManager, battle.Province.Stage)
+	if ows.estimate == nil {
+		result, err := game.AutoResolve(func(seed int64) (*game.BattleManager, error) {
+			return newSetupBattle(ows.dataManager, stageName, battle.Player.Preset, "", nil, &battle, nil, nil, nil, seed)
+		}, playerArmyID, game.AutoResolveTrials)
+		if err != nil {
+			ows.status = fmt.Sprintf("自動解決に失敗しました: %v", err)
+			return
+		}
+		ows.estimate = &result
+		return
+	}
+	
+	outcome := autoResolveOutcome(*ows.estimate)
+	recordBattleResult(battle.Province.Stage, battle.Player.Preset, outcome, ows.estimate.BattleTime, 0, false)
+	ows.overworld().ResolveBattle(battle, outcome)
+	saveOverworld(ows.overworld())
+	ows.status = fmt.Sprintf("%sの合戦: %s（自動解決）", battle.Province.Name, resultNames[outcome])
+	ows.estimate = nil
+}
+
+// overworldBattle returns the campaign battle being fought over the province, if any
+func overworldBattle(gameData *GameData) (campaign.Battle, bool) {
+	if gameData.Overworld == nil || gameData.Province == "" {
+		return campaign.Battle{}, false
+	}
+	for _, battle := range gameData.Overworld.Battles() {
+		if battle.Province.ID == gameData.Province {
+			return battle, true
+		}
+	}
+	return campaign.Battle{}, false
+}
+
+// resolveOverworldBattle applies the result of the battle fought over the province to the campaign map
+func resolveOverworldBattle(gameData *GameData, result string) {
+	if battle, ok := overworldBattle(gameData); ok {
+		gameData.Overworld.ResolveBattle(battle, result)
+		saveOverworld(gameData.Overworld)
+	}
+}
+
+// saveOverworld writes the campaign map into the campaign save, keeping the cleared stages
+func saveOverworld(overworld *campaign.Overworld) {
+	state, err := save.LoadCampaign(save.DefaultCampaignPath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load campaign: %v\n", err)
+		return
+	}
+	overworld.Store(state)
+	if err := state.Save(save.DefaultCampaignPath); err != nil {
+		fmt.Printf("Warning: Failed to save campaign: %v\n", err)
+	}
+}
+
+// sideColor returns the display color of a side on the map
+func sideColor(side string) color.RGBA {
+	switch side {
+	case data.SidePlayer:
+		return armyColor(playerArmyID)
+	case data.SideEnemy:
+		return armyColor(playerArmyID + 1)
+	default:
+		return unclaimedColor
+	}
+}
+
+// provinceAtCursor returns the province under the mouse cursor, or nil
+func (ows *OverworldScene) provinceAtCursor() *campaign.Province {
+	mouseX, mouseY := controls.CursorPosition()
+	for _, province := range ows.overworld().Provinces {
+		dx, dy := float64(mouseX)-province.X, float64(mouseY)-province.Y
+		if dx*dx+dy*dy <= provinceRadius*provinceRadius {
+			return province
+		}
+	}
+	return nil
+}
+
+// Draw draws the map, the armies and the side panel
+func (ows *OverworldScene) Draw(screen *ebiten.Image) {
+	screen.Fill(graphics.CurrentTheme().Background)
+	overworld := ows.overworld()
+	
+	title := fmt.Sprintf("戦略マップ  ターン%d", overworld.Turn)
+	ows.textRenderer.DrawTextWithSize(screen, title, 40, 40, graphics.CurrentTheme().Text, 24)
+	
+	// Paths, drawn once per pair
+	for _, province := range overworld.Provinces {
+		for _, link := range province.Links {
+			other := overworld.GetProvince(link)
+			if other == nil || link < province.ID {
+				continue
+			}
+			vector.StrokeLine(screen, float32(province.X), float32(province.Y), float32(other.X), float32(other.Y), 3, color.RGBA{149, 165, 166, 160}, true)
+		}
+	}
+	
+	for _, province := range overworld.Provinces {
+		ows.drawProvince(screen, province)
+	}
+	
+	ows.drawPanel(screen)
+	
+	if event := overworld.Event(); event != nil && overworld.Winner() == "" {
+		ows.drawEvent(screen, event)
+	} else if province := ows.provinceAtCursor(); province != nil {
+		ows.drawBattlefield(screen, province)
+	}
+	
+	controlsText := "クリック: 軍勢選択・進軍  Tab: 軍勢切替  1-4: 徴募  G/Z/X/C: 装備  Enter: 合戦  A: 自動解決  E: ターン終了  Esc: タイトル"
+	ows.textRenderer.DrawText(screen, controlsText, 40, 740, graphics.CurrentTheme().TextMuted)
+}
+
+// drawEvent draws the event of the turn over the map with its numbered choices
+func (ows *OverworldScene) drawEvent(screen *ebiten.Image, event *data.CampaignEventConfig) {
+	x, y := float32(eventBoxX), float32(eventBoxY)
+	vector.DrawFilledRect(screen, x, y, eventBoxWidth, eventBoxHeight, graphics.CurrentTheme().PanelDark, false)
+	vector.StrokeRect(screen, x, y, eventBoxWidth, eventBoxHeight, 2, graphics.CurrentTheme().Highlight, false)
+	
+	textX, textY := float64(eventBoxX+20), float64(eventBoxY+20)
+	step := ows.textRenderer.Spacing(overworldRowStep)
+	ows.textRenderer.DrawTextWithSize(screen, event.Name, textX, textY, graphics.CurrentTheme().Highlight, 20)
+	textY += 40
+	ows.textRenderer.DrawText(screen, event.Description, textX, textY, graphics.CurrentTheme().Text)
+	textY += step * 2
+	
+	for i, choice := range event.Choices {
+		if i >= len(recruitKeys) {
+			break
+		}
+		ows.textRenderer.DrawText(screen, fmt.Sprintf("%d: %s", i+1, eventChoiceText(choice)), textX, textY, graphics.CurrentTheme().Text)
+		textY += step
+	}
+	if len(event.Choices) == 0 {
+		ows.textRenderer.DrawText(screen, "Enter: 閉じる", textX, textY, graphics.CurrentTheme().TextMuted)
+	}
+}
+
+// drawProvince draws a province in its owner's color with the flags of the armies standing in it
+func (ows *OverworldScene) drawProvince(screen *ebiten.Image, province *campaign.Province) {
+	overworld := ows.overworld()
+	x, y := float32(province.X), float32(province.Y)
+	vector.DrawFilledCircle(screen, x, y, provinceRadius, sideColor(province.Owner), true)
+	
+	// Highlight where the selected army can march and where battles wait
+	switch {
+	case overworld.IsContested(province.ID):
+		vector.StrokeCircle(screen, x, y, provinceRadius+4, 3, graphics.CurrentTheme().Warning, true)
+	case ows.selectedProvince == province || (ows.selectedArmy != nil && ows.selectedArmy.Province == province.ID):
+		vector.StrokeCircle(screen, x, y, provinceRadius+4, 3, graphics.CurrentTheme().Text, true)
+	case ows.selectedArmy != nil && !ows.selectedArmy.Moved && overworld.IsLinked(ows.selectedArmy.Province, province.ID):
+		vector.StrokeCircle(screen, x, y, provinceRadius+4, 2, graphics.CurrentTheme().Success, true)
+	}
+	
+	// Player flags on the left, enemy flags on the right; armies that marched this turn are dimmed
+	for _, side := range []string{data.SidePlayer, data.SideEnemy} {
+		for i, army := range overworld.ArmiesAt(province.ID, side) {
+			flagX := x - provinceRadius - armyFlagWidth - 4 - float32(i*(armyFlagWidth+2))
+			if side == data.SideEnemy {
+				flagX = x + provinceRadius + 4 + float32(i*(armyFlagWidth+2))
+			}
+			flagColor := sideColor(side)
+			if army.Moved {
+				flagColor.A = 128
+			}
+			vector.DrawFilledRect(screen, flagX, y-armyFlagHeight/2, armyFlagWidth, armyFlagHeight, flagColor, true)
+			vector.StrokeRect(screen, flagX, y-armyFlagHeight/2, armyFlagWidth, armyFlagHeight, 1, graphics.CurrentTheme().Text, true)
+		}
+	}
+	
+	width, _ := ows.textRenderer.MeasureText(province.Name)
+	ows.textRenderer.DrawText(screen, province.Name, province.X-width/2, province.Y+provinceRadius+6, graphics.CurrentTheme().Text)
+}
+
+// drawBattlefield draws the thumbnail of the stage a battle in the province is fought on beside it,
+// kept left of the side panel
+func (ows *OverworldScene) drawBattlefield(screen *ebiten.Image, province *campaign.Province) {
+	thumbnail := stageThumbnail(ows.dataManager, province.Stage)
+	if thumbnail == nil {
+		return
+	}
+	bounds := thumbnail.Bounds()
+	scale := provinceThumb / float64(max(bounds.Dx(), bounds.Dy()))
+	width, height := float64(bounds.Dx())*scale, float64(bounds.Dy())*scale
+	
+	x, y := province.X+provinceRadius+12, province.Y-height/2
+	if x+width > overworldPanelX-10 {
+		x = province.X - provinceRadius - 12 - width
+	}
+	y = max(y, overworldPanelY)
+	vector.DrawFilledRect(screen, float32(x-4), float32(y-24), float32(width+8), float32(height+28), graphics.CurrentTheme().PanelDark, false)
+	ows.textRenderer.DrawText(screen, stageDisplayName(ows.dataManager, province.Stage), x, y-20, graphics.CurrentTheme().Text)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), graphics.CurrentTheme().Inset, false)
+	drawThumbnail(screen, thumbnail, x, y, width, height)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), 1, graphics.CurrentTheme().TextMuted, false)
+}
+
+// drawPanel draws the selected army, the pending battles and the last message
+func (ows *OverworldScene) drawPanel(screen *ebiten.Image) {
+	overworld := ows.overworld()
+	x, y := float64(overworldPanelX), float64(overworldPanelY)
+	step := ows.textRenderer.Spacing(overworldRowStep)
+	textColor := graphics.CurrentTheme().Text
+	dimColor := graphics.CurrentTheme().TextMuted
+	
+	if winner := overworld.Winner(); winner != "" {
+		message := "キャンペーン勝利！"
+		if winner == data.SideEnemy {
+			message = "キャンペーン敗北…"
+		}
+		ows.textRenderer.DrawTextWithSize(screen, message, x, y, textColor, 20)
+		ows.textRenderer.DrawText(screen, "N: 新しいキャンペーン", x, y+30, dimColor)
+		return
+	}
+	
+	// Treasury and what the next turn brings in
+	goldText := fmt.Sprintf("軍資金: %d", overworld.Gold[data.SidePlayer])
+	ows.textRenderer.DrawText(screen, goldText, x, y, textColor)
+	y += step
+	balanceText := fmt.Sprintf("収入 +%d  維持費 -%d", overworld.Income(data.SidePlayer), overworld.Upkeep(data.SidePlayer))
+	ows.textRenderer.DrawText(screen, balanceText, x, y, dimColor)
+	y += step * 2
+	
+	ows.textRenderer.DrawText(screen, "選択中の軍勢:", x, y, textColor)
+	y += step
+	if army := ows.selectedArmy; army != nil {
+		ows.textRenderer.DrawText(screen, army.Name+"（"+army.Preset+"）", x, y, dimColor)
+		y += step
+		state := "進軍できます"
+		if army.Moved {
+			state = "移動済み"
+		}
+		ows.textRenderer.DrawText(screen, overworld.GetProvince(army.Province).Name+"  "+state, x, y, dimColor)
+		y += step
+		groupsText := fmt.Sprintf("部隊 %d/%d", len(army.Groups), campaign.MaxArmyGroups)
+		ows.textRenderer.DrawText(screen, groupsText, x, y, dimColor)
+		
+		// The selected group's leader and its equipment
+		if len(army.Groups) > 0 {
+			index := min(ows.selectedGroup, len(army.Groups)-1)
+			group := army.Groups[index]
+			y += step
+			leaderText := fmt.Sprintf("G: 部隊%d %s隊長", index+1, ows.unitName(group.Leader))
+			ows.textRenderer.DrawText(screen, leaderText, x, y, textColor)
+			for _, equipmentKey := range equipmentKeys {
+				y += step
+				itemText := fmt.Sprintf(" %s: %s %s", equipmentKey.key, equipmentKey.label, ows.itemName(group.Equipment.Get(equipmentKey.slot)))
+				ows.textRenderer.DrawText(screen, itemText, x, y, dimColor)
+			}
+		}
+	} else {
+		ows.textRenderer.DrawText(screen, "なし", x, y, dimColor)
+	}
+	y += step * 2
+	
+	// Recruits on offer in a held province
+	if province := ows.selectedProvince; province != nil && province.Owner == data.SidePlayer {
+		ows.textRenderer.DrawText(screen, province.Name+"で徴募:", x, y, textColor)
+		y += step
+		for i, recruit := range overworld.Recruits() {
+			if i >= len(recruitKeys) {
+				break
+			}
+			recruitText := fmt.Sprintf("%d: %s  %d金", i+1, recruit.Name, recruit.Cost)
+			recruitColor := dimColor
+			if recruit.Cost > overworld.Gold[data.SidePlayer] {
+				recruitColor = graphics.CurrentTheme().TextDisabled
+			}
+			ows.textRenderer.DrawText(screen, recruitText, x, y, recruitColor)
+			y += step
+		}
+		y += step
+	}
+	
+	ows.textRenderer.DrawText(screen, "合戦:", x, y, textColor)
+	y += step
+	battles := overworld.Battles()
+	if len(battles) == 0 {
+		ows.textRenderer.DrawText(screen, "なし", x, y, dimColor)
+		y += step
+	}
+	for _, battle := range battles {
+		text := fmt.Sprintf("%s: %s 対 %s（%d部隊）", battle.Province.Name, battle.Player.Name, battle.Enemy.Name, len(battle.EnemyGroups))
+		ows.textRenderer.DrawText(screen, text, x, y, dimColor)
+		y += step
+	}
+	
+	// Estimate of the battle next in line
+	if ows.estimate != nil && len(battles) > 0 {
+		y += step
+		estimateText := fmt.Sprintf("勝率%.0f%%（%d勝%d分%d敗）", ows.estimate.WinRate()*100, ows.estimate.Wins, ows.estimate.Draws, ows.estimate.Losses)
+		ows.textRenderer.DrawText(screen, estimateText, x, y, textColor)
+		y += step
+		ows.textRenderer.DrawText(screen, fmt.Sprintf("もう一度Aで「%s」", resultNames[autoResolveOutcome(*ows.estimate)]), x, y, dimColor)
+		y += step
+	}
+	
+	if ows.status != "" {
+		y += step
+		ows.textRenderer.DrawText(screen, ows.status, x, y, graphics.CurrentTheme().Highlight)
+	}
+}
@@ -0,0 +1,100 @@
+package scenes
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+)
+
+// pauseScene is pushed over the battle while it is paused; the camera still moves, and the battle
+// can be stepped, rewound to the setup or shown with the help and the settings over the pause
+type pauseScene struct {
+	battle *BattleSceneUnified
+}
+
+// Update handles the input of the paused battle
+func (ps *pauseScene) Update() error {
+	bs := ps.battle
+	bs.deltaTime = controls.DeltaTime()
+	if bs.camera != nil {
+		bs.camera.Update(bs.deltaTime)
+	}
+	bs.updateScrolling()
+	if bs.minimap != nil {
+		bs.minimap.Update()
+	}
+	bs.handleCameraInput()
+	
+	// P, Esc, the pause button or a click anywhere off the HUD resumes
+	resume := controls.IsActionJustPressed(controls.ActionPause) || controls.IsKeyJustPressed(ebiten.KeyEscape) ||
+		bs.hud.pauseButton.IsClicked()
+	if resume || (controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && !bs.hud.IsHovered() && !bs.isCursorOverMinimap()) {
+		bs.sceneManager.PopScene()
+		return nil
+	}
+	
+	switch {
+	case controls.IsActionJustPressed(controls.ActionReturnToSetup) || bs.hud.backButton.IsClicked():
+		bs.returnToSetup()
+	case controls.IsActionJustPressed(controls.ActionSettings) && bs.settings != nil:
+		bs.sceneManager.PushScene(bs.settings, false)
+	case controls.IsActionJustPressed(controls.ActionHelp) || bs.hud.helpButton.IsClicked():
+		bs.sceneManager.PushScene(bs.help, true)
+	case controls.IsActionJustPressed(controls.ActionScreenshot):
+		bs.saveScreenshot()
+	default:
+		bs.handleStepInput()
+	}
+	return nil
+}
+
+// Draw draws the pause over the dimmed battle
+func (ps *pauseScene) Draw(screen *ebiten.Image) {
+	ps.battle.drawPauseOverlay(screen)
+}
+
+// OnEnter stops the battle
+func (ps *pauseScene) OnEnter(data interface{}) {
+	ps.battle.isPaused = true
+}
+
+// OnExit lets the battle run again
+func (ps *pauseScene) OnExit() {
+	ps.battle.isPaused = false
+}
+
+// helpScene is pushed over the battle to show the controls; the battle waits while it is read,
+// except a co-op or watched one, which cannot stop
+type helpScene struct {
+	battle *BattleSceneUnified
+}
+
+// Update closes the help with its key, Esc or the help button
+func (hs *helpScene) Update() error {
+	bs := hs.battle
+	if controls.IsActionJustPressed(controls.ActionHelp) || controls.IsKeyJustPressed(ebiten.KeyEscape) ||
+		bs.hud.helpButton.IsClicked() {
+		bs.sceneManager.PopScene()
+		return nil
+	}
+	if bs.isNetworked() {
+		bs.deltaTime = controls.DeltaTime()
+		bs.advance()
+	}
+	return nil
+}
+
+// Draw draws the help over the dimmed battle, with the help button to close it
+func (hs *helpScene) Draw(screen *ebiten.Image) {
+	hs.battle.drawHelp(screen)
+	hs.battle.hud.helpButton.Draw(screen, hs.battle.textRenderer)
+}
+
+// OnEnter is called when the help is shown
+func (hs *helpScene) OnEnter(data interface{}) {
+	// Nothing to prepare; the help reads the key bindings as it is drawn
+}
+
+// OnExit is called when the help is closed
+func (hs *helpScene) OnExit() {
+	// Nothing to clean up
+}
@@ -0,0 +1,466 @@
+package scenes
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// customArmyChoice is the preset choice that fields the groups deployed on the recruitment screen
+const customArmyChoice = "自軍編成"
+
+// Recruitment screen layout
+const (
+	recruitListX   = 100
+	rosterListX    = 520
+	recruitListY   = 150
+	recruitRowStep = 24
+	recruitBackY   = 640
+)
+
+// RecruitmentScene is where the player spends the gold won in battles on groups for the custom army:
+// hiring, reinforcing and equipping them, and choosing which take the field under the point budget
+type RecruitmentScene struct {
+	sceneManager *SceneManager
+	dataManager  *data.DataManager
+	textRenderer *graphics.TextRenderer
+	
+	profile      *save.Profile // nil: プロフィールを読み込めなかった
+	selectedItem int           // 雇える部隊、雇った部隊、戻るボタンの順
+	status       string
+	backScene    SceneType // 戻るで帰る画面（軍勢設定かチャレンジ）
+}
+
+// NewRecruitmentScene creates a new recruitment scene
+func NewRecruitmentScene(sceneManager *SceneManager, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) *RecruitmentScene {
+	return &RecruitmentScene{
+		sceneManager: sceneManager,
+		dataManager:  dataManager,
+		textRenderer: textRenderer,
+	}
+}
+
+// OnEnter loads the player's gold and hired groups, and remembers the screen to go back to
+func (rs *RecruitmentScene) OnEnter(data interface{}) {
+	rs.selectedItem = 0
+	rs.status = ""
+	rs.backScene = SceneArmySetup
+	if rs.sceneManager.PreviousScene() == SceneChallenge {
+		rs.backScene = SceneChallenge
+	}
+	profile, err := save.LoadProfile(save.DefaultProfilePath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load profile: %v\n", err)
+		rs.profile = nil
+		rs.status = "プロフィールを読み込めませんでした"
+		return
+	}
+	rs.profile = profile
+}
+
+// OnExit is called when exiting this scene
+func (rs *RecruitmentScene) OnExit() {
+	// Nothing to clean up
+}
+
+// config returns the hireable groups and their prices
+func (rs *RecruitmentScene) config() *data.RecruitmentConfig {
+	return rs.dataManager.Recruitment
+}
+
+// Update handles hiring, reinforcing, equipping, deploying and dismissing groups
+func (rs *RecruitmentScene) Update() error {
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		rs.sceneManager.TransitionTo(rs.backScene, nil)
+		return nil
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		rs.selectedItem = (rs.selectedItem + rs.lastItem()) % (rs.lastItem() + 1)
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		rs.selectedItem = (rs.selectedItem + 1) % (rs.lastItem() + 1)
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) {
+		rs.confirmSelection()
+	}
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		rs.handleClick()
+	}
+	
+	// Upgrades of the selected hired group
+	if index, ok := rs.selectedGroup(); ok {
+		if controls.IsKeyJustPressed(ebiten.KeyA) {
+			rs.reinforce(index)
+		}
+		for _, equipmentKey := range equipmentKeys {
+			if controls.IsKeyJustPressed(equipmentKey.key) {
+				rs.cycleItem(index, equipmentKey.slot)
+			}
+		}
+		if controls.IsKeyJustPressed(ebiten.KeyDelete) || controls.IsKeyJustPressed(ebiten.KeyBackspace) {
+			rs.dismiss(index)
+		}
+	}
+	return nil
+}
+
+// lastItem returns the index of the back button, after the recruits and the hired groups
+func (rs *RecruitmentScene) lastItem() int {
+	return len(rs.config().Recruits) + len(rs.roster())
+}
+
+// roster returns the hired groups, or none when the profile could not be loaded
+func (rs *RecruitmentScene) roster() []save.RosterGroup {
+	if rs.profile == nil {
+		return nil
+	}
+	return rs.profile.Roster
+}
+
+// selectedGroup returns the index of the selected hired group
+func (rs *RecruitmentScene) selectedGroup() (int, bool) {
+	index := rs.selectedItem - len(rs.config().Recruits)
+	return index, index >= 0 && index < len(rs.roster())
+}
+
+// confirmSelection hires the selected recruit, sends the selected group to the field or back, or leaves
+func (rs *RecruitmentScene) confirmSelection() {
+	if rs.selectedItem < len(rs.config().Recruits) {
+		rs.hire(rs.config().Recruits[rs.selectedItem])
+		return
+	}
+	if index, ok := rs.selectedGroup(); ok {
+		rs.toggleDeployed(index)
+		return
+	}
+	rs.sceneManager.TransitionTo(rs.backScene, nil)
+}
+
+// handleClick selects and confirms the clicked row
+func (rs *RecruitmentScene) handleClick() {
+	for item := 0; item <= rs.lastItem(); item++ {
+		x, y, text := rs.rowPosition(item)
+		if isCursorOverText(rs.textRenderer, "> "+text, x-20, y) {
+			rs.selectedItem = item
+			rs.confirmSelection()
+			return
+		}
+	}
+}
+
+// rowPosition returns where a row is drawn and its text
+func (rs *RecruitmentScene) rowPosition(item int) (float64, float64, string) {
+	recruits := rs.config().Recruits
+	switch {
+	case item < len(recruits):
+		return recruitListX, float64(recruitListY + recruitRowStep*item), rs.recruitText(recruits[item])
+	case item < rs.lastItem():
+		index := item - len(recruits)
+		return rosterListX, float64(recruitListY + recruitRowStep*index), rs.groupText(rs.roster()[index])
+	default:
+		return recruitListX, recruitBackY, "戻る"
+	}
+}
+
+// hire pays for a recruit and adds it to the roster, deployed when it fits the budget
+func (rs *RecruitmentScene) hire(recruit data.MercenaryConfig) {
+	if rs.profile == nil {
+		return
+	}
+	if rs.profile.Gold < recruit.Cost {
+		rs.status = fmt.Sprintf("所持金が足りません（%d / %d）", rs.profile.Gold, recruit.Cost)
+		return
+	}
+	
+	group := save.RosterGroup{Recruit: recruit.Name, Leader: recruit.Leader, Member: recruit.Member, Count: recruit.Count, Spent: recruit.Cost}
+	group.Deployed = canDeployGroup(rs.config(), rs.profile.Roster, group, -1) == nil
+	rs.profile.Gold -= recruit.Cost
+	rs.profile.Roster = append(rs.profile.Roster, group)
+	rs.status = recruit.Name + "を雇いました"
+	if !group.Deployed {
+		rs.status += "（待機）"
+	}
+	rs.saveProfile()
+}
+
+// reinforce adds one soldier to a hired group
+func (rs *RecruitmentScene) reinforce(index int) {
+	group := &rs.profile.Roster[index]
+	recruit, ok := rs.config().GetRecruit(group.Recruit)
+	switch {
+	case !ok || group.Count >= recruit.MaxCount:
+		rs.status = group.Recruit + "はこれ以上増員できません"
+		return
+	case rs.profile.Gold < recruit.MemberCost:
+		rs.status = fmt.Sprintf("所持金が足りません（%d / %d）", rs.profile.Gold, recruit.MemberCost)
+		return
+	case group.Deployed && rs.deployedPoints()+recruit.MemberPoints > rs.config().PointBudget:
+		rs.status = "戦力点が足りません"
+		return
+	}
+	
+	group.Count++
+	group.Spent += recruit.MemberCost
+	rs.profile.Gold -= recruit.MemberCost
+	rs.status = fmt.Sprintf("%sを増員しました（%d人）", group.Recruit, group.Count)
+	rs.saveProfile()
+}
+
+// cycleItem buys the leader of a hired group the next item with a price that fits the slot,
+// or empties the slot after the last; the item taken off is lost
+func (rs *RecruitmentScene) cycleItem(index int, slot string) {
+	group := &rs.profile.Roster[index]
+	choices := []string{""}
+	for _, id := range rs.dataManager.GetSlotItemIDs(slot) {
+		if _, ok := rs.config().GetItemPrice(id); ok {
+			choices = append(choices, id)
+		}
+	}
+	
+	equipment := rosterEquipment(*group)
+	next := 0
+	if current := slices.Index(choices, equipment.Get(slot)); current >= 0 {
+		next = (current + 1) % len(choices)
+	}
+	price, _ := rs.config().GetItemPrice(choices[next])
+	if rs.profile.Gold < price {
+		rs.status = fmt.Sprintf("所持金が足りません（%d / %d）", rs.profile.Gold, price)
+		return
+	}
+	
+	equipment.Set(slot, choices[next])
+	group.Weapon, group.Armor, group.Banner = equipment.Weapon, equipment.Armor, equipment.Banner
+	group.Spent += price
+	rs.profile.Gold -= price
+	rs.status = ""
+	rs.saveProfile()
+}
+
+// dismiss sends a hired group away, giving back part of the gold spent on it
+func (rs *RecruitmentScene) dismiss(index int) {
+	group := rs.profile.Roster[index]
+	refund := int(float64(group.Spent) * rs.config().RefundRate)
+	rs.profile.Roster = slices.Delete(rs.profile.Roster, index, index+1)
+	rs.profile.Gold += refund
+	rs.status = fmt.Sprintf("%sを解散しました（%d金が戻りました）", group.Recruit, refund)
+	rs.saveProfile()
+}
+
+// toggleDeployed sends a hired group to the field or back to wait
+func (rs *RecruitmentScene) toggleDeployed(index int) {
+	group := &rs.profile.Roster[index]
+	if !group.Deployed {
+		if err := canDeployGroup(rs.config(), rs.profile.Roster, *group, index); err != nil {
+			rs.status = err.Error()
+			return
+		}
+	}
+	group.Deployed = !group.Deployed
+	rs.status = ""
+	rs.saveProfile()
+}
+
+// deployedPoints returns the points the deployed groups take up
+func (rs *RecruitmentScene) deployedPoints() int {
+	points := 0
+	for _, group := range rs.roster() {
+		if group.Deployed {
+			points += groupPoints(rs.config(), group)
+		}
+	}
+	return points
+}
+
+// saveProfile writes the changed gold and roster
+func (rs *RecruitmentScene) saveProfile() {
+	if err := rs.profile.Save(save.DefaultProfilePath); err != nil {
+		fmt.Printf("Warning: Failed to save profile: %v\n", err)
+		rs.status = "保存できませんでした: " + err.Error()
+	}
+}
+
+// Draw draws the recruits on offer, the hired groups and the selected group's upgrades
+func (rs *RecruitmentScene) Draw(screen *ebiten.Image) {
+	screen.Fill(graphics.CurrentTheme().Background)
+	textColor := graphics.CurrentTheme().Text
+	dimColor := graphics.CurrentTheme().TextMuted
+	config := rs.config()
+	
+	rs.textRenderer.DrawTextWithSize(screen, "部隊編成", 450, 50, textColor, 24)
+	if rs.profile != nil {
+		summary := fmt.Sprintf("所持金: %d金  戦力点: %d / %d", rs.profile.Gold, rs.deployedPoints(), config.PointBudget)
+		rs.textRenderer.DrawText(screen, summary, recruitListX, 90, textColor)
+	}
+	
+	rs.textRenderer.DrawText(screen, "雇える部隊:", recruitListX, recruitListY-recruitRowStep, textColor)
+	rs.textRenderer.DrawText(screen, "雇った部隊:", rosterListX, recruitListY-recruitRowStep, textColor)
+	if len(rs.roster()) == 0 {
+		rs.textRenderer.DrawText(screen, "なし", rosterListX, recruitListY, dimColor)
+	}
+	for item := 0; item <= rs.lastItem(); item++ {
+		x, y, text := rs.rowPosition(item)
+		if item == rs.selectedItem {
+			rs.textRenderer.DrawTextWithShadow(screen, "> "+text, x-20, y, graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
+		} else {
+			rs.textRenderer.DrawText(screen, text, x, y, textColor)
+		}
+	}
+	
+	// The selected group's strength and equipment
+	if index, ok := rs.selectedGroup(); ok {
+		group := rs.roster()[index]
+		y := float64(recruitBackY - recruitRowStep*5)
+		detail := fmt.Sprintf("%s  %s隊長と%s×%d  戦力点%d", group.Recruit, rs.unitName(group.Leader), rs.unitName(group.Member), group.Count, groupPoints(config, group))
+		if recruit, ok := config.GetRecruit(group.Recruit); ok && group.Count < recruit.MaxCount {
+			detail += fmt.Sprintf("  A: 増員 %d金", recruit.MemberCost)
+		}
+		rs.textRenderer.DrawText(screen, detail, rosterListX, y, textColor)
+		equipment := rosterEquipment(group)
+		for _, equipmentKey := range equipmentKeys {
+			y += recruitRowStep
+			itemText := fmt.Sprintf(" %s: %s %s", equipmentKey.key, equipmentKey.label, rs.itemName(equipment.Get(equipmentKey.slot)))
+			rs.textRenderer.DrawText(screen, itemText, rosterListX, y, dimColor)
+		}
+	}
+	
+	if rs.status != "" {
+		rs.textRenderer.DrawText(screen, rs.status, recruitListX, recruitBackY+40, graphics.CurrentTheme().Highlight)
+	}
+	
+	controlsText := "↑↓: 選択  Enter/クリック: 雇う・出陣/待機  A: 増員  Z/X/C: 装備を買う  Delete: 解散  Esc: 戻る"
+	rs.textRenderer.DrawText(screen, controlsText, 120, 700, dimColor)
+}
+
+// recruitText returns the row label of a recruit on offer
+func (rs *RecruitmentScene) recruitText(recruit data.MercenaryConfig) string {
+	return fmt.Sprintf("%s  %s×%d  %d金  戦力点%d", recruit.Name, rs.unitName(recruit.Member), recruit.Count, recruit.Cost, recruit.Points)
+}
+
+// groupText returns the row label of a hired group
+func (rs *RecruitmentScene) groupText(group save.RosterGroup) string {
+	state := "[ ] "
+	if group.Deployed {
+		state = "[x] "
+	}
+	return fmt.Sprintf("%s%s ×%d  戦力点%d", state, group.Recruit, group.Count, groupPoints(rs.config(), group))
+}
+
+// itemName returns the display name of an item, or "なし" for an empty slot
+func (rs *RecruitmentScene) itemName(itemID string) string {
+	if itemID == "" {
+		return "なし"
+	}
+	if item, err := rs.dataManager.GetItemConfig(itemID); err == nil {
+		price, _ := rs.config().GetItemPrice(itemID)
+		return fmt.Sprintf("%s（%d金）", item.Name, price)
+	}
+	return itemID
+}
+
+// unitName returns the display name of a unit type, or its ID if unknown
+func (rs *RecruitmentScene) unitName(unitType string) string {
+	return unitTypeDisplayName(rs.dataManager, unitType)
+}
+
+// groupPoints returns the points a hired group takes up; groups whose recruit left the config count one per soldier
+func groupPoints(config *data.RecruitmentConfig, group save.RosterGroup) int {
+	if recruit, ok := config.GetRecruit(group.Recruit); ok {
+		return recruit.GroupPoints(group.Count)
+	}
+	return group.Count + 1
+}
+
+// canDeployGroup reports why the group cannot join the deployed groups of the roster, or nil;
+// skip is the group's own roster index (-1: not hired yet)
+func canDeployGroup(config *data.RecruitmentConfig, roster []save.RosterGroup, group save.RosterGroup, skip int) error {
+	deployed, points := 0, 0
+	for i, other := range roster {
+		if i != skip && other.Deployed {
+			deployed++
+			points += groupPoints(config, other)
+		}
+	}
+	if deployed >= config.MaxGroups {
+		return fmt.Errorf("出陣できるのは%d部隊までです", config.MaxGroups)
+	}
+	if points+groupPoints(config, group) > config.PointBudget {
+		return fmt.Errorf("戦力点が足りません（%d / %d）", points+groupPoints(config, group), config.PointBudget)
+	}
+	return nil
+}
+
+// deployedRecruits returns the names of the groups the custom army takes the field with, or nil for a preset
+func deployedRecruits(profile *save.Profile, presetName string) []string {
+	if presetName != customArmyChoice {
+		return nil
+	}
+	var names []string
+	for _, group := range profile.Roster {
+		if group.Deployed {
+			names = append(names, group.Recruit)
+		}
+	}
+	return names
+}
+
+// deployRecruits sends the hired groups with the names to the field in place of the deployed ones,
+// one group per name, and returns the names no hired group could answer within the limits
+func deployRecruits(config *data.RecruitmentConfig, roster []save.RosterGroup, names []string) []string {
+	for i := range roster {
+		roster[i].Deployed = false
+	}
+	
+	var missing []string
+	for _, name := range names {
+		found := false
+		for i, group := range roster {
+			if group.Recruit == name && !group.Deployed && canDeployGroup(config, roster, group, i) == nil {
+				roster[i].Deployed = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// rosterEquipment returns the items the leader of a hired group carries
+func rosterEquipment(group save.RosterGroup) data.Equipment {
+	return data.Equipment{Weapon: group.Weapon, Armor: group.Armor, Banner: group.Banner}
+}
+
+// loadCustomArmy returns the deployed groups of the player's roster and the player's gold
+func loadCustomArmy() ([]data.ReinforcementGroupConfig, int) {
+	profile, err := save.LoadProfile(save.DefaultProfilePath)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load profile: %v\n", err)
+		return nil, 0
+	}
+	
+	return deployedGroups(profile.Roster), profile.Gold
+}
+
+// deployedGroups returns the deployed groups of a roster as the army takes the field with them
+func deployedGroups(roster []save.RosterGroup) []data.ReinforcementGroupConfig {
+	var groups []data.ReinforcementGroupConfig
+	for _, group := range roster {
+		if group.Deployed {
+			groups = append(groups, data.ReinforcementGroupConfig{
+				Leader:    group.Leader,
+				Member:    group.Member,
+				Count:     group.Count,
+				Equipment: rosterEquipment(group),
+			})
+		}
+	}
+	return groups
+}
@@ -0,0 +1,69 @@
+package scenes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/save"
+)
+
+// reportTitle heads every battle report
+const reportTitle = "ゴチャキャラバトル 戦闘報告"
+
+// reportSetup returns the report lines describing the battle the game data sets up
+func reportSetup(gameData *GameData) []string {
+	mutators := "なし"
+	if len(gameData.Mutators) > 0 {
+		var names []string
+		for _, id := range gameData.Mutators {
+			if mutator := game.GetMutator(id); mutator != nil {
+				names = append(names, mutator.Name)
+			}
+		}
+		mutators = strings.Join(names, "・")
+	}
+	return []string{
+		reportTitle,
+		fmt.Sprintf("ステージ: %s / 編成: %s", gameData.CurrentStage, presetSummary(gameData)),
+		"特殊ルール: " + mutators,
+	}
+}
+
+// reportWinner returns the report line of the result
+func reportWinner(winner string) string {
+	if winner == resultNames[save.ResultDraw] {
+		return "結果: 引き分け"
+	}
+	return "結果: " + winner + " 勝利"
+}
+
+// battleReport sums up the finished battle as text to paste into a chat or a bug report
+// The setup code at the end lets anyone fight the battle again from the same seed
+func (bs *BattleSceneUnified) battleReport(winner string) string {
+	gameData := bs.sceneManager.gameData
+	bm := bs.battleManager
+	lines := reportSetup(gameData)
+	lines = append(lines,
+		reportWinner(winner),
+		fmt.Sprintf("戦闘時間: %02d:%02d", int(bm.BattleTime)/60, int(bm.BattleTime)%60))
+	for _, army := range bm.Armies {
+		lines = append(lines, fmt.Sprintf("%s: 生存 %d/%d体", army.Name, army.GetAliveCount(), len(army.GetAllUnits())))
+	}
+	lines = append(lines,
+		fmt.Sprintf("シード: %d", bs.seed),
+		"設定コード: "+newSetupCode(bs.dataManager, gameData, bs.seed).String())
+	return strings.Join(lines, "\n")
+}
+
+// autoResolveReport sums up an auto-resolved battle from its simulated runs
+func autoResolveReport(dataManager *data.DataManager, gameData *GameData, winner string, result game.AutoResolveResult) string {
+	lines := reportSetup(gameData)
+	lines = append(lines,
+		reportWinner(winner)+"（自動解決）",
+		fmt.Sprintf("模擬戦: %d勝%d分%d敗（勝率%.0f%%）", result.Wins, result.Draws, result.Losses, result.WinRate()*100),
+		fmt.Sprintf("予想戦死数: 自軍%.1f/%d体 敵軍%.1f/%d体", result.Casualties, result.Units, result.EnemyCasualties, result.EnemyUnits),
+		"設定コード: "+newSetupCode(dataManager, gameData, gameData.Seed).String())
+	return strings.Join(lines, "\n")
+}
@@ -5,10 +5,36 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/game"
 	"github.com/shirou/tinygocha/internal/graphics"
 )
 
+// Heatmap panel layout (right of the statistics panel)
+const (
+	heatmapPanelX    = 810
+	heatmapPanelY    = 250
+	heatmapPanelSize = 200
+)
+
+// heatmapLayerColors are the colors of the heatmap layers, indexed by game.HeatmapLayer
+var heatmapLayerColors = []color.RGBA{
+	{231, 76, 60, 255},  // 戦死: 赤
+	{243, 156, 18, 255}, // ダメージ: 橙
+	{52, 152, 219, 255}, // 移動: 青
+}
+
+// Result menus after an ordinary battle and after a battle fought over an overworld province
+var (
+	resultMenuItems    = []string{"再戦", "軍勢変更", "タイトル"}
+	overworldMenuItems = []string{"戦略マップ", "タイトル"}
+)
+
+// highlightsMenuItem replays the last battle's highlights; it is offered before the last item
+// when the battle had any
+const highlightsMenuItem = "ハイライト"
+
 // ResultScene represents the battle result screen
 type ResultScene struct {
 	sceneManager *SceneManager
@@ -16,6 +42,11 @@ type ResultScene struct {
 	winner       string
 	selectedItem int
 	menuItems    []string
+	copyMessage  string // 報告のコピーの結果
+	
+	// Heatmap of the last battle and the layers shown
+	heatmap     *game.BattleHeatmap
+	shownLayers []bool
 }
 
 // NewResultScene creates a new result scene
@@ -24,59 +55,106 @@ func NewResultScene(sceneManager *SceneManager, textRenderer *graphics.TextRende
 		sceneManager: sceneManager,
 		textRenderer: textRenderer,
 		selectedItem: 0,
-		menuItems:    []string{"再戦", "軍勢変更", "タイトル"},
+		menuItems:    resultMenuItems,
+		shownLayers:  []bool{true, true, false},
 	}
 }
 
 // Update updates the result scene
 func (rs *ResultScene) Update() error {
+	// The battle report goes to the clipboard with C
+	if controls.IsKeyJustPressed(ebiten.KeyC) && rs.sceneManager.gameData.Report != "" {
+		rs.copyMessage = copyToClipboard(rs.sceneManager.gameData.Report, "戦闘報告")
+	}
+	
 	// Handle input
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
 		rs.selectedItem--
 		if rs.selectedItem < 0 {
 			rs.selectedItem = len(rs.menuItems) - 1
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
 		rs.selectedItem++
 		if rs.selectedItem >= len(rs.menuItems) {
 			rs.selectedItem = 0
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		switch rs.selectedItem {
-		case 0: // 再戦
+	// Heatmap layers toggle with 1-3 or a click on their labels
+	for i, key := range []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3} {
+		if controls.IsKeyJustPressed(key) {
+			rs.shownLayers[i] = !rs.shownLayers[i]
+		}
+	}
+	if rs.heatmap != nil && controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		for i, layer := range game.HeatmapLayers {
+			if isCursorOverText(rs.textRenderer, heatmapLayerLabel(i, layer), heatmapPanelX, heatmapLayerLabelY(i)) {
+				rs.shownLayers[i] = !rs.shownLayers[i]
+			}
+		}
+	}
+	
+	confirmed := controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace)
+	
+	// Pointing at a menu item selects it, and clicking confirms it
+	if updateMenuMouse(rs.textRenderer, rs.menuRegions(), &rs.selectedItem) {
+		confirmed = true
+	}
+	
+	if confirmed {
+		switch rs.menuItems[rs.selectedItem] {
+		case "再戦":
 			rs.sceneManager.TransitionTo(SceneBattle, nil)
-		case 1: // 軍勢変更
-			rs.sceneManager.TransitionTo(SceneArmySetup, nil)
-		case 2: // タイトル
+		case "軍勢変更":
+			rs.sceneManager.TransitionTo(setupScene(rs.sceneManager.gameData), nil)
+		case "戦略マップ":
+			rs.sceneManager.TransitionTo(SceneOverworld, nil)
+		case highlightsMenuItem:
+			rs.sceneManager.gameData.WatchReel = true
+			rs.sceneManager.TransitionTo(SceneBattle, nil)
+		case "タイトル":
 			rs.sceneManager.TransitionTo(SceneTitle, nil)
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
 		rs.sceneManager.TransitionTo(SceneTitle, nil)
 	}
 	
 	return nil
 }
 
+// menuRegions returns where the menu items are drawn
+func (rs *ResultScene) menuRegions() []menuRegion {
+	regions := make([]menuRegion, len(rs.menuItems))
+	for i, item := range rs.menuItems {
+		regions[i] = menuRegion{i, "> " + item + " <", 330 + float64(i*100), 500}
+	}
+	return regions
+}
+
 // Draw draws the result scene
 func (rs *ResultScene) Draw(screen *ebiten.Image) {
 	// Clear screen with dark background
-	screen.Fill(color.RGBA{44, 62, 80, 255}) // #2C3E50
+	screen.Fill(graphics.CurrentTheme().Background)
 	
 	// Draw winner announcement
 	winnerText := fmt.Sprintf("%s 勝利！", rs.winner)
 	if rs.winner == "引き分け" {
 		winnerText = "引き分け！"
 	}
-	rs.textRenderer.DrawTextWithSize(screen, winnerText, 400, 150, color.RGBA{236, 240, 241, 255}, 32)
+	rs.textRenderer.DrawTextWithSize(screen, winnerText, 400, 150, graphics.CurrentTheme().Text, 32)
+	
+	// Draw the stars of a challenge
+	if rating := rs.sceneManager.gameData.Rating; rating != "" {
+		rs.textRenderer.DrawText(screen, rating, 400, 200, graphics.CurrentTheme().Highlight)
+	}
 	
 	// Draw battle statistics
 	rs.drawStatistics(screen)
+	rs.drawHeatmap(screen)
 	
 	// Draw menu items
 	for i, item := range rs.menuItems {
@@ -86,15 +164,18 @@ func (rs *ResultScene) Draw(screen *ebiten.Image) {
 		// Highlight selected item
 		if i == rs.selectedItem {
 			rs.textRenderer.DrawTextWithShadow(screen, "> "+item+" <", x-20, y, 
-				color.RGBA{52, 152, 219, 255}, color.RGBA{0, 0, 0, 128})
+				graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
 		} else {
-			rs.textRenderer.DrawText(screen, item, x, y, color.RGBA{236, 240, 241, 255})
+			rs.textRenderer.DrawText(screen, item, x, y, graphics.CurrentTheme().Text)
 		}
 	}
 	
 	// Draw controls hint
-	controlsText := "↑↓: 選択  Enter: 決定  Esc: タイトル"
-	rs.textRenderer.DrawText(screen, controlsText, 350, 600, color.RGBA{149, 165, 166, 255})
+	controlsText := "↑↓/マウス: 選択  Enter/クリック: 決定  1-3: ヒートマップ切替  C: 戦闘報告をコピー  Esc: タイトル"
+	rs.textRenderer.DrawText(screen, controlsText, 350, 600, graphics.CurrentTheme().TextMuted)
+	if rs.copyMessage != "" {
+		rs.textRenderer.DrawText(screen, rs.copyMessage, 350, 625, graphics.CurrentTheme().Highlight)
+	}
 }
 
 // drawStatistics draws battle statistics
@@ -108,12 +189,12 @@ func (rs *ResultScene) drawStatistics(screen *ebiten.Image) {
 	// Draw panel background
 	for dy := 0; dy < panelHeight; dy++ {
 		for dx := 0; dx < panelWidth; dx++ {
-			screen.Set(panelX+dx, panelY+dy, color.RGBA{52, 73, 94, 255}) // #34495E
+			screen.Set(panelX+dx, panelY+dy, graphics.CurrentTheme().Panel)
 		}
 	}
 	
 	// Draw panel border
-	borderColor := color.RGBA{236, 240, 241, 255} // #ECF0F1
+	borderColor := graphics.CurrentTheme().Text
 	for dx := 0; dx < panelWidth; dx++ {
 		screen.Set(panelX+dx, panelY, borderColor)
 		screen.Set(panelX+dx, panelY+panelHeight-1, borderColor)
@@ -125,21 +206,77 @@ func (rs *ResultScene) drawStatistics(screen *ebiten.Image) {
 	
 	// Battle statistics (placeholder data)
 	statsTitle := "戦闘統計"
-	rs.textRenderer.DrawTextWithSize(screen, statsTitle, float64(panelX+20), float64(panelY+20), color.RGBA{236, 240, 241, 255}, 20)
+	rs.textRenderer.DrawTextWithSize(screen, statsTitle, float64(panelX+20), float64(panelY+20), graphics.CurrentTheme().Text, 20)
 	
 	// Left column - General stats
-	rs.textRenderer.DrawText(screen, "戦闘時間: 3:45", float64(panelX+20), float64(panelY+50), color.RGBA{236, 240, 241, 255})
-	rs.textRenderer.DrawText(screen, "軍勢A生存: 8", float64(panelX+20), float64(panelY+70), color.RGBA{236, 240, 241, 255})
-	rs.textRenderer.DrawText(screen, "軍勢B生存: 2", float64(panelX+20), float64(panelY+90), color.RGBA{236, 240, 241, 255})
-	rs.textRenderer.DrawText(screen, "総ダメージ", float64(panelX+20), float64(panelY+110), color.RGBA{236, 240, 241, 255})
-	rs.textRenderer.DrawText(screen, "A: 1200  B: 800", float64(panelX+20), float64(panelY+130), color.RGBA{236, 240, 241, 255})
+	rs.textRenderer.DrawText(screen, "戦闘時間: 3:45", float64(panelX+20), float64(panelY+50), graphics.CurrentTheme().Text)
+	rs.textRenderer.DrawText(screen, "軍勢A生存: 8", float64(panelX+20), float64(panelY+70), graphics.CurrentTheme().Text)
+	rs.textRenderer.DrawText(screen, "軍勢B生存: 2", float64(panelX+20), float64(panelY+90), graphics.CurrentTheme().Text)
+	rs.textRenderer.DrawText(screen, "総ダメージ", float64(panelX+20), float64(panelY+110), graphics.CurrentTheme().Text)
+	rs.textRenderer.DrawText(screen, "A: 1200  B: 800", float64(panelX+20), float64(panelY+130), graphics.CurrentTheme().Text)
 	
 	// Right column - MVP
 	mvpTitle := "MVP"
-	rs.textRenderer.DrawTextWithSize(screen, mvpTitle, float64(panelX+350), float64(panelY+50), color.RGBA{236, 240, 241, 255}, 18)
-	rs.textRenderer.DrawText(screen, "弓兵リーダー", float64(panelX+350), float64(panelY+70), color.RGBA{236, 240, 241, 255})
-	rs.textRenderer.DrawText(screen, "撃破数: 5", float64(panelX+350), float64(panelY+90), color.RGBA{236, 240, 241, 255})
-	rs.textRenderer.DrawText(screen, "与ダメージ: 450", float64(panelX+350), float64(panelY+110), color.RGBA{236, 240, 241, 255})
+	rs.textRenderer.DrawTextWithSize(screen, mvpTitle, float64(panelX+350), float64(panelY+50), graphics.CurrentTheme().Text, 18)
+	rs.textRenderer.DrawText(screen, "弓兵リーダー", float64(panelX+350), float64(panelY+70), graphics.CurrentTheme().Text)
+	rs.textRenderer.DrawText(screen, "撃破数: 5", float64(panelX+350), float64(panelY+90), graphics.CurrentTheme().Text)
+	rs.textRenderer.DrawText(screen, "与ダメージ: 450", float64(panelX+350), float64(panelY+110), graphics.CurrentTheme().Text)
+}
+
+// heatmapLayerLabel returns the toggle label of a heatmap layer
+func heatmapLayerLabel(index int, layer game.HeatmapLayer) string {
+	return fmt.Sprintf("%d: %s", index+1, layer)
+}
+
+// heatmapLayerLabelY returns the y position of a heatmap layer's toggle label
+func heatmapLayerLabelY(index int) float64 {
+	return float64(heatmapPanelY + heatmapPanelSize + 10 + index*18)
+}
+
+// drawHeatmap draws the shown layers of the last battle's heatmap over a map of the battlefield
+func (rs *ResultScene) drawHeatmap(screen *ebiten.Image) {
+	if rs.heatmap == nil {
+		return
+	}
+	
+	vector.DrawFilledRect(screen, heatmapPanelX, heatmapPanelY, heatmapPanelSize, heatmapPanelSize, graphics.CurrentTheme().Inset, false)
+	
+	// Scale the map to the panel keeping its aspect ratio
+	cellSize := float32(heatmapPanelSize) / float32(max(rs.heatmap.Cols, rs.heatmap.Rows))
+	for i, layer := range game.HeatmapLayers {
+		if !rs.shownLayers[i] {
+			continue
+		}
+		highest := rs.heatmap.Max(layer)
+		if highest <= 0 {
+			continue
+		}
+		
+		// Brighter cells saw more; the busiest cell is nearly opaque
+		for row := 0; row < rs.heatmap.Rows; row++ {
+			for col := 0; col < rs.heatmap.Cols; col++ {
+				value := rs.heatmap.Get(layer, col, row)
+				if value <= 0 {
+					continue
+				}
+				cellColor := heatmapLayerColors[i]
+				cellColor.A = uint8(40 + 200*value/highest)
+				x := heatmapPanelX + float32(col)*cellSize
+				y := heatmapPanelY + float32(row)*cellSize
+				vector.DrawFilledRect(screen, x, y, cellSize, cellSize, cellColor, false)
+			}
+		}
+	}
+	vector.StrokeRect(screen, heatmapPanelX, heatmapPanelY, heatmapPanelSize, heatmapPanelSize, 1, graphics.CurrentTheme().Text, false)
+	
+	// Layer toggles
+	for i, layer := range game.HeatmapLayers {
+		labelColor := graphics.CurrentTheme().TextDisabled
+		if rs.shownLayers[i] {
+			labelColor = heatmapLayerColors[i]
+		}
+		rs.textRenderer.DrawText(screen, heatmapLayerLabel(i, layer), heatmapPanelX, heatmapLayerLabelY(i), labelColor)
+	}
 }
 
 // OnEnter is called when entering this scene
@@ -148,7 +285,24 @@ func (rs *ResultScene) OnEnter(data interface{}) {
 	if winner, ok := data.(string); ok {
 		rs.winner = winner
 	}
+	
+	// Heatmap recorded by the battle that just ended
+	rs.heatmap = nil
+	if gameData, ok := data.(*GameData); ok {
+		rs.heatmap = gameData.Heatmap
+	}
 	rs.selectedItem = 0
+	rs.copyMessage = ""
+	
+	// A battle fought over a province returns to the overworld instead of being replayed
+	rs.menuItems = resultMenuItems
+	if rs.sceneManager.gameData.Province != "" {
+		rs.menuItems = overworldMenuItems
+	}
+	if rs.sceneManager.gameData.Highlights != nil {
+		last := len(rs.menuItems) - 1
+		rs.menuItems = append(append(append([]string{}, rs.menuItems[:last]...), highlightsMenuItem), rs.menuItems[last])
+	}
 }
 
 // OnExit is called when exiting this scene
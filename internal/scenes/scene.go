@@ -2,6 +2,12 @@ package scenes
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/campaign"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/netplay"
 )
 
 // SceneType represents different types of scenes
@@ -14,6 +20,11 @@ const (
 	SceneBattle
 	SceneResult
 	ScenePause
+	SceneLobby
+	SceneOverworld
+	SceneRecruitment
+	SceneChallenge
+	SceneSettings
 )
 
 // Scene interface that all scenes must implement
@@ -29,24 +40,161 @@ type GameData struct {
 	// Will be expanded as we implement more features
 	CurrentStage  string
 	CurrentPreset string
+	EnemyPreset   string                          // 敵軍のプリセット（空: 自軍と同じ、game.RandomPreset: 戦闘ごとに選ぶ）
+	Army          []data.ReinforcementGroupConfig // 自軍編成で出陣する部隊（空: プリセットの部隊）
+	Mutators      []string                        // 有効な特殊ルールのID
+	Doctrines     []string                        // 軍勢ドクトリンのID（0: 自軍, 1: 敵軍、空: なし）
+	Handicaps     []game.Handicap                 // 能力の倍率（0: 自軍, 1: 敵軍、空: 補正なし）
+	Seed          int64                           // 戦闘の乱数シード（0: 毎回ランダム）
+	Report        string                          // 直前の戦闘の報告（結果画面でコピーできる）
+	Heatmap       *game.BattleHeatmap             // 直前の戦闘のヒートマップ（結果画面で表示）
+	Highlights    *highlightReel                  // 直前の戦闘の見どころ（nil: なし）
+	WatchReel     bool                            // 戦闘画面で見どころを再生する（結果画面から）
+	Coop          *netplay.Session                // 協力プレイの接続（nil: 1人プレイ）
+	Broadcast     *netplay.Broadcaster            // 観戦者への配信（協力プレイのホストのみ）
+	Watch         *netplay.Watcher                // 観戦している配信（nil: 観戦していない）
+	Overworld     *campaign.Overworld             // 進行中の戦略マップ（nil: キャンペーンを開いていない）
+	Province      string                          // 戦略マップで合戦中の地方ID（空: 通常の戦闘）
+	Challenge     string                          // 挑戦中のチャレンジID（空: 通常の戦闘）
+	Rating        string                          // 直前のチャレンジの星の評価（結果画面で表示、空: チャレンジではない）
+	CompactHUD    bool                            // 小さな画面向けのHUDを使う（設定またはウィンドウの大きさで決まる）
 	// ArmyA        *ArmyConfig
 	// ArmyB        *ArmyConfig
 	// BattleResult *BattleResult
 }
 
+// defaultStage is offered when stages.toml lists no stage for the selector
+var defaultStage = data.StageListing{ID: "forest_battle", Name: "森の戦い"}
+
+// stageChoices lists the selectable stages from stages.toml in menu order
+func stageChoices(dataManager *data.DataManager) []data.StageListing {
+	if dataManager != nil {
+		if stages := dataManager.ListStages(); len(stages) > 0 {
+			return stages
+		}
+	}
+	return []data.StageListing{defaultStage}
+}
+
+// stageConfigID returns the config ID of the stage with the display name, as scenes pass stages
+// between them by name (empty: no such stage)
+func stageConfigID(dataManager *data.DataManager, stageName string) string {
+	if dataManager == nil {
+		if stageName == defaultStage.Name {
+			return defaultStage.ID
+		}
+		return ""
+	}
+	for _, id := range dataManager.GetStageIDs() {
+		if stage, err := dataManager.GetStageConfig(id); err == nil && stage.Name == stageName {
+			return id
+		}
+	}
+	return ""
+}
+
+// stageDisplayName returns the display name of a stage config ID, as the battle scene expects
+func stageDisplayName(dataManager *data.DataManager, stageID string) string {
+	if dataManager != nil {
+		if stage, err := dataManager.GetStageConfig(stageID); err == nil && stage.Name != "" {
+			return stage.Name
+		}
+	}
+	return stageID
+}
+
+// presetChoices lists the selectable preset armies in menu order, the mods' after the built-in ones
+func presetChoices(dataManager *data.DataManager) []string {
+	return game.ListPresets(dataManager)
+}
+
+// sameAsPlayerChoice is the enemy preset choice that fields the player's preset
+const sameAsPlayerChoice = "自軍と同じ"
+
+// enemyPresetChoices lists the enemy's preset choices in menu order: the player's preset, each preset army or one picked at random
+func enemyPresetChoices(dataManager *data.DataManager) []string {
+	return append(append([]string{sameAsPlayerChoice}, presetChoices(dataManager)...), game.RandomPreset)
+}
+
+// presetSummary names the player's preset, followed by the enemy's when the setup chose its own
+func presetSummary(gameData *GameData) string {
+	if gameData.EnemyPreset == "" {
+		return gameData.CurrentPreset
+	}
+	return gameData.CurrentPreset + " 対 " + gameData.EnemyPreset
+}
+
+// isCursorOverText reports whether the mouse cursor is over text drawn at x, y
+func isCursorOverText(textRenderer *graphics.TextRenderer, str string, x, y float64) bool {
+	width, height := textRenderer.MeasureText(str)
+	mouseX, mouseY := controls.CursorPosition()
+	mx, my := float64(mouseX), float64(mouseY)
+	return mx >= x && mx < x+width && my >= y && my < y+height
+}
+
+// menuRegion is where a menu item is drawn, given as the text of its selected form and where that
+// is drawn, so the mouse can point at the item
+type menuRegion struct {
+	item int
+	text string
+	x, y float64
+}
+
+// regionAt returns the region under the mouse cursor
+func regionAt(textRenderer *graphics.TextRenderer, regions []menuRegion) (menuRegion, bool) {
+	for _, region := range regions {
+		if isCursorOverText(textRenderer, region.text, region.x, region.y) {
+			return region, true
+		}
+	}
+	return menuRegion{}, false
+}
+
+// updateMenuMouse highlights the item the mouse moves onto by selecting it, and reports whether an item
+// was clicked (selecting it too); the cursor resting on an item leaves the keyboard's selection alone
+func updateMenuMouse(textRenderer *graphics.TextRenderer, regions []menuRegion, selected *int) bool {
+	moved, clicked := controls.CursorMoved(), controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	if !moved && !clicked {
+		return false
+	}
+	region, ok := regionAt(textRenderer, regions)
+	if !ok {
+		return false
+	}
+	*selected = region.item
+	return clicked
+}
+
 // SceneTransition handles smooth transitions between scenes
+// Neither scene is updated while it runs; the next scene is entered partway through, as the style wants
 type SceneTransition struct {
 	IsTransitioning bool
-	FromScene      SceneType
-	ToScene        SceneType
-	Progress       float64
-	Duration       float64
+	FromScene       SceneType
+	ToScene         SceneType
+	Progress        float64
+	Duration        float64
+	Style           TransitionStyle
+	Easing          Easing
+	switched        bool          // 次のシーンに切り替えた
+	outgoing        *ebiten.Image // 前のシーンの最後の画面（クロスフェード・ワイプ用）
+	captured        bool          // outgoing にこの遷移の前のシーンを描いた
+}
+
+// overlayDimAlpha is how strongly a scene shown beneath a pushed one is dimmed (0-255)
+const overlayDimAlpha = 128
+
+// pushedScene is a scene shown over the current one, such as the pause or the help
+type pushedScene struct {
+	scene     Scene
+	drawBelow bool // 下のシーンを暗くして描く（false: このシーンだけを描く）
 }
 
 // SceneManager manages all scenes and transitions
+// Scenes pushed over the current one take the input until popped; the scenes beneath are not updated
 type SceneManager struct {
 	currentScene SceneType
 	scenes       map[SceneType]Scene
+	stack        []pushedScene // 今のシーンの上に重ねたシーン（最後が一番上）
 	gameData     *GameData
 	transition   *SceneTransition
 }
@@ -59,7 +207,7 @@ func NewSceneManager() *SceneManager {
 		gameData:     &GameData{},
 		transition: &SceneTransition{
 			IsTransitioning: false,
-			Duration:        0.5, // 0.5 seconds transition
+			Duration:        defaultTransition.Duration,
 		},
 	}
 }
@@ -69,21 +217,59 @@ func (sm *SceneManager) RegisterScene(sceneType SceneType, scene Scene) {
 	sm.scenes[sceneType] = scene
 }
 
-// TransitionTo starts a transition to a new scene
-func (sm *SceneManager) TransitionTo(sceneType SceneType, data interface{}) {
+// TransitionTo starts a transition to a new scene, fading through black
+func (sm *SceneManager) TransitionTo(sceneType SceneType, sceneData interface{}) {
+	sm.TransitionWith(sceneType, sceneData, defaultTransition)
+}
+
+// TransitionWith starts a transition to a new scene with the given style, duration and easing
+func (sm *SceneManager) TransitionWith(sceneType SceneType, sceneData interface{}, options TransitionOptions) {
 	if sm.currentScene == sceneType {
 		return
 	}
-
+	
 	sm.transition.IsTransitioning = true
 	sm.transition.FromScene = sm.currentScene
 	sm.transition.ToScene = sceneType
 	sm.transition.Progress = 0.0
-
+	sm.transition.Duration = options.Duration
+	sm.transition.Style = options.Style
+	sm.transition.Easing = options.Easing
+	sm.transition.switched = false
+	sm.transition.captured = false
+	
 	// Pass data to the new scene
-	if data != nil {
+	if sceneData != nil {
 		// Update game data based on the passed data
-		if battleData, ok := data.(map[string]interface{}); ok {
+		if battleData, ok := sceneData.(map[string]interface{}); ok {
+			// A newly set up battle is an ordinary one unless the overworld says otherwise
+			sm.gameData.Province = ""
+			if province, exists := battleData["province"]; exists {
+				if provinceID, ok := province.(string); ok {
+					sm.gameData.Province = provinceID
+				}
+			}
+			// and no challenge unless the challenge screen set it up
+			sm.gameData.Challenge = ""
+			if challenge, exists := battleData["challenge"]; exists {
+				if challengeID, ok := challenge.(string); ok {
+					sm.gameData.Challenge = challengeID
+				}
+			}
+			// and fields the preset's groups unless the player chose the custom army
+			sm.gameData.Army = nil
+			if army, exists := battleData["army"]; exists {
+				if groups, ok := army.([]data.ReinforcementGroupConfig); ok {
+					sm.gameData.Army = groups
+				}
+			}
+			// and starts from a fresh seed unless a setup code gave one
+			sm.gameData.Seed = 0
+			if seed, exists := battleData["seed"]; exists {
+				if seedValue, ok := seed.(int64); ok {
+					sm.gameData.Seed = seedValue
+				}
+			}
 			if stage, exists := battleData["stage"]; exists {
 				if stageStr, ok := stage.(string); ok {
 					sm.gameData.CurrentStage = stageStr
@@ -94,6 +280,28 @@ func (sm *SceneManager) TransitionTo(sceneType SceneType, data interface{}) {
 					sm.gameData.CurrentPreset = presetStr
 				}
 			}
+			// The enemy fields the same preset unless the setup chose its own
+			sm.gameData.EnemyPreset = ""
+			if preset, exists := battleData["enemy_preset"]; exists {
+				if presetStr, ok := preset.(string); ok {
+					sm.gameData.EnemyPreset = presetStr
+				}
+			}
+			if mutators, exists := battleData["mutators"]; exists {
+				if mutatorIDs, ok := mutators.([]string); ok {
+					sm.gameData.Mutators = mutatorIDs
+				}
+			}
+			if doctrines, exists := battleData["doctrines"]; exists {
+				if doctrineIDs, ok := doctrines.([]string); ok {
+					sm.gameData.Doctrines = doctrineIDs
+				}
+			}
+			if handicaps, exists := battleData["handicaps"]; exists {
+				if armyHandicaps, ok := handicaps.([]game.Handicap); ok {
+					sm.gameData.Handicaps = armyHandicaps
+				}
+			}
 		}
 	}
 }
@@ -101,25 +309,26 @@ func (sm *SceneManager) TransitionTo(sceneType SceneType, data interface{}) {
 // Update updates the current scene and handles transitions
 func (sm *SceneManager) Update() error {
 	if sm.transition.IsTransitioning {
-		sm.transition.Progress += 1.0 / 60.0 / sm.transition.Duration // Assuming 60 FPS
+		if sm.transition.Duration > 0 {
+			sm.transition.Progress += controls.DeltaTime() / sm.transition.Duration
+		} else {
+			sm.transition.Progress = 1.0
+		}
 		
+		if !sm.transition.switched && sm.transition.Progress >= sm.transition.Style.switchPoint() {
+			sm.switchScene()
+		}
 		if sm.transition.Progress >= 1.0 {
-			// Transition complete
-			if currentScene := sm.scenes[sm.currentScene]; currentScene != nil {
-				currentScene.OnExit()
-			}
-			
-			sm.currentScene = sm.transition.ToScene
-			
-			if newScene := sm.scenes[sm.currentScene]; newScene != nil {
-				newScene.OnEnter(sm.gameData)
-			}
-			
 			sm.transition.IsTransitioning = false
 		}
 		return nil
 	}
-
+	
+	// Only the topmost scene takes the input
+	if len(sm.stack) > 0 {
+		return sm.stack[len(sm.stack)-1].scene.Update()
+	}
+	
 	// Update current scene
 	if scene := sm.scenes[sm.currentScene]; scene != nil {
 		return scene.Update()
@@ -128,24 +337,83 @@ func (sm *SceneManager) Update() error {
 	return nil
 }
 
-// Draw draws the current scene with transition effects
-func (sm *SceneManager) Draw(screen *ebiten.Image) {
-	if sm.transition.IsTransitioning {
-		// During transition, we could implement fade effects here
-		// For now, just draw the current scene
+// switchScene leaves the old scene for the one the transition goes to; the scenes pushed over
+// the old one go with it
+func (sm *SceneManager) switchScene() {
+	for len(sm.stack) > 0 {
+		sm.PopScene()
+	}
+	if currentScene := sm.scenes[sm.currentScene]; currentScene != nil {
+		currentScene.OnExit()
+	}
+	
+	sm.currentScene = sm.transition.ToScene
+	sm.transition.switched = true
+	
+	if newScene := sm.scenes[sm.currentScene]; newScene != nil {
+		newScene.OnEnter(sm.gameData)
+	}
+}
+
+// PushScene shows a scene over the current one until PopScene; with drawBelow the scenes beneath
+// are still drawn, dimmed, under it
+func (sm *SceneManager) PushScene(scene Scene, drawBelow bool) {
+	sm.stack = append(sm.stack, pushedScene{scene: scene, drawBelow: drawBelow})
+	scene.OnEnter(sm.gameData)
+}
+
+// PopScene removes the topmost pushed scene, handing the input back to the scene beneath
+func (sm *SceneManager) PopScene() {
+	if len(sm.stack) == 0 {
+		return
+	}
+	top := sm.stack[len(sm.stack)-1]
+	sm.stack = sm.stack[:len(sm.stack)-1]
+	top.scene.OnExit()
+}
+
+// IsPushed reports whether the scene is among those pushed over the current one
+func (sm *SceneManager) IsPushed(scene Scene) bool {
+	for _, pushed := range sm.stack {
+		if pushed.scene == scene {
+			return true
+		}
+	}
+	return false
+}
+
+// drawStack draws the current scene and those pushed over it, from the lowest one still seen,
+// dimming what lies beneath each scene pushed with drawBelow
+func (sm *SceneManager) drawStack(screen *ebiten.Image) {
+	lowest := len(sm.stack) - 1
+	for lowest >= 0 && sm.stack[lowest].drawBelow {
+		lowest--
+	}
+	if lowest < 0 {
 		if scene := sm.scenes[sm.currentScene]; scene != nil {
 			scene.Draw(screen)
 		}
-		
-		// Apply fade effect based on transition progress
-		// This will be implemented later with proper graphics
-		return
+		lowest = 0
 	}
+	for i := lowest; i < len(sm.stack); i++ {
+		if sm.stack[i].drawBelow {
+			dim := ebiten.NewImage(screen.Bounds().Dx(), screen.Bounds().Dy())
+			dim.Fill(graphics.WithAlpha(graphics.CurrentTheme().Overlay, overlayDimAlpha))
+			screen.DrawImage(dim, nil)
+		}
+		sm.stack[i].scene.Draw(screen)
+	}
+}
 
-	// Draw current scene
-	if scene := sm.scenes[sm.currentScene]; scene != nil {
-		scene.Draw(screen)
+// Draw draws the current scene with transition effects
+func (sm *SceneManager) Draw(screen *ebiten.Image) {
+	if sm.transition.IsTransitioning {
+		sm.drawTransition(screen)
+		return
 	}
+	
+	// Draw current scene with the scenes pushed over it
+	sm.drawStack(screen)
 }
 
 // GetCurrentScene returns the current scene type
@@ -153,7 +421,28 @@ func (sm *SceneManager) GetCurrentScene() SceneType {
 	return sm.currentScene
 }
 
+// PreviousScene returns the scene the last transition came from
+func (sm *SceneManager) PreviousScene() SceneType {
+	return sm.transition.FromScene
+}
+
 // GetGameData returns the shared game data
 func (sm *SceneManager) GetGameData() *GameData {
 	return sm.gameData
 }
+
+// SetCoopSession shares every battle with a networked co-op partner
+func (sm *SceneManager) SetCoopSession(session *netplay.Session) {
+	sm.gameData.Coop = session
+}
+
+// SetCompactHUD picks the compact battle HUD for small screens, or the standard one
+func (sm *SceneManager) SetCompactHUD(compact bool) {
+	sm.gameData.CompactHUD = compact
+}
+
+// SetObserver watches the battles streamed by a co-op host, starting right away
+func (sm *SceneManager) SetObserver(watcher *netplay.Watcher) {
+	sm.gameData.Watch = watcher
+	sm.TransitionTo(SceneBattle, nil)
+}
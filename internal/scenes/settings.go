@@ -0,0 +1,385 @@
+package scenes
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/config"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/sound"
+)
+
+// Settings screen layout and steps
+const (
+	settingsLeftX     = 90
+	settingsRightX    = 560
+	settingsValueStep = 240 // 項目名から値までの幅
+	settingsTopY      = 130
+	settingsRowStep   = 30
+	
+	volumeStep      = 0.1
+	uiScaleStep     = 0.25
+	scrollSpeedStep = 100.0
+	minScrollSpeed  = 100.0
+	maxScrollSpeed  = 2000.0
+)
+
+// actionLabels name the battle commands in the key bindings
+var actionLabels = map[controls.Action]string{
+	controls.ActionPause:         "一時停止",
+	controls.ActionTacticalPause: "作戦タイム",
+	controls.ActionSlower:        "戦闘速度を下げる",
+	controls.ActionFaster:        "戦闘速度を上げる",
+	controls.ActionStepBack:      "1ティック戻す",
+	controls.ActionStepForward:   "1ティック進める",
+	controls.ActionRewind:        "5秒巻き戻す",
+	controls.ActionResume:        "巻き戻した所から再開",
+	controls.ActionDirector:      "ディレクターモード",
+	controls.ActionEject:         "櫓から出す",
+	controls.ActionAssignGroup:   "指揮権を相方に渡す",
+	controls.ActionHelp:          "操作方法",
+	controls.ActionScreenshot:    "スクリーンショット",
+	controls.ActionSettings:      "設定（一時停止中）",
+	controls.ActionReturnToSetup: "軍勢設定に戻る",
+}
+
+// settingsRow is one line of the settings: a value changed with ←→, a command run with Enter,
+// or a battle command whose key is bound with Enter
+type settingsRow struct {
+	label  string
+	value  func() string
+	adjust func(step int) // ←→で値を変える（nil: 値ではない）
+	run    func()         // Enterで実行する（nil: Enterは→と同じ）
+	action controls.Action
+}
+
+// SettingsMenu edits the configuration, putting each change into effect at once and saving it to
+// config.toml when closed. It is a screen of its own from the title and opens over a paused battle
+type SettingsMenu struct {
+	config       *config.Config
+	dataManager  *data.DataManager
+	textRenderer *graphics.TextRenderer
+	
+	rows     []settingsRow
+	general  int // 左の列の行数（残りはキー割り当て）
+	selected int
+	binding  bool // 選択中のコマンドに割り当てるキーを待っている
+	open     bool
+}
+
+// NewSettingsMenu creates the settings for the shared configuration
+func NewSettingsMenu(cfg *config.Config, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) *SettingsMenu {
+	sm := &SettingsMenu{
+		config:       cfg,
+		dataManager:  dataManager,
+		textRenderer: textRenderer,
+	}
+	sm.rows = []settingsRow{
+		sm.volumeRow("全体の音量", &cfg.Audio.MasterVolume),
+		sm.volumeRow("効果音の音量", &cfg.Audio.SFXVolume),
+		sm.volumeRow("BGMの音量", &cfg.Audio.BGMVolume),
+		sm.toggleRow("音を出す", &cfg.Audio.Enabled, func() { sound.Apply(cfg.Audio); sound.PlayClick() }),
+		sm.toggleRow("FPS表示", &cfg.Graphics.ShowFPS, nil),
+		sm.toggleRow("垂直同期", &cfg.Graphics.VSync, func() { ebiten.SetVsyncEnabled(cfg.Graphics.VSync) }),
+		{
+			label: "画面の拡大率",
+			value: func() string { return fmt.Sprintf("%.2f倍", cfg.Graphics.GetUIScale()) },
+			adjust: func(step int) {
+				scale := cfg.Graphics.GetUIScale() + float64(step)*uiScaleStep
+				cfg.Graphics.UIScale = math.Max(config.MinUIScale, math.Min(config.MaxUIScale, scale))
+				applyUIScale(cfg.Graphics)
+			},
+		},
+		{
+			label:  "言語",
+			value:  sm.languageName,
+			adjust: sm.stepLanguage,
+		},
+		sm.scrollRow("キーのスクロール速度", &cfg.Controls.KeyScrollSpeed, config.DefaultKeyScrollSpeed),
+		sm.scrollRow("画面端のスクロール速度", &cfg.Controls.EdgeScrollSpeed, config.DefaultEdgeScrollSpeed),
+		{
+			label: "キー割り当てを元に戻す",
+			run: func() {
+				controls.ResetBindings()
+				cfg.Controls.KeyBindings = nil
+			},
+		},
+		{label: "戻る", run: sm.Close},
+	}
+	sm.general = len(sm.rows)
+	for _, action := range controls.Actions {
+		sm.rows = append(sm.rows, settingsRow{label: actionLabels[action], action: action})
+	}
+	return sm
+}
+
+// volumeRow changes a volume in steps of 10%, sounding the new effect volume
+func (sm *SettingsMenu) volumeRow(label string, volume *float64) settingsRow {
+	return settingsRow{
+		label: label,
+		value: func() string { return fmt.Sprintf("%d%%", int(math.Round(*volume*100))) },
+		adjust: func(step int) {
+			*volume = math.Max(0, math.Min(1, math.Round((*volume+float64(step)*volumeStep)*10)/10))
+			sound.Apply(sm.config.Audio)
+			sound.PlayClick()
+		},
+	}
+}
+
+// toggleRow turns a setting on and off, running apply after each change
+func (sm *SettingsMenu) toggleRow(label string, enabled *bool, apply func()) settingsRow {
+	return settingsRow{
+		label: label,
+		value: func() string { return onOff(*enabled) },
+		adjust: func(int) {
+			*enabled = !*enabled
+			if apply != nil {
+				apply()
+			}
+		},
+	}
+}
+
+// scrollRow changes a camera scroll speed in steps of 100 px/s; the battle reads it every tick
+func (sm *SettingsMenu) scrollRow(label string, speed *float64, defaultSpeed float64) settingsRow {
+	return settingsRow{
+		label: label,
+		value: func() string { return fmt.Sprintf("%.0f", orDefault(*speed, defaultSpeed)) },
+		adjust: func(step int) {
+			*speed = math.Max(minScrollSpeed, math.Min(maxScrollSpeed, orDefault(*speed, defaultSpeed)+float64(step)*scrollSpeedStep))
+		},
+	}
+}
+
+// orDefault returns the speed, or the default while it is unset
+func orDefault(speed, defaultSpeed float64) float64 {
+	if speed <= 0 {
+		return defaultSpeed
+	}
+	return speed
+}
+
+// keyName returns the name of the key bound to a battle command, for the on-screen hints
+func keyName(action controls.Action) string {
+	return controls.ActionKey(action).String()
+}
+
+// languageName returns the name of the UI language from i18n.toml
+func (sm *SettingsMenu) languageName() string {
+	if language, err := sm.dataManager.GetLanguageConfig(sm.config.Game.Language); err == nil && language.Name != "" {
+		return language.Name
+	}
+	return sm.config.Game.Language
+}
+
+// stepLanguage switches to the next or previous language of i18n.toml
+func (sm *SettingsMenu) stepLanguage(step int) {
+	languages := sm.dataManager.ListLanguages()
+	if len(languages) == 0 {
+		return
+	}
+	index := slices.Index(languages, sm.config.Game.Language)
+	index = (index + step + len(languages)) % len(languages)
+	sm.config.Game.Language = languages[index]
+	applyLanguage(sm.config.Game.Language, sm.dataManager, sm.textRenderer)
+}
+
+// Open shows the settings from their first line
+func (sm *SettingsMenu) Open() {
+	sm.open = true
+	sm.selected = 0
+	sm.binding = false
+}
+
+// IsOpen reports whether the settings are shown
+func (sm *SettingsMenu) IsOpen() bool {
+	return sm.open
+}
+
+// Close saves the settings to config.toml and hides them; replays and scripted runs leave the file alone
+func (sm *SettingsMenu) Close() {
+	sm.open = false
+	sm.binding = false
+	if !controls.IsPlayed() {
+		return
+	}
+	if err := sm.config.SaveConfig(config.DefaultConfigPath); err != nil {
+		fmt.Printf("Warning: Failed to save settings: %v\n", err)
+	}
+}
+
+// Update handles the settings' input: ↑↓ choose a line, ←→ change its value, Enter runs it or
+// waits for the key to bind, and Esc closes the settings (or gives up waiting for a key)
+func (sm *SettingsMenu) Update() {
+	if !sm.open {
+		return
+	}
+	if sm.binding {
+		sm.updateBinding()
+		return
+	}
+	
+	if controls.IsKeyJustPressed(ebiten.KeyEscape) {
+		sm.Close()
+		return
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		sm.selected = (sm.selected - 1 + len(sm.rows)) % len(sm.rows)
+	}
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		sm.selected = (sm.selected + 1) % len(sm.rows)
+	}
+	
+	// Clicking a line selects it and acts as Enter
+	confirmed := controls.IsKeyJustPressed(ebiten.KeyEnter)
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		for i, row := range sm.rows {
+			x, y := sm.rowPosition(i)
+			if isCursorOverText(sm.textRenderer, row.label, x, y) {
+				sm.selected = i
+				confirmed = true
+				break
+			}
+		}
+	}
+	
+	row := sm.rows[sm.selected]
+	switch {
+	case row.adjust != nil && controls.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		row.adjust(-1)
+	case row.adjust != nil && (controls.IsKeyJustPressed(ebiten.KeyArrowRight) || (confirmed && row.run == nil)):
+		row.adjust(1)
+	case confirmed && row.run != nil:
+		row.run()
+	case confirmed && row.action != "":
+		sm.binding = true
+	}
+}
+
+// updateBinding binds the next key pressed to the selected command; Esc keeps the old key
+func (sm *SettingsMenu) updateBinding() {
+	key, pressed := controls.JustPressedKey()
+	if !pressed {
+		return
+	}
+	sm.binding = false
+	if key == ebiten.KeyEscape {
+		return
+	}
+	controls.Bind(sm.rows[sm.selected].action, key)
+	sm.config.Controls.KeyBindings = controls.Bindings()
+}
+
+// rowPosition returns where a line of the settings is drawn: the settings on the left, the keys on the right
+func (sm *SettingsMenu) rowPosition(index int) (float64, float64) {
+	if index < sm.general {
+		return settingsLeftX, float64(settingsTopY + index*settingsRowStep)
+	}
+	return settingsRightX, float64(settingsTopY + (index-sm.general)*settingsRowStep)
+}
+
+// Draw draws the settings over the whole screen
+func (sm *SettingsMenu) Draw(screen *ebiten.Image) {
+	theme := graphics.CurrentTheme()
+	screen.Fill(theme.Background)
+	sm.textRenderer.DrawTextWithSize(screen, "設定", settingsLeftX, 50, theme.Text, 28)
+	sm.textRenderer.DrawText(screen, "キー割り当て", settingsRightX, 100, theme.TextMuted)
+	
+	for i, row := range sm.rows {
+		x, y := sm.rowPosition(i)
+		textColor := theme.Text
+		if i == sm.selected {
+			textColor = theme.Accent
+			sm.textRenderer.DrawText(screen, ">", x-20, y, textColor)
+		}
+		sm.textRenderer.DrawText(screen, row.label, x, y, textColor)
+		
+		value := ""
+		switch {
+		case row.value != nil:
+			value = "< " + row.value() + " >"
+		case row.action != "" && sm.binding && i == sm.selected:
+			value = "キーを押してください"
+		case row.action != "":
+			value = controls.ActionKey(row.action).String()
+		}
+		sm.textRenderer.DrawText(screen, value, x+settingsValueStep, y, textColor)
+	}
+	
+	hint := "↑↓: 選択  ←→: 変更  Enter: 実行・キーを割り当てる  Esc: 保存して戻る"
+	if sm.binding {
+		hint = "割り当てるキーを押してください（Esc: やめる）"
+	}
+	sm.textRenderer.DrawText(screen, hint, settingsLeftX, 700, theme.TextHint)
+}
+
+// ApplySettings puts the whole configuration into effect; call it once at startup,
+// after which the settings apply each change as it is made
+func ApplySettings(cfg *config.Config, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) {
+	ebiten.SetVsyncEnabled(cfg.Graphics.VSync)
+	applyUIScale(cfg.Graphics)
+	applyLanguage(cfg.Game.Language, dataManager, textRenderer)
+	controls.SetBindings(cfg.Controls.KeyBindings)
+	sound.Apply(cfg.Audio)
+}
+
+// applyUIScale sizes the window to the screen times the UI scale
+func applyUIScale(graphicsConfig config.GraphicsConfig) {
+	scale := graphicsConfig.GetUIScale()
+	ebiten.SetWindowSize(int(hudScreenWidth*scale), int(hudScreenHeight*scale))
+}
+
+// applyLanguage sizes text for the UI language; Latin text needs larger glyphs and more room between rows
+func applyLanguage(language string, dataManager *data.DataManager, textRenderer *graphics.TextRenderer) {
+	config, err := dataManager.GetLanguageConfig(language)
+	if err != nil {
+		fmt.Printf("Warning: %v, using default text metrics\n", err)
+		config = data.LanguageConfig{}
+	}
+	textRenderer.SetLanguageMetrics(config.FontScale, config.LineSpacing)
+}
+
+// SettingsScene shows the settings as a screen of their own, returning to the title when closed,
+// or pushed over the paused battle, which it goes back to
+type SettingsScene struct {
+	sceneManager *SceneManager
+	menu         *SettingsMenu
+}
+
+// NewSettingsScene creates a new settings scene
+func NewSettingsScene(sceneManager *SceneManager, menu *SettingsMenu) *SettingsScene {
+	return &SettingsScene{sceneManager: sceneManager, menu: menu}
+}
+
+// Update updates the settings scene
+func (ss *SettingsScene) Update() error {
+	ss.menu.Update()
+	if ss.menu.IsOpen() {
+		return nil
+	}
+	if ss.sceneManager.IsPushed(ss) {
+		ss.sceneManager.PopScene()
+	} else {
+		ss.sceneManager.TransitionTo(SceneTitle, nil)
+	}
+	return nil
+}
+
+// Draw draws the settings scene
+func (ss *SettingsScene) Draw(screen *ebiten.Image) {
+	ss.menu.Draw(screen)
+}
+
+// OnEnter opens the settings
+func (ss *SettingsScene) OnEnter(data interface{}) {
+	ss.menu.Open()
+}
+
+// OnExit is called when exiting this scene
+func (ss *SettingsScene) OnExit() {
+	// Nothing to clean up; the settings were saved when closed
+}
@@ -0,0 +1,192 @@
+package scenes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/clipboard"
+	"github.com/shirou/tinygocha/internal/data"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+)
+
+// setupCodePrefix marks a setup code and its format version
+const setupCodePrefix = "TGC1-"
+
+// setupSeedLimit keeps the seeds given to copied setups short enough to read out
+const setupSeedLimit = 1_000_000_000
+
+// setupCode is a battle setup shared as text, the challenge code players swap:
+// pasting it in the army setup fights the same battle from the same seed
+type setupCode struct {
+	Stage       string          `json:"stage"` // ステージ設定ID
+	Preset      string          `json:"preset"`
+	EnemyPreset string          `json:"enemy_preset,omitempty"` // 空: 敵軍も Preset
+	Mutators    []string        `json:"mutators,omitempty"`
+	Doctrines   []string        `json:"doctrines,omitempty"`
+	Handicaps   []game.Handicap `json:"handicaps,omitempty"`
+	Seed        int64           `json:"seed"`
+}
+
+// newSetupCode returns the code of the battle the game data describes, fought from the seed
+func newSetupCode(dataManager *data.DataManager, gameData *GameData, seed int64) setupCode {
+	return setupCode{
+		Stage:       stageConfigID(dataManager, gameData.CurrentStage),
+		Preset:      gameData.CurrentPreset,
+		EnemyPreset: gameData.EnemyPreset,
+		Mutators:    gameData.Mutators,
+		Doctrines:   gameData.Doctrines,
+		Handicaps:   gameData.Handicaps,
+		Seed:        seed,
+	}
+}
+
+// String encodes the setup as a single line of URL-safe text
+func (c setupCode) String() string {
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return setupCodePrefix + base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// parseSetupCode decodes a setup code, checking that its stage is offered and its presets exist
+func parseSetupCode(dataManager *data.DataManager, text string) (setupCode, error) {
+	var code setupCode
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(text), setupCodePrefix)
+	if !ok {
+		return code, fmt.Errorf("設定コードではありません")
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return code, fmt.Errorf("設定コードが壊れています")
+	}
+	if err := json.Unmarshal(decoded, &code); err != nil {
+		return code, fmt.Errorf("設定コードが壊れています")
+	}
+	
+	if !slices.ContainsFunc(stageChoices(dataManager), func(stage data.StageListing) bool { return stage.ID == code.Stage }) {
+		return code, fmt.Errorf("不明なステージ: %s", code.Stage)
+	}
+	if !slices.Contains(presetChoices(dataManager), code.Preset) {
+		return code, fmt.Errorf("不明な編成: %s", code.Preset)
+	}
+	if code.EnemyPreset != "" && !slices.Contains(enemyPresetChoices(dataManager), code.EnemyPreset) {
+		return code, fmt.Errorf("不明な編成: %s", code.EnemyPreset)
+	}
+	return code, nil
+}
+
+// copyToClipboard puts the text on the clipboard and returns the message telling the player how it went
+func copyToClipboard(text, what string) string {
+	if err := clipboard.WriteText(text); err != nil {
+		fmt.Printf("Warning: Failed to copy to the clipboard: %v\n", err)
+		return "コピーできませんでした: " + err.Error()
+	}
+	return what + "をコピーしました"
+}
+
+// copySetupCode copies the code of the current setup; a setup without a seed gets one,
+// so the player fights the same battle as whoever pastes the code
+func (as *ArmySetupScene) copySetupCode() {
+	if as.getPresetName() == customArmyChoice {
+		as.message = "自軍編成は設定コードで共有できません"
+		return
+	}
+	if as.seed == 0 {
+		as.seed = time.Now().UnixNano()%setupSeedLimit + 1
+	}
+	code := setupCode{
+		Stage:       as.stages[as.selectedStage].ID,
+		Preset:      as.presetArmies[as.selectedPreset],
+		EnemyPreset: as.getEnemyPreset(),
+		Mutators:    as.getEnabledMutatorIDs(),
+		Doctrines:   as.getDoctrineIDs(),
+		Handicaps:   as.getHandicaps(),
+		Seed:        as.seed,
+	}
+	as.message = copyToClipboard(code.String(), "設定コード")
+}
+
+// pasteSetupCode applies the setup code on the clipboard
+func (as *ArmySetupScene) pasteSetupCode() {
+	text, err := clipboard.ReadText()
+	if err != nil {
+		fmt.Printf("Warning: Failed to paste from the clipboard: %v\n", err)
+		as.message = "貼り付けできませんでした: " + err.Error()
+		return
+	}
+	code, err := parseSetupCode(as.dataManager, text)
+	if err != nil {
+		as.message = err.Error()
+		return
+	}
+	as.applySetupCode(code)
+	as.message = "設定コードを読み込みました"
+}
+
+// applySetupCode selects the setup of the code; doctrines and mutators this version lacks are left out,
+// and handicaps fall back to the nearest step
+func (as *ArmySetupScene) applySetupCode(code setupCode) {
+	as.selectedStage = slices.IndexFunc(as.stages, func(stage data.StageListing) bool { return stage.ID == code.Stage })
+	as.selectedPreset = slices.Index(as.presetArmies, code.Preset)
+	as.selectedEnemy = max(slices.Index(as.enemyPresets, code.EnemyPreset), 0)
+	
+	as.enabledMutators = make(map[string]bool)
+	for _, id := range code.Mutators {
+		if game.GetMutator(id) != nil {
+			as.enabledMutators[id] = true
+		}
+	}
+	
+	as.selectedDoctrines = make([]int, len(doctrineSides))
+	for side := range doctrineSides {
+		if side < len(code.Doctrines) {
+			as.selectedDoctrines[side] = slices.Index(as.doctrineIDs, code.Doctrines[side]) + 1
+		}
+	}
+	
+	as.selectedHandicaps = newHandicapSelection()
+	for side := range doctrineSides {
+		if side >= len(code.Handicaps) {
+			continue
+		}
+		handicap := code.Handicaps[side]
+		for stat, value := range []float64{handicap.HP, handicap.Attack, handicap.Count} {
+			as.selectedHandicaps[side*len(handicapStats)+stat] = nearestHandicapStep(value)
+		}
+	}
+	
+	as.seed = code.Seed
+	as.invalidateForecast()
+}
+
+// nearestHandicapStep returns the index of the handicap step closest to the multiplier
+func nearestHandicapStep(value float64) int {
+	nearest := defaultHandicapStep
+	for i, step := range handicapSteps {
+		if math.Abs(step-value) < math.Abs(handicapSteps[nearest]-value) {
+			nearest = i
+		}
+	}
+	return nearest
+}
+
+// drawSetupCode draws the seed of the setup and the outcome of the last copy or paste
+func (as *ArmySetupScene) drawSetupCode(screen *ebiten.Image) {
+	seedText := "シード: ランダム"
+	if as.seed != 0 {
+		seedText = fmt.Sprintf("シード: %d", as.seed)
+	}
+	seedText += "  Ctrl+C: 設定コードをコピー  Ctrl+V: 貼り付け"
+	as.textRenderer.DrawText(screen, seedText, 100, 80, graphics.CurrentTheme().TextMuted)
+	if as.message != "" {
+		as.textRenderer.DrawText(screen, as.message, 100, 98, graphics.CurrentTheme().Highlight)
+	}
+}
@@ -0,0 +1,192 @@
+package scenes
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/shirou/tinygocha/internal/controls"
+)
+
+// Smoke test tuning
+const (
+	smokeSettleTime  = 0.5  // 画面に入ってからキーを押すまでの秒数（一度は描画させる）
+	smokeStepTimeout = 30.0 // 1つの画面でこの秒数進まなければ失敗（戦闘は戦闘時間に加えて）
+)
+
+// smokeSceneNames name the screens the smoke test walks through in its log
+var smokeSceneNames = map[SceneType]string{
+	SceneTitle:     "title",
+	SceneArmySetup: "army setup",
+	SceneBattle:    "battle",
+	SceneResult:    "result",
+}
+
+// SmokeTest walks the game on its own from the title through the army setup and a short battle
+// to the result screen by pressing keys as a player would, logging each step, so a run shows
+// the main flow still works after a change. It stops at the first screen that does not move on
+type SmokeTest struct {
+	sceneManager  *SceneManager
+	battleSeconds float64
+	
+	scene     SceneType // 今いる画面
+	elapsed   float64   // 今の画面に入ってからの秒数
+	total     float64   // 動作確認を始めてからの秒数
+	pressed   bool      // 前のティックにキーを押した（続けて押すと押しっぱなしになる）
+	shortened bool      // 戦闘の制限時間を縮めた
+	
+	lines    []string
+	failure  string // 失敗の理由（空: 失敗していない）
+	finished bool
+}
+
+// NewSmokeTest creates a smoke test that fights battleSeconds of battle; call controls.StartScript with it
+func NewSmokeTest(sceneManager *SceneManager, battleSeconds float64) *SmokeTest {
+	st := &SmokeTest{
+		sceneManager:  sceneManager,
+		battleSeconds: battleSeconds,
+		scene:         -1,
+	}
+	st.logf("smoke test started (%.0fs of battle)", battleSeconds)
+	return st
+}
+
+// Update presses the keys that move the current screen on; call it after the scene manager's Update every tick
+func (st *SmokeTest) Update() {
+	if st.finished {
+		return
+	}
+	deltaTime := controls.DeltaTime()
+	st.total += deltaTime
+	if st.sceneManager.transition.IsTransitioning {
+		return
+	}
+	
+	current := st.sceneManager.GetCurrentScene()
+	if current != st.scene {
+		st.scene, st.elapsed, st.pressed = current, 0, false
+		st.logf("entered %s", st.sceneName(current))
+	}
+	st.elapsed += deltaTime
+	
+	timeout := smokeStepTimeout
+	if current == SceneBattle {
+		timeout += st.battleSeconds
+	}
+	if st.elapsed > timeout {
+		st.Fail(fmt.Sprintf("stuck on the %s screen for %.0fs", st.sceneName(current), timeout))
+		return
+	}
+	if st.elapsed < smokeSettleTime {
+		return
+	}
+	
+	switch current {
+	case SceneTitle:
+		// 「戦闘開始」 is the first item
+		st.press(ebiten.KeyEnter)
+	case SceneArmySetup:
+		setup, ok := st.sceneManager.scenes[SceneArmySetup].(*ArmySetupScene)
+		if !ok {
+			st.Fail("army setup scene is not registered")
+			return
+		}
+		switch {
+		case setup.selectedItem < startItem:
+			st.press(ebiten.KeyArrowDown)
+		case setup.selectedItem > startItem:
+			st.press(ebiten.KeyArrowUp)
+		default:
+			st.press(ebiten.KeyEnter)
+		}
+	case SceneBattle:
+		st.updateBattle()
+	case SceneResult:
+		result, _ := st.sceneManager.scenes[SceneResult].(*ResultScene)
+		if result != nil {
+			st.logf("result: %s", result.winner)
+		}
+		st.logf("PASS")
+		st.finished = true
+	default:
+		st.Fail(fmt.Sprintf("left the flow for the %s screen", st.sceneName(current)))
+	}
+}
+
+// updateBattle confirms the rules card and the trap placement, then lets the battle run until
+// the time limit, cut down to the smoke test's battle seconds, ends it the ordinary way
+func (st *SmokeTest) updateBattle() {
+	battle, ok := st.sceneManager.scenes[SceneBattle].(*BattleSceneUnified)
+	if !ok {
+		st.Fail("battle scene is not registered")
+		return
+	}
+	if battle.battleManager == nil {
+		return
+	}
+	if !st.shortened {
+		battle.battleManager.TimeLimit = min(battle.battleManager.TimeLimit, st.battleSeconds)
+		st.shortened = true
+		st.logf("battle on %s (%s)", st.sceneManager.gameData.CurrentStage, presetSummary(st.sceneManager.gameData))
+	}
+	if battle.showRulesCard || battle.placingTraps {
+		st.press(ebiten.KeyEnter)
+	}
+}
+
+// press presses the key on every other tick, so each press is a new one
+func (st *SmokeTest) press(key ebiten.Key) {
+	if st.pressed {
+		st.pressed = false
+		return
+	}
+	controls.Press(key)
+	st.pressed = true
+}
+
+// sceneName returns the screen's name for the log
+func (st *SmokeTest) sceneName(scene SceneType) string {
+	if name, ok := smokeSceneNames[scene]; ok {
+		return name
+	}
+	return fmt.Sprintf("scene %d", scene)
+}
+
+// logf adds a line to the log, stamped with the time since the smoke test started
+func (st *SmokeTest) logf(format string, args ...any) {
+	line := fmt.Sprintf("[%6.2fs] ", st.total) + fmt.Sprintf(format, args...)
+	st.lines = append(st.lines, line)
+	fmt.Println("Smoke: " + line)
+}
+
+// Fail ends the smoke test with the reason, such as a panic caught by the game loop
+func (st *SmokeTest) Fail(reason string) {
+	if st.failure != "" {
+		return
+	}
+	st.failure = reason
+	st.logf("FAIL: %s", reason)
+	st.finished = true
+}
+
+// Finished reports whether the smoke test passed or failed and the game should end
+func (st *SmokeTest) Finished() bool {
+	return st.finished
+}
+
+// Passed reports whether the smoke test reached the result screen without failing
+func (st *SmokeTest) Passed() bool {
+	return st.finished && st.failure == ""
+}
+
+// WriteLog writes the log of the steps and the outcome to the file
+func (st *SmokeTest) WriteLog(path string) error {
+	if !st.finished {
+		st.Fail("the game closed before the result screen")
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(st.lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write smoke test log %s: %w", path, err)
+	}
+	return nil
+}
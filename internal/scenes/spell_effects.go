@@ -0,0 +1,59 @@
+package scenes
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+)
+
+// projectileRadius is the on-screen size of a spell in flight at zoom 1
+const projectileRadius = 4.0
+
+// spellColors caches the colors parsed from the abilities' visual, by color string
+var spellColors = make(map[string]color.RGBA)
+
+// spellColor returns the color of the ability's light and projectile, or magicGlowColor when it sets none
+func spellColor(ability *game.Ability) color.RGBA {
+	if ability == nil || ability.Visual == "" {
+		return magicGlowColor
+	}
+	if spellColor, cached := spellColors[ability.Visual]; cached {
+		return spellColor
+	}
+	spellColor, err := graphics.ParseHexColor(ability.Visual)
+	if err != nil {
+		spellColor = magicGlowColor
+	}
+	spellColors[ability.Visual] = spellColor
+	return spellColor
+}
+
+// isProjectileVisible reports whether the player sees the spell: it is seen when its caster or its target is
+func (bs *BattleSceneUnified) isProjectileVisible(projectile *game.SpellProjectile) bool {
+	return bs.isUnitVisible(projectile.Caster) || bs.isUnitVisible(projectile.Target)
+}
+
+// drawProjectiles draws the spells flying towards their targets
+func (bs *BattleSceneUnified) drawProjectiles(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	for _, projectile := range bs.battleManager.Projectiles {
+		if !bs.isProjectileVisible(projectile) {
+			continue
+		}
+		x, y := transform.Apply(projectile.Position.X, projectile.Position.Y)
+		vector.DrawFilledCircle(screen, float32(x), float32(y), float32(max(projectileRadius*zoom, 2)), spellColor(projectile.Ability), true)
+	}
+}
+
+// scaleGlow returns the color at the given strength, with premultiplied alpha
+func scaleGlow(glow color.RGBA, strength float64) color.RGBA {
+	return color.RGBA{
+		uint8(float64(glow.R) * strength),
+		uint8(float64(glow.G) * strength),
+		uint8(float64(glow.B) * strength),
+		uint8(float64(glow.A) * strength),
+	}
+}
@@ -0,0 +1,171 @@
+package scenes
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/data"
+)
+
+// Stage thumbnail cache
+const (
+	thumbnailDir  = "cache/thumbnails" // サムネイルPNGを書き出すフォルダ
+	thumbnailSize = 320                // サムネイルの長い辺（px）
+)
+
+// stageThumbnails holds the thumbnails loaded or drawn this run by file name (nil: the stage does not exist)
+var stageThumbnails = make(map[string]*ebiten.Image)
+
+// stageThumbnail returns the stage's thumbnail: the battlefield's terrain, obstacles, structures and
+// objectives on a transparent background, thumbnailSize on its longer side. It is drawn the first time
+// and written to thumbnailDir as "<stage>-<fingerprint>.png", so later runs load it until the stage's data
+// changes. It must be called while the game is running (from Draw) since drawing it reads pixels back
+func stageThumbnail(dataManager *data.DataManager, stageID string) *ebiten.Image {
+	if dataManager == nil {
+		return nil
+	}
+	stage, err := dataManager.GetStageConfig(stageID)
+	if err != nil {
+		return nil
+	}
+	
+	filename := thumbnailPath(stageID, stage)
+	if img, loaded := stageThumbnails[filename]; loaded {
+		return img
+	}
+	img, _, err := ebitenutil.NewImageFromFile(filename)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("Warning: Failed to load stage thumbnail %s: %v\n", filename, err)
+		}
+		img = drawStageThumbnail(stage)
+		if err := saveThumbnail(img, stageID, filename); err != nil {
+			fmt.Printf("Warning: Failed to save stage thumbnail %s: %v\n", filename, err)
+		}
+	}
+	stageThumbnails[filename] = img
+	return img
+}
+
+// thumbnailPath returns the cache file of the stage's thumbnail as its data is now
+func thumbnailPath(stageID string, stage data.StageConfig) string {
+	return filepath.Join(thumbnailDir, stageID+"-"+stage.Fingerprint()+".png")
+}
+
+// saveThumbnail writes the thumbnail to its cache file and removes the stage's older ones
+func saveThumbnail(img *ebiten.Image, stageID, filename string) error {
+	if err := os.MkdirAll(thumbnailDir, 0755); err != nil {
+		return err
+	}
+	
+	// Stale files share the stage prefix and the length of the name (the fingerprint has a fixed length),
+	// which keeps "forest" from removing the thumbnails of "forest-night"
+	stale, _ := filepath.Glob(filepath.Join(thumbnailDir, stageID+"-*.png"))
+	for _, old := range stale {
+		if old != filename && len(old) == len(filename) {
+			os.Remove(old)
+		}
+	}
+	
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return png.Encode(file, img)
+}
+
+// drawStageThumbnail draws the stage's fixed layout: its preview image when it has one,
+// slow and impassable terrain, trees and boulders, structures, victory zones, capture points,
+// neutral camps and supply points. Deployment points depend on the armies and are left out
+func drawStageThumbnail(stage data.StageConfig) *ebiten.Image {
+	width, height := stage.WorldSize()
+	scale := thumbnailSize / math.Max(width, height)
+	img := ebiten.NewImage(max(int(math.Ceil(width*scale)), 1), max(int(math.Ceil(height*scale)), 1))
+	toThumbnail := func(x, y float64) (float32, float32) {
+		return float32(x * scale), float32(y * scale)
+	}
+	
+	if stage.Preview != "" {
+		preview, _, err := ebitenutil.NewImageFromFile(stage.Preview)
+		if err != nil {
+			fmt.Printf("Warning: Failed to load stage preview %s: %v\n", stage.Preview, err)
+		} else {
+			bounds := preview.Bounds()
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(width*scale/float64(bounds.Dx()), height*scale/float64(bounds.Dy()))
+			img.DrawImage(preview, op)
+		}
+	}
+	
+	// Slow and impassable terrain
+	for _, area := range stage.TerrainAreas {
+		x, y := toThumbnail(area.X1, area.Y1)
+		vector.DrawFilledRect(img, x, y, float32((area.X2-area.X1)*scale), float32((area.Y2-area.Y1)*scale), terrainAreaColor(area.MovementModifier), false)
+	}
+	
+	// Trees and boulders
+	for _, obstacle := range stage.Obstacles {
+		x, y := toThumbnail(obstacle.X, obstacle.Y)
+		obstacleColor := color.RGBA{39, 174, 96, 255}
+		if obstacle.Kind == data.ObstacleRock {
+			obstacleColor = color.RGBA{149, 165, 166, 255}
+		}
+		vector.DrawFilledCircle(img, x, y, float32(max(obstacle.Radius*scale, 2)), obstacleColor, true)
+	}
+	
+	// Gates, walls and towers
+	for _, structure := range stage.Structures {
+		x, y := toThumbnail(min(structure.X1, structure.X2), min(structure.Y1, structure.Y2))
+		width := float32(max(math.Abs(structure.X2-structure.X1)*scale, 2))
+		height := float32(max(math.Abs(structure.Y2-structure.Y1)*scale, 2))
+		vector.DrawFilledRect(img, x, y, width, height, color.RGBA{120, 120, 125, 255}, false)
+		vector.StrokeRect(img, x, y, width, height, 1, armyColor(structure.ArmyID()), false)
+	}
+	
+	// Victory zones
+	for _, condition := range stage.VictoryConditions {
+		if condition.Radius <= 0 {
+			continue
+		}
+		x, y := toThumbnail(condition.X, condition.Y)
+		vector.StrokeCircle(img, x, y, float32(condition.Radius*scale), 1, color.RGBA{255, 255, 255, 200}, true)
+	}
+	
+	// Capture points
+	for _, point := range stage.CapturePoints {
+		x, y := toThumbnail(point.X, point.Y)
+		vector.DrawFilledCircle(img, x, y, float32(point.Radius*scale), color.RGBA{236, 240, 241, 80}, true)
+	}
+	
+	// Neutral camps
+	for _, camp := range stage.NeutralCamps {
+		x, y := toThumbnail(camp.X, camp.Y)
+		vector.StrokeCircle(img, x, y, float32(camp.GuardRadius*scale), 1, neutralCreatureColor, true)
+	}
+	
+	// Supply points
+	for _, point := range stage.SupplyPoints {
+		x, y := toThumbnail(point.X, point.Y)
+		vector.StrokeCircle(img, x, y, float32(point.GetRadius()*scale), 1, armyColor(point.ArmyID()), true)
+	}
+	return img
+}
+
+// drawThumbnail draws the stage's thumbnail scaled to the given width and height at x, y
+func drawThumbnail(screen, thumbnail *ebiten.Image, x, y, width, height float64) {
+	bounds := thumbnail.Bounds()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(width/float64(bounds.Dx()), height/float64(bounds.Dy()))
+	op.GeoM.Translate(x, y)
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(thumbnail, op)
+}
@@ -1,10 +1,9 @@
 package scenes
 
 import (
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/shirou/tinygocha/internal/controls"
 	"github.com/shirou/tinygocha/internal/graphics"
 )
 
@@ -22,32 +21,48 @@ func NewTitleScene(sceneManager *SceneManager, textRenderer *graphics.TextRender
 		sceneManager: sceneManager,
 		textRenderer: textRenderer,
 		selectedItem: 0,
-		menuItems:    []string{"戦闘開始", "終了"},
+		menuItems:    []string{"戦闘開始", "チャレンジ", "キャンペーン", "LAN協力プレイ", "設定", "終了"},
 	}
 }
 
 // Update updates the title scene
 func (ts *TitleScene) Update() error {
 	// Handle input
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowUp) {
 		ts.selectedItem--
 		if ts.selectedItem < 0 {
 			ts.selectedItem = len(ts.menuItems) - 1
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+	if controls.IsKeyJustPressed(ebiten.KeyArrowDown) {
 		ts.selectedItem++
 		if ts.selectedItem >= len(ts.menuItems) {
 			ts.selectedItem = 0
 		}
 	}
 	
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+	confirmed := controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace)
+	
+	// Pointing at a menu item selects it, and clicking confirms it
+	if updateMenuMouse(ts.textRenderer, ts.menuRegions(), &ts.selectedItem) {
+		confirmed = true
+	}
+	
+	// The menus wipe the title away
+	if confirmed {
 		switch ts.selectedItem {
 		case 0: // 戦闘開始
-			ts.sceneManager.TransitionTo(SceneArmySetup, nil)
-		case 1: // 終了
+			ts.sceneManager.TransitionWith(SceneArmySetup, nil, menuTransition)
+		case 1: // チャレンジ
+			ts.sceneManager.TransitionWith(SceneChallenge, nil, menuTransition)
+		case 2: // キャンペーン
+			ts.sceneManager.TransitionWith(SceneOverworld, nil, menuTransition)
+		case 3: // LAN協力プレイ
+			ts.sceneManager.TransitionWith(SceneLobby, nil, menuTransition)
+		case 4: // 設定
+			ts.sceneManager.TransitionWith(SceneSettings, nil, menuTransition)
+		case 5: // 終了
 			return ebiten.Termination
 		}
 	}
@@ -55,38 +70,47 @@ func (ts *TitleScene) Update() error {
 	return nil
 }
 
+// menuRegions returns where the menu items are drawn
+func (ts *TitleScene) menuRegions() []menuRegion {
+	regions := make([]menuRegion, len(ts.menuItems))
+	for i, item := range ts.menuItems {
+		regions[i] = menuRegion{i, "> " + item + " <", 430, 350 + float64(i*40)}
+	}
+	return regions
+}
+
 // Draw draws the title scene
 func (ts *TitleScene) Draw(screen *ebiten.Image) {
 	// Clear screen with dark background
-	screen.Fill(color.RGBA{44, 62, 80, 255}) // #2C3E50
+	screen.Fill(graphics.CurrentTheme().Background)
 	
 	// Draw title
 	titleText := "ゴチャキャラバトル"
-	ts.textRenderer.DrawTextWithSize(screen, titleText, 320, 200, color.RGBA{236, 240, 241, 255}, 32)
+	ts.textRenderer.DrawTextWithSize(screen, titleText, 320, 200, graphics.CurrentTheme().Text, 32)
 	
 	// Draw version
 	versionText := "Version 0.1.0 (Demo)"
-	ts.textRenderer.DrawText(screen, versionText, 400, 250, color.RGBA{149, 165, 166, 255})
+	ts.textRenderer.DrawText(screen, versionText, 400, 250, graphics.CurrentTheme().TextMuted)
 	
 	// Draw menu items
 	for i, item := range ts.menuItems {
 		x := 450.0
-		y := 350.0 + float64(i*50)
+		y := 350.0 + float64(i*40)
 		
 		// Highlight selected item
 		if i == ts.selectedItem {
 			// Draw selection indicator with shadow
 			selectedText := "> " + item + " <"
 			ts.textRenderer.DrawTextWithShadow(screen, selectedText, x-20, y, 
-				color.RGBA{52, 152, 219, 255}, color.RGBA{0, 0, 0, 128})
+				graphics.CurrentTheme().Accent, graphics.CurrentTheme().Shadow)
 		} else {
-			ts.textRenderer.DrawText(screen, item, x, y, color.RGBA{236, 240, 241, 255})
+			ts.textRenderer.DrawText(screen, item, x, y, graphics.CurrentTheme().Text)
 		}
 	}
 	
 	// Draw controls hint
-	controlsText := "↑↓: 選択  Enter/Space: 決定"
-	ts.textRenderer.DrawText(screen, controlsText, 350, 500, color.RGBA{149, 165, 166, 255})
+	controlsText := "↑↓/マウス: 選択  Enter/Space/クリック: 決定"
+	ts.textRenderer.DrawText(screen, controlsText, 350, 550, graphics.CurrentTheme().TextMuted)
 }
 
 // OnEnter is called when entering this scene
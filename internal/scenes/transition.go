@@ -0,0 +1,133 @@
+package scenes
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TransitionStyle is how the screen changes from one scene to the next
+type TransitionStyle int
+
+const (
+	TransitionFade      TransitionStyle = iota // 黒にフェードアウトしてから次のシーンをフェードイン
+	TransitionCrossfade                        // 前のシーンが次のシーンに溶けて変わる
+	TransitionWipe                             // 前のシーンが左から右へ拭き取られて次のシーンが現れる
+)
+
+// Easing maps the linear progress of a transition (0-1) to how far it looks along
+type Easing func(t float64) float64
+
+// EaseLinear moves the transition at an even pace
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseInOut starts and ends the transition gently
+func EaseInOut(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+// EaseOut starts the transition quickly and slows it down at the end
+func EaseOut(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// TransitionOptions choose the look and the length of a scene transition
+type TransitionOptions struct {
+	Style    TransitionStyle
+	Duration float64 // 秒
+	Easing   Easing  // nil: EaseLinear
+}
+
+// Transitions used between the scenes
+var (
+	defaultTransition = TransitionOptions{Style: TransitionFade, Duration: 0.5, Easing: EaseInOut}
+	menuTransition    = TransitionOptions{Style: TransitionWipe, Duration: 0.4, Easing: EaseOut}
+	resultTransition  = TransitionOptions{Style: TransitionCrossfade, Duration: 0.8, Easing: EaseInOut}
+)
+
+// switchPoint returns the progress at which the style puts the next scene in place: halfway through
+// a fade, and at once for the styles that draw the last screen of the previous scene over the next
+func (style TransitionStyle) switchPoint() float64 {
+	if style == TransitionFade {
+		return 0.5
+	}
+	return 0
+}
+
+// keepsOutgoing reports whether the style draws the last screen of the previous scene during the transition
+func (style TransitionStyle) keepsOutgoing() bool {
+	return style != TransitionFade
+}
+
+// eased returns the transition's progress through its easing, from 0 to 1
+func (st *SceneTransition) eased(progress float64) float64 {
+	progress = min(max(progress, 0), 1)
+	if st.Easing == nil {
+		return progress
+	}
+	return st.Easing(progress)
+}
+
+// drawTransition draws the scenes while a transition runs, keeping the last screen of the previous
+// scene in an offscreen image for the styles that need it
+func (sm *SceneManager) drawTransition(screen *ebiten.Image) {
+	st := sm.transition
+	if !st.switched {
+		if st.Style.keepsOutgoing() && !st.captured {
+			sm.captureOutgoing(screen.Bounds().Dx(), screen.Bounds().Dy())
+		}
+		sm.drawStack(screen)
+		if st.Style == TransitionFade {
+			drawBlack(screen, st.eased(st.Progress/st.Style.switchPoint()))
+		}
+		return
+	}
+	
+	sm.drawStack(screen)
+	switch st.Style {
+	case TransitionFade:
+		point := st.Style.switchPoint()
+		drawBlack(screen, 1-st.eased((st.Progress-point)/(1-point)))
+	case TransitionCrossfade:
+		if st.captured {
+			op := &ebiten.DrawImageOptions{}
+			op.ColorScale.ScaleAlpha(float32(1 - st.eased(st.Progress)))
+			screen.DrawImage(st.outgoing, op)
+		}
+	case TransitionWipe:
+		if st.captured {
+			bounds := st.outgoing.Bounds()
+			edge := bounds.Min.X + int(float64(bounds.Dx())*st.eased(st.Progress))
+			if edge < bounds.Max.X {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Translate(float64(edge), 0)
+				screen.DrawImage(st.outgoing.SubImage(image.Rect(edge, bounds.Min.Y, bounds.Max.X, bounds.Max.Y)).(*ebiten.Image), op)
+			}
+		}
+	}
+}
+
+// captureOutgoing draws the previous scene into the offscreen image the transition keeps of it
+func (sm *SceneManager) captureOutgoing(width, height int) {
+	st := sm.transition
+	if st.outgoing == nil || st.outgoing.Bounds().Dx() != width || st.outgoing.Bounds().Dy() != height {
+		st.outgoing = ebiten.NewImage(width, height)
+	}
+	st.outgoing.Clear()
+	sm.drawStack(st.outgoing)
+	st.captured = true
+}
+
+// drawBlack darkens the whole screen, from clear at 0 to black at 1
+func drawBlack(screen *ebiten.Image, alpha float64) {
+	alpha = min(max(alpha, 0), 1)
+	if alpha == 0 {
+		return
+	}
+	black := ebiten.NewImage(screen.Bounds().Dx(), screen.Bounds().Dy())
+	black.Fill(color.RGBA{0, 0, 0, uint8(alpha * 255)})
+	screen.DrawImage(black, nil)
+}
@@ -0,0 +1,146 @@
+package scenes
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/shirou/tinygocha/internal/controls"
+	"github.com/shirou/tinygocha/internal/game"
+	"github.com/shirou/tinygocha/internal/graphics"
+	gamemath "github.com/shirou/tinygocha/internal/math"
+)
+
+// trapMarkerRadius is the on-screen size of a trap at zoom 1, matching the distance it springs at
+const trapMarkerRadius = 30
+
+// trapKeys select the kind of trap to lay
+var trapKeys = []struct {
+	key  ebiten.Key
+	kind string
+}{
+	{ebiten.Key1, game.TrapSpikePit},
+	{ebiten.Key2, game.TrapCaltrops},
+}
+
+// canPlaceTraps reports whether the player gets a trap placement phase before the battle
+// Networked battles keep the traps the AI laid, so every peer starts from the same field
+func (bs *BattleSceneUnified) canPlaceTraps() bool {
+	kit := bs.battleManager.GetTrapKit(playerArmyID)
+	return !bs.isNetworked() && kit.SpikePits+kit.Caltrops > 0
+}
+
+// startTrapPlacement opens the trap placement phase on the player's own traps
+func (bs *BattleSceneUnified) startTrapPlacement() {
+	bs.placingTraps = true
+	bs.trapKind = game.TrapSpikePit
+	bs.trapMessage = ""
+}
+
+// handleTrapPlacementInput lays and takes back the player's traps until the battle is started
+func (bs *BattleSceneUnified) handleTrapPlacementInput() {
+	bs.trapsDoneButton.X, bs.trapsDoneButton.Y = 1024-bs.trapsDoneButton.Width-10, 92
+	if controls.IsKeyJustPressed(ebiten.KeyEnter) || controls.IsKeyJustPressed(ebiten.KeySpace) || bs.trapsDoneButton.IsClicked() {
+		bs.placingTraps = false
+		bs.battleManager.StartBattle()
+		fmt.Println("Battle started!")
+		return
+	}
+	
+	for _, trapKey := range trapKeys {
+		if controls.IsKeyJustPressed(trapKey.key) {
+			bs.trapKind = trapKey.kind
+		}
+	}
+	
+	// Clicks on the status bar, the minimap and the HUD do not reach the field
+	mouseX, mouseY := controls.CursorPosition()
+	if mouseY < 60 || bs.isCursorOverMinimap() || bs.hud.IsHovered() || bs.trapsDoneButton.IsHovered() {
+		return
+	}
+	worldX, worldY := bs.camera.ScreenToWorld(mouseX, mouseY)
+	position := gamemath.Vector2D{X: worldX, Y: worldY}
+	
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		bs.trapMessage = ""
+		if err := bs.battleManager.PlaceTrap(playerArmyID, bs.trapKind, position); err != nil {
+			bs.trapMessage = err.Error()
+		}
+	}
+	if controls.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		bs.trapMessage = ""
+		bs.battleManager.RemoveTrap(playerArmyID, position)
+	}
+}
+
+// isTrapVisible reports whether the player knows of the trap; observers on the free camera see them all
+func (bs *BattleSceneUnified) isTrapVisible(trap *game.Trap) bool {
+	if bs.observer != nil && bs.spectateView == spectateFree {
+		return true
+	}
+	return bs.battleManager.IsTrapVisibleTo(playerArmyID, trap)
+}
+
+// drawTraps draws the traps the player knows of: spike pits as dark holes and caltrops as scattered spikes
+// Allied traps stay faint until they spring
+func (bs *BattleSceneUnified) drawTraps(screen *ebiten.Image, transform ebiten.GeoM) {
+	zoom := bs.camera.GetZoom()
+	radius := float32(trapMarkerRadius * zoom)
+	for _, trap := range bs.battleManager.Traps {
+		if !bs.isTrapVisible(trap) {
+			continue
+		}
+		x, y := transform.Apply(trap.Position.X, trap.Position.Y)
+		alpha := uint8(230)
+		if !trap.Revealed {
+			alpha = 140
+		}
+		
+		switch trap.Kind {
+		case game.TrapSpikePit:
+			fill := color.RGBA{60, 40, 20, alpha}
+			if trap.Triggered {
+				fill = color.RGBA{30, 20, 10, alpha}
+			}
+			vector.DrawFilledCircle(screen, float32(x), float32(y), radius*0.7, fill, true)
+			vector.StrokeCircle(screen, float32(x), float32(y), radius*0.7, 2, color.RGBA{160, 120, 70, alpha}, true)
+		default:
+			// 6つの棘を円周に散らして描く
+			spike := color.RGBA{170, 170, 180, alpha}
+			for i := 0; i < 6; i++ {
+				angle := float64(i) * math.Pi / 3
+				sx := float32(x) + radius*0.6*float32(math.Cos(angle))
+				sy := float32(y) + radius*0.6*float32(math.Sin(angle))
+				vector.DrawFilledCircle(screen, sx, sy, max(radius*0.12, 2), spike, true)
+			}
+		}
+	}
+}
+
+// drawTrapPlacementBanner shows the traps left and how to lay them
+func (bs *BattleSceneUnified) drawTrapPlacementBanner(screen *ebiten.Image) {
+	banner := ebiten.NewImage(1024, 56)
+	banner.Fill(graphics.CurrentTheme().Overlay)
+	
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(0, 60)
+	screen.DrawImage(banner, op)
+	
+	var kinds string
+	for i, trapKey := range trapKeys {
+		mark := " "
+		if trapKey.kind == bs.trapKind {
+			mark = "▶"
+		}
+		kinds += fmt.Sprintf("%s%d: %s(残り%d)  ", mark, i+1, game.TrapName(trapKey.kind), bs.battleManager.TrapsLeft(playerArmyID, trapKey.kind))
+	}
+	bannerText := "罠の配置 - " + kinds + "左クリック: 設置  右クリック: 撤去  Enter: 開戦"
+	bs.textRenderer.DrawCenteredText(screen, bannerText, 512, 75, graphics.CurrentTheme().Highlight)
+	if bs.trapMessage != "" {
+		bs.textRenderer.DrawCenteredText(screen, bs.trapMessage, 512, 100, graphics.CurrentTheme().Danger)
+	}
+	
+	bs.trapsDoneButton.Draw(screen, bs.textRenderer)
+}
@@ -0,0 +1,61 @@
+// Package sound plays the game's sound effects at the volumes of the audio settings
+package sound
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/shirou/tinygocha/internal/config"
+)
+
+// Tone of the menu click
+const (
+	sampleRate     = 44100
+	clickFrequency = 880.0 // Hz
+	clickDuration  = 0.05  // 秒
+)
+
+var (
+	context *audio.Context
+	click   []byte  // クリック音のPCM（16bitステレオ）
+	volume  float64 // 効果音の音量（マスター×効果音、無効なら0）
+)
+
+// Apply sets the volumes from the audio settings; call it at startup and whenever they change
+// There is no music yet, so the BGM volume is only kept in the settings
+func Apply(settings config.AudioConfig) {
+	volume = settings.MasterVolume * settings.SFXVolume
+	if !settings.Enabled {
+		volume = 0
+	}
+}
+
+// PlayClick plays a short tone at the effect volume, such as when a menu value changes
+// The audio device is opened the first time a sound plays
+func PlayClick() {
+	if volume <= 0 {
+		return
+	}
+	if context == nil {
+		context = audio.NewContext(sampleRate)
+		click = clickTone()
+	}
+	player := context.NewPlayerFromBytes(click)
+	player.SetVolume(volume)
+	player.Play()
+}
+
+// clickTone generates the click as a sine wave fading out
+func clickTone() []byte {
+	samples := int(sampleRate * clickDuration)
+	pcm := make([]byte, samples*4)
+	for i := range samples {
+		fade := 1 - float64(i)/float64(samples)
+		value := int16(math.Sin(2*math.Pi*clickFrequency*float64(i)/sampleRate) * fade * 0.3 * math.MaxInt16)
+		for channel := range 2 {
+			pcm[i*4+channel*2] = byte(value)
+			pcm[i*4+channel*2+1] = byte(value >> 8)
+		}
+	}
+	return pcm
+}
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"image/color"
 	"log"
+	"os"
+	"runtime/debug"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/shirou/tinygocha/internal/config"
+	"github.com/shirou/tinygocha/internal/controls"
 	"github.com/shirou/tinygocha/internal/data"
 	"github.com/shirou/tinygocha/internal/graphics"
+	"github.com/shirou/tinygocha/internal/netplay"
 	"github.com/shirou/tinygocha/internal/scenes"
 )
 
@@ -17,6 +22,9 @@ const (
 	screenHeight = 768
 )
 
+// themeNoticeTicks is how long the name of a newly switched theme stays on screen
+const themeNoticeTicks = 90
+
 // Game represents the main game structure
 type Game struct {
 	sceneManager   *scenes.SceneManager
@@ -24,12 +32,20 @@ type Game struct {
 	config         *config.Config
 	fontManager    *graphics.FontManager
 	textRenderer   *graphics.TextRenderer
+	
+	// UI themes switched through with F7
+	themes      []*graphics.Theme
+	themeIndex  int
+	themeNotice int // Ticks left to show the theme name
+	
+	smoke   *scenes.SmokeTest // Walks the main flow on its own with -smoke (nil: played normally)
+	watcher *netplay.Watcher  // Co-op stream to watch once the data is loaded (nil: played normally)
 }
 
 // NewGame creates a new game instance
 func NewGame() *Game {
 	// Load configuration
-	cfg, err := config.LoadConfig("config.toml")
+	cfg, err := config.LoadConfig(config.DefaultConfigPath)
 	if err != nil {
 		log.Printf("Warning: Failed to load config: %v, using defaults", err)
 		cfg = config.DefaultConfig()
@@ -53,53 +69,215 @@ func NewGame() *Game {
 	
 	// Create text renderer
 	textRenderer := graphics.NewTextRenderer(fontManager)
+	textRenderer.SetScale(cfg.Graphics.GetTextScale())
 	
-	// Create data manager and load all data
+	// Create data manager; the data is loaded behind the loading screen, and the scenes
+	// that read it are created once it is in
 	dataManager := data.NewDataManager()
-	if err := dataManager.LoadAll(); err != nil {
-		log.Printf("Warning: Failed to load data files: %v", err)
-		// Continue with default/empty data
+	dataManager.Mods = data.FindMods(data.DefaultModDir, cfg.Mods.Enabled, cfg.Mods.Disabled)
+	
+	g := &Game{
+		sceneManager: scenes.NewSceneManager(),
+		dataManager:  dataManager,
+		config:       cfg,
+		fontManager:  fontManager,
+		textRenderer: textRenderer,
+	}
+	g.sceneManager.PushScene(scenes.NewLoadingScene(g.sceneManager, textRenderer, scenes.LoadingTask{
+		Run: func(progress scenes.LoadingProgress) {
+			if err := dataManager.LoadAllWithProgress(progress); err != nil {
+				log.Printf("Warning: Failed to load data files: %v", err)
+				// Continue with default/empty data
+			}
+		},
+		Finish: g.start,
+	}), false)
+	return g
+}
+
+// start readies the game once the data is loaded: it applies the settings and the theme,
+// creates the scenes and opens the title, or the watched co-op battle
+func (g *Game) start() {
+	cfg, dataManager, textRenderer := g.config, g.dataManager, g.textRenderer
+	
+	// Apply the window, text, key and sound settings; the settings screen applies later changes itself
+	scenes.ApplySettings(cfg, dataManager, textRenderer)
+	
+	g.themes, g.themeIndex = loadThemes(dataManager, cfg.Graphics.Theme)
+	if len(g.themes) > 0 {
+		graphics.SetTheme(g.themes[g.themeIndex])
 	}
 	
-	sceneManager := scenes.NewSceneManager()
+	sceneManager := g.sceneManager
 	
 	// Register all scenes with text renderer
 	sceneManager.RegisterScene(scenes.SceneTitle, scenes.NewTitleScene(sceneManager, textRenderer))
-	sceneManager.RegisterScene(scenes.SceneArmySetup, scenes.NewArmySetupScene(sceneManager, textRenderer))
-	sceneManager.RegisterScene(scenes.SceneBattle, scenes.NewBattleSceneUnified(sceneManager, dataManager, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneArmySetup, scenes.NewArmySetupScene(sceneManager, dataManager, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneBattle, scenes.NewBattleSceneUnified(sceneManager, dataManager, cfg, textRenderer))
 	sceneManager.RegisterScene(scenes.SceneResult, scenes.NewResultScene(sceneManager, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneLobby, scenes.NewLobbyScene(sceneManager, dataManager, cfg, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneOverworld, scenes.NewOverworldScene(sceneManager, dataManager, cfg, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneRecruitment, scenes.NewRecruitmentScene(sceneManager, dataManager, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneChallenge, scenes.NewChallengeScene(sceneManager, dataManager, textRenderer))
+	sceneManager.RegisterScene(scenes.SceneSettings, scenes.NewSettingsScene(sceneManager, scenes.NewSettingsMenu(cfg, dataManager, textRenderer)))
 	
-	return &Game{
-		sceneManager: sceneManager,
-		dataManager:  dataManager,
-		config:       cfg,
-		fontManager:  fontManager,
-		textRenderer: textRenderer,
+	if g.watcher != nil {
+		sceneManager.SetObserver(g.watcher)
 	}
 }
 
+// loadThemes builds the UI themes in their order and finds the configured one
+// Themes with bad colors are skipped; an unknown theme ID falls back to the first theme
+func loadThemes(dataManager *data.DataManager, themeID string) ([]*graphics.Theme, int) {
+	var themes []*graphics.Theme
+	selected := -1
+	for _, listing := range dataManager.ListThemes() {
+		themeConfig, err := dataManager.GetThemeConfig(listing.ID)
+		if err != nil {
+			continue
+		}
+		theme, err := graphics.NewTheme(listing.Name, themeConfig.Colors)
+		if err != nil {
+			log.Printf("Warning: Skipping theme %s: %v", listing.ID, err)
+			continue
+		}
+		if listing.ID == themeID {
+			selected = len(themes)
+		}
+		themes = append(themes, theme)
+	}
+	if selected < 0 {
+		if len(themes) > 0 {
+			log.Printf("Warning: Theme %s not found, using %s", themeID, themes[0].Name)
+		}
+		selected = 0
+	}
+	return themes, selected
+}
+
 // Update updates the game logic
-func (g *Game) Update() error {
-	return g.sceneManager.Update()
+func (g *Game) Update() (err error) {
+	if g.smoke != nil {
+		defer g.recoverSmoke(&err)
+		if g.smoke.Finished() {
+			return ebiten.Termination
+		}
+	}
+	
+	controls.Update()
+	
+	// A replay ends the game once all recorded input is played
+	if controls.IsPlaybackFinished() {
+		return ebiten.Termination
+	}
+	
+	// F7 switches the UI theme on any screen
+	if controls.IsKeyJustPressed(ebiten.KeyF7) && len(g.themes) > 1 {
+		g.themeIndex = (g.themeIndex + 1) % len(g.themes)
+		graphics.SetTheme(g.themes[g.themeIndex])
+		g.themeNotice = themeNoticeTicks
+	}
+	if g.themeNotice > 0 {
+		g.themeNotice--
+	}
+	if err := g.sceneManager.Update(); err != nil {
+		return err
+	}
+	
+	// The smoke test presses the keys for the next tick
+	if g.smoke != nil {
+		g.smoke.Update()
+	}
+	return nil
+}
+
+// recoverSmoke turns a panic during the smoke test into its failure, ending the game on the next update
+// rather than crashing, so the log is still written
+func (g *Game) recoverSmoke(err *error) {
+	if r := recover(); r != nil {
+		g.smoke.Fail(fmt.Sprintf("panic: %v\n%s", r, debug.Stack()))
+		if err != nil {
+			*err = ebiten.Termination
+		}
+	}
 }
 
 // Draw draws the game screen
 func (g *Game) Draw(screen *ebiten.Image) {
+	if g.smoke != nil {
+		defer g.recoverSmoke(nil)
+	}
 	g.sceneManager.Draw(screen)
+	g.sceneManager.DrawGamepadHints(screen, g.textRenderer)
 	
 	// Draw FPS if enabled
 	if g.config.Graphics.ShowFPS {
 		fpsText := "FPS: " + fmt.Sprintf("%.1f", ebiten.ActualFPS())
-		g.textRenderer.DrawText(screen, fpsText, 10, 10, color.RGBA{255, 255, 255, 255})
+		g.textRenderer.DrawText(screen, fpsText, 10, 10, graphics.CurrentTheme().TextBright)
+	}
+	
+	if g.themeNotice > 0 {
+		theme := graphics.CurrentTheme()
+		g.textRenderer.DrawTextWithShadow(screen, "テーマ: "+theme.Name, 440, 10, theme.Highlight, theme.Shadow)
 	}
 }
 
 // Layout returns the game's logical screen size
+// The screen is scaled to the window, so small windows switch the battle to the compact HUD
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	g.sceneManager.SetCompactHUD(g.config.Graphics.IsCompactHUD(outsideWidth, outsideHeight, controls.TouchActive()))
 	return screenWidth, screenHeight
 }
 
 func main() {
+	recordPath := flag.String("record", "", "record input to the given JSON file")
+	replayPath := flag.String("replay", "", "play back input from the given JSON file")
+	hostAddr := flag.String("host", "", "host a co-op battle, waiting for a partner on the given address (e.g. :7777)")
+	joinAddr := flag.String("join", "", "join a co-op battle hosted at the given address")
+	observeAddr := flag.String("observe", "", "watch the co-op battles streamed by the host at the given address (e.g. 192.168.0.10:7779)")
+	smoke := flag.Bool("smoke", false, "walk from the title through setup and a short battle to the result on its own, log each step and exit (1 on failure)")
+	smokeSeconds := flag.Float64("smoke-seconds", 10, "seconds of battle the smoke test fights")
+	smokeLog := flag.String("smoke-log", "smoke.log", "file the smoke test writes its pass/fail log to")
+	flag.Parse()
+	
+	if *smoke && (*recordPath != "" || *replayPath != "" || *hostAddr != "" || *joinAddr != "" || *observeAddr != "") {
+		log.Fatal("-smoke cannot be combined with -record, -replay, -host, -join or -observe")
+	}
+	
+	// Co-op: connect to the partner before opening the window
+	var coop *netplay.Session
+	if *hostAddr != "" || *joinAddr != "" {
+		var err error
+		if *hostAddr != "" {
+			coop, err = netplay.Host(*hostAddr)
+		} else {
+			coop, err = netplay.Join(*joinAddr)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer coop.Close()
+	}
+	
+	// Observers follow a co-op host's stream instead of playing
+	var watcher *netplay.Watcher
+	if *observeAddr != "" && coop == nil {
+		var err error
+		watcher, err = netplay.Watch(*observeAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer watcher.Close()
+	}
+	
+	if *replayPath != "" {
+		if err := controls.LoadPlayback(*replayPath); err != nil {
+			log.Fatal(err)
+		}
+	} else if *recordPath != "" {
+		controls.StartRecording(time.Now().UnixNano())
+	}
+	
 	// Set window properties
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("ゴチャキャラバトル - Demo")
@@ -107,8 +285,36 @@ func main() {
 	
 	// Create and run the game
 	game := NewGame()
+	if coop != nil {
+		game.sceneManager.SetCoopSession(coop)
+	}
+	game.watcher = watcher
+	if *smoke {
+		game.smoke = scenes.NewSmokeTest(game.sceneManager, *smokeSeconds)
+		controls.StartScript()
+	}
 	
 	if err := ebiten.RunGame(game); err != nil {
-		log.Fatal(err)
+		if game.smoke == nil {
+			log.Fatal(err)
+		}
+		game.smoke.Fail(err.Error())
+	}
+	
+	if game.smoke != nil {
+		if err := game.smoke.WriteLog(*smokeLog); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		if !game.smoke.Passed() {
+			os.Exit(1)
+		}
+		return
+	}
+	
+	if *recordPath != "" && *replayPath == "" {
+		if err := controls.SaveRecording(*recordPath); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Input recorded to %s", *recordPath)
 	}
 }